@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	orchestratorpb "github.com/tensorfleet/api-gateway/proto/orchestrator"
+)
+
+// adminRequest is the body of the admin endpoints. The operator is taken
+// from the X-User-ID header.
+type adminRequest struct {
+	Reason          string `json:"reason"`
+	ResetAttempts   bool   `json:"reset_attempts"`
+	RequeueInFlight bool   `json:"requeue_in_flight"`
+	Deregister      bool   `json:"deregister"`
+	Key             string `json:"key"`
+	Overwrite       bool   `json:"overwrite"`
+}
+
+func bindAdminRequest(c *gin.Context) (adminRequest, string, bool) {
+	var req adminRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return req, "", false
+		}
+	}
+	operator := c.GetHeader("X-User-ID")
+	if operator == "" {
+		operator = "anonymous"
+	}
+	return req, operator, true
+}
+
+// respondAdmin writes the outcome of an admin RPC.
+func respondAdmin(c *gin.Context, action string, resp *orchestratorpb.AdminResponse, err error) {
+	if err != nil {
+		log.Printf("Error running admin action %s: %v", action, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to " + action,
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if !resp.Success {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": resp.Message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":        true,
+		"message":        resp.Message,
+		"requeued_tasks": resp.RequeuedTasks,
+	})
+}
+
+func (gs *GatewayServer) handleRequeueTask(c *gin.Context) {
+	req, operator, ok := bindAdminRequest(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resp, err := gs.adminClient.RequeueTask(ctx, &orchestratorpb.RequeueTaskRequest{
+		JobId:         c.Param("id"),
+		TaskId:        c.Param("task_id"),
+		Reason:        req.Reason,
+		Operator:      operator,
+		ResetAttempts: req.ResetAttempts,
+	})
+	respondAdmin(c, "requeue task", resp, err)
+}
+
+func (gs *GatewayServer) handleForceFailJob(c *gin.Context) {
+	req, operator, ok := bindAdminRequest(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resp, err := gs.adminClient.ForceFailJob(ctx, &orchestratorpb.ForceFailJobRequest{
+		JobId:    c.Param("id"),
+		Reason:   req.Reason,
+		Operator: operator,
+	})
+	respondAdmin(c, "fail job", resp, err)
+}
+
+func (gs *GatewayServer) handleDrainWorker(c *gin.Context) {
+	req, operator, ok := bindAdminRequest(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resp, err := gs.adminClient.DrainWorker(ctx, &orchestratorpb.DrainWorkerRequest{
+		WorkerId:        c.Param("id"),
+		Reason:          req.Reason,
+		Operator:        operator,
+		RequeueInFlight: req.RequeueInFlight,
+		Deregister:      req.Deregister,
+	})
+	respondAdmin(c, "drain worker", resp, err)
+}
+
+// respondSnapshot writes the outcome of a snapshot RPC.
+func respondSnapshot(c *gin.Context, action string, resp *orchestratorpb.SnapshotResponse, err error) {
+	if err != nil {
+		log.Printf("Error running admin action %s: %v", action, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to " + action,
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if !resp.Success {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": resp.Message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":        true,
+		"message":        resp.Message,
+		"key":            resp.Key,
+		"format_version": resp.FormatVersion,
+		"created_at_ms":  resp.CreatedAtMs,
+		"size_bytes":     resp.SizeBytes,
+		"jobs":           resp.Jobs,
+		"workers":        resp.Workers,
+		"skipped_jobs":   resp.SkippedJobs,
+	})
+}
+
+func (gs *GatewayServer) handleExportSnapshot(c *gin.Context) {
+	req, operator, ok := bindAdminRequest(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	resp, err := gs.adminClient.ExportSnapshot(ctx, &orchestratorpb.ExportSnapshotRequest{
+		Reason:   req.Reason,
+		Operator: operator,
+	})
+	respondSnapshot(c, "export snapshot", resp, err)
+}
+
+func (gs *GatewayServer) handleImportSnapshot(c *gin.Context) {
+	req, operator, ok := bindAdminRequest(c)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	resp, err := gs.adminClient.ImportSnapshot(ctx, &orchestratorpb.ImportSnapshotRequest{
+		Key:       req.Key,
+		Reason:    req.Reason,
+		Operator:  operator,
+		Overwrite: req.Overwrite,
+	})
+	respondSnapshot(c, "import snapshot", resp, err)
+}
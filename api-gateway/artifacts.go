@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Workers upload the artifacts tasks declare to the storage service's
+// artifacts bucket, under jobs/<job_id>/tasks/<task_id>/<path>. The gateway
+// lists and serves them from there.
+const artifactsBucket = "artifacts"
+
+// storageServiceURL is the base URL of the storage service.
+func storageServiceURL() string {
+	if u := os.Getenv("STORAGE_SERVICE_URL"); u != "" {
+		return u
+	}
+	return "http://storage:8081"
+}
+
+var storageHTTPClient = &http.Client{Timeout: 5 * time.Minute}
+
+type taskArtifact struct {
+	TaskID       string `json:"task_id"`
+	Path         string `json:"path"` // Relative to the task's working directory
+	SizeBytes    int64  `json:"size_bytes"`
+	LastModified string `json:"last_modified,omitempty"`
+	URL          string `json:"url"` // Where the gateway serves its content
+}
+
+func jobArtifactsPrefix(jobID string) string {
+	return path.Join("jobs", jobID, "tasks") + "/"
+}
+
+// handleListJobArtifacts lists the artifacts a job's tasks produced.
+func (gs *GatewayServer) handleListJobArtifacts(c *gin.Context) {
+	jobID := c.Param("id")
+	prefix := jobArtifactsPrefix(jobID)
+	if taskID := c.Query("task_id"); taskID != "" {
+		prefix += taskID + "/"
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	endpoint, err := url.JoinPath(gs.storageURL, "api/v1/list", artifactsBucket)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid storage service URL"})
+		return
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?prefix="+url.QueryEscape(prefix), nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list artifacts"})
+		return
+	}
+	resp, err := storageHTTPClient.Do(req)
+	if err != nil {
+		log.Printf("Error listing artifacts of job %s: %v", jobID, err)
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Storage service unavailable"})
+		return
+	}
+	defer resp.Body.Close()
+
+	var listing struct {
+		Objects []struct {
+			Name         string `json:"name"`
+			Size         int64  `json:"size"`
+			LastModified string `json:"last_modified"`
+		} `json:"objects"`
+	}
+	if resp.StatusCode != http.StatusOK {
+		log.Printf("Error listing artifacts of job %s: storage service returned status %d", jobID, resp.StatusCode)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to list artifacts"})
+		return
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&listing); err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Malformed response from storage service"})
+		return
+	}
+
+	jobPrefix := jobArtifactsPrefix(jobID)
+	artifacts := make([]taskArtifact, 0, len(listing.Objects))
+	for _, obj := range listing.Objects {
+		taskID, rel, ok := strings.Cut(strings.TrimPrefix(obj.Name, jobPrefix), "/")
+		if !ok {
+			continue
+		}
+		artifacts = append(artifacts, taskArtifact{
+			TaskID:       taskID,
+			Path:         rel,
+			SizeBytes:    obj.Size,
+			LastModified: obj.LastModified,
+			URL:          fmt.Sprintf("/api/v1/jobs/%s/artifacts/%s/%s", jobID, taskID, rel),
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"job_id":    jobID,
+		"artifacts": artifacts,
+		"total":     len(artifacts),
+	})
+}
+
+// handleGetJobArtifact serves the content of one artifact, addressed as
+// <task_id>/<path>.
+func (gs *GatewayServer) handleGetJobArtifact(c *gin.Context) {
+	jobID := c.Param("id")
+	name := strings.TrimPrefix(c.Param("path"), "/")
+	if name == "" || path.Clean(name) != name || strings.HasPrefix(name, "../") || !strings.Contains(name, "/") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Artifact must be addressed as <task_id>/<path>"})
+		return
+	}
+
+	endpoint, err := url.JoinPath(gs.storageURL, "api/v1/download", artifactsBucket, jobArtifactsPrefix(jobID), name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid storage service URL"})
+		return
+	}
+	req, err := http.NewRequestWithContext(c.Request.Context(), http.MethodGet, endpoint, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch artifact"})
+		return
+	}
+	resp, err := storageHTTPClient.Do(req)
+	if err != nil {
+		log.Printf("Error fetching artifact %s of job %s: %v", name, jobID, err)
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Storage service unavailable"})
+		return
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Artifact not found"})
+		return
+	case resp.StatusCode != http.StatusOK:
+		log.Printf("Error fetching artifact %s of job %s: storage service returned status %d", name, jobID, resp.StatusCode)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to fetch artifact"})
+		return
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", path.Base(name)))
+	c.DataFromReader(http.StatusOK, resp.ContentLength, contentType, resp.Body, nil)
+}
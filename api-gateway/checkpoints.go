@@ -0,0 +1,359 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The orchestrator uploads a job's checkpoints to the storage service every
+// checkpoint_every_epochs epochs. The gateway lists, serves, pins and
+// deletes them there, and sets the job's retention policy, which the
+// storage service applies as checkpoints arrive.
+
+// storageCheckpoint is a checkpoint record as the storage service returns
+// it.
+type storageCheckpoint struct {
+	ID        string                 `json:"_id"`
+	JobID     string                 `json:"job_id"`
+	Epoch     int                    `json:"epoch"`
+	MinioPath string                 `json:"minio_path"`
+	Metrics   map[string]interface{} `json:"metrics"`
+	SizeBytes int64                  `json:"size_bytes"`
+	Checksum  string                 `json:"checksum"`
+	Pinned    bool                   `json:"pinned"`
+	CreatedAt string                 `json:"created_at"`
+}
+
+type jobCheckpoint struct {
+	CheckpointID string                 `json:"checkpoint_id"`
+	Epoch        int                    `json:"epoch"`
+	Metrics      map[string]interface{} `json:"metrics,omitempty"`
+	SizeBytes    int64                  `json:"size_bytes"`
+	Checksum     string                 `json:"checksum,omitempty"`
+	Pinned       bool                   `json:"pinned"`
+	Location     string                 `json:"location"`
+	CreatedAt    string                 `json:"created_at"`
+	URL          string                 `json:"url"` // Where the gateway serves its weights
+}
+
+func (cp *storageCheckpoint) toJob() jobCheckpoint {
+	return jobCheckpoint{
+		CheckpointID: cp.ID,
+		Epoch:        cp.Epoch,
+		Metrics:      cp.Metrics,
+		SizeBytes:    cp.SizeBytes,
+		Checksum:     cp.Checksum,
+		Pinned:       cp.Pinned,
+		Location:     cp.MinioPath,
+		CreatedAt:    cp.CreatedAt,
+		URL:          fmt.Sprintf("/api/v1/jobs/%s/checkpoints/%s/weights", cp.JobID, cp.ID),
+	}
+}
+
+// CheckpointRetention is which checkpoints of a job are kept: the latest
+// keep_last by epoch and the best keep_best by metric. Pinned checkpoints
+// are always kept.
+type CheckpointRetention struct {
+	KeepLast int    `json:"keep_last" binding:"min=0"`
+	KeepBest int    `json:"keep_best" binding:"min=0"`
+	Metric   string `json:"metric,omitempty"`
+	Mode     string `json:"mode,omitempty" binding:"omitempty,oneof=min max"`
+}
+
+// handleListJobCheckpoints lists a job's checkpoints, latest epoch first.
+func (gs *GatewayServer) handleListJobCheckpoints(c *gin.Context) {
+	jobID := c.Param("id")
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	resp, err := gs.storageRequest(ctx, c, http.MethodGet, nil, "api/v1/checkpoints", jobID)
+	if err != nil {
+		log.Printf("Error listing checkpoints of job %s: %v", jobID, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		log.Printf("Error listing checkpoints of job %s: storage service returned status %d", jobID, resp.StatusCode)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to list checkpoints"})
+		return
+	}
+	var listing struct {
+		Checkpoints []storageCheckpoint `json:"checkpoints"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&listing); err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Malformed response from storage service"})
+		return
+	}
+
+	checkpoints := make([]jobCheckpoint, 0, len(listing.Checkpoints))
+	for i := range listing.Checkpoints {
+		checkpoints = append(checkpoints, listing.Checkpoints[i].toJob())
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"job_id":      jobID,
+		"checkpoints": checkpoints,
+		"total":       len(checkpoints),
+	})
+}
+
+// handleDownloadJobCheckpoint serves a checkpoint's weights.
+func (gs *GatewayServer) handleDownloadJobCheckpoint(c *gin.Context) {
+	jobID, checkpointID := c.Param("id"), c.Param("checkpoint_id")
+
+	resp, err := gs.storageRequest(c.Request.Context(), c, http.MethodGet, nil, "api/v1/checkpoints", jobID, checkpointID)
+	if err != nil {
+		log.Printf("Error fetching checkpoint %s of job %s: %v", checkpointID, jobID, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Checkpoint not found"})
+		return
+	case resp.StatusCode != http.StatusOK:
+		log.Printf("Error fetching checkpoint %s of job %s: storage service returned status %d", checkpointID, jobID, resp.StatusCode)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to fetch checkpoint"})
+		return
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	disposition := resp.Header.Get("Content-Disposition")
+	if disposition == "" {
+		disposition = fmt.Sprintf("attachment; filename=%q", checkpointID+".weights")
+	}
+	c.Header("Content-Disposition", disposition)
+	c.DataFromReader(http.StatusOK, resp.ContentLength, contentType, resp.Body, nil)
+}
+
+// handleDeleteJobCheckpoint deletes a checkpoint unless it is pinned.
+func (gs *GatewayServer) handleDeleteJobCheckpoint(c *gin.Context) {
+	jobID, checkpointID := c.Param("id"), c.Param("checkpoint_id")
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	resp, err := gs.storageRequest(ctx, c, http.MethodDelete, nil, "api/v1/checkpoints", jobID, checkpointID)
+	if err != nil {
+		log.Printf("Error deleting checkpoint %s of job %s: %v", checkpointID, jobID, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Checkpoint not found"})
+		return
+	case resp.StatusCode == http.StatusConflict:
+		c.JSON(http.StatusConflict, gin.H{"error": storageError(resp, "Checkpoint is pinned")})
+		return
+	case resp.StatusCode != http.StatusOK:
+		log.Printf("Error deleting checkpoint %s of job %s: storage service returned status %d", checkpointID, jobID, resp.StatusCode)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to delete checkpoint"})
+		return
+	}
+	log.Printf("Deleted checkpoint %s of job %s", checkpointID, jobID)
+	c.JSON(http.StatusOK, gin.H{"job_id": jobID, "checkpoint_id": checkpointID, "message": "Checkpoint deleted"})
+}
+
+// handlePinJobCheckpoint pins a checkpoint on POST, so that retention never
+// deletes it, and unpins it on DELETE.
+func (gs *GatewayServer) handlePinJobCheckpoint(c *gin.Context) {
+	jobID, checkpointID := c.Param("id"), c.Param("checkpoint_id")
+	method := http.MethodPut
+	if c.Request.Method == http.MethodDelete {
+		method = http.MethodDelete
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	resp, err := gs.storageRequest(ctx, c, method, nil, "api/v1/checkpoints", jobID, checkpointID, "pin")
+	if err != nil {
+		log.Printf("Error pinning checkpoint %s of job %s: %v", checkpointID, jobID, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Checkpoint not found"})
+		return
+	case resp.StatusCode != http.StatusOK:
+		log.Printf("Error pinning checkpoint %s of job %s: storage service returned status %d", checkpointID, jobID, resp.StatusCode)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to pin checkpoint"})
+		return
+	}
+	var checkpoint storageCheckpoint
+	if err := json.NewDecoder(resp.Body).Decode(&checkpoint); err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Malformed response from storage service"})
+		return
+	}
+	c.JSON(http.StatusOK, checkpoint.toJob())
+}
+
+// handleGetCheckpointRetention returns a job's retention policy.
+func (gs *GatewayServer) handleGetCheckpointRetention(c *gin.Context) {
+	jobID := c.Param("id")
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	resp, err := gs.storageRequest(ctx, c, http.MethodGet, nil, "api/v1/checkpoints", jobID, "retention")
+	if err != nil {
+		log.Printf("Error getting retention policy of job %s: %v", jobID, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job has no retention policy"})
+		return
+	case resp.StatusCode != http.StatusOK:
+		log.Printf("Error getting retention policy of job %s: storage service returned status %d", jobID, resp.StatusCode)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to get retention policy"})
+		return
+	}
+	var policy CheckpointRetention
+	if err := json.NewDecoder(resp.Body).Decode(&policy); err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Malformed response from storage service"})
+		return
+	}
+	c.JSON(http.StatusOK, policy)
+}
+
+// handleSetCheckpointRetention sets a job's retention policy, applying it
+// to the checkpoints the job already has.
+func (gs *GatewayServer) handleSetCheckpointRetention(c *gin.Context) {
+	jobID := c.Param("id")
+	var policy CheckpointRetention
+	if err := c.ShouldBindJSON(&policy); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if policy.KeepLast == 0 && policy.KeepBest == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "keep_last or keep_best is required"})
+		return
+	}
+	body, err := json.Marshal(policy)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set retention policy"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	resp, err := gs.sendToStorage(ctx, c, http.MethodPut, nil, "application/json", bytes.NewReader(body), "api/v1/checkpoints", jobID, "retention")
+	if err != nil {
+		log.Printf("Error setting retention policy of job %s: %v", jobID, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusBadRequest:
+		c.JSON(http.StatusBadRequest, gin.H{"error": storageError(resp, "Invalid retention policy")})
+		return
+	case resp.StatusCode != http.StatusOK:
+		log.Printf("Error setting retention policy of job %s: storage service returned status %d", jobID, resp.StatusCode)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to set retention policy"})
+		return
+	}
+	var result struct {
+		Policy       CheckpointRetention `json:"policy"`
+		DeletedCount int                 `json:"deleted_count"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Malformed response from storage service"})
+		return
+	}
+	log.Printf("Set retention policy of job %s", jobID)
+	c.JSON(http.StatusOK, gin.H{
+		"job_id":        jobID,
+		"policy":        result.Policy,
+		"deleted_count": result.DeletedCount,
+	})
+}
+
+// handleDeleteCheckpointRetention removes a job's retention policy; its
+// checkpoints are kept from then on.
+func (gs *GatewayServer) handleDeleteCheckpointRetention(c *gin.Context) {
+	jobID := c.Param("id")
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	resp, err := gs.storageRequest(ctx, c, http.MethodDelete, nil, "api/v1/checkpoints", jobID, "retention")
+	if err != nil {
+		log.Printf("Error deleting retention policy of job %s: %v", jobID, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job has no retention policy"})
+		return
+	case resp.StatusCode != http.StatusOK:
+		log.Printf("Error deleting retention policy of job %s: storage service returned status %d", jobID, resp.StatusCode)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to delete retention policy"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"job_id": jobID, "message": "Retention policy deleted"})
+}
+
+// handleCleanupJobCheckpoints applies a retention policy once, given as
+// ?keep_last=, ?keep_best=, ?metric= and ?mode=.
+func (gs *GatewayServer) handleCleanupJobCheckpoints(c *gin.Context) {
+	jobID := c.Param("id")
+	query := url.Values{}
+	query.Set("keep", c.DefaultQuery("keep_last", "5"))
+	for _, param := range []string{"keep_best", "metric", "mode"} {
+		if v := c.Query(param); v != "" {
+			query.Set(param, v)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	resp, err := gs.storageRequest(ctx, c, http.MethodPost, query, "api/v1/checkpoints", jobID, "cleanup")
+	if err != nil {
+		log.Printf("Error cleaning up checkpoints of job %s: %v", jobID, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusBadRequest:
+		c.JSON(http.StatusBadRequest, gin.H{"error": storageError(resp, "Invalid retention policy")})
+		return
+	case resp.StatusCode != http.StatusOK:
+		log.Printf("Error cleaning up checkpoints of job %s: storage service returned status %d", jobID, resp.StatusCode)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to clean up checkpoints"})
+		return
+	}
+	var result struct {
+		DeletedCount int `json:"deleted_count"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Malformed response from storage service"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"job_id": jobID, "deleted_count": result.DeletedCount})
+}
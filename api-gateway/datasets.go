@@ -0,0 +1,284 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Datasets are registered with the storage service, which keeps a record
+// of each and the data of those uploaded to it. Jobs refer to a registered
+// dataset by ID instead of by path.
+
+// maxDatasetUpload is the largest dataset that may be uploaded through the
+// gateway; larger ones are written to the object store and registered by
+// location.
+const maxDatasetUpload = 100 << 20
+
+type datasetField struct {
+	Name string `json:"name" binding:"required"`
+	Type string `json:"type,omitempty"`
+}
+
+// storageDataset is a dataset record as the storage service returns it.
+type storageDataset struct {
+	ID          string         `json:"_id"`
+	Name        string         `json:"name"`
+	MinioPath   string         `json:"minio_path"`
+	Description string         `json:"description"`
+	Format      string         `json:"format"`
+	SizeBytes   int64          `json:"size_bytes"`
+	Checksum    string         `json:"checksum"`
+	NumRows     *int64         `json:"num_rows"`
+	NumColumns  *int64         `json:"num_columns"`
+	Columns     []string       `json:"columns"`
+	Schema      []datasetField `json:"schema"`
+	CreatedAt   string         `json:"created_at"`
+}
+
+type registeredDataset struct {
+	DatasetID   string         `json:"dataset_id"`
+	Name        string         `json:"name"`
+	Location    string         `json:"location"` // What jobs using the dataset read
+	Description string         `json:"description,omitempty"`
+	Format      string         `json:"format,omitempty"`
+	SizeBytes   int64          `json:"size_bytes"`
+	Checksum    string         `json:"checksum,omitempty"`
+	NumRows     *int64         `json:"num_rows,omitempty"`
+	NumColumns  *int64         `json:"num_columns,omitempty"`
+	Columns     []string       `json:"columns,omitempty"`
+	Schema      []datasetField `json:"schema,omitempty"`
+	CreatedAt   string         `json:"created_at"`
+}
+
+func (d *storageDataset) toRegistered() registeredDataset {
+	return registeredDataset{
+		DatasetID:   d.ID,
+		Name:        d.Name,
+		Location:    d.MinioPath,
+		Description: d.Description,
+		Format:      d.Format,
+		SizeBytes:   d.SizeBytes,
+		Checksum:    d.Checksum,
+		NumRows:     d.NumRows,
+		NumColumns:  d.NumColumns,
+		Columns:     d.Columns,
+		Schema:      d.Schema,
+		CreatedAt:   d.CreatedAt,
+	}
+}
+
+// DatasetRegistration registers a dataset already in the storage service:
+// an object given as s3://<bucket>/<object>, or by its name in the datasets
+// bucket.
+type DatasetRegistration struct {
+	Name        string         `json:"name" binding:"required"`
+	Location    string         `json:"location" binding:"required"`
+	Description string         `json:"description,omitempty"`
+	Format      string         `json:"format,omitempty"`
+	SizeBytes   int64          `json:"size_bytes,omitempty"`
+	Checksum    string         `json:"checksum,omitempty"`
+	NumRows     *int64         `json:"num_rows,omitempty"`
+	Schema      []datasetField `json:"schema,omitempty" binding:"omitempty,dive"`
+}
+
+// handleCreateDataset registers a dataset given as JSON, or uploads one
+// sent as a multipart form with its data in the file field and optional
+// metadata JSON (name, description, format, num_rows, schema).
+func (gs *GatewayServer) handleCreateDataset(c *gin.Context) {
+	var contentType string
+	var body io.Reader
+	if strings.HasPrefix(c.ContentType(), "multipart/form-data") {
+		if c.Request.ContentLength > maxDatasetUpload {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+				"error": fmt.Sprintf("Datasets over %d bytes must be written to object storage and registered by location", maxDatasetUpload),
+			})
+			return
+		}
+		// The form is passed on to the storage service as it is
+		body = http.MaxBytesReader(c.Writer, c.Request.Body, maxDatasetUpload)
+		contentType = c.GetHeader("Content-Type")
+	} else {
+		var req DatasetRegistration
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		metadata, err := json.Marshal(req)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to register dataset"})
+			return
+		}
+		var buf bytes.Buffer
+		form := multipart.NewWriter(&buf)
+		if err := form.WriteField("metadata", string(metadata)); err != nil || form.Close() != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to register dataset"})
+			return
+		}
+		body, contentType = &buf, form.FormDataContentType()
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Minute)
+	defer cancel()
+
+	resp, err := gs.sendToStorage(ctx, c, http.MethodPost, nil, contentType, body, "api/v1/datasets")
+	if err != nil {
+		log.Printf("Error creating dataset: %v", err)
+		return
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusBadRequest:
+		c.JSON(http.StatusBadRequest, gin.H{"error": storageError(resp, "Invalid dataset")})
+		return
+	case resp.StatusCode != http.StatusCreated:
+		log.Printf("Error creating dataset: storage service returned status %d", resp.StatusCode)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to create dataset"})
+		return
+	}
+	var created struct {
+		Result struct {
+			DatasetID string `json:"dataset_id"`
+			MinioPath string `json:"minio_path"`
+			Checksum  string `json:"checksum"`
+		} `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Malformed response from storage service"})
+		return
+	}
+	log.Printf("Created dataset %s at %s", created.Result.DatasetID, created.Result.MinioPath)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"dataset_id": created.Result.DatasetID,
+		"location":   created.Result.MinioPath,
+		"checksum":   created.Result.Checksum,
+	})
+}
+
+// handleListDatasets lists registered datasets, newest first.
+func (gs *GatewayServer) handleListDatasets(c *gin.Context) {
+	query := url.Values{}
+	if l := c.Query("limit"); l != "" {
+		limit, err := strconv.Atoi(l)
+		if err != nil || limit <= 0 || limit > 500 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 500"})
+			return
+		}
+		query.Set("limit", l)
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	resp, err := gs.storageRequest(ctx, c, http.MethodGet, query, "api/v1/datasets")
+	if err != nil {
+		log.Printf("Error listing datasets: %v", err)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		log.Printf("Error listing datasets: storage service returned status %d", resp.StatusCode)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to list datasets"})
+		return
+	}
+	var listing struct {
+		Datasets []storageDataset `json:"datasets"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&listing); err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Malformed response from storage service"})
+		return
+	}
+
+	datasets := make([]registeredDataset, 0, len(listing.Datasets))
+	for i := range listing.Datasets {
+		datasets = append(datasets, listing.Datasets[i].toRegistered())
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"datasets": datasets,
+		"total":    len(datasets),
+	})
+}
+
+// getDataset reads a registered dataset. It writes the error response and
+// returns nil if it cannot; missing datasets are answered with notFound.
+func (gs *GatewayServer) getDataset(ctx context.Context, c *gin.Context, datasetID string, notFound int) *storageDataset {
+	resp, err := gs.storageRequest(ctx, c, http.MethodGet, nil, "api/v1/datasets", datasetID)
+	if err != nil {
+		log.Printf("Error getting dataset %s: %v", datasetID, err)
+		return nil
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		c.JSON(notFound, gin.H{"error": "Dataset not found: " + datasetID})
+		return nil
+	case resp.StatusCode != http.StatusOK:
+		log.Printf("Error getting dataset %s: storage service returned status %d", datasetID, resp.StatusCode)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to get dataset"})
+		return nil
+	}
+	var dataset storageDataset
+	if err := json.NewDecoder(resp.Body).Decode(&dataset); err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Malformed response from storage service"})
+		return nil
+	}
+	return &dataset
+}
+
+func (gs *GatewayServer) handleGetDataset(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	if dataset := gs.getDataset(ctx, c, c.Param("id"), http.StatusNotFound); dataset != nil {
+		c.JSON(http.StatusOK, dataset.toRegistered())
+	}
+}
+
+// resolveDatasets replaces the dataset IDs a job submission gives with the
+// locations of the datasets, which is what the orchestrator plans shards
+// from. It writes the error response and returns false if it cannot.
+func (gs *GatewayServer) resolveDatasets(c *gin.Context, req *JobSubmitRequest) bool {
+	refs := []struct {
+		field, id string
+		path      *string
+	}{
+		{"dataset", req.DatasetID, &req.DatasetPath},
+		{"validation_dataset", req.ValidationDatasetID, &req.ValidationDatasetPath},
+	}
+	for _, ref := range refs {
+		if ref.id == "" {
+			continue
+		}
+		if *ref.path != "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Give %s_id or %s_path, not both", ref.field, ref.field)})
+			return false
+		}
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+		dataset := gs.getDataset(ctx, c, ref.id, http.StatusBadRequest)
+		cancel()
+		if dataset == nil {
+			return false
+		}
+		*ref.path = dataset.MinioPath
+	}
+	if req.DatasetPath == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "dataset_id or dataset_path is required"})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseFieldSelection reads the ?fields=job_id,status,progress query parameter.
+// A nil result means the caller asked for the full payload.
+func parseFieldSelection(c *gin.Context) map[string]bool {
+	raw := c.Query("fields")
+	if raw == "" {
+		return nil
+	}
+
+	fields := make(map[string]bool)
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		if name != "" {
+			fields[name] = true
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return fields
+}
+
+// shapeFields trims a response down to the selected top-level JSON fields.
+// It works on anything that encodes to a JSON object (structs, gin.H, maps),
+// so handlers keep building their full responses and shape them last.
+func shapeFields[T any](v T, fields map[string]bool) interface{} {
+	if fields == nil {
+		return v
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+
+	var full map[string]json.RawMessage
+	if err := json.Unmarshal(data, &full); err != nil {
+		// Not a JSON object - nothing to select from
+		return v
+	}
+
+	shaped := make(map[string]json.RawMessage, len(fields))
+	for name, value := range full {
+		if fields[name] {
+			shaped[name] = value
+		}
+	}
+	return shaped
+}
+
+// shapeEach applies shapeFields to every element of a list response.
+func shapeEach[T any](items []T, fields map[string]bool) interface{} {
+	if fields == nil {
+		return items
+	}
+
+	shaped := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		shaped = append(shaped, shapeFields(item, fields))
+	}
+	return shaped
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// leaderMetadataKey is the trailer in which a standby orchestrator names the
+// current leader.
+const leaderMetadataKey = "orchestrator-leader"
+
+// leaderResolver sends orchestrator calls to the current leader. Calls start
+// at the configured address; when a standby answers with the leader's
+// address the resolver switches to it, and falls back to the configured
+// address when the leader becomes unreachable.
+type leaderResolver struct {
+	seedAddr string
+
+	mu     sync.Mutex
+	leader string
+	conns  map[string]*grpc.ClientConn
+}
+
+func newLeaderResolver(seedAddr string) *leaderResolver {
+	return &leaderResolver{
+		seedAddr: seedAddr,
+		leader:   seedAddr,
+		conns:    make(map[string]*grpc.ClientConn),
+	}
+}
+
+// dial returns the connection the gateway client is built on. Its
+// interceptors send every call over the connection to the current leader.
+func (r *leaderResolver) dial() (*grpc.ClientConn, error) {
+	return grpc.Dial(r.seedAddr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(r.unaryInterceptor),
+		grpc.WithStreamInterceptor(r.streamInterceptor),
+	)
+}
+
+func (r *leaderResolver) connFor(addr string) (*grpc.ClientConn, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if conn, ok := r.conns[addr]; ok {
+		return conn, nil
+	}
+	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	r.conns[addr] = conn
+	return conn, nil
+}
+
+func (r *leaderResolver) current() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.leader
+}
+
+// follow updates the leader after a call failed with Unavailable. It returns
+// true if the call is worth retrying against a different address.
+func (r *leaderResolver) follow(tried string, trailer metadata.MD) bool {
+	next := r.seedAddr
+	if values := trailer.Get(leaderMetadataKey); len(values) > 0 && values[0] != "" {
+		next = values[0]
+	}
+	if next == tried {
+		return false
+	}
+
+	r.mu.Lock()
+	r.leader = next
+	r.mu.Unlock()
+	log.Printf("Orchestrator %s unavailable, switching to %s", tried, next)
+	return true
+}
+
+func (r *leaderResolver) unaryInterceptor(ctx context.Context, method string, req, reply interface{}, _ *grpc.ClientConn, _ grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	// One retry covers both a standby redirect and a failed leader
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		addr := r.current()
+		conn, dialErr := r.connFor(addr)
+		if dialErr != nil {
+			return dialErr
+		}
+
+		var trailer metadata.MD
+		err = conn.Invoke(ctx, method, req, reply, append(opts, grpc.Trailer(&trailer))...)
+		if status.Code(err) != codes.Unavailable || !r.follow(addr, trailer) {
+			return err
+		}
+	}
+	return err
+}
+
+// streamInterceptor opens streams on the current leader. A stream rejected
+// by a standby is not retried, but later calls go to the new leader.
+func (r *leaderResolver) streamInterceptor(ctx context.Context, desc *grpc.StreamDesc, _ *grpc.ClientConn, method string, _ grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	addr := r.current()
+	conn, err := r.connFor(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := conn.NewStream(ctx, desc, method, opts...)
+	if err != nil {
+		if status.Code(err) == codes.Unavailable {
+			r.follow(addr, nil)
+		}
+		return nil, err
+	}
+	return &leaderStream{ClientStream: stream, resolver: r, addr: addr}, nil
+}
+
+type leaderStream struct {
+	grpc.ClientStream
+	resolver *leaderResolver
+	addr     string
+}
+
+func (s *leaderStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if status.Code(err) == codes.Unavailable {
+		s.resolver.follow(s.addr, s.Trailer())
+	}
+	return err
+}
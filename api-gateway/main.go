@@ -2,19 +2,21 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"os"
-	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-redis/redis/v8"
 	"github.com/google/uuid"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
 
 	orchestratorpb "github.com/tensorfleet/api-gateway/proto/orchestrator"
 )
@@ -27,17 +29,12 @@ func min(a, b int) int {
 	return b
 }
 
-// Helper function to get maximum of two int64 values (ensures non-negative uptime)
-func max(a, b int64) int64 {
-	if a > b {
-		return a
-	}
-	return b
-}
-
 type GatewayServer struct {
 	orchestratorClient orchestratorpb.OrchestratorServiceClient
+	adminClient        orchestratorpb.OrchestratorAdminServiceClient
+	orchestratorHealth healthpb.HealthClient
 	redisClient        *redis.Client
+	storageURL         string // Storage service holding task artifacts, datasets, checkpoints and saved models
 	router             *gin.Engine
 }
 
@@ -49,7 +46,8 @@ func NewGatewayServer() (*GatewayServer, error) {
 	}
 
 	log.Printf("Connecting to orchestrator at %s", orchestratorAddr)
-	conn, err := grpc.Dial(orchestratorAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	// Orchestrator replicas may run with leader election; follow the leader
+	conn, err := newLeaderResolver(orchestratorAddr).dial()
 	if err != nil {
 		return nil, err
 	}
@@ -94,7 +92,10 @@ func NewGatewayServer() (*GatewayServer, error) {
 
 	gs := &GatewayServer{
 		orchestratorClient: client,
+		adminClient:        orchestratorpb.NewOrchestratorAdminServiceClient(conn),
+		orchestratorHealth: healthpb.NewHealthClient(conn),
 		redisClient:        rdb,
+		storageURL:         storageServiceURL(),
 		router:             router,
 	}
 
@@ -108,6 +109,9 @@ func (gs *GatewayServer) setupRoutes() {
 		c.JSON(http.StatusOK, gin.H{"status": "healthy"})
 	})
 
+	// Readiness: the gateway is only useful while the orchestrator serves
+	gs.router.GET("/ready", gs.handleReady)
+
 	// Worker activity endpoint (public, no auth required for demo)
 	gs.router.GET("/worker-activity", gs.handleWorkerActivity)
 
@@ -117,18 +121,167 @@ func (gs *GatewayServer) setupRoutes() {
 		api.POST("/jobs", gs.handleSubmitJob)
 		api.GET("/jobs/:id", gs.handleGetJobStatus)
 		api.GET("/jobs/:id/logs", gs.handleGetJobLogs)
+		api.GET("/jobs/:id/logs/entries", gs.handleGetJobLogEntries)
+		api.GET("/jobs/:id/metrics", gs.handleGetJobMetricsHistory)
+		api.GET("/jobs/:id/events", gs.handleGetJobEvents)
+		api.GET("/jobs/:id/tasks", gs.handleGetJobTasks)
+		api.GET("/jobs/:id/artifacts", gs.handleListJobArtifacts)
+		api.GET("/jobs/:id/artifacts/*path", gs.handleGetJobArtifact)
+		api.GET("/jobs/:id/checkpoints", gs.handleListJobCheckpoints)
+		api.POST("/jobs/:id/checkpoints/cleanup", gs.handleCleanupJobCheckpoints)
+		api.GET("/jobs/:id/checkpoints/retention", gs.handleGetCheckpointRetention)
+		api.PUT("/jobs/:id/checkpoints/retention", gs.handleSetCheckpointRetention)
+		api.DELETE("/jobs/:id/checkpoints/retention", gs.handleDeleteCheckpointRetention)
+		api.GET("/jobs/:id/checkpoints/:checkpoint_id/weights", gs.handleDownloadJobCheckpoint)
+		api.DELETE("/jobs/:id/checkpoints/:checkpoint_id", gs.handleDeleteJobCheckpoint)
+		api.POST("/jobs/:id/checkpoints/:checkpoint_id/pin", gs.handlePinJobCheckpoint)
+		api.DELETE("/jobs/:id/checkpoints/:checkpoint_id/pin", gs.handlePinJobCheckpoint)
 		api.GET("/jobs", gs.handleListJobs)
+		api.POST("/datasets", gs.handleCreateDataset)
+		api.GET("/datasets", gs.handleListDatasets)
+		api.GET("/datasets/:id", gs.handleGetDataset)
+		api.GET("/models", gs.handleListModels)
+		api.GET("/models/:id", gs.handleGetModel)
+		api.GET("/models/:id/lineage", gs.handleGetModelLineage)
+		api.GET("/models/:id/weights", gs.handleDownloadModel)
+		api.DELETE("/models/:id", gs.handleDeleteModel)
 		api.DELETE("/jobs/:id", gs.handleCancelJob)
+		api.POST("/jobs/:id/pause", gs.handlePauseJob)
+		api.POST("/jobs/:id/resume", gs.handleResumeJob)
 		api.GET("/workers", gs.handleGetWorkers)
+		api.POST("/workers/:id/unquarantine", gs.handleUnquarantineWorker)
+		api.GET("/usage", gs.handleGetUsage)
+		api.GET("/users/:id/usage", gs.handleGetUsage)
+		api.PUT("/users/:id/quota", gs.handleSetUserQuota)
+		api.GET("/cluster/summary", gs.handleGetClusterSummary)
+
+		// Operator interventions, audited under the X-User-ID operator
+		api.POST("/admin/jobs/:id/fail", gs.handleForceFailJob)
+		api.POST("/admin/jobs/:id/tasks/:task_id/requeue", gs.handleRequeueTask)
+		api.POST("/admin/workers/:id/drain", gs.handleDrainWorker)
+		api.POST("/admin/snapshots", gs.handleExportSnapshot)
+		api.POST("/admin/snapshots/import", gs.handleImportSnapshot)
 	}
 }
 
 type JobSubmitRequest struct {
 	ModelType       string            `json:"model_type" binding:"required"`
-	DatasetPath     string            `json:"dataset_path" binding:"required"`
+	DatasetPath     string            `json:"dataset_path"`
+	DatasetID       string            `json:"dataset_id"` // A registered dataset, instead of dataset_path
 	Hyperparameters map[string]string `json:"hyperparameters"`
 	NumWorkers      int32             `json:"num_workers"`
 	Epochs          int32             `json:"epochs"`
+
+	// Optional limits enforced by the orchestrator's job watchdog
+	MaxDurationSeconds     int64 `json:"max_duration_seconds"`
+	ProgressTimeoutSeconds int64 `json:"progress_timeout_seconds"`
+
+	// Optional failure policy; retries fall back to the orchestrator default
+	MaxTaskRetries        *int32  `json:"max_task_retries"`
+	MaxFailedTasksPercent float64 `json:"max_failed_tasks_percent"`
+
+	// Scheduling priority, higher runs first
+	Priority int32 `json:"priority"`
+
+	// Optional constraints on the workers that may run the job
+	Requirements *ResourceRequirements `json:"requirements"`
+
+	// Run epochs one at a time instead of overlapping them
+	SyncEpochs bool `json:"sync_epochs"`
+
+	// Start only once num_workers workers are free to run the job together
+	GangScheduling bool `json:"gang_scheduling"`
+
+	// Most of the job's tasks running at once, e.g. for rate-limited data
+	// sources; 0 leaves it uncapped
+	MaxParallelTasks int32 `json:"max_parallel_tasks"`
+
+	// Container image the tasks run in on workers using the docker
+	// executor; empty for the worker's default
+	Image string `json:"image"`
+
+	// "preprocess" and/or "evaluate" tasks to run before and after
+	// training
+	Stages []string `json:"stages"`
+
+	// Held-out dataset every epoch is validated on; empty to skip
+	ValidationDatasetPath string `json:"validation_dataset_path"`
+	ValidationDatasetID   string `json:"validation_dataset_id"`
+
+	// What the task result cache may do for the job: "read_write"
+	// (default) reuses results of identical tasks, "refresh" recomputes
+	// them, "off" bypasses the cache
+	ResultCache string `json:"result_cache"`
+
+	// How worker weights are merged each epoch, e.g. "average" (default),
+	// "weighted_average", "trimmed_mean" or "sum"
+	Aggregation string `json:"aggregation"`
+
+	// Checkpoint after every N epochs so the job can be resumed; 0 disables
+	CheckpointEveryEpochs int32 `json:"checkpoint_every_epochs"`
+
+	// Seconds a worker may spend on one task before it is requeued; 0 lets
+	// the orchestrator derive it from observed task durations
+	TaskTimeoutSeconds int64 `json:"task_timeout_seconds"`
+
+	// Task layout; with dataset_size the orchestrator derives whichever of
+	// the other two is missing
+	BatchesPerEpoch int32 `json:"batches_per_epoch"`
+	SamplesPerBatch int32 `json:"samples_per_batch"`
+	DatasetSize     int64 `json:"dataset_size"`
+
+	// Optional: stop once the monitored metric stops improving
+	EarlyStopping *EarlyStopping `json:"early_stopping"`
+
+	// Optional: jobs that must complete first, and whether to "fail"
+	// (default), "cancel" or "ignore" when one of them does not
+	DependsOn           []string `json:"depends_on"`
+	OnDependencyFailure string   `json:"on_dependency_failure"`
+
+	// Project the job belongs to; "default" when empty
+	Namespace string `json:"namespace"`
+
+	// Regional orchestrator to run the job on; picked by dataset locality
+	// or capacity when empty, "local" for the gateway's orchestrator
+	Region string `json:"region"`
+}
+
+type EarlyStopping struct {
+	Metric   string  `json:"metric"` // "loss" (default), "accuracy", "val_loss" or "val_accuracy"
+	Patience int32   `json:"patience"`
+	MinDelta float64 `json:"min_delta"`
+}
+
+func (e *EarlyStopping) toProto() *orchestratorpb.EarlyStopping {
+	if e == nil {
+		return nil
+	}
+	return &orchestratorpb.EarlyStopping{
+		Metric:   e.Metric,
+		Patience: e.Patience,
+		MinDelta: e.MinDelta,
+	}
+}
+
+type ResourceRequirements struct {
+	MinCPUCores int32             `json:"min_cpu_cores"`
+	MinMemoryMB int64             `json:"min_memory_mb"`
+	MinGPUCount int32             `json:"min_gpu_count"`
+	GPUType     string            `json:"gpu_type"`
+	Labels      map[string]string `json:"labels"`
+}
+
+func (r *ResourceRequirements) toProto() *orchestratorpb.ResourceRequirements {
+	if r == nil {
+		return nil
+	}
+	return &orchestratorpb.ResourceRequirements{
+		MinCpuCores: r.MinCPUCores,
+		MinMemoryMb: r.MinMemoryMB,
+		MinGpuCount: r.MinGPUCount,
+		GpuType:     r.GPUType,
+		Labels:      r.Labels,
+	}
 }
 
 func (gs *GatewayServer) handleSubmitJob(c *gin.Context) {
@@ -137,6 +290,9 @@ func (gs *GatewayServer) handleSubmitJob(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if !gs.resolveDatasets(c, &req) {
+		return
+	}
 
 	// Generate job ID
 	jobID := uuid.New().String()
@@ -160,46 +316,65 @@ func (gs *GatewayServer) handleSubmitJob(c *gin.Context) {
 	resp, err := gs.orchestratorClient.CreateTrainingJob(ctx, &orchestratorpb.TrainingJobRequest{
 		JobId:           jobID,
 		UserId:          userID,
+		Namespace:       req.Namespace,
+		Region:          req.Region,
 		ModelType:       req.ModelType,
 		DatasetPath:     req.DatasetPath,
 		Hyperparameters:  req.Hyperparameters,
 		NumWorkers:      req.NumWorkers,
 		Epochs:          req.Epochs,
+
+		MaxDurationSeconds:     req.MaxDurationSeconds,
+		ProgressTimeoutSeconds: req.ProgressTimeoutSeconds,
+		MaxTaskRetries:         req.MaxTaskRetries,
+		MaxFailedTasksPercent:  req.MaxFailedTasksPercent,
+		Priority:               req.Priority,
+		Requirements:           req.Requirements.toProto(),
+		SyncEpochs:             req.SyncEpochs,
+		GangScheduling:         req.GangScheduling,
+		MaxParallelTasks:       req.MaxParallelTasks,
+		Image:                  req.Image,
+		Stages:                 req.Stages,
+		ValidationDatasetPath:  req.ValidationDatasetPath,
+		ResultCache:            req.ResultCache,
+		Aggregation:            req.Aggregation,
+		CheckpointEveryEpochs:  req.CheckpointEveryEpochs,
+		TaskTimeoutSeconds:     req.TaskTimeoutSeconds,
+		BatchesPerEpoch:        req.BatchesPerEpoch,
+		SamplesPerBatch:        req.SamplesPerBatch,
+		DatasetSize:            req.DatasetSize,
+		EarlyStopping:          req.EarlyStopping.toProto(),
+		DependsOn:              req.DependsOn,
+		OnDependencyFailure:    req.OnDependencyFailure,
 	})
 
+	if status.Code(err) == codes.ResourceExhausted {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": status.Convert(err).Message()})
+		return
+	}
 	if err != nil {
 		log.Printf("Error creating job: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create job"})
 		return
 	}
-
-	// Store job metadata in Redis for history
-	jobMetadata := map[string]interface{}{
-		"job_id":       jobID,
-		"user_id":      userID,
-		"model_type":   req.ModelType,
-		"dataset_path": req.DatasetPath,
-		"status":       resp.Status,
-		"total_tasks":  resp.NumTasks,
-		"completed_tasks": 0,
-		"created_at":   time.Now().Unix(),
+	if resp.Status == "FAILED" {
+		// Rejected while planning shards, e.g. the dataset does not exist
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"job_id": resp.JobId,
+			"status": resp.Status,
+			"error":  resp.Message,
+		})
+		return
 	}
 
-	jobJSON, err := json.Marshal(jobMetadata)
-	if err == nil {
-		// Store job in Redis with 7 day expiration
-		gs.redisClient.Set(ctx, fmt.Sprintf("job:%s", jobID), jobJSON, 7*24*time.Hour)
-		log.Printf("Stored job %s metadata in Redis", jobID)
-	} else {
-		log.Printf("Warning: Failed to store job metadata in Redis: %v", err)
-	}
+	// The orchestrator keeps the job record and its history; see JOB_RETENTION
 
-	c.JSON(http.StatusAccepted, gin.H{
+	c.JSON(http.StatusAccepted, shapeFields(gin.H{
 		"job_id":    resp.JobId,
 		"status":    resp.Status,
 		"num_tasks": resp.NumTasks,
 		"message":   resp.Message,
-	})
+	}, parseFieldSelection(c)))
 }
 
 func (gs *GatewayServer) handleGetJobStatus(c *gin.Context) {
@@ -218,18 +393,300 @@ func (gs *GatewayServer) handleGetJobStatus(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	epochMetrics := make([]gin.H, 0, len(resp.EpochMetrics))
+	for _, m := range resp.EpochMetrics {
+		epochMetrics = append(epochMetrics, gin.H{
+			"epoch":           m.Epoch,
+			"loss":            m.Loss,
+			"accuracy":        m.Accuracy,
+			"completed_tasks": m.CompletedTasks,
+			"complete":        m.Complete,
+		})
+	}
+
+	validationMetrics := make([]gin.H, 0, len(resp.ValidationMetrics))
+	for _, m := range resp.ValidationMetrics {
+		validationMetrics = append(validationMetrics, gin.H{
+			"epoch":    m.Epoch,
+			"loss":     m.Loss,
+			"accuracy": m.Accuracy,
+		})
+	}
+
+	transitions := make([]gin.H, 0, len(resp.Transitions))
+	for _, t := range resp.Transitions {
+		transitions = append(transitions, gin.H{
+			"from":         jobStateName(t.From),
+			"to":           jobStateName(t.To),
+			"reason":       t.Reason,
+			"timestamp_ms": t.TimestampMs,
+		})
+	}
+
+	runningTasks := make([]gin.H, 0, len(resp.RunningTasks))
+	for _, p := range resp.RunningTasks {
+		runningTasks = append(runningTasks, taskProgressJSON(p))
+	}
+
+	c.JSON(http.StatusOK, shapeFields(gin.H{
 		"job_id":          resp.JobId,
+		"namespace":       resp.Namespace,
+		"region":          resp.Region,
 		"status":          resp.Status,
 		"progress":        resp.Progress,
 		"completed_tasks": resp.CompletedTasks,
+		"cached_tasks":    resp.CachedTasks,
 		"total_tasks":     resp.TotalTasks,
 		"current_loss":    resp.CurrentLoss,
 		"current_accuracy": resp.CurrentAccuracy,
 		"message":         resp.Message,
+		"num_workers":       resp.NumWorkers,
+		"allocated_workers": resp.AllocatedWorkers,
+		"queue_position":    resp.QueuePosition,
+		"epoch_metrics":     epochMetrics,
+		"validation_metrics": validationMetrics,
+		"transitions":       transitions,
+		"task_durations":    taskDurationsJSON(resp.TaskDurations),
+		"estimated_remaining_ms": resp.EstimatedRemainingMs,
+		"detailed_progress":      resp.DetailedProgress,
+		"running_tasks":          runningTasks,
+	}, parseFieldSelection(c)))
+}
+
+func (gs *GatewayServer) handleGetJobMetricsHistory(c *gin.Context) {
+	jobID := c.Param("id")
+
+	var since int64
+	if raw := c.Query("since"); raw != "" {
+		parsed, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "since must be a Unix timestamp in milliseconds"})
+			return
+		}
+		since = parsed
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := gs.orchestratorClient.GetJobMetricsHistory(ctx, &orchestratorpb.JobMetricsHistoryRequest{
+		JobId:       jobID,
+		Kind:        c.Query("kind"),
+		SinceUnixMs: since,
+	})
+	if err != nil {
+		log.Printf("Error getting job metrics history: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get job metrics history"})
+		return
+	}
+
+	samples := make([]gin.H, 0, len(resp.Samples))
+	for _, sample := range resp.Samples {
+		samples = append(samples, gin.H{
+			"kind":         sample.Kind,
+			"epoch":        sample.Epoch,
+			"task_id":      sample.TaskId,
+			"loss":         sample.Loss,
+			"accuracy":     sample.Accuracy,
+			"timestamp_ms": sample.TimestampMs,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"job_id":  resp.JobId,
+		"samples": samples,
+		"count":   len(samples),
+	})
+}
+
+func (gs *GatewayServer) handleGetJobEvents(c *gin.Context) {
+	jobID := c.Param("id")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := gs.orchestratorClient.GetJobEvents(ctx, &orchestratorpb.JobEventsRequest{
+		JobId: jobID,
+		Type:  c.Query("type"),
+	})
+	if err != nil {
+		log.Printf("Error getting job events: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get job events"})
+		return
+	}
+
+	events := make([]gin.H, 0, len(resp.Events))
+	for _, event := range resp.Events {
+		events = append(events, gin.H{
+			"type":         event.Type,
+			"message":      event.Message,
+			"task_id":      event.TaskId,
+			"worker_id":    event.WorkerId,
+			"timestamp_ms": event.TimestampMs,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"job_id": resp.JobId,
+		"events": events,
+		"count":  len(events),
 	})
 }
 
+func (gs *GatewayServer) handleGetJobTasks(c *gin.Context) {
+	req := &orchestratorpb.GetJobTasksRequest{
+		JobId:     c.Param("id"),
+		Status:    strings.ToUpper(c.Query("status")),
+		WorkerId:  c.Query("worker_id"),
+		PageToken: c.Query("page_token"),
+	}
+	for name, field := range map[string]*int32{"epoch": &req.Epoch, "limit": &req.PageSize} {
+		raw := c.Query(name)
+		if raw == "" {
+			continue
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": name + " must be a positive integer"})
+			return
+		}
+		*field = int32(n)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := gs.orchestratorClient.GetJobTasks(ctx, req)
+	if err != nil {
+		switch status.Code(err) {
+		case codes.NotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		case codes.InvalidArgument:
+			c.JSON(http.StatusBadRequest, gin.H{"error": status.Convert(err).Message()})
+		default:
+			log.Printf("Error getting job tasks: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get job tasks"})
+		}
+		return
+	}
+
+	tasks := make([]gin.H, 0, len(resp.Tasks))
+	for _, task := range resp.Tasks {
+		var progress gin.H
+		if task.Progress != nil {
+			progress = taskProgressJSON(task.Progress)
+		}
+		var usage gin.H
+		if task.ResourceUsage != nil {
+			usage = taskResourceUsageJSON(task.ResourceUsage)
+		}
+		tasks = append(tasks, gin.H{
+			"task_id":             task.TaskId,
+			"type":                task.Type,
+			"status":              task.Status,
+			"worker_id":           task.WorkerId,
+			"epoch":               task.Epoch,
+			"batch_start":         task.BatchStart,
+			"batch_end":           task.BatchEnd,
+			"loss":                task.Loss,
+			"accuracy":            task.Accuracy,
+			"attempts":            task.Attempts,
+			"last_error":          task.LastError,
+			"created_at_ms":       task.CreatedAtMs,
+			"assigned_at_ms":      task.AssignedAtMs,
+			"completed_at_ms":     task.CompletedAtMs,
+			"lease_expires_at_ms": task.LeaseExpiresAtMs,
+			"wait_ms":             task.WaitMs,
+			"run_ms":              task.RunMs,
+			"straggling":          task.Straggling,
+			"progress":            progress,
+			"resource_usage":      usage,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"job_id":          resp.JobId,
+		"tasks":           shapeEach(tasks, parseFieldSelection(c)),
+		"total":           resp.Total,
+		"next_page_token": resp.NextPageToken,
+		"durations":       taskDurationsJSON(resp.Durations),
+	})
+}
+
+// taskProgressJSON renders the progress a worker reported for a running
+// task.
+func taskProgressJSON(p *orchestratorpb.TaskProgress) gin.H {
+	return gin.H{
+		"task_id":        p.TaskId,
+		"worker_id":      p.WorkerId,
+		"batches_done":   p.BatchesDone,
+		"batches_total":  p.BatchesTotal,
+		"loss":           p.Loss,
+		"accuracy":       p.Accuracy,
+		"reported_at_ms": p.ReportedAtMs,
+	}
+}
+
+// taskResourceUsageJSON renders what a task's last attempt used of its
+// resource limits.
+func taskResourceUsageJSON(u *orchestratorpb.TaskResourceUsage) gin.H {
+	return gin.H{
+		"cpu_limit_cores":       u.CpuLimitCores,
+		"memory_limit_bytes":    u.MemoryLimitBytes,
+		"peak_memory_bytes":     u.PeakMemoryBytes,
+		"cpu_seconds":           u.CpuSeconds,
+		"cpu_throttled_seconds": u.CpuThrottledSeconds,
+		"oom_killed":            u.OomKilled,
+	}
+}
+
+// taskDurationsJSON renders task duration percentiles.
+func taskDurationsJSON(d *orchestratorpb.TaskDurationStats) gin.H {
+	return gin.H{
+		"samples": d.GetSamples(),
+		"p50_ms":  d.GetP50Ms(),
+		"p95_ms":  d.GetP95Ms(),
+		"p99_ms":  d.GetP99Ms(),
+	}
+}
+
+// jobStateName renders a job state the way job statuses appear elsewhere in
+// the API, e.g. JOB_STATE_RUNNING as "RUNNING".
+func jobStateName(state orchestratorpb.JobState) string {
+	return strings.TrimPrefix(state.String(), "JOB_STATE_")
+}
+
+// watchJobStatus subscribes to the orchestrator's status stream for a job.
+// The returned channel is closed when the stream ends or ctx is cancelled.
+func (gs *GatewayServer) watchJobStatus(ctx context.Context, jobID string) (<-chan *orchestratorpb.GetJobStatusResponse, error) {
+	stream, err := gs.orchestratorClient.WatchJobStatus(ctx, &orchestratorpb.WatchJobStatusRequest{
+		JobId: jobID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(chan *orchestratorpb.GetJobStatusResponse)
+	go func() {
+		defer close(updates)
+		for {
+			resp, err := stream.Recv()
+			if err != nil {
+				if err != io.EOF && ctx.Err() == nil {
+					log.Printf("Job status stream for %s failed: %v", jobID, err)
+				}
+				return
+			}
+			select {
+			case updates <- resp:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return updates, nil
+}
+
 func (gs *GatewayServer) handleGetJobLogs(c *gin.Context) {
 	jobID := c.Param("id")
 
@@ -270,28 +727,41 @@ func (gs *GatewayServer) handleGetJobLogs(c *gin.Context) {
 	sendLog("INFO", fmt.Sprintf("Distributing %d tasks across workers", resp.TotalTasks))
 	time.Sleep(100 * time.Millisecond)
 
-	// Stream logs with updates every 2 seconds
-	ticker := time.NewTicker(2 * time.Second)
-	defer ticker.Stop()
-
+	// Stream logs whenever the orchestrator reports a change
 	clientGone := c.Request.Context().Done()
-	
+	updates, err := gs.watchJobStatus(c.Request.Context(), jobID)
+	if err != nil {
+		sendLog("ERROR", fmt.Sprintf("Failed to watch job status: %v", err))
+		return
+	}
+
+	// Interleave the training logs workers ship, filtered like
+	// /logs/entries and starting with the last ?tail lines
+	filter, _ := taskLogFilterFromQuery(c)
+	tail := int64(defaultTaskLogTail)
+	if n, err := strconv.Atoi(c.Query("tail")); err == nil && n >= 0 {
+		tail = int64(n)
+	}
+	taskLogs := gs.followTaskLogs(c.Request.Context(), jobID, tail)
+
 	// Stream continuously until job completes, fails, is cancelled, or client disconnects
 	for {
 		select {
 		case <-clientGone:
 			log.Printf("Client disconnected from log stream for job %s", jobID)
 			return
-		case <-ticker.C:
-			// Get updated status
-			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-			resp, err := gs.orchestratorClient.GetJobStatus(ctx, &orchestratorpb.GetJobStatusRequest{
-				JobId: jobID,
-			})
-			cancel()
-
-			if err != nil {
-				sendLog("ERROR", fmt.Sprintf("Failed to get job status: %v", err))
+		case entry, ok := <-taskLogs:
+			if !ok {
+				taskLogs = nil
+				continue
+			}
+			if filter.matches(entry) {
+				sendLog(entry.Level, fmt.Sprintf("[worker %s, task %s] %s",
+					entry.WorkerID[:min(8, len(entry.WorkerID))], entry.TaskID[:min(8, len(entry.TaskID))], entry.Message))
+			}
+		case resp, ok := <-updates:
+			if !ok {
+				sendLog("ERROR", "Job status stream ended unexpectedly")
 				return
 			}
 
@@ -313,6 +783,12 @@ func (gs *GatewayServer) handleGetJobLogs(c *gin.Context) {
 					resp.CurrentLoss, resp.CurrentAccuracy))
 				sendLog("INFO", "Log streaming ended")
 				return
+			} else if resp.Status == "COMPLETED_EARLY" {
+				sendLog("INFO", fmt.Sprintf("Job %s stopped early: %s", jobID, resp.Message))
+				sendLog("INFO", fmt.Sprintf("Final metrics - Loss: %.4f, Accuracy: %.4f", 
+					resp.CurrentLoss, resp.CurrentAccuracy))
+				sendLog("INFO", "Log streaming ended")
+				return
 			} else if resp.Status == "FAILED" {
 				sendLog("ERROR", fmt.Sprintf("Job %s failed", jobID))
 				sendLog("INFO", "Log streaming ended")
@@ -387,104 +863,106 @@ func (gs *GatewayServer) handleListJobs(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	// Get all job keys from Redis
-	keys, err := gs.redisClient.Keys(ctx, "job:*").Result()
-	if err != nil {
-		log.Printf("Error fetching job keys from Redis: %v", err)
-		c.JSON(http.StatusOK, gin.H{
-			"jobs":  []interface{}{},
-			"total": 0,
-		})
-		return
-	}
-
-	type JobSummary struct {
-		JobID        string  `json:"job_id"`
-		ModelType    string  `json:"model_type"`
-		Status       string  `json:"status"`
-		Progress     int32   `json:"progress"`
-		TotalTasks   int32   `json:"total_tasks"`
-		CompletedTasks int32 `json:"completed_tasks"`
-		CreatedAt    int64   `json:"created_at"`
-		UserID       string  `json:"user_id,omitempty"`
+	req := &orchestratorpb.ListJobsRequest{
+		UserId:    c.Query("user_id"),
+		Namespace: c.Query("namespace"),
+		ModelType: c.Query("model_type"),
+		PageToken: c.Query("page_token"),
 	}
-
-	jobs := []JobSummary{}
-
-	for _, key := range keys {
-		// Get job data from Redis
-		jobData, err := gs.redisClient.Get(ctx, key).Result()
-		if err != nil {
-			log.Printf("Error fetching job %s: %v", key, err)
-			continue
-		}
-
-		// Parse job data (field names match orchestrator's uppercase format)
-		var job struct {
-			JobID          string `json:"JobID"`
-			UserID         string `json:"UserID"`
-			ModelType      string `json:"ModelType"`
-			DatasetPath    string `json:"DatasetPath"`
-			Status         string `json:"Status"`
-			TotalTasks     int32  `json:"TotalTasks"`
-			CompletedTasks int32  `json:"CompletedTasks"`
-			CreatedAt      string `json:"CreatedAt"` // RFC3339 timestamp
+	if statuses := c.Query("status"); statuses != "" {
+		for _, name := range strings.Split(statuses, ",") {
+			state, ok := orchestratorpb.JobState_value["JOB_STATE_"+strings.ToUpper(strings.TrimSpace(name))]
+			if !ok {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown job status: " + name})
+				return
+			}
+			req.States = append(req.States, orchestratorpb.JobState(state))
 		}
-
-		if err := json.Unmarshal([]byte(jobData), &job); err != nil {
-			log.Printf("Error parsing job %s: %v", key, err)
-			continue
+	}
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
 		}
+		req.PageSize = int32(n)
+	}
 
-		// Calculate progress
-		progress := int32(0)
-		if job.TotalTasks > 0 {
-			progress = int32(float64(job.CompletedTasks) / float64(job.TotalTasks) * 100)
+	resp, err := gs.orchestratorClient.ListJobs(ctx, req)
+	if err != nil {
+		if status.Code(err) == codes.InvalidArgument {
+			c.JSON(http.StatusBadRequest, gin.H{"error": status.Convert(err).Message()})
+			return
 		}
-
-		// Try to get fresh status from orchestrator
-		statusResp, err := gs.orchestratorClient.GetJobStatus(ctx, &orchestratorpb.GetJobStatusRequest{
-			JobId: job.JobID,
+		log.Printf("Error listing jobs: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to list jobs",
+			"details": err.Error(),
 		})
+		return
+	}
 
-		if err == nil && statusResp != nil {
-			job.Status = statusResp.Status
-			job.CompletedTasks = statusResp.CompletedTasks
-			job.TotalTasks = statusResp.TotalTasks
-			progress = statusResp.Progress
-		}
-
-		// Parse timestamp
-		createdAtUnix := int64(0)
-		if job.CreatedAt != "" {
-			if t, err := time.Parse(time.RFC3339, job.CreatedAt); err == nil {
-				createdAtUnix = t.Unix()
-			}
-		}
+	type JobSummary struct {
+		JobID          string `json:"job_id"`
+		ModelType      string `json:"model_type"`
+		Status         string `json:"status"`
+		Progress       int32  `json:"progress"`
+		TotalTasks     int32  `json:"total_tasks"`
+		CompletedTasks int32  `json:"completed_tasks"`
+		CreatedAt      int64  `json:"created_at"`
+		UserID         string `json:"user_id,omitempty"`
+		Namespace      string `json:"namespace"`
+		Region         string `json:"region,omitempty"`
+	}
 
+	jobs := make([]JobSummary, 0, len(resp.Jobs))
+	for _, job := range resp.Jobs {
 		jobs = append(jobs, JobSummary{
-			JobID:          job.JobID,
+			JobID:          job.JobId,
 			ModelType:      job.ModelType,
 			Status:         job.Status,
-			Progress:       progress,
+			Progress:       job.Progress,
 			TotalTasks:     job.TotalTasks,
 			CompletedTasks: job.CompletedTasks,
-			CreatedAt:      createdAtUnix,
-			UserID:         job.UserID,
+			CreatedAt:      job.CreatedAtMs / 1000,
+			UserID:         job.UserId,
+			Namespace:      job.Namespace,
+			Region:         job.Region,
 		})
 	}
 
-	// Sort jobs by creation time (newest first)
-	sort.Slice(jobs, func(i, j int) bool {
-		return jobs[i].CreatedAt > jobs[j].CreatedAt
-	})
-
 	c.JSON(http.StatusOK, gin.H{
-		"jobs":  jobs,
-		"total": len(jobs),
+		"jobs":            shapeEach(jobs, parseFieldSelection(c)),
+		"total":           resp.Total,
+		"next_page_token": resp.NextPageToken,
 	})
 }
 
+// handleReady reports whether the orchestrator answers its gRPC health check
+// with SERVING, following the leader when replicas run with leader election.
+func (gs *GatewayServer) handleReady(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	resp, err := gs.orchestratorHealth.Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status":  "not ready",
+			"details": err.Error(),
+		})
+		return
+	}
+	if resp.Status != healthpb.HealthCheckResponse_SERVING {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"status":       "not ready",
+			"orchestrator": resp.Status.String(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "ready", "orchestrator": resp.Status.String()})
+}
+
 func (gs *GatewayServer) handleWorkerActivity(c *gin.Context) {
 	log.Printf("!!! FIXED HANDLER CALLED !!! Using gRPC instead of HTTP proxy")
 	
@@ -518,21 +996,29 @@ func (gs *GatewayServer) handleWorkerActivity(c *gin.Context) {
 			busyWorkers++
 		}
 
-		// Calculate uptime (assuming workers started when they first contacted orchestrator)
-		// Ensure uptime is never negative by using max(0, calculated_uptime)
-		uptime := max(0, time.Now().Unix() - worker.LastActivityTime)
+		// Host load as of the worker's latest heartbeat
+		load := worker.GetLoad()
 
 		workers = append(workers, map[string]interface{}{
-			"worker_id":           worker.WorkerId,
-			"status":              worker.Status,
-			"current_task_id":     worker.CurrentTaskId,
-			"current_job_id":      worker.CurrentJobId,
-			"tasks_completed":     worker.TasksCompleted,
-			"last_activity_time":  worker.LastActivityTime,
-			"cpu_usage":           50 + (len(worker.WorkerId)%30), // Simple deterministic "random" based on worker ID
-			"memory_usage":        40 + (len(worker.WorkerId)%40),
-			"uptime":              uptime,
-			"is_active":           isActive,
+			"worker_id":                 worker.WorkerId,
+			"status":                    worker.Status,
+			"current_task_id":           worker.CurrentTaskId,
+			"current_job_id":            worker.CurrentJobId,
+			"tasks_completed":           worker.TasksCompleted,
+			"last_activity_time":        worker.LastActivityTime,
+			"cpu_usage":                 load.GetCpuPercent(),
+			"memory_usage":              load.GetMemoryPercent(),
+			"load_average":              load.GetLoadAverage(),
+			"memory_used_bytes":         load.GetMemoryUsedBytes(),
+			"disk_usage":                load.GetDiskPercent(),
+			"net_sent_bytes_per_second": load.GetNetSentBytesPerSecond(),
+			"net_recv_bytes_per_second": load.GetNetRecvBytesPerSecond(),
+			"gpus":                      load.GetGpus(),
+			"backpressure":              load.GetBackpressure(),
+			"backpressure_reason":       load.GetBackpressureReason(),
+			"load_reported_at_ms":       worker.LoadReportedAtMs,
+			"uptime":                    worker.UptimeSeconds,
+			"is_active":                 isActive,
 		})
 	}
 
@@ -571,18 +1057,251 @@ func (gs *GatewayServer) handleGetWorkers(c *gin.Context) {
 		workers = append(workers, map[string]interface{}{
 			"id":                 worker.WorkerId,
 			"worker_id":          worker.WorkerId,
+			"hostname":           worker.Hostname,
+			"executor":           worker.Executor,
 			"status":             worker.Status,
 			"current_task_id":    worker.CurrentTaskId,
 			"current_job_id":     worker.CurrentJobId,
 			"tasks_completed":    worker.TasksCompleted,
 			"last_activity":      worker.LastActivityTime,
 			"is_active":          isActive,
+			"capabilities":       worker.Capabilities,
+			"quarantine_reason":  worker.QuarantineReason,
+			"max_concurrent_tasks": worker.MaxConcurrentTasks,
+			"in_flight_tasks":    worker.InFlightTasks,
+			"task_durations":     taskDurationsJSON(worker.TaskDurations),
+			"load":               worker.Load,
+			"uptime_seconds":     worker.UptimeSeconds,
+			"cached_datasets":    worker.CachedDatasets,
+			"dataset_cache_hits":   worker.DatasetCacheHits,
+			"dataset_cache_misses": worker.DatasetCacheMisses,
 		})
 	}
 
 	c.JSON(http.StatusOK, workers)
 }
 
+func (gs *GatewayServer) handleUnquarantineWorker(c *gin.Context) {
+	workerID := c.Param("id")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resp, err := gs.orchestratorClient.UnquarantineWorker(ctx, &orchestratorpb.UnquarantineWorkerRequest{
+		WorkerId: workerID,
+	})
+	if err != nil {
+		log.Printf("Error unquarantining worker: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to unquarantine worker",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if !resp.Success {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": resp.Message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":   true,
+		"message":   resp.Message,
+		"worker_id": workerID,
+	})
+}
+
+// handleGetUsage reports task-hour usage and quotas, for one user when the
+// path names one and for every user otherwise.
+func (gs *GatewayServer) handleGetUsage(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resp, err := gs.orchestratorClient.GetUserUsage(ctx, &orchestratorpb.GetUserUsageRequest{
+		UserId: c.Param("id"),
+	})
+	if err != nil {
+		log.Printf("Error getting user usage: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to get usage",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	users := make([]gin.H, 0, len(resp.Users))
+	for _, u := range resp.Users {
+		users = append(users, gin.H{
+			"user_id":          u.UserId,
+			"task_hours":       u.TaskHours,
+			"tasks_completed":  u.TasksCompleted,
+			"jobs_submitted":   u.JobsSubmitted,
+			"quota_task_hours": u.QuotaTaskHours,
+			"updated_at":       time.UnixMilli(u.UpdatedAtMs).Format(time.RFC3339),
+		})
+	}
+	if c.Param("id") != "" && len(users) == 1 {
+		c.JSON(http.StatusOK, users[0])
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"users": users})
+}
+
+// handleGetClusterSummary returns the counts and throughput the dashboard
+// shows, computed by the orchestrator.
+func (gs *GatewayServer) handleGetClusterSummary(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resp, err := gs.orchestratorClient.GetClusterSummary(ctx, &orchestratorpb.ClusterSummaryRequest{})
+	if err != nil {
+		log.Printf("Error getting cluster summary: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to get cluster summary",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"jobs_by_status":      resp.JobsByStatus,
+		"queued_tasks":        resp.QueuedTasks,
+		"running_tasks":       resp.RunningTasks,
+		"workers_by_status":   resp.WorkersByStatus,
+		"quarantined_workers": resp.QuarantinedWorkers,
+		"draining_workers":    resp.DrainingWorkers,
+		"throughput": gin.H{
+			"window_seconds":   resp.WindowSeconds,
+			"tasks_completed":  resp.TasksCompleted,
+			"jobs_completed":   resp.JobsCompleted,
+			"jobs_failed":      resp.JobsFailed,
+			"tasks_per_minute": resp.TasksPerMinute,
+		},
+		"avg_job_duration_ms": resp.AvgJobDurationMs,
+		"generated_at":        time.UnixMilli(resp.GeneratedAtMs).Format(time.RFC3339),
+	})
+}
+
+// handleSetUserQuota sets a user's task-hour quota; a null quota restores the
+// cluster default and 0 means unlimited.
+func (gs *GatewayServer) handleSetUserQuota(c *gin.Context) {
+	var req struct {
+		TaskHoursQuota *float64 `json:"task_hours_quota"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resp, err := gs.orchestratorClient.SetUserQuota(ctx, &orchestratorpb.SetUserQuotaRequest{
+		UserId:         c.Param("id"),
+		TaskHoursQuota: req.TaskHoursQuota,
+	})
+	if err != nil {
+		log.Printf("Error setting user quota: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to set quota",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if !resp.Success {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": resp.Message,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":          true,
+		"message":          resp.Message,
+		"user_id":          resp.Usage.UserId,
+		"task_hours":       resp.Usage.TaskHours,
+		"quota_task_hours": resp.Usage.QuotaTaskHours,
+	})
+}
+
+func (gs *GatewayServer) handlePauseJob(c *gin.Context) {
+	jobID := c.Param("id")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resp, err := gs.orchestratorClient.PauseJob(ctx, &orchestratorpb.PauseJobRequest{
+		JobId: jobID,
+	})
+	if err != nil {
+		log.Printf("Error pausing job: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to pause job",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if !resp.Success {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success":         false,
+			"message":         resp.Message,
+			"previous_status": resp.PreviousStatus,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":         true,
+		"message":         resp.Message,
+		"job_id":          jobID,
+		"previous_status": resp.PreviousStatus,
+	})
+}
+
+func (gs *GatewayServer) handleResumeJob(c *gin.Context) {
+	jobID := c.Param("id")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resp, err := gs.orchestratorClient.ResumeJob(ctx, &orchestratorpb.ResumeJobRequest{
+		JobId: jobID,
+	})
+	if err != nil {
+		log.Printf("Error resuming job: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Failed to resume job",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	if !resp.Success {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success":         false,
+			"message":         resp.Message,
+			"previous_status": resp.PreviousStatus,
+		})
+		return
+	}
+
+	log.Printf("Job %s resumed from epoch %d", jobID, resp.ResumeEpoch)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success":         true,
+		"message":         resp.Message,
+		"job_id":          jobID,
+		"previous_status": resp.PreviousStatus,
+		"resume_epoch":    resp.ResumeEpoch,
+	})
+}
+
 func (gs *GatewayServer) handleCancelJob(c *gin.Context) {
 	jobID := c.Param("id")
 	
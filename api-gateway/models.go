@@ -0,0 +1,309 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// The orchestrator auto-saves each completed job's model to the storage
+// service, which keeps a record of it and its weights. The gateway lists,
+// describes, serves and deletes them from there.
+
+// storageModel is a model record as the storage service returns it.
+type storageModel struct {
+	ID              string                 `json:"_id"`
+	JobID           string                 `json:"job_id"`
+	JobName         string                 `json:"job_name"`
+	Name            string                 `json:"name"`
+	MinioPath       string                 `json:"minio_path"`
+	Algorithm       string                 `json:"algorithm"`
+	ModelType       string                 `json:"model_type"`
+	Hyperparameters map[string]interface{} `json:"hyperparameters"`
+	Metrics         map[string]interface{} `json:"metrics"`
+	Version         string                 `json:"version"`
+	DatasetName     string                 `json:"dataset_name"`
+	DatasetPath     string                 `json:"dataset_path"`
+	DatasetID       string                 `json:"dataset_id"`
+	SizeBytes       int64                  `json:"size_bytes"`
+	Checksum        string                 `json:"checksum"`
+	Status          string                 `json:"status"`
+	CreatedAt       string                 `json:"created_at"`
+}
+
+type savedModel struct {
+	ModelID         string                 `json:"model_id"`
+	JobID           string                 `json:"job_id"`
+	JobName         string                 `json:"job_name,omitempty"`
+	Name            string                 `json:"name"`
+	Algorithm       string                 `json:"algorithm,omitempty"`
+	ModelType       string                 `json:"model_type,omitempty"`
+	Version         string                 `json:"version,omitempty"`
+	DatasetName     string                 `json:"dataset_name,omitempty"`
+	DatasetPath     string                 `json:"dataset_path,omitempty"`
+	DatasetID       string                 `json:"dataset_id,omitempty"`
+	Hyperparameters map[string]interface{} `json:"hyperparameters,omitempty"`
+	Metrics         map[string]interface{} `json:"metrics,omitempty"`
+	SizeBytes       int64                  `json:"size_bytes"`
+	Checksum        string                 `json:"checksum,omitempty"`
+	Status          string                 `json:"status,omitempty"`
+	Location        string                 `json:"location"`
+	CreatedAt       string                 `json:"created_at"`
+	URL             string                 `json:"url"` // Where the gateway serves its weights
+}
+
+func (m *storageModel) toSaved() savedModel {
+	return savedModel{
+		ModelID:         m.ID,
+		JobID:           m.JobID,
+		JobName:         m.JobName,
+		Name:            m.Name,
+		Algorithm:       m.Algorithm,
+		ModelType:       m.ModelType,
+		Version:         m.Version,
+		DatasetName:     m.DatasetName,
+		DatasetPath:     m.DatasetPath,
+		DatasetID:       m.DatasetID,
+		Hyperparameters: m.Hyperparameters,
+		Metrics:         m.Metrics,
+		SizeBytes:       m.SizeBytes,
+		Checksum:        m.Checksum,
+		Status:          m.Status,
+		Location:        m.MinioPath,
+		CreatedAt:       m.CreatedAt,
+		URL:             fmt.Sprintf("/api/v1/models/%s/weights", m.ID),
+	}
+}
+
+// storageRequest sends a request to the storage service endpoint made of
+// elem. A transport error is answered with 503 before it is returned.
+func (gs *GatewayServer) storageRequest(ctx context.Context, c *gin.Context, method string, query url.Values, elem ...string) (*http.Response, error) {
+	return gs.sendToStorage(ctx, c, method, query, "", nil, elem...)
+}
+
+// sendToStorage is storageRequest with a body of the content type.
+func (gs *GatewayServer) sendToStorage(ctx context.Context, c *gin.Context, method string, query url.Values, contentType string, body io.Reader, elem ...string) (*http.Response, error) {
+	endpoint, err := url.JoinPath(gs.storageURL, elem...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Invalid storage service URL"})
+		return nil, err
+	}
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reach storage service"})
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := storageHTTPClient.Do(req)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("Body exceeds %d bytes", tooLarge.Limit)})
+		return nil, err
+	}
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Storage service unavailable"})
+		return nil, err
+	}
+	return resp, nil
+}
+
+// storageError is the error message of a storage service response, or
+// fallback if it has none.
+func storageError(resp *http.Response, fallback string) string {
+	var body struct {
+		Error string `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || body.Error == "" {
+		return fallback
+	}
+	return body.Error
+}
+
+// handleListModels lists saved models newest first, only those of ?job_id=,
+// ?name= and ?dataset= if given.
+func (gs *GatewayServer) handleListModels(c *gin.Context) {
+	query := url.Values{}
+	for _, filter := range []string{"job_id", "name", "dataset"} {
+		if v := c.Query(filter); v != "" {
+			query.Set(filter, v)
+		}
+	}
+	if l := c.Query("limit"); l != "" {
+		limit, err := strconv.Atoi(l)
+		if err != nil || limit <= 0 || limit > 500 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 500"})
+			return
+		}
+		query.Set("limit", l)
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	resp, err := gs.storageRequest(ctx, c, http.MethodGet, query, "api/v1/models")
+	if err != nil {
+		log.Printf("Error listing models: %v", err)
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		log.Printf("Error listing models: storage service returned status %d", resp.StatusCode)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to list models"})
+		return
+	}
+	var listing struct {
+		Models []storageModel `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&listing); err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Malformed response from storage service"})
+		return
+	}
+
+	models := make([]savedModel, 0, len(listing.Models))
+	for i := range listing.Models {
+		models = append(models, listing.Models[i].toSaved())
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"models": models,
+		"total":  len(models),
+	})
+}
+
+// handleGetModel returns what was recorded about a saved model.
+func (gs *GatewayServer) handleGetModel(c *gin.Context) {
+	modelID := c.Param("id")
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	resp, err := gs.storageRequest(ctx, c, http.MethodGet, nil, "api/v1/models", modelID, "metadata")
+	if err != nil {
+		log.Printf("Error getting model %s: %v", modelID, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Model not found"})
+		return
+	case resp.StatusCode != http.StatusOK:
+		log.Printf("Error getting model %s: storage service returned status %d", modelID, resp.StatusCode)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to get model"})
+		return
+	}
+	var model storageModel
+	if err := json.NewDecoder(resp.Body).Decode(&model); err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Malformed response from storage service"})
+		return
+	}
+	c.JSON(http.StatusOK, model.toSaved())
+}
+
+// handleGetModelLineage reports what produced a saved model. The storage
+// service's lineage is passed through as it is.
+func (gs *GatewayServer) handleGetModelLineage(c *gin.Context) {
+	modelID := c.Param("id")
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	resp, err := gs.storageRequest(ctx, c, http.MethodGet, nil, "api/v1/models", modelID, "lineage")
+	if err != nil {
+		log.Printf("Error getting lineage of model %s: %v", modelID, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Model not found"})
+		return
+	case resp.StatusCode != http.StatusOK:
+		log.Printf("Error getting lineage of model %s: storage service returned status %d", modelID, resp.StatusCode)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to get model lineage"})
+		return
+	}
+	var lineage json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&lineage); err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Malformed response from storage service"})
+		return
+	}
+	c.JSON(http.StatusOK, lineage)
+}
+
+// handleDownloadModel serves a saved model's weights.
+func (gs *GatewayServer) handleDownloadModel(c *gin.Context) {
+	modelID := c.Param("id")
+
+	resp, err := gs.storageRequest(c.Request.Context(), c, http.MethodGet, nil, "api/v1/models", modelID)
+	if err != nil {
+		log.Printf("Error fetching weights of model %s: %v", modelID, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Model not found"})
+		return
+	case resp.StatusCode != http.StatusOK:
+		log.Printf("Error fetching weights of model %s: storage service returned status %d", modelID, resp.StatusCode)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to fetch model weights"})
+		return
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	disposition := resp.Header.Get("Content-Disposition")
+	if disposition == "" {
+		disposition = fmt.Sprintf("attachment; filename=%q", modelID+".weights")
+	}
+	c.Header("Content-Disposition", disposition)
+	c.DataFromReader(http.StatusOK, resp.ContentLength, contentType, resp.Body, nil)
+}
+
+// handleDeleteModel deletes a saved model and, unless another model shares
+// them, its weights.
+func (gs *GatewayServer) handleDeleteModel(c *gin.Context) {
+	modelID := c.Param("id")
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	resp, err := gs.storageRequest(ctx, c, http.MethodDelete, nil, "api/v1/models", modelID)
+	if err != nil {
+		log.Printf("Error deleting model %s: %v", modelID, err)
+		return
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Model not found"})
+		return
+	case resp.StatusCode != http.StatusOK:
+		log.Printf("Error deleting model %s: storage service returned status %d", modelID, resp.StatusCode)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to delete model"})
+		return
+	}
+	log.Printf("Deleted model %s", modelID)
+	c.JSON(http.StatusOK, gin.H{"model_id": modelID, "message": "Model deleted"})
+}
@@ -21,11 +21,31 @@ const _ = grpc.SupportPackageIsVersion9
 const (
 	OrchestratorService_CreateTrainingJob_FullMethodName    = "/orchestrator.OrchestratorService/CreateTrainingJob"
 	OrchestratorService_GetJobStatus_FullMethodName         = "/orchestrator.OrchestratorService/GetJobStatus"
+	OrchestratorService_GetJob_FullMethodName               = "/orchestrator.OrchestratorService/GetJob"
+	OrchestratorService_ListJobs_FullMethodName             = "/orchestrator.OrchestratorService/ListJobs"
+	OrchestratorService_GetJobTasks_FullMethodName          = "/orchestrator.OrchestratorService/GetJobTasks"
+	OrchestratorService_WatchJobStatus_FullMethodName       = "/orchestrator.OrchestratorService/WatchJobStatus"
 	OrchestratorService_AssignTask_FullMethodName           = "/orchestrator.OrchestratorService/AssignTask"
+	OrchestratorService_StreamTasks_FullMethodName          = "/orchestrator.OrchestratorService/StreamTasks"
 	OrchestratorService_ReportTaskCompletion_FullMethodName = "/orchestrator.OrchestratorService/ReportTaskCompletion"
+	OrchestratorService_ReleaseTask_FullMethodName          = "/orchestrator.OrchestratorService/ReleaseTask"
+	OrchestratorService_ReportTaskProgress_FullMethodName   = "/orchestrator.OrchestratorService/ReportTaskProgress"
+	OrchestratorService_ShipTaskLogs_FullMethodName         = "/orchestrator.OrchestratorService/ShipTaskLogs"
 	OrchestratorService_UpdateJobMetrics_FullMethodName     = "/orchestrator.OrchestratorService/UpdateJobMetrics"
 	OrchestratorService_CancelJob_FullMethodName            = "/orchestrator.OrchestratorService/CancelJob"
 	OrchestratorService_GetWorkerActivity_FullMethodName    = "/orchestrator.OrchestratorService/GetWorkerActivity"
+	OrchestratorService_RegisterWorker_FullMethodName       = "/orchestrator.OrchestratorService/RegisterWorker"
+	OrchestratorService_DeregisterWorker_FullMethodName     = "/orchestrator.OrchestratorService/DeregisterWorker"
+	OrchestratorService_Heartbeat_FullMethodName            = "/orchestrator.OrchestratorService/Heartbeat"
+	OrchestratorService_UnquarantineWorker_FullMethodName   = "/orchestrator.OrchestratorService/UnquarantineWorker"
+	OrchestratorService_ResumeJob_FullMethodName            = "/orchestrator.OrchestratorService/ResumeJob"
+	OrchestratorService_PauseJob_FullMethodName             = "/orchestrator.OrchestratorService/PauseJob"
+	OrchestratorService_GetJobMetricsHistory_FullMethodName = "/orchestrator.OrchestratorService/GetJobMetricsHistory"
+	OrchestratorService_GetJobEvents_FullMethodName         = "/orchestrator.OrchestratorService/GetJobEvents"
+	OrchestratorService_Drain_FullMethodName                = "/orchestrator.OrchestratorService/Drain"
+	OrchestratorService_GetUserUsage_FullMethodName         = "/orchestrator.OrchestratorService/GetUserUsage"
+	OrchestratorService_SetUserQuota_FullMethodName         = "/orchestrator.OrchestratorService/SetUserQuota"
+	OrchestratorService_GetClusterSummary_FullMethodName    = "/orchestrator.OrchestratorService/GetClusterSummary"
 )
 
 // OrchestratorServiceClient is the client API for OrchestratorService service.
@@ -34,11 +54,44 @@ const (
 type OrchestratorServiceClient interface {
 	CreateTrainingJob(ctx context.Context, in *TrainingJobRequest, opts ...grpc.CallOption) (*TrainingJobResponse, error)
 	GetJobStatus(ctx context.Context, in *GetJobStatusRequest, opts ...grpc.CallOption) (*GetJobStatusResponse, error)
+	// The full record of one job, and jobs filtered and paged newest first.
+	GetJob(ctx context.Context, in *GetJobRequest, opts ...grpc.CallOption) (*GetJobResponse, error)
+	ListJobs(ctx context.Context, in *ListJobsRequest, opts ...grpc.CallOption) (*ListJobsResponse, error)
+	GetJobTasks(ctx context.Context, in *GetJobTasksRequest, opts ...grpc.CallOption) (*GetJobTasksResponse, error)
+	WatchJobStatus(ctx context.Context, in *WatchJobStatusRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GetJobStatusResponse], error)
 	AssignTask(ctx context.Context, in *AssignTaskRequest, opts ...grpc.CallOption) (*AssignTaskResponse, error)
+	// Each request asks for one task; tasks are pushed as they become available.
+	StreamTasks(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[TaskStreamRequest, AssignTaskResponse], error)
 	ReportTaskCompletion(ctx context.Context, in *TaskCompletionRequest, opts ...grpc.CallOption) (*TaskCompletionResponse, error)
+	// Hands back a task the worker will not finish, e.g. because it is shutting
+	// down. The task is requeued without counting the attempt.
+	ReleaseTask(ctx context.Context, in *ReleaseTaskRequest, opts ...grpc.CallOption) (*ReleaseTaskResponse, error)
+	// Reports how far a running task has got, with its interim metrics.
+	ReportTaskProgress(ctx context.Context, in *TaskProgressRequest, opts ...grpc.CallOption) (*TaskProgressResponse, error)
+	// Ships a batch of task log lines to the central log store.
+	ShipTaskLogs(ctx context.Context, in *ShipTaskLogsRequest, opts ...grpc.CallOption) (*ShipTaskLogsResponse, error)
 	UpdateJobMetrics(ctx context.Context, in *JobMetricsRequest, opts ...grpc.CallOption) (*JobMetricsResponse, error)
 	CancelJob(ctx context.Context, in *CancelJobRequest, opts ...grpc.CallOption) (*CancelJobResponse, error)
 	GetWorkerActivity(ctx context.Context, in *WorkerActivityRequest, opts ...grpc.CallOption) (*WorkerActivityResponse, error)
+	RegisterWorker(ctx context.Context, in *RegisterWorkerRequest, opts ...grpc.CallOption) (*RegisterWorkerResponse, error)
+	// Removes a worker that is shutting down cleanly. Tasks it still holds are
+	// requeued without counting the attempt, instead of waiting for their
+	// leases to expire.
+	DeregisterWorker(ctx context.Context, in *DeregisterWorkerRequest, opts ...grpc.CallOption) (*DeregisterWorkerResponse, error)
+	Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error)
+	UnquarantineWorker(ctx context.Context, in *UnquarantineWorkerRequest, opts ...grpc.CallOption) (*UnquarantineWorkerResponse, error)
+	ResumeJob(ctx context.Context, in *ResumeJobRequest, opts ...grpc.CallOption) (*ResumeJobResponse, error)
+	PauseJob(ctx context.Context, in *PauseJobRequest, opts ...grpc.CallOption) (*PauseJobResponse, error)
+	GetJobMetricsHistory(ctx context.Context, in *JobMetricsHistoryRequest, opts ...grpc.CallOption) (*JobMetricsHistoryResponse, error)
+	GetJobEvents(ctx context.Context, in *JobEventsRequest, opts ...grpc.CallOption) (*JobEventsResponse, error)
+	// Stops accepting jobs and handing out tasks, waits for in-flight task
+	// reports, persists state and shuts the orchestrator down.
+	Drain(ctx context.Context, in *DrainRequest, opts ...grpc.CallOption) (*DrainResponse, error)
+	// Per-user task-hour accounting and quotas.
+	GetUserUsage(ctx context.Context, in *GetUserUsageRequest, opts ...grpc.CallOption) (*GetUserUsageResponse, error)
+	SetUserQuota(ctx context.Context, in *SetUserQuotaRequest, opts ...grpc.CallOption) (*SetUserQuotaResponse, error)
+	// Job, task and worker counts and recent throughput for dashboards.
+	GetClusterSummary(ctx context.Context, in *ClusterSummaryRequest, opts ...grpc.CallOption) (*ClusterSummaryResponse, error)
 }
 
 type orchestratorServiceClient struct {
@@ -69,6 +122,55 @@ func (c *orchestratorServiceClient) GetJobStatus(ctx context.Context, in *GetJob
 	return out, nil
 }
 
+func (c *orchestratorServiceClient) GetJob(ctx context.Context, in *GetJobRequest, opts ...grpc.CallOption) (*GetJobResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetJobResponse)
+	err := c.cc.Invoke(ctx, OrchestratorService_GetJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorServiceClient) ListJobs(ctx context.Context, in *ListJobsRequest, opts ...grpc.CallOption) (*ListJobsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListJobsResponse)
+	err := c.cc.Invoke(ctx, OrchestratorService_ListJobs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorServiceClient) GetJobTasks(ctx context.Context, in *GetJobTasksRequest, opts ...grpc.CallOption) (*GetJobTasksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetJobTasksResponse)
+	err := c.cc.Invoke(ctx, OrchestratorService_GetJobTasks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorServiceClient) WatchJobStatus(ctx context.Context, in *WatchJobStatusRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[GetJobStatusResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &OrchestratorService_ServiceDesc.Streams[0], OrchestratorService_WatchJobStatus_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchJobStatusRequest, GetJobStatusResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OrchestratorService_WatchJobStatusClient = grpc.ServerStreamingClient[GetJobStatusResponse]
+
 func (c *orchestratorServiceClient) AssignTask(ctx context.Context, in *AssignTaskRequest, opts ...grpc.CallOption) (*AssignTaskResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(AssignTaskResponse)
@@ -79,6 +181,19 @@ func (c *orchestratorServiceClient) AssignTask(ctx context.Context, in *AssignTa
 	return out, nil
 }
 
+func (c *orchestratorServiceClient) StreamTasks(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[TaskStreamRequest, AssignTaskResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &OrchestratorService_ServiceDesc.Streams[1], OrchestratorService_StreamTasks_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[TaskStreamRequest, AssignTaskResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OrchestratorService_StreamTasksClient = grpc.BidiStreamingClient[TaskStreamRequest, AssignTaskResponse]
+
 func (c *orchestratorServiceClient) ReportTaskCompletion(ctx context.Context, in *TaskCompletionRequest, opts ...grpc.CallOption) (*TaskCompletionResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(TaskCompletionResponse)
@@ -89,6 +204,36 @@ func (c *orchestratorServiceClient) ReportTaskCompletion(ctx context.Context, in
 	return out, nil
 }
 
+func (c *orchestratorServiceClient) ReleaseTask(ctx context.Context, in *ReleaseTaskRequest, opts ...grpc.CallOption) (*ReleaseTaskResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReleaseTaskResponse)
+	err := c.cc.Invoke(ctx, OrchestratorService_ReleaseTask_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorServiceClient) ReportTaskProgress(ctx context.Context, in *TaskProgressRequest, opts ...grpc.CallOption) (*TaskProgressResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TaskProgressResponse)
+	err := c.cc.Invoke(ctx, OrchestratorService_ReportTaskProgress_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorServiceClient) ShipTaskLogs(ctx context.Context, in *ShipTaskLogsRequest, opts ...grpc.CallOption) (*ShipTaskLogsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ShipTaskLogsResponse)
+	err := c.cc.Invoke(ctx, OrchestratorService_ShipTaskLogs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *orchestratorServiceClient) UpdateJobMetrics(ctx context.Context, in *JobMetricsRequest, opts ...grpc.CallOption) (*JobMetricsResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(JobMetricsResponse)
@@ -119,17 +264,170 @@ func (c *orchestratorServiceClient) GetWorkerActivity(ctx context.Context, in *W
 	return out, nil
 }
 
+func (c *orchestratorServiceClient) RegisterWorker(ctx context.Context, in *RegisterWorkerRequest, opts ...grpc.CallOption) (*RegisterWorkerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegisterWorkerResponse)
+	err := c.cc.Invoke(ctx, OrchestratorService_RegisterWorker_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorServiceClient) DeregisterWorker(ctx context.Context, in *DeregisterWorkerRequest, opts ...grpc.CallOption) (*DeregisterWorkerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeregisterWorkerResponse)
+	err := c.cc.Invoke(ctx, OrchestratorService_DeregisterWorker_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorServiceClient) Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HeartbeatResponse)
+	err := c.cc.Invoke(ctx, OrchestratorService_Heartbeat_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorServiceClient) UnquarantineWorker(ctx context.Context, in *UnquarantineWorkerRequest, opts ...grpc.CallOption) (*UnquarantineWorkerResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UnquarantineWorkerResponse)
+	err := c.cc.Invoke(ctx, OrchestratorService_UnquarantineWorker_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorServiceClient) ResumeJob(ctx context.Context, in *ResumeJobRequest, opts ...grpc.CallOption) (*ResumeJobResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResumeJobResponse)
+	err := c.cc.Invoke(ctx, OrchestratorService_ResumeJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorServiceClient) PauseJob(ctx context.Context, in *PauseJobRequest, opts ...grpc.CallOption) (*PauseJobResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PauseJobResponse)
+	err := c.cc.Invoke(ctx, OrchestratorService_PauseJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorServiceClient) GetJobMetricsHistory(ctx context.Context, in *JobMetricsHistoryRequest, opts ...grpc.CallOption) (*JobMetricsHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JobMetricsHistoryResponse)
+	err := c.cc.Invoke(ctx, OrchestratorService_GetJobMetricsHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorServiceClient) GetJobEvents(ctx context.Context, in *JobEventsRequest, opts ...grpc.CallOption) (*JobEventsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JobEventsResponse)
+	err := c.cc.Invoke(ctx, OrchestratorService_GetJobEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorServiceClient) Drain(ctx context.Context, in *DrainRequest, opts ...grpc.CallOption) (*DrainResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DrainResponse)
+	err := c.cc.Invoke(ctx, OrchestratorService_Drain_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorServiceClient) GetUserUsage(ctx context.Context, in *GetUserUsageRequest, opts ...grpc.CallOption) (*GetUserUsageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUserUsageResponse)
+	err := c.cc.Invoke(ctx, OrchestratorService_GetUserUsage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorServiceClient) SetUserQuota(ctx context.Context, in *SetUserQuotaRequest, opts ...grpc.CallOption) (*SetUserQuotaResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetUserQuotaResponse)
+	err := c.cc.Invoke(ctx, OrchestratorService_SetUserQuota_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorServiceClient) GetClusterSummary(ctx context.Context, in *ClusterSummaryRequest, opts ...grpc.CallOption) (*ClusterSummaryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ClusterSummaryResponse)
+	err := c.cc.Invoke(ctx, OrchestratorService_GetClusterSummary_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // OrchestratorServiceServer is the server API for OrchestratorService service.
 // All implementations must embed UnimplementedOrchestratorServiceServer
 // for forward compatibility.
 type OrchestratorServiceServer interface {
 	CreateTrainingJob(context.Context, *TrainingJobRequest) (*TrainingJobResponse, error)
 	GetJobStatus(context.Context, *GetJobStatusRequest) (*GetJobStatusResponse, error)
+	// The full record of one job, and jobs filtered and paged newest first.
+	GetJob(context.Context, *GetJobRequest) (*GetJobResponse, error)
+	ListJobs(context.Context, *ListJobsRequest) (*ListJobsResponse, error)
+	GetJobTasks(context.Context, *GetJobTasksRequest) (*GetJobTasksResponse, error)
+	WatchJobStatus(*WatchJobStatusRequest, grpc.ServerStreamingServer[GetJobStatusResponse]) error
 	AssignTask(context.Context, *AssignTaskRequest) (*AssignTaskResponse, error)
+	// Each request asks for one task; tasks are pushed as they become available.
+	StreamTasks(grpc.BidiStreamingServer[TaskStreamRequest, AssignTaskResponse]) error
 	ReportTaskCompletion(context.Context, *TaskCompletionRequest) (*TaskCompletionResponse, error)
+	// Hands back a task the worker will not finish, e.g. because it is shutting
+	// down. The task is requeued without counting the attempt.
+	ReleaseTask(context.Context, *ReleaseTaskRequest) (*ReleaseTaskResponse, error)
+	// Reports how far a running task has got, with its interim metrics.
+	ReportTaskProgress(context.Context, *TaskProgressRequest) (*TaskProgressResponse, error)
+	// Ships a batch of task log lines to the central log store.
+	ShipTaskLogs(context.Context, *ShipTaskLogsRequest) (*ShipTaskLogsResponse, error)
 	UpdateJobMetrics(context.Context, *JobMetricsRequest) (*JobMetricsResponse, error)
 	CancelJob(context.Context, *CancelJobRequest) (*CancelJobResponse, error)
 	GetWorkerActivity(context.Context, *WorkerActivityRequest) (*WorkerActivityResponse, error)
+	RegisterWorker(context.Context, *RegisterWorkerRequest) (*RegisterWorkerResponse, error)
+	// Removes a worker that is shutting down cleanly. Tasks it still holds are
+	// requeued without counting the attempt, instead of waiting for their
+	// leases to expire.
+	DeregisterWorker(context.Context, *DeregisterWorkerRequest) (*DeregisterWorkerResponse, error)
+	Heartbeat(context.Context, *HeartbeatRequest) (*HeartbeatResponse, error)
+	UnquarantineWorker(context.Context, *UnquarantineWorkerRequest) (*UnquarantineWorkerResponse, error)
+	ResumeJob(context.Context, *ResumeJobRequest) (*ResumeJobResponse, error)
+	PauseJob(context.Context, *PauseJobRequest) (*PauseJobResponse, error)
+	GetJobMetricsHistory(context.Context, *JobMetricsHistoryRequest) (*JobMetricsHistoryResponse, error)
+	GetJobEvents(context.Context, *JobEventsRequest) (*JobEventsResponse, error)
+	// Stops accepting jobs and handing out tasks, waits for in-flight task
+	// reports, persists state and shuts the orchestrator down.
+	Drain(context.Context, *DrainRequest) (*DrainResponse, error)
+	// Per-user task-hour accounting and quotas.
+	GetUserUsage(context.Context, *GetUserUsageRequest) (*GetUserUsageResponse, error)
+	SetUserQuota(context.Context, *SetUserQuotaRequest) (*SetUserQuotaResponse, error)
+	// Job, task and worker counts and recent throughput for dashboards.
+	GetClusterSummary(context.Context, *ClusterSummaryRequest) (*ClusterSummaryResponse, error)
 	mustEmbedUnimplementedOrchestratorServiceServer()
 }
 
@@ -146,12 +444,36 @@ func (UnimplementedOrchestratorServiceServer) CreateTrainingJob(context.Context,
 func (UnimplementedOrchestratorServiceServer) GetJobStatus(context.Context, *GetJobStatusRequest) (*GetJobStatusResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetJobStatus not implemented")
 }
+func (UnimplementedOrchestratorServiceServer) GetJob(context.Context, *GetJobRequest) (*GetJobResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetJob not implemented")
+}
+func (UnimplementedOrchestratorServiceServer) ListJobs(context.Context, *ListJobsRequest) (*ListJobsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListJobs not implemented")
+}
+func (UnimplementedOrchestratorServiceServer) GetJobTasks(context.Context, *GetJobTasksRequest) (*GetJobTasksResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetJobTasks not implemented")
+}
+func (UnimplementedOrchestratorServiceServer) WatchJobStatus(*WatchJobStatusRequest, grpc.ServerStreamingServer[GetJobStatusResponse]) error {
+	return status.Error(codes.Unimplemented, "method WatchJobStatus not implemented")
+}
 func (UnimplementedOrchestratorServiceServer) AssignTask(context.Context, *AssignTaskRequest) (*AssignTaskResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method AssignTask not implemented")
 }
+func (UnimplementedOrchestratorServiceServer) StreamTasks(grpc.BidiStreamingServer[TaskStreamRequest, AssignTaskResponse]) error {
+	return status.Error(codes.Unimplemented, "method StreamTasks not implemented")
+}
 func (UnimplementedOrchestratorServiceServer) ReportTaskCompletion(context.Context, *TaskCompletionRequest) (*TaskCompletionResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method ReportTaskCompletion not implemented")
 }
+func (UnimplementedOrchestratorServiceServer) ReleaseTask(context.Context, *ReleaseTaskRequest) (*ReleaseTaskResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ReleaseTask not implemented")
+}
+func (UnimplementedOrchestratorServiceServer) ReportTaskProgress(context.Context, *TaskProgressRequest) (*TaskProgressResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ReportTaskProgress not implemented")
+}
+func (UnimplementedOrchestratorServiceServer) ShipTaskLogs(context.Context, *ShipTaskLogsRequest) (*ShipTaskLogsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ShipTaskLogs not implemented")
+}
 func (UnimplementedOrchestratorServiceServer) UpdateJobMetrics(context.Context, *JobMetricsRequest) (*JobMetricsResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method UpdateJobMetrics not implemented")
 }
@@ -161,6 +483,42 @@ func (UnimplementedOrchestratorServiceServer) CancelJob(context.Context, *Cancel
 func (UnimplementedOrchestratorServiceServer) GetWorkerActivity(context.Context, *WorkerActivityRequest) (*WorkerActivityResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetWorkerActivity not implemented")
 }
+func (UnimplementedOrchestratorServiceServer) RegisterWorker(context.Context, *RegisterWorkerRequest) (*RegisterWorkerResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RegisterWorker not implemented")
+}
+func (UnimplementedOrchestratorServiceServer) DeregisterWorker(context.Context, *DeregisterWorkerRequest) (*DeregisterWorkerResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeregisterWorker not implemented")
+}
+func (UnimplementedOrchestratorServiceServer) Heartbeat(context.Context, *HeartbeatRequest) (*HeartbeatResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Heartbeat not implemented")
+}
+func (UnimplementedOrchestratorServiceServer) UnquarantineWorker(context.Context, *UnquarantineWorkerRequest) (*UnquarantineWorkerResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method UnquarantineWorker not implemented")
+}
+func (UnimplementedOrchestratorServiceServer) ResumeJob(context.Context, *ResumeJobRequest) (*ResumeJobResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ResumeJob not implemented")
+}
+func (UnimplementedOrchestratorServiceServer) PauseJob(context.Context, *PauseJobRequest) (*PauseJobResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method PauseJob not implemented")
+}
+func (UnimplementedOrchestratorServiceServer) GetJobMetricsHistory(context.Context, *JobMetricsHistoryRequest) (*JobMetricsHistoryResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetJobMetricsHistory not implemented")
+}
+func (UnimplementedOrchestratorServiceServer) GetJobEvents(context.Context, *JobEventsRequest) (*JobEventsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetJobEvents not implemented")
+}
+func (UnimplementedOrchestratorServiceServer) Drain(context.Context, *DrainRequest) (*DrainResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Drain not implemented")
+}
+func (UnimplementedOrchestratorServiceServer) GetUserUsage(context.Context, *GetUserUsageRequest) (*GetUserUsageResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetUserUsage not implemented")
+}
+func (UnimplementedOrchestratorServiceServer) SetUserQuota(context.Context, *SetUserQuotaRequest) (*SetUserQuotaResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetUserQuota not implemented")
+}
+func (UnimplementedOrchestratorServiceServer) GetClusterSummary(context.Context, *ClusterSummaryRequest) (*ClusterSummaryResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetClusterSummary not implemented")
+}
 func (UnimplementedOrchestratorServiceServer) mustEmbedUnimplementedOrchestratorServiceServer() {}
 func (UnimplementedOrchestratorServiceServer) testEmbeddedByValue()                             {}
 
@@ -218,6 +576,71 @@ func _OrchestratorService_GetJobStatus_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _OrchestratorService_GetJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).GetJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrchestratorService_GetJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).GetJob(ctx, req.(*GetJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrchestratorService_ListJobs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListJobsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).ListJobs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrchestratorService_ListJobs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).ListJobs(ctx, req.(*ListJobsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrchestratorService_GetJobTasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetJobTasksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).GetJobTasks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrchestratorService_GetJobTasks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).GetJobTasks(ctx, req.(*GetJobTasksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrchestratorService_WatchJobStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchJobStatusRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OrchestratorServiceServer).WatchJobStatus(m, &grpc.GenericServerStream[WatchJobStatusRequest, GetJobStatusResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OrchestratorService_WatchJobStatusServer = grpc.ServerStreamingServer[GetJobStatusResponse]
+
 func _OrchestratorService_AssignTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(AssignTaskRequest)
 	if err := dec(in); err != nil {
@@ -236,6 +659,13 @@ func _OrchestratorService_AssignTask_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _OrchestratorService_StreamTasks_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(OrchestratorServiceServer).StreamTasks(&grpc.GenericServerStream[TaskStreamRequest, AssignTaskResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OrchestratorService_StreamTasksServer = grpc.BidiStreamingServer[TaskStreamRequest, AssignTaskResponse]
+
 func _OrchestratorService_ReportTaskCompletion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(TaskCompletionRequest)
 	if err := dec(in); err != nil {
@@ -254,6 +684,60 @@ func _OrchestratorService_ReportTaskCompletion_Handler(srv interface{}, ctx cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _OrchestratorService_ReleaseTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReleaseTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).ReleaseTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrchestratorService_ReleaseTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).ReleaseTask(ctx, req.(*ReleaseTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrchestratorService_ReportTaskProgress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TaskProgressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).ReportTaskProgress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrchestratorService_ReportTaskProgress_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).ReportTaskProgress(ctx, req.(*TaskProgressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrchestratorService_ShipTaskLogs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShipTaskLogsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).ShipTaskLogs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrchestratorService_ShipTaskLogs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).ShipTaskLogs(ctx, req.(*ShipTaskLogsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _OrchestratorService_UpdateJobMetrics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(JobMetricsRequest)
 	if err := dec(in); err != nil {
@@ -308,13 +792,229 @@ func _OrchestratorService_GetWorkerActivity_Handler(srv interface{}, ctx context
 	return interceptor(ctx, in, info, handler)
 }
 
-// OrchestratorService_ServiceDesc is the grpc.ServiceDesc for OrchestratorService service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var OrchestratorService_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "orchestrator.OrchestratorService",
-	HandlerType: (*OrchestratorServiceServer)(nil),
-	Methods: []grpc.MethodDesc{
+func _OrchestratorService_RegisterWorker_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterWorkerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).RegisterWorker(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrchestratorService_RegisterWorker_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).RegisterWorker(ctx, req.(*RegisterWorkerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrchestratorService_DeregisterWorker_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeregisterWorkerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).DeregisterWorker(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrchestratorService_DeregisterWorker_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).DeregisterWorker(ctx, req.(*DeregisterWorkerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrchestratorService_Heartbeat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HeartbeatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).Heartbeat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrchestratorService_Heartbeat_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).Heartbeat(ctx, req.(*HeartbeatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrchestratorService_UnquarantineWorker_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UnquarantineWorkerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).UnquarantineWorker(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrchestratorService_UnquarantineWorker_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).UnquarantineWorker(ctx, req.(*UnquarantineWorkerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrchestratorService_ResumeJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).ResumeJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrchestratorService_ResumeJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).ResumeJob(ctx, req.(*ResumeJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrchestratorService_PauseJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).PauseJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrchestratorService_PauseJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).PauseJob(ctx, req.(*PauseJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrchestratorService_GetJobMetricsHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobMetricsHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).GetJobMetricsHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrchestratorService_GetJobMetricsHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).GetJobMetricsHistory(ctx, req.(*JobMetricsHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrchestratorService_GetJobEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).GetJobEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrchestratorService_GetJobEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).GetJobEvents(ctx, req.(*JobEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrchestratorService_Drain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DrainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).Drain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrchestratorService_Drain_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).Drain(ctx, req.(*DrainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrchestratorService_GetUserUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUserUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).GetUserUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrchestratorService_GetUserUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).GetUserUsage(ctx, req.(*GetUserUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrchestratorService_SetUserQuota_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetUserQuotaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).SetUserQuota(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrchestratorService_SetUserQuota_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).SetUserQuota(ctx, req.(*SetUserQuotaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrchestratorService_GetClusterSummary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ClusterSummaryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorServiceServer).GetClusterSummary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrchestratorService_GetClusterSummary_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorServiceServer).GetClusterSummary(ctx, req.(*ClusterSummaryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// OrchestratorService_ServiceDesc is the grpc.ServiceDesc for OrchestratorService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var OrchestratorService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "orchestrator.OrchestratorService",
+	HandlerType: (*OrchestratorServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
 		{
 			MethodName: "CreateTrainingJob",
 			Handler:    _OrchestratorService_CreateTrainingJob_Handler,
@@ -323,6 +1023,18 @@ var OrchestratorService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetJobStatus",
 			Handler:    _OrchestratorService_GetJobStatus_Handler,
 		},
+		{
+			MethodName: "GetJob",
+			Handler:    _OrchestratorService_GetJob_Handler,
+		},
+		{
+			MethodName: "ListJobs",
+			Handler:    _OrchestratorService_ListJobs_Handler,
+		},
+		{
+			MethodName: "GetJobTasks",
+			Handler:    _OrchestratorService_GetJobTasks_Handler,
+		},
 		{
 			MethodName: "AssignTask",
 			Handler:    _OrchestratorService_AssignTask_Handler,
@@ -331,6 +1043,18 @@ var OrchestratorService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ReportTaskCompletion",
 			Handler:    _OrchestratorService_ReportTaskCompletion_Handler,
 		},
+		{
+			MethodName: "ReleaseTask",
+			Handler:    _OrchestratorService_ReleaseTask_Handler,
+		},
+		{
+			MethodName: "ReportTaskProgress",
+			Handler:    _OrchestratorService_ReportTaskProgress_Handler,
+		},
+		{
+			MethodName: "ShipTaskLogs",
+			Handler:    _OrchestratorService_ShipTaskLogs_Handler,
+		},
 		{
 			MethodName: "UpdateJobMetrics",
 			Handler:    _OrchestratorService_UpdateJobMetrics_Handler,
@@ -343,6 +1067,345 @@ var OrchestratorService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetWorkerActivity",
 			Handler:    _OrchestratorService_GetWorkerActivity_Handler,
 		},
+		{
+			MethodName: "RegisterWorker",
+			Handler:    _OrchestratorService_RegisterWorker_Handler,
+		},
+		{
+			MethodName: "DeregisterWorker",
+			Handler:    _OrchestratorService_DeregisterWorker_Handler,
+		},
+		{
+			MethodName: "Heartbeat",
+			Handler:    _OrchestratorService_Heartbeat_Handler,
+		},
+		{
+			MethodName: "UnquarantineWorker",
+			Handler:    _OrchestratorService_UnquarantineWorker_Handler,
+		},
+		{
+			MethodName: "ResumeJob",
+			Handler:    _OrchestratorService_ResumeJob_Handler,
+		},
+		{
+			MethodName: "PauseJob",
+			Handler:    _OrchestratorService_PauseJob_Handler,
+		},
+		{
+			MethodName: "GetJobMetricsHistory",
+			Handler:    _OrchestratorService_GetJobMetricsHistory_Handler,
+		},
+		{
+			MethodName: "GetJobEvents",
+			Handler:    _OrchestratorService_GetJobEvents_Handler,
+		},
+		{
+			MethodName: "Drain",
+			Handler:    _OrchestratorService_Drain_Handler,
+		},
+		{
+			MethodName: "GetUserUsage",
+			Handler:    _OrchestratorService_GetUserUsage_Handler,
+		},
+		{
+			MethodName: "SetUserQuota",
+			Handler:    _OrchestratorService_SetUserQuota_Handler,
+		},
+		{
+			MethodName: "GetClusterSummary",
+			Handler:    _OrchestratorService_GetClusterSummary_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchJobStatus",
+			Handler:       _OrchestratorService_WatchJobStatus_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamTasks",
+			Handler:       _OrchestratorService_StreamTasks_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "orchestrator.proto",
+}
+
+const (
+	OrchestratorAdminService_RequeueTask_FullMethodName    = "/orchestrator.OrchestratorAdminService/RequeueTask"
+	OrchestratorAdminService_ForceFailJob_FullMethodName   = "/orchestrator.OrchestratorAdminService/ForceFailJob"
+	OrchestratorAdminService_DrainWorker_FullMethodName    = "/orchestrator.OrchestratorAdminService/DrainWorker"
+	OrchestratorAdminService_ExportSnapshot_FullMethodName = "/orchestrator.OrchestratorAdminService/ExportSnapshot"
+	OrchestratorAdminService_ImportSnapshot_FullMethodName = "/orchestrator.OrchestratorAdminService/ImportSnapshot"
+)
+
+// OrchestratorAdminServiceClient is the client API for OrchestratorAdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Operator interventions on single tasks, jobs and workers. Every action is
+// logged and recorded as an ADMIN_ACTION event naming the operator.
+type OrchestratorAdminServiceClient interface {
+	// Puts a task that is assigned, or failed in an epoch that has not
+	// settled, back on the queue.
+	RequeueTask(ctx context.Context, in *RequeueTaskRequest, opts ...grpc.CallOption) (*AdminResponse, error)
+	// Fails a job that has not finished, whatever its state.
+	ForceFailJob(ctx context.Context, in *ForceFailJobRequest, opts ...grpc.CallOption) (*AdminResponse, error)
+	// Stops handing tasks to a worker, optionally requeueing its in-flight
+	// tasks and forgetting the worker.
+	DrainWorker(ctx context.Context, in *DrainWorkerRequest, opts ...grpc.CallOption) (*AdminResponse, error)
+	// Writes the control-plane state (jobs with their tasks, workers and user
+	// usage) to object storage as a versioned snapshot.
+	ExportSnapshot(ctx context.Context, in *ExportSnapshotRequest, opts ...grpc.CallOption) (*SnapshotResponse, error)
+	// Loads the jobs and user usage of a snapshot written by ExportSnapshot
+	// into the job store and resumes its unfinished jobs.
+	ImportSnapshot(ctx context.Context, in *ImportSnapshotRequest, opts ...grpc.CallOption) (*SnapshotResponse, error)
+}
+
+type orchestratorAdminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewOrchestratorAdminServiceClient(cc grpc.ClientConnInterface) OrchestratorAdminServiceClient {
+	return &orchestratorAdminServiceClient{cc}
+}
+
+func (c *orchestratorAdminServiceClient) RequeueTask(ctx context.Context, in *RequeueTaskRequest, opts ...grpc.CallOption) (*AdminResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AdminResponse)
+	err := c.cc.Invoke(ctx, OrchestratorAdminService_RequeueTask_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorAdminServiceClient) ForceFailJob(ctx context.Context, in *ForceFailJobRequest, opts ...grpc.CallOption) (*AdminResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AdminResponse)
+	err := c.cc.Invoke(ctx, OrchestratorAdminService_ForceFailJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorAdminServiceClient) DrainWorker(ctx context.Context, in *DrainWorkerRequest, opts ...grpc.CallOption) (*AdminResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AdminResponse)
+	err := c.cc.Invoke(ctx, OrchestratorAdminService_DrainWorker_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorAdminServiceClient) ExportSnapshot(ctx context.Context, in *ExportSnapshotRequest, opts ...grpc.CallOption) (*SnapshotResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SnapshotResponse)
+	err := c.cc.Invoke(ctx, OrchestratorAdminService_ExportSnapshot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orchestratorAdminServiceClient) ImportSnapshot(ctx context.Context, in *ImportSnapshotRequest, opts ...grpc.CallOption) (*SnapshotResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SnapshotResponse)
+	err := c.cc.Invoke(ctx, OrchestratorAdminService_ImportSnapshot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// OrchestratorAdminServiceServer is the server API for OrchestratorAdminService service.
+// All implementations must embed UnimplementedOrchestratorAdminServiceServer
+// for forward compatibility.
+//
+// Operator interventions on single tasks, jobs and workers. Every action is
+// logged and recorded as an ADMIN_ACTION event naming the operator.
+type OrchestratorAdminServiceServer interface {
+	// Puts a task that is assigned, or failed in an epoch that has not
+	// settled, back on the queue.
+	RequeueTask(context.Context, *RequeueTaskRequest) (*AdminResponse, error)
+	// Fails a job that has not finished, whatever its state.
+	ForceFailJob(context.Context, *ForceFailJobRequest) (*AdminResponse, error)
+	// Stops handing tasks to a worker, optionally requeueing its in-flight
+	// tasks and forgetting the worker.
+	DrainWorker(context.Context, *DrainWorkerRequest) (*AdminResponse, error)
+	// Writes the control-plane state (jobs with their tasks, workers and user
+	// usage) to object storage as a versioned snapshot.
+	ExportSnapshot(context.Context, *ExportSnapshotRequest) (*SnapshotResponse, error)
+	// Loads the jobs and user usage of a snapshot written by ExportSnapshot
+	// into the job store and resumes its unfinished jobs.
+	ImportSnapshot(context.Context, *ImportSnapshotRequest) (*SnapshotResponse, error)
+	mustEmbedUnimplementedOrchestratorAdminServiceServer()
+}
+
+// UnimplementedOrchestratorAdminServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedOrchestratorAdminServiceServer struct{}
+
+func (UnimplementedOrchestratorAdminServiceServer) RequeueTask(context.Context, *RequeueTaskRequest) (*AdminResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RequeueTask not implemented")
+}
+func (UnimplementedOrchestratorAdminServiceServer) ForceFailJob(context.Context, *ForceFailJobRequest) (*AdminResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ForceFailJob not implemented")
+}
+func (UnimplementedOrchestratorAdminServiceServer) DrainWorker(context.Context, *DrainWorkerRequest) (*AdminResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DrainWorker not implemented")
+}
+func (UnimplementedOrchestratorAdminServiceServer) ExportSnapshot(context.Context, *ExportSnapshotRequest) (*SnapshotResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ExportSnapshot not implemented")
+}
+func (UnimplementedOrchestratorAdminServiceServer) ImportSnapshot(context.Context, *ImportSnapshotRequest) (*SnapshotResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ImportSnapshot not implemented")
+}
+func (UnimplementedOrchestratorAdminServiceServer) mustEmbedUnimplementedOrchestratorAdminServiceServer() {
+}
+func (UnimplementedOrchestratorAdminServiceServer) testEmbeddedByValue() {}
+
+// UnsafeOrchestratorAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to OrchestratorAdminServiceServer will
+// result in compilation errors.
+type UnsafeOrchestratorAdminServiceServer interface {
+	mustEmbedUnimplementedOrchestratorAdminServiceServer()
+}
+
+func RegisterOrchestratorAdminServiceServer(s grpc.ServiceRegistrar, srv OrchestratorAdminServiceServer) {
+	// If the following call panics, it indicates UnimplementedOrchestratorAdminServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&OrchestratorAdminService_ServiceDesc, srv)
+}
+
+func _OrchestratorAdminService_RequeueTask_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequeueTaskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorAdminServiceServer).RequeueTask(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrchestratorAdminService_RequeueTask_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorAdminServiceServer).RequeueTask(ctx, req.(*RequeueTaskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrchestratorAdminService_ForceFailJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ForceFailJobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorAdminServiceServer).ForceFailJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrchestratorAdminService_ForceFailJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorAdminServiceServer).ForceFailJob(ctx, req.(*ForceFailJobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrchestratorAdminService_DrainWorker_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DrainWorkerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorAdminServiceServer).DrainWorker(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrchestratorAdminService_DrainWorker_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorAdminServiceServer).DrainWorker(ctx, req.(*DrainWorkerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrchestratorAdminService_ExportSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorAdminServiceServer).ExportSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrchestratorAdminService_ExportSnapshot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorAdminServiceServer).ExportSnapshot(ctx, req.(*ExportSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrchestratorAdminService_ImportSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrchestratorAdminServiceServer).ImportSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrchestratorAdminService_ImportSnapshot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrchestratorAdminServiceServer).ImportSnapshot(ctx, req.(*ImportSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// OrchestratorAdminService_ServiceDesc is the grpc.ServiceDesc for OrchestratorAdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var OrchestratorAdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "orchestrator.OrchestratorAdminService",
+	HandlerType: (*OrchestratorAdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RequeueTask",
+			Handler:    _OrchestratorAdminService_RequeueTask_Handler,
+		},
+		{
+			MethodName: "ForceFailJob",
+			Handler:    _OrchestratorAdminService_ForceFailJob_Handler,
+		},
+		{
+			MethodName: "DrainWorker",
+			Handler:    _OrchestratorAdminService_DrainWorker_Handler,
+		},
+		{
+			MethodName: "ExportSnapshot",
+			Handler:    _OrchestratorAdminService_ExportSnapshot_Handler,
+		},
+		{
+			MethodName: "ImportSnapshot",
+			Handler:    _OrchestratorAdminService_ImportSnapshot_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "orchestrator.proto",
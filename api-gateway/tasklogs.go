@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+)
+
+// Task logs shipped by workers are kept by the orchestrator in a Redis
+// stream per job, logs:<job_id>, which the gateway reads directly.
+const taskLogStreamPrefix = "logs:"
+
+const (
+	defaultTaskLogLimit = 500
+	maxTaskLogLimit     = 5000
+	// defaultTaskLogTail is how many earlier lines a log stream starts with.
+	defaultTaskLogTail = 100
+)
+
+// logLevels orders log levels by severity, for the level filter.
+var logLevels = map[string]int{"DEBUG": 0, "INFO": 1, "WARN": 2, "ERROR": 3}
+
+type taskLogEntry struct {
+	ID        string            `json:"id"`
+	TaskID    string            `json:"task_id"`
+	WorkerID  string            `json:"worker_id"`
+	Level     string            `json:"level"`
+	Message   string            `json:"message"`
+	Timestamp int64             `json:"timestamp"` // Unix milliseconds, as the worker logged it
+	Fields    map[string]string `json:"fields,omitempty"`
+}
+
+func taskLogEntryFromMessage(m redis.XMessage) taskLogEntry {
+	str := func(key string) string {
+		s, _ := m.Values[key].(string)
+		return s
+	}
+	entry := taskLogEntry{
+		ID:       m.ID,
+		TaskID:   str("task_id"),
+		WorkerID: str("worker_id"),
+		Level:    str("level"),
+		Message:  str("message"),
+	}
+	entry.Timestamp, _ = strconv.ParseInt(str("timestamp"), 10, 64)
+	if fields := str("fields"); fields != "" && fields != "null" {
+		json.Unmarshal([]byte(fields), &entry.Fields)
+	}
+	return entry
+}
+
+// taskLogFilter selects log lines by task, worker and least severity.
+type taskLogFilter struct {
+	taskID   string
+	workerID string
+	minLevel int
+}
+
+func taskLogFilterFromQuery(c *gin.Context) (taskLogFilter, bool) {
+	f := taskLogFilter{taskID: c.Query("task_id"), workerID: c.Query("worker_id")}
+	if level := c.Query("level"); level != "" {
+		rank, ok := logLevels[strings.ToUpper(level)]
+		if !ok {
+			return f, false
+		}
+		f.minLevel = rank
+	}
+	return f, true
+}
+
+func (f taskLogFilter) matches(e taskLogEntry) bool {
+	return (f.taskID == "" || e.TaskID == f.taskID) &&
+		(f.workerID == "" || e.WorkerID == f.workerID) &&
+		logLevels[strings.ToUpper(e.Level)] >= f.minLevel
+}
+
+// handleGetJobLogEntries returns a job's shipped task logs, oldest first.
+// Pass next_after back as after for the lines that follow.
+func (gs *GatewayServer) handleGetJobLogEntries(c *gin.Context) {
+	jobID := c.Param("id")
+	filter, ok := taskLogFilterFromQuery(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "level must be one of DEBUG, INFO, WARN or ERROR"})
+		return
+	}
+	limit := defaultTaskLogLimit
+	if s := c.Query("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+		limit = min(n, maxTaskLogLimit)
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	entries := make([]taskLogEntry, 0)
+	after := c.Query("after")
+	for len(entries) < limit {
+		start := "-"
+		if after != "" {
+			start = "(" + after
+		}
+		msgs, err := gs.redisClient.XRangeN(ctx, taskLogStreamPrefix+jobID, start, "+", int64(limit)).Result()
+		if err != nil {
+			log.Printf("Error reading logs of job %s: %v", jobID, err)
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Log store unavailable"})
+			return
+		}
+		for _, m := range msgs {
+			after = m.ID
+			if entry := taskLogEntryFromMessage(m); filter.matches(entry) {
+				entries = append(entries, entry)
+				if len(entries) == limit {
+					break
+				}
+			}
+		}
+		if len(msgs) < limit {
+			break
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"job_id":     jobID,
+		"entries":    entries,
+		"next_after": after,
+	})
+}
+
+// followTaskLogs streams a job's shipped task logs until ctx is done,
+// starting with the last tail lines already stored.
+func (gs *GatewayServer) followTaskLogs(ctx context.Context, jobID string, tail int64) <-chan taskLogEntry {
+	stream := taskLogStreamPrefix + jobID
+	lines := make(chan taskLogEntry)
+	go func() {
+		defer close(lines)
+
+		send := func(m redis.XMessage) bool {
+			select {
+			case lines <- taskLogEntryFromMessage(m):
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		// "$" before the stream exists would miss lines added meanwhile
+		last := "0"
+		if tail > 0 {
+			recent, err := gs.redisClient.XRevRangeN(ctx, stream, "+", "-", tail).Result()
+			if err == nil && len(recent) > 0 {
+				last = recent[0].ID
+				for i := len(recent) - 1; i >= 0; i-- {
+					if !send(recent[i]) {
+						return
+					}
+				}
+			}
+		} else if info, err := gs.redisClient.XInfoStream(ctx, stream).Result(); err == nil {
+			last = info.LastGeneratedID
+		}
+
+		for ctx.Err() == nil {
+			res, err := gs.redisClient.XRead(ctx, &redis.XReadArgs{
+				Streams: []string{stream, last},
+				Block:   5 * time.Second,
+			}).Result()
+			if err == redis.Nil {
+				continue
+			}
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Printf("Error following logs of job %s: %v", jobID, err)
+					time.Sleep(time.Second)
+				}
+				continue
+			}
+			for _, s := range res {
+				for _, m := range s.Messages {
+					last = m.ID
+					if !send(m) {
+						return
+					}
+				}
+			}
+		}
+	}()
+	return lines
+}
@@ -25,9 +25,11 @@ const StatusChip = ({ status }) => {
       case 'RUNNING':
         return 'primary';
       case 'COMPLETED':
+      case 'COMPLETED_EARLY':
         return 'success';
       case 'FAILED':
         return 'error';
+      case 'STALLED':
       case 'CANCELLED':
         return 'warning';
       default:
@@ -55,12 +55,14 @@ const JobProgressMonitor = ({ jobId, jobData }) => {
     switch (status?.toUpperCase()) {
       case 'QUEUED':
       case 'PENDING':
+      case 'STALLED':
         return 'warning';
       case 'RUNNING':
       case 'PROGRESS':
         return 'info';
       case 'SUCCESS':
       case 'COMPLETED':
+      case 'COMPLETED_EARLY':
         return 'success';
       case 'FAILURE':
       case 'FAILED':
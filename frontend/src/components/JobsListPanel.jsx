@@ -77,9 +77,11 @@ const JobsListPanel = ({ jobs, onSelectJob, selectedJobId }) => {
       case 'RUNNING':
         return 'primary';
       case 'COMPLETED':
+      case 'COMPLETED_EARLY':
         return 'success';
       case 'FAILED':
         return 'error';
+      case 'STALLED':
       case 'CANCELLED':
         return 'warning';
       default:
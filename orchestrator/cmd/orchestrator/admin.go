@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	orchestratorpb "github.com/tensorfleet/orchestrator/proto/orchestrator"
+)
+
+const WorkerStatusDraining = "DRAINING"
+
+// AdminServer serves OrchestratorAdminService on the orchestrator's state.
+type AdminServer struct {
+	orchestratorpb.UnimplementedOrchestratorAdminServiceServer
+	s *OrchestratorServer
+}
+
+func NewAdminServer(s *OrchestratorServer) *AdminServer {
+	return &AdminServer{s: s}
+}
+
+// audit logs an operator action and records it as an ADMIN_ACTION event of
+// the job, or publishes it when it concerns no single job. Caller must hold
+// s.mu.
+func (s *OrchestratorServer) audit(operator, jobID string, event JobEvent) {
+	if operator == "" {
+		operator = "unknown operator"
+	}
+	event.Type = EventAdminAction
+	event.Message = operator + ": " + event.Message
+	log.Printf("Audit: %s", event.Message)
+
+	if jobID != "" {
+		s.recordEvent(jobID, event)
+		return
+	}
+	s.events.publish(ClusterEvent{
+		Type:     event.Type,
+		TaskID:   event.TaskID,
+		WorkerID: event.WorkerID,
+		Message:  event.Message,
+	})
+}
+
+// reclaimTask takes an assigned task back from its worker, which is told
+// why, and makes it pending again without counting the attempt against its
+// retries. The task is not queued. Caller must hold s.mu.
+func (s *OrchestratorServer) reclaimTask(job *Job, task *Task, reason string) {
+	if worker, ok := s.workers[task.WorkerID]; ok {
+		go s.cancelOnWorker(worker.WorkerID, task.TaskID, reason)
+	}
+	s.unassignTask(job, task)
+}
+
+// unassignTask is reclaimTask for a task its worker has already stopped.
+// Caller must hold s.mu.
+func (s *OrchestratorServer) unassignTask(job *Job, task *Task) {
+	if worker, ok := s.workers[task.WorkerID]; ok && worker.CurrentTaskID == task.TaskID {
+		worker.CurrentTaskID = ""
+		worker.Status = WorkerStatusIdle
+	}
+	job.release(task.WorkerID)
+	s.chargeTaskAttempt(job, task, false)
+	task.Attempts--
+	task.Status = TaskStatusPending
+	task.WorkerID = ""
+	task.AssignedAt = nil
+	task.LeaseExpiresAt = time.Time{}
+}
+
+func (a *AdminServer) RequeueTask(ctx context.Context, req *orchestratorpb.RequeueTaskRequest) (*orchestratorpb.AdminResponse, error) {
+	s := a.s
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[req.JobId]
+	if !ok {
+		return &orchestratorpb.AdminResponse{Success: false, Message: fmt.Sprintf("Job not found: %s", req.JobId)}, nil
+	}
+	task := job.findTask(req.TaskId)
+	if task == nil {
+		return &orchestratorpb.AdminResponse{Success: false, Message: fmt.Sprintf("Task not found: %s", req.TaskId)}, nil
+	}
+	if job.Status.IsTerminal() {
+		return &orchestratorpb.AdminResponse{Success: false, Message: fmt.Sprintf("Job %s is %s", job.JobID, job.Status)}, nil
+	}
+
+	previousWorker := task.WorkerID
+	switch {
+	case task.Status == TaskStatusAssigned:
+		s.reclaimTask(job, task, "requeued by operator")
+	case task.Status == TaskStatusFailed && !job.SettledEpochs[task.Epoch]:
+		job.FailedTasks--
+		task.Status = TaskStatusPending
+	default:
+		return &orchestratorpb.AdminResponse{
+			Success: false,
+			Message: fmt.Sprintf("Cannot requeue task with status %s", task.Status),
+		}, nil
+	}
+	if req.ResetAttempts {
+		task.Attempts = 0
+	}
+	task.LastError = "Requeued by operator: " + req.Reason
+	s.enqueueTask(job, task)
+
+	s.audit(req.Operator, job.JobID, JobEvent{
+		Message:  fmt.Sprintf("requeued epoch %d task: %s", task.Epoch, req.Reason),
+		TaskID:   task.TaskID,
+		WorkerID: previousWorker,
+	})
+	if err := s.saveJob(ctx, job); err != nil {
+		log.Printf("Warning: Failed to save job: %v", err)
+	}
+
+	return &orchestratorpb.AdminResponse{
+		Success:       true,
+		Message:       fmt.Sprintf("Task %s requeued", task.TaskID),
+		RequeuedTasks: 1,
+	}, nil
+}
+
+func (a *AdminServer) ForceFailJob(ctx context.Context, req *orchestratorpb.ForceFailJobRequest) (*orchestratorpb.AdminResponse, error) {
+	s := a.s
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[req.JobId]
+	if !ok {
+		return &orchestratorpb.AdminResponse{Success: false, Message: fmt.Sprintf("Job not found: %s", req.JobId)}, nil
+	}
+
+	reason := "Failed by operator"
+	if req.Reason != "" {
+		reason += ": " + req.Reason
+	}
+	if err := job.transition(JobStatusFailed, reason); err != nil {
+		return &orchestratorpb.AdminResponse{
+			Success: false,
+			Message: fmt.Sprintf("Cannot fail job with status: %s", job.Status),
+		}, nil
+	}
+	job.dropReservations()
+	cancelled := s.cancelOutstandingTasks(job)
+
+	log.Printf("Job %s failed: %s", job.JobID, reason)
+	s.recordEvent(job.JobID, JobEvent{Type: EventJobFailed, Message: reason})
+	s.audit(req.Operator, job.JobID, JobEvent{
+		Message: fmt.Sprintf("force-failed job, %d outstanding tasks cancelled: %s", cancelled, req.Reason),
+	})
+	s.releaseDependents(ctx, job)
+	s.admitQueuedJobs()
+
+	if err := s.saveJob(ctx, job); err != nil {
+		log.Printf("Warning: Failed to save job: %v", err)
+	}
+
+	return &orchestratorpb.AdminResponse{
+		Success: true,
+		Message: fmt.Sprintf("Job %s failed", job.JobID),
+	}, nil
+}
+
+func (a *AdminServer) DrainWorker(ctx context.Context, req *orchestratorpb.DrainWorkerRequest) (*orchestratorpb.AdminResponse, error) {
+	s := a.s
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	worker, ok := s.workers[req.WorkerId]
+	if !ok {
+		return &orchestratorpb.AdminResponse{Success: false, Message: fmt.Sprintf("Worker not found: %s", req.WorkerId)}, nil
+	}
+	worker.Draining = true
+	worker.DrainReason = req.Reason
+
+	requeued := 0
+	if req.RequeueInFlight || req.Deregister {
+		requeued = s.requeueWorkerTasks(ctx, worker.WorkerID, func(job *Job, task *Task) {
+			s.reclaimTask(job, task, "worker drained")
+			s.enqueueTask(job, task)
+			s.audit(req.Operator, job.JobID, JobEvent{
+				Message:  fmt.Sprintf("requeued epoch %d task while draining worker %s", task.Epoch, worker.WorkerID),
+				TaskID:   task.TaskID,
+				WorkerID: worker.WorkerID,
+			})
+		})
+	}
+
+	action := "drained"
+	if req.Deregister {
+		action = "deregistered"
+		delete(s.workers, worker.WorkerID)
+		s.closeWorkerConn(worker.WorkerID)
+		s.dropWorkerReservations(worker.WorkerID)
+	}
+	// Jobs may have lost their only capable worker
+	s.refreshResourceAvailability()
+
+	s.audit(req.Operator, "", JobEvent{
+		Message:  fmt.Sprintf("%s worker %s, %d in-flight tasks requeued: %s", action, worker.WorkerID, requeued, req.Reason),
+		WorkerID: worker.WorkerID,
+	})
+
+	return &orchestratorpb.AdminResponse{
+		Success:       true,
+		Message:       fmt.Sprintf("Worker %s %s", worker.WorkerID, action),
+		RequeuedTasks: int32(requeued),
+	}, nil
+}
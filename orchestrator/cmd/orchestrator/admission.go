@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sort"
+)
+
+// Cluster-wide admission limits. A job is admitted only while fewer than
+// maxConcurrentJobs jobs are active and their outstanding tasks stay within
+// maxConcurrentTasks. Zero disables a limit.
+var (
+	maxConcurrentJobs  = getEnvInt("MAX_CONCURRENT_JOBS", 0)
+	maxConcurrentTasks = getEnvInt("MAX_CONCURRENT_TASKS", 0)
+)
+
+// isActive reports whether the job has been admitted and is not finished.
+// Paused jobs keep their admission slot.
+func (j *Job) isActive() bool {
+	return j.isRunning() || j.Status == JobStatusPendingResources || j.Status == JobStatusPaused
+}
+
+// isRunning reports whether the job's tasks are being handed out: it is
+// RUNNING, or STALLED and still waiting for a task to complete.
+func (j *Job) isRunning() bool {
+	return j.Status == JobStatusRunning || j.Status == JobStatusStalled
+}
+
+// outstandingTasks is the number of tasks of the job that still have to run.
+func (j *Job) outstandingTasks() int {
+	return j.TotalTasks - j.CompletedTasks - j.FailedTasks
+}
+
+// submitJob queues a new job for admission and admits whatever fits.
+// Caller must hold s.mu.
+func (s *OrchestratorServer) submitJob(job *Job) {
+	if !job.mustTransition(JobStatusQueued, "Waiting for cluster capacity") {
+		return
+	}
+	// A resumed job may still have a stale entry from before it was cancelled
+	for i, queued := range s.admissionQueue {
+		if queued == job {
+			s.admissionQueue = append(s.admissionQueue[:i], s.admissionQueue[i+1:]...)
+			break
+		}
+	}
+	s.admissionQueue = append(s.admissionQueue, job)
+	s.sortAdmissionQueue()
+
+	s.admitQueuedJobs()
+}
+
+// sortAdmissionQueue orders queued jobs by priority, then by age.
+// Caller must hold s.mu.
+func (s *OrchestratorServer) sortAdmissionQueue() {
+	sort.SliceStable(s.admissionQueue, func(i, k int) bool {
+		a, b := s.admissionQueue[i], s.admissionQueue[k]
+		if a.Priority != b.Priority {
+			return a.Priority > b.Priority
+		}
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+}
+
+// admitQueuedJobs starts queued jobs in order while capacity allows. Jobs are
+// admitted strictly in queue order so a large job at the head is not starved
+// by smaller ones behind it; a high-priority job at the head may preempt
+// running ones. Caller must hold s.mu.
+func (s *OrchestratorServer) admitQueuedJobs() {
+	for len(s.admissionQueue) > 0 {
+		job := s.admissionQueue[0]
+		if job.Status != JobStatusQueued {
+			// Cancelled while waiting
+			s.admissionQueue = s.admissionQueue[1:]
+			continue
+		}
+		if job.GangScheduling && !s.gangAvailable(job) {
+			return
+		}
+		if !s.hasCapacityFor(job) && !s.preemptFor(job) {
+			return
+		}
+
+		s.admissionQueue = s.admissionQueue[1:]
+		if job.GangScheduling {
+			s.reserveGang(job)
+		}
+		s.startJob(job)
+
+		if err := s.saveJob(context.Background(), job); err != nil {
+			log.Printf("Warning: Failed to save job: %v", err)
+		}
+	}
+}
+
+// hasCapacityFor reports whether admitting the job keeps the cluster within
+// its limits. Caller must hold s.mu.
+func (s *OrchestratorServer) hasCapacityFor(job *Job) bool {
+	activeJobs, activeTasks := s.activeLoad()
+	return admissionFits(activeJobs, activeTasks, job)
+}
+
+// activeLoad counts the active jobs and their outstanding tasks. Caller must
+// hold s.mu.
+func (s *OrchestratorServer) activeLoad() (activeJobs, activeTasks int) {
+	for _, other := range s.jobs {
+		if other.isActive() {
+			activeJobs++
+			activeTasks += other.outstandingTasks()
+		}
+	}
+	return activeJobs, activeTasks
+}
+
+// admissionFits reports whether the job fits next to the given load. A job
+// always fits when nothing else is active, so a job larger than
+// maxConcurrentTasks can still run on its own.
+func admissionFits(activeJobs, activeTasks int, job *Job) bool {
+	if activeJobs == 0 {
+		return true
+	}
+	if maxConcurrentJobs > 0 && activeJobs >= maxConcurrentJobs {
+		return false
+	}
+	if maxConcurrentTasks > 0 && activeTasks+job.outstandingTasks() > maxConcurrentTasks {
+		return false
+	}
+	return true
+}
+
+// startJob moves an admitted job to RUNNING and makes its tasks available to
+// workers. Caller must hold s.mu.
+func (s *OrchestratorServer) startJob(job *Job) {
+	if !job.Requirements.IsZero() && !s.hasCapableWorker(job.Requirements) {
+		job.mustTransition(JobStatusPendingResources, noCapableWorkerReason)
+	} else {
+		job.mustTransition(JobStatusRunning, "")
+	}
+	job.StartedAt = job.UpdatedAt
+	job.LastProgressAt = job.UpdatedAt
+
+	s.taskQueue.ClearTombstone(job.JobID)
+	for _, task := range job.Tasks {
+		if task.Status == TaskStatusPending && job.dispatchable(task) {
+			s.enqueueTask(job, task)
+		}
+	}
+	log.Printf("Admitted job %s (%d tasks)", job.JobID, job.TotalTasks)
+	s.recordEvent(job.JobID, JobEvent{Type: EventJobStarted, Message: "Job admitted with status " + string(job.Status)})
+
+	// A preempted job's in-flight tasks may have finished while it waited
+	s.advanceEpochBarrier(job)
+	s.completeJobIfSettled(job)
+}
+
+// queuePosition returns the job's 1-based position in the admission queue,
+// or 0 if it is not queued. Caller must hold s.mu.
+func (s *OrchestratorServer) queuePosition(jobID string) int {
+	position := 0
+	for _, job := range s.admissionQueue {
+		if job.Status != JobStatusQueued {
+			continue
+		}
+		position++
+		if job.JobID == jobID {
+			return position
+		}
+	}
+	return 0
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"sort"
+	"time"
+)
+
+// datasetAffinityWait is how long a queued task is held back for a worker
+// that already has its dataset cached before any worker may take it. Zero
+// disables affinity scheduling.
+var datasetAffinityWait = getEnvDuration("DATASET_AFFINITY_WAIT", 3*time.Second)
+
+// setCachedDatasets replaces the worker's reported dataset cache contents.
+// Caller must hold s.mu.
+func (w *WorkerActivity) setCachedDatasets(datasets []string) {
+	now := time.Now()
+	w.CachedDatasets = make(map[string]time.Time, len(datasets))
+	for _, dataset := range datasets {
+		w.CachedDatasets[dataset] = now
+	}
+}
+
+// markDatasetCached records that the worker has fetched a dataset, e.g.
+// after it completed one of the dataset's tasks. Caller must hold s.mu.
+func (w *WorkerActivity) markDatasetCached(dataset string) {
+	if dataset == "" {
+		return
+	}
+	if w.CachedDatasets == nil {
+		w.CachedDatasets = make(map[string]time.Time)
+	}
+	w.CachedDatasets[dataset] = time.Now()
+}
+
+func (w *WorkerActivity) hasDataset(dataset string) bool {
+	_, ok := w.CachedDatasets[dataset]
+	return ok
+}
+
+func (w *WorkerActivity) cachedDatasetList() []string {
+	datasets := make([]string, 0, len(w.CachedDatasets))
+	for dataset := range w.CachedDatasets {
+		datasets = append(datasets, dataset)
+	}
+	sort.Strings(datasets)
+	return datasets
+}
+
+// preferredElsewhere reports whether a task should be held back from the
+// requesting worker because another idle worker already caches the task's
+// dataset. Tasks are only held for datasetAffinityWait after being queued,
+// so affinity never stalls a job. Caller must hold s.mu.
+func (s *OrchestratorServer) preferredElsewhere(job *Job, task *Task, workerID string, now time.Time) bool {
+	dataset := task.datasetPath(job)
+	if datasetAffinityWait <= 0 || dataset == "" {
+		return false
+	}
+	if worker, ok := s.workers[workerID]; ok && worker.hasDataset(dataset) {
+		return false
+	}
+	if now.Sub(task.QueuedAt) > datasetAffinityWait {
+		return false
+	}
+
+	for id, worker := range s.workers {
+		if id == workerID || worker.Status != WorkerStatusIdle || !worker.schedulable() {
+			continue
+		}
+		if worker.hasDataset(dataset) && job.Requirements.SatisfiedBy(worker.Capabilities) &&
+			job.canServe(id, now) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"log"
+	"math"
+)
+
+// Names of the built-in aggregation rules, see aggregators.go.
+const (
+	AggregationAverage         = "average"
+	AggregationWeightedAverage = "weighted_average"
+	AggregationTrimmedMean     = "trimmed_mean"
+	AggregationSum             = "sum"
+)
+
+// decodeWeights parses model weights in the wire format shared with the
+// workers: a flat array of little-endian float32 values.
+func decodeWeights(data []byte) ([]float32, error) {
+	if len(data)%4 != 0 {
+		return nil, fmt.Errorf("weights length %d is not a multiple of 4", len(data))
+	}
+	weights := make([]float32, len(data)/4)
+	for i := range weights {
+		weights[i] = math.Float32frombits(binary.LittleEndian.Uint32(data[i*4:]))
+	}
+	return weights, nil
+}
+
+func encodeWeights(weights []float32) []byte {
+	data := make([]byte, len(weights)*4)
+	for i, w := range weights {
+		binary.LittleEndian.PutUint32(data[i*4:], math.Float32bits(w))
+	}
+	return data
+}
+
+// collectWeights records the weights a worker returned for a task as a
+// contribution to its epoch. Caller must hold s.mu.
+func (s *OrchestratorServer) collectWeights(job *Job, task *Task, data []byte) {
+	if len(data) == 0 {
+		return
+	}
+	weights, err := decodeWeights(data)
+	if err != nil {
+		log.Printf("Warning: Ignoring weights for task %s: %v", task.TaskID, err)
+		return
+	}
+
+	if job.pendingWeights == nil {
+		job.pendingWeights = make(map[int32][]WeightUpdate)
+	}
+	updates := job.pendingWeights[task.Epoch]
+	if len(updates) > 0 && len(weights) != len(updates[0].Weights) {
+		log.Printf("Warning: Ignoring weights for task %s: got %d values, epoch %d has %d",
+			task.TaskID, len(weights), task.Epoch, len(updates[0].Weights))
+		return
+	}
+
+	job.pendingWeights[task.Epoch] = append(updates, WeightUpdate{
+		Weights: weights,
+		Batches: task.BatchEnd - task.BatchStart,
+	})
+}
+
+// aggregateEpochIfSettled merges the weights collected for an epoch with the
+// job's aggregation rule once all of its tasks have settled. The result
+// becomes the job's model and is sent with every task assigned afterwards.
+// Caller must hold s.mu.
+func (s *OrchestratorServer) aggregateEpochIfSettled(job *Job, epoch int32) {
+	updates, ok := job.pendingWeights[epoch]
+	if !ok {
+		return
+	}
+	if !job.epochSettled(epoch) {
+		return
+	}
+	delete(job.pendingWeights, epoch)
+
+	aggregator, err := lookupAggregator(job.Aggregation)
+	if err != nil {
+		log.Printf("Warning: Not aggregating weights of job %s: %v", job.JobID, err)
+		return
+	}
+
+	// Epochs may settle out of order when they overlap; never replace the
+	// model with an older result unless the rule builds on the current model
+	incremental := false
+	if inc, ok := aggregator.(IncrementalAggregator); ok {
+		incremental = inc.Incremental()
+	}
+	if !incremental && len(job.ModelWeights) > 0 && epoch < job.WeightsEpoch {
+		return
+	}
+
+	var current []float32
+	if len(job.ModelWeights) > 0 {
+		current, err = decodeWeights(job.ModelWeights)
+		if err != nil {
+			log.Printf("Warning: Discarding current weights of job %s: %v", job.JobID, err)
+			current = nil
+		}
+	}
+
+	merged, err := aggregator.Aggregate(current, updates)
+	if err != nil {
+		log.Printf("Warning: Failed to aggregate weights of job %s for epoch %d: %v", job.JobID, epoch, err)
+		return
+	}
+
+	job.ModelWeights = encodeWeights(merged)
+	if epoch > job.WeightsEpoch {
+		job.WeightsEpoch = epoch
+	}
+	log.Printf("Aggregated weights of job %s for epoch %d from %d tasks (%s)",
+		job.JobID, epoch, len(updates), aggregator.Name())
+}
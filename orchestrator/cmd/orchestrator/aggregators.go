@@ -0,0 +1,170 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// WeightUpdate is one task's contribution to an epoch: the weights (or
+// gradients) a worker returned and how many batches produced them.
+type WeightUpdate struct {
+	Weights []float32
+	Batches int32
+}
+
+// Aggregator merges the updates returned for an epoch into the job's next
+// model. current is the model before the epoch and is nil until the first
+// epoch has been aggregated; all updates have the same length. Implementations
+// must not modify their arguments.
+//
+// To experiment with a new rule, implement Aggregator and register it with
+// RegisterAggregator from an init function; jobs select it by name through
+// the aggregation field of their spec.
+type Aggregator interface {
+	Name() string
+	Aggregate(current []float32, updates []WeightUpdate) ([]float32, error)
+}
+
+// IncrementalAggregator is implemented by aggregators whose result builds on
+// the current model, such as gradient sums. Their output stays valid when an
+// older epoch settles after a newer one, so it is never skipped as stale.
+type IncrementalAggregator interface {
+	Aggregator
+	Incremental() bool
+}
+
+var (
+	aggregatorsMu sync.RWMutex
+	aggregators   = make(map[string]Aggregator)
+)
+
+// RegisterAggregator makes an aggregation rule available to jobs under its
+// name, replacing any previous rule with that name.
+func RegisterAggregator(a Aggregator) {
+	aggregatorsMu.Lock()
+	defer aggregatorsMu.Unlock()
+	aggregators[a.Name()] = a
+}
+
+// lookupAggregator returns the aggregation rule registered under name.
+func lookupAggregator(name string) (Aggregator, error) {
+	aggregatorsMu.RLock()
+	defer aggregatorsMu.RUnlock()
+	a, ok := aggregators[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown aggregation mode: %s", name)
+	}
+	return a, nil
+}
+
+func init() {
+	RegisterAggregator(FedAvg{})
+	RegisterAggregator(WeightedAverage{})
+	RegisterAggregator(TrimmedMean{Fraction: 0.1})
+	RegisterAggregator(GradientSum{})
+}
+
+// FedAvg is federated averaging: the new model is the plain mean of the
+// weights returned by the epoch's tasks.
+type FedAvg struct{}
+
+func (FedAvg) Name() string { return AggregationAverage }
+
+func (FedAvg) Aggregate(current []float32, updates []WeightUpdate) ([]float32, error) {
+	merged := make([]float64, len(updates[0].Weights))
+	for _, u := range updates {
+		for i, w := range u.Weights {
+			merged[i] += float64(w)
+		}
+	}
+	return scale(merged, 1/float64(len(updates))), nil
+}
+
+// WeightedAverage averages the returned weights weighted by how many batches
+// each task trained on, so larger shards count for more.
+type WeightedAverage struct{}
+
+func (WeightedAverage) Name() string { return AggregationWeightedAverage }
+
+func (WeightedAverage) Aggregate(current []float32, updates []WeightUpdate) ([]float32, error) {
+	total := 0.0
+	for _, u := range updates {
+		total += float64(u.Batches)
+	}
+	if total <= 0 {
+		return FedAvg{}.Aggregate(current, updates)
+	}
+
+	merged := make([]float64, len(updates[0].Weights))
+	for _, u := range updates {
+		for i, w := range u.Weights {
+			merged[i] += float64(w) * float64(u.Batches)
+		}
+	}
+	return scale(merged, 1/total), nil
+}
+
+// TrimmedMean drops the Fraction largest and smallest values of each
+// parameter before averaging, which limits the influence of outlier or
+// faulty workers.
+type TrimmedMean struct {
+	Fraction float64
+}
+
+func (TrimmedMean) Name() string { return AggregationTrimmedMean }
+
+func (t TrimmedMean) Aggregate(current []float32, updates []WeightUpdate) ([]float32, error) {
+	if t.Fraction < 0 || t.Fraction >= 0.5 {
+		return nil, fmt.Errorf("trim fraction must be in [0, 0.5), got %v", t.Fraction)
+	}
+	trim := int(float64(len(updates)) * t.Fraction)
+	kept := len(updates) - 2*trim
+
+	merged := make([]float64, len(updates[0].Weights))
+	values := make([]float64, len(updates))
+	for i := range merged {
+		for k, u := range updates {
+			values[k] = float64(u.Weights[i])
+		}
+		sort.Float64s(values)
+		for _, v := range values[trim : trim+kept] {
+			merged[i] += v
+		}
+	}
+	return scale(merged, 1/float64(kept)), nil
+}
+
+// GradientSum treats the returned values as gradients and applies their sum
+// to the current model.
+type GradientSum struct{}
+
+func (GradientSum) Name() string { return AggregationSum }
+
+func (GradientSum) Incremental() bool { return true }
+
+func (GradientSum) Aggregate(current []float32, updates []WeightUpdate) ([]float32, error) {
+	merged := make([]float64, len(updates[0].Weights))
+	if current != nil {
+		if len(current) != len(merged) {
+			return nil, fmt.Errorf("gradients have %d values, model has %d", len(merged), len(current))
+		}
+		for i, w := range current {
+			merged[i] = float64(w)
+		}
+	}
+	for _, u := range updates {
+		for i, g := range u.Weights {
+			merged[i] += float64(g)
+		}
+	}
+	return scale(merged, 1), nil
+}
+
+func scale(values []float64, factor float64) []float32 {
+	out := make([]float32, len(values))
+	for i, v := range values {
+		out[i] = float32(v * factor)
+	}
+	return out
+}
@@ -0,0 +1,385 @@
+package main
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	orchestratorpb "github.com/tensorfleet/orchestrator/proto/orchestrator"
+	orchestratorv2pb "github.com/tensorfleet/orchestrator/proto/orchestratorv2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// V2Server serves orchestrator.v2.OrchestratorService. It is a translation
+// layer over the v1 service: requests are mapped onto the v1 handlers or
+// read the same state, so both versions always agree.
+type V2Server struct {
+	orchestratorv2pb.UnimplementedOrchestratorServiceServer
+	s *OrchestratorServer
+}
+
+func NewV2Server(s *OrchestratorServer) *V2Server {
+	return &V2Server{s: s}
+}
+
+// v2Enum maps a v1 status string onto the v2 enum value with the same name,
+// e.g. RUNNING onto JOB_STATE_RUNNING; unknown statuses map to 0.
+func v2Enum(values map[string]int32, prefix, status string) int32 {
+	return values[prefix+status]
+}
+
+func jobStateV2(st JobStatus) orchestratorv2pb.JobState {
+	return orchestratorv2pb.JobState(v2Enum(orchestratorv2pb.JobState_value, "JOB_STATE_", string(st)))
+}
+
+// jobStatusFromV2 is the JobStatus named by a v2 state.
+func jobStatusFromV2(state orchestratorv2pb.JobState) (JobStatus, bool) {
+	st := JobStatus(strings.TrimPrefix(state.String(), "JOB_STATE_"))
+	_, ok := jobStatusProto[st]
+	return st, ok
+}
+
+func workerSpecV2(cpu int32, memory int64, gpus int32, gpuType string, labels map[string]string) *orchestratorv2pb.WorkerSpec {
+	return &orchestratorv2pb.WorkerSpec{
+		CpuCores: cpu,
+		MemoryMb: memory,
+		GpuCount: gpus,
+		GpuType:  gpuType,
+		Labels:   labels,
+	}
+}
+
+// jobV2 describes a job; detailed adds its history and current allocation.
+// Caller must hold s.mu.
+func (s *OrchestratorServer) jobV2(job *Job, detailed bool) *orchestratorv2pb.Job {
+	info := s.jobInfo(job, detailed)
+	req := job.Requirements
+
+	spec := &orchestratorv2pb.JobSpec{
+		UserId:                 job.UserID,
+		Namespace:              job.namespace(),
+		Region:                 job.Region,
+		ModelType:              job.ModelType,
+		DatasetPath:            job.DatasetPath,
+		Hyperparameters:        job.Hyperparameters,
+		NumWorkers:             job.NumWorkers,
+		Epochs:                 job.Epochs,
+		Priority:               job.Priority,
+		Requirements:           workerSpecV2(req.MinCPUCores, req.MinMemoryMB, req.MinGPUCount, req.GPUType, req.Labels),
+		SyncEpochs:             job.SyncEpochs,
+		GangScheduling:         job.GangScheduling,
+		MaxParallelTasks:       job.MaxParallelTasks,
+		Image:                  job.Image,
+		Stages:                 job.Stages,
+		ValidationDatasetPath:  job.ValidationDatasetPath,
+		ResultCache:            job.ResultCache,
+		Aggregation:            job.Aggregation,
+		CheckpointEveryEpochs:  job.CheckpointEvery,
+		MaxDurationSeconds:     int64(job.MaxDuration / time.Second),
+		ProgressTimeoutSeconds: int64(job.ProgressTimeout / time.Second),
+		TaskTimeoutSeconds:     int64(job.TaskTimeout / time.Second),
+		MaxFailedTasksPercent:  job.MaxFailedTasksPercent,
+		BatchesPerEpoch:        job.BatchesPerEpoch,
+		SamplesPerBatch:        job.SamplesPerBatch,
+		DatasetSize:            job.DatasetSize,
+		DependsOn:              job.DependsOn,
+		OnDependencyFailure:    job.OnDependencyFailure,
+	}
+	retries := int32(job.MaxTaskRetries)
+	spec.MaxTaskRetries = &retries
+	if es := job.EarlyStopping; es != nil {
+		spec.EarlyStopping = &orchestratorv2pb.EarlyStopping{Metric: es.Metric, Patience: es.Patience, MinDelta: es.MinDelta}
+	}
+
+	out := &orchestratorv2pb.Job{
+		JobId:            job.JobID,
+		Spec:             spec,
+		State:            jobStateV2(job.Status),
+		StatusMessage:    job.StatusMessage,
+		Progress:         info.Progress,
+		CurrentEpoch:     job.CurrentEpoch,
+		TotalTasks:       info.TotalTasks,
+		CompletedTasks:   info.CompletedTasks,
+		FailedTasks:      info.FailedTasks,
+		CurrentLoss:      job.CurrentLoss,
+		CurrentAccuracy:  job.CurrentAccuracy,
+		CreatedAtMs:      info.CreatedAtMs,
+		UpdatedAtMs:      info.UpdatedAtMs,
+		StartedAtMs:      info.StartedAtMs,
+		AllocatedWorkers: info.AllocatedWorkers,
+		QueuePosition:    info.QueuePosition,
+		CachedTasks:      int32(job.CachedTasks),
+	}
+	if detailed {
+		for _, t := range job.Transitions {
+			out.Transitions = append(out.Transitions, &orchestratorv2pb.StatusTransition{
+				From:        jobStateV2(t.From),
+				To:          jobStateV2(t.To),
+				Reason:      t.Reason,
+				TimestampMs: t.At.UnixMilli(),
+			})
+		}
+		for _, m := range info.EpochMetrics {
+			out.EpochMetrics = append(out.EpochMetrics, &orchestratorv2pb.EpochMetrics{
+				Epoch:          m.Epoch,
+				Loss:           m.Loss,
+				Accuracy:       m.Accuracy,
+				CompletedTasks: m.CompletedTasks,
+				Settled:        m.Complete,
+			})
+		}
+	}
+	return out
+}
+
+// getJob returns the full v2 view of a job.
+func (v *V2Server) getJob(ctx context.Context, jobID string) (*orchestratorv2pb.Job, error) {
+	s := v.s
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, err := s.lookupJob(ctx, jobID)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "job not found: %s", jobID)
+	}
+	return s.jobV2(job, true), nil
+}
+
+// requireJob fails with NOT_FOUND unless the job is loaded.
+func (v *V2Server) requireJob(jobID string) error {
+	v.s.mu.RLock()
+	defer v.s.mu.RUnlock()
+	if _, ok := v.s.jobs[jobID]; !ok {
+		return status.Errorf(codes.NotFound, "job not found: %s", jobID)
+	}
+	return nil
+}
+
+func (v *V2Server) CreateJob(ctx context.Context, req *orchestratorv2pb.CreateJobRequest) (*orchestratorv2pb.Job, error) {
+	spec := req.Spec
+	if spec == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "spec is required")
+	}
+
+	v1 := &orchestratorpb.TrainingJobRequest{
+		JobId:                  req.JobId,
+		UserId:                 spec.UserId,
+		Namespace:              spec.Namespace,
+		Region:                 spec.Region,
+		ModelType:              spec.ModelType,
+		DatasetPath:            spec.DatasetPath,
+		Hyperparameters:        spec.Hyperparameters,
+		NumWorkers:             spec.NumWorkers,
+		Epochs:                 spec.Epochs,
+		MaxDurationSeconds:     spec.MaxDurationSeconds,
+		ProgressTimeoutSeconds: spec.ProgressTimeoutSeconds,
+		MaxTaskRetries:         spec.MaxTaskRetries,
+		MaxFailedTasksPercent:  spec.MaxFailedTasksPercent,
+		Priority:               spec.Priority,
+		SyncEpochs:             spec.SyncEpochs,
+		GangScheduling:         spec.GangScheduling,
+		MaxParallelTasks:       spec.MaxParallelTasks,
+		Image:                  spec.Image,
+		Stages:                 spec.Stages,
+		ValidationDatasetPath:  spec.ValidationDatasetPath,
+		ResultCache:            spec.ResultCache,
+		Aggregation:            spec.Aggregation,
+		CheckpointEveryEpochs:  spec.CheckpointEveryEpochs,
+		TaskTimeoutSeconds:     spec.TaskTimeoutSeconds,
+		BatchesPerEpoch:        spec.BatchesPerEpoch,
+		SamplesPerBatch:        spec.SamplesPerBatch,
+		DatasetSize:            spec.DatasetSize,
+		DependsOn:              spec.DependsOn,
+		OnDependencyFailure:    spec.OnDependencyFailure,
+	}
+	if r := spec.Requirements; r != nil {
+		v1.Requirements = &orchestratorpb.ResourceRequirements{
+			MinCpuCores: r.CpuCores,
+			MinMemoryMb: r.MemoryMb,
+			MinGpuCount: r.GpuCount,
+			GpuType:     r.GpuType,
+			Labels:      r.Labels,
+		}
+	}
+	if es := spec.EarlyStopping; es != nil {
+		v1.EarlyStopping = &orchestratorpb.EarlyStopping{Metric: es.Metric, Patience: es.Patience, MinDelta: es.MinDelta}
+	}
+
+	if _, err := v.s.CreateTrainingJob(ctx, v1); err != nil {
+		return nil, err
+	}
+	return v.getJob(ctx, req.JobId)
+}
+
+func (v *V2Server) GetJob(ctx context.Context, req *orchestratorv2pb.GetJobRequest) (*orchestratorv2pb.Job, error) {
+	return v.getJob(ctx, req.JobId)
+}
+
+func (v *V2Server) ListJobs(ctx context.Context, req *orchestratorv2pb.ListJobsRequest) (*orchestratorv2pb.ListJobsResponse, error) {
+	v1 := &orchestratorpb.ListJobsRequest{
+		UserId:    req.UserId,
+		Namespace: req.Namespace,
+		ModelType: req.ModelType,
+		PageSize:  req.PageSize,
+		PageToken: req.PageToken,
+	}
+	for _, state := range req.States {
+		st, ok := jobStatusFromV2(state)
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown job state: %s", state)
+		}
+		v1.States = append(v1.States, st.toProto())
+	}
+
+	resp := &orchestratorv2pb.ListJobsResponse{}
+	next, total, err := v.s.listJobs(ctx, v1, func(job *Job) {
+		resp.Jobs = append(resp.Jobs, v.s.jobV2(job, false))
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp.NextPageToken = next
+	resp.Total = int32(total)
+	return resp, nil
+}
+
+// jobChange checks the outcome of a v1 job-changing RPC and returns the job
+// as it is now.
+func (v *V2Server) jobChange(ctx context.Context, jobID string, success bool, message string, err error) (*orchestratorv2pb.Job, error) {
+	if err != nil {
+		return nil, err
+	}
+	if !success {
+		return nil, status.Error(codes.FailedPrecondition, message)
+	}
+	return v.getJob(ctx, jobID)
+}
+
+func (v *V2Server) CancelJob(ctx context.Context, req *orchestratorv2pb.CancelJobRequest) (*orchestratorv2pb.Job, error) {
+	if err := v.requireJob(req.JobId); err != nil {
+		return nil, err
+	}
+	resp, err := v.s.CancelJob(ctx, &orchestratorpb.CancelJobRequest{JobId: req.JobId})
+	return v.jobChange(ctx, req.JobId, resp.GetSuccess(), resp.GetMessage(), err)
+}
+
+func (v *V2Server) PauseJob(ctx context.Context, req *orchestratorv2pb.PauseJobRequest) (*orchestratorv2pb.Job, error) {
+	if err := v.requireJob(req.JobId); err != nil {
+		return nil, err
+	}
+	resp, err := v.s.PauseJob(ctx, &orchestratorpb.PauseJobRequest{JobId: req.JobId})
+	return v.jobChange(ctx, req.JobId, resp.GetSuccess(), resp.GetMessage(), err)
+}
+
+func (v *V2Server) ResumeJob(ctx context.Context, req *orchestratorv2pb.ResumeJobRequest) (*orchestratorv2pb.Job, error) {
+	// Finished jobs may only be in the job store, which ResumeJob consults
+	resp, err := v.s.ResumeJob(ctx, &orchestratorpb.ResumeJobRequest{JobId: req.JobId})
+	return v.jobChange(ctx, req.JobId, resp.GetSuccess(), resp.GetMessage(), err)
+}
+
+func (v *V2Server) ListTasks(ctx context.Context, req *orchestratorv2pb.ListTasksRequest) (*orchestratorv2pb.ListTasksResponse, error) {
+	taskStatus := ""
+	if req.State != orchestratorv2pb.TaskState_TASK_STATE_UNSPECIFIED {
+		taskStatus = strings.TrimPrefix(req.State.String(), "TASK_STATE_")
+	}
+	resp, err := v.s.GetJobTasks(ctx, &orchestratorpb.GetJobTasksRequest{
+		JobId:     req.JobId,
+		Status:    taskStatus,
+		Epoch:     req.Epoch,
+		WorkerId:  req.WorkerId,
+		PageSize:  req.PageSize,
+		PageToken: req.PageToken,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := &orchestratorv2pb.ListTasksResponse{
+		Tasks:         make([]*orchestratorv2pb.Task, 0, len(resp.Tasks)),
+		NextPageToken: resp.NextPageToken,
+		Total:         resp.Total,
+	}
+	for _, t := range resp.Tasks {
+		out.Tasks = append(out.Tasks, &orchestratorv2pb.Task{
+			TaskId:           t.TaskId,
+			JobId:            resp.JobId,
+			State:            orchestratorv2pb.TaskState(v2Enum(orchestratorv2pb.TaskState_value, "TASK_STATE_", t.Status)),
+			WorkerId:         t.WorkerId,
+			Epoch:            t.Epoch,
+			BatchStart:       t.BatchStart,
+			BatchEnd:         t.BatchEnd,
+			Loss:             t.Loss,
+			Accuracy:         t.Accuracy,
+			Attempts:         t.Attempts,
+			LastError:        t.LastError,
+			CreatedAtMs:      t.CreatedAtMs,
+			AssignedAtMs:     t.AssignedAtMs,
+			CompletedAtMs:    t.CompletedAtMs,
+			LeaseExpiresAtMs: t.LeaseExpiresAtMs,
+			WaitMs:           t.WaitMs,
+			RunMs:            t.RunMs,
+		})
+	}
+	return out, nil
+}
+
+func (v *V2Server) ListJobEvents(ctx context.Context, req *orchestratorv2pb.ListJobEventsRequest) (*orchestratorv2pb.ListJobEventsResponse, error) {
+	resp, err := v.s.GetJobEvents(ctx, &orchestratorpb.JobEventsRequest{JobId: req.JobId, Type: req.Type})
+	if err != nil {
+		return nil, err
+	}
+
+	out := &orchestratorv2pb.ListJobEventsResponse{
+		Events: make([]*orchestratorv2pb.JobEvent, 0, len(resp.Events)),
+	}
+	for _, e := range resp.Events {
+		out.Events = append(out.Events, &orchestratorv2pb.JobEvent{
+			Type:        e.Type,
+			Message:     e.Message,
+			TaskId:      e.TaskId,
+			WorkerId:    e.WorkerId,
+			TimestampMs: e.TimestampMs,
+		})
+	}
+	return out, nil
+}
+
+func (v *V2Server) ListWorkers(ctx context.Context, req *orchestratorv2pb.ListWorkersRequest) (*orchestratorv2pb.ListWorkersResponse, error) {
+	s := v.s
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := &orchestratorv2pb.ListWorkersResponse{}
+	for _, worker := range s.workers {
+		state := orchestratorv2pb.WorkerState(v2Enum(orchestratorv2pb.WorkerState_value, "WORKER_STATE_", worker.displayStatus()))
+		if req.State != orchestratorv2pb.WorkerState_WORKER_STATE_UNSPECIFIED && state != req.State {
+			continue
+		}
+		reason := worker.quarantine.Reason
+		if !worker.quarantine.Quarantined {
+			reason = worker.DrainReason
+		}
+		c := worker.Capabilities
+		out.Workers = append(out.Workers, &orchestratorv2pb.Worker{
+			WorkerId:           worker.WorkerID,
+			State:              state,
+			StateReason:        reason,
+			Hostname:           worker.Hostname,
+			Address:            worker.Address,
+			Capabilities:       workerSpecV2(c.CPUCores, c.MemoryMB, c.GPUCount, c.GPUType, c.Labels),
+			CurrentTaskId:      worker.CurrentTaskID,
+			CurrentJobId:       worker.CurrentJobID,
+			TasksCompleted:     int32(worker.TasksCompleted),
+			MaxConcurrentTasks: int32(worker.maxInFlight()),
+			InFlightTasks:      int32(worker.inFlight()),
+			CachedDatasets:     worker.cachedDatasetList(),
+			LastHeartbeatMs:    unixMillis(worker.LastHeartbeat),
+			RegisteredAtMs:     unixMillis(worker.RegisteredAt),
+			Executor:           worker.Executor,
+		})
+	}
+	sort.Slice(out.Workers, func(i, k int) bool { return out.Workers[i].WorkerId < out.Workers[k].WorkerId })
+	return out, nil
+}
@@ -0,0 +1,394 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The autoscaler resizes the worker Deployment from the orchestrator's own
+// view of the cluster: pending tasks waiting for a worker and workers sitting
+// idle. It adds workers while tasks wait and none is idle, and removes idle
+// workers once nothing waits, within AUTOSCALE_MIN_WORKERS and
+// AUTOSCALE_MAX_WORKERS. After scaling up it waits AUTOSCALE_UP_COOLDOWN
+// before scaling up again, giving new pods time to register, and
+// AUTOSCALE_DOWN_COOLDOWN after any change before scaling down. Before
+// scaling down it sets the deletion cost of each worker's pod to the tasks
+// the worker holds, so the Deployment removes idle workers rather than ones
+// running tasks. Only the leader scales.
+var (
+	autoscaling             = os.Getenv("AUTOSCALE") == "true"
+	autoscaleDeployment     = os.Getenv("AUTOSCALE_DEPLOYMENT")
+	autoscaleMinWorkers     = getEnvInt("AUTOSCALE_MIN_WORKERS", 1)
+	autoscaleMaxWorkers     = getEnvInt("AUTOSCALE_MAX_WORKERS", 10)
+	autoscaleTasksPerWorker = getEnvInt("AUTOSCALE_TASKS_PER_WORKER", 4)
+	autoscaleInterval       = getEnvDuration("AUTOSCALE_INTERVAL", 30*time.Second)
+	autoscaleUpCooldown     = getEnvDuration("AUTOSCALE_UP_COOLDOWN", time.Minute)
+	autoscaleDownCooldown   = getEnvDuration("AUTOSCALE_DOWN_COOLDOWN", 5*time.Minute)
+)
+
+const (
+	serviceAccountDir       = "/var/run/secrets/kubernetes.io/serviceaccount"
+	serviceAccountTokenPath = serviceAccountDir + "/token"
+	defaultWorkerDeployment = "worker"
+
+	// Pods of a ReplicaSet with the lowest cost are deleted first when it
+	// is scaled down
+	podDeletionCostAnnotation = "controller.kubernetes.io/pod-deletion-cost"
+)
+
+// EventWorkersScaled is published when the autoscaler resizes the worker
+// Deployment.
+const EventWorkersScaled = "WORKER_POOL_SCALED"
+
+// Autoscaler scales the worker Deployment through the Kubernetes API. A nil
+// autoscaler does nothing.
+type Autoscaler struct {
+	s          *OrchestratorServer
+	kube       *kubeClient
+	deployment string
+
+	lastScaleUp time.Time
+	lastScale   time.Time
+}
+
+// newAutoscaler returns nil unless AUTOSCALE=true and the orchestrator runs
+// in a Kubernetes pod with a service account.
+func newAutoscaler(s *OrchestratorServer) *Autoscaler {
+	if !autoscaling {
+		return nil
+	}
+
+	kube, err := newInClusterKubeClient()
+	if err != nil {
+		log.Printf("Warning: Autoscaling disabled: %v", err)
+		return nil
+	}
+	deployment := autoscaleDeployment
+	if deployment == "" {
+		deployment = defaultWorkerDeployment
+	}
+
+	log.Printf("Autoscaling deployment %s/%s between %d and %d workers",
+		kube.namespace, deployment, autoscaleMinWorkers, autoscaleMaxWorkers)
+	return &Autoscaler{s: s, kube: kube, deployment: deployment}
+}
+
+// run re-evaluates the worker count every autoscaleInterval while leading
+// until ctx is done.
+func (a *Autoscaler) run(ctx context.Context, leading func() bool) {
+	if a == nil {
+		return
+	}
+
+	ticker := time.NewTicker(autoscaleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !leading() {
+				continue
+			}
+			if err := a.evaluate(ctx); err != nil {
+				log.Printf("Warning: Autoscaling failed: %v", err)
+			}
+		}
+	}
+}
+
+// workerPoolLoad is the demand on the worker pool at one point in time.
+type workerPoolLoad struct {
+	Busy          int // Live workers running tasks
+	Idle          int // Live workers with nothing to do
+	WaitingTasks  int // Pending tasks of running jobs ready to be dispatched
+	GangShortfall int // Workers a gang job at the head of the queue still lacks
+}
+
+// workerPoolLoad snapshots the demand on the worker pool. Draining,
+// quarantined and offline workers are left out.
+func (s *OrchestratorServer) workerPoolLoad() workerPoolLoad {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var load workerPoolLoad
+	for _, worker := range s.workers {
+		if !worker.schedulable() {
+			continue
+		}
+		if worker.Status == WorkerStatusIdle && worker.inFlight() == 0 {
+			load.Idle++
+		} else {
+			load.Busy++
+		}
+	}
+	for _, job := range s.jobs {
+		if !job.isRunning() {
+			continue
+		}
+		waiting := 0
+		for _, task := range job.Tasks {
+			if task.Status == TaskStatusPending && job.dispatchable(task) {
+				waiting++
+			}
+		}
+		load.WaitingTasks += job.parallelismHeadroom(waiting)
+	}
+	if len(s.admissionQueue) > 0 {
+		if head := s.admissionQueue[0]; head.GangScheduling && head.Status == JobStatusQueued {
+			if free := len(s.freeGangWorkers(head)); free < int(head.NumWorkers) {
+				load.GangShortfall = int(head.NumWorkers) - free
+			}
+		}
+	}
+	return load
+}
+
+// podDeletionCosts maps the pods of live workers to the tasks they hold.
+// A worker's hostname is the name of its pod.
+func (s *OrchestratorServer) podDeletionCosts() map[string]int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	costs := make(map[string]int, len(s.workers))
+	for _, worker := range s.workers {
+		if worker.Hostname == "" || worker.Status == WorkerStatusOffline {
+			continue
+		}
+		cost := worker.inFlight()
+		if cost == 0 && worker.Status != WorkerStatusIdle {
+			cost = 1
+		}
+		costs[worker.Hostname] = cost
+	}
+	return costs
+}
+
+// desiredWorkers is the worker count for the load, starting from the
+// current replica count and clamped to the configured bounds.
+func desiredWorkers(current int, load workerPoolLoad) int {
+	desired := current
+	switch {
+	case load.WaitingTasks > 0 && load.Idle == 0:
+		perWorker := autoscaleTasksPerWorker
+		if perWorker < 1 {
+			perWorker = 1
+		}
+		if want := load.Busy + (load.WaitingTasks+perWorker-1)/perWorker; want > desired {
+			desired = want
+		}
+	case load.WaitingTasks == 0 && load.GangShortfall == 0 && load.Idle > 0:
+		desired = current - load.Idle
+	}
+	if load.GangShortfall > 0 {
+		if want := load.Busy + load.Idle + load.GangShortfall; want > desired {
+			desired = want
+		}
+	}
+
+	if desired > autoscaleMaxWorkers {
+		desired = autoscaleMaxWorkers
+	}
+	if desired < autoscaleMinWorkers {
+		desired = autoscaleMinWorkers
+	}
+	return desired
+}
+
+func (a *Autoscaler) evaluate(ctx context.Context) error {
+	current, err := a.kube.getScale(ctx, a.deployment)
+	if err != nil {
+		return err
+	}
+	load := a.s.workerPoolLoad()
+	desired := desiredWorkers(current, load)
+
+	now := time.Now()
+	switch {
+	case desired == current:
+		return nil
+	case desired > current && now.Sub(a.lastScaleUp) < autoscaleUpCooldown:
+		return nil
+	case desired < current && now.Sub(a.lastScale) < autoscaleDownCooldown:
+		return nil
+	}
+
+	if desired < current {
+		if err := a.rankWorkerPods(ctx); err != nil {
+			return fmt.Errorf("not scaling down, failed to rank worker pods: %v", err)
+		}
+	}
+	if err := a.kube.setScale(ctx, a.deployment, desired); err != nil {
+		return err
+	}
+	a.lastScale = now
+	if desired > current {
+		a.lastScaleUp = now
+	}
+
+	message := fmt.Sprintf("Scaled workers from %d to %d (%d busy, %d idle, %d tasks waiting",
+		current, desired, load.Busy, load.Idle, load.WaitingTasks)
+	if load.GangShortfall > 0 {
+		message += fmt.Sprintf(", gang job short of %d workers", load.GangShortfall)
+	}
+	message += ")"
+	log.Print(message)
+	a.s.events.publish(ClusterEvent{Type: EventWorkersScaled, Message: message})
+	return nil
+}
+
+// rankWorkerPods sets the deletion cost of each worker's pod so that
+// scaling down removes idle workers first. Pods that are gone, or workers
+// not running in a pod of the namespace, are skipped.
+func (a *Autoscaler) rankWorkerPods(ctx context.Context) error {
+	for pod, cost := range a.s.podDeletionCosts() {
+		err := a.kube.annotatePod(ctx, pod, podDeletionCostAnnotation, strconv.Itoa(cost))
+		var apiErr *kubeAPIError
+		if errors.As(err, &apiErr) && apiErr.Status == http.StatusNotFound {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// kubeClient is just enough of a Kubernetes API client to read and set a
+// Deployment's replica count and annotate its pods, authenticated as the
+// pod's service account.
+type kubeClient struct {
+	baseURL   string
+	namespace string
+	client    *http.Client
+}
+
+func newInClusterKubeClient() (*kubeClient, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, fmt.Errorf("not running in a Kubernetes cluster")
+	}
+	if _, err := os.Stat(serviceAccountTokenPath); err != nil {
+		return nil, fmt.Errorf("no service account token: %v", err)
+	}
+	caCert, err := os.ReadFile(serviceAccountDir + "/ca.crt")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cluster CA: %v", err)
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(caCert) {
+		return nil, fmt.Errorf("invalid cluster CA certificate")
+	}
+
+	namespace := os.Getenv("AUTOSCALE_NAMESPACE")
+	if namespace == "" {
+		if ns, err := os.ReadFile(serviceAccountDir + "/namespace"); err == nil {
+			namespace = strings.TrimSpace(string(ns))
+		}
+	}
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	return &kubeClient{
+		baseURL:   "https://" + net.JoinHostPort(host, port),
+		namespace: namespace,
+		client: &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}},
+		},
+	}, nil
+}
+
+// deploymentScale is the autoscaling/v1 Scale subresource of a Deployment.
+type deploymentScale struct {
+	Spec struct {
+		Replicas int `json:"replicas"`
+	} `json:"spec"`
+}
+
+func (k *kubeClient) scaleURL(deployment string) string {
+	return fmt.Sprintf("%s/apis/apps/v1/namespaces/%s/deployments/%s/scale", k.baseURL, k.namespace, deployment)
+}
+
+func (k *kubeClient) getScale(ctx context.Context, deployment string) (int, error) {
+	var scale deploymentScale
+	if err := k.do(ctx, http.MethodGet, k.scaleURL(deployment), "", nil, &scale); err != nil {
+		return 0, err
+	}
+	return scale.Spec.Replicas, nil
+}
+
+func (k *kubeClient) setScale(ctx context.Context, deployment string, replicas int) error {
+	patch := []byte(fmt.Sprintf(`{"spec":{"replicas":%d}}`, replicas))
+	return k.do(ctx, http.MethodPatch, k.scaleURL(deployment), "application/merge-patch+json", patch, nil)
+}
+
+func (k *kubeClient) annotatePod(ctx context.Context, pod, key, value string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": map[string]string{key: value}},
+	})
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s/api/v1/namespaces/%s/pods/%s", k.baseURL, k.namespace, pod)
+	return k.do(ctx, http.MethodPatch, url, "application/merge-patch+json", patch, nil)
+}
+
+// kubeAPIError is a Kubernetes API request answered with an error status.
+type kubeAPIError struct {
+	Method string
+	URL    string
+	Status int
+	Detail string
+}
+
+func (e *kubeAPIError) Error() string {
+	return fmt.Sprintf("%s %s: status %d: %s", e.Method, e.URL, e.Status, e.Detail)
+}
+
+func (k *kubeClient) do(ctx context.Context, method, url, contentType string, body []byte, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	// The kubelet rotates the token file, so it is read for every request
+	token, err := os.ReadFile(serviceAccountTokenPath)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	req.Header.Set("Accept", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &kubeAPIError{Method: method, URL: url, Status: resp.StatusCode, Detail: strings.TrimSpace(string(detail))}
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+)
+
+// dispatchable reports whether a task may be queued now. Training waits for
+// the job's preprocessing, validation for its epoch and evaluation for all
+// of its training. Jobs in sync-epoch mode only release the training tasks
+// of their current epoch.
+func (j *Job) dispatchable(task *Task) bool {
+	switch task.taskType() {
+	case TaskTypePreprocess:
+		return true
+	case TaskTypeEvaluate:
+		return j.trainingSettled()
+	case TaskTypeValidate:
+		return j.SettledEpochs[task.Epoch]
+	}
+	if !j.preprocessed() {
+		return false
+	}
+	return !j.SyncEpochs || task.Epoch <= j.CurrentEpoch
+}
+
+// epochSettled reports whether no training task of the epoch is still
+// waiting or running.
+func (j *Job) epochSettled(epoch int32) bool {
+	for _, task := range j.Tasks {
+		if task.Epoch == epoch && !task.isStage() && (task.Status == TaskStatusPending || task.Status == TaskStatusAssigned) {
+			return false
+		}
+	}
+	return true
+}
+
+// onTaskSettled runs the per-epoch bookkeeping after a task of the job
+// completed or failed permanently, and queues the tasks it unblocked.
+// Caller must hold s.mu.
+func (s *OrchestratorServer) onTaskSettled(ctx context.Context, job *Job, task *Task) {
+	defer s.releaseStages(job, task)
+	if task.taskType() == TaskTypeValidate {
+		s.onValidationSettled(ctx, job, task)
+		return
+	}
+	if task.isStage() {
+		if task.Status == TaskStatusCompleted {
+			s.onStageSettled(job, task)
+		}
+		return
+	}
+	s.aggregateEpochIfSettled(job, task.Epoch)
+	if job.epochSettled(task.Epoch) && !job.SettledEpochs[task.Epoch] {
+		if job.SettledEpochs == nil {
+			job.SettledEpochs = make(map[int32]bool)
+		}
+		job.SettledEpochs[task.Epoch] = true
+		s.recordEpochSample(job, task.Epoch)
+		s.recordEvent(job.JobID, JobEvent{
+			Type:    EventEpochCompleted,
+			Message: fmt.Sprintf("Epoch %d completed", task.Epoch),
+		})
+	}
+	s.maybeCheckpoint(job)
+	s.maybeStopEarly(job)
+}
+
+// advanceEpochBarrier releases the next epoch of a sync-epoch job once every
+// task of the current epoch has settled. Caller must hold s.mu.
+func (s *OrchestratorServer) advanceEpochBarrier(job *Job) {
+	if !job.SyncEpochs || !job.isActive() {
+		return
+	}
+
+	for job.CurrentEpoch < job.Epochs-1 && job.epochSettled(job.CurrentEpoch) {
+		job.CurrentEpoch++
+		released := 0
+		for _, task := range job.Tasks {
+			if task.Epoch == job.CurrentEpoch && task.Status == TaskStatusPending && job.dispatchable(task) {
+				s.enqueueTask(job, task)
+				released++
+			}
+		}
+		log.Printf("Job %s passed epoch barrier, released %d tasks of epoch %d",
+			job.JobID, released, job.CurrentEpoch)
+	}
+}
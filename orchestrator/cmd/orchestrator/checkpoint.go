@@ -0,0 +1,258 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	orchestratorpb "github.com/tensorfleet/orchestrator/proto/orchestrator"
+	storagepb "github.com/tensorfleet/orchestrator/proto/storage"
+	"google.golang.org/grpc/status"
+)
+
+// Checkpoint records the model state of a job after an epoch.
+type Checkpoint struct {
+	Epoch     int32
+	Path      string // Location in the storage service, once uploaded
+	Loss      float64
+	Accuracy  float64
+	CreatedAt time.Time
+}
+
+// storageServiceURL is the base URL of the storage service.
+func storageServiceURL() string {
+	if url := os.Getenv("STORAGE_SERVICE_URL"); url != "" {
+		return url
+	}
+	return "http://storage:8081"
+}
+
+// maybeCheckpoint records a checkpoint once every CheckpointEvery-th epoch
+// has settled, together with every epoch before it. The model weights are
+// kept on the job for resuming and uploaded to the storage service in the
+// background. Caller must hold s.mu.
+func (s *OrchestratorServer) maybeCheckpoint(job *Job) {
+	if job.CheckpointEvery <= 0 {
+		return
+	}
+
+	epoch := int32(-1)
+	for e := int32(0); e < job.Epochs && job.epochSettled(e); e++ {
+		if (e+1)%job.CheckpointEvery == 0 {
+			epoch = e
+		}
+	}
+	if epoch < 0 {
+		return
+	}
+	if latest := job.latestCheckpoint(); latest != nil && latest.Epoch >= epoch {
+		return
+	}
+
+	checkpoint := Checkpoint{
+		Epoch:     epoch,
+		CreatedAt: time.Now(),
+	}
+	if m := job.epochMetrics(epoch); m != nil {
+		checkpoint.Loss = m.Loss
+		checkpoint.Accuracy = m.Accuracy
+	}
+	job.Checkpoints = append(job.Checkpoints, checkpoint)
+	job.CheckpointWeights = job.ModelWeights
+	log.Printf("Checkpointed job %s after epoch %d", job.JobID, epoch)
+	s.recordEvent(job.JobID, JobEvent{
+		Type:    EventCheckpointSaved,
+		Message: fmt.Sprintf("Checkpoint recorded after epoch %d", epoch),
+	})
+
+	go s.uploadCheckpoint(job.JobID, job.ModelType, job.DatasetPath, checkpoint, job.CheckpointWeights)
+}
+
+// latestCheckpoint returns the job's most recent checkpoint, or nil.
+func (j *Job) latestCheckpoint() *Checkpoint {
+	if len(j.Checkpoints) == 0 {
+		return nil
+	}
+	return &j.Checkpoints[len(j.Checkpoints)-1]
+}
+
+// uploadCheckpoint stores checkpoint weights, in the object store if there
+// is one and through the storage service otherwise, registers the
+// checkpoint with the storage service and records where it went.
+func (s *OrchestratorServer) uploadCheckpoint(jobID, modelType, datasetPath string, checkpoint Checkpoint, weights []byte) {
+	req := &storagepb.SaveCheckpointRequest{
+		JobId:       jobID,
+		JobName:     jobID,
+		Algorithm:   modelType,
+		DatasetName: datasetPath,
+		Epoch:       checkpoint.Epoch,
+		Metrics: map[string]float64{
+			"loss":     checkpoint.Loss,
+			"accuracy": checkpoint.Accuracy,
+		},
+	}
+	if s.objects != nil {
+		key, digest, err := s.objects.PutContent(context.Background(), checkpointsBucket, weights)
+		if err != nil {
+			log.Printf("Warning: Failed to write checkpoint of job %s to object store, uploading it instead: %v", jobID, err)
+		} else {
+			req.DataObject = &storagepb.StoredObject{
+				ObjectName: key,
+				Checksum:   digest,
+				SizeBytes:  int64(len(weights)),
+			}
+		}
+	}
+	if req.DataObject == nil {
+		req.Data = weights
+	}
+
+	resp, err := s.storage.SaveCheckpoint(withStorageAuth(context.Background()), req)
+	if err != nil {
+		log.Printf("Warning: Failed to upload checkpoint for job %s: %v", jobID, err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if job, ok := s.jobs[jobID]; ok {
+		for i := range job.Checkpoints {
+			if job.Checkpoints[i].Epoch == checkpoint.Epoch {
+				job.Checkpoints[i].Path = resp.Location
+			}
+		}
+		if err := s.saveJob(context.Background(), job); err != nil {
+			log.Printf("Warning: Failed to save job: %v", err)
+		}
+	}
+	log.Printf("Uploaded checkpoint of job %s for epoch %d to %s", jobID, checkpoint.Epoch, resp.Location)
+}
+
+// ResumeJob continues a paused job, or restarts a failed or cancelled job
+// from its latest checkpoint (from the first epoch if it has none). Epochs
+// after the checkpoint are rerun from scratch.
+func (s *OrchestratorServer) ResumeJob(ctx context.Context, req *orchestratorpb.ResumeJobRequest) (*orchestratorpb.ResumeJobResponse, error) {
+	if region := s.delegatedRegion(ctx, req.JobId); region != "" {
+		return s.resumeDelegatedJob(ctx, req.JobId, region)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.draining {
+		return nil, drainingError()
+	}
+
+	job, exists := s.jobs[req.JobId]
+	if !exists {
+		var err error
+		job, err = s.loadJob(ctx, req.JobId)
+		if err != nil {
+			return &orchestratorpb.ResumeJobResponse{
+				Success: false,
+				Message: fmt.Sprintf("Job not found: %s", req.JobId),
+			}, nil
+		}
+		s.jobs[req.JobId] = job
+	}
+
+	previousStatus := job.Status
+	if err := s.checkQuota(ctx, job.UserID); err != nil {
+		return &orchestratorpb.ResumeJobResponse{
+			Success:        false,
+			Message:        status.Convert(err).Message(),
+			PreviousStatus: string(previousStatus),
+		}, nil
+	}
+	if job.Status == JobStatusPaused {
+		return s.unpauseJob(ctx, job), nil
+	}
+	if !job.Status.CanTransitionTo(JobStatusQueued) {
+		return &orchestratorpb.ResumeJobResponse{
+			Success:        false,
+			Message:        fmt.Sprintf("Cannot resume job with status: %s", job.Status),
+			PreviousStatus: string(previousStatus),
+		}, nil
+	}
+	if job.TotalTasks == 0 {
+		// Failed before its tasks were created, e.g. a missing dataset
+		return &orchestratorpb.ResumeJobResponse{
+			Success:        false,
+			Message:        "Job has no tasks to resume; submit it again",
+			PreviousStatus: string(previousStatus),
+		}, nil
+	}
+
+	resumeEpoch := int32(0)
+	job.ModelWeights = nil
+	job.WeightsEpoch = 0
+	if latest := job.latestCheckpoint(); latest != nil {
+		resumeEpoch = latest.Epoch + 1
+		job.ModelWeights = job.CheckpointWeights
+		job.WeightsEpoch = latest.Epoch
+	}
+	if resumeEpoch >= job.Epochs {
+		return &orchestratorpb.ResumeJobResponse{
+			Success:        false,
+			Message:        "Job has no epochs left to run after its latest checkpoint",
+			PreviousStatus: string(previousStatus),
+		}, nil
+	}
+
+	// Rerun everything after the checkpoint; earlier epochs keep their results
+	job.CompletedTasks, job.FailedTasks = 0, 0
+	for _, task := range job.Tasks {
+		if task.Epoch >= resumeEpoch {
+			task.Status = TaskStatusPending
+			task.WorkerID = ""
+			task.Attempts = 0
+			task.LastError = ""
+			task.AssignedAt = nil
+			task.LeaseExpiresAt = time.Time{}
+			task.CompletedAt = nil
+			continue
+		}
+		switch task.Status {
+		case TaskStatusCompleted:
+			job.CompletedTasks++
+		case TaskStatusFailed:
+			job.FailedTasks++
+		}
+	}
+	job.pendingWeights = nil
+	job.CurrentEpoch = resumeEpoch
+	for epoch := range job.SettledEpochs {
+		if epoch >= resumeEpoch {
+			delete(job.SettledEpochs, epoch)
+		}
+	}
+	kept := job.EpochMetrics[:0]
+	for _, m := range job.EpochMetrics {
+		if m.Epoch < resumeEpoch {
+			kept = append(kept, m)
+		}
+	}
+	job.EpochMetrics = kept
+	job.dropValidationMetrics(resumeEpoch)
+	job.refreshCurrentMetrics()
+
+	s.submitOrBlock(ctx, job)
+	if err := s.saveJob(ctx, job); err != nil {
+		log.Printf("Warning: Failed to save job: %v", err)
+	}
+
+	log.Printf("Resumed job %s from epoch %d (previous status: %s)", job.JobID, resumeEpoch, previousStatus)
+	s.recordEvent(job.JobID, JobEvent{
+		Type:    EventJobResumed,
+		Message: fmt.Sprintf("Resumed from epoch %d (previous status: %s)", resumeEpoch, previousStatus),
+	})
+
+	return &orchestratorpb.ResumeJobResponse{
+		Success:        true,
+		Message:        fmt.Sprintf("Job %s resumed from epoch %d", job.JobID, resumeEpoch),
+		PreviousStatus: string(previousStatus),
+		ResumeEpoch:    resumeEpoch,
+	}, nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// getEnvDuration reads a Go duration string (e.g. "30s", "5m") from the
+// environment, falling back to def when unset or invalid.
+func getEnvDuration(key string, def time.Duration) time.Duration {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return def
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		log.Printf("Warning: invalid %s=%q, using default %s", key, raw, def)
+		return def
+	}
+	return d
+}
+
+// getEnvFloat reads a decimal number from the environment, falling back to
+// def when unset or invalid.
+func getEnvFloat(key string, def float64) float64 {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return def
+	}
+	f, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		log.Printf("Warning: invalid %s=%q, using default %g", key, raw, def)
+		return def
+	}
+	return f
+}
+
+// getEnvInt reads an integer from the environment, falling back to def when
+// unset or invalid.
+func getEnvInt(key string, def int) int {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return def
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil {
+		log.Printf("Warning: invalid %s=%q, using default %d", key, raw, def)
+		return def
+	}
+	return n
+}
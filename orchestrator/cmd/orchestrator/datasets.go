@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	orchestratorpb "github.com/tensorfleet/orchestrator/proto/orchestrator"
+)
+
+// datasetPlanning makes job creation look the dataset up in the storage
+// service and shard it by its real record count. Jobs whose dataset does not
+// exist fail straight away. Set DATASET_PLANNING=false to shard by the
+// request alone.
+var datasetPlanning = os.Getenv("DATASET_PLANNING") != "false"
+
+var errDatasetNotFound = errors.New("dataset not found")
+
+// DatasetInfo is what the storage service knows about a dataset.
+type DatasetInfo struct {
+	Name      string `json:"name"`
+	MinioPath string `json:"minio_path"`
+	SizeBytes int64  `json:"size_bytes"`
+	NumRows   *int64 `json:"num_rows"` // Unknown for unregistered objects
+}
+
+// fetchDatasetInfo asks the storage service about the dataset a job refers
+// to. It returns errDatasetNotFound if the storage service has no such
+// dataset.
+func fetchDatasetInfo(ctx context.Context, path string) (*DatasetInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	endpoint := storageServiceURL() + "/api/v1/datasets/info?path=" + url.QueryEscape(path)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errDatasetNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("storage service returned status %d", resp.StatusCode)
+	}
+
+	var info DatasetInfo
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, fmt.Errorf("failed to decode dataset info: %v", err)
+	}
+	return &info, nil
+}
+
+// planShards works out the task layout of a new job. The dataset's record
+// count, when the storage service knows it, takes precedence over the size
+// given in the request. If the storage service cannot be reached the
+// request's layout is used as is.
+func planShards(ctx context.Context, req *orchestratorpb.TrainingJobRequest) (shardPlan, error) {
+	requested := shardPlan{
+		BatchesPerEpoch: req.BatchesPerEpoch,
+		SamplesPerBatch: req.SamplesPerBatch,
+		DatasetSize:     req.DatasetSize,
+	}
+
+	if datasetPlanning {
+		info, err := fetchDatasetInfo(ctx, req.DatasetPath)
+		switch {
+		case errors.Is(err, errDatasetNotFound):
+			return shardPlan{}, fmt.Errorf("dataset not found: %s", req.DatasetPath)
+		case err != nil:
+			log.Printf("Warning: Cannot look up dataset %s, sharding by request: %v", req.DatasetPath, err)
+		case info.NumRows != nil && *info.NumRows > 0:
+			if requested.DatasetSize > 0 && requested.DatasetSize != *info.NumRows {
+				log.Printf("Dataset %s has %d records, not the requested %d",
+					req.DatasetPath, *info.NumRows, requested.DatasetSize)
+			}
+			requested.DatasetSize = *info.NumRows
+		}
+	}
+
+	return planBatches(requested, req.Epochs)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// defaultProgressTimeout applies to jobs that don't set their own
+// progress_timeout_seconds. Zero disables the no-progress check.
+var (
+	defaultProgressTimeout = getEnvDuration("JOB_PROGRESS_TIMEOUT", 0)
+	jobWatchdogInterval    = getEnvDuration("JOB_WATCHDOG_INTERVAL", 10*time.Second)
+)
+
+// monitorJobDeadlines cancels running jobs that exceed their wall-clock
+// limit or stop making progress, and flags the ones that have stalled.
+func (s *OrchestratorServer) monitorJobDeadlines(ctx context.Context) {
+	ticker := time.NewTicker(jobWatchdogInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.enforceJobDeadlines(ctx)
+			s.detectStalledJobs(ctx)
+		}
+	}
+}
+
+func (s *OrchestratorServer) enforceJobDeadlines(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for _, job := range s.jobs {
+		if !job.isRunning() {
+			continue
+		}
+
+		reason := ""
+		switch {
+		case job.MaxDuration > 0 && now.Sub(job.StartedAt) > job.MaxDuration:
+			reason = fmt.Sprintf("Timed out: exceeded max duration of %s", job.MaxDuration)
+		case job.ProgressTimeout > 0 && now.Sub(job.LastProgressAt) > job.ProgressTimeout:
+			reason = fmt.Sprintf("Timed out: no task completed for %s", job.ProgressTimeout)
+		default:
+			continue
+		}
+
+		log.Printf("Cancelling job %s: %s", job.JobID, reason)
+		s.cancelJobLocked(job, reason)
+
+		if err := s.saveJob(ctx, job); err != nil {
+			log.Printf("Warning: Failed to save job: %v", err)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// What happens to a job when one of its dependencies fails or is cancelled.
+const (
+	DependencyFailureFail   = "fail"   // Fail the job (default)
+	DependencyFailureCancel = "cancel" // Cancel the job
+	DependencyFailureIgnore = "ignore" // Run the job anyway
+)
+
+// validateDependencies checks a new job's dependency list and failure
+// policy. Dependencies must already exist, which also keeps the dependency
+// graph acyclic. Caller must hold s.mu.
+func (s *OrchestratorServer) validateDependencies(ctx context.Context, job *Job) error {
+	switch job.OnDependencyFailure {
+	case DependencyFailureFail, DependencyFailureCancel, DependencyFailureIgnore:
+	default:
+		return fmt.Errorf("unknown dependency failure policy: %s", job.OnDependencyFailure)
+	}
+
+	seen := make(map[string]bool, len(job.DependsOn))
+	for _, id := range job.DependsOn {
+		if id == job.JobID {
+			return fmt.Errorf("job cannot depend on itself")
+		}
+		if seen[id] {
+			return fmt.Errorf("duplicate dependency: %s", id)
+		}
+		seen[id] = true
+		if _, err := s.lookupJob(ctx, id); err != nil {
+			return fmt.Errorf("dependency not found: %s", id)
+		}
+	}
+	return nil
+}
+
+// lookupJob finds a job in memory or, failing that, in the job store.
+// Caller must hold s.mu.
+func (s *OrchestratorServer) lookupJob(ctx context.Context, jobID string) (*Job, error) {
+	if job, ok := s.jobs[jobID]; ok {
+		return job, nil
+	}
+	return s.loadJob(ctx, jobID)
+}
+
+// dependencyStatus reports which of the job's dependencies have yet to
+// finish, and why the first unsuccessful one will not complete, if any.
+// Caller must hold s.mu.
+func (s *OrchestratorServer) dependencyStatus(ctx context.Context, job *Job) (waiting []string, failure string) {
+	for _, id := range job.DependsOn {
+		dep, err := s.lookupJob(ctx, id)
+		switch {
+		case err != nil:
+			if failure == "" {
+				failure = fmt.Sprintf("dependency %s no longer exists", id)
+			}
+		case dep.Status == JobStatusCompleted || dep.Status == JobStatusCompletedEarly:
+		case dep.Status == JobStatusFailed || dep.Status == JobStatusCancelled:
+			if failure == "" {
+				failure = fmt.Sprintf("dependency %s is %s", id, dep.Status)
+			}
+		default:
+			waiting = append(waiting, id)
+		}
+	}
+	return waiting, failure
+}
+
+// submitOrBlock queues a job whose tasks are ready, holding it in BLOCKED
+// while any of its dependencies has not completed. Caller must hold s.mu.
+func (s *OrchestratorServer) submitOrBlock(ctx context.Context, job *Job) {
+	if len(job.DependsOn) > 0 {
+		if waiting, _ := s.dependencyStatus(ctx, job); len(waiting) > 0 {
+			reason := "Waiting for dependencies: " + strings.Join(waiting, ", ")
+			if !job.mustTransition(JobStatusBlocked, reason) {
+				return
+			}
+			s.recordEvent(job.JobID, JobEvent{Type: EventJobBlocked, Message: reason})
+			// A dependency that already failed is handled right away
+			s.resolveDependencies(ctx, job)
+			return
+		}
+	}
+	s.submitJob(job)
+}
+
+// resolveDependencies moves a BLOCKED job on once its dependencies allow:
+// it is queued when all of them have completed, and failed or cancelled,
+// according to its policy, as soon as one of them fails. Caller must hold
+// s.mu.
+func (s *OrchestratorServer) resolveDependencies(ctx context.Context, job *Job) {
+	if job.Status != JobStatusBlocked {
+		return
+	}
+
+	waiting, failure := s.dependencyStatus(ctx, job)
+	if failure != "" {
+		reason := "Upstream " + failure
+		switch job.OnDependencyFailure {
+		case DependencyFailureCancel:
+			s.cancelJobLocked(job, reason)
+			return
+		case DependencyFailureFail:
+			job.mustTransition(JobStatusFailed, reason)
+			log.Printf("Job %s failed: %s", job.JobID, reason)
+			s.recordEvent(job.JobID, JobEvent{Type: EventJobFailed, Message: reason})
+			s.releaseDependents(ctx, job)
+			return
+		}
+	}
+	if len(waiting) > 0 {
+		return
+	}
+
+	s.recordEvent(job.JobID, JobEvent{Type: EventJobUnblocked, Message: "All dependencies finished"})
+	log.Printf("Dependencies of job %s finished, queueing it", job.JobID)
+	s.submitJob(job)
+}
+
+// releaseDependents re-evaluates the BLOCKED jobs that depend on a job that
+// just finished. Caller must hold s.mu.
+func (s *OrchestratorServer) releaseDependents(ctx context.Context, finished *Job) {
+	for _, job := range s.jobs {
+		if job.Status != JobStatusBlocked || !job.dependsOn(finished.JobID) {
+			continue
+		}
+		s.resolveDependencies(ctx, job)
+		if job.Status != JobStatusBlocked {
+			if err := s.saveJob(ctx, job); err != nil {
+				log.Printf("Warning: Failed to save job: %v", err)
+			}
+		}
+	}
+}
+
+func (j *Job) dependsOn(jobID string) bool {
+	for _, id := range j.DependsOn {
+		if id == jobID {
+			return true
+		}
+	}
+	return false
+}
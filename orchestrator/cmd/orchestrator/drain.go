@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	orchestratorpb "github.com/tensorfleet/orchestrator/proto/orchestrator"
+)
+
+// drainTimeout bounds how long a drain waits for in-flight tasks to be
+// reported before the orchestrator shuts down anyway. Tasks still assigned
+// then are requeued by whichever orchestrator loads the persisted state.
+var drainTimeout = getEnvDuration("DRAIN_TIMEOUT", 60*time.Second)
+
+// errDraining is returned to callers that ask for new work during a drain.
+var errDraining = errors.New("orchestrator is draining")
+
+// drainingError is the gRPC form of errDraining; clients retry elsewhere.
+func drainingError() error {
+	return status.Error(codes.Unavailable, errDraining.Error())
+}
+
+// startDrain stops the orchestrator from accepting jobs and handing out
+// tasks. It reports false if a drain was already under way.
+func (s *OrchestratorServer) startDrain(reason string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.draining {
+		return false
+	}
+	s.draining = true
+	log.Printf("Draining orchestrator (%s); %d tasks in flight", reason, s.inFlightTasks())
+	return true
+}
+
+// isDraining reports whether a drain has started.
+func (s *OrchestratorServer) isDraining() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.draining
+}
+
+// inFlightTasks counts tasks assigned to workers and not yet reported.
+// Caller must hold s.mu.
+func (s *OrchestratorServer) inFlightTasks() int {
+	count := 0
+	for _, job := range s.jobs {
+		for _, task := range job.Tasks {
+			if task.Status == TaskStatusAssigned {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// finishDrain waits for in-flight tasks to be reported, then persists all
+// jobs.
+func (s *OrchestratorServer) finishDrain(ctx context.Context) {
+	s.waitForInFlightTasks(ctx)
+
+	s.mu.Lock()
+	for _, job := range s.jobs {
+		if err := s.saveJob(context.Background(), job); err != nil {
+			log.Printf("Warning: Failed to save job %s: %v", job.JobID, err)
+		}
+	}
+	log.Printf("Persisted %d jobs", len(s.jobs))
+	s.mu.Unlock()
+
+	s.writes.flush(ctx)
+}
+
+// waitForInFlightTasks returns once no task is assigned to a worker or the
+// context expires.
+func (s *OrchestratorServer) waitForInFlightTasks(ctx context.Context) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		s.mu.RLock()
+		remaining := s.inFlightTasks()
+		s.mu.RUnlock()
+		if remaining == 0 {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Printf("Warning: Drain timed out with %d tasks in flight", remaining)
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Drain starts a graceful shutdown. It returns once the drain has begun;
+// the orchestrator exits after in-flight tasks are reported.
+func (s *OrchestratorServer) Drain(ctx context.Context, req *orchestratorpb.DrainRequest) (*orchestratorpb.DrainResponse, error) {
+	reason := req.Reason
+	if reason == "" {
+		reason = "Drain requested"
+	}
+
+	if !s.startDrain(reason) {
+		return &orchestratorpb.DrainResponse{
+			Success: false,
+			Message: "Orchestrator is already draining",
+		}, nil
+	}
+
+	s.mu.RLock()
+	inFlight := s.inFlightTasks()
+	s.mu.RUnlock()
+
+	select {
+	case s.drainRequested <- struct{}{}:
+	default:
+	}
+
+	return &orchestratorpb.DrainResponse{
+		Success:       true,
+		Message:       "Orchestrator is draining",
+		InFlightTasks: int32(inFlight),
+	}, nil
+}
+
+// stopGracefully lets open RPCs finish, but stops the server outright if
+// long-lived streams keep it open past the timeout.
+func stopGracefully(grpcServer *grpc.Server, timeout time.Duration) {
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(timeout):
+		log.Println("Warning: Forcing gRPC server to stop")
+		grpcServer.Stop()
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	orchestratorpb "github.com/tensorfleet/orchestrator/proto/orchestrator"
+)
+
+// Task durations run from assignment to successful completion. Each job and
+// each worker keeps the latest taskDurationWindow of them, from which tail
+// percentiles are computed. Once a job has taskTimeoutMinSamples durations,
+// a running task that has taken stragglerFactor times the job's P95 is
+// flagged as straggling, and the job's remaining time is estimated from its
+// median.
+var (
+	taskDurationWindow = getEnvInt("TASK_DURATION_WINDOW", 500)
+	stragglerFactor    = getEnvFloat("STRAGGLER_FACTOR", 2)
+)
+
+// durationWindow is a ring of the most recent task durations.
+type durationWindow struct {
+	Samples []time.Duration
+	Next    int // Sample overwritten next once the window is full
+}
+
+func (w *durationWindow) observe(d time.Duration) {
+	if taskDurationWindow <= 0 {
+		return
+	}
+	if len(w.Samples) < taskDurationWindow {
+		w.Samples = append(w.Samples, d)
+		return
+	}
+	if w.Next >= len(w.Samples) {
+		w.Next = 0
+	}
+	w.Samples[w.Next] = d
+	w.Next++
+}
+
+// durationStats are nearest-rank percentiles of a durationWindow.
+type durationStats struct {
+	Samples       int
+	P50, P95, P99 time.Duration
+}
+
+func (w *durationWindow) stats() durationStats {
+	if len(w.Samples) == 0 {
+		return durationStats{}
+	}
+	sorted := append([]time.Duration(nil), w.Samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	rank := func(p float64) time.Duration {
+		i := int(math.Ceil(p/100*float64(len(sorted)))) - 1
+		if i < 0 {
+			i = 0
+		}
+		return sorted[i]
+	}
+	return durationStats{
+		Samples: len(sorted),
+		P50:     rank(50),
+		P95:     rank(95),
+		P99:     rank(99),
+	}
+}
+
+func (d durationStats) toProto() *orchestratorpb.TaskDurationStats {
+	return &orchestratorpb.TaskDurationStats{
+		Samples: int32(d.Samples),
+		P50Ms:   d.P50.Milliseconds(),
+		P95Ms:   d.P95.Milliseconds(),
+		P99Ms:   d.P99.Milliseconds(),
+	}
+}
+
+// observeTaskDuration records a completed task's run time for its job and
+// the worker that ran it. Caller must hold s.mu.
+func (s *OrchestratorServer) observeTaskDuration(job *Job, workerID string, d time.Duration) {
+	job.observeTaskDuration(d)
+	if worker, ok := s.workers[workerID]; ok {
+		worker.TaskDurations.observe(d)
+	}
+}
+
+// stragglerThreshold is how long one of the job's tasks may run before it is
+// considered straggling, or 0 while too few tasks have completed to tell.
+func (j *Job) stragglerThreshold() time.Duration {
+	if stragglerFactor <= 0 || len(j.TaskDurations.Samples) < taskTimeoutMinSamples {
+		return 0
+	}
+	return time.Duration(float64(j.TaskDurations.stats().P95) * stragglerFactor)
+}
+
+// flagStragglers marks the job's running tasks that have passed the
+// straggler threshold, or whose reported progress projects that they will,
+// recording an event the first time per attempt. Caller must hold s.mu.
+func (s *OrchestratorServer) flagStragglers(job *Job, now time.Time) bool {
+	threshold := job.stragglerThreshold()
+	if threshold <= 0 {
+		return false
+	}
+
+	flagged := false
+	for _, task := range job.Tasks {
+		// Stages cover the whole dataset and are not measured by its batches
+		if task.Status != TaskStatusAssigned || task.Straggling || task.AssignedAt == nil || task.isStage() {
+			continue
+		}
+		elapsed := now.Sub(*task.AssignedAt)
+		projected := task.projectedDuration(now)
+		if projected < threshold {
+			continue
+		}
+		task.Straggling = true
+		flagged = true
+
+		message := fmt.Sprintf("Running for %s, past %.1f× the job's P95 task duration",
+			elapsed.Round(time.Second), stragglerFactor)
+		if projected > elapsed {
+			message = fmt.Sprintf("Projected to take %s at its reported progress (%.0f%% in %s), past %.1f× the job's P95 task duration",
+				projected.Round(time.Second), task.Progress.fraction()*100, elapsed.Round(time.Second), stragglerFactor)
+		}
+		log.Printf("Task %s of job %s on worker %s is straggling: %s", task.TaskID, job.JobID, task.WorkerID, message)
+		s.recordEvent(job.JobID, JobEvent{
+			Type:     EventTaskStraggling,
+			Message:  message,
+			TaskID:   task.TaskID,
+			WorkerID: task.WorkerID,
+		})
+	}
+	return flagged
+}
+
+// estimatedRemaining is how long the job's unfinished tasks should take at
+// its median task duration, spread over the workers serving it, or 0 while
+// too few tasks have completed to tell.
+func (j *Job) estimatedRemaining(now time.Time) time.Duration {
+	if len(j.TaskDurations.Samples) < taskTimeoutMinSamples {
+		return 0
+	}
+	remaining := j.TotalTasks - j.CompletedTasks - j.FailedTasks
+	if remaining <= 0 {
+		return 0
+	}
+	parallel := len(j.activeReservations(now))
+	if parallel == 0 {
+		parallel = 1
+	}
+	rounds := (remaining + parallel - 1) / parallel
+	return j.TaskDurations.stats().P50 * time.Duration(rounds)
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	orchestratorpb "github.com/tensorfleet/orchestrator/proto/orchestrator"
+)
+
+// Metrics early stopping can monitor: those of training, and those of the
+// epoch's validation on a held-out dataset.
+const (
+	EarlyStopMetricLoss        = "loss"         // Lower is better
+	EarlyStopMetricAccuracy    = "accuracy"     // Higher is better
+	EarlyStopMetricValLoss     = "val_loss"     // Lower is better
+	EarlyStopMetricValAccuracy = "val_accuracy" // Higher is better
+)
+
+// EarlyStoppingPolicy stops a job once its monitored epoch metric has not
+// improved by more than MinDelta for Patience settled epochs in a row.
+type EarlyStoppingPolicy struct {
+	Metric   string
+	Patience int32
+	MinDelta float64
+}
+
+// earlyStoppingFromProto validates a job's early-stopping settings. It
+// returns nil if early stopping is disabled.
+func earlyStoppingFromProto(p *orchestratorpb.EarlyStopping) (*EarlyStoppingPolicy, error) {
+	if p == nil || p.Patience <= 0 {
+		return nil, nil
+	}
+	policy := &EarlyStoppingPolicy{
+		Metric:   p.Metric,
+		Patience: p.Patience,
+		MinDelta: p.MinDelta,
+	}
+	if policy.Metric == "" {
+		policy.Metric = EarlyStopMetricLoss
+	}
+	switch policy.Metric {
+	case EarlyStopMetricLoss, EarlyStopMetricAccuracy, EarlyStopMetricValLoss, EarlyStopMetricValAccuracy:
+	default:
+		return nil, fmt.Errorf("unknown early stopping metric: %s", policy.Metric)
+	}
+	if policy.MinDelta < 0 {
+		return nil, fmt.Errorf("early stopping min_delta must not be negative")
+	}
+	return policy, nil
+}
+
+// validated reports whether the policy monitors the epochs' validation
+// rather than their training.
+func (p *EarlyStoppingPolicy) validated() bool {
+	return p.Metric == EarlyStopMetricValLoss || p.Metric == EarlyStopMetricValAccuracy
+}
+
+func (p *EarlyStoppingPolicy) higherIsBetter() bool {
+	return p.Metric == EarlyStopMetricAccuracy || p.Metric == EarlyStopMetricValAccuracy
+}
+
+func (p *EarlyStoppingPolicy) value(m *EpochMetrics) float64 {
+	if p.higherIsBetter() {
+		return m.Accuracy
+	}
+	return m.Loss
+}
+
+func (p *EarlyStoppingPolicy) improves(value, best float64) bool {
+	if p.higherIsBetter() {
+		return value > best+p.MinDelta
+	}
+	return value < best-p.MinDelta
+}
+
+// plateauReason walks the job's settled epochs in order and returns why the
+// job should stop, or "" while the metric is still improving. Validation
+// metrics are only walked as far as the epochs' validation has settled. The
+// last epoch never triggers a stop since the job finishes anyway.
+func (j *Job) plateauReason() string {
+	p := j.EarlyStopping
+	var best float64
+	bestEpoch := int32(-1)
+	for e := int32(0); e < j.Epochs-1 && j.SettledEpochs[e]; e++ {
+		m := j.epochMetrics(e)
+		if p.validated() {
+			if !j.validationSettled(e) {
+				break
+			}
+			m = j.validationMetrics(e)
+		}
+		if m == nil {
+			// No task of the epoch completed; it tells nothing about the metric
+			continue
+		}
+		if v := p.value(m); bestEpoch < 0 || p.improves(v, best) {
+			best, bestEpoch = v, e
+			continue
+		}
+		if e-bestEpoch >= p.Patience {
+			return fmt.Sprintf("%s did not improve by more than %g for %d epochs (best %.4f at epoch %d)",
+				p.Metric, p.MinDelta, p.Patience, best, bestEpoch)
+		}
+	}
+	return ""
+}
+
+// maybeStopEarly completes the job early once its monitored metric has
+// plateaued. Tasks of later epochs are cancelled. Caller must hold s.mu.
+func (s *OrchestratorServer) maybeStopEarly(job *Job) {
+	if job.EarlyStopping == nil || !job.isActive() {
+		return
+	}
+	reason := job.plateauReason()
+	if reason == "" || !job.mustTransition(JobStatusCompletedEarly, reason) {
+		return
+	}
+	job.dropReservations()
+	drained := s.cancelOutstandingTasks(job)
+
+	log.Printf("Job %s stopped early: %s", job.JobID, reason)
+	s.recordEvent(job.JobID, JobEvent{
+		Type:    EventJobCompletedEarly,
+		Message: fmt.Sprintf("Stopped early after epoch %d: %s (%d tasks skipped)", job.lastSettledEpoch(), reason, drained),
+	})
+	s.releaseDependents(context.Background(), job)
+	s.admitQueuedJobs()
+
+	go s.autoSaveModel(context.Background(), job.JobID, job)
+}
+
+// lastSettledEpoch is the last epoch of the job's settled prefix, or -1.
+func (j *Job) lastSettledEpoch() int32 {
+	e := int32(-1)
+	for e+1 < j.Epochs && j.SettledEpochs[e+1] {
+		e++
+	}
+	return e
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	orchestratorpb "github.com/tensorfleet/orchestrator/proto/orchestrator"
+)
+
+// Job event types.
+const (
+	EventJobCreated        = "JOB_CREATED"
+	EventJobStarted        = "JOB_STARTED"
+	EventJobResumed        = "JOB_RESUMED"
+	EventJobCompleted      = "JOB_COMPLETED"
+	EventJobCompletedEarly = "JOB_COMPLETED_EARLY"
+	EventJobFailed         = "JOB_FAILED"
+	EventJobCancelled      = "JOB_CANCELLED"
+	EventJobBlocked        = "JOB_BLOCKED"
+	EventJobUnblocked      = "JOB_UNBLOCKED"
+	EventJobPreempted      = "JOB_PREEMPTED"
+	EventJobStalled        = "JOB_STALLED"
+	EventJobRecovered      = "JOB_RECOVERED"
+	EventJobDelegated      = "JOB_DELEGATED"
+	EventGangReserved      = "GANG_RESERVED"
+	EventTaskAssigned      = "TASK_ASSIGNED"
+	EventTaskRetried       = "TASK_RETRIED"
+	EventTaskFailed        = "TASK_FAILED"
+	EventTaskPreempted     = "TASK_PREEMPTED"
+	EventTaskStraggling    = "TASK_STRAGGLING"
+	EventTaskReleased      = "TASK_RELEASED"
+	EventTaskCached        = "TASK_CACHED"
+	EventWorkerFailed      = "WORKER_FAILED"
+	EventEpochCompleted    = "EPOCH_COMPLETED"
+	EventStageCompleted    = "STAGE_COMPLETED"
+	EventEpochValidated    = "EPOCH_VALIDATED"
+	EventCheckpointSaved   = "CHECKPOINT_SAVED"
+	EventModelSaved        = "MODEL_SAVED"
+	EventModelSaveFailed   = "MODEL_SAVE_FAILED"
+	EventAdminAction       = "ADMIN_ACTION"
+)
+
+// JobEvent is one entry of a job's event log.
+type JobEvent struct {
+	Type      string `json:"type"`
+	Message   string `json:"message"`
+	TaskID    string `json:"task_id,omitempty"`
+	WorkerID  string `json:"worker_id,omitempty"`
+	Timestamp int64  `json:"timestamp"` // Unix milliseconds
+}
+
+// recordEvent appends an event to the job's log in the job store and
+// publishes it.
+func (s *OrchestratorServer) recordEvent(jobID string, event JobEvent) {
+	event.Timestamp = time.Now().UnixMilli()
+	s.writes.queue("record "+event.Type+" event for job "+jobID, func(ctx context.Context) error {
+		return s.store.AppendEvent(ctx, jobID, event)
+	})
+	s.events.publish(ClusterEvent{
+		Type:      event.Type,
+		JobID:     jobID,
+		TaskID:    event.TaskID,
+		WorkerID:  event.WorkerID,
+		Message:   event.Message,
+		Timestamp: event.Timestamp,
+	})
+}
+
+func (s *OrchestratorServer) GetJobEvents(ctx context.Context, req *orchestratorpb.JobEventsRequest) (*orchestratorpb.JobEventsResponse, error) {
+	stored, err := s.store.ListEvents(ctx, req.JobId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load job events: %v", err)
+	}
+
+	events := make([]*orchestratorpb.JobEvent, 0, len(stored))
+	for _, event := range stored {
+		if req.Type != "" && event.Type != req.Type {
+			continue
+		}
+		events = append(events, &orchestratorpb.JobEvent{
+			Type:        event.Type,
+			Message:     event.Message,
+			TaskId:      event.TaskID,
+			WorkerId:    event.WorkerID,
+			TimestampMs: event.Timestamp,
+		})
+	}
+
+	return &orchestratorpb.JobEventsResponse{
+		JobId:  req.JobId,
+		Events: events,
+	}, nil
+}
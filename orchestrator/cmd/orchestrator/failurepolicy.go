@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// maxFailureSummaryTasks caps how many failing tasks are listed in a job's
+// status message.
+const maxFailureSummaryTasks = 5
+
+// applyFailurePolicy runs after a task of the job failed permanently. The job
+// is failed once its failed-task share exceeds MaxFailedTasksPercent (with the
+// default of 0, any permanently failed task fails the job); otherwise it
+// carries on and may still complete. Failed validation tasks are not
+// counted. Caller must hold s.mu.
+func (s *OrchestratorServer) applyFailurePolicy(job *Job) {
+	if !job.isActive() {
+		return
+	}
+
+	failedPercent := 0.0
+	if job.TotalTasks > 0 {
+		failedPercent = float64(job.FailedTasks-job.failedValidations()) / float64(job.TotalTasks) * 100
+	}
+
+	// Training cannot run without the data its preprocessing prepares
+	if failedPercent > job.MaxFailedTasksPercent || job.preprocessFailed() {
+		job.mustTransition(JobStatusFailed, failureSummary(job))
+		job.dropReservations()
+		log.Printf("Job %s failed: %s", job.JobID, job.StatusMessage)
+		s.recordEvent(job.JobID, JobEvent{Type: EventJobFailed, Message: job.StatusMessage})
+		s.releaseDependents(context.Background(), job)
+		s.admitQueuedJobs()
+		return
+	}
+
+	s.completeJobIfSettled(job)
+}
+
+// completeJobIfSettled marks a running job COMPLETED once every task has
+// either completed or failed within the job's tolerance, and triggers the
+// model auto-save. Caller must hold s.mu.
+func (s *OrchestratorServer) completeJobIfSettled(job *Job) bool {
+	if !job.isActive() || job.CompletedTasks+job.FailedTasks < job.TotalTasks {
+		return false
+	}
+
+	reason := ""
+	if job.FailedTasks > 0 {
+		reason = failureSummary(job)
+	}
+	job.mustTransition(JobStatusCompleted, reason)
+	job.dropReservations()
+	log.Printf("Job %s completed!", job.JobID)
+	s.recordEvent(job.JobID, JobEvent{
+		Type:    EventJobCompleted,
+		Message: fmt.Sprintf("Completed %d/%d tasks", job.CompletedTasks, job.TotalTasks),
+	})
+	s.releaseDependents(context.Background(), job)
+	s.admitQueuedJobs()
+
+	// Trigger automatic model saving in background
+	go s.autoSaveModel(context.Background(), job.JobID, job)
+	return true
+}
+
+// failureSummary describes which tasks failed and why, e.g.
+// "3/100 tasks failed (limit 2.0%): task 1a2b3c4d (epoch 2): lease expired; ..."
+func failureSummary(job *Job) string {
+	var details []string
+	for _, task := range job.Tasks {
+		if task.Status != TaskStatusFailed {
+			continue
+		}
+		if len(details) == maxFailureSummaryTasks {
+			details = append(details, "...")
+			break
+		}
+		details = append(details, fmt.Sprintf("task %s (epoch %d): %s",
+			task.TaskID[:min(8, len(task.TaskID))], task.Epoch, task.LastError))
+	}
+
+	return fmt.Sprintf("%d/%d tasks failed (limit %.1f%%): %s",
+		job.FailedTasks, job.TotalTasks, job.MaxFailedTasksPercent, strings.Join(details, "; "))
+}
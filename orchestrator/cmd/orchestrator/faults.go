@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+)
+
+// Fault injection makes the orchestrator misbehave on purpose so CI and
+// staging exercise retries, lease expiry and worker re-registration. It is
+// off unless FAULT_INJECTION is "true" and must never be enabled in
+// production.
+var (
+	faultInjection       = os.Getenv("FAULT_INJECTION") == "true"
+	faultAssignFailRate  = getEnvFloat("FAULT_ASSIGN_FAIL_RATE", 0)
+	faultCompletionDelay = getEnvDuration("FAULT_COMPLETION_DELAY", 0)
+	faultWorkerDropRate  = getEnvFloat("FAULT_WORKER_DROP_RATE", 0)
+	faultSeed            = getEnvInt("FAULT_SEED", 0)
+)
+
+// FaultInjector decides which operations fail. A fixed FAULT_SEED replays
+// the same sequence of decisions for the same sequence of calls.
+type FaultInjector struct {
+	assignFailRate  float64       // Fraction of task assignments that fail at once
+	completionDelay time.Duration // Most a completion report is held back
+	workerDropRate  float64       // Chance per liveness check that a worker is dropped
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// newFaultInjector returns nil unless fault injection is enabled.
+func newFaultInjector() *FaultInjector {
+	if !faultInjection {
+		return nil
+	}
+	seed := int64(faultSeed)
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	f := &FaultInjector{
+		assignFailRate:  clampRate(faultAssignFailRate),
+		completionDelay: faultCompletionDelay,
+		workerDropRate:  clampRate(faultWorkerDropRate),
+		rng:             rand.New(rand.NewSource(seed)),
+	}
+	log.Printf("⚠️  Fault injection enabled (seed %d): %.0f%% of assignments fail, completions delayed up to %s, %.0f%% of workers dropped per check",
+		seed, f.assignFailRate*100, f.completionDelay, f.workerDropRate*100)
+	return f
+}
+
+func clampRate(rate float64) float64 {
+	if rate < 0 {
+		return 0
+	}
+	if rate > 1 {
+		return 1
+	}
+	return rate
+}
+
+func (f *FaultInjector) roll(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.rng.Float64() < rate
+}
+
+// failAssignment reports whether a task just leased should fail as if its
+// worker had rejected it.
+func (f *FaultInjector) failAssignment() bool {
+	return f != nil && f.roll(f.assignFailRate)
+}
+
+// dropWorker reports whether a worker should be dropped as if it had gone
+// silent.
+func (f *FaultInjector) dropWorker() bool {
+	return f != nil && f.roll(f.workerDropRate)
+}
+
+// delayCompletion holds a completion report back for a random time up to
+// the configured delay, or until ctx is done.
+func (f *FaultInjector) delayCompletion(ctx context.Context) {
+	if f == nil || f.completionDelay <= 0 {
+		return
+	}
+	f.mu.Lock()
+	delay := time.Duration(f.rng.Int63n(int64(f.completionDelay)))
+	f.mu.Unlock()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
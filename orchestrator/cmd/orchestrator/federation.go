@@ -0,0 +1,538 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	orchestratorpb "github.com/tensorfleet/orchestrator/proto/orchestrator"
+)
+
+// A federating orchestrator delegates whole jobs to regional orchestrators,
+// so a job can run in the region where its data lives. It keeps a DELEGATED
+// record of each such job, mirrors the region's progress into it and
+// finishes it when the region does. Cancel, pause and resume are forwarded
+// to the region; task-level RPCs should be made against the region itself.
+var (
+	// Regional orchestrators as "name=host:port,..."; federation is off
+	// when empty
+	federationRegions = os.Getenv("FEDERATION_REGIONS")
+
+	// Dataset path prefixes and the region holding them, as
+	// "prefix=region,..."; the longest matching prefix wins
+	federationDatasetRegions = os.Getenv("FEDERATION_DATASET_REGIONS")
+
+	// How often the progress of delegated jobs is pulled from their regions
+	federationSyncInterval = getEnvDuration("FEDERATION_SYNC_INTERVAL", 10*time.Second)
+)
+
+// LocalRegion names this orchestrator in job requests and dataset mappings.
+const LocalRegion = "local"
+
+const federationRPCTimeout = 5 * time.Second
+
+// Federation holds the connections to the regional orchestrators.
+type Federation struct {
+	regions  []string // In configuration order
+	clients  map[string]orchestratorpb.OrchestratorServiceClient
+	datasets []datasetRegion // Longest prefix first
+}
+
+type datasetRegion struct {
+	prefix string
+	region string
+}
+
+// newFederation connects to the configured regions. It returns nil when
+// federation is not configured.
+func newFederation() (*Federation, error) {
+	regions, err := parseFederationList("FEDERATION_REGIONS", federationRegions)
+	if err != nil || len(regions) == 0 {
+		return nil, err
+	}
+
+	f := &Federation{clients: make(map[string]orchestratorpb.OrchestratorServiceClient)}
+	for _, entry := range regions {
+		name, address := entry[0], entry[1]
+		if name == LocalRegion {
+			return nil, fmt.Errorf("FEDERATION_REGIONS: %q is reserved for this orchestrator", LocalRegion)
+		}
+		if _, dup := f.clients[name]; dup {
+			return nil, fmt.Errorf("FEDERATION_REGIONS: region %q listed twice", name)
+		}
+		conn, err := grpc.Dial(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to region %s: %v", name, err)
+		}
+		f.regions = append(f.regions, name)
+		f.clients[name] = orchestratorpb.NewOrchestratorServiceClient(conn)
+	}
+
+	datasets, err := parseFederationList("FEDERATION_DATASET_REGIONS", federationDatasetRegions)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range datasets {
+		prefix, region := entry[0], entry[1]
+		if _, ok := f.clients[region]; !ok && region != LocalRegion {
+			return nil, fmt.Errorf("FEDERATION_DATASET_REGIONS: unknown region %q", region)
+		}
+		f.datasets = append(f.datasets, datasetRegion{prefix: prefix, region: region})
+	}
+	sort.SliceStable(f.datasets, func(i, k int) bool { return len(f.datasets[i].prefix) > len(f.datasets[k].prefix) })
+
+	log.Printf("Federating jobs to regions %s", strings.Join(f.regions, ", "))
+	return f, nil
+}
+
+// parseFederationList splits "key=value,..." into pairs.
+func parseFederationList(name, raw string) ([][2]string, error) {
+	var out [][2]string
+	for _, item := range strings.Split(raw, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(item, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok || key == "" || value == "" {
+			return nil, fmt.Errorf("%s: malformed entry %q, want key=value", name, item)
+		}
+		out = append(out, [2]string{key, value})
+	}
+	return out, nil
+}
+
+// datasetRegion returns the region configured for the dataset's location.
+func (f *Federation) datasetRegion(datasetPath string) (string, bool) {
+	for _, d := range f.datasets {
+		if strings.HasPrefix(datasetPath, d.prefix) {
+			return d.region, true
+		}
+	}
+	return "", false
+}
+
+// freeTaskSlots is how many more tasks the region's serving workers can
+// run at once.
+func (f *Federation) freeTaskSlots(ctx context.Context, region string) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, federationRPCTimeout)
+	defer cancel()
+	resp, err := f.clients[region].GetWorkerActivity(ctx, &orchestratorpb.WorkerActivityRequest{})
+	if err != nil {
+		return 0, err
+	}
+	free := 0
+	for _, w := range resp.Workers {
+		free += freeSlots(w.Status, int(w.MaxConcurrentTasks), int(w.InFlightTasks))
+	}
+	return free, nil
+}
+
+// freeSlots is the spare task capacity of a worker in the given status.
+func freeSlots(status string, maxTasks, inFlight int) int {
+	if status != WorkerStatusIdle && status != WorkerStatusBusy || inFlight >= maxTasks {
+		return 0
+	}
+	return maxTasks - inFlight
+}
+
+// freeTaskSlots is freeSlots summed over this orchestrator's workers.
+// Caller must hold s.mu.
+func (s *OrchestratorServer) freeTaskSlots() int {
+	free := 0
+	for _, w := range s.workers {
+		free += freeSlots(w.displayStatus(), w.maxInFlight(), w.inFlight())
+	}
+	return free
+}
+
+// placeJob decides where a new job runs: "" for here, otherwise the region
+// to delegate it to. An explicit region wins, then the region holding the
+// dataset, then whichever of this orchestrator and the reachable regions
+// has the most free task slots. Jobs with dependencies run here, where
+// their dependencies are tracked.
+func (s *OrchestratorServer) placeJob(ctx context.Context, req *orchestratorpb.TrainingJobRequest) (string, error) {
+	f := s.federation
+	switch {
+	case req.Region == LocalRegion:
+		return "", nil
+	case req.Region != "":
+		if f == nil || f.clients[req.Region] == nil {
+			return "", fmt.Errorf("unknown region %q", req.Region)
+		}
+		if len(req.DependsOn) > 0 {
+			return "", fmt.Errorf("jobs with depends_on cannot be delegated to a region")
+		}
+		return req.Region, nil
+	case f == nil || len(req.DependsOn) > 0:
+		return "", nil
+	}
+
+	if region, ok := f.datasetRegion(req.DatasetPath); ok {
+		if region == LocalRegion {
+			return "", nil
+		}
+		return region, nil
+	}
+
+	free := make([]int, len(f.regions))
+	var wg sync.WaitGroup
+	for i, region := range f.regions {
+		wg.Add(1)
+		go func(i int, region string) {
+			defer wg.Done()
+			n, err := f.freeTaskSlots(ctx, region)
+			if err != nil {
+				log.Printf("Warning: Region %s unavailable for placement: %v", region, err)
+				n = -1
+			}
+			free[i] = n
+		}(i, region)
+	}
+	wg.Wait()
+
+	s.mu.RLock()
+	best, bestFree := "", s.freeTaskSlots()
+	s.mu.RUnlock()
+	for i, region := range f.regions {
+		if free[i] > bestFree {
+			best, bestFree = region, free[i]
+		}
+	}
+	return best, nil
+}
+
+// delegateJob submits a validated job to a region and keeps a DELEGATED
+// record of it here.
+func (s *OrchestratorServer) delegateJob(ctx context.Context, job *Job, req *orchestratorpb.TrainingJobRequest, region string) (*orchestratorpb.TrainingJobResponse, error) {
+	job.Region = region
+
+	s.mu.Lock()
+	if err := s.checkQuota(ctx, job.UserID); err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	if job.UserID != "" {
+		usage := s.userUsage(ctx, job.UserID)
+		usage.JobsSubmitted++
+		s.saveUsage(usage)
+	}
+	s.jobs[job.JobID] = job
+	if err := s.saveJob(ctx, job); err != nil {
+		log.Printf("Warning: Failed to save job: %v", err)
+	}
+	s.mu.Unlock()
+
+	// The region runs the job itself even if it federates further
+	forward := proto.Clone(req).(*orchestratorpb.TrainingJobRequest)
+	forward.Region = LocalRegion
+	rctx, cancel := context.WithTimeout(ctx, federationRPCTimeout)
+	resp, err := s.federation.clients[region].CreateTrainingJob(rctx, forward)
+	cancel()
+
+	s.mu.Lock()
+	if job.Status != JobStatusPending {
+		// Cancelled while being submitted
+		st := job.Status
+		s.mu.Unlock()
+		if err == nil {
+			s.forwardCancel(region, job.JobID)
+		}
+		return &orchestratorpb.TrainingJobResponse{
+			JobId:   job.JobID,
+			Status:  string(st),
+			Message: job.StatusMessage,
+		}, nil
+	}
+	if err != nil {
+		reason := fmt.Sprintf("Region %s rejected the job: %s", region, status.Convert(err).Message())
+		job.mustTransition(JobStatusFailed, reason)
+		s.recordEvent(job.JobID, JobEvent{Type: EventJobFailed, Message: reason})
+		s.releaseDependents(ctx, job)
+		if err := s.saveJob(ctx, job); err != nil {
+			log.Printf("Warning: Failed to save job: %v", err)
+		}
+		s.mu.Unlock()
+		log.Printf("Job %s could not be delegated: %s", job.JobID, reason)
+
+		return &orchestratorpb.TrainingJobResponse{
+			JobId:   job.JobID,
+			Status:  string(JobStatusFailed),
+			Message: reason,
+		}, nil
+	}
+
+	reason := fmt.Sprintf("Delegated to region %s", region)
+	job.mustTransition(JobStatusDelegated, reason)
+	job.TotalTasks = int(resp.NumTasks)
+	s.recordEvent(job.JobID, JobEvent{Type: EventJobDelegated, Message: reason})
+	s.mirrorRegionalStatus(ctx, job, &orchestratorpb.GetJobStatusResponse{
+		Status:     resp.Status,
+		Message:    resp.Message,
+		TotalTasks: resp.NumTasks,
+	})
+	st := job.Status
+	if err := s.saveJob(ctx, job); err != nil {
+		log.Printf("Warning: Failed to save job: %v", err)
+	}
+	s.mu.Unlock()
+	log.Printf("Delegated job %s to region %s", job.JobID, region)
+
+	return &orchestratorpb.TrainingJobResponse{
+		JobId:    job.JobID,
+		Status:   string(st),
+		NumTasks: resp.NumTasks,
+		Message:  fmt.Sprintf("%s: %s", reason, resp.Message),
+	}, nil
+}
+
+// regionalFinishEvents are the events of a delegated job finishing.
+var regionalFinishEvents = map[JobStatus]string{
+	JobStatusCompleted:      EventJobCompleted,
+	JobStatusCompletedEarly: EventJobCompletedEarly,
+	JobStatusFailed:         EventJobFailed,
+	JobStatusCancelled:      EventJobCancelled,
+}
+
+// mirrorRegionalStatus copies a region's view of a delegated job into its
+// record here, finishing it once the region has. It reports whether the
+// record changed. Caller must hold s.mu.
+func (s *OrchestratorServer) mirrorRegionalStatus(ctx context.Context, job *Job, resp *orchestratorpb.GetJobStatusResponse) bool {
+	if job.Status != JobStatusDelegated {
+		return false
+	}
+
+	changed := job.TotalTasks != int(resp.TotalTasks) || job.CompletedTasks != int(resp.CompletedTasks) ||
+		job.CurrentLoss != resp.CurrentLoss || job.CurrentAccuracy != resp.CurrentAccuracy
+	job.TotalTasks = int(resp.TotalTasks)
+	job.CompletedTasks = int(resp.CompletedTasks)
+	job.CurrentLoss = resp.CurrentLoss
+	job.CurrentAccuracy = resp.CurrentAccuracy
+
+	regional := JobStatus(resp.Status)
+	if event, finished := regionalFinishEvents[regional]; finished {
+		reason := fmt.Sprintf("%s in region %s", regional, job.Region)
+		if resp.Message != "" {
+			reason = fmt.Sprintf("%s: %s", reason, resp.Message)
+		}
+		job.mustTransition(regional, reason)
+		s.recordEvent(job.JobID, JobEvent{Type: event, Message: reason})
+		s.releaseDependents(ctx, job)
+		return true
+	}
+
+	message := fmt.Sprintf("%s in region %s", regional, job.Region)
+	if message != job.StatusMessage {
+		job.StatusMessage = message
+		changed = true
+	}
+	if changed {
+		job.UpdatedAt = time.Now()
+	}
+	return changed
+}
+
+// delegatedRegion returns the region a job was delegated to, or "" if the
+// job runs here or does not exist.
+func (s *OrchestratorServer) delegatedRegion(ctx context.Context, jobID string) string {
+	if s.federation == nil {
+		return ""
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, err := s.lookupJob(ctx, jobID)
+	if err != nil {
+		return ""
+	}
+	return job.Region
+}
+
+// regionalJobStatus asks the job's region for its status and mirrors it
+// here. It returns false if the region could not answer, in which case the
+// record here is the best there is.
+func (s *OrchestratorServer) regionalJobStatus(ctx context.Context, jobID, region string) (*orchestratorpb.GetJobStatusResponse, bool) {
+	rctx, cancel := context.WithTimeout(ctx, federationRPCTimeout)
+	resp, err := s.federation.clients[region].GetJobStatus(rctx, &orchestratorpb.GetJobStatusRequest{JobId: jobID})
+	cancel()
+	if err != nil {
+		log.Printf("Warning: Failed to get status of job %s from region %s: %v", jobID, region, err)
+		return nil, false
+	}
+	resp.Region = region
+	s.applyRegionalStatus(ctx, jobID, resp)
+	return resp, true
+}
+
+// applyRegionalStatus mirrors a region's status into the job's record and
+// saves it if it changed.
+func (s *OrchestratorServer) applyRegionalStatus(ctx context.Context, jobID string, resp *orchestratorpb.GetJobStatusResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[jobID]
+	if ok && s.mirrorRegionalStatus(ctx, job, resp) {
+		if err := s.saveJob(ctx, job); err != nil {
+			log.Printf("Warning: Failed to save job: %v", err)
+		}
+	}
+}
+
+// syncDelegatedJobs keeps the records of delegated jobs up to date while
+// this orchestrator leads.
+func (s *OrchestratorServer) syncDelegatedJobs(ctx context.Context, leading func() bool) {
+	if s.federation == nil {
+		return
+	}
+	ticker := time.NewTicker(federationSyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if leading() {
+				s.syncDelegatedOnce(ctx)
+			}
+		}
+	}
+}
+
+func (s *OrchestratorServer) syncDelegatedOnce(ctx context.Context) {
+	delegated := make(map[string]string)
+	s.mu.RLock()
+	for id, job := range s.jobs {
+		if job.Status == JobStatusDelegated {
+			delegated[id] = job.Region
+		}
+	}
+	s.mu.RUnlock()
+
+	for jobID, region := range delegated {
+		client, ok := s.federation.clients[region]
+		if !ok {
+			s.failDelegatedJob(ctx, jobID, fmt.Sprintf("Region %s is no longer configured", region))
+			continue
+		}
+		rctx, cancel := context.WithTimeout(ctx, federationRPCTimeout)
+		resp, err := client.GetJobStatus(rctx, &orchestratorpb.GetJobStatusRequest{JobId: jobID})
+		cancel()
+		switch {
+		case err == nil:
+			s.applyRegionalStatus(ctx, jobID, resp)
+		case status.Code(err) == codes.NotFound:
+			s.failDelegatedJob(ctx, jobID, fmt.Sprintf("Region %s no longer knows the job", region))
+		default:
+			log.Printf("Warning: Failed to sync job %s from region %s: %v", jobID, region, err)
+		}
+	}
+}
+
+// failDelegatedJob gives up on a delegated job whose region lost track of it.
+func (s *OrchestratorServer) failDelegatedJob(ctx context.Context, jobID, reason string) {
+	s.mu.Lock()
+	job, ok := s.jobs[jobID]
+	if !ok || job.Status != JobStatusDelegated {
+		s.mu.Unlock()
+		return
+	}
+	job.mustTransition(JobStatusFailed, reason)
+	s.recordEvent(job.JobID, JobEvent{Type: EventJobFailed, Message: reason})
+	s.releaseDependents(ctx, job)
+	if err := s.saveJob(ctx, job); err != nil {
+		log.Printf("Warning: Failed to save job: %v", err)
+	}
+	s.mu.Unlock()
+	log.Printf("Job %s failed: %s", jobID, reason)
+}
+
+// cancelDelegatedJob cancels a job in its region and, if that worked,
+// here too.
+func (s *OrchestratorServer) cancelDelegatedJob(ctx context.Context, jobID, region string) (*orchestratorpb.CancelJobResponse, error) {
+	rctx, cancel := context.WithTimeout(ctx, federationRPCTimeout)
+	resp, err := s.federation.clients[region].CancelJob(rctx, &orchestratorpb.CancelJobRequest{JobId: jobID})
+	cancel()
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "region %s: %s", region, status.Convert(err).Message())
+	}
+	if !resp.Success {
+		return resp, nil
+	}
+
+	s.mu.Lock()
+	job, ok := s.jobs[jobID]
+	if ok && job.Status == JobStatusDelegated {
+		s.cancelJobLocked(job, "Cancelled by user")
+	}
+	if ok {
+		if err := s.saveJob(ctx, job); err != nil {
+			log.Printf("Warning: Failed to save job: %v", err)
+		}
+	}
+	s.mu.Unlock()
+	log.Printf("Job %s cancelled in region %s", jobID, region)
+	return resp, nil
+}
+
+// forwardCancel cancels a job in a region in the background.
+func (s *OrchestratorServer) forwardCancel(region, jobID string) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), federationRPCTimeout)
+		defer cancel()
+		if _, err := s.federation.clients[region].CancelJob(ctx, &orchestratorpb.CancelJobRequest{JobId: jobID}); err != nil {
+			log.Printf("Warning: Failed to cancel job %s in region %s: %v", jobID, region, err)
+		}
+	}()
+}
+
+// pauseDelegatedJob pauses a job in its region. Its record here stays
+// DELEGATED; the region's status shows up at the next sync.
+func (s *OrchestratorServer) pauseDelegatedJob(ctx context.Context, jobID, region string) (*orchestratorpb.PauseJobResponse, error) {
+	rctx, cancel := context.WithTimeout(ctx, federationRPCTimeout)
+	defer cancel()
+	resp, err := s.federation.clients[region].PauseJob(rctx, &orchestratorpb.PauseJobRequest{JobId: jobID})
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "region %s: %s", region, status.Convert(err).Message())
+	}
+	return resp, nil
+}
+
+// resumeDelegatedJob resumes a job in its region, reopening its record here
+// if it had finished.
+func (s *OrchestratorServer) resumeDelegatedJob(ctx context.Context, jobID, region string) (*orchestratorpb.ResumeJobResponse, error) {
+	rctx, cancel := context.WithTimeout(ctx, federationRPCTimeout)
+	resp, err := s.federation.clients[region].ResumeJob(rctx, &orchestratorpb.ResumeJobRequest{JobId: jobID})
+	cancel()
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "region %s: %s", region, status.Convert(err).Message())
+	}
+	if !resp.Success {
+		return resp, nil
+	}
+
+	s.mu.Lock()
+	job, err := s.lookupJob(ctx, jobID)
+	reopened := err == nil && job.Status.IsTerminal() &&
+		job.mustTransition(JobStatusDelegated, fmt.Sprintf("Resumed in region %s", region))
+	if reopened {
+		s.jobs[jobID] = job
+		s.recordEvent(job.JobID, JobEvent{Type: EventJobResumed, Message: job.StatusMessage})
+		if err := s.saveJob(ctx, job); err != nil {
+			log.Printf("Warning: Failed to save job: %v", err)
+		}
+	}
+	s.mu.Unlock()
+	return resp, nil
+}
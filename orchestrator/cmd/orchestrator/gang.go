@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Gang scheduling starts a synchronous job only when all NumWorkers workers
+// it needs are free at the same time, instead of letting it begin on the
+// first worker and stall at its first epoch barrier waiting for the rest.
+// The gang is reserved when the job is admitted and released when it
+// finishes or is preempted. A gang member that goes offline is replaced by
+// the next capable worker that asks for work.
+//
+// Workers on spot or preemptible instances carry the preemptibleLabel. A
+// synchronous gang stalls at its barrier whenever a member is reclaimed, so
+// it is not started on preemptible workers alone while a capable worker
+// that is not preemptible exists; those are picked first.
+
+// preemptibleLabel is the label of workers that may be reclaimed at short
+// notice, set from WORKER_PREEMPTIBLE on the worker.
+const preemptibleLabel = "preemptible"
+
+// preemptible reports whether the worker runs on a preemptible instance.
+func (c WorkerCapabilities) preemptible() bool {
+	return c.Labels[preemptibleLabel] == "true"
+}
+
+// freeGangWorkers returns the IDs of the workers that could join the job's
+// gang right now: live, capable, idle and not held by another gang.
+// Caller must hold s.mu.
+func (s *OrchestratorServer) freeGangWorkers(job *Job) []string {
+	var free []string
+	for _, worker := range s.workers {
+		if !worker.schedulable() || worker.Status != WorkerStatusIdle || worker.inFlight() > 0 {
+			continue
+		}
+		if !job.Requirements.SatisfiedBy(worker.Capabilities) {
+			continue
+		}
+		if holder := s.gangHolder(worker.WorkerID); holder != nil && holder != job {
+			continue
+		}
+		free = append(free, worker.WorkerID)
+	}
+	// Workers that are not preemptible first
+	sort.Slice(free, func(a, b int) bool {
+		pa, pb := s.workers[free[a]].Capabilities.preemptible(), s.workers[free[b]].Capabilities.preemptible()
+		if pa != pb {
+			return pb
+		}
+		return free[a] < free[b]
+	})
+	return free
+}
+
+// onDemandWorkerExists reports whether a live worker that is not
+// preemptible could serve the job, busy or not. Caller must hold s.mu.
+func (s *OrchestratorServer) onDemandWorkerExists(job *Job) bool {
+	for _, worker := range s.workers {
+		if worker.schedulable() && !worker.Capabilities.preemptible() && job.Requirements.SatisfiedBy(worker.Capabilities) {
+			return true
+		}
+	}
+	return false
+}
+
+// gangHolder returns the unfinished gang job holding the worker, or nil.
+// Caller must hold s.mu.
+func (s *OrchestratorServer) gangHolder(workerID string) *Job {
+	for _, job := range s.jobs {
+		if !job.GangScheduling || job.Status.IsTerminal() {
+			continue
+		}
+		if r, ok := job.Reservations[workerID]; ok && r.Gang {
+			return job
+		}
+	}
+	return nil
+}
+
+// gangAvailable reports whether enough workers are free to start the gang
+// job, noting in its status message what it waits for when not.
+// Caller must hold s.mu.
+func (s *OrchestratorServer) gangAvailable(job *Job) bool {
+	free := s.freeGangWorkers(job)
+	if len(free) < int(job.NumWorkers) {
+		job.StatusMessage = fmt.Sprintf("Waiting for %d workers to be free together (%d free)", job.NumWorkers, len(free))
+		return false
+	}
+	// Free workers are sorted with preemptible ones last
+	if job.SyncEpochs && s.workers[free[0]].Capabilities.preemptible() && s.onDemandWorkerExists(job) {
+		job.StatusMessage = "Waiting for a worker that is not preemptible to join the gang"
+		return false
+	}
+	return true
+}
+
+// reserveGang reserves NumWorkers free workers for the job at once.
+// Caller must hold s.mu after checking gangAvailable.
+func (s *OrchestratorServer) reserveGang(job *Job) {
+	free := s.freeGangWorkers(job)
+	if len(free) > int(job.NumWorkers) {
+		free = free[:job.NumWorkers]
+	}
+
+	now := time.Now()
+	job.Reservations = make(map[string]*WorkerReservation, len(free))
+	for _, workerID := range free {
+		job.Reservations[workerID] = &WorkerReservation{LastUsed: now, Gang: true}
+	}
+
+	message := fmt.Sprintf("Reserved workers %s", strings.Join(free, ", "))
+	log.Printf("Job %s: %s", job.JobID, message)
+	s.recordEvent(job.JobID, JobEvent{Type: EventGangReserved, Message: message})
+}
+
+// admitWaitingGang retries admission when a gang job heads the admission
+// queue, e.g. after a worker became free. Caller must hold s.mu.
+func (s *OrchestratorServer) admitWaitingGang() {
+	if len(s.admissionQueue) > 0 && s.admissionQueue[0].GangScheduling {
+		s.admitQueuedJobs()
+	}
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"strings"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	orchestratorpb "github.com/tensorfleet/orchestrator/proto/orchestrator"
+)
+
+// setServingStatus reports the status for the server as a whole and for the
+// orchestrator service, the two names probes and clients ask about.
+func setServingStatus(hs *health.Server, status healthpb.HealthCheckResponse_ServingStatus) {
+	hs.SetServingStatus("", status)
+	hs.SetServingStatus(orchestratorpb.OrchestratorService_ServiceDesc.ServiceName, status)
+}
+
+func isHealthMethod(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, "/"+healthpb.Health_ServiceDesc.ServiceName+"/")
+}
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"log"
+	"net"
+	"os"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	orchestratorpb "github.com/tensorfleet/orchestrator/proto/orchestrator"
+)
+
+// Every RPC passes through panic recovery, request logging, per-peer rate
+// limiting and, for the RPCs workers make, token authentication, in that
+// order, before reaching the leader check and the handler.
+var (
+	// Shared secret workers present as "authorization: Bearer <token>";
+	// worker RPCs are unauthenticated when it is empty
+	workerAuthToken = os.Getenv("WORKER_AUTH_TOKEN")
+
+	// "all" logs every RPC, "errors" (default) only failed ones, "off" none
+	grpcRequestLog = os.Getenv("GRPC_REQUEST_LOG")
+
+	// Requests per second each peer address may make (0 disables), and how
+	// many it may make in a burst (default twice the rate)
+	grpcRateLimit = getEnvFloat("GRPC_RATE_LIMIT", 0)
+	grpcRateBurst = getEnvInt("GRPC_RATE_BURST", 0)
+)
+
+const authMetadataKey = "authorization"
+
+// workerMethods are the RPCs workers make, which require WORKER_AUTH_TOKEN.
+var workerMethods = map[string]bool{
+	orchestratorpb.OrchestratorService_RegisterWorker_FullMethodName:       true,
+	orchestratorpb.OrchestratorService_DeregisterWorker_FullMethodName:     true,
+	orchestratorpb.OrchestratorService_Heartbeat_FullMethodName:            true,
+	orchestratorpb.OrchestratorService_AssignTask_FullMethodName:           true,
+	orchestratorpb.OrchestratorService_StreamTasks_FullMethodName:          true,
+	orchestratorpb.OrchestratorService_ReportTaskCompletion_FullMethodName: true,
+	orchestratorpb.OrchestratorService_ReleaseTask_FullMethodName:          true,
+	orchestratorpb.OrchestratorService_ReportTaskProgress_FullMethodName:   true,
+	orchestratorpb.OrchestratorService_ShipTaskLogs_FullMethodName:         true,
+	orchestratorpb.OrchestratorService_UpdateJobMetrics_FullMethodName:     true,
+}
+
+// serverInterceptors returns the orchestrator's interceptor chain as server
+// options.
+func serverInterceptors() []grpc.ServerOption {
+	limiter := newPeerRateLimiter(grpcRateLimit, grpcRateBurst)
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(
+			recoveryUnaryInterceptor,
+			loggingUnaryInterceptor,
+			limiter.UnaryInterceptor,
+			authUnaryInterceptor,
+		),
+		grpc.ChainStreamInterceptor(
+			recoveryStreamInterceptor,
+			loggingStreamInterceptor,
+			limiter.StreamInterceptor,
+			authStreamInterceptor,
+		),
+	}
+}
+
+// recoveryUnaryInterceptor turns a handler panic into an INTERNAL error
+// instead of crashing the orchestrator.
+func recoveryUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = recovered(info.FullMethod, r)
+		}
+	}()
+	return handler(ctx, req)
+}
+
+func recoveryStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = recovered(info.FullMethod, r)
+		}
+	}()
+	return handler(srv, ss)
+}
+
+func recovered(method string, r interface{}) error {
+	log.Printf("Error: panic in %s: %v\n%s", method, r, debug.Stack())
+	return status.Errorf(codes.Internal, "internal error in %s", method)
+}
+
+func loggingUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	logRequest(ctx, info.FullMethod, start, err)
+	return resp, err
+}
+
+func loggingStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	start := time.Now()
+	err := handler(srv, ss)
+	logRequest(ss.Context(), info.FullMethod, start, err)
+	return err
+}
+
+// logRequest writes one key=value line per RPC, as GRPC_REQUEST_LOG allows.
+func logRequest(ctx context.Context, method string, start time.Time, err error) {
+	code := status.Code(err)
+	switch grpcRequestLog {
+	case "off":
+		return
+	case "all":
+	default:
+		if code == codes.OK {
+			return
+		}
+	}
+
+	line := "grpc method=" + method + " peer=" + peerHost(ctx) + " code=" + code.String() +
+		" duration=" + time.Since(start).Round(time.Microsecond).String()
+	if err != nil {
+		line += " error=" + quoteLogValue(status.Convert(err).Message())
+	}
+	log.Print(line)
+}
+
+func quoteLogValue(v string) string {
+	return `"` + strings.ReplaceAll(v, `"`, `\"`) + `"`
+}
+
+// peerHost is the IP address the RPC came from, or "unknown".
+func peerHost(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return "unknown"
+	}
+	addr := p.Addr.String()
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
+
+func authUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := authorize(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func authStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := authorize(ss.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+// authorize checks the bearer token of worker RPCs against WORKER_AUTH_TOKEN.
+func authorize(ctx context.Context, method string) error {
+	if workerAuthToken == "" || !workerMethods[method] {
+		return nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get(authMetadataKey) {
+		token := strings.TrimPrefix(value, "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(workerAuthToken)) == 1 {
+			return nil
+		}
+	}
+	return status.Errorf(codes.Unauthenticated, "missing or invalid worker token")
+}
+
+// withWorkerAuth adds WORKER_AUTH_TOKEN to an outgoing call to a worker.
+func withWorkerAuth(ctx context.Context) context.Context {
+	if workerAuthToken == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, authMetadataKey, "Bearer "+workerAuthToken)
+}
+
+// peerRateLimiter keeps a token bucket per peer address. Buckets of peers
+// that have been quiet for a while are dropped. A nil limiter allows
+// everything.
+type peerRateLimiter struct {
+	rate  float64
+	burst float64
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastPrune time.Time
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+const rateLimiterIdle = 10 * time.Minute
+
+func newPeerRateLimiter(rate float64, burst int) *peerRateLimiter {
+	if rate <= 0 {
+		return nil
+	}
+	b := float64(burst)
+	if b <= 0 {
+		b = 2 * rate
+	}
+	if b < 1 {
+		b = 1
+	}
+	return &peerRateLimiter{rate: rate, burst: b, buckets: make(map[string]*tokenBucket)}
+}
+
+// allow takes a token from the peer's bucket if one is left.
+func (l *peerRateLimiter) allow(peerAddr string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastPrune) > rateLimiterIdle {
+		for addr, b := range l.buckets {
+			if now.Sub(b.last) > rateLimiterIdle {
+				delete(l.buckets, addr)
+			}
+		}
+		l.lastPrune = now
+	}
+
+	b, ok := l.buckets[peerAddr]
+	if !ok {
+		b = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[peerAddr] = b
+	}
+	b.tokens += now.Sub(b.last).Seconds() * l.rate
+	if b.tokens > l.burst {
+		b.tokens = l.burst
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func (l *peerRateLimiter) check(ctx context.Context, method string) error {
+	if l == nil || isHealthMethod(method) {
+		return nil
+	}
+	if !l.allow(peerHost(ctx), time.Now()) {
+		return status.Errorf(codes.ResourceExhausted, "rate limit of %g requests per second exceeded", l.rate)
+	}
+	return nil
+}
+
+func (l *peerRateLimiter) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := l.check(ctx, info.FullMethod); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// StreamInterceptor limits opening streams; messages on an open stream are
+// not counted.
+func (l *peerRateLimiter) StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := l.check(ss.Context(), info.FullMethod); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
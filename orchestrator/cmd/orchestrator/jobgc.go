@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Finished jobs are dropped from memory finishedJobTTL after their last
+// update; reads fall back to the job store from then on.
+var (
+	finishedJobTTL = getEnvDuration("FINISHED_JOB_TTL", time.Hour)
+	jobGCInterval  = getEnvDuration("JOB_GC_INTERVAL", 5*time.Minute)
+)
+
+// collectFinishedJobs periodically evicts finished jobs from memory.
+func (s *OrchestratorServer) collectFinishedJobs(ctx context.Context) {
+	ticker := time.NewTicker(jobGCInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.evictFinishedJobs(ctx)
+		}
+	}
+}
+
+// evictFinishedJobs removes jobs that finished more than finishedJobTTL ago,
+// together with their tasks. Each is saved first so the store has its final
+// state; a job that cannot be saved stays in memory until the next run.
+// Task queue tombstones older than finishedJobTTL are swept too, whether or
+// not their jobs are evicted.
+func (s *OrchestratorServer) evictFinishedJobs(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cutoff := time.Now().Add(-finishedJobTTL)
+	s.taskQueue.SweepTombstones(cutoff)
+	evicted := 0
+	for jobID, job := range s.jobs {
+		if !job.Status.IsTerminal() || job.UpdatedAt.After(cutoff) {
+			continue
+		}
+		if err := s.store.SaveJob(ctx, job); err != nil {
+			log.Printf("Warning: Keeping finished job %s in memory, failed to save it: %v", jobID, err)
+			continue
+		}
+		delete(s.jobs, jobID)
+		s.taskQueue.ClearTombstone(jobID)
+		evicted++
+	}
+
+	// Cancelled jobs may still sit in the admission queue
+	kept := s.admissionQueue[:0]
+	for _, job := range s.admissionQueue {
+		if _, ok := s.jobs[job.JobID]; ok {
+			kept = append(kept, job)
+		}
+	}
+	for i := len(kept); i < len(s.admissionQueue); i++ {
+		s.admissionQueue[i] = nil
+	}
+	s.admissionQueue = kept
+
+	if evicted > 0 {
+		log.Printf("Evicted %d finished jobs from memory, %d remain", evicted, len(s.jobs))
+	}
+}
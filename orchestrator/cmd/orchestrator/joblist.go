@@ -0,0 +1,356 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	orchestratorpb "github.com/tensorfleet/orchestrator/proto/orchestrator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultJobPageSize  = 50
+	maxJobPageSize      = 500
+	defaultTaskPageSize = 100
+	maxTaskPageSize     = 1000
+)
+
+func jobStatusFromProto(state orchestratorpb.JobState) (JobStatus, bool) {
+	for st, p := range jobStatusProto {
+		if p == state {
+			return st, true
+		}
+	}
+	return "", false
+}
+
+func unixMillis(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+// jobInfo describes a job; detailed adds its history and current
+// allocation. Caller must hold s.mu.
+func (s *OrchestratorServer) jobInfo(job *Job, detailed bool) *orchestratorpb.JobInfo {
+	progress := int32(0)
+	if job.TotalTasks > 0 {
+		progress = int32(float64(job.CompletedTasks) / float64(job.TotalTasks) * 100)
+	}
+
+	info := &orchestratorpb.JobInfo{
+		JobId:           job.JobID,
+		UserId:          job.UserID,
+		Namespace:       job.namespace(),
+		Region:          job.Region,
+		ModelType:       job.ModelType,
+		DatasetPath:     job.DatasetPath,
+		State:           job.Status.toProto(),
+		Status:          string(job.Status),
+		StatusMessage:   job.StatusMessage,
+		Priority:        job.Priority,
+		Epochs:          job.Epochs,
+		CurrentEpoch:    job.CurrentEpoch,
+		NumWorkers:      job.NumWorkers,
+		TotalTasks:      int32(job.TotalTasks),
+		CompletedTasks:  int32(job.CompletedTasks),
+		FailedTasks:     int32(job.FailedTasks),
+		Progress:        progress,
+		CurrentLoss:     job.CurrentLoss,
+		CurrentAccuracy: job.CurrentAccuracy,
+		CreatedAtMs:     unixMillis(job.CreatedAt),
+		UpdatedAtMs:     unixMillis(job.UpdatedAt),
+		StartedAtMs:     unixMillis(job.StartedAt),
+	}
+	info.MaxParallelTasks = job.MaxParallelTasks
+	info.Image = job.Image
+	if detailed {
+		info.Hyperparameters = job.Hyperparameters
+		info.DependsOn = job.DependsOn
+		info.EpochMetrics = job.epochMetricsProto()
+		info.Transitions = job.transitionsProto()
+		info.AllocatedWorkers = job.activeReservations(time.Now())
+		info.QueuePosition = int32(s.queuePosition(job.JobID))
+		info.TaskDurations = job.TaskDurations.stats().toProto()
+		info.EstimatedRemainingMs = job.estimatedRemaining(time.Now()).Milliseconds()
+	}
+	return info
+}
+
+// GetJob returns the full record of a job, from memory or the job store.
+func (s *OrchestratorServer) GetJob(ctx context.Context, req *orchestratorpb.GetJobRequest) (*orchestratorpb.GetJobResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, err := s.lookupJob(ctx, req.JobId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "job not found: %s", req.JobId)
+	}
+	return &orchestratorpb.GetJobResponse{Job: s.jobInfo(job, true)}, nil
+}
+
+// ListJobs returns the jobs matching the request's filters, newest first.
+// Jobs in memory take precedence over their stored records, which may lag
+// behind.
+func (s *OrchestratorServer) ListJobs(ctx context.Context, req *orchestratorpb.ListJobsRequest) (*orchestratorpb.ListJobsResponse, error) {
+	resp := &orchestratorpb.ListJobsResponse{}
+	next, total, err := s.listJobs(ctx, req, func(job *Job) {
+		resp.Jobs = append(resp.Jobs, s.jobInfo(job, false))
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp.NextPageToken = next
+	resp.Total = int32(total)
+	return resp, nil
+}
+
+// listJobs calls visit with s.mu held for each job on the requested page and
+// returns the next page's token and the number of matching jobs.
+func (s *OrchestratorServer) listJobs(ctx context.Context, req *orchestratorpb.ListJobsRequest, visit func(*Job)) (string, int, error) {
+	filter := JobFilter{UserID: req.UserId, Namespace: req.Namespace, ModelType: req.ModelType}
+	for _, state := range req.States {
+		st, ok := jobStatusFromProto(state)
+		if !ok {
+			return "", 0, status.Errorf(codes.InvalidArgument, "unknown job state: %s", state)
+		}
+		filter.Statuses = append(filter.Statuses, st)
+	}
+	pageSize := int(req.PageSize)
+	if pageSize <= 0 {
+		pageSize = defaultJobPageSize
+	}
+	if pageSize > maxJobPageSize {
+		pageSize = maxJobPageSize
+	}
+	after, err := decodeJobCursor(req.PageToken)
+	if err != nil {
+		return "", 0, status.Errorf(codes.InvalidArgument, "invalid page_token")
+	}
+
+	// The store skips the jobs in memory and reads only its part of the
+	// page; one more job than fits tells whether another page follows
+	s.mu.RLock()
+	inMemory := make(map[string]bool, len(s.jobs))
+	for jobID := range s.jobs {
+		inMemory[jobID] = true
+	}
+	s.mu.RUnlock()
+
+	stored, total, err := s.store.ListJobs(ctx, filter, JobPage{After: after, Limit: pageSize + 1, Skip: inMemory})
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to list jobs: %v", err)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var jobs []*Job
+	for _, job := range stored {
+		if _, ok := s.jobs[job.JobID]; ok {
+			// Loaded since the store was read; listed from memory below
+			total--
+			continue
+		}
+		jobs = append(jobs, job)
+	}
+	// A job loaded since the store was read and stored off the page is
+	// counted twice; the total is a hint
+	for _, job := range s.jobs {
+		if filter.matches(job) {
+			total++
+			jobs = append(jobs, job)
+		}
+	}
+	sortJobs(jobs)
+
+	page, more := JobPage{After: after, Limit: pageSize}.of(jobs)
+	for _, job := range page {
+		visit(job)
+	}
+	next := ""
+	if more {
+		next = jobCursorOf(page[len(page)-1]).encode()
+	}
+	return next, total, nil
+}
+
+func taskInfo(task *Task, now time.Time) *orchestratorpb.TaskInfo {
+	info := &orchestratorpb.TaskInfo{
+		TaskId:           task.TaskID,
+		Type:             task.taskType(),
+		Status:           task.Status,
+		WorkerId:         task.WorkerID,
+		Epoch:            task.Epoch,
+		BatchStart:       task.BatchStart,
+		BatchEnd:         task.BatchEnd,
+		Loss:             task.Loss,
+		Accuracy:         task.Accuracy,
+		Attempts:         int32(task.Attempts),
+		LastError:        task.LastError,
+		CreatedAtMs:      unixMillis(task.CreatedAt),
+		LeaseExpiresAtMs: unixMillis(task.LeaseExpiresAt),
+		Straggling:       task.Straggling && task.Status == TaskStatusAssigned,
+		ResourceUsage:    task.ResourceUsage.toProto(),
+	}
+	if task.Status == TaskStatusAssigned && task.Progress != nil {
+		info.Progress = task.Progress.toProto(task)
+	}
+	if task.CompletedAt != nil {
+		info.CompletedAtMs = unixMillis(*task.CompletedAt)
+	}
+
+	queuedAt := task.QueuedAt
+	if queuedAt.IsZero() {
+		queuedAt = task.CreatedAt
+	}
+	if task.AssignedAt == nil {
+		if task.Status == TaskStatusPending {
+			info.WaitMs = now.Sub(queuedAt).Milliseconds()
+		}
+		return info
+	}
+	info.AssignedAtMs = unixMillis(*task.AssignedAt)
+	if task.AssignedAt.After(queuedAt) {
+		info.WaitMs = task.AssignedAt.Sub(queuedAt).Milliseconds()
+	}
+	switch {
+	case task.CompletedAt != nil:
+		info.RunMs = task.CompletedAt.Sub(*task.AssignedAt).Milliseconds()
+	case task.Status == TaskStatusAssigned:
+		info.RunMs = now.Sub(*task.AssignedAt).Milliseconds()
+	}
+	return info
+}
+
+// GetJobTasks returns a page of the job's tasks in the order they were
+// created, i.e. by epoch and then batch.
+func (s *OrchestratorServer) GetJobTasks(ctx context.Context, req *orchestratorpb.GetJobTasksRequest) (*orchestratorpb.GetJobTasksResponse, error) {
+	pageSize := int(req.PageSize)
+	if pageSize <= 0 {
+		pageSize = defaultTaskPageSize
+	}
+	if pageSize > maxTaskPageSize {
+		pageSize = maxTaskPageSize
+	}
+	after := ""
+	if req.PageToken != "" {
+		raw, err := base64.RawURLEncoding.DecodeString(req.PageToken)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page_token")
+		}
+		after = string(raw)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, err := s.lookupJob(ctx, req.JobId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "job not found: %s", req.JobId)
+	}
+
+	// The page starts after the token's task even if that task no longer
+	// matches the filters
+	var tasks []*Task
+	start, found := 0, after == ""
+	for _, task := range job.Tasks {
+		matches := (req.Status == "" || task.Status == req.Status) &&
+			(req.Epoch == 0 || task.Epoch == req.Epoch) &&
+			(req.WorkerId == "" || task.WorkerID == req.WorkerId)
+		if matches {
+			tasks = append(tasks, task)
+		}
+		if task.TaskID == after {
+			start, found = len(tasks), true
+		}
+	}
+	if !found {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid page_token")
+	}
+	end := start + pageSize
+	if end > len(tasks) {
+		end = len(tasks)
+	}
+
+	now := time.Now()
+	resp := &orchestratorpb.GetJobTasksResponse{
+		JobId:     job.JobID,
+		Tasks:     make([]*orchestratorpb.TaskInfo, 0, end-start),
+		Total:     int32(len(tasks)),
+		Durations: job.TaskDurations.stats().toProto(),
+	}
+	for _, task := range tasks[start:end] {
+		resp.Tasks = append(resp.Tasks, taskInfo(task, now))
+	}
+	if end < len(tasks) {
+		resp.NextPageToken = base64.RawURLEncoding.EncodeToString([]byte(tasks[end-1].TaskID))
+	}
+	return resp, nil
+}
+
+// jobCursor is a position in the newest-first job order; page tokens encode
+// the last job of a page.
+type jobCursor struct {
+	createdAt int64 // Unix microseconds, the precision the job stores keep
+	jobID     string
+}
+
+func jobCursorOf(job *Job) jobCursor {
+	return jobCursor{createdAt: job.CreatedAt.UnixMicro(), jobID: job.JobID}
+}
+
+// sortJobs sorts jobs newest first.
+func sortJobs(jobs []*Job) {
+	sort.Slice(jobs, func(i, k int) bool { return jobCursorOf(jobs[i]).before(jobCursorOf(jobs[k])) })
+}
+
+// of returns the jobs on the page out of jobs sorted newest first, and
+// whether more follow it.
+func (p JobPage) of(jobs []*Job) ([]*Job, bool) {
+	if p.After != nil {
+		jobs = jobs[sort.Search(len(jobs), func(i int) bool { return p.After.before(jobCursorOf(jobs[i])) }):]
+	}
+	if p.Limit > 0 && len(jobs) > p.Limit {
+		return jobs[:p.Limit], true
+	}
+	return jobs, false
+}
+
+// before reports whether c sorts ahead of other: newer first, then by ID.
+func (c jobCursor) before(other jobCursor) bool {
+	if c.createdAt != other.createdAt {
+		return c.createdAt > other.createdAt
+	}
+	return c.jobID < other.jobID
+}
+
+func (c jobCursor) encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(c.createdAt, 10) + ":" + c.jobID))
+}
+
+func decodeJobCursor(token string) (*jobCursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, err
+	}
+	createdAt, jobID, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return nil, fmt.Errorf("malformed cursor")
+	}
+	micros, err := strconv.ParseInt(createdAt, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &jobCursor{createdAt: micros, jobID: jobID}, nil
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	orchestratorpb "github.com/tensorfleet/orchestrator/proto/orchestrator"
+)
+
+// JobStatus is the lifecycle state of a job. Jobs move between states only
+// through Job.transition, which enforces jobTransitions.
+type JobStatus string
+
+const (
+	JobStatusPending          JobStatus = "PENDING"
+	JobStatusSharding         JobStatus = "SHARDING"          // Resolving the dataset and creating tasks
+	JobStatusBlocked          JobStatus = "BLOCKED"           // Waiting for the jobs it depends on
+	JobStatusQueued           JobStatus = "QUEUED"            // Waiting for cluster capacity
+	JobStatusRunning          JobStatus = "RUNNING"           // Tasks are being dispatched
+	JobStatusStalled          JobStatus = "STALLED"           // Dispatching, but no task completed for jobStallTimeout
+	JobStatusPendingResources JobStatus = "PENDING_RESOURCES" // No live worker meets the requirements
+	JobStatusPaused           JobStatus = "PAUSED"            // Dispatch halted by the user
+	JobStatusDelegated        JobStatus = "DELEGATED"         // Running on a regional orchestrator
+	JobStatusCompleted        JobStatus = "COMPLETED"
+	JobStatusCompletedEarly   JobStatus = "COMPLETED_EARLY" // Stopped once its metric plateaued
+	JobStatusFailed           JobStatus = "FAILED"
+	JobStatusCancelled        JobStatus = "CANCELLED"
+)
+
+// jobTransitions lists the states each state may move to.
+var jobTransitions = map[JobStatus][]JobStatus{
+	JobStatusPending:          {JobStatusSharding, JobStatusQueued, JobStatusDelegated, JobStatusFailed, JobStatusCancelled},
+	JobStatusSharding:         {JobStatusBlocked, JobStatusQueued, JobStatusFailed, JobStatusCancelled},
+	JobStatusBlocked:          {JobStatusQueued, JobStatusFailed, JobStatusCancelled},
+	JobStatusQueued:           {JobStatusRunning, JobStatusPendingResources, JobStatusFailed, JobStatusCancelled},
+	JobStatusRunning:          {JobStatusQueued, JobStatusStalled, JobStatusPendingResources, JobStatusPaused, JobStatusCompleted, JobStatusCompletedEarly, JobStatusFailed, JobStatusCancelled},
+	JobStatusStalled:          {JobStatusRunning, JobStatusQueued, JobStatusPendingResources, JobStatusPaused, JobStatusCompleted, JobStatusCompletedEarly, JobStatusFailed, JobStatusCancelled},
+	JobStatusPendingResources: {JobStatusQueued, JobStatusRunning, JobStatusPaused, JobStatusCompleted, JobStatusCompletedEarly, JobStatusFailed, JobStatusCancelled},
+	JobStatusPaused:           {JobStatusRunning, JobStatusPendingResources, JobStatusCompleted, JobStatusCompletedEarly, JobStatusFailed, JobStatusCancelled},
+	JobStatusDelegated:        {JobStatusCompleted, JobStatusCompletedEarly, JobStatusFailed, JobStatusCancelled},
+	JobStatusFailed:           {JobStatusBlocked, JobStatusQueued, JobStatusDelegated},
+	JobStatusCancelled:        {JobStatusBlocked, JobStatusQueued, JobStatusDelegated},
+	JobStatusCompleted:        {},
+	JobStatusCompletedEarly:   {},
+}
+
+var jobStatusProto = map[JobStatus]orchestratorpb.JobState{
+	JobStatusPending:          orchestratorpb.JobState_JOB_STATE_PENDING,
+	JobStatusSharding:         orchestratorpb.JobState_JOB_STATE_SHARDING,
+	JobStatusBlocked:          orchestratorpb.JobState_JOB_STATE_BLOCKED,
+	JobStatusQueued:           orchestratorpb.JobState_JOB_STATE_QUEUED,
+	JobStatusRunning:          orchestratorpb.JobState_JOB_STATE_RUNNING,
+	JobStatusStalled:          orchestratorpb.JobState_JOB_STATE_STALLED,
+	JobStatusPendingResources: orchestratorpb.JobState_JOB_STATE_PENDING_RESOURCES,
+	JobStatusPaused:           orchestratorpb.JobState_JOB_STATE_PAUSED,
+	JobStatusDelegated:        orchestratorpb.JobState_JOB_STATE_DELEGATED,
+	JobStatusCompleted:        orchestratorpb.JobState_JOB_STATE_COMPLETED,
+	JobStatusCompletedEarly:   orchestratorpb.JobState_JOB_STATE_COMPLETED_EARLY,
+	JobStatusFailed:           orchestratorpb.JobState_JOB_STATE_FAILED,
+	JobStatusCancelled:        orchestratorpb.JobState_JOB_STATE_CANCELLED,
+}
+
+// JobTransition records one status change of a job.
+type JobTransition struct {
+	From   JobStatus
+	To     JobStatus
+	Reason string
+	At     time.Time
+}
+
+// IsTerminal reports whether a job in this status will not change again on
+// its own.
+func (st JobStatus) IsTerminal() bool {
+	return st == JobStatusCompleted || st == JobStatusCompletedEarly || st == JobStatusFailed || st == JobStatusCancelled
+}
+
+// CanTransitionTo reports whether a job may move from st to the given status.
+func (st JobStatus) CanTransitionTo(to JobStatus) bool {
+	for _, allowed := range jobTransitions[st] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+func (st JobStatus) toProto() orchestratorpb.JobState {
+	return jobStatusProto[st]
+}
+
+// transition moves the job to a new status, recording when and why.
+// The reason becomes the job's status message.
+func (j *Job) transition(to JobStatus, reason string) error {
+	if !j.Status.CanTransitionTo(to) {
+		return fmt.Errorf("invalid job transition %s -> %s", j.Status, to)
+	}
+
+	now := time.Now()
+	j.Transitions = append(j.Transitions, JobTransition{
+		From:   j.Status,
+		To:     to,
+		Reason: reason,
+		At:     now,
+	})
+	j.Status = to
+	j.StatusMessage = reason
+	j.UpdatedAt = now
+	return nil
+}
+
+// mustTransition is transition for callers that have already checked the
+// current status; a rejected transition is logged and leaves the job as is.
+func (j *Job) mustTransition(to JobStatus, reason string) bool {
+	if err := j.transition(to, reason); err != nil {
+		log.Printf("Warning: Job %s: %v", j.JobID, err)
+		return false
+	}
+	return true
+}
+
+// supersedes reports whether this record of a job is newer than another:
+// a finished job supersedes an unfinished one, otherwise the one updated
+// last does.
+func (j *Job) supersedes(other *Job) bool {
+	if j.Status.IsTerminal() != other.Status.IsTerminal() {
+		return j.Status.IsTerminal()
+	}
+	return j.UpdatedAt.After(other.UpdatedAt)
+}
+
+func (j *Job) transitionsProto() []*orchestratorpb.JobStatusTransition {
+	out := make([]*orchestratorpb.JobStatusTransition, 0, len(j.Transitions))
+	for _, t := range j.Transitions {
+		out = append(out, &orchestratorpb.JobStatusTransition{
+			From:        t.From.toProto(),
+			To:          t.To.toProto(),
+			Reason:      t.Reason,
+			TimestampMs: t.At.UnixMilli(),
+		})
+	}
+	return out
+}
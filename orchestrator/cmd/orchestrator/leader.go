@@ -0,0 +1,308 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Leader election lets several orchestrator replicas run side by side. The
+// replica holding the Redis lock serves every RPC; standbys reject them with
+// the leader's address and take over once the lock expires.
+var (
+	leaderElection      = os.Getenv("LEADER_ELECTION") == "true"
+	leaderLockTTL       = getEnvDuration("LEADER_LOCK_TTL", 15*time.Second)
+	leaderRenewInterval = getEnvDuration("LEADER_RENEW_INTERVAL", 5*time.Second)
+)
+
+const (
+	leaderLockKey = "orchestrator:leader"
+
+	// leaderMetadataKey carries the leader's address on rejected RPCs so
+	// clients can redirect to it.
+	leaderMetadataKey = "orchestrator-leader"
+)
+
+// renewLeaderScript extends the lock only while this replica still holds it.
+var renewLeaderScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+// LeaderElector competes for the leader lock on behalf of one replica. The
+// lock value is the replica's advertised gRPC address.
+type LeaderElector struct {
+	client   *redis.Client
+	addr     string
+	isLeader atomic.Bool
+}
+
+func newLeaderElector(port string) *LeaderElector {
+	redisAddr := os.Getenv("REDIS_ADDR")
+	if redisAddr == "" {
+		redisAddr = "redis:6379"
+	}
+
+	addr := os.Getenv("ADVERTISE_ADDR")
+	if addr == "" {
+		hostname, _ := os.Hostname()
+		addr = net.JoinHostPort(hostname, port)
+	}
+
+	return &LeaderElector{
+		client: redis.NewClient(&redis.Options{Addr: redisAddr}),
+		addr:   addr,
+	}
+}
+
+// IsLeader reports whether this replica currently holds the lock.
+func (e *LeaderElector) IsLeader() bool {
+	return e.isLeader.Load()
+}
+
+// Leader returns the address of the current leader, or "" if there is none.
+func (e *LeaderElector) Leader(ctx context.Context) string {
+	addr, err := e.client.Get(ctx, leaderLockKey).Result()
+	if err != nil {
+		return ""
+	}
+	return addr
+}
+
+// Run campaigns for the lock until ctx is done, calling onElected each time
+// this replica becomes leader and onDemoted each time it stops being one. A
+// renewal that fails is retried until the lock would have expired; the
+// leader then steps down, before a standby can take the lock over, and
+// campaigns again as a standby. It steps down at once if another replica
+// holds the lock.
+func (e *LeaderElector) Run(ctx context.Context, onElected, onDemoted func()) {
+	ticker := time.NewTicker(leaderRenewInterval)
+	defer ticker.Stop()
+
+	var expires time.Time // When the lock this replica holds runs out
+	for {
+		if e.IsLeader() {
+			attempted := time.Now()
+			renewed, err := renewLeaderScript.Run(ctx, e.client, []string{leaderLockKey}, e.addr, leaderLockTTL.Milliseconds()).Int()
+			switch {
+			case err == nil && renewed != 0:
+				expires = attempted.Add(leaderLockTTL)
+			case err == nil:
+				log.Printf("Lost leadership: another replica holds the lock; stepping down")
+				e.stepDown(onDemoted)
+			case time.Now().Add(leaderRenewInterval).After(expires):
+				log.Printf("Lost leadership: could not renew the lock before it expires (%v); stepping down", err)
+				e.stepDown(onDemoted)
+			default:
+				log.Printf("Warning: Failed to renew leader lock, retrying until it expires at %s: %v", expires.Format(time.RFC3339), err)
+			}
+		} else {
+			attempted := time.Now()
+			acquired, err := e.client.SetNX(ctx, leaderLockKey, e.addr, leaderLockTTL).Result()
+			if err != nil {
+				log.Printf("Warning: Leader election failed: %v", err)
+			} else if acquired {
+				log.Printf("Elected leader as %s", e.addr)
+				expires = attempted.Add(leaderLockTTL)
+				e.isLeader.Store(true)
+				onElected()
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			e.resign()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// stepDown stops serving as leader without releasing the lock, which is
+// either another replica's or about to expire.
+func (e *LeaderElector) stepDown(onDemoted func()) {
+	if e.isLeader.Swap(false) {
+		onDemoted()
+	}
+}
+
+// resign releases the lock so a standby can take over without waiting for
+// it to expire.
+func (e *LeaderElector) resign() {
+	if !e.isLeader.Swap(false) {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if _, err := renewLeaderScript.Run(ctx, e.client, []string{leaderLockKey}, e.addr, 1).Result(); err != nil {
+		log.Printf("Warning: Failed to release leader lock: %v", err)
+	}
+}
+
+// notLeader builds the error returned by standbys and attaches the current
+// leader's address as trailer metadata.
+func (e *LeaderElector) notLeader(ctx context.Context) (metadata.MD, error) {
+	leader := e.Leader(ctx)
+	return metadata.Pairs(leaderMetadataKey, leader),
+		status.Errorf(codes.Unavailable, "orchestrator %s is not the leader (leader: %q)", e.addr, leader)
+}
+
+// UnaryInterceptor rejects unary RPCs while this replica is a standby.
+// Health checks are always answered so probes can tell the replicas apart.
+func (e *LeaderElector) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if e.IsLeader() || isHealthMethod(info.FullMethod) {
+		return handler(ctx, req)
+	}
+	md, err := e.notLeader(ctx)
+	grpc.SetTrailer(ctx, md)
+	return nil, err
+}
+
+// StreamInterceptor rejects streaming RPCs while this replica is a standby.
+func (e *LeaderElector) StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if e.IsLeader() || isHealthMethod(info.FullMethod) {
+		return handler(srv, ss)
+	}
+	md, err := e.notLeader(ss.Context())
+	ss.SetTrailer(md)
+	return err
+}
+
+// reportLeadership keeps the gRPC health status in line with leadership so
+// readiness probes route traffic only to the leader. A leader reports
+// NOT_SERVING while accepting() is false.
+func (e *LeaderElector) reportLeadership(ctx context.Context, hs *health.Server, accepting func() bool) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		servingStatus := healthpb.HealthCheckResponse_NOT_SERVING
+		if e.IsLeader() && accepting() {
+			servingStatus = healthpb.HealthCheckResponse_SERVING
+		}
+		setServingStatus(hs, servingStatus)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// lead makes this replica the leader: it rebuilds the scheduling state from
+// the job store and takes task streams again.
+func (s *OrchestratorServer) lead(ctx context.Context) {
+	s.mu.Lock()
+	s.standby = false
+	s.mu.Unlock()
+	s.rehydrate(ctx)
+}
+
+// stepDown forgets the in-memory scheduling state once this replica is no
+// longer the leader, since the next leader changes the jobs it holds. Open
+// task streams end within taskStreamPollInterval, and the workers reconnect
+// to the new leader. Becoming leader again rebuilds the state.
+func (s *OrchestratorServer) stepDown() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.standby = true
+	s.jobs = make(map[string]*Job)
+	s.admissionQueue = nil
+	s.workers = make(map[string]*WorkerActivity)
+	s.closeWorkerConns()
+	s.quarantine = make(map[string]*workerQuarantine)
+	s.usage = make(map[string]*UserUsage)
+	s.taskQueue.reset()
+}
+
+// isStandby reports whether this replica stepped down as leader.
+func (s *OrchestratorServer) isStandby() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.standby
+}
+
+// rehydrate rebuilds in-memory scheduling state from the job store after
+// this replica becomes leader, or at startup when it runs alone. Assigned
+// tasks keep their leases: workers still running them report to the new
+// leader, and tasks whose workers are gone are requeued when their lease
+// expires.
+func (s *OrchestratorServer) rehydrate(ctx context.Context) {
+	jobs, err := s.store.LoadActiveJobs(ctx)
+	if err != nil {
+		log.Printf("Warning: Failed to load jobs from the job store: %v", err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	restored := 0
+	var blocked []*Job
+	for _, job := range jobs {
+		if _, exists := s.jobs[job.JobID]; exists {
+			continue
+		}
+		// Reservations name workers of the previous leader
+		job.dropReservations()
+		s.jobs[job.JobID] = job
+		restored++
+
+		switch {
+		case job.Status == JobStatusSharding:
+			// The request that would have created its tasks is gone
+			job.mustTransition(JobStatusFailed, "Orchestrator changed while planning dataset shards; submit the job again")
+			if err := s.saveJob(ctx, job); err != nil {
+				log.Printf("Warning: Failed to save job: %v", err)
+			}
+		case job.Status == JobStatusPending && job.Region != "":
+			// Whether the region accepted it is unknown; the next sync
+			// mirrors the job or fails it if the region never got it
+			job.mustTransition(JobStatusDelegated, fmt.Sprintf("Delegated to region %s", job.Region))
+			if err := s.saveJob(ctx, job); err != nil {
+				log.Printf("Warning: Failed to save job: %v", err)
+			}
+		case job.Status == JobStatusPending:
+			s.submitJob(job)
+		case job.Status == JobStatusBlocked:
+			blocked = append(blocked, job)
+		case job.Status == JobStatusQueued:
+			s.admissionQueue = append(s.admissionQueue, job)
+		case job.isActive():
+			for _, task := range job.Tasks {
+				if task.Status == TaskStatusPending && job.dispatchable(task) {
+					s.enqueueTask(job, task)
+				}
+			}
+		}
+	}
+
+	// Dependencies may have finished while there was no leader
+	for _, job := range blocked {
+		s.resolveDependencies(ctx, job)
+		if job.Status != JobStatusBlocked {
+			if err := s.saveJob(ctx, job); err != nil {
+				log.Printf("Warning: Failed to save job: %v", err)
+			}
+		}
+	}
+
+	s.sortAdmissionQueue()
+	s.admitQueuedJobs()
+
+	log.Printf("Restored %d active jobs from the job store", restored)
+}
@@ -1,224 +1,518 @@
 package main
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net"
-	"net/http"
 	"os"
+	"os/signal"
 	"sync"
+	"syscall"
 	"time"
-	"github.com/go-redis/redis/v8"
-	"github.com/google/uuid"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
 
 	orchestratorpb "github.com/tensorfleet/orchestrator/proto/orchestrator"
+	orchestratorv2pb "github.com/tensorfleet/orchestrator/proto/orchestratorv2"
+	storagepb "github.com/tensorfleet/orchestrator/proto/storage"
 )
 
 type OrchestratorServer struct {
 	orchestratorpb.UnimplementedOrchestratorServiceServer
-	redisClient *redis.Client
-	jobs        map[string]*Job
-	taskQueue   chan *Task
-	workers     map[string]*WorkerActivity // Track worker activity
-	mu          sync.RWMutex
+	store          JobStore // Durable job records, events and metrics
+	jobs           map[string]*Job
+	taskQueue      *TaskQueue
+	scheduler      Scheduler                    // Picks which queued task a worker gets
+	admissionQueue []*Job                       // QUEUED jobs in admission order
+	workers        map[string]*WorkerActivity   // Track worker activity
+	usage          map[string]*UserUsage        // Per-user accounting by user ID, loaded on demand
+	quarantine     map[string]*workerQuarantine // Task failures and quarantine by worker ID, kept across evictions
+	mu             sync.RWMutex
+
+	workerConns map[string]workerConn // Worker gRPC connections by worker ID
+	connMu      sync.Mutex
+
+	watchers map[string]map[chan struct{}]struct{} // WatchJobStatus subscribers by job ID
+	watchMu  sync.Mutex
+
+	draining       bool          // No new jobs or task assignments; guarded by mu
+	standby        bool          // Stepped down as leader; guarded by mu
+	drainRequested chan struct{} // Signalled by the Drain RPC
+
+	events     *EventPublisher                // Publishes cluster events to Redis; nil when disabled
+	writes     *StoreWriter                   // Writes events, metric samples and usage to the store outside mu
+	taskLogs   *TaskLogStore                  // Keeps shipped task logs in Redis; nil when disabled
+	results    *TaskResultCache               // Results of completed tasks by fingerprint; nil when disabled
+	objects    *ObjectStore                   // Where model weights are written; nil to go through the storage service
+	storage    storagepb.StorageServiceClient // Records models and checkpoints
+	federation *Federation                    // Regional orchestrators jobs may be delegated to; nil when not federating
+	faults     *FaultInjector                 // Simulated failures for testing; nil unless FAULT_INJECTION is set
 }
 
 type Job struct {
 	JobID           string
 	UserID          string
+	Namespace       string // Project the job belongs to; see namespace()
+	Region          string // Regional orchestrator running the job; empty when it runs here
 	ModelType       string
 	DatasetPath     string
 	Hyperparameters map[string]string
 	NumWorkers      int32
 	Epochs          int32
-	Status          string
+	Priority        int32 // Higher is scheduled first
+	Status          JobStatus
+	Transitions     []JobTransition // Status history, oldest first
 	Tasks           []*Task
 	CompletedTasks  int
+	FailedTasks     int
 	TotalTasks      int
-	CurrentLoss     float64
+
+	// Most of the job's tasks in flight at once; 0 for no cap
+	MaxParallelTasks int32
+
+	// Container image workers run the job's tasks in; empty for their default
+	Image string
+
+	// Failure policy
+	MaxTaskRetries        int
+	MaxFailedTasksPercent float64
+
+	// Task layout: each task trains one batch of SamplesPerBatch samples;
+	// DatasetSize is 0 when unknown
+	BatchesPerEpoch int32
+	SamplesPerBatch int32
+	DatasetSize     int64
+
+	CurrentLoss     float64 // Metrics of the latest settled epoch
 	CurrentAccuracy float64
+	EpochMetrics    []*EpochMetrics
+	SettledEpochs   map[int32]bool // Epochs whose completion has been recorded
+	StatusMessage   string         // Why the job reached its current status, if notable
+	MaxDuration     time.Duration
+	ProgressTimeout time.Duration
+
+	// Per-task execution timeout; when unset it is derived from the
+	// average duration of the job's completed tasks
+	TaskTimeout         time.Duration
+	AvgTaskDuration     time.Duration
+	TaskDurationSamples int
+	TaskDurations       durationWindow // Latest task durations, for percentiles
+	LastProgressAt      time.Time
+	StartedAt           time.Time // When the job was admitted
+
+	// Workers currently serving the job, capped at NumWorkers. Gang jobs
+	// reserve all of them at admission and hold them until they finish.
+	Reservations   map[string]*WorkerReservation
+	GangScheduling bool
+
+	// What each serving worker must provide
+	Requirements ResourceRequirements
+
+	// In sync-epoch mode only tasks of CurrentEpoch are dispatched
+	SyncEpochs   bool
+	CurrentEpoch int32
+
+	// Preprocessing and evaluation tasks run before and after training
+	Stages []string
+
+	// Held-out dataset every epoch is validated on, and the results;
+	// empty when the job is not validated
+	ValidationDatasetPath string
+	ValidationMetrics     []*EpochMetrics
+
+	// What the task result cache may do for the job, and how many of its
+	// tasks were resolved from it
+	ResultCache string
+	CachedTasks int
+
+	// Parameter-server state: the latest merged weights and the
+	// per-epoch weights still being collected
+	Aggregation    string
+	ModelWeights   []byte
+	WeightsEpoch   int32
+	pendingWeights map[int32][]WeightUpdate
+
+	// Checkpoint policy and history; CheckpointWeights holds the model
+	// state of the latest checkpoint
+	CheckpointEvery   int32
+	Checkpoints       []Checkpoint
+	CheckpointWeights []byte
+
+	// Optional plateau detection on the epoch metrics
+	EarlyStopping *EarlyStoppingPolicy
+
+	// Jobs that must complete before this one is queued, and what to do
+	// if one of them does not
+	DependsOn           []string
+	OnDependencyFailure string
+
 	CreatedAt       time.Time
 	UpdatedAt       time.Time
+
+	// Version of the stored record this job was last saved as or loaded
+	// from; the job store refuses a save based on an outdated version
+	Version int64
 }
 
 type Task struct {
-	TaskID      string
-	JobID       string
-	WorkerID    string
-	Status      string
-	Epoch       int32
-	BatchStart  int32
-	BatchEnd    int32
-	Loss        float64
-	Accuracy    float64
-	CreatedAt   time.Time
-	CompletedAt *time.Time
+	TaskID         string
+	JobID          string
+	WorkerID       string
+	Status         string
+	Type           string // TaskTypeTrain when empty
+	Epoch          int32
+	BatchStart     int32
+	BatchEnd       int32
+	Loss           float64
+	Accuracy       float64
+	Attempts       int
+	LastError      string
+	CreatedAt      time.Time
+	QueuedAt       time.Time
+	AssignedAt     *time.Time
+	LeaseExpiresAt time.Time
+	CompletedAt    *time.Time
+	Params         map[string]string  // Set by the task planner; sent with the hyperparameters
+	Straggling     bool               // Current attempt has run well past the job's P95
+	Progress       *TaskProgress      // Reported by the worker during the current attempt
+	ResourceUsage  *TaskResourceUsage // Reported by the worker with the last attempt's result
+	Fingerprint    string             // Hash of the current attempt's inputs; see resultcache.go
+
+	// Leased while its worker had every slot taken, so held prefetched
+	// until it reports the task started
+	Prefetched bool
+
+	// Merged weights a validation task scores, taken when its epoch
+	// settled; not persisted
+	weights []byte
 }
 
 type WorkerActivity struct {
 	WorkerID         string
+	Hostname         string
+	Address          string // gRPC address of the worker's own server
+	Executor         string // Training backend, as the worker reported it
 	CurrentTaskID    string
 	CurrentJobID     string
 	TasksCompleted   int
 	LastActivityTime time.Time
-	Status           string // "IDLE", "BUSY"
+	LastHeartbeat    time.Time
+	RegisteredAt     time.Time
+	Status           string // "IDLE", "BUSY", "OFFLINE", "SUSPECT"
+	MissedLeases     int    // Tasks whose lease expired while assigned here
+	Capabilities     WorkerCapabilities
+	CachedDatasets   map[string]time.Time // Dataset path -> when last reported
+	DatasetCacheHits int                  // Completed tasks whose dataset was already cached
+	DatasetFetches   int                  // Completed tasks whose dataset was downloaded
+	TaskDurations    durationWindow       // Latest durations of tasks it completed
+	StartedAt        time.Time            // When the worker process started, as it reported
+	Load             WorkerLoad           // Host load from the latest heartbeat
+	LoadReportedAt   time.Time
+
+	// Tasks the worker runs at once (0 until it registers), tasks it may
+	// hold prefetched on top, and the tasks leased to it; entries that have
+	// left the worker are pruned lazily
+	MaxConcurrentTasks int
+	PrefetchTasks      int
+	leased             map[string]*Task
+
+	// Quarantine after repeated task failures; the record in s.quarantine
+	quarantine *workerQuarantine
+
+	// Set by an operator through DrainWorker
+	Draining    bool
+	DrainReason string
+
+	// Issued at registration; task RPCs must present it
+	sessionToken string
 }
 
 // autoSaveModel triggers automatic model saving when job completes
 func (s *OrchestratorServer) autoSaveModel(ctx context.Context, jobID string, job *Job) {
-	storageURL := os.Getenv("STORAGE_SERVICE_URL")
-	if storageURL == "" {
-		storageURL = "http://storage:8081"
-	}
-
-	// Prepare job data to send to storage service
-	jobData := map[string]interface{}{
-		"job_id":           job.JobID,
-		"job_name":         job.JobID, // Using JobID as job name for now
-		"model_type":       job.ModelType,
-		"dataset_path":     job.DatasetPath,
-		"hyperparameters":  job.Hyperparameters,
-		"current_accuracy": job.CurrentAccuracy,
-		"current_loss":     job.CurrentLoss,
-		"completed_tasks":  job.CompletedTasks,
-		"total_tasks":      job.TotalTasks,
-		"epochs":          job.Epochs,
-		"num_workers":     job.NumWorkers,
-		"status":          job.Status,
-		"created_at":      job.CreatedAt.Format(time.RFC3339),
-		"updated_at":      job.UpdatedAt.Format(time.RFC3339),
-	}
-
-	// Convert to JSON
-	jsonData, err := json.Marshal(jobData)
-	if err != nil {
-		log.Printf("Warning: Failed to marshal job data for auto-save: %v", err)
-		return
+	req := &storagepb.SaveModelRequest{
+		JobId:           job.JobID,
+		JobName:         job.JobID, // Using JobID as job name for now
+		Namespace:       job.namespace(),
+		ModelType:       job.ModelType,
+		DatasetPath:     job.DatasetPath,
+		Hyperparameters: job.Hyperparameters,
+		Accuracy:        job.CurrentAccuracy,
+		Loss:            job.CurrentLoss,
+		CompletedTasks:  int32(job.CompletedTasks),
+		TotalTasks:      int32(job.TotalTasks),
+		Epochs:          job.Epochs,
+		NumWorkers:      job.NumWorkers,
+		Status:          string(job.Status),
 	}
 
-	// Call storage service to auto-save model
-	client := &http.Client{Timeout: 30 * time.Second}
-	url := fmt.Sprintf("%s/api/v1/jobs/%s/auto-save-model", storageURL, jobID)
-	
-	resp, err := client.Post(url, "application/json", bytes.NewBuffer(jsonData))
+	// With an object store the weights are written there and the storage
+	// service only records where they are
+	if s.objects != nil && len(job.ModelWeights) > 0 {
+		key, digest, err := s.objects.PutContent(ctx, modelsBucket, job.ModelWeights)
+		if err != nil {
+			log.Printf("Warning: Failed to write model weights of job %s to object store: %v", jobID, err)
+		} else {
+			req.WeightsObject = &storagepb.StoredObject{
+				ObjectName: key,
+				Checksum:   digest,
+				SizeBytes:  int64(len(job.ModelWeights)),
+			}
+		}
+	}
+
+	// Otherwise the weights go to the storage service with the job
+	if req.WeightsObject == nil {
+		req.ModelWeights = job.ModelWeights
+	}
+
+	resp, err := s.storage.SaveModel(withStorageAuth(ctx), req)
 	if err != nil {
-		log.Printf("Warning: Failed to auto-save model for job %s: %v", jobID, err)
+		log.Printf("⚠️  Failed to auto-save model for job %s: %v", jobID, err)
+		s.recordEvent(jobID, JobEvent{Type: EventModelSaveFailed, Message: status.Convert(err).Message()})
 		return
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode == 201 {
-		log.Printf("✅ Successfully auto-saved model for completed job %s", jobID)
-	} else if resp.StatusCode == 200 {
-		log.Printf("ℹ️  Model already exists for job %s", jobID)
+	if resp.Created {
+		log.Printf("✅ Successfully auto-saved model for completed job %s as %s version %s", jobID, resp.Model.GetName(), resp.Model.GetVersion())
+		s.recordEvent(jobID, JobEvent{Type: EventModelSaved, Message: "Model auto-saved to storage"})
 	} else {
-		log.Printf("⚠️  Failed to auto-save model for job %s (status: %d)", jobID, resp.StatusCode)
+		log.Printf("ℹ️  Model already exists for job %s", jobID)
+		s.recordEvent(jobID, JobEvent{Type: EventModelSaved, Message: "Model already saved in storage"})
 	}
 }
 
 func NewOrchestratorServer() (*OrchestratorServer, error) {
-	redisAddr := os.Getenv("REDIS_ADDR")
-	if redisAddr == "" {
-		redisAddr = "redis:6379"
+	store, err := newJobStore()
+	if err != nil {
+		return nil, err
 	}
-
-	rdb := redis.NewClient(&redis.Options{
-		Addr: redisAddr,
-	})
-
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	if err := rdb.Ping(ctx).Err(); err != nil {
-		log.Printf("Warning: Redis connection failed: %v", err)
-	} else {
-		log.Println("Connected to Redis successfully")
+	scheduler, err := configuredScheduler()
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Scheduling tasks with the %s scheduler", scheduler.Name())
+	federation, err := newFederation()
+	if err != nil {
+		return nil, err
+	}
+	storage, err := newStorageClient()
+	if err != nil {
+		return nil, err
 	}
 
 	return &OrchestratorServer{
-		redisClient: rdb,
+		store:       store,
 		jobs:        make(map[string]*Job),
-		taskQueue:   make(chan *Task, 1000),
+		taskQueue:   NewTaskQueue(queueAgingInterval),
+		scheduler:   scheduler,
 		workers:     make(map[string]*WorkerActivity),
+		quarantine:  make(map[string]*workerQuarantine),
+		usage:       make(map[string]*UserUsage),
+		workerConns: make(map[string]workerConn),
+		watchers:    make(map[string]map[chan struct{}]struct{}),
+
+		drainRequested: make(chan struct{}, 1),
+		events:         newEventPublisher(),
+		writes:         newStoreWriter(),
+		taskLogs:       newTaskLogStore(),
+		results:        newTaskResultCache(),
+		objects:        newObjectStore(),
+		storage:        storage,
+		federation:     federation,
+		faults:         newFaultInjector(),
 	}, nil
 }
 
 func (s *OrchestratorServer) CreateTrainingJob(ctx context.Context, req *orchestratorpb.TrainingJobRequest) (*orchestratorpb.TrainingJobResponse, error) {
+	if s.isDraining() {
+		return nil, drainingError()
+	}
 	log.Printf("Creating training job: %s for user: %s", req.JobId, req.UserId)
 
+	namespace, err := normalizeNamespace(req.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
 	job := &Job{
 		JobID:           req.JobId,
 		UserID:          req.UserId,
+		Namespace:       namespace,
 		ModelType:       req.ModelType,
 		DatasetPath:     req.DatasetPath,
 		Hyperparameters: req.Hyperparameters,
 		NumWorkers:      req.NumWorkers,
 		Epochs:          req.Epochs,
-		Status:          "PENDING",
+		Priority:        req.Priority,
+		Requirements:    requirementsFromProto(req.Requirements),
+		SyncEpochs:      req.SyncEpochs,
+		GangScheduling:  req.GangScheduling,
+		Aggregation:     req.Aggregation,
+		CheckpointEvery: req.CheckpointEveryEpochs,
+		Stages:          req.Stages,
+		Status:          JobStatusPending,
 		Tasks:           []*Task{},
+		MaxTaskRetries:  maxTaskRetries,
+		MaxDuration:     time.Duration(req.MaxDurationSeconds) * time.Second,
+		ProgressTimeout: time.Duration(req.ProgressTimeoutSeconds) * time.Second,
+		TaskTimeout:     time.Duration(req.TaskTimeoutSeconds) * time.Second,
 		CreatedAt:       time.Now(),
 		UpdatedAt:       time.Now(),
+		LastProgressAt:  time.Now(),
 	}
-
-	// Create tasks - split training across epochs and batches
-	numBatches := int32(10) // Simulate 10 batches per epoch
-	for epoch := int32(0); epoch < req.Epochs; epoch++ {
-		for batch := int32(0); batch < numBatches; batch++ {
-			task := &Task{
-				TaskID:     uuid.New().String(),
-				JobID:      req.JobId,
-				Status:     "PENDING",
-				Epoch:      epoch,
-				BatchStart: batch * 100,
-				BatchEnd:   (batch + 1) * 100,
-				CreatedAt:  time.Now(),
-			}
-			job.Tasks = append(job.Tasks, task)
-		}
+	job.Requirements.Namespace = namespace
+	if job.ProgressTimeout == 0 {
+		job.ProgressTimeout = defaultProgressTimeout
+	}
+	if req.MaxTaskRetries != nil {
+		job.MaxTaskRetries = int(req.GetMaxTaskRetries())
+	}
+	job.MaxFailedTasksPercent = req.MaxFailedTasksPercent
+	job.MaxParallelTasks = req.MaxParallelTasks
+	job.Image = req.Image
+	if job.Aggregation == "" {
+		job.Aggregation = AggregationAverage
+	}
+	if _, err := lookupAggregator(job.Aggregation); err != nil {
+		return nil, err
+	}
+	if job.GangScheduling && job.NumWorkers <= 0 {
+		return nil, fmt.Errorf("gang scheduling requires num_workers")
+	}
+	if job.MaxParallelTasks < 0 {
+		return nil, fmt.Errorf("max_parallel_tasks must not be negative")
+	}
+	if err := validateStages(job.Stages); err != nil {
+		return nil, err
+	}
+	earlyStopping, err := earlyStoppingFromProto(req.EarlyStopping)
+	if err != nil {
+		return nil, err
+	}
+	job.EarlyStopping = earlyStopping
+	job.ValidationDatasetPath = req.ValidationDatasetPath
+	if earlyStopping != nil && earlyStopping.validated() && job.ValidationDatasetPath == "" {
+		return nil, fmt.Errorf("early stopping on %s requires validation_dataset_path", earlyStopping.Metric)
+	}
+	job.ResultCache, err = resultCachePolicy(req.ResultCache)
+	if err != nil {
+		return nil, err
+	}
+	job.DependsOn = req.DependsOn
+	job.OnDependencyFailure = req.OnDependencyFailure
+	if job.OnDependencyFailure == "" {
+		job.OnDependencyFailure = DependencyFailureFail
 	}
 
-	job.TotalTasks = len(job.Tasks)
-	job.Status = "RUNNING"
+	region, err := s.placeJob(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if region != "" {
+		return s.delegateJob(ctx, job, req, region)
+	}
 
 	s.mu.Lock()
+	if err := s.checkQuota(ctx, job.UserID); err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	if err := s.validateDependencies(ctx, job); err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	if job.UserID != "" {
+		usage := s.userUsage(ctx, job.UserID)
+		usage.JobsSubmitted++
+		s.saveUsage(usage)
+	}
 	s.jobs[req.JobId] = job
+	job.mustTransition(JobStatusSharding, "Planning dataset shards")
+	if err := s.saveJob(ctx, job); err != nil {
+		log.Printf("Warning: Failed to save job: %v", err)
+	}
 	s.mu.Unlock()
 
-	// Enqueue tasks asynchronously to avoid blocking job creation
-	go func() {
-		for _, task := range job.Tasks {
-			s.taskQueue <- task
+	// Split training across epochs and batches of the actual dataset
+	plan, planErr := planShards(ctx, req)
+
+	s.mu.Lock()
+	if job.Status != JobStatusSharding {
+		// Cancelled while planning
+		status := job.Status
+		s.mu.Unlock()
+		return &orchestratorpb.TrainingJobResponse{
+			JobId:   req.JobId,
+			Status:  string(status),
+			Message: job.StatusMessage,
+		}, nil
+	}
+	if planErr == nil {
+		planErr = job.createTasks(plan)
+	}
+	if planErr != nil {
+		job.mustTransition(JobStatusFailed, planErr.Error())
+		s.recordEvent(job.JobID, JobEvent{Type: EventJobFailed, Message: planErr.Error()})
+		s.releaseDependents(ctx, job)
+		if err := s.saveJob(ctx, job); err != nil {
+			log.Printf("Warning: Failed to save job: %v", err)
 		}
-	}()
+		s.mu.Unlock()
+		log.Printf("Job %s failed during sharding: %v", req.JobId, planErr)
+
+		return &orchestratorpb.TrainingJobResponse{
+			JobId:   req.JobId,
+			Status:  string(JobStatusFailed),
+			Message: planErr.Error(),
+		}, nil
+	}
 
-	// Persist to Redis
-	if err := s.saveJobToRedis(ctx, job); err != nil {
-		log.Printf("Warning: Failed to save job to Redis: %v", err)
+	s.recordEvent(job.JobID, JobEvent{
+		Type: EventJobCreated,
+		Message: fmt.Sprintf("Job created with %d tasks over %d epochs (%d batches of %d samples)",
+			job.TotalTasks, job.Epochs, job.BatchesPerEpoch, job.SamplesPerBatch),
+	})
+	s.submitOrBlock(ctx, job)
+	status := job.Status
+	numTasks := job.TotalTasks
+
+	// Persist the job
+	if err := s.saveJob(ctx, job); err != nil {
+		log.Printf("Warning: Failed to save job: %v", err)
 	}
+	s.mu.Unlock()
 
-	log.Printf("Created job %s with %d tasks", req.JobId, job.TotalTasks)
+	log.Printf("Created job %s with %d tasks", req.JobId, numTasks)
 
 	return &orchestratorpb.TrainingJobResponse{
 		JobId:    req.JobId,
-		Status:   "RUNNING",
-		NumTasks: int32(job.TotalTasks),
-		Message:  fmt.Sprintf("Job created with %d tasks", job.TotalTasks),
+		Status:   string(status),
+		NumTasks: int32(numTasks),
+		Message:  fmt.Sprintf("Job created with %d tasks", numTasks),
 	}, nil
 }
 
 func (s *OrchestratorServer) GetJobStatus(ctx context.Context, req *orchestratorpb.GetJobStatusRequest) (*orchestratorpb.GetJobStatusResponse, error) {
+	// Delegated jobs report their region's live status when it answers
+	if region := s.delegatedRegion(ctx, req.JobId); region != "" {
+		if resp, ok := s.regionalJobStatus(ctx, req.JobId, region); ok {
+			return resp, nil
+		}
+	}
+
 	s.mu.RLock()
 	job, exists := s.jobs[req.JobId]
 	s.mu.RUnlock()
 
 	if !exists {
-		// Try to load from Redis
+		// Try to load from the job store
 		var err error
-		job, err = s.loadJobFromRedis(ctx, req.JobId)
+		job, err = s.loadJob(ctx, req.JobId)
 		if err != nil {
 			return nil, fmt.Errorf("job not found: %s", req.JobId)
 		}
@@ -232,105 +526,316 @@ func (s *OrchestratorServer) GetJobStatus(ctx context.Context, req *orchestrator
 		progress = int32(float64(job.CompletedTasks) / float64(job.TotalTasks) * 100)
 	}
 
+	s.mu.Lock()
+	allocatedWorkers := job.activeReservations(time.Now())
+	queuePosition := s.queuePosition(job.JobID)
+	epochMetrics := job.epochMetricsProto()
+	validationMetrics := job.validationMetricsProto()
+	status := job.Status
+	transitions := job.transitionsProto()
+	durations := job.TaskDurations.stats()
+	remaining := job.estimatedRemaining(time.Now())
+	detailedProgress := job.detailedProgress()
+	runningTasks := job.runningTaskProgress()
+	s.mu.Unlock()
+
+	message := fmt.Sprintf("Completed %d/%d tasks", job.CompletedTasks, job.TotalTasks)
+	if job.StatusMessage != "" {
+		message = fmt.Sprintf("%s (%s)", message, job.StatusMessage)
+	}
+
 	return &orchestratorpb.GetJobStatusResponse{
-		JobId:           job.JobID,
-		Status:          job.Status,
-		Progress:        progress,
-		CompletedTasks:  int32(job.CompletedTasks),
-		TotalTasks:      int32(job.TotalTasks),
-		CurrentLoss:     job.CurrentLoss,
-		CurrentAccuracy: job.CurrentAccuracy,
-		Message:         fmt.Sprintf("Completed %d/%d tasks", job.CompletedTasks, job.TotalTasks),
+		JobId:                job.JobID,
+		Namespace:            job.namespace(),
+		Region:               job.Region,
+		Status:               string(status),
+		Progress:             progress,
+		CompletedTasks:       int32(job.CompletedTasks),
+		TotalTasks:           int32(job.TotalTasks),
+		CurrentLoss:          job.CurrentLoss,
+		CurrentAccuracy:      job.CurrentAccuracy,
+		Message:              message,
+		NumWorkers:           job.NumWorkers,
+		AllocatedWorkers:     allocatedWorkers,
+		QueuePosition:        int32(queuePosition),
+		EpochMetrics:         epochMetrics,
+		State:                status.toProto(),
+		Transitions:          transitions,
+		TaskDurations:        durations.toProto(),
+		EstimatedRemainingMs: remaining.Milliseconds(),
+		DetailedProgress:     detailedProgress,
+		RunningTasks:         runningTasks,
+		ValidationMetrics:    validationMetrics,
+		CachedTasks:          int32(job.CachedTasks),
 	}, nil
 }
 
 func (s *OrchestratorServer) AssignTask(ctx context.Context, req *orchestratorpb.AssignTaskRequest) (*orchestratorpb.AssignTaskResponse, error) {
-	select {
-	case task := <-s.taskQueue:
-		s.mu.RLock()
-		job := s.jobs[task.JobID]
-		s.mu.RUnlock()
+	task, err := s.assignNextTask(ctx, req.WorkerId, time.Now().Add(5*time.Second))
+	if errors.Is(err, errDraining) {
+		return nil, drainingError()
+	}
+	return task, err
+}
 
-		if job == nil {
-			return nil, fmt.Errorf("job not found for task")
+// assignNextTask leases the next task the worker may run, waiting until the
+// deadline for one to become available. It returns errNoTasks if none did.
+func (s *OrchestratorServer) assignNextTask(ctx context.Context, workerID string, deadline time.Time) (*orchestratorpb.AssignTaskResponse, error) {
+	for {
+		s.mu.Lock()
+		if s.draining {
+			s.mu.Unlock()
+			return nil, errDraining
 		}
+		now := time.Now()
+		var capabilities WorkerCapabilities
+		if worker, ok := s.workers[workerID]; ok {
+			if worker.quarantine.Quarantined {
+				s.mu.Unlock()
+				return nil, fmt.Errorf("worker %s is quarantined: %s", workerID, worker.quarantine.Reason)
+			}
+			if worker.Draining {
+				s.mu.Unlock()
+				return nil, fmt.Errorf("worker %s is draining: %s", workerID, worker.DrainReason)
+			}
+			capabilities = worker.Capabilities
+			// Hold back work while the worker runs all it can or its
+			// host is too busy
+			if worker.atCapacity() || worker.overloaded(now) {
+				s.mu.Unlock()
+				if !waitForCapacity(ctx, deadline) {
+					return nil, errNoTasks
+				}
+				continue
+			}
+		}
+		// A worker in a gang only runs its gang job's tasks
+		gang := s.gangHolder(workerID)
+		placement := Placement{WorkerID: workerID, Capabilities: capabilities, Now: now}
+		pick := func(candidates []*Task) int {
+			return s.scheduler.Pick(s, placement, candidates)
+		}
+		parallelism := parallelismGate{}
+		task := s.taskQueue.Select(capabilities.pool(), func(t *Task) QueueDecision {
+			job := s.jobs[t.JobID]
+			// Drop stale queue entries, e.g. a requeued task that a late
+			// report from its original worker already completed
+			if job == nil || t.Status != TaskStatusPending {
+				return QueueDrop
+			}
+			// Only hand out work the job is ready for and this worker can run
+			if !job.isRunning() || !job.Requirements.SatisfiedBy(capabilities) || !capabilities.runs(t.taskType()) {
+				return QueueKeep
+			}
+			if gang != nil && gang != job {
+				return QueueKeep
+			}
+			// Respect the job's NumWorkers reservation and parallelism cap
+			if !job.canServe(workerID, now) || !parallelism.allows(job) {
+				return QueueKeep
+			}
+			// Briefly leave the task for an idle worker that has the data cached
+			if s.preferredElsewhere(job, t, workerID, now) {
+				return QueueKeep
+			}
+			return QueueTake
+		}, s.scheduler.Lookahead(), pick)
 
-		task.WorkerID = req.WorkerId
-		task.Status = "ASSIGNED"
+		if task == nil {
+			s.mu.Unlock()
+			if !s.taskQueue.Wait(ctx, deadline) {
+				return nil, errNoTasks
+			}
+			continue
+		}
 
-		// Update worker activity
-		s.mu.Lock()
-		workerActivity, ok := s.workers[req.WorkerId]
-		if !ok {
-			workerActivity = &WorkerActivity{
-				WorkerID:      req.WorkerId,
-				Status:        "BUSY",
-				TasksCompleted: 0,
-				LastActivityTime: time.Now(),
+		job := s.jobs[task.JobID]
+		// Tasks an earlier identical one already ran never reach a worker.
+		// The cache is read without the lock; the task is off the queue
+		// meanwhile, so no other worker takes it
+		if s.fingerprintTask(job, task) {
+			fingerprint := task.Fingerprint
+			s.mu.Unlock()
+			result := s.lookupTaskResult(ctx, task.TaskID, fingerprint)
+			s.mu.Lock()
+			// Cancelled or settled while the cache was read
+			if s.jobs[task.JobID] != job || task.Status != TaskStatusPending {
+				s.mu.Unlock()
+				continue
 			}
-			s.workers[req.WorkerId] = workerActivity
+			if result != nil {
+				s.resolveFromCache(ctx, job, task, result)
+				if err := s.saveJob(ctx, job); err != nil {
+					log.Printf("Warning: Failed to save job: %v", err)
+				}
+				s.mu.Unlock()
+				continue
+			}
+			// Paused, or the orchestrator is shutting down
+			if !job.isRunning() || s.draining {
+				s.enqueueTask(job, task)
+				s.mu.Unlock()
+				continue
+			}
+		}
+		s.leaseTask(job, task, workerID)
+		if s.faults.failAssignment() {
+			s.retryTask(job, task, "injected fault: assignment failed")
+			s.mu.Unlock()
+			continue
 		}
+
+		// Update worker activity
+		workerActivity := s.touchWorker(workerID)
+		workerActivity.trackLease(task)
 		workerActivity.CurrentTaskID = task.TaskID
 		workerActivity.CurrentJobID = task.JobID
-		workerActivity.Status = "BUSY"
-		workerActivity.LastActivityTime = time.Now()
+		workerActivity.Status = WorkerStatusBusy
+		workerActivity.LastActivityTime = now
+		weights := task.startingWeights(job)
+		datasetPath := task.datasetPath(job)
+		queuedAt := task.QueuedAt
+		attempt := task.Attempts
 		s.mu.Unlock()
 
-		log.Printf("Assigned task %s (epoch %d) to worker %s", task.TaskID, task.Epoch, req.WorkerId)
+		log.Printf("Assigned task %s (job priority %d, epoch %d, attempt %d) to worker %s",
+			task.TaskID, job.Priority, task.Epoch, attempt, workerID)
 
 		return &orchestratorpb.AssignTaskResponse{
 			TaskId:          task.TaskID,
 			JobId:           task.JobID,
 			ModelType:       job.ModelType,
-			DatasetPath:     job.DatasetPath,
-			Hyperparameters: job.Hyperparameters,
+			DatasetPath:     datasetPath,
+			Hyperparameters: task.hyperparameters(job),
 			Epoch:           task.Epoch,
 			BatchStart:      task.BatchStart,
 			BatchEnd:        task.BatchEnd,
+			ModelWeights:    weights,
+			TaskType:        task.taskType(),
+			Image:           job.Image,
+			GpuCount:        job.Requirements.MinGPUCount,
+			CpuCores:        job.Requirements.MinCPUCores,
+			MemoryMb:        job.Requirements.MinMemoryMB,
+			QueuedAtMs:      unixMillis(queuedAt),
+			Attempt:         int32(attempt),
 		}, nil
-	case <-time.After(5 * time.Second):
-		return nil, fmt.Errorf("no tasks available")
 	}
 }
 
 func (s *OrchestratorServer) ReportTaskCompletion(ctx context.Context, req *orchestratorpb.TaskCompletionRequest) (*orchestratorpb.TaskCompletionResponse, error) {
 	log.Printf("Task %s completed by worker %s: success=%v, loss=%.4f, accuracy=%.4f", 
 		req.TaskId, req.WorkerId, req.Success, req.Loss, req.Accuracy)
+	s.faults.delayCompletion(ctx)
+
+	// Large weights are uploaded to storage and only referenced here.
+	// Without them the task's work is lost, so it is treated as failed
+	// and run again
+	if req.Success && len(req.ModelWeights) == 0 && req.ModelWeightsRef != nil {
+		weights, err := s.fetchUploadedWeights(ctx, req.ModelWeightsRef)
+		if err != nil {
+			log.Printf("Warning: Failed to fetch weights of task %s: %v", req.TaskId, err)
+			req.Success = false
+			req.ErrorMessage = fmt.Sprintf("Failed to fetch uploaded weights: %v", err)
+		} else {
+			req.ModelWeights = weights
+		}
+	}
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	job, exists := s.jobs[req.JobId]
 	if !exists {
-		return nil, fmt.Errorf("job not found")
+		return nil, status.Errorf(codes.NotFound, "job not found: %s", req.JobId)
+	}
+
+	// Reports are idempotent per task: only the first one settling it
+	// counts. Retried calls change nothing
+	task := job.findTask(req.TaskId)
+	if task == nil {
+		log.Printf("Ignoring report for unknown task %s of job %s", req.TaskId, req.JobId)
+		return &orchestratorpb.TaskCompletionResponse{
+			Acknowledged: false,
+			Message:      "Unknown task",
+		}, nil
+	}
+	if task.Status == TaskStatusCompleted || task.Status == TaskStatusFailed || task.Status == TaskStatusCancelled {
+		// Late report for a task that was already settled, e.g. after its
+		// lease expired and another worker finished it
+		log.Printf("Ignoring report for already settled task %s (status: %s)", req.TaskId, task.Status)
+		return &orchestratorpb.TaskCompletionResponse{
+			Acknowledged: true,
+			Message:      "Task already settled",
+			Duplicate:    true,
+		}, nil
+	}
+	if task.Status != TaskStatusAssigned || task.WorkerID != req.WorkerId || int(req.Attempt) != task.Attempts {
+		// Results are only taken from the worker holding the task, for the
+		// attempt it was given; the session token proves who is calling,
+		// not that the task is theirs
+		if req.Success {
+			log.Printf("Warning: Rejecting result of task %s from worker %s, which does not hold attempt %d of it",
+				req.TaskId, req.WorkerId, req.Attempt)
+			return &orchestratorpb.TaskCompletionResponse{
+				Acknowledged: false,
+				Message:      "Task is not leased to this worker",
+			}, nil
+		}
+		// A failure of an attempt that was since requeued or handed to
+		// another worker must not count against the current one
+		log.Printf("Ignoring stale failure report for task %s from worker %s", req.TaskId, req.WorkerId)
+		return &orchestratorpb.TaskCompletionResponse{
+			Acknowledged: true,
+			Message:      "Task has moved on from this attempt",
+			Duplicate:    true,
+		}, nil
+	}
+	if usage := resourceUsageFromProto(req.ResourceUsage); usage != nil {
+		task.ResourceUsage = usage
 	}
 
 	if req.Success {
-		job.CompletedTasks++
-		job.CurrentLoss = req.Loss
-		job.CurrentAccuracy = req.Accuracy
-		job.UpdatedAt = time.Now()
-
-		if job.CompletedTasks >= job.TotalTasks {
-			job.Status = "COMPLETED"
-			log.Printf("Job %s completed!", req.JobId)
-			
-			// Trigger automatic model saving in background
-			go s.autoSaveModel(ctx, req.JobId, job)
+		now := time.Now()
+		job.release(task.WorkerID)
+		// Stages take no part in the job's task timing or epoch metrics
+		if task.AssignedAt != nil && !task.isStage() {
+			s.observeTaskDuration(job, req.WorkerId, now.Sub(*task.AssignedAt))
 		}
+		s.chargeTaskAttempt(job, task, true)
+		s.storeTaskResult(job, task, req.Loss, req.Accuracy, req.ModelWeights)
+		s.completeTask(ctx, job, task, req.Loss, req.Accuracy, req.ModelWeights)
+	} else {
+		s.recordEvent(job.JobID, JobEvent{
+			Type:     EventWorkerFailed,
+			Message:  "Task failed on worker: " + req.ErrorMessage,
+			TaskID:   task.TaskID,
+			WorkerID: req.WorkerId,
+		})
+		s.recordWorkerFailure(req.WorkerId, req.ErrorMessage)
+		s.retryTask(job, task, req.ErrorMessage)
 	}
 
 	// Update worker activity
-	workerActivity, ok := s.workers[req.WorkerId]
-	if ok {
-		workerActivity.TasksCompleted++
-		workerActivity.Status = "IDLE"
-		workerActivity.LastActivityTime = time.Now()
+	workerActivity := s.touchWorker(req.WorkerId)
+	if req.Success {
+		workerActivity.markDatasetCached(task.datasetPath(job))
+		switch req.DatasetCache {
+		case orchestratorpb.DatasetCacheResult_DATASET_CACHE_HIT:
+			workerActivity.DatasetCacheHits++
+		case orchestratorpb.DatasetCacheResult_DATASET_CACHE_MISS:
+			workerActivity.DatasetFetches++
+		}
+	}
+	workerActivity.TasksCompleted++
+	if workerActivity.inFlight() == 0 {
+		workerActivity.Status = WorkerStatusIdle
+		s.admitWaitingGang()
 	}
+	workerActivity.LastActivityTime = time.Now()
 
-	// Save to Redis
-	if err := s.saveJobToRedis(ctx, job); err != nil {
-		log.Printf("Warning: Failed to save job to Redis: %v", err)
+	// Persist the job
+	if err := s.saveJob(ctx, job); err != nil {
+		log.Printf("Warning: Failed to save job: %v", err)
 	}
 
 	return &orchestratorpb.TaskCompletionResponse{
@@ -339,6 +844,44 @@ func (s *OrchestratorServer) ReportTaskCompletion(ctx context.Context, req *orch
 	}, nil
 }
 
+// completeTask records a task's result and moves the job on: the result
+// counts towards its epoch, the weights towards the epoch's merge, and the
+// tasks it unblocked are queued. Caller must hold s.mu.
+func (s *OrchestratorServer) completeTask(ctx context.Context, job *Job, task *Task, loss, accuracy float64, weights []byte) {
+	now := time.Now()
+	task.Status = TaskStatusCompleted
+	task.Loss = loss
+	task.Accuracy = accuracy
+	task.CompletedAt = &now
+	if !task.isStage() {
+		job.recordTaskMetrics(task)
+		s.appendMetricSample(job.JobID, MetricSample{
+			Kind:     MetricSampleTask,
+			Epoch:    task.Epoch,
+			TaskID:   task.TaskID,
+			Loss:     task.Loss,
+			Accuracy: task.Accuracy,
+		})
+		s.collectWeights(job, task, weights)
+	}
+	s.events.publish(ClusterEvent{
+		Type:     EventTaskCompleted,
+		JobID:    job.JobID,
+		TaskID:   task.TaskID,
+		WorkerID: task.WorkerID,
+		Message:  fmt.Sprintf("Epoch %d %s task completed: loss=%.4f, accuracy=%.4f", task.Epoch, task.taskType(), task.Loss, task.Accuracy),
+	})
+	s.onTaskSettled(ctx, job, task)
+
+	job.CompletedTasks++
+	job.UpdatedAt = time.Now()
+	job.LastProgressAt = job.UpdatedAt
+	s.recoverStalledJob(job)
+
+	s.completeJobIfSettled(job)
+	s.advanceEpochBarrier(job)
+}
+
 func (s *OrchestratorServer) UpdateJobMetrics(ctx context.Context, req *orchestratorpb.JobMetricsRequest) (*orchestratorpb.JobMetricsResponse, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -356,14 +899,18 @@ func (s *OrchestratorServer) UpdateJobMetrics(ctx context.Context, req *orchestr
 }
 
 func (s *OrchestratorServer) CancelJob(ctx context.Context, req *orchestratorpb.CancelJobRequest) (*orchestratorpb.CancelJobResponse, error) {
+	if region := s.delegatedRegion(ctx, req.JobId); region != "" {
+		return s.cancelDelegatedJob(ctx, req.JobId, region)
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	job, exists := s.jobs[req.JobId]
 	if !exists {
-		// Try to load from Redis
+		// Try to load from the job store
 		var err error
-		job, err = s.loadJobFromRedis(ctx, req.JobId)
+		job, err = s.loadJob(ctx, req.JobId)
 		if err != nil {
 			return &orchestratorpb.CancelJobResponse{
 				Success: false,
@@ -376,21 +923,19 @@ func (s *OrchestratorServer) CancelJob(ctx context.Context, req *orchestratorpb.
 	previousStatus := job.Status
 
 	// Check if job can be cancelled
-	if job.Status == "COMPLETED" || job.Status == "FAILED" || job.Status == "CANCELLED" {
+	if job.Status.IsTerminal() {
 		return &orchestratorpb.CancelJobResponse{
 			Success:        false,
 			Message:        fmt.Sprintf("Cannot cancel job with status: %s", job.Status),
-			PreviousStatus: previousStatus,
+			PreviousStatus: string(previousStatus),
 		}, nil
 	}
 
-	// Update job status to CANCELLED
-	job.Status = "CANCELLED"
-	job.UpdatedAt = time.Now()
+	s.cancelJobLocked(job, "Cancelled by user")
 
-	// Save to Redis
-	if err := s.saveJobToRedis(ctx, job); err != nil {
-		log.Printf("Failed to save cancelled job to Redis: %v", err)
+	// Persist the job
+	if err := s.saveJob(ctx, job); err != nil {
+		log.Printf("Failed to save cancelled job: %v", err)
 	}
 
 	log.Printf("Job %s cancelled (previous status: %s)", req.JobId, previousStatus)
@@ -398,10 +943,56 @@ func (s *OrchestratorServer) CancelJob(ctx context.Context, req *orchestratorpb.
 	return &orchestratorpb.CancelJobResponse{
 		Success:        true,
 		Message:        fmt.Sprintf("Job %s has been cancelled", req.JobId),
-		PreviousStatus: previousStatus,
+		PreviousStatus: string(previousStatus),
 	}, nil
 }
 
+// cancelJobLocked moves a job to CANCELLED and settles its outstanding
+// tasks. Queued tasks are drained from the task queue; in-flight ones are
+// cancelled on their workers. Caller must hold s.mu.
+func (s *OrchestratorServer) cancelJobLocked(job *Job, reason string) {
+	if !job.mustTransition(JobStatusCancelled, reason) {
+		return
+	}
+	job.dropReservations()
+	s.recordEvent(job.JobID, JobEvent{Type: EventJobCancelled, Message: reason})
+
+	if drained := s.cancelOutstandingTasks(job); drained > 0 {
+		log.Printf("Drained %d outstanding tasks of cancelled job %s", drained, job.JobID)
+	}
+
+	s.releaseDependents(context.Background(), job)
+	s.admitQueuedJobs()
+}
+
+// cancelOutstandingTasks cancels the job's pending tasks and stops its
+// assigned ones on their workers. The job is tombstoned in the task queue so
+// none of its tasks is dispatched until it is started again. It returns how
+// many tasks were cancelled. Caller must hold s.mu.
+func (s *OrchestratorServer) cancelOutstandingTasks(job *Job) int {
+	s.taskQueue.Tombstone(job.JobID)
+	cancelled := 0
+	for _, task := range job.Tasks {
+		switch task.Status {
+		case TaskStatusPending:
+			task.Status = TaskStatusCancelled
+			cancelled++
+		case TaskStatusAssigned:
+			s.chargeTaskAttempt(job, task, false)
+			task.Status = TaskStatusCancelled
+			cancelled++
+			if worker, ok := s.workers[task.WorkerID]; ok {
+				if worker.CurrentTaskID == task.TaskID {
+					worker.CurrentTaskID = ""
+					worker.Status = WorkerStatusIdle
+				}
+				go s.cancelOnWorker(worker.WorkerID, task.TaskID, "job "+string(job.Status))
+			}
+		}
+	}
+	return cancelled
+}
+
 func (s *OrchestratorServer) GetWorkerActivity(ctx context.Context, req *orchestratorpb.WorkerActivityRequest) (*orchestratorpb.WorkerActivityResponse, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -409,12 +1000,28 @@ func (s *OrchestratorServer) GetWorkerActivity(ctx context.Context, req *orchest
 	workers := make([]*orchestratorpb.WorkerInfo, 0, len(s.workers))
 	for _, worker := range s.workers {
 		workers = append(workers, &orchestratorpb.WorkerInfo{
-			WorkerId:         worker.WorkerID,
-			Status:           worker.Status,
-			CurrentTaskId:    worker.CurrentTaskID,
-			CurrentJobId:     worker.CurrentJobID,
-			TasksCompleted:   int32(worker.TasksCompleted),
-			LastActivityTime: worker.LastActivityTime.Unix(),
+			WorkerId:           worker.WorkerID,
+			Status:             worker.displayStatus(),
+			CurrentTaskId:      worker.CurrentTaskID,
+			CurrentJobId:       worker.CurrentJobID,
+			TasksCompleted:     int32(worker.TasksCompleted),
+			LastActivityTime:   worker.LastActivityTime.Unix(),
+			Hostname:           worker.Hostname,
+			Address:            worker.Address,
+			LastHeartbeatTime:  worker.LastHeartbeat.Unix(),
+			RegisteredAt:       worker.RegisteredAt.Unix(),
+			Capabilities:       worker.Capabilities.toProto(),
+			CachedDatasets:     worker.cachedDatasetList(),
+			QuarantineReason:   worker.quarantine.Reason,
+			MaxConcurrentTasks: int32(worker.maxInFlight()),
+			InFlightTasks:      int32(worker.inFlight()),
+			TaskDurations:      worker.TaskDurations.stats().toProto(),
+			Load:               worker.Load.toProto(),
+			LoadReportedAtMs:   unixMillis(worker.LoadReportedAt),
+			UptimeSeconds:      int64(worker.uptime(time.Now()) / time.Second),
+			DatasetCacheHits:   int32(worker.DatasetCacheHits),
+			DatasetCacheMisses: int32(worker.DatasetFetches),
+			Executor:           worker.Executor,
 		})
 	}
 
@@ -424,27 +1031,34 @@ func (s *OrchestratorServer) GetWorkerActivity(ctx context.Context, req *orchest
 	}, nil
 }
 
-func (s *OrchestratorServer) saveJobToRedis(ctx context.Context, job *Job) error {
-	data, err := json.Marshal(job)
-	if err != nil {
-		return err
-	}
-
-	return s.redisClient.Set(ctx, "job:"+job.JobID, data, 24*time.Hour).Err()
-}
+// saveJob persists the job and advances its Version. Caller must hold s.mu.
+func (s *OrchestratorServer) saveJob(ctx context.Context, job *Job) error {
+	// Every persisted change is also pushed to WatchJobStatus subscribers
+	// and, for status changes, to event subscribers
+	s.notifyJobChanged(job.JobID)
+	s.events.publishTransitions(job)
 
-func (s *OrchestratorServer) loadJobFromRedis(ctx context.Context, jobID string) (*Job, error) {
-	data, err := s.redisClient.Get(ctx, "job:"+jobID).Bytes()
-	if err != nil {
-		return nil, err
+	err := s.store.SaveJob(ctx, job)
+	var conflict *VersionConflictError
+	if !errors.As(err, &conflict) {
+		return err
 	}
 
-	var job Job
-	if err := json.Unmarshal(data, &job); err != nil {
-		return nil, err
+	// Someone else wrote the record since we last did, e.g. a replica that
+	// has not noticed it lost leadership. A finished job is never
+	// overwritten by an unfinished one; otherwise the later update wins.
+	stored, loadErr := s.store.LoadJob(ctx, job.JobID)
+	if loadErr == nil && stored.supersedes(job) {
+		log.Printf("Warning: Keeping stored job %s (%s, version %d) over %s: %v",
+			job.JobID, stored.Status, stored.Version, job.Status, conflict)
+		return conflict
 	}
+	job.Version = conflict.Stored
+	return s.store.SaveJob(ctx, job)
+}
 
-	return &job, nil
+func (s *OrchestratorServer) loadJob(ctx context.Context, jobID string) (*Job, error) {
+	return s.store.LoadJob(ctx, jobID)
 }
 
 func main() {
@@ -463,11 +1077,106 @@ func main() {
 		log.Fatalf("Failed to create orchestrator: %v", err)
 	}
 
-	grpcServer := grpc.NewServer()
+	// Report NOT_SERVING until the orchestrator can take work, so gRPC
+	// probes distinguish an open port from a serving replica
+	healthServer := health.NewServer()
+	setServingStatus(healthServer, healthpb.HealthCheckResponse_NOT_SERVING)
+
+	// Recovery, logging, rate limiting and worker auth wrap every RPC.
+	// Standbys reject RPCs until they win the leader lock, then pick up
+	// the previous leader's jobs from the job store
+	serverOpts := serverInterceptors()
+	electionCtx, stopElection := context.WithCancel(context.Background())
+	defer stopElection()
+	leading := func() bool { return true }
+	if leaderElection {
+		elector := newLeaderElector(port)
+		leading = elector.IsLeader
+		serverOpts = append(serverOpts,
+			grpc.ChainUnaryInterceptor(elector.UnaryInterceptor),
+			grpc.ChainStreamInterceptor(elector.StreamInterceptor),
+		)
+		go elector.reportLeadership(context.Background(), healthServer, func() bool {
+			return !server.isDraining()
+		})
+		go elector.Run(electionCtx, func() {
+			server.lead(context.Background())
+		}, server.stepDown)
+	} else {
+		// A lone replica picks up where its previous run left off before
+		// taking requests
+		server.rehydrate(context.Background())
+		setServingStatus(healthServer, healthpb.HealthCheckResponse_SERVING)
+	}
+
+	// Track worker liveness from heartbeats
+	go server.monitorWorkers(context.Background())
+
+	// Requeue tasks whose workers stopped responding
+	go server.monitorTaskLeases(context.Background())
+
+	// Cancel jobs that overrun their deadlines
+	go server.monitorJobDeadlines(context.Background())
+
+	// Drop long-finished jobs from memory; the job store keeps them
+	go server.collectFinishedJobs(context.Background())
+
+	// Archive and delete job records past their retention
+	go server.archiveJobRecords(context.Background(), leading)
+
+	// Publish cluster events to Redis subscribers
+	go server.events.run(context.Background())
+
+	// Write job events, metric samples and usage to the job store
+	go server.writes.run(context.Background())
+
+	// Mirror the progress of jobs delegated to regional orchestrators
+	go server.syncDelegatedJobs(context.Background(), leading)
+
+	// Resize the worker Deployment to the queue
+	go newAutoscaler(server).run(context.Background(), leading)
+
+	// Worker sessions are checked last: a standby knows no sessions and
+	// must redirect workers to the leader instead
+	serverOpts = append(serverOpts,
+		grpc.ChainUnaryInterceptor(server.sessionUnaryInterceptor),
+		grpc.ChainStreamInterceptor(server.sessionStreamInterceptor),
+	)
+
+	grpcServer := grpc.NewServer(serverOpts...)
 	orchestratorpb.RegisterOrchestratorServiceServer(grpcServer, server)
+	orchestratorpb.RegisterOrchestratorAdminServiceServer(grpcServer, NewAdminServer(server))
+	// v1 stays registered next to v2 until clients have moved over
+	orchestratorv2pb.RegisterOrchestratorServiceServer(grpcServer, NewV2Server(server))
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+
+	// Drain on SIGTERM or the Drain RPC: stop taking work, let in-flight
+	// task reports land, persist state, then stop serving
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT)
+		select {
+		case sig := <-signals:
+			server.startDrain(fmt.Sprintf("received %s", sig))
+		case <-server.drainRequested:
+		}
+
+		setServingStatus(healthServer, healthpb.HealthCheckResponse_NOT_SERVING)
+		ctx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+		server.finishDrain(ctx)
+		cancel()
+		// Hand leadership to a standby
+		stopElection()
+		stopGracefully(grpcServer, 10*time.Second)
+	}()
 
 	log.Printf("Orchestrator server listening on port %s", port)
 	if err := grpcServer.Serve(lis); err != nil {
 		log.Fatalf("Failed to serve: %v", err)
 	}
+	// Serve returns as soon as stopping begins; wait for open RPCs
+	<-stopped
+	log.Println("Orchestrator stopped")
 }
@@ -0,0 +1,89 @@
+package main
+
+import orchestratorpb "github.com/tensorfleet/orchestrator/proto/orchestrator"
+
+// EpochMetrics aggregates the metrics reported by an epoch's completed
+// tasks. Loss and Accuracy are means weighted by each task's batch count.
+type EpochMetrics struct {
+	Epoch          int32
+	Loss           float64
+	Accuracy       float64
+	Batches        int32
+	CompletedTasks int
+}
+
+// epochMetrics returns the metrics entry for an epoch, or nil if no task of
+// the epoch has completed yet.
+func (j *Job) epochMetrics(epoch int32) *EpochMetrics {
+	for _, m := range j.EpochMetrics {
+		if m.Epoch == epoch {
+			return m
+		}
+	}
+	return nil
+}
+
+// recordTaskMetrics folds a completed task's loss and accuracy into its
+// epoch's means and refreshes the job's current metrics.
+func (j *Job) recordTaskMetrics(task *Task) {
+	m := j.epochMetrics(task.Epoch)
+	if m == nil {
+		m = &EpochMetrics{Epoch: task.Epoch}
+		j.EpochMetrics = append(j.EpochMetrics, m)
+	}
+
+	batches := task.BatchEnd - task.BatchStart
+	if batches <= 0 {
+		batches = 1
+	}
+	total := float64(m.Batches + batches)
+	m.Loss += (task.Loss - m.Loss) * float64(batches) / total
+	m.Accuracy += (task.Accuracy - m.Accuracy) * float64(batches) / total
+	m.Batches += batches
+	m.CompletedTasks++
+
+	j.refreshCurrentMetrics()
+}
+
+// refreshCurrentMetrics sets the job's current loss and accuracy to those of
+// its latest settled epoch, or of the latest epoch with results while none
+// has settled yet.
+func (j *Job) refreshCurrentMetrics() {
+	var latestSettled, latest *EpochMetrics
+	for _, m := range j.EpochMetrics {
+		if latest == nil || m.Epoch > latest.Epoch {
+			latest = m
+		}
+		if j.epochSettled(m.Epoch) && (latestSettled == nil || m.Epoch > latestSettled.Epoch) {
+			latestSettled = m
+		}
+	}
+
+	current := latestSettled
+	if current == nil {
+		current = latest
+	}
+	if current != nil {
+		j.CurrentLoss = current.Loss
+		j.CurrentAccuracy = current.Accuracy
+	}
+}
+
+// epochMetricsProto reports per-epoch metrics in epoch order.
+func (j *Job) epochMetricsProto() []*orchestratorpb.EpochMetrics {
+	out := make([]*orchestratorpb.EpochMetrics, 0, len(j.EpochMetrics))
+	for epoch := int32(0); epoch < j.Epochs; epoch++ {
+		m := j.epochMetrics(epoch)
+		if m == nil {
+			continue
+		}
+		out = append(out, &orchestratorpb.EpochMetrics{
+			Epoch:          m.Epoch,
+			Loss:           m.Loss,
+			Accuracy:       m.Accuracy,
+			CompletedTasks: int32(m.CompletedTasks),
+			Complete:       j.epochSettled(m.Epoch),
+		})
+	}
+	return out
+}
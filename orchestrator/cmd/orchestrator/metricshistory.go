@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	orchestratorpb "github.com/tensorfleet/orchestrator/proto/orchestrator"
+)
+
+// Kinds of metric samples kept in a job's history.
+const (
+	MetricSampleTask       = "task"
+	MetricSampleEpoch      = "epoch"
+	MetricSampleValidation = "validation" // An epoch's validation, see validation.go
+)
+
+// MetricSample is one point of a job's training curve.
+type MetricSample struct {
+	Kind      string  `json:"kind"`
+	Epoch     int32   `json:"epoch"`
+	TaskID    string  `json:"task_id,omitempty"`
+	Loss      float64 `json:"loss"`
+	Accuracy  float64 `json:"accuracy"`
+	Timestamp int64   `json:"timestamp"` // Unix milliseconds
+}
+
+// appendMetricSample adds a sample to the job's metric history.
+func (s *OrchestratorServer) appendMetricSample(jobID string, sample MetricSample) {
+	sample.Timestamp = time.Now().UnixMilli()
+	s.writes.queue("record metric sample for job "+jobID, func(ctx context.Context) error {
+		return s.store.AppendMetricSample(ctx, jobID, sample)
+	})
+}
+
+// recordEpochSample adds the epoch's aggregate metrics to the history once
+// the epoch has settled. Caller must hold s.mu.
+func (s *OrchestratorServer) recordEpochSample(job *Job, epoch int32) {
+	m := job.epochMetrics(epoch)
+	if m == nil {
+		return
+	}
+
+	s.appendMetricSample(job.JobID, MetricSample{
+		Kind:     MetricSampleEpoch,
+		Epoch:    epoch,
+		Loss:     m.Loss,
+		Accuracy: m.Accuracy,
+	})
+}
+
+func (s *OrchestratorServer) GetJobMetricsHistory(ctx context.Context, req *orchestratorpb.JobMetricsHistoryRequest) (*orchestratorpb.JobMetricsHistoryResponse, error) {
+	if req.Kind != "" && req.Kind != MetricSampleTask && req.Kind != MetricSampleEpoch && req.Kind != MetricSampleValidation {
+		return nil, fmt.Errorf("unknown metric sample kind: %s", req.Kind)
+	}
+
+	stored, err := s.store.ListMetricSamples(ctx, req.JobId, req.SinceUnixMs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load metrics history: %v", err)
+	}
+
+	samples := make([]*orchestratorpb.MetricSample, 0, len(stored))
+	for _, sample := range stored {
+		if req.Kind != "" && sample.Kind != req.Kind {
+			continue
+		}
+		samples = append(samples, &orchestratorpb.MetricSample{
+			Kind:        sample.Kind,
+			Epoch:       sample.Epoch,
+			TaskId:      sample.TaskID,
+			Loss:        sample.Loss,
+			Accuracy:    sample.Accuracy,
+			TimestampMs: sample.Timestamp,
+		})
+	}
+
+	return &orchestratorpb.JobMetricsHistoryResponse{
+		JobId:   req.JobId,
+		Samples: samples,
+	}, nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Namespaces group jobs by project for teams sharing one deployment. Every
+// job belongs to exactly one, DefaultNamespace unless its request names
+// another. Job listings can be filtered by namespace, and workers labelled
+// namespace=<name> form a dedicated pool that only runs that namespace's
+// jobs; unlabelled workers serve every namespace.
+const (
+	DefaultNamespace = "default"
+	namespaceLabel   = "namespace"
+)
+
+// Namespace names follow Kubernetes DNS labels so they can be reused for
+// worker pools and RBAC.
+var namespacePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,61}[a-z0-9])?$`)
+
+// normalizeNamespace validates a requested namespace, mapping empty to
+// DefaultNamespace.
+func normalizeNamespace(name string) (string, error) {
+	if name == "" {
+		return DefaultNamespace, nil
+	}
+	if !namespacePattern.MatchString(name) {
+		return "", fmt.Errorf("invalid namespace %q: use at most 63 lowercase letters, digits and dashes", name)
+	}
+	return name, nil
+}
+
+// namespace returns the job's namespace. Jobs saved before namespaces
+// existed belong to the default one.
+func (j *Job) namespace() string {
+	if j.Namespace == "" {
+		return DefaultNamespace
+	}
+	return j.Namespace
+}
+
+// servesNamespace reports whether a worker with the given capabilities may
+// run jobs of the namespace: it is either shared or in that namespace's pool.
+func (c WorkerCapabilities) servesNamespace(namespace string) bool {
+	pool, dedicated := c.Labels[namespaceLabel]
+	if !dedicated {
+		return true
+	}
+	if namespace == "" {
+		namespace = DefaultNamespace
+	}
+	return pool == namespace
+}
+
+// pool returns the namespace whose dedicated pool the worker is in, or ""
+// if it is shared.
+func (c WorkerCapabilities) pool() string {
+	return c.Labels[namespaceLabel]
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Buckets the orchestrator writes weights to; the storage service creates
+// them on startup.
+const (
+	modelsBucket      = "models"
+	checkpointsBucket = "checkpoints"
+)
+
+// snapshotsBucket holds control-plane snapshots; the orchestrator creates it
+// on first use.
+const snapshotsBucket = "snapshots"
+
+// ObjectStore writes model weights straight to S3-compatible storage such as
+// MinIO, so large weights do not pass through the storage service, which
+// then only records metadata pointing at the object. Objects are content
+// addressed: the key is the SHA-256 of the data, so identical weights are
+// stored once.
+type ObjectStore struct {
+	endpoint  string // host:port
+	accessKey string
+	secretKey string
+	region    string
+	secure    bool
+	client    *http.Client
+}
+
+// newObjectStore reads the same MINIO_* settings as the storage service. It
+// returns nil unless MINIO_ENDPOINT is set, in which case weights are
+// uploaded through the storage service instead.
+func newObjectStore() *ObjectStore {
+	endpoint := os.Getenv("MINIO_ENDPOINT")
+	if endpoint == "" {
+		return nil
+	}
+
+	store := &ObjectStore{
+		endpoint:  endpoint,
+		accessKey: os.Getenv("MINIO_ACCESS_KEY"),
+		secretKey: os.Getenv("MINIO_SECRET_KEY"),
+		region:    os.Getenv("MINIO_REGION"),
+		secure:    strings.ToLower(os.Getenv("MINIO_SECURE")) == "true",
+		client:    &http.Client{Timeout: 60 * time.Second},
+	}
+	if store.accessKey == "" {
+		store.accessKey = "minioadmin"
+	}
+	if store.secretKey == "" {
+		store.secretKey = "minioadmin"
+	}
+	if store.region == "" {
+		store.region = "us-east-1"
+	}
+
+	log.Printf("Writing model weights to object store at %s", endpoint)
+	return store
+}
+
+func (o *ObjectStore) objectURL(bucket, key string) string {
+	scheme := "http"
+	if o.secure {
+		scheme = "https"
+	}
+	if key == "" {
+		return fmt.Sprintf("%s://%s/%s", scheme, o.endpoint, bucket)
+	}
+	return fmt.Sprintf("%s://%s/%s/%s", scheme, o.endpoint, bucket, key)
+}
+
+// PutContent stores data under a key derived from its SHA-256 unless an
+// object with that key already exists. It returns the key and the hex
+// digest.
+func (o *ObjectStore) PutContent(ctx context.Context, bucket string, data []byte) (key, digest string, err error) {
+	sum := sha256.Sum256(data)
+	digest = hex.EncodeToString(sum[:])
+	key = "weights/" + digest
+
+	exists, err := o.exists(ctx, bucket, key)
+	if err != nil {
+		return "", "", err
+	}
+	if !exists {
+		if err := o.put(ctx, bucket, key, data, digest); err != nil {
+			return "", "", err
+		}
+	}
+	return key, digest, nil
+}
+
+// Put stores data under the given key, replacing any object there.
+func (o *ObjectStore) Put(ctx context.Context, bucket, key string, data []byte) error {
+	sum := sha256.Sum256(data)
+	return o.put(ctx, bucket, key, data, hex.EncodeToString(sum[:]))
+}
+
+// Get returns the object stored under the key.
+func (o *ObjectStore) Get(ctx context.Context, bucket, key string) ([]byte, error) {
+	resp, err := o.do(ctx, http.MethodGet, bucket, key, nil, emptyPayloadHash)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("object store returned status %d for %s/%s", resp.StatusCode, bucket, key)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// EnsureBucket creates the bucket unless it exists.
+func (o *ObjectStore) EnsureBucket(ctx context.Context, bucket string) error {
+	exists, err := o.exists(ctx, bucket, "")
+	if err != nil || exists {
+		return err
+	}
+	resp, err := o.do(ctx, http.MethodPut, bucket, "", nil, emptyPayloadHash)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	// Another replica may have created it in the meantime
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusConflict {
+		return fmt.Errorf("object store returned status %d creating bucket %s", resp.StatusCode, bucket)
+	}
+	return nil
+}
+
+func (o *ObjectStore) exists(ctx context.Context, bucket, key string) (bool, error) {
+	resp, err := o.do(ctx, http.MethodHead, bucket, key, nil, emptyPayloadHash)
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("object store returned status %d for %s/%s", resp.StatusCode, bucket, key)
+	}
+}
+
+func (o *ObjectStore) put(ctx context.Context, bucket, key string, data []byte, digest string) error {
+	resp, err := o.do(ctx, http.MethodPut, bucket, key, data, digest)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("object store returned status %d for %s/%s", resp.StatusCode, bucket, key)
+	}
+	return nil
+}
+
+// emptyPayloadHash is the SHA-256 of an empty body.
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+func (o *ObjectStore) do(ctx context.Context, method, bucket, key string, data []byte, payloadHash string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, o.objectURL(bucket, key), bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if data != nil {
+		req.Header.Set("Content-Type", "application/octet-stream")
+	}
+	o.sign(req, payloadHash, time.Now().UTC())
+	return o.client.Do(req)
+}
+
+// sign adds an AWS Signature Version 4 to the request.
+func (o *ObjectStore) sign(req *http.Request, payloadHash string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+
+	const signedHeaders = "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		"host:" + req.URL.Host,
+		"x-amz-content-sha256:" + payloadHash,
+		"x-amz-date:" + amzDate,
+		"",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + o.region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	signingKey := hmacSHA256([]byte("AWS4"+o.secretKey), date)
+	for _, part := range []string{o.region, "s3", "aws4_request"} {
+		signingKey = hmacSHA256(signingKey, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		o.accessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
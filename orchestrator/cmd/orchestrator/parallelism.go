@@ -0,0 +1,49 @@
+package main
+
+// A job may cap how many of its tasks run at once with max_parallel_tasks,
+// e.g. when it reads from a rate-limited data source. The cap holds however
+// much capacity the cluster has; 0 leaves the job uncapped.
+
+// inFlightTasks is the number of the job's tasks leased to workers.
+func (j *Job) inFlightTasks() int {
+	n := 0
+	for _, task := range j.Tasks {
+		if task.Status == TaskStatusAssigned {
+			n++
+		}
+	}
+	return n
+}
+
+// parallelismHeadroom is how many of the given waiting tasks the job may
+// start now. Caller must hold s.mu.
+func (j *Job) parallelismHeadroom(waiting int) int {
+	if j.MaxParallelTasks <= 0 {
+		return waiting
+	}
+	free := int(j.MaxParallelTasks) - j.inFlightTasks()
+	if free < 0 {
+		free = 0
+	}
+	if waiting < free {
+		return waiting
+	}
+	return free
+}
+
+// parallelismGate checks parallelism caps during one pass over the task
+// queue, counting each capped job's in-flight tasks once.
+type parallelismGate map[*Job]int
+
+// allows reports whether another of the job's tasks may be leased.
+func (g parallelismGate) allows(job *Job) bool {
+	if job.MaxParallelTasks <= 0 {
+		return true
+	}
+	n, ok := g[job]
+	if !ok {
+		n = job.inFlightTasks()
+		g[job] = n
+	}
+	return n < int(job.MaxParallelTasks)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	orchestratorpb "github.com/tensorfleet/orchestrator/proto/orchestrator"
+)
+
+// PauseJob stops handing out a running job's tasks. Tasks already on workers
+// finish normally; the job keeps its admission slot and continues where it
+// left off when resumed with ResumeJob.
+func (s *OrchestratorServer) PauseJob(ctx context.Context, req *orchestratorpb.PauseJobRequest) (*orchestratorpb.PauseJobResponse, error) {
+	if region := s.delegatedRegion(ctx, req.JobId); region != "" {
+		return s.pauseDelegatedJob(ctx, req.JobId, region)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, exists := s.jobs[req.JobId]
+	if !exists {
+		return &orchestratorpb.PauseJobResponse{
+			Success: false,
+			Message: fmt.Sprintf("Job not found: %s", req.JobId),
+		}, nil
+	}
+
+	previousStatus := job.Status
+	if err := job.transition(JobStatusPaused, "Paused by user"); err != nil {
+		return &orchestratorpb.PauseJobResponse{
+			Success:        false,
+			Message:        fmt.Sprintf("Cannot pause job with status: %s", job.Status),
+			PreviousStatus: string(previousStatus),
+		}, nil
+	}
+	// A gang job keeps its workers so it can resume without queueing again
+	if !job.GangScheduling {
+		job.dropReservations()
+	}
+
+	if err := s.saveJob(ctx, job); err != nil {
+		log.Printf("Warning: Failed to save job: %v", err)
+	}
+	log.Printf("Job %s paused (previous status: %s)", job.JobID, previousStatus)
+
+	return &orchestratorpb.PauseJobResponse{
+		Success:        true,
+		Message:        fmt.Sprintf("Job %s has been paused", job.JobID),
+		PreviousStatus: string(previousStatus),
+	}, nil
+}
+
+// unpauseJob lets a paused job dispatch tasks again. Caller must hold s.mu.
+func (s *OrchestratorServer) unpauseJob(ctx context.Context, job *Job) *orchestratorpb.ResumeJobResponse {
+	if !job.Requirements.IsZero() && !s.hasCapableWorker(job.Requirements) {
+		job.mustTransition(JobStatusPendingResources, noCapableWorkerReason)
+	} else {
+		job.mustTransition(JobStatusRunning, "")
+	}
+	// Time spent paused does not count against the progress timeout
+	job.LastProgressAt = time.Now()
+	s.taskQueue.signal()
+
+	// Tasks that finished while paused may have settled the job
+	s.completeJobIfSettled(job)
+
+	if err := s.saveJob(ctx, job); err != nil {
+		log.Printf("Warning: Failed to save job: %v", err)
+	}
+	log.Printf("Job %s unpaused", job.JobID)
+
+	return &orchestratorpb.ResumeJobResponse{
+		Success:        true,
+		Message:        fmt.Sprintf("Job %s resumed", job.JobID),
+		PreviousStatus: string(JobStatusPaused),
+		ResumeEpoch:    job.CurrentEpoch,
+	}
+}
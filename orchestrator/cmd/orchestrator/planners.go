@@ -0,0 +1,286 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Task planners, selected by model_type.
+const (
+	PlannerEpochBatch   = "epoch-batch"
+	PlannerTrees        = "trees"
+	PlannerBoostedTrees = "boosted-trees"
+	PlannerEmbeddings   = "embedding-shards"
+)
+
+// Defaults for tree ensembles and sharded embedding tables whose
+// hyperparameters leave the decomposition open.
+const (
+	defaultNumTrees        = 100
+	defaultTreesPerTask    = 10
+	defaultEmbeddingShards = 4
+)
+
+// TaskPlanner decomposes a job into tasks. The batch layout has already been
+// validated against the dataset; how it is used is up to the planner. A
+// planner may change the job's Epochs and SyncEpochs where its decomposition
+// requires it, and may attach Params to tasks, which workers receive merged
+// into the job's hyperparameters.
+//
+// To support a new kind of model, implement TaskPlanner and register it for
+// its model types with RegisterTaskPlanner from an init function. Model types
+// without a planner are split into epochs × batches.
+type TaskPlanner interface {
+	Name() string
+	Plan(job *Job, layout shardPlan) ([]*Task, error)
+}
+
+var (
+	plannersMu sync.RWMutex
+	planners   = make(map[string]TaskPlanner)
+)
+
+// RegisterTaskPlanner makes the planner build the tasks of jobs with any of
+// the given model types, replacing any planner registered for them before.
+func RegisterTaskPlanner(p TaskPlanner, modelTypes ...string) {
+	plannersMu.Lock()
+	defer plannersMu.Unlock()
+	for _, modelType := range modelTypes {
+		planners[modelType] = p
+	}
+}
+
+// plannerFor returns the planner registered for the model type, or the
+// epoch × batch planner.
+func plannerFor(modelType string) TaskPlanner {
+	plannersMu.RLock()
+	defer plannersMu.RUnlock()
+	if p, ok := planners[modelType]; ok {
+		return p
+	}
+	return EpochBatchPlanner{}
+}
+
+func init() {
+	RegisterTaskPlanner(TreeEnsemblePlanner{}, "random_forest", "decision_tree")
+	RegisterTaskPlanner(TreeEnsemblePlanner{Boosted: true}, "xgboost", "lightgbm", "gradient_boosting")
+	RegisterTaskPlanner(EmbeddingShardPlanner{}, "embedding", "word2vec", "matrix_factorization")
+}
+
+// createTasks applies the batch layout and has the planner for the job's
+// model type build its tasks.
+func (j *Job) createTasks(plan shardPlan) error {
+	j.BatchesPerEpoch = plan.BatchesPerEpoch
+	j.SamplesPerBatch = plan.SamplesPerBatch
+	j.DatasetSize = plan.DatasetSize
+
+	planner := plannerFor(j.ModelType)
+	tasks, err := planner.Plan(j, plan)
+	if err != nil {
+		return fmt.Errorf("%s planner: %v", planner.Name(), err)
+	}
+	if len(tasks) == 0 {
+		return fmt.Errorf("%s planner produced no tasks", planner.Name())
+	}
+	if maxTasksPerJob > 0 && len(tasks) > maxTasksPerJob {
+		return fmt.Errorf("job would create %d tasks, more than the limit of %d", len(tasks), maxTasksPerJob)
+	}
+	j.Tasks = tasks
+	j.addStageTasks(plan)
+	j.TotalTasks = len(j.Tasks)
+	return nil
+}
+
+// newTask returns a pending task of the job.
+func (j *Job) newTask(epoch, start, end int32, now time.Time) *Task {
+	return &Task{
+		TaskID:     uuid.New().String(),
+		JobID:      j.JobID,
+		Status:     TaskStatusPending,
+		Epoch:      epoch,
+		BatchStart: start,
+		BatchEnd:   end,
+		CreatedAt:  now,
+	}
+}
+
+// batchBounds returns the sample offsets of a batch. The last batch stops at
+// the end of the dataset when its size is known.
+func batchBounds(layout shardPlan, batch int32) (int32, int32) {
+	start := batch * layout.SamplesPerBatch
+	end := start + layout.SamplesPerBatch
+	if layout.DatasetSize > 0 && int64(end) > layout.DatasetSize {
+		end = int32(layout.DatasetSize)
+	}
+	return start, end
+}
+
+// datasetEnd is the sample offset one past the last sample the layout covers.
+func datasetEnd(layout shardPlan) int32 {
+	if layout.DatasetSize > 0 {
+		return int32(layout.DatasetSize)
+	}
+	return layout.BatchesPerEpoch * layout.SamplesPerBatch
+}
+
+// intHyperparameter reads a positive integer hyperparameter, falling back to
+// def when it is not set.
+func intHyperparameter(job *Job, name string, def int) (int, error) {
+	value, ok := job.Hyperparameters[name]
+	if !ok || value == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("hyperparameter %s must be a positive integer, got %q", name, value)
+	}
+	return n, nil
+}
+
+// hyperparameters returns what a worker is sent for the task: the job's
+// hyperparameters with the task's planner params on top.
+func (t *Task) hyperparameters(job *Job) map[string]string {
+	if len(t.Params) == 0 {
+		return job.Hyperparameters
+	}
+	merged := make(map[string]string, len(job.Hyperparameters)+len(t.Params))
+	for k, v := range job.Hyperparameters {
+		merged[k] = v
+	}
+	for k, v := range t.Params {
+		merged[k] = v
+	}
+	return merged
+}
+
+// EpochBatchPlanner builds one task per batch per epoch, for models trained
+// by gradient descent over minibatches such as CNNs and transformers.
+type EpochBatchPlanner struct{}
+
+func (EpochBatchPlanner) Name() string { return PlannerEpochBatch }
+
+func (EpochBatchPlanner) Plan(job *Job, layout shardPlan) ([]*Task, error) {
+	now := time.Now()
+	tasks := make([]*Task, 0, int(job.Epochs)*int(layout.BatchesPerEpoch))
+	for epoch := int32(0); epoch < job.Epochs; epoch++ {
+		for batch := int32(0); batch < layout.BatchesPerEpoch; batch++ {
+			start, end := batchBounds(layout, batch)
+			tasks = append(tasks, job.newTask(epoch, start, end, now))
+		}
+	}
+	return tasks, nil
+}
+
+// TreeEnsemblePlanner splits tree ensembles by trees rather than by batches;
+// the ensemble size is the n_estimators hyperparameter.
+//
+// Bagged ensembles such as random forests grow independent trees, so the job
+// runs as a single epoch in which each task grows trees_per_task trees over
+// the whole dataset (tree_start and num_trees params).
+//
+// Boosted ensembles fit each tree to the residuals of the ones before it, so
+// every tree is an epoch of its own (tree_index param), split into batches
+// that each contribute split statistics, and epochs are synchronised.
+type TreeEnsemblePlanner struct {
+	Boosted bool
+}
+
+func (p TreeEnsemblePlanner) Name() string {
+	if p.Boosted {
+		return PlannerBoostedTrees
+	}
+	return PlannerTrees
+}
+
+func (p TreeEnsemblePlanner) Plan(job *Job, layout shardPlan) ([]*Task, error) {
+	numTrees, err := intHyperparameter(job, "n_estimators", defaultNumTrees)
+	if err != nil {
+		return nil, err
+	}
+	if maxTasksPerJob > 0 && numTrees > maxTasksPerJob {
+		return nil, fmt.Errorf("n_estimators %d is more than the limit of %d tasks", numTrees, maxTasksPerJob)
+	}
+
+	now := time.Now()
+	if p.Boosted {
+		if maxTasksPerJob > 0 && numTrees*int(layout.BatchesPerEpoch) > maxTasksPerJob {
+			return nil, fmt.Errorf("%d trees of %d batches is more than the limit of %d tasks",
+				numTrees, layout.BatchesPerEpoch, maxTasksPerJob)
+		}
+		job.Epochs = int32(numTrees)
+		job.SyncEpochs = true
+		tasks := make([]*Task, 0, numTrees*int(layout.BatchesPerEpoch))
+		for tree := int32(0); tree < job.Epochs; tree++ {
+			for batch := int32(0); batch < layout.BatchesPerEpoch; batch++ {
+				start, end := batchBounds(layout, batch)
+				task := job.newTask(tree, start, end, now)
+				task.Params = map[string]string{"tree_index": strconv.Itoa(int(tree))}
+				tasks = append(tasks, task)
+			}
+		}
+		return tasks, nil
+	}
+
+	perTask, err := intHyperparameter(job, "trees_per_task", defaultTreesPerTask)
+	if err != nil {
+		return nil, err
+	}
+	job.Epochs = 1
+	end := datasetEnd(layout)
+	var tasks []*Task
+	for first := 0; first < numTrees; first += perTask {
+		count := perTask
+		if first+count > numTrees {
+			count = numTrees - first
+		}
+		task := job.newTask(0, 0, end, now)
+		task.Params = map[string]string{
+			"tree_start": strconv.Itoa(first),
+			"num_trees":  strconv.Itoa(count),
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// EmbeddingShardPlanner splits a large embedding table instead of the data:
+// each epoch has one task per table shard, covering the whole dataset and
+// updating only its shard's rows (shard_index and num_shards params). The
+// number of shards is the embedding_shards hyperparameter, or num_workers.
+type EmbeddingShardPlanner struct{}
+
+func (EmbeddingShardPlanner) Name() string { return PlannerEmbeddings }
+
+func (EmbeddingShardPlanner) Plan(job *Job, layout shardPlan) ([]*Task, error) {
+	def := defaultEmbeddingShards
+	if job.NumWorkers > 0 {
+		def = int(job.NumWorkers)
+	}
+	shards, err := intHyperparameter(job, "embedding_shards", def)
+	if err != nil {
+		return nil, err
+	}
+	if maxTasksPerJob > 0 && shards*int(job.Epochs) > maxTasksPerJob {
+		return nil, fmt.Errorf("%d shards over %d epochs is more than the limit of %d tasks",
+			shards, job.Epochs, maxTasksPerJob)
+	}
+
+	now := time.Now()
+	end := datasetEnd(layout)
+	tasks := make([]*Task, 0, shards*int(job.Epochs))
+	for epoch := int32(0); epoch < job.Epochs; epoch++ {
+		for shard := 0; shard < shards; shard++ {
+			task := job.newTask(epoch, 0, end, now)
+			task.Params = map[string]string{
+				"shard_index": strconv.Itoa(shard),
+				"num_shards":  strconv.Itoa(shards),
+			}
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks, nil
+}
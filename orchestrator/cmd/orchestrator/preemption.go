@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+)
+
+// Priority preemption. When the admission limits leave no room for a queued
+// job of at least preemptionMinPriority, running jobs of lower priority are
+// sent back to the admission queue, lowest priority first, until it fits.
+// Their queued tasks wait until they are admitted again; with
+// preemptInFlight their tasks on workers are cancelled and requeued too,
+// otherwise those finish normally. A preemptionMinPriority of 0 disables
+// preemption.
+var (
+	preemptionMinPriority = getEnvInt("PREEMPTION_MIN_PRIORITY", 0)
+	preemptInFlight       = os.Getenv("PREEMPT_IN_FLIGHT") == "true"
+)
+
+// canPreempt reports whether the job may preempt lower-priority work.
+func (j *Job) canPreempt() bool {
+	return preemptionMinPriority > 0 && int(j.Priority) >= preemptionMinPriority
+}
+
+// preemptionCandidates lists the running jobs of lower priority than the
+// given one in the order they would be preempted: lowest priority first,
+// most recently started first on ties. Paused jobs are left alone. Caller
+// must hold s.mu.
+func (s *OrchestratorServer) preemptionCandidates(incoming *Job) []*Job {
+	var candidates []*Job
+	for _, job := range s.jobs {
+		if (job.isRunning() || job.Status == JobStatusPendingResources) && job.Priority < incoming.Priority {
+			candidates = append(candidates, job)
+		}
+	}
+	sort.Slice(candidates, func(i, k int) bool {
+		a, b := candidates[i], candidates[k]
+		if a.Priority != b.Priority {
+			return a.Priority < b.Priority
+		}
+		return a.StartedAt.After(b.StartedAt)
+	})
+	return candidates
+}
+
+// preemptJob sends a running job back to the admission queue to make room
+// for a higher-priority one. Caller must hold s.mu.
+func (s *OrchestratorServer) preemptJob(victim, by *Job) {
+	reason := fmt.Sprintf("Preempted by job %s (priority %d)", by.JobID, by.Priority)
+	if !victim.mustTransition(JobStatusQueued, reason) {
+		return
+	}
+	victim.dropReservations()
+
+	requeued := 0
+	if preemptInFlight {
+		for _, task := range victim.Tasks {
+			if task.Status != TaskStatusAssigned {
+				continue
+			}
+			s.recordEvent(victim.JobID, JobEvent{
+				Type:     EventTaskPreempted,
+				Message:  fmt.Sprintf("Epoch %d task preempted by job %s", task.Epoch, by.JobID),
+				TaskID:   task.TaskID,
+				WorkerID: task.WorkerID,
+			})
+			// Preemption is not the task's fault; it keeps its retries
+			s.reclaimTask(victim, task, "preempted by job "+by.JobID)
+			requeued++
+		}
+	}
+
+	s.admissionQueue = append(s.admissionQueue, victim)
+	s.sortAdmissionQueue()
+
+	log.Printf("Preempted job %s (priority %d) for job %s (priority %d), %d in-flight tasks requeued",
+		victim.JobID, victim.Priority, by.JobID, by.Priority, requeued)
+	s.recordEvent(victim.JobID, JobEvent{Type: EventJobPreempted, Message: reason})
+	s.recordEvent(by.JobID, JobEvent{
+		Type:    EventJobPreempted,
+		Message: fmt.Sprintf("Preempted job %s (priority %d) to make room", victim.JobID, victim.Priority),
+	})
+
+	if err := s.saveJob(context.Background(), victim); err != nil {
+		log.Printf("Warning: Failed to save job: %v", err)
+	}
+}
+
+// preemptFor preempts lower-priority jobs until the given job fits within the
+// admission limits. Nothing is preempted unless that makes the job fit; it
+// returns whether it does. Caller must hold s.mu.
+func (s *OrchestratorServer) preemptFor(job *Job) bool {
+	if !job.canPreempt() {
+		return false
+	}
+
+	activeJobs, activeTasks := s.activeLoad()
+	var victims []*Job
+	for _, candidate := range s.preemptionCandidates(job) {
+		if admissionFits(activeJobs, activeTasks, job) {
+			break
+		}
+		victims = append(victims, candidate)
+		activeJobs--
+		activeTasks -= candidate.outstandingTasks()
+	}
+	if !admissionFits(activeJobs, activeTasks, job) {
+		return false
+	}
+
+	for _, victim := range victims {
+		s.preemptJob(victim, job)
+	}
+	return true
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	orchestratorpb "github.com/tensorfleet/orchestrator/proto/orchestrator"
+)
+
+// TaskProgress is how far a running task has got, as its worker last
+// reported.
+type TaskProgress struct {
+	BatchesDone  int32
+	BatchesTotal int32
+	Loss         float64 // Interim metrics over the batches done so far
+	Accuracy     float64
+	ReportedAt   time.Time
+}
+
+// fraction is the share of the task's batches done, between 0 and 1.
+func (p *TaskProgress) fraction() float64 {
+	if p == nil || p.BatchesTotal <= 0 {
+		return 0
+	}
+	return min(float64(p.BatchesDone)/float64(p.BatchesTotal), 1)
+}
+
+func (p *TaskProgress) toProto(task *Task) *orchestratorpb.TaskProgress {
+	return &orchestratorpb.TaskProgress{
+		TaskId:       task.TaskID,
+		WorkerId:     task.WorkerID,
+		BatchesDone:  p.BatchesDone,
+		BatchesTotal: p.BatchesTotal,
+		Loss:         p.Loss,
+		Accuracy:     p.Accuracy,
+		ReportedAtMs: unixMillis(p.ReportedAt),
+	}
+}
+
+// minProjectionFraction is how much of a task must be done before its
+// reported progress is trusted to project how long it will take; the first
+// batches are often slow while data loads and kernels warm up.
+const minProjectionFraction = 0.1
+
+// projectedDuration is how long the task's current attempt should take in
+// all, extrapolated from its reported progress, or the time it has run so
+// far when it has not reported enough progress to tell.
+func (t *Task) projectedDuration(now time.Time) time.Duration {
+	elapsed := now.Sub(*t.AssignedAt)
+	if f := t.Progress.fraction(); f >= minProjectionFraction {
+		return time.Duration(float64(elapsed) / f)
+	}
+	return elapsed
+}
+
+// detailedProgress is the share of the job's tasks done, in percent,
+// counting the reported progress of running tasks. Caller must hold s.mu.
+func (j *Job) detailedProgress() float64 {
+	if j.TotalTasks == 0 {
+		return 0
+	}
+	done := float64(j.CompletedTasks)
+	for _, task := range j.Tasks {
+		if task.Status == TaskStatusAssigned {
+			done += task.Progress.fraction()
+		}
+	}
+	return done / float64(j.TotalTasks) * 100
+}
+
+// runningTaskProgress lists the reported progress of the job's running
+// tasks. Caller must hold s.mu.
+func (j *Job) runningTaskProgress() []*orchestratorpb.TaskProgress {
+	var running []*orchestratorpb.TaskProgress
+	for _, task := range j.Tasks {
+		if task.Status == TaskStatusAssigned && task.Progress != nil {
+			running = append(running, task.Progress.toProto(task))
+		}
+	}
+	return running
+}
+
+// ReportTaskProgress records how far a running task has got. Reports are
+// not saved on their own: the latest reaches the job store with the job's
+// next change, and is cleared when the task is leased again. A worker
+// reporting on a task it no longer holds is told to stop it.
+func (s *OrchestratorServer) ReportTaskProgress(ctx context.Context, req *orchestratorpb.TaskProgressRequest) (*orchestratorpb.TaskProgressResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[req.JobId]
+	if !ok {
+		return &orchestratorpb.TaskProgressResponse{Stop: true, Message: "Unknown task"}, nil
+	}
+	task := job.findTask(req.TaskId)
+	if task == nil {
+		return &orchestratorpb.TaskProgressResponse{Stop: true, Message: "Unknown task"}, nil
+	}
+	if task.Status != TaskStatusAssigned || task.WorkerID != req.WorkerId {
+		return &orchestratorpb.TaskProgressResponse{
+			Stop:    true,
+			Message: "Task is not assigned to this worker",
+		}, nil
+	}
+
+	now := time.Now()
+	if req.Started {
+		// Held prefetched until now: the attempt's clock starts over
+		task.AssignedAt = &now
+		task.LeaseExpiresAt = now.Add(job.timeoutFor(task))
+	}
+	// Any report means the task runs, and its lease holds it from now on
+	task.Prefetched = false
+	task.Progress = &TaskProgress{
+		BatchesDone:  req.BatchesDone,
+		BatchesTotal: req.BatchesTotal,
+		Loss:         req.Loss,
+		Accuracy:     req.Accuracy,
+		ReportedAt:   now,
+	}
+	if worker, ok := s.workers[req.WorkerId]; ok {
+		worker.LastActivityTime = now
+	}
+	if s.flagStragglers(job, now) {
+		if err := s.saveJob(ctx, job); err != nil {
+			log.Printf("Warning: Failed to save job: %v", err)
+		}
+	} else {
+		s.notifyJobChanged(job.JobID)
+	}
+
+	return &orchestratorpb.TaskProgressResponse{Acknowledged: true}, nil
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Cluster events are published to Redis so the gateway, notifiers and the
+// autoscaler can react to changes without polling the orchestrator. Every
+// event is appended to the eventStreamKey stream, capped at roughly
+// eventStreamMaxLen entries, and published on the channel of its kind:
+// events:job, events:task, events:worker or, for operator actions,
+// events:admin. Publishing is best effort; events are dropped rather than
+// slow down scheduling when Redis falls behind.
+var (
+	eventPublishing   = os.Getenv("EVENT_PUBLISH") != "false"
+	eventStreamMaxLen = int64(getEnvInt("EVENT_STREAM_MAX_LEN", 10000))
+)
+
+const (
+	eventStreamKey     = "events"
+	eventChannelPrefix = "events:"
+	eventBufferSize    = 1024
+)
+
+// Cluster event types besides the job event types.
+const (
+	EventJobStatusChanged   = "JOB_STATUS_CHANGED"
+	EventTaskCompleted      = "TASK_COMPLETED"
+	EventWorkerRegistered   = "WORKER_REGISTERED"
+	EventWorkerDeregistered = "WORKER_DEREGISTERED"
+	EventWorkerOffline      = "WORKER_OFFLINE"
+	EventWorkerEvicted      = "WORKER_EVICTED"
+)
+
+// ClusterEvent is one published event.
+type ClusterEvent struct {
+	Type       string `json:"type"`
+	JobID      string `json:"job_id,omitempty"`
+	TaskID     string `json:"task_id,omitempty"`
+	WorkerID   string `json:"worker_id,omitempty"`
+	Status     string `json:"status,omitempty"`      // New status of a JOB_STATUS_CHANGED event
+	PrevStatus string `json:"prev_status,omitempty"` // Old status of a JOB_STATUS_CHANGED event
+	Message    string `json:"message,omitempty"`
+	Timestamp  int64  `json:"timestamp"` // Unix milliseconds
+}
+
+// kind is the channel the event is published on.
+func (e *ClusterEvent) kind() string {
+	switch {
+	case strings.HasPrefix(e.Type, "TASK_"):
+		return "task"
+	case strings.HasPrefix(e.Type, "WORKER_"):
+		return "worker"
+	case strings.HasPrefix(e.Type, "ADMIN_"):
+		return "admin"
+	default:
+		return "job"
+	}
+}
+
+// EventPublisher hands events to Redis from a background goroutine. A nil
+// publisher drops everything.
+type EventPublisher struct {
+	client *redis.Client
+	events chan ClusterEvent
+
+	mu        sync.Mutex
+	published map[string]int // Transitions published per active job
+}
+
+func newEventPublisher() *EventPublisher {
+	if !eventPublishing {
+		return nil
+	}
+
+	redisAddr := os.Getenv("REDIS_ADDR")
+	if redisAddr == "" {
+		redisAddr = "redis:6379"
+	}
+
+	return &EventPublisher{
+		client:    redis.NewClient(&redis.Options{Addr: redisAddr}),
+		events:    make(chan ClusterEvent, eventBufferSize),
+		published: make(map[string]int),
+	}
+}
+
+// publish queues an event without blocking.
+func (p *EventPublisher) publish(event ClusterEvent) {
+	if p == nil {
+		return
+	}
+	if event.Timestamp == 0 {
+		event.Timestamp = time.Now().UnixMilli()
+	}
+	select {
+	case p.events <- event:
+	default:
+		log.Printf("Warning: Event buffer full, dropping %s event", event.Type)
+	}
+}
+
+// publishTransitions publishes the job's status changes since the last call.
+// Only the latest change is published for a job not seen before, e.g. after
+// a restart.
+func (p *EventPublisher) publishTransitions(job *Job) {
+	if p == nil || len(job.Transitions) == 0 {
+		return
+	}
+
+	p.mu.Lock()
+	seen, ok := p.published[job.JobID]
+	if !ok {
+		seen = len(job.Transitions) - 1
+	}
+	if job.Status.IsTerminal() {
+		delete(p.published, job.JobID)
+	} else {
+		p.published[job.JobID] = len(job.Transitions)
+	}
+	p.mu.Unlock()
+
+	for _, t := range job.Transitions[seen:] {
+		p.publish(ClusterEvent{
+			Type:       EventJobStatusChanged,
+			JobID:      job.JobID,
+			Status:     string(t.To),
+			PrevStatus: string(t.From),
+			Message:    t.Reason,
+			Timestamp:  t.At.UnixMilli(),
+		})
+	}
+}
+
+// run sends queued events to Redis until ctx is done.
+func (p *EventPublisher) run(ctx context.Context) {
+	if p == nil {
+		return
+	}
+
+	failing := false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-p.events:
+			err := p.send(ctx, event)
+			// Log once per outage rather than once per event
+			if err != nil && !failing {
+				log.Printf("Warning: Failed to publish events: %v", err)
+			} else if err == nil && failing {
+				log.Println("Publishing events again")
+			}
+			failing = err != nil
+		}
+	}
+}
+
+func (p *EventPublisher) send(ctx context.Context, event ClusterEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	pipe := p.client.Pipeline()
+	pipe.XAdd(ctx, &redis.XAddArgs{
+		Stream: eventStreamKey,
+		MaxLen: eventStreamMaxLen,
+		Approx: true,
+		Values: map[string]interface{}{"type": event.Type, "event": data},
+	})
+	pipe.Publish(ctx, eventChannelPrefix+event.kind(), data)
+	_, err = pipe.Exec(ctx)
+	return err
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	orchestratorpb "github.com/tensorfleet/orchestrator/proto/orchestrator"
+)
+
+const WorkerStatusQuarantined = "QUARANTINED"
+
+// A worker that fails quarantineFailures tasks within quarantineWindow is
+// taken out of scheduling until an operator calls UnquarantineWorker.
+var (
+	quarantineFailures = getEnvInt("WORKER_QUARANTINE_FAILURES", 5)
+	quarantineWindow   = getEnvDuration("WORKER_QUARANTINE_WINDOW", 10*time.Minute)
+)
+
+// workerQuarantine is a worker's recent task failures and whether they got
+// it quarantined. It is kept by worker ID apart from the worker's entry, so a
+// worker evicted while quarantined is still quarantined when it registers
+// again.
+type workerQuarantine struct {
+	RecentFailures []time.Time
+	Quarantined    bool
+	Reason         string
+}
+
+// workerQuarantine returns the quarantine record of a worker, creating it
+// on first sight. Caller must hold s.mu.
+func (s *OrchestratorServer) workerQuarantine(workerID string) *workerQuarantine {
+	q, ok := s.quarantine[workerID]
+	if !ok {
+		q = &workerQuarantine{}
+		s.quarantine[workerID] = q
+	}
+	return q
+}
+
+// sweepQuarantine drops the records of workers that are gone unless they
+// are quarantined or have failures that still count. Caller must hold s.mu.
+func (s *OrchestratorServer) sweepQuarantine(now time.Time) {
+	for id, q := range s.quarantine {
+		if _, ok := s.workers[id]; !ok && !q.Quarantined && !q.recentlyFailed(now) {
+			delete(s.quarantine, id)
+		}
+	}
+}
+
+// recentlyFailed reports whether any failure still counts towards
+// quarantine.
+func (q *workerQuarantine) recentlyFailed(now time.Time) bool {
+	for _, at := range q.RecentFailures {
+		if now.Sub(at) <= quarantineWindow {
+			return true
+		}
+	}
+	return false
+}
+
+// schedulable reports whether the worker may be given tasks.
+func (w *WorkerActivity) schedulable() bool {
+	return !w.quarantine.Quarantined && !w.Draining && w.Status != WorkerStatusOffline
+}
+
+// displayStatus is the status shown in worker activity views.
+func (w *WorkerActivity) displayStatus() string {
+	if w.quarantine.Quarantined {
+		return WorkerStatusQuarantined
+	}
+	if w.Draining {
+		return WorkerStatusDraining
+	}
+	return w.Status
+}
+
+// recordWorkerFailure counts a failed or abandoned task against a worker and
+// quarantines it once it crosses the threshold. Caller must hold s.mu.
+func (s *OrchestratorServer) recordWorkerFailure(workerID, reason string) {
+	q := s.workerQuarantine(workerID)
+	if q.Quarantined || quarantineFailures <= 0 {
+		return
+	}
+
+	now := time.Now()
+	recent := q.RecentFailures[:0]
+	for _, at := range q.RecentFailures {
+		if now.Sub(at) <= quarantineWindow {
+			recent = append(recent, at)
+		}
+	}
+	q.RecentFailures = append(recent, now)
+
+	if len(q.RecentFailures) < quarantineFailures {
+		return
+	}
+
+	q.Quarantined = true
+	q.Reason = fmt.Sprintf("%d task failures within %s, last: %s",
+		len(q.RecentFailures), quarantineWindow, reason)
+	s.dropWorkerReservations(workerID)
+	s.refreshResourceAvailability()
+	log.Printf("Worker %s QUARANTINED: %s", workerID, q.Reason)
+}
+
+func (s *OrchestratorServer) UnquarantineWorker(ctx context.Context, req *orchestratorpb.UnquarantineWorkerRequest) (*orchestratorpb.UnquarantineWorkerResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q, ok := s.quarantine[req.WorkerId]
+	if !ok {
+		if _, ok := s.workers[req.WorkerId]; !ok {
+			return &orchestratorpb.UnquarantineWorkerResponse{
+				Success: false,
+				Message: fmt.Sprintf("Worker not found: %s", req.WorkerId),
+			}, nil
+		}
+	}
+	if !ok || !q.Quarantined {
+		return &orchestratorpb.UnquarantineWorkerResponse{
+			Success: false,
+			Message: fmt.Sprintf("Worker %s is not quarantined", req.WorkerId),
+		}, nil
+	}
+
+	q.Quarantined = false
+	q.Reason = ""
+	q.RecentFailures = nil
+	s.refreshResourceAvailability()
+
+	log.Printf("Worker %s released from quarantine", req.WorkerId)
+
+	return &orchestratorpb.UnquarantineWorkerResponse{
+		Success: true,
+		Message: fmt.Sprintf("Worker %s released from quarantine", req.WorkerId),
+	}, nil
+}
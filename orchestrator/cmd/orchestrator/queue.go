@@ -0,0 +1,333 @@
+package main
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+// queueAgingInterval is how long a job has to wait to gain one priority
+// level, so low-priority work is never starved by a steady stream of
+// high-priority submissions.
+var queueAgingInterval = getEnvDuration("TASK_QUEUE_AGING", 30*time.Second)
+
+// TaskQueue is the orchestrator's pending-task queue. Tasks are ordered by
+// their job's priority (higher first) plus aging, then by job submission time.
+// Each namespace has a queue of its own, so a worker in a namespace's
+// dedicated pool looks only at that namespace's tasks.
+type TaskQueue struct {
+	mu         sync.Mutex
+	queues     map[string]*taskHeap // By namespace
+	queued     map[*Task]struct{}   // Tasks in the queues
+	seq        uint64
+	aging      time.Duration
+	ready      chan struct{}        // Signalled when items may be available
+	tombstones map[string]time.Time // Jobs whose tasks are dropped instead of handed out, by when
+}
+
+type queuedTask struct {
+	task        *Task
+	namespace   string
+	score       float64
+	submittedAt time.Time
+	seq         uint64
+}
+
+func NewTaskQueue(aging time.Duration) *TaskQueue {
+	return &TaskQueue{
+		aging:      aging,
+		queues:     make(map[string]*taskHeap),
+		queued:     make(map[*Task]struct{}),
+		ready:      make(chan struct{}, 1),
+		tombstones: make(map[string]time.Time),
+	}
+}
+
+// reset empties the queue and forgets its tombstones.
+func (q *TaskQueue) reset() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.queues = make(map[string]*taskHeap)
+	q.queued = make(map[*Task]struct{})
+	q.tombstones = make(map[string]time.Time)
+}
+
+// Push enqueues a task on behalf of a job of the namespace with the given
+// priority and submission time. A task already queued keeps its place; Push
+// reports whether the task was added.
+func (q *TaskQueue) Push(task *Task, namespace string, priority int32, submittedAt time.Time) bool {
+	q.mu.Lock()
+	if _, ok := q.queued[task]; ok {
+		q.mu.Unlock()
+		return false
+	}
+	q.seq++
+	q.push(&queuedTask{
+		task:        task,
+		namespace:   namespace,
+		score:       q.score(priority, submittedAt),
+		submittedAt: submittedAt,
+		seq:         q.seq,
+	})
+	q.mu.Unlock()
+
+	q.signal()
+	return true
+}
+
+// Queued reports whether the task is in the queue.
+func (q *TaskQueue) Queued(task *Task) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	_, ok := q.queued[task]
+	return ok
+}
+
+// push adds an item to its namespace's queue. Caller must hold q.mu.
+func (q *TaskQueue) push(item *queuedTask) {
+	q.queued[item.task] = struct{}{}
+	items, ok := q.queues[item.namespace]
+	if !ok {
+		items = &taskHeap{}
+		q.queues[item.namespace] = items
+	}
+	heap.Push(items, item)
+}
+
+// pop removes the highest-priority item of the namespace's queue, or of all
+// queues if namespace is empty. It returns nil if there is none. Caller must
+// hold q.mu.
+func (q *TaskQueue) pop(namespace string) *queuedTask {
+	var best *taskHeap
+	if namespace != "" {
+		best = q.queues[namespace]
+	} else {
+		for _, items := range q.queues {
+			if items.Len() > 0 && (best == nil || queuedAhead((*items)[0], (*best)[0])) {
+				best = items
+			}
+		}
+	}
+	if best == nil || best.Len() == 0 {
+		return nil
+	}
+	item := heap.Pop(best).(*queuedTask)
+	if best.Len() == 0 {
+		delete(q.queues, item.namespace)
+	}
+	delete(q.queued, item.task)
+	return item
+}
+
+// QueueDecision tells TryPop what to do with a candidate task.
+type QueueDecision int
+
+const (
+	QueueTake QueueDecision = iota // Hand the task out
+	QueueKeep                      // Not for this caller, leave it queued
+	QueueDrop                      // Stale entry, remove it from the queue
+)
+
+// TryPop returns the highest-priority task of the namespace, or of any
+// namespace if it is empty, that decide accepts, without blocking.
+// Candidates are offered in priority order; kept ones stay queued in their
+// original position. Callers holding s.mu may call TryPop, but decide must
+// not block or re-enter the queue.
+func (q *TaskQueue) TryPop(namespace string, decide func(*Task) QueueDecision) *Task {
+	return q.Select(namespace, decide, 1, nil)
+}
+
+// Select is TryPop choosing among the first lookahead tasks decide accepts:
+// pick is given them in priority order and returns the index of the one to
+// take. The others stay queued. pick may be nil when lookahead is 1.
+func (q *TaskQueue) Select(namespace string, decide func(*Task) QueueDecision, lookahead int, pick func([]*Task) int) *Task {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if lookahead < 1 {
+		lookahead = 1
+	}
+	var kept, eligible []*queuedTask
+	defer func() {
+		for _, item := range kept {
+			q.push(item)
+		}
+	}()
+
+	for len(eligible) < lookahead {
+		item := q.pop(namespace)
+		if item == nil {
+			break
+		}
+		if _, dead := q.tombstones[item.task.JobID]; dead {
+			continue
+		}
+		switch decide(item.task) {
+		case QueueTake:
+			eligible = append(eligible, item)
+		case QueueKeep:
+			kept = append(kept, item)
+		}
+	}
+	if len(eligible) == 0 {
+		return nil
+	}
+
+	chosen := 0
+	if len(eligible) > 1 {
+		tasks := make([]*Task, len(eligible))
+		for i, item := range eligible {
+			tasks[i] = item.task
+		}
+		if i := pick(tasks); i > 0 && i < len(eligible) {
+			chosen = i
+		}
+	}
+	for i, item := range eligible {
+		if i != chosen {
+			kept = append(kept, item)
+		}
+	}
+
+	// Let the next waiter look at what is left
+	if len(q.queues)+len(kept) > 0 {
+		q.signal()
+	}
+	return eligible[chosen].task
+}
+
+// Tombstone removes every queued task of the job and makes the queue drop
+// any pushed later, until ClearTombstone or SweepTombstones. It returns how
+// many tasks were removed.
+func (q *TaskQueue) Tombstone(jobID string) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.tombstones[jobID] = time.Now()
+	removed := 0
+	for namespace, items := range q.queues {
+		kept := (*items)[:0]
+		for _, item := range *items {
+			if item.task.JobID != jobID {
+				kept = append(kept, item)
+			} else {
+				delete(q.queued, item.task)
+			}
+		}
+		if len(kept) == len(*items) {
+			continue
+		}
+		removed += len(*items) - len(kept)
+		for i := len(kept); i < len(*items); i++ {
+			(*items)[i] = nil
+		}
+		*items = kept
+		if len(kept) == 0 {
+			delete(q.queues, namespace)
+			continue
+		}
+		heap.Init(items)
+	}
+	return removed
+}
+
+// ClearTombstone lets the job's tasks be handed out again, e.g. when a
+// cancelled job is resumed.
+func (q *TaskQueue) ClearTombstone(jobID string) {
+	q.mu.Lock()
+	delete(q.tombstones, jobID)
+	q.mu.Unlock()
+}
+
+// SweepTombstones forgets the tombstones set before cutoff. By then the
+// job's tasks have long left the queue and nothing pushes them again. It
+// returns how many were forgotten.
+func (q *TaskQueue) SweepTombstones(cutoff time.Time) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	swept := 0
+	for jobID, at := range q.tombstones {
+		if at.Before(cutoff) {
+			delete(q.tombstones, jobID)
+			swept++
+		}
+	}
+	return swept
+}
+
+// Wait blocks until new tasks may be available, the deadline passes or ctx
+// is cancelled. It returns false in the latter two cases.
+func (q *TaskQueue) Wait(ctx context.Context, deadline time.Time) bool {
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+
+	select {
+	case <-q.ready:
+		return true
+	case <-timer.C:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// Len returns the number of queued tasks.
+func (q *TaskQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	n := 0
+	for _, items := range q.queues {
+		n += items.Len()
+	}
+	return n
+}
+
+func (q *TaskQueue) signal() {
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+// score folds aging into a static sort key. A task's effective priority at
+// time t is priority + (t - submittedAt)/aging; since t is the same for every
+// queued task, ordering by priority - submittedAt/aging is equivalent and
+// never needs re-heapifying.
+func (q *TaskQueue) score(priority int32, submittedAt time.Time) float64 {
+	if q.aging <= 0 {
+		return float64(priority)
+	}
+	return float64(priority) - float64(submittedAt.UnixNano())/float64(q.aging.Nanoseconds())
+}
+
+// taskHeap implements heap.Interface as a max-heap on score.
+type taskHeap []*queuedTask
+
+func (h taskHeap) Len() int { return len(h) }
+
+func (h taskHeap) Less(i, j int) bool { return queuedAhead(h[i], h[j]) }
+
+// queuedAhead reports whether a is handed out before b.
+func queuedAhead(a, b *queuedTask) bool {
+	if a.score != b.score {
+		return a.score > b.score
+	}
+	if !a.submittedAt.Equal(b.submittedAt) {
+		return a.submittedAt.Before(b.submittedAt)
+	}
+	return a.seq < b.seq
+}
+
+func (h taskHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *taskHeap) Push(x interface{}) { *h = append(*h, x.(*queuedTask)) }
+
+func (h *taskHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return item
+}
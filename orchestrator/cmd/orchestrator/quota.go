@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	orchestratorpb "github.com/tensorfleet/orchestrator/proto/orchestrator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultTaskHoursQuota is how many task-hours each user may consume before
+// their new jobs are refused; 0 means unlimited. SetUserQuota overrides it
+// per user. A worker runs one task at a time, so task-hours are also the
+// worker-hours spent on the user's behalf.
+var defaultTaskHoursQuota = getEnvFloat("USER_TASK_HOURS_QUOTA", 0)
+
+// UserUsage is the cluster time consumed by one user's jobs.
+type UserUsage struct {
+	UserID         string
+	TaskSeconds    float64 // Worker time spent on the user's tasks, failed attempts included
+	TasksCompleted int
+	JobsSubmitted  int
+	QuotaTaskHours *float64 // Overrides defaultTaskHoursQuota when set
+	UpdatedAt      time.Time
+}
+
+func (u *UserUsage) taskHours() float64 {
+	return u.TaskSeconds / 3600
+}
+
+// quota is the user's task-hour limit; 0 means unlimited.
+func (u *UserUsage) quota() float64 {
+	if u.QuotaTaskHours != nil {
+		return *u.QuotaTaskHours
+	}
+	return defaultTaskHoursQuota
+}
+
+func (u *UserUsage) toProto() *orchestratorpb.UserUsage {
+	return &orchestratorpb.UserUsage{
+		UserId:         u.UserID,
+		TaskHours:      u.taskHours(),
+		TasksCompleted: int32(u.TasksCompleted),
+		JobsSubmitted:  int32(u.JobsSubmitted),
+		QuotaTaskHours: u.quota(),
+		UpdatedAtMs:    u.UpdatedAt.UnixMilli(),
+	}
+}
+
+// userUsage returns the user's usage record, loading it from the job store
+// the first time. Caller must hold s.mu.
+func (s *OrchestratorServer) userUsage(ctx context.Context, userID string) *UserUsage {
+	if usage, ok := s.usage[userID]; ok {
+		return usage
+	}
+	usage, err := s.store.LoadUserUsage(ctx, userID)
+	if err != nil {
+		log.Printf("Warning: Failed to load usage of user %s: %v", userID, err)
+	}
+	if usage == nil {
+		usage = &UserUsage{UserID: userID}
+	}
+	s.usage[userID] = usage
+	return usage
+}
+
+// saveUsage queues a write of the user's usage record. Caller must hold
+// s.mu.
+func (s *OrchestratorServer) saveUsage(usage *UserUsage) {
+	usage.UpdatedAt = time.Now()
+	// The record keeps changing under s.mu; write it as it is now
+	saved := *usage
+	if usage.QuotaTaskHours != nil {
+		quota := *usage.QuotaTaskHours
+		saved.QuotaTaskHours = &quota
+	}
+	s.writes.queue("save usage of user "+usage.UserID, func(ctx context.Context) error {
+		return s.store.SaveUserUsage(ctx, &saved)
+	})
+}
+
+// checkQuota refuses work for a user who has used up their task-hour quota.
+// Jobs without a user are not accounted. Caller must hold s.mu.
+func (s *OrchestratorServer) checkQuota(ctx context.Context, userID string) error {
+	if userID == "" {
+		return nil
+	}
+	usage := s.userUsage(ctx, userID)
+	if quota := usage.quota(); quota > 0 && usage.taskHours() >= quota {
+		return status.Errorf(codes.ResourceExhausted,
+			"user %s has used %.2f of %.2f task-hours", userID, usage.taskHours(), quota)
+	}
+	return nil
+}
+
+// chargeTaskAttempt bills the time a worker spent on a task attempt to the
+// job's user. Call it before the attempt's AssignedAt is cleared. Caller must
+// hold s.mu.
+func (s *OrchestratorServer) chargeTaskAttempt(job *Job, task *Task, completed bool) {
+	if job.UserID == "" || task.AssignedAt == nil {
+		return
+	}
+	usage := s.userUsage(context.Background(), job.UserID)
+	usage.TaskSeconds += time.Since(*task.AssignedAt).Seconds()
+	if completed {
+		usage.TasksCompleted++
+	}
+	s.saveUsage(usage)
+}
+
+// GetUserUsage reports the usage and quota of one user, or of every user
+// with recorded usage when no user is given.
+func (s *OrchestratorServer) GetUserUsage(ctx context.Context, req *orchestratorpb.GetUserUsageRequest) (*orchestratorpb.GetUserUsageResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if req.UserId != "" {
+		return &orchestratorpb.GetUserUsageResponse{
+			Users: []*orchestratorpb.UserUsage{s.userUsage(ctx, req.UserId).toProto()},
+		}, nil
+	}
+
+	stored, err := s.store.ListUserUsage(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list usage: %v", err)
+	}
+	for _, usage := range stored {
+		// In-memory records may be ahead of the store
+		if _, ok := s.usage[usage.UserID]; !ok {
+			s.usage[usage.UserID] = usage
+		}
+	}
+
+	users := make([]*orchestratorpb.UserUsage, 0, len(s.usage))
+	for _, usage := range s.usage {
+		users = append(users, usage.toProto())
+	}
+	sort.Slice(users, func(i, k int) bool { return users[i].UserId < users[k].UserId })
+	return &orchestratorpb.GetUserUsageResponse{Users: users}, nil
+}
+
+// SetUserQuota sets a user's task-hour quota, or restores the default when
+// none is given. A quota of 0 makes the user unlimited.
+func (s *OrchestratorServer) SetUserQuota(ctx context.Context, req *orchestratorpb.SetUserQuotaRequest) (*orchestratorpb.SetUserQuotaResponse, error) {
+	if req.UserId == "" {
+		return &orchestratorpb.SetUserQuotaResponse{Success: false, Message: "user_id is required"}, nil
+	}
+	if req.TaskHoursQuota != nil && req.GetTaskHoursQuota() < 0 {
+		return &orchestratorpb.SetUserQuotaResponse{Success: false, Message: "Quota must not be negative"}, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	usage := s.userUsage(ctx, req.UserId)
+	usage.QuotaTaskHours = nil
+	if req.TaskHoursQuota != nil {
+		quota := req.GetTaskHoursQuota()
+		usage.QuotaTaskHours = &quota
+	}
+	s.saveUsage(usage)
+
+	log.Printf("Quota of user %s set to %.2f task-hours", req.UserId, usage.quota())
+	return &orchestratorpb.SetUserQuotaResponse{
+		Success: true,
+		Message: fmt.Sprintf("Quota of user %s is %.2f task-hours", req.UserId, usage.quota()),
+		Usage:   usage.toProto(),
+	}, nil
+}
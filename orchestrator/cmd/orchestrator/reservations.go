@@ -0,0 +1,87 @@
+package main
+
+import (
+	"sort"
+	"time"
+)
+
+// reservationLinger keeps a worker reserved for a job for a while after its
+// last task of that job, so the same workers keep serving a job between
+// tasks instead of the slots churning on every completion.
+var reservationLinger = getEnvDuration("WORKER_RESERVATION_LINGER", 15*time.Second)
+
+// WorkerReservation tracks a worker's share in serving a job.
+type WorkerReservation struct {
+	InFlight int       // Tasks of the job currently leased to the worker
+	LastUsed time.Time // When the worker last finished or was given a task
+	Gang     bool      // Held by a gang job until it finishes, even when idle
+}
+
+// activeReservations prunes lapsed reservations and returns the IDs of the
+// workers currently serving the job. Caller must hold s.mu.
+func (j *Job) activeReservations(now time.Time) []string {
+	workerIDs := make([]string, 0, len(j.Reservations))
+	for workerID, r := range j.Reservations {
+		if !r.Gang && r.InFlight <= 0 && now.Sub(r.LastUsed) > reservationLinger {
+			delete(j.Reservations, workerID)
+			continue
+		}
+		workerIDs = append(workerIDs, workerID)
+	}
+	sort.Strings(workerIDs)
+	return workerIDs
+}
+
+// canServe reports whether a worker may take one of the job's tasks without
+// exceeding NumWorkers distinct workers. Caller must hold s.mu.
+func (j *Job) canServe(workerID string, now time.Time) bool {
+	if j.NumWorkers <= 0 {
+		return true
+	}
+	if _, ok := j.Reservations[workerID]; ok {
+		return true
+	}
+	return len(j.activeReservations(now)) < int(j.NumWorkers)
+}
+
+// reserve records a task of the job being leased to the worker. Caller must
+// hold s.mu.
+func (j *Job) reserve(workerID string, now time.Time) {
+	if j.Reservations == nil {
+		j.Reservations = make(map[string]*WorkerReservation)
+	}
+	r, ok := j.Reservations[workerID]
+	if !ok {
+		r = &WorkerReservation{}
+		j.Reservations[workerID] = r
+	}
+	r.InFlight++
+	r.LastUsed = now
+}
+
+// release records that a task leased to the worker is no longer in flight.
+// Caller must hold s.mu.
+func (j *Job) release(workerID string) {
+	r, ok := j.Reservations[workerID]
+	if !ok {
+		return
+	}
+	if r.InFlight > 0 {
+		r.InFlight--
+	}
+	r.LastUsed = time.Now()
+}
+
+// dropReservations frees every worker held by a job, e.g. once it finishes.
+// Caller must hold s.mu.
+func (j *Job) dropReservations() {
+	j.Reservations = nil
+}
+
+// dropWorkerReservations frees a worker from every job, e.g. when it goes
+// offline. Caller must hold s.mu.
+func (s *OrchestratorServer) dropWorkerReservations(workerID string) {
+	for _, job := range s.jobs {
+		delete(job.Reservations, workerID)
+	}
+}
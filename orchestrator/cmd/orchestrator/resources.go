@@ -0,0 +1,184 @@
+package main
+
+import (
+	"log"
+	"slices"
+	"strings"
+
+	orchestratorpb "github.com/tensorfleet/orchestrator/proto/orchestrator"
+)
+
+// noCapableWorkerReason explains why a job is PENDING_RESOURCES. Its tasks
+// stay queued until a capable worker registers.
+const noCapableWorkerReason = "No registered worker satisfies the job's resource requirements"
+
+// WorkerCapabilities describes what a worker can run, as advertised at
+// registration.
+type WorkerCapabilities struct {
+	CPUCores int32
+	MemoryMB int64
+	GPUCount int32
+	GPUType  string
+	Labels   map[string]string
+
+	// Task types the worker runs; empty for all of them
+	TaskTypes []string
+}
+
+// ResourceRequirements is what a job needs from each worker serving it.
+type ResourceRequirements struct {
+	MinCPUCores int32
+	MinMemoryMB int64
+	MinGPUCount int32
+	GPUType     string
+	Labels      map[string]string
+	Namespace   string // The job's; workers of other namespaces' pools are excluded
+}
+
+func capabilitiesFromProto(pb *orchestratorpb.WorkerCapabilities) WorkerCapabilities {
+	if pb == nil {
+		return WorkerCapabilities{}
+	}
+	return WorkerCapabilities{
+		CPUCores: pb.CpuCores,
+		MemoryMB: pb.MemoryMb,
+		GPUCount: pb.GpuCount,
+		GPUType:  pb.GpuType,
+		Labels:   pb.Labels,
+
+		TaskTypes: pb.TaskTypes,
+	}
+}
+
+func (c WorkerCapabilities) toProto() *orchestratorpb.WorkerCapabilities {
+	return &orchestratorpb.WorkerCapabilities{
+		CpuCores: c.CPUCores,
+		MemoryMb: c.MemoryMB,
+		GpuCount: c.GPUCount,
+		GpuType:  c.GPUType,
+		Labels:   c.Labels,
+
+		TaskTypes: c.TaskTypes,
+	}
+}
+
+// runs reports whether the worker runs tasks of the type.
+func (c WorkerCapabilities) runs(taskType string) bool {
+	return len(c.TaskTypes) == 0 || slices.Contains(c.TaskTypes, taskType)
+}
+
+func requirementsFromProto(pb *orchestratorpb.ResourceRequirements) ResourceRequirements {
+	if pb == nil {
+		return ResourceRequirements{}
+	}
+	return ResourceRequirements{
+		MinCPUCores: pb.MinCpuCores,
+		MinMemoryMB: pb.MinMemoryMb,
+		MinGPUCount: pb.MinGpuCount,
+		GPUType:     pb.GpuType,
+		Labels:      pb.Labels,
+	}
+}
+
+// IsZero reports whether the job places no constraints on workers.
+func (r ResourceRequirements) IsZero() bool {
+	return r.MinCPUCores == 0 && r.MinMemoryMB == 0 && r.MinGPUCount == 0 &&
+		r.GPUType == "" && len(r.Labels) == 0
+}
+
+// SatisfiedBy reports whether a worker with the given capabilities can run
+// the job's tasks. Workers that never advertised capabilities only match
+// jobs without requirements.
+func (r ResourceRequirements) SatisfiedBy(c WorkerCapabilities) bool {
+	if !c.servesNamespace(r.Namespace) {
+		return false
+	}
+	if c.CPUCores < r.MinCPUCores || c.MemoryMB < r.MinMemoryMB || c.GPUCount < r.MinGPUCount {
+		return false
+	}
+	if r.GPUType != "" && !strings.EqualFold(r.GPUType, c.GPUType) {
+		return false
+	}
+	for key, value := range r.Labels {
+		if c.Labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// refreshResourceAvailability parks jobs no live worker can serve in
+// PENDING_RESOURCES and resumes them once a capable worker shows up.
+// Caller must hold s.mu.
+func (s *OrchestratorServer) refreshResourceAvailability() {
+	for _, job := range s.jobs {
+		if job.Requirements.IsZero() {
+			continue
+		}
+		if !job.isRunning() && job.Status != JobStatusPendingResources {
+			continue
+		}
+
+		satisfiable := s.hasCapableWorker(job.Requirements)
+		switch {
+		case !satisfiable && job.isRunning():
+			job.mustTransition(JobStatusPendingResources, noCapableWorkerReason)
+			log.Printf("Job %s is waiting for resources", job.JobID)
+		case satisfiable && job.Status == JobStatusPendingResources:
+			job.mustTransition(JobStatusRunning, "")
+			// Wake waiting AssignTask calls; the queued tasks are now servable
+			s.taskQueue.signal()
+			log.Printf("Job %s resumed: a capable worker is available", job.JobID)
+		}
+	}
+}
+
+// hasCapableWorker reports whether any live worker satisfies the
+// requirements. Caller must hold s.mu.
+func (s *OrchestratorServer) hasCapableWorker(req ResourceRequirements) bool {
+	for _, worker := range s.workers {
+		if worker.schedulable() && req.SatisfiedBy(worker.Capabilities) {
+			return true
+		}
+	}
+	return false
+}
+
+// TaskResourceUsage is what an attempt used of the CPU and memory limits its
+// worker ran it under, as the worker reported it.
+type TaskResourceUsage struct {
+	CPULimitCores       float64
+	MemoryLimitBytes    int64
+	PeakMemoryBytes     int64
+	CPUSeconds          float64
+	CPUThrottledSeconds float64
+	OOMKilled           bool
+}
+
+func resourceUsageFromProto(pb *orchestratorpb.TaskResourceUsage) *TaskResourceUsage {
+	if pb == nil {
+		return nil
+	}
+	return &TaskResourceUsage{
+		CPULimitCores:       pb.CpuLimitCores,
+		MemoryLimitBytes:    pb.MemoryLimitBytes,
+		PeakMemoryBytes:     pb.PeakMemoryBytes,
+		CPUSeconds:          pb.CpuSeconds,
+		CPUThrottledSeconds: pb.CpuThrottledSeconds,
+		OOMKilled:           pb.OomKilled,
+	}
+}
+
+func (u *TaskResourceUsage) toProto() *orchestratorpb.TaskResourceUsage {
+	if u == nil {
+		return nil
+	}
+	return &orchestratorpb.TaskResourceUsage{
+		CpuLimitCores:       u.CPULimitCores,
+		MemoryLimitBytes:    u.MemoryLimitBytes,
+		PeakMemoryBytes:     u.PeakMemoryBytes,
+		CpuSeconds:          u.CPUSeconds,
+		CpuThrottledSeconds: u.CPUThrottledSeconds,
+		OomKilled:           u.OOMKilled,
+	}
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Task result cache policies a job may choose.
+const (
+	ResultCacheReadWrite = "read_write" // Resolve tasks from the cache and store new results
+	ResultCacheRefresh   = "refresh"    // Rerun every task, storing the results
+	ResultCacheOff       = "off"        // Neither read nor write the cache
+)
+
+// The results of completed tasks are kept in Redis under
+// task_result:<fingerprint> for taskResultCacheTTL, so that a job submitted
+// again, e.g. after the gateway timed out on the first submission, gets
+// them without retraining. Results with weights over
+// taskResultCacheMaxBytes are not kept. A lookup taking longer than
+// taskResultCacheLookupTimeout is given up on and the task run.
+var (
+	taskResultCaching            = os.Getenv("TASK_RESULT_CACHE") != "false"
+	taskResultCacheTTL           = getEnvDuration("TASK_RESULT_CACHE_TTL", 24*time.Hour)
+	taskResultCacheMaxBytes      = getEnvInt("TASK_RESULT_CACHE_MAX_BYTES", 16<<20)
+	taskResultCacheLookupTimeout = getEnvDuration("TASK_RESULT_CACHE_LOOKUP_TIMEOUT", 250*time.Millisecond)
+)
+
+const taskResultKeyPrefix = "task_result:"
+
+// CachedResult is what a completed task computed, and which task that was.
+type CachedResult struct {
+	Loss         float64   `json:"loss"`
+	Accuracy     float64   `json:"accuracy"`
+	ModelWeights []byte    `json:"model_weights,omitempty"`
+	JobID        string    `json:"job_id"`
+	TaskID       string    `json:"task_id"`
+	CompletedAt  time.Time `json:"completed_at"`
+}
+
+// TaskResultCache keeps task results by fingerprint. A nil cache keeps
+// nothing.
+type TaskResultCache struct {
+	client *redis.Client
+}
+
+func newTaskResultCache() *TaskResultCache {
+	if !taskResultCaching {
+		return nil
+	}
+
+	redisAddr := os.Getenv("REDIS_ADDR")
+	if redisAddr == "" {
+		redisAddr = "redis:6379"
+	}
+	return &TaskResultCache{client: redis.NewClient(&redis.Options{Addr: redisAddr})}
+}
+
+// get returns the result stored under the fingerprint, or nil if there is
+// none.
+func (c *TaskResultCache) get(ctx context.Context, fingerprint string) (*CachedResult, error) {
+	data, err := c.client.Get(ctx, taskResultKeyPrefix+fingerprint).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var result CachedResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *TaskResultCache) put(ctx context.Context, fingerprint string, result *CachedResult) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	return c.client.Set(ctx, taskResultKeyPrefix+fingerprint, data, taskResultCacheTTL).Err()
+}
+
+// resultCachePolicy checks a new job's cache policy; empty means read_write.
+func resultCachePolicy(policy string) (string, error) {
+	switch policy {
+	case "":
+		return ResultCacheReadWrite, nil
+	case ResultCacheReadWrite, ResultCacheRefresh, ResultCacheOff:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown result_cache policy %q: must be %q, %q or %q",
+			policy, ResultCacheReadWrite, ResultCacheRefresh, ResultCacheOff)
+	}
+}
+
+// resultCache is the job's cache policy; jobs saved before there was one
+// read and write the cache.
+func (j *Job) resultCache() string {
+	if j.ResultCache == "" {
+		return ResultCacheReadWrite
+	}
+	return j.ResultCache
+}
+
+// cacheable reports whether the task's result may be kept. Preprocessing is
+// not: what it produces lives beside the dataset, not in its result.
+func (t *Task) cacheable() bool {
+	return t.taskType() != TaskTypePreprocess
+}
+
+// fingerprint hashes everything the task's result depends on: the model,
+// the dataset and the part of it the task covers, the hyperparameters it is
+// sent, its epoch and the weights it starts from. Datasets are known by
+// path only, so a job over a dataset changed in place should refresh.
+func (t *Task) fingerprint(job *Job, weights []byte) string {
+	h := sha256.New()
+	write := func(field string) {
+		// Length-prefixed so that no two inputs hash the same fields
+		fmt.Fprintf(h, "%d:%s;", len(field), field)
+	}
+
+	write(t.taskType())
+	write(job.ModelType)
+	write(t.datasetPath(job))
+	write(job.Image)
+	params := t.hyperparameters(job)
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		write(k)
+		write(params[k])
+	}
+	write(strconv.Itoa(int(t.Epoch)))
+	write(strconv.Itoa(int(t.BatchStart)))
+	write(strconv.Itoa(int(t.BatchEnd)))
+	digest := sha256.Sum256(weights)
+	write(hex.EncodeToString(digest[:]))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// fingerprintTask fingerprints a task about to be assigned, for its result
+// to be kept under. It returns true if the job reads the cache, so that an
+// identical task's result should be looked up first. Caller must hold s.mu.
+func (s *OrchestratorServer) fingerprintTask(job *Job, task *Task) bool {
+	task.Fingerprint = ""
+	if s.results == nil || !task.cacheable() || job.resultCache() == ResultCacheOff {
+		return false
+	}
+	task.Fingerprint = task.fingerprint(job, task.startingWeights(job))
+	return job.resultCache() == ResultCacheReadWrite
+}
+
+// lookupTaskResult reads the result kept under a task's fingerprint, or
+// returns nil if there is none or the cache does not answer in time. It is
+// called on every assignment, so it must not hold s.mu.
+func (s *OrchestratorServer) lookupTaskResult(ctx context.Context, taskID, fingerprint string) *CachedResult {
+	ctx, cancel := context.WithTimeout(ctx, taskResultCacheLookupTimeout)
+	defer cancel()
+	result, err := s.results.get(ctx, fingerprint)
+	if err != nil {
+		log.Printf("Warning: Failed to look up cached result of task %s: %v", taskID, err)
+		return nil
+	}
+	return result
+}
+
+// resolveFromCache completes a task with the result an identical task
+// computed, instead of running it. Caller must hold s.mu.
+func (s *OrchestratorServer) resolveFromCache(ctx context.Context, job *Job, task *Task, result *CachedResult) {
+	log.Printf("Resolved task %s of job %s from the result of task %s of job %s",
+		task.TaskID, job.JobID, result.TaskID, result.JobID)
+	task.LastError = ""
+	job.CachedTasks++
+	s.recordEvent(job.JobID, JobEvent{
+		Type:    EventTaskCached,
+		Message: fmt.Sprintf("Epoch %d %s task resolved from cached result of task %s", task.Epoch, task.taskType(), result.TaskID),
+		TaskID:  task.TaskID,
+	})
+	s.completeTask(ctx, job, task, result.Loss, result.Accuracy, result.ModelWeights)
+}
+
+// storeTaskResult keeps the result of a task completed by a worker under the
+// fingerprint of the attempt, if the job writes the cache. The write does
+// not hold up the caller. Caller must hold s.mu.
+func (s *OrchestratorServer) storeTaskResult(job *Job, task *Task, loss, accuracy float64, weights []byte) {
+	if s.results == nil || task.Fingerprint == "" || job.resultCache() == ResultCacheOff {
+		return
+	}
+	if len(weights) > taskResultCacheMaxBytes {
+		return
+	}
+
+	fingerprint := task.Fingerprint
+	result := &CachedResult{
+		Loss:         loss,
+		Accuracy:     accuracy,
+		ModelWeights: weights,
+		JobID:        job.JobID,
+		TaskID:       task.TaskID,
+		CompletedAt:  time.Now(),
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := s.results.put(ctx, fingerprint, result); err != nil {
+			log.Printf("Warning: Failed to cache result of task %s: %v", result.TaskID, err)
+		}
+	}()
+}
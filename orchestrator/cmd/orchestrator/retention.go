@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"time"
+)
+
+// Retention of job records. The orchestrator owns each job's record and
+// history in the job store. A finished job is kept for jobRetention after its
+// last update, then copied to the storage service's jobs bucket (unless
+// JOB_ARCHIVE=false) and deleted.
+var (
+	jobRetention       = getEnvDuration("JOB_RETENTION", 7*24*time.Hour)
+	jobArchiving       = os.Getenv("JOB_ARCHIVE") != "false"
+	jobArchiveInterval = getEnvDuration("JOB_ARCHIVE_INTERVAL", time.Hour)
+)
+
+// archiveGrace is how long records outlive jobRetention in stores that expire
+// them on their own, so the archiver gets to them first.
+const archiveGrace = 24 * time.Hour
+
+// recordTTL is how long a store that expires records on its own keeps a job
+// and its history after the last write.
+func recordTTL() time.Duration {
+	if jobArchiving {
+		return jobRetention + archiveGrace
+	}
+	return jobRetention
+}
+
+// JobArchive is the document a finished job is archived as.
+type JobArchive struct {
+	Job        *Job           `json:"job"`
+	Events     []JobEvent     `json:"events"`
+	Metrics    []MetricSample `json:"metrics"`
+	ArchivedAt time.Time      `json:"archived_at"`
+}
+
+// archiveJobRecords periodically archives and deletes finished jobs past
+// their retention. Only the leader, as reported by leading, does so.
+func (s *OrchestratorServer) archiveJobRecords(ctx context.Context, leading func() bool) {
+	ticker := time.NewTicker(jobArchiveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if leading() {
+				s.expireJobRecords(ctx)
+			}
+		}
+	}
+}
+
+func (s *OrchestratorServer) expireJobRecords(ctx context.Context) {
+	jobIDs, err := s.store.ListFinishedJobs(ctx, time.Now().Add(-jobRetention))
+	if err != nil {
+		log.Printf("Warning: Failed to list expired jobs: %v", err)
+		return
+	}
+
+	expired := 0
+	for _, jobID := range jobIDs {
+		s.mu.RLock()
+		_, inMemory := s.jobs[jobID]
+		s.mu.RUnlock()
+		if inMemory {
+			// Recently read back; expire it once it has been evicted again
+			continue
+		}
+
+		if jobArchiving {
+			path, err := s.archiveJob(ctx, jobID)
+			if err != nil {
+				log.Printf("Warning: Failed to archive job %s, keeping it: %v", jobID, err)
+				continue
+			}
+			log.Printf("Archived job %s to %s", jobID, path)
+		}
+		if err := s.store.DeleteJob(ctx, jobID); err != nil {
+			log.Printf("Warning: Failed to delete expired job %s: %v", jobID, err)
+			continue
+		}
+		expired++
+	}
+	if expired > 0 {
+		log.Printf("Expired %d job records older than %s", expired, jobRetention)
+	}
+}
+
+// archiveJob uploads a job with its events and metric history to the storage
+// service and returns where it was stored.
+func (s *OrchestratorServer) archiveJob(ctx context.Context, jobID string) (string, error) {
+	job, err := s.store.LoadJob(ctx, jobID)
+	if err != nil {
+		return "", err
+	}
+	events, err := s.store.ListEvents(ctx, jobID)
+	if err != nil {
+		return "", fmt.Errorf("failed to read events: %v", err)
+	}
+	metrics, err := s.store.ListMetricSamples(ctx, jobID, 0)
+	if err != nil {
+		return "", fmt.Errorf("failed to read metrics: %v", err)
+	}
+
+	data, err := json.Marshal(JobArchive{
+		Job:        job,
+		Events:     events,
+		Metrics:    metrics,
+		ArchivedAt: time.Now(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	file, err := form.CreateFormFile("file", jobID+".json")
+	if err == nil {
+		_, err = file.Write(data)
+	}
+	if err == nil {
+		err = form.Close()
+	}
+	if err != nil {
+		return "", err
+	}
+
+	objectName := "archive/" + jobID + ".json"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		storageServiceURL()+"/api/v1/upload/jobs/"+objectName, &body)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	if storageAuthToken != "" {
+		req.Header.Set("Authorization", "Bearer "+storageAuthToken)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("storage service returned status %d", resp.StatusCode)
+	}
+	return "s3://jobs/" + objectName, nil
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// schedulerLookahead is how many eligible tasks, in queue order, schedulers
+// other than priority choose between for each assignment. Tasks further back
+// wait until the ones ahead of them are handed out.
+var schedulerLookahead = getEnvInt("SCHEDULER_LOOKAHEAD", 64)
+
+// Scheduling policies selectable through SCHEDULER.
+const (
+	SchedulerPriority   = "priority"
+	SchedulerFIFO       = "fifo"
+	SchedulerFairShare  = "fair-share"
+	SchedulerBinPacking = "bin-packing"
+)
+
+// Placement is the worker a task is being chosen for.
+type Placement struct {
+	WorkerID     string
+	Capabilities WorkerCapabilities
+	Now          time.Time
+}
+
+// Scheduler decides which queued task a worker is given. The task queue
+// offers the tasks the worker may run in queue order, i.e. by job priority
+// with aging and then submission time; the scheduler picks one of them.
+//
+// To try a new policy, implement Scheduler and register it with
+// RegisterScheduler from an init function; the orchestrator selects it by
+// name through SCHEDULER.
+type Scheduler interface {
+	Name() string
+	// Lookahead is how many candidates Pick chooses between; 1 always
+	// takes the head of the queue.
+	Lookahead() int
+	// Pick returns the index in candidates of the task to assign. It is
+	// called with s.mu held and must not modify the orchestrator's state.
+	Pick(s *OrchestratorServer, p Placement, candidates []*Task) int
+}
+
+var (
+	schedulersMu sync.RWMutex
+	schedulers   = make(map[string]Scheduler)
+)
+
+// RegisterScheduler makes a scheduling policy available under its name,
+// replacing any previous policy with that name.
+func RegisterScheduler(sc Scheduler) {
+	schedulersMu.Lock()
+	defer schedulersMu.Unlock()
+	schedulers[sc.Name()] = sc
+}
+
+// lookupScheduler returns the scheduling policy registered under name.
+func lookupScheduler(name string) (Scheduler, error) {
+	schedulersMu.RLock()
+	defer schedulersMu.RUnlock()
+	sc, ok := schedulers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown scheduler: %s", name)
+	}
+	return sc, nil
+}
+
+// configuredScheduler returns the policy named by SCHEDULER, priority by
+// default.
+func configuredScheduler() (Scheduler, error) {
+	name := os.Getenv("SCHEDULER")
+	if name == "" {
+		name = SchedulerPriority
+	}
+	return lookupScheduler(name)
+}
+
+func init() {
+	RegisterScheduler(PriorityScheduler{})
+	RegisterScheduler(FIFOScheduler{})
+	RegisterScheduler(FairShareScheduler{})
+	RegisterScheduler(BinPackingScheduler{})
+}
+
+// PriorityScheduler hands out the head of the queue: the highest job
+// priority, with aging, then the oldest job.
+type PriorityScheduler struct{}
+
+func (PriorityScheduler) Name() string { return SchedulerPriority }
+
+func (PriorityScheduler) Lookahead() int { return 1 }
+
+func (PriorityScheduler) Pick(*OrchestratorServer, Placement, []*Task) int { return 0 }
+
+// FIFOScheduler hands out the task that has been queued longest, ignoring
+// job priority.
+type FIFOScheduler struct{}
+
+func (FIFOScheduler) Name() string { return SchedulerFIFO }
+
+func (FIFOScheduler) Lookahead() int { return schedulerLookahead }
+
+func (FIFOScheduler) Pick(_ *OrchestratorServer, _ Placement, candidates []*Task) int {
+	oldest := 0
+	for i, task := range candidates {
+		if task.QueuedAt.Before(candidates[oldest].QueuedAt) {
+			oldest = i
+		}
+	}
+	return oldest
+}
+
+// FairShareScheduler hands out a task of the user with the fewest tasks
+// running, so one user's large jobs cannot occupy every worker. Ties go to
+// the task ahead in the queue. Running tasks are counted from the workers'
+// leases, so a pick costs as much as there are tasks running rather than
+// tasks in all.
+type FairShareScheduler struct{}
+
+func (FairShareScheduler) Name() string { return SchedulerFairShare }
+
+func (FairShareScheduler) Lookahead() int { return schedulerLookahead }
+
+func (FairShareScheduler) Pick(s *OrchestratorServer, _ Placement, candidates []*Task) int {
+	running := make(map[string]int)
+	for _, worker := range s.workers {
+		for _, task := range worker.leased {
+			if task.Status != TaskStatusAssigned || task.WorkerID != worker.WorkerID {
+				continue
+			}
+			if job := s.jobs[task.JobID]; job != nil && job.isRunning() {
+				running[job.UserID]++
+			}
+		}
+	}
+
+	best, bestRunning := 0, -1
+	for i, task := range candidates {
+		n := running[s.jobs[task.JobID].UserID]
+		if bestRunning < 0 || n < bestRunning {
+			best, bestRunning = i, n
+		}
+	}
+	return best
+}
+
+// BinPackingScheduler hands out the task whose job needs the most of the
+// worker, leaving large workers free for jobs only they can serve. GPUs
+// weigh most, then CPU cores, then memory. Ties go to the task ahead in the
+// queue.
+type BinPackingScheduler struct{}
+
+func (BinPackingScheduler) Name() string { return SchedulerBinPacking }
+
+func (BinPackingScheduler) Lookahead() int { return schedulerLookahead }
+
+func (BinPackingScheduler) Pick(s *OrchestratorServer, _ Placement, candidates []*Task) int {
+	best := 0
+	for i, task := range candidates {
+		if tighterFit(s.jobs[task.JobID].Requirements, s.jobs[candidates[best].JobID].Requirements) {
+			best = i
+		}
+	}
+	return best
+}
+
+// tighterFit reports whether a job with requirements a uses more of a worker
+// than one with requirements b.
+func tighterFit(a, b ResourceRequirements) bool {
+	if a.MinGPUCount != b.MinGPUCount {
+		return a.MinGPUCount > b.MinGPUCount
+	}
+	if a.MinCPUCores != b.MinCPUCores {
+		return a.MinCPUCores > b.MinCPUCores
+	}
+	return a.MinMemoryMB > b.MinMemoryMB
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"os"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	orchestratorpb "github.com/tensorfleet/orchestrator/proto/orchestrator"
+)
+
+// Each registration issues the worker a fresh session token. Task RPCs must
+// carry the worker's ID and token as metadata, and may only act for that
+// worker, so a process that can reach the port cannot take or report tasks
+// in another worker's name. A worker that is evicted, or that the
+// orchestrator forgot across a restart, gets UNAUTHENTICATED and registers
+// again.
+var requireWorkerSessions = os.Getenv("WORKER_SESSIONS") != "false"
+
+const (
+	workerIDMetadataKey      = "x-worker-id"
+	workerSessionMetadataKey = "x-worker-session"
+)
+
+// sessionMethods are the RPCs that act for a worker and need its session.
+var sessionMethods = map[string]bool{
+	orchestratorpb.OrchestratorService_AssignTask_FullMethodName:           true,
+	orchestratorpb.OrchestratorService_StreamTasks_FullMethodName:          true,
+	orchestratorpb.OrchestratorService_ReportTaskCompletion_FullMethodName: true,
+	orchestratorpb.OrchestratorService_ReleaseTask_FullMethodName:          true,
+	orchestratorpb.OrchestratorService_ReportTaskProgress_FullMethodName:   true,
+	orchestratorpb.OrchestratorService_DeregisterWorker_FullMethodName:     true,
+	orchestratorpb.OrchestratorService_ShipTaskLogs_FullMethodName:         true,
+}
+
+func newSessionToken() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// workerRequest is implemented by requests made on behalf of a worker.
+type workerRequest interface {
+	GetWorkerId() string
+}
+
+// authenticateSession returns the worker whose session the call carries,
+// or "" for calls that need none.
+func (s *OrchestratorServer) authenticateSession(ctx context.Context, method string) (string, error) {
+	if !requireWorkerSessions || !sessionMethods[method] {
+		return "", nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	ids, tokens := md.Get(workerIDMetadataKey), md.Get(workerSessionMetadataKey)
+	if len(ids) == 0 || len(tokens) == 0 {
+		return "", status.Errorf(codes.Unauthenticated, "missing worker session; call RegisterWorker first")
+	}
+
+	s.mu.RLock()
+	expected := ""
+	if worker, ok := s.workers[ids[0]]; ok {
+		expected = worker.sessionToken
+	}
+	s.mu.RUnlock()
+	if expected == "" || subtle.ConstantTimeCompare([]byte(tokens[0]), []byte(expected)) != 1 {
+		return "", status.Errorf(codes.Unauthenticated, "invalid or expired worker session; register again")
+	}
+	return ids[0], nil
+}
+
+func checkSessionWorker(sessionWorker string, req interface{}) error {
+	if r, ok := req.(workerRequest); ok && sessionWorker != "" && r.GetWorkerId() != sessionWorker {
+		return status.Errorf(codes.PermissionDenied, "session of worker %s cannot act for worker %s", sessionWorker, r.GetWorkerId())
+	}
+	return nil
+}
+
+func (s *OrchestratorServer) sessionUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	workerID, err := s.authenticateSession(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSessionWorker(workerID, req); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *OrchestratorServer) sessionStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	workerID, err := s.authenticateSession(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	if workerID == "" {
+		return handler(srv, ss)
+	}
+	return handler(srv, &sessionStream{ServerStream: ss, workerID: workerID})
+}
+
+// sessionStream rejects messages sent for another worker than the one the
+// stream was opened with.
+type sessionStream struct {
+	grpc.ServerStream
+	workerID string
+}
+
+func (ss *sessionStream) RecvMsg(m interface{}) error {
+	if err := ss.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return checkSessionWorker(ss.workerID, m)
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// Defaults for jobs that give neither a batch layout nor a dataset size.
+const (
+	defaultBatchesPerEpoch = 10
+	defaultSamplesPerBatch = 100
+)
+
+// maxTasksPerJob caps epochs × batches so a tiny batch size on a large
+// dataset cannot flood the scheduler.
+var maxTasksPerJob = getEnvInt("MAX_TASKS_PER_JOB", 100000)
+
+// shardPlan is how a job's dataset is split into batches. The job's task
+// planner decides how batches map to tasks.
+type shardPlan struct {
+	BatchesPerEpoch int32
+	SamplesPerBatch int32
+	DatasetSize     int64 // 0 when unknown
+}
+
+// planBatches completes a requested layout. When the dataset size is known,
+// whichever of batches per epoch and samples per batch is missing is derived
+// from it.
+func planBatches(requested shardPlan, epochs int32) (shardPlan, error) {
+	if requested.BatchesPerEpoch < 0 || requested.SamplesPerBatch < 0 || requested.DatasetSize < 0 {
+		return shardPlan{}, fmt.Errorf("batches_per_epoch, samples_per_batch and dataset_size must not be negative")
+	}
+
+	size := requested.DatasetSize
+	batches := int64(requested.BatchesPerEpoch)
+	samples := int64(requested.SamplesPerBatch)
+	if size > 0 && batches > 0 && samples == 0 {
+		samples = ceilDiv(size, batches)
+	}
+	if samples == 0 {
+		samples = defaultSamplesPerBatch
+	}
+	if batches == 0 {
+		batches = defaultBatchesPerEpoch
+		if size > 0 {
+			batches = ceilDiv(size, samples)
+		}
+	}
+
+	if size > 0 && (batches-1)*samples >= size {
+		return shardPlan{}, fmt.Errorf("%d batches of %d samples do not fit a dataset of %d samples",
+			batches, samples, size)
+	}
+	if batches*samples > math.MaxInt32 {
+		return shardPlan{}, fmt.Errorf("dataset of %d batches of %d samples is too large", batches, samples)
+	}
+	if maxTasksPerJob > 0 && batches*int64(epochs) > int64(maxTasksPerJob) {
+		return shardPlan{}, fmt.Errorf("job would create %d tasks, more than the limit of %d; use larger batches",
+			batches*int64(epochs), maxTasksPerJob)
+	}
+	return shardPlan{
+		BatchesPerEpoch: int32(batches),
+		SamplesPerBatch: int32(samples),
+		DatasetSize:     size,
+	}, nil
+}
+
+func ceilDiv(a, b int64) int64 {
+	return (a + b - 1) / b
+}
@@ -0,0 +1,325 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"os"
+	"slices"
+	"time"
+
+	orchestratorpb "github.com/tensorfleet/orchestrator/proto/orchestrator"
+)
+
+// snapshotFormatVersion is bumped whenever Snapshot changes in a way older
+// orchestrators cannot read. Import refuses snapshots newer than it knows.
+const snapshotFormatVersion = 1
+
+// Snapshot is the control-plane state written by ExportSnapshot, stored as
+// gzipped JSON. Jobs carry their tasks, so the task queue and admission
+// queue are rebuilt from them on import. Workers are recorded for
+// inspection only: they register again with whichever orchestrator they
+// heartbeat to.
+type Snapshot struct {
+	FormatVersion int
+	CreatedAt     time.Time
+	Source        string // Host of the orchestrator that wrote it
+	Jobs          []*Job
+	Workers       []*WorkerActivity
+	Usage         []*UserUsage
+}
+
+func encodeSnapshot(snap *Snapshot) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(zw).Encode(snap); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decodeSnapshot(data []byte) (*Snapshot, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	var snap Snapshot
+	if err := json.NewDecoder(zr).Decode(&snap); err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(io.Discard, zr); err != nil {
+		return nil, err
+	}
+	return &snap, nil
+}
+
+// snapshotKey names a snapshot by when it was taken.
+func snapshotKey(at time.Time) string {
+	return at.UTC().Format("20060102T150405.000Z") + ".json.gz"
+}
+
+func snapshotResponse(snap *Snapshot, key string, size int) *orchestratorpb.SnapshotResponse {
+	return &orchestratorpb.SnapshotResponse{
+		Success:       true,
+		Key:           key,
+		FormatVersion: int32(snap.FormatVersion),
+		CreatedAtMs:   unixMillis(snap.CreatedAt),
+		SizeBytes:     int64(size),
+		Jobs:          int32(len(snap.Jobs)),
+		Workers:       int32(len(snap.Workers)),
+	}
+}
+
+// snapshotCopy copies what a snapshot records of the job, so it can be
+// encoded without s.mu. Caller must hold s.mu.
+func (j *Job) snapshotCopy() *Job {
+	c := *j
+	c.Hyperparameters = maps.Clone(j.Hyperparameters)
+	c.Transitions = slices.Clone(j.Transitions)
+	c.Tasks = make([]*Task, len(j.Tasks))
+	for i, task := range j.Tasks {
+		c.Tasks[i] = task.snapshotCopy()
+	}
+	c.EpochMetrics = cloneEpochMetrics(j.EpochMetrics)
+	c.ValidationMetrics = cloneEpochMetrics(j.ValidationMetrics)
+	c.SettledEpochs = maps.Clone(j.SettledEpochs)
+	if j.Reservations != nil {
+		c.Reservations = make(map[string]*WorkerReservation, len(j.Reservations))
+		for workerID, reservation := range j.Reservations {
+			r := *reservation
+			c.Reservations[workerID] = &r
+		}
+	}
+	c.TaskDurations.Samples = slices.Clone(j.TaskDurations.Samples)
+	c.Requirements.Labels = maps.Clone(j.Requirements.Labels)
+	c.Stages = slices.Clone(j.Stages)
+	c.ModelWeights = bytes.Clone(j.ModelWeights)
+	c.pendingWeights = nil
+	c.Checkpoints = slices.Clone(j.Checkpoints)
+	c.CheckpointWeights = bytes.Clone(j.CheckpointWeights)
+	if j.EarlyStopping != nil {
+		policy := *j.EarlyStopping
+		c.EarlyStopping = &policy
+	}
+	c.DependsOn = slices.Clone(j.DependsOn)
+	return &c
+}
+
+func cloneEpochMetrics(metrics []*EpochMetrics) []*EpochMetrics {
+	if metrics == nil {
+		return nil
+	}
+	c := make([]*EpochMetrics, len(metrics))
+	for i, m := range metrics {
+		epoch := *m
+		c[i] = &epoch
+	}
+	return c
+}
+
+// snapshotCopy copies what a snapshot records of the task. Caller must
+// hold s.mu.
+func (t *Task) snapshotCopy() *Task {
+	c := *t
+	if t.AssignedAt != nil {
+		at := *t.AssignedAt
+		c.AssignedAt = &at
+	}
+	if t.CompletedAt != nil {
+		at := *t.CompletedAt
+		c.CompletedAt = &at
+	}
+	c.Params = maps.Clone(t.Params)
+	if t.Progress != nil {
+		progress := *t.Progress
+		c.Progress = &progress
+	}
+	if t.ResourceUsage != nil {
+		usage := *t.ResourceUsage
+		c.ResourceUsage = &usage
+	}
+	c.weights = nil
+	return &c
+}
+
+// snapshotCopy copies what a snapshot records of the worker. Caller must
+// hold s.mu.
+func (w *WorkerActivity) snapshotCopy() *WorkerActivity {
+	c := *w
+	c.Capabilities.Labels = maps.Clone(w.Capabilities.Labels)
+	c.Capabilities.TaskTypes = slices.Clone(w.Capabilities.TaskTypes)
+	c.CachedDatasets = maps.Clone(w.CachedDatasets)
+	c.TaskDurations.Samples = slices.Clone(w.TaskDurations.Samples)
+	c.Load.GPUs = slices.Clone(w.Load.GPUs)
+	c.leased = nil
+	c.quarantine = nil
+	return &c
+}
+
+// snapshotCopy copies the usage record. Caller must hold s.mu.
+func (u *UserUsage) snapshotCopy() *UserUsage {
+	c := *u
+	if u.QuotaTaskHours != nil {
+		quota := *u.QuotaTaskHours
+		c.QuotaTaskHours = &quota
+	}
+	return &c
+}
+
+func (a *AdminServer) ExportSnapshot(ctx context.Context, req *orchestratorpb.ExportSnapshotRequest) (*orchestratorpb.SnapshotResponse, error) {
+	s := a.s
+	if s.objects == nil {
+		return &orchestratorpb.SnapshotResponse{Success: false, Message: "Snapshots need an object store; set MINIO_ENDPOINT"}, nil
+	}
+
+	stored, _, err := s.store.ListJobs(ctx, JobFilter{}, JobPage{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %v", err)
+	}
+	storedUsage, err := s.store.ListUserUsage(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user usage: %v", err)
+	}
+
+	host, _ := os.Hostname()
+	snap := &Snapshot{FormatVersion: snapshotFormatVersion, CreatedAt: time.Now().UTC(), Source: host}
+
+	// Jobs and usage in memory are newer than their stored records
+	s.mu.RLock()
+	for _, job := range stored {
+		if _, ok := s.jobs[job.JobID]; !ok {
+			snap.Jobs = append(snap.Jobs, job)
+		}
+	}
+	for _, job := range s.jobs {
+		snap.Jobs = append(snap.Jobs, job.snapshotCopy())
+	}
+	for _, worker := range s.workers {
+		snap.Workers = append(snap.Workers, worker.snapshotCopy())
+	}
+	for _, usage := range storedUsage {
+		if _, ok := s.usage[usage.UserID]; !ok {
+			snap.Usage = append(snap.Usage, usage)
+		}
+	}
+	for _, usage := range s.usage {
+		snap.Usage = append(snap.Usage, usage.snapshotCopy())
+	}
+	s.mu.RUnlock()
+
+	// Encoding a large snapshot takes a while; it works on the copies
+	data, err := encodeSnapshot(snap)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode snapshot: %v", err)
+	}
+
+	key := snapshotKey(snap.CreatedAt)
+	if err := s.objects.EnsureBucket(ctx, snapshotsBucket); err != nil {
+		return &orchestratorpb.SnapshotResponse{Success: false, Message: fmt.Sprintf("Failed to write snapshot: %v", err)}, nil
+	}
+	if err := s.objects.Put(ctx, snapshotsBucket, key, data); err != nil {
+		return &orchestratorpb.SnapshotResponse{Success: false, Message: fmt.Sprintf("Failed to write snapshot: %v", err)}, nil
+	}
+
+	s.mu.Lock()
+	s.audit(req.Operator, "", JobEvent{
+		Message: fmt.Sprintf("exported snapshot %s of %d jobs: %s", key, len(snap.Jobs), req.Reason),
+	})
+	s.mu.Unlock()
+
+	resp := snapshotResponse(snap, key, len(data))
+	resp.Message = fmt.Sprintf("Snapshot %s written with %d jobs and %d workers", key, len(snap.Jobs), len(snap.Workers))
+	return resp, nil
+}
+
+func (a *AdminServer) ImportSnapshot(ctx context.Context, req *orchestratorpb.ImportSnapshotRequest) (*orchestratorpb.SnapshotResponse, error) {
+	s := a.s
+	if s.objects == nil {
+		return &orchestratorpb.SnapshotResponse{Success: false, Message: "Snapshots need an object store; set MINIO_ENDPOINT"}, nil
+	}
+	if req.Key == "" {
+		return &orchestratorpb.SnapshotResponse{Success: false, Message: "key is required"}, nil
+	}
+
+	data, err := s.objects.Get(ctx, snapshotsBucket, req.Key)
+	if err != nil {
+		return &orchestratorpb.SnapshotResponse{Success: false, Message: fmt.Sprintf("Failed to read snapshot: %v", err)}, nil
+	}
+	snap, err := decodeSnapshot(data)
+	if err != nil {
+		return &orchestratorpb.SnapshotResponse{Success: false, Message: fmt.Sprintf("Malformed snapshot: %v", err)}, nil
+	}
+	if snap.FormatVersion < 1 || snap.FormatVersion > snapshotFormatVersion {
+		return &orchestratorpb.SnapshotResponse{
+			Success: false,
+			Message: fmt.Sprintf("Unsupported snapshot format %d; this orchestrator reads up to %d", snap.FormatVersion, snapshotFormatVersion),
+		}, nil
+	}
+
+	imported, skipped := 0, 0
+	for _, job := range snap.Jobs {
+		s.mu.RLock()
+		_, inMemory := s.jobs[job.JobID]
+		s.mu.RUnlock()
+		if inMemory {
+			skipped++
+			continue
+		}
+
+		// Saving is conditional on the stored version, so take it over
+		existing, err := s.store.LoadJob(ctx, job.JobID)
+		if err == nil && !req.Overwrite {
+			skipped++
+			continue
+		}
+		job.Version = 0
+		if err == nil {
+			job.Version = existing.Version
+		}
+		if err := s.store.SaveJob(ctx, job); err != nil {
+			log.Printf("Warning: Failed to import job %s: %v", job.JobID, err)
+			skipped++
+			continue
+		}
+		imported++
+	}
+
+	for _, usage := range snap.Usage {
+		if !req.Overwrite {
+			if existing, err := s.store.LoadUserUsage(ctx, usage.UserID); err != nil || existing != nil {
+				continue
+			}
+		}
+		if err := s.store.SaveUserUsage(ctx, usage); err != nil {
+			log.Printf("Warning: Failed to import usage of user %s: %v", usage.UserID, err)
+			continue
+		}
+		s.mu.Lock()
+		delete(s.usage, usage.UserID)
+		s.mu.Unlock()
+	}
+
+	// Unfinished imported jobs are scheduled like a new leader's
+	s.rehydrate(ctx)
+
+	s.mu.Lock()
+	s.audit(req.Operator, "", JobEvent{
+		Message: fmt.Sprintf("imported %d jobs from snapshot %s (%d skipped): %s", imported, req.Key, skipped, req.Reason),
+	})
+	s.mu.Unlock()
+
+	resp := snapshotResponse(snap, req.Key, len(data))
+	resp.SkippedJobs = int32(skipped)
+	resp.Message = fmt.Sprintf("Imported %d of %d jobs from snapshot %s", imported, len(snap.Jobs), req.Key)
+	return resp, nil
+}
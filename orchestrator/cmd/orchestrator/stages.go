@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"time"
+)
+
+// Task types. Training tasks are planned by the job's TaskPlanner; a job may
+// add a preprocessing task that runs before any of them, an evaluation task
+// that runs once they have all settled, and a validation task per epoch
+// that scores the epoch's merged weights on a held-out dataset.
+const (
+	TaskTypeTrain      = "train"
+	TaskTypePreprocess = "preprocess"
+	TaskTypeEvaluate   = "evaluate"
+	TaskTypeValidate   = "validate"
+)
+
+// taskType is the task's type; tasks saved before types existed train.
+func (t *Task) taskType() string {
+	if t.Type == "" {
+		return TaskTypeTrain
+	}
+	return t.Type
+}
+
+// isStage reports whether the task is a preprocessing, evaluation or
+// validation stage rather than training. Stages take no part in the epochs: their weights
+// are not aggregated and their metrics are not the job's.
+func (t *Task) isStage() bool {
+	return t.taskType() != TaskTypeTrain
+}
+
+// validateStages checks a new job's stage list.
+func validateStages(stages []string) error {
+	seen := make(map[string]bool, len(stages))
+	for _, stage := range stages {
+		if stage != TaskTypePreprocess && stage != TaskTypeEvaluate {
+			return fmt.Errorf("unknown stage %q: must be %q or %q", stage, TaskTypePreprocess, TaskTypeEvaluate)
+		}
+		if seen[stage] {
+			return fmt.Errorf("duplicate stage: %s", stage)
+		}
+		seen[stage] = true
+	}
+	return nil
+}
+
+// addStageTasks puts the job's stage tasks around its planned training
+// tasks. Preprocessing and evaluation cover the whole dataset: the one in
+// epoch 0, the other in the last epoch, with the final weights. Validation
+// tasks cover the whole held-out dataset, one in each epoch.
+func (j *Job) addStageTasks(layout shardPlan) {
+	now := time.Now()
+	end := datasetEnd(layout)
+	if slices.Contains(j.Stages, TaskTypePreprocess) {
+		task := j.newTask(0, 0, end, now)
+		task.Type = TaskTypePreprocess
+		j.Tasks = append([]*Task{task}, j.Tasks...)
+	}
+	if j.ValidationDatasetPath != "" {
+		for epoch := int32(0); epoch < j.Epochs; epoch++ {
+			task := j.newTask(epoch, 0, 0, now)
+			task.Type = TaskTypeValidate
+			j.Tasks = append(j.Tasks, task)
+		}
+	}
+	if slices.Contains(j.Stages, TaskTypeEvaluate) {
+		task := j.newTask(max(j.Epochs-1, 0), 0, end, now)
+		task.Type = TaskTypeEvaluate
+		j.Tasks = append(j.Tasks, task)
+	}
+}
+
+// preprocessed reports whether the job's training may start: it has no
+// preprocessing stage or that has completed.
+func (j *Job) preprocessed() bool {
+	for _, task := range j.Tasks {
+		if task.taskType() == TaskTypePreprocess && task.Status != TaskStatusCompleted {
+			return false
+		}
+	}
+	return true
+}
+
+// preprocessFailed reports whether the job's preprocessing failed for good,
+// which leaves its training nothing to run on.
+func (j *Job) preprocessFailed() bool {
+	for _, task := range j.Tasks {
+		if task.taskType() == TaskTypePreprocess && task.Status == TaskStatusFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// trainingSettled reports whether no training task is still waiting or
+// running.
+func (j *Job) trainingSettled() bool {
+	for _, task := range j.Tasks {
+		if !task.isStage() && (task.Status == TaskStatusPending || task.Status == TaskStatusAssigned) {
+			return false
+		}
+	}
+	return true
+}
+
+// onStageSettled records the outcome of a stage task that completed.
+// Caller must hold s.mu.
+func (s *OrchestratorServer) onStageSettled(job *Job, task *Task) {
+	switch task.taskType() {
+	case TaskTypePreprocess:
+		s.recordEvent(job.JobID, JobEvent{
+			Type:    EventStageCompleted,
+			Message: "Preprocessing completed",
+			TaskID:  task.TaskID,
+		})
+	case TaskTypeEvaluate:
+		s.recordEvent(job.JobID, JobEvent{
+			Type:    EventStageCompleted,
+			Message: fmt.Sprintf("Evaluation completed: loss=%.4f, accuracy=%.4f", task.Loss, task.Accuracy),
+			TaskID:  task.TaskID,
+		})
+	}
+}
+
+// releaseStages queues the tasks a settled task has unblocked: the
+// training tasks once preprocessing completes, an epoch's validation once
+// the epoch has settled, and the evaluation once training has settled.
+// Caller must hold s.mu.
+func (s *OrchestratorServer) releaseStages(job *Job, settled *Task) {
+	if !job.isActive() {
+		return
+	}
+	released := 0
+	for _, task := range job.Tasks {
+		if task.Status != TaskStatusPending || !job.dispatchable(task) {
+			continue
+		}
+		switch {
+		case settled.taskType() == TaskTypePreprocess && !task.isStage():
+		case !settled.isStage() && task.taskType() == TaskTypeEvaluate:
+		case !settled.isStage() && task.taskType() == TaskTypeValidate && task.Epoch == settled.Epoch:
+			task.weights = job.ModelWeights
+		default:
+			continue
+		}
+		s.enqueueTask(job, task)
+		released++
+	}
+	if released > 0 {
+		log.Printf("Job %s settled %s task %s, released %d tasks", job.JobID, settled.taskType(), settled.TaskID, released)
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// jobStallTimeout is how long a RUNNING job may go without a task completing
+// before the watchdog marks it STALLED and raises a JOB_STALLED event. Tasks
+// held by workers that are gone are requeued at the same time. The job keeps
+// dispatching and returns to RUNNING with the next completed task. Zero
+// disables the check; unlike progress_timeout_seconds it never cancels.
+var jobStallTimeout = getEnvDuration("JOB_STALL_TIMEOUT", 15*time.Minute)
+
+// stallClock is when the job last made progress or entered its current
+// status, whichever is later, so time spent paused or waiting for resources
+// does not count.
+func (j *Job) stallClock() time.Time {
+	since := j.LastProgressAt
+	if n := len(j.Transitions); n > 0 && j.Transitions[n-1].At.After(since) {
+		since = j.Transitions[n-1].At
+	}
+	return since
+}
+
+func (s *OrchestratorServer) detectStalledJobs(ctx context.Context) {
+	if jobStallTimeout <= 0 {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for _, job := range s.jobs {
+		if job.Status != JobStatusRunning || now.Sub(job.stallClock()) < jobStallTimeout {
+			continue
+		}
+
+		requeued := s.requeueAbandonedTasks(job)
+		reason := fmt.Sprintf("No task completed for %s: %s", jobStallTimeout, s.stallDiagnosis(job))
+		if requeued > 0 {
+			reason += fmt.Sprintf("; requeued %d tasks from workers that are gone", requeued)
+		}
+		if !job.mustTransition(JobStatusStalled, reason) {
+			continue
+		}
+
+		log.Printf("Warning: Job %s stalled: %s", job.JobID, reason)
+		s.recordEvent(job.JobID, JobEvent{Type: EventJobStalled, Message: reason})
+		if err := s.saveJob(ctx, job); err != nil {
+			log.Printf("Warning: Failed to save job: %v", err)
+		}
+	}
+}
+
+// stallDiagnosis explains as well as it can why a job is not progressing.
+// Caller must hold s.mu.
+func (s *OrchestratorServer) stallDiagnosis(job *Job) string {
+	if !s.hasCapableWorker(job.Requirements) {
+		return "no live worker can run its tasks"
+	}
+
+	assigned, waiting := 0, 0
+	for _, task := range job.Tasks {
+		switch {
+		case task.Status == TaskStatusAssigned:
+			assigned++
+		case task.Status == TaskStatusPending && job.dispatchable(task):
+			waiting++
+		}
+	}
+	switch {
+	case assigned > 0:
+		return fmt.Sprintf("%d tasks assigned to workers but none reported", assigned)
+	case waiting > 0:
+		return fmt.Sprintf("%d tasks waiting for a worker", waiting)
+	default:
+		return "no task is ready to run"
+	}
+}
+
+// requeueAbandonedTasks takes back the job's tasks assigned to workers that
+// are offline or no longer known, without waiting for their leases to
+// expire. Caller must hold s.mu.
+func (s *OrchestratorServer) requeueAbandonedTasks(job *Job) int {
+	requeued := 0
+	for _, task := range job.Tasks {
+		if task.Status != TaskStatusAssigned {
+			continue
+		}
+		if worker, ok := s.workers[task.WorkerID]; ok && worker.Status != WorkerStatusOffline {
+			continue
+		}
+		s.reclaimTask(job, task, "worker went offline")
+		s.enqueueTask(job, task)
+		requeued++
+	}
+	return requeued
+}
+
+// recoverStalledJob returns a STALLED job to RUNNING once a task completes.
+// Caller must hold s.mu.
+func (s *OrchestratorServer) recoverStalledJob(job *Job) {
+	if job.Status != JobStatusStalled || !job.mustTransition(JobStatusRunning, "") {
+		return
+	}
+	log.Printf("Job %s is progressing again", job.JobID)
+	s.recordEvent(job.JobID, JobEvent{Type: EventJobRecovered, Message: "A task completed; the job is progressing again"})
+}
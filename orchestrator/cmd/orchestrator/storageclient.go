@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+
+	storagepb "github.com/tensorfleet/orchestrator/proto/storage"
+)
+
+// Models and checkpoints are recorded through the storage service's
+// StorageService; the weights themselves go to the object store when there
+// is one and travel in the call otherwise.
+
+// storageAuthToken is the secret shared with the storage service, sent with
+// every StorageService call. The service requires it when it has one set.
+var storageAuthToken = os.Getenv("STORAGE_AUTH_TOKEN")
+
+// storageMaxMessage bounds a StorageService call, which may carry model
+// weights. It matches what the storage service accepts.
+const storageMaxMessage = 256 << 20
+
+// storageServiceConfig retries StorageService calls the service could not
+// be reached for, with exponential backoff. SaveModel and SaveCheckpoint
+// return what an earlier attempt recorded, so a call whose answer was lost
+// is safe to retry as well.
+const storageServiceConfig = `{
+	"methodConfig": [{
+		"name": [{"service": "storage.StorageService"}],
+		"timeout": "30s",
+		"retryPolicy": {
+			"maxAttempts": 5,
+			"initialBackoff": "0.5s",
+			"maxBackoff": "10s",
+			"backoffMultiplier": 2,
+			"retryableStatusCodes": ["UNAVAILABLE"]
+		}
+	}]
+}`
+
+// storageServiceAddr is the gRPC address of the storage service.
+func storageServiceAddr() string {
+	if addr := os.Getenv("STORAGE_SERVICE_ADDR"); addr != "" {
+		return addr
+	}
+	return "storage:50053"
+}
+
+// newStorageClient returns a StorageService client. The connection is made
+// on first use, so the storage service need not be up yet.
+func newStorageClient() (storagepb.StorageServiceClient, error) {
+	addr := storageServiceAddr()
+	conn, err := grpc.Dial(addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultServiceConfig(storageServiceConfig),
+		grpc.WithDefaultCallOptions(
+			grpc.MaxCallSendMsgSize(storageMaxMessage),
+			// Calls with weights larger than the buffer would not be retried
+			grpc.MaxRetryRPCBufferSize(storageMaxMessage),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to storage service at %s: %v", addr, err)
+	}
+	log.Printf("Saving models and checkpoints through the storage service at %s", addr)
+	return storagepb.NewStorageServiceClient(conn), nil
+}
+
+func withStorageAuth(ctx context.Context) context.Context {
+	if storageAuthToken == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, authMetadataKey, "Bearer "+storageAuthToken)
+}
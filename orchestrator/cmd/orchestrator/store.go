@@ -0,0 +1,593 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// JobStore persists jobs together with their event logs and metric history.
+// In-memory state in OrchestratorServer stays authoritative while the
+// orchestrator runs; the store serves jobs that are no longer in memory.
+type JobStore interface {
+	// SaveJob writes the job if the stored record is still at job.Version
+	// and then advances job.Version. Otherwise it returns a
+	// *VersionConflictError and leaves the record and job.Version alone.
+	SaveJob(ctx context.Context, job *Job) error
+	LoadJob(ctx context.Context, jobID string) (*Job, error)
+	// LoadActiveJobs returns every stored job that has not finished.
+	LoadActiveJobs(ctx context.Context) ([]*Job, error)
+
+	AppendEvent(ctx context.Context, jobID string, event JobEvent) error
+	ListEvents(ctx context.Context, jobID string) ([]JobEvent, error)
+
+	AppendMetricSample(ctx context.Context, jobID string, sample MetricSample) error
+	// ListMetricSamples returns samples recorded at or after sinceMs
+	// (Unix milliseconds; 0 for all), oldest first.
+	ListMetricSamples(ctx context.Context, jobID string, sinceMs int64) ([]MetricSample, error)
+
+	// ListFinishedJobs returns the IDs of finished jobs last updated before
+	// the given time.
+	ListFinishedJobs(ctx context.Context, updatedBefore time.Time) ([]string, error)
+	// ListJobs returns the stored jobs matching the filter on the page,
+	// newest first, and how many stored jobs match in all, skipped ones
+	// aside. Only the jobs on the page are decoded.
+	ListJobs(ctx context.Context, filter JobFilter, page JobPage) ([]*Job, int, error)
+	// DeleteJob removes a job together with its events and metrics.
+	DeleteJob(ctx context.Context, jobID string) error
+
+	SaveUserUsage(ctx context.Context, usage *UserUsage) error
+	// LoadUserUsage returns nil and no error for a user without a record.
+	LoadUserUsage(ctx context.Context, userID string) (*UserUsage, error)
+	ListUserUsage(ctx context.Context) ([]*UserUsage, error)
+
+	Close() error
+}
+
+// JobFilter selects jobs for ListJobs; zero fields match everything.
+type JobFilter struct {
+	UserID    string
+	Namespace string
+	ModelType string
+	Statuses  []JobStatus
+}
+
+// JobPage selects a page of the newest-first job order.
+type JobPage struct {
+	After *jobCursor      // Last job of the previous page; nil for the first page
+	Limit int             // Jobs on the page; 0 for all
+	Skip  map[string]bool // IDs of jobs to leave out, e.g. those in memory
+}
+
+func (f JobFilter) matches(job *Job) bool {
+	if f.UserID != "" && job.UserID != f.UserID {
+		return false
+	}
+	if f.Namespace != "" && job.namespace() != f.Namespace {
+		return false
+	}
+	if f.ModelType != "" && job.ModelType != f.ModelType {
+		return false
+	}
+	if len(f.Statuses) == 0 {
+		return true
+	}
+	for _, st := range f.Statuses {
+		if job.Status == st {
+			return true
+		}
+	}
+	return false
+}
+
+// VersionConflictError is returned by SaveJob when the stored record was
+// written by someone else since the job was last saved or loaded.
+type VersionConflictError struct {
+	JobID    string
+	Expected int64 // Version the save was based on
+	Stored   int64 // Version currently stored
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("job %s was saved as version %d since version %d", e.JobID, e.Stored, e.Expected)
+}
+
+// newJobStore builds the store selected by JOB_STORE. Redis is the default;
+// "postgres" keeps durable history in the database named by POSTGRES_DSN.
+func newJobStore() (JobStore, error) {
+	switch backend := os.Getenv("JOB_STORE"); backend {
+	case "", "redis":
+		return newRedisJobStore(), nil
+	case "postgres":
+		dsn := os.Getenv("POSTGRES_DSN")
+		if dsn == "" {
+			return nil, fmt.Errorf("JOB_STORE=postgres requires POSTGRES_DSN")
+		}
+		return newPostgresJobStore(dsn)
+	default:
+		return nil, fmt.Errorf("unknown JOB_STORE: %s", backend)
+	}
+}
+
+// RedisJobStore keeps each job as a JSON document with a list of events and
+// a sorted set of metric samples beside it, all expiring after recordTTL as
+// a backstop to the archiver. User usage records do not expire. Stored jobs
+// are indexed in a hash of summaries by ID, so listing them neither scans
+// the keyspace nor decodes jobs that are not listed; IDs whose records have
+// expired are pruned as they are found.
+type RedisJobStore struct {
+	client *redis.Client
+}
+
+func newRedisJobStore() *RedisJobStore {
+	redisAddr := os.Getenv("REDIS_ADDR")
+	if redisAddr == "" {
+		redisAddr = "redis:6379"
+	}
+
+	rdb := redis.NewClient(&redis.Options{
+		Addr: redisAddr,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	store := &RedisJobStore{client: rdb}
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		log.Printf("Warning: Redis connection failed: %v", err)
+		return store
+	}
+	log.Println("Connected to Redis successfully")
+
+	indexCtx, cancelIndex := context.WithTimeout(context.Background(), time.Minute)
+	defer cancelIndex()
+	if err := store.backfillIndex(indexCtx); err != nil {
+		log.Printf("Warning: Failed to build the job index: %v", err)
+	}
+	return store
+}
+
+func jobKey(jobID string) string {
+	return "job:" + jobID
+}
+
+func jobEventsKey(jobID string) string {
+	return "job:" + jobID + ":events"
+}
+
+func metricsHistoryKey(jobID string) string {
+	return "job:" + jobID + ":metrics"
+}
+
+func jobVersionKey(jobID string) string {
+	return "job:" + jobID + ":version"
+}
+
+// jobIndexKey holds the summary of every stored job by ID.
+const jobIndexKey = "jobs:summaries"
+
+// legacyJobIndexKey is the set of job IDs earlier releases indexed jobs in.
+// It is replaced by jobIndexKey when that is built.
+const legacyJobIndexKey = "jobs:index"
+
+// jobSummary is what the job index keeps of a job: what jobs are filtered
+// and ordered by when they are listed.
+type jobSummary struct {
+	UserID    string    `json:"user_id,omitempty"`
+	Namespace string    `json:"namespace,omitempty"`
+	ModelType string    `json:"model_type,omitempty"`
+	Status    JobStatus `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func summarizeJob(job *Job) jobSummary {
+	return jobSummary{
+		UserID:    job.UserID,
+		Namespace: job.Namespace,
+		ModelType: job.ModelType,
+		Status:    job.Status,
+		CreatedAt: job.CreatedAt,
+	}
+}
+
+// job is a stand-in for the summarized job, good for filtering and
+// ordering it.
+func (s jobSummary) job(jobID string) *Job {
+	return &Job{
+		JobID:     jobID,
+		UserID:    s.UserID,
+		Namespace: s.Namespace,
+		ModelType: s.ModelType,
+		Status:    s.Status,
+		CreatedAt: s.CreatedAt,
+	}
+}
+
+// jobIndexBatch is how many job records are fetched per round trip.
+const jobIndexBatch = 100
+
+// isJobRecordKey tells job records apart from the keys kept beside them.
+func isJobRecordKey(key string) bool {
+	return !strings.HasSuffix(key, ":events") && !strings.HasSuffix(key, ":metrics") &&
+		!strings.HasSuffix(key, ":version")
+}
+
+// saveJobScript writes a job record (KEYS[1]) and its version (KEYS[2])
+// if the stored version is still ARGV[1], and indexes the job ID (ARGV[4])
+// with its summary (ARGV[5]) in KEYS[3]. It returns {1, new version} on
+// success and {0, stored version} on conflict.
+var saveJobScript = redis.NewScript(`
+local stored = tonumber(redis.call("GET", KEYS[2]) or "0")
+if stored ~= tonumber(ARGV[1]) then
+	return {0, stored}
+end
+redis.call("SET", KEYS[1], ARGV[2], "PX", ARGV[3])
+redis.call("SET", KEYS[2], stored + 1, "PX", ARGV[3])
+redis.call("HSET", KEYS[3], ARGV[4], ARGV[5])
+return {1, stored + 1}`)
+
+func (r *RedisJobStore) SaveJob(ctx context.Context, job *Job) error {
+	expected := job.Version
+	job.Version = expected + 1
+	data, err := json.Marshal(job)
+	job.Version = expected
+	if err != nil {
+		return err
+	}
+	summary, err := json.Marshal(summarizeJob(job))
+	if err != nil {
+		return err
+	}
+
+	result, err := saveJobScript.Run(ctx, r.client,
+		[]string{jobKey(job.JobID), jobVersionKey(job.JobID), jobIndexKey},
+		expected, data, recordTTL().Milliseconds(), job.JobID, summary).Int64Slice()
+	if err != nil {
+		return err
+	}
+	if result[0] == 0 {
+		return &VersionConflictError{JobID: job.JobID, Expected: expected, Stored: result[1]}
+	}
+	job.Version = result[1]
+	return nil
+}
+
+func (r *RedisJobStore) LoadJob(ctx context.Context, jobID string) (*Job, error) {
+	data, err := r.client.Get(ctx, jobKey(jobID)).Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	var job Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+func (r *RedisJobStore) LoadActiveJobs(ctx context.Context) ([]*Job, error) {
+	var jobs []*Job
+	err := r.eachJob(ctx, func(job *Job) {
+		if !job.Status.IsTerminal() {
+			jobs = append(jobs, job)
+		}
+	})
+	return jobs, err
+}
+
+// backfillIndex adds the jobs stored before the index existed to it. It
+// scans the keyspace once; later starts find the index and skip the scan.
+func (r *RedisJobStore) backfillIndex(ctx context.Context) error {
+	exists, err := r.client.Exists(ctx, jobIndexKey).Result()
+	if err != nil || exists > 0 {
+		return err
+	}
+
+	var keys []string
+	iter := r.client.Scan(ctx, 0, jobKey("*"), 100).Iterator()
+	for iter.Next(ctx) {
+		if key := iter.Val(); isJobRecordKey(key) {
+			keys = append(keys, key)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+
+	indexed := 0
+	for start := 0; start < len(keys); start += jobIndexBatch {
+		end := start + jobIndexBatch
+		if end > len(keys) {
+			end = len(keys)
+		}
+		values, err := r.client.MGet(ctx, keys[start:end]...).Result()
+		if err != nil {
+			return err
+		}
+		summaries := make(map[string]interface{}, len(values))
+		for _, value := range values {
+			var job Job
+			if data, ok := value.(string); !ok || json.Unmarshal([]byte(data), &job) != nil || job.JobID == "" {
+				continue
+			}
+			summary, err := json.Marshal(summarizeJob(&job))
+			if err != nil {
+				return err
+			}
+			summaries[job.JobID] = summary
+		}
+		if len(summaries) == 0 {
+			continue
+		}
+		if err := r.client.HSet(ctx, jobIndexKey, summaries).Err(); err != nil {
+			return err
+		}
+		indexed += len(summaries)
+	}
+	if err := r.client.Del(ctx, legacyJobIndexKey).Err(); err != nil {
+		log.Printf("Warning: Failed to delete the old job index: %v", err)
+	}
+	if indexed > 0 {
+		log.Printf("Indexed %d stored jobs", indexed)
+	}
+	return nil
+}
+
+// eachJob calls fn for every indexed job record, fetching them in batches.
+// IDs whose records have expired are removed from the index; records of
+// other services kept under job:<id> are skipped.
+func (r *RedisJobStore) eachJob(ctx context.Context, fn func(*Job)) error {
+	jobIDs, err := r.client.HKeys(ctx, jobIndexKey).Result()
+	if err != nil {
+		return err
+	}
+
+	var expired []string
+	for start := 0; start < len(jobIDs); start += jobIndexBatch {
+		end := start + jobIndexBatch
+		if end > len(jobIDs) {
+			end = len(jobIDs)
+		}
+		keys := make([]string, 0, end-start)
+		for _, id := range jobIDs[start:end] {
+			keys = append(keys, jobKey(id))
+		}
+		values, err := r.client.MGet(ctx, keys...).Result()
+		if err != nil {
+			return err
+		}
+
+		for i, value := range values {
+			id := jobIDs[start+i]
+			data, ok := value.(string)
+			if !ok {
+				expired = append(expired, id)
+				continue
+			}
+			var job Job
+			if err := json.Unmarshal([]byte(data), &job); err != nil {
+				log.Printf("Warning: Skipping unreadable job record %s: %v", keys[i], err)
+				continue
+			}
+			if job.JobID != "" {
+				fn(&job)
+			}
+		}
+	}
+
+	r.pruneIndex(ctx, expired)
+	return nil
+}
+
+// pruneIndex removes the IDs of jobs whose records have expired from the
+// index.
+func (r *RedisJobStore) pruneIndex(ctx context.Context, expired []string) {
+	if len(expired) == 0 {
+		return
+	}
+	if err := r.client.HDel(ctx, jobIndexKey, expired...).Err(); err != nil {
+		log.Printf("Warning: Failed to prune the job index: %v", err)
+	}
+}
+
+func (r *RedisJobStore) AppendEvent(ctx context.Context, jobID string, event JobEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	key := jobEventsKey(jobID)
+	pipe := r.client.TxPipeline()
+	pipe.RPush(ctx, key, data)
+	pipe.Expire(ctx, key, recordTTL())
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+func (r *RedisJobStore) ListEvents(ctx context.Context, jobID string) ([]JobEvent, error) {
+	entries, err := r.client.LRange(ctx, jobEventsKey(jobID), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]JobEvent, 0, len(entries))
+	for _, entry := range entries {
+		var event JobEvent
+		if err := json.Unmarshal([]byte(entry), &event); err != nil {
+			log.Printf("Warning: Skipping malformed event for job %s: %v", jobID, err)
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func (r *RedisJobStore) AppendMetricSample(ctx context.Context, jobID string, sample MetricSample) error {
+	data, err := json.Marshal(sample)
+	if err != nil {
+		return err
+	}
+
+	key := metricsHistoryKey(jobID)
+	pipe := r.client.TxPipeline()
+	pipe.ZAdd(ctx, key, &redis.Z{Score: float64(sample.Timestamp), Member: data})
+	pipe.Expire(ctx, key, recordTTL())
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+func (r *RedisJobStore) ListMetricSamples(ctx context.Context, jobID string, sinceMs int64) ([]MetricSample, error) {
+	minScore := "-inf"
+	if sinceMs > 0 {
+		minScore = strconv.FormatInt(sinceMs, 10)
+	}
+	members, err := r.client.ZRangeByScore(ctx, metricsHistoryKey(jobID), &redis.ZRangeBy{
+		Min: minScore,
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]MetricSample, 0, len(members))
+	for _, member := range members {
+		var sample MetricSample
+		if err := json.Unmarshal([]byte(member), &sample); err != nil {
+			log.Printf("Warning: Skipping malformed metric sample for job %s: %v", jobID, err)
+			continue
+		}
+		samples = append(samples, sample)
+	}
+	return samples, nil
+}
+
+func (r *RedisJobStore) ListFinishedJobs(ctx context.Context, updatedBefore time.Time) ([]string, error) {
+	var jobIDs []string
+	err := r.eachJob(ctx, func(job *Job) {
+		if job.Status.IsTerminal() && job.UpdatedAt.Before(updatedBefore) {
+			jobIDs = append(jobIDs, job.JobID)
+		}
+	})
+	return jobIDs, err
+}
+
+func (r *RedisJobStore) ListJobs(ctx context.Context, filter JobFilter, page JobPage) ([]*Job, int, error) {
+	index, err := r.client.HGetAll(ctx, jobIndexKey).Result()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var matched []*Job
+	for jobID, raw := range index {
+		if page.Skip[jobID] {
+			continue
+		}
+		var summary jobSummary
+		if err := json.Unmarshal([]byte(raw), &summary); err != nil {
+			log.Printf("Warning: Skipping unreadable summary of job %s: %v", jobID, err)
+			continue
+		}
+		if job := summary.job(jobID); filter.matches(job) {
+			matched = append(matched, job)
+		}
+	}
+	sortJobs(matched)
+	onPage, _ := page.of(matched)
+
+	// Only the jobs on the page are read
+	jobs := make([]*Job, 0, len(onPage))
+	var expired []string
+	for start := 0; start < len(onPage); start += jobIndexBatch {
+		end := start + jobIndexBatch
+		if end > len(onPage) {
+			end = len(onPage)
+		}
+		keys := make([]string, 0, end-start)
+		for _, job := range onPage[start:end] {
+			keys = append(keys, jobKey(job.JobID))
+		}
+		values, err := r.client.MGet(ctx, keys...).Result()
+		if err != nil {
+			return nil, 0, err
+		}
+		for i, value := range values {
+			data, ok := value.(string)
+			if !ok {
+				expired = append(expired, onPage[start+i].JobID)
+				continue
+			}
+			var job Job
+			if err := json.Unmarshal([]byte(data), &job); err != nil {
+				log.Printf("Warning: Skipping unreadable job record %s: %v", keys[i], err)
+				continue
+			}
+			jobs = append(jobs, &job)
+		}
+	}
+	r.pruneIndex(ctx, expired)
+	return jobs, len(matched) - len(expired), nil
+}
+
+func (r *RedisJobStore) DeleteJob(ctx context.Context, jobID string) error {
+	pipe := r.client.TxPipeline()
+	pipe.Del(ctx, jobKey(jobID), jobVersionKey(jobID), jobEventsKey(jobID), metricsHistoryKey(jobID))
+	pipe.HDel(ctx, jobIndexKey, jobID)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func userUsageKey(userID string) string {
+	return "usage:" + userID
+}
+
+func (r *RedisJobStore) SaveUserUsage(ctx context.Context, usage *UserUsage) error {
+	data, err := json.Marshal(usage)
+	if err != nil {
+		return err
+	}
+	return r.client.Set(ctx, userUsageKey(usage.UserID), data, 0).Err()
+}
+
+func (r *RedisJobStore) LoadUserUsage(ctx context.Context, userID string) (*UserUsage, error) {
+	data, err := r.client.Get(ctx, userUsageKey(userID)).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var usage UserUsage
+	if err := json.Unmarshal(data, &usage); err != nil {
+		return nil, err
+	}
+	return &usage, nil
+}
+
+func (r *RedisJobStore) ListUserUsage(ctx context.Context) ([]*UserUsage, error) {
+	var users []*UserUsage
+	iter := r.client.Scan(ctx, 0, userUsageKey("*"), 100).Iterator()
+	for iter.Next(ctx) {
+		usage, err := r.LoadUserUsage(ctx, strings.TrimPrefix(iter.Val(), "usage:"))
+		if err != nil {
+			log.Printf("Warning: Skipping unreadable usage record %s: %v", iter.Val(), err)
+			continue
+		}
+		if usage != nil {
+			users = append(users, usage)
+		}
+	}
+	return users, iter.Err()
+}
+
+func (r *RedisJobStore) Close() error {
+	return r.client.Close()
+}
@@ -0,0 +1,551 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// postgresSchema creates the tables used by PostgresJobStore. Jobs are kept
+// whole as JSON for reloading; tasks, events and metrics are also stored as
+// plain rows so history can be queried and reported on with SQL.
+const postgresSchema = `
+CREATE TABLE IF NOT EXISTS jobs (
+	job_id          TEXT PRIMARY KEY,
+	user_id         TEXT NOT NULL,
+	namespace       TEXT NOT NULL DEFAULT 'default',
+	model_type      TEXT NOT NULL,
+	dataset_path    TEXT NOT NULL,
+	status          TEXT NOT NULL,
+	status_message  TEXT NOT NULL DEFAULT '',
+	priority        INTEGER NOT NULL DEFAULT 0,
+	epochs          INTEGER NOT NULL,
+	total_tasks     INTEGER NOT NULL,
+	completed_tasks INTEGER NOT NULL,
+	failed_tasks    INTEGER NOT NULL,
+	current_loss    DOUBLE PRECISION NOT NULL DEFAULT 0,
+	current_accuracy DOUBLE PRECISION NOT NULL DEFAULT 0,
+	created_at      TIMESTAMPTZ NOT NULL,
+	updated_at      TIMESTAMPTZ NOT NULL,
+	version         BIGINT NOT NULL DEFAULT 0,
+	data            JSONB NOT NULL
+);
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS namespace TEXT NOT NULL DEFAULT 'default';
+CREATE INDEX IF NOT EXISTS jobs_user_id_idx ON jobs (user_id);
+CREATE INDEX IF NOT EXISTS jobs_namespace_idx ON jobs (namespace);
+CREATE INDEX IF NOT EXISTS jobs_status_idx ON jobs (status);
+CREATE INDEX IF NOT EXISTS jobs_created_at_idx ON jobs (created_at DESC, job_id);
+
+CREATE TABLE IF NOT EXISTS tasks (
+	task_id      TEXT PRIMARY KEY,
+	job_id       TEXT NOT NULL REFERENCES jobs (job_id) ON DELETE CASCADE,
+	epoch        INTEGER NOT NULL,
+	batch_start  INTEGER NOT NULL,
+	batch_end    INTEGER NOT NULL,
+	status       TEXT NOT NULL,
+	worker_id    TEXT NOT NULL DEFAULT '',
+	attempts     INTEGER NOT NULL DEFAULT 0,
+	loss         DOUBLE PRECISION NOT NULL DEFAULT 0,
+	accuracy     DOUBLE PRECISION NOT NULL DEFAULT 0,
+	last_error   TEXT NOT NULL DEFAULT '',
+	created_at   TIMESTAMPTZ NOT NULL,
+	completed_at TIMESTAMPTZ
+);
+CREATE INDEX IF NOT EXISTS tasks_job_id_idx ON tasks (job_id);
+
+CREATE TABLE IF NOT EXISTS job_events (
+	id         BIGSERIAL PRIMARY KEY,
+	job_id     TEXT NOT NULL,
+	type       TEXT NOT NULL,
+	message    TEXT NOT NULL,
+	task_id    TEXT NOT NULL DEFAULT '',
+	worker_id  TEXT NOT NULL DEFAULT '',
+	created_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX IF NOT EXISTS job_events_job_id_idx ON job_events (job_id, id);
+
+CREATE TABLE IF NOT EXISTS job_metrics (
+	id         BIGSERIAL PRIMARY KEY,
+	job_id     TEXT NOT NULL,
+	kind       TEXT NOT NULL,
+	epoch      INTEGER NOT NULL,
+	task_id    TEXT NOT NULL DEFAULT '',
+	loss       DOUBLE PRECISION NOT NULL,
+	accuracy   DOUBLE PRECISION NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX IF NOT EXISTS job_metrics_job_id_idx ON job_metrics (job_id, created_at);
+
+CREATE TABLE IF NOT EXISTS user_usage (
+	user_id          TEXT PRIMARY KEY,
+	task_seconds     DOUBLE PRECISION NOT NULL DEFAULT 0,
+	tasks_completed  INTEGER NOT NULL DEFAULT 0,
+	jobs_submitted   INTEGER NOT NULL DEFAULT 0,
+	quota_task_hours DOUBLE PRECISION,
+	updated_at       TIMESTAMPTZ NOT NULL
+);
+`
+
+// PostgresJobStore keeps jobs and their history in Postgres until the
+// archiver deletes them.
+// The driver is registered under the "postgres" name by building with
+// -tags postgres.
+type PostgresJobStore struct {
+	db *sql.DB
+
+	// The task rows of each job as last written, so saving a job only
+	// writes the tasks that changed since
+	savedMu sync.Mutex
+	saved   map[string]*savedTasks
+}
+
+// savedTasks are the task rows of a job as written with a version of it.
+type savedTasks struct {
+	version int64
+	rows    map[string]taskRow
+}
+
+// taskRow is what a task row holds that changes.
+type taskRow struct {
+	status      string
+	workerID    string
+	attempts    int
+	loss        float64
+	accuracy    float64
+	lastError   string
+	completedAt time.Time
+}
+
+func newTaskRow(task *Task) taskRow {
+	row := taskRow{
+		status:    task.Status,
+		workerID:  task.WorkerID,
+		attempts:  task.Attempts,
+		loss:      task.Loss,
+		accuracy:  task.Accuracy,
+		lastError: task.LastError,
+	}
+	if task.CompletedAt != nil {
+		row.completedAt = *task.CompletedAt
+	}
+	return row
+}
+
+// changedTasks returns the tasks of the job whose rows differ from those
+// written with the version it was loaded or last saved at, and the rows
+// they will have. Every task has changed if that version is not the one
+// this store wrote last, e.g. because another orchestrator saved the job.
+func (p *PostgresJobStore) changedTasks(job *Job) ([]*Task, map[string]taskRow) {
+	p.savedMu.Lock()
+	defer p.savedMu.Unlock()
+	var written map[string]taskRow
+	if saved := p.saved[job.JobID]; saved != nil && saved.version == job.Version {
+		written = saved.rows
+	}
+
+	var changed []*Task
+	rows := make(map[string]taskRow, len(job.Tasks))
+	for _, task := range job.Tasks {
+		row := newTaskRow(task)
+		rows[task.TaskID] = row
+		if prev, ok := written[task.TaskID]; !ok || prev != row {
+			changed = append(changed, task)
+		}
+	}
+	return changed, rows
+}
+
+func newPostgresJobStore(dsn string) (*PostgresJobStore, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open postgres (build with -tags postgres): %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("postgres connection failed: %v", err)
+	}
+	if _, err := db.ExecContext(ctx, postgresSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create postgres schema: %v", err)
+	}
+
+	log.Println("Connected to Postgres successfully")
+	return &PostgresJobStore{db: db, saved: make(map[string]*savedTasks)}, nil
+}
+
+func (p *PostgresJobStore) SaveJob(ctx context.Context, job *Job) error {
+	expected := job.Version
+	job.Version = expected + 1
+	data, err := json.Marshal(job)
+	job.Version = expected
+	if err != nil {
+		return err
+	}
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// The update only applies while the stored version is the expected one
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO jobs (job_id, user_id, model_type, dataset_path, status, status_message,
+			priority, epochs, total_tasks, completed_tasks, failed_tasks,
+			current_loss, current_accuracy, created_at, updated_at, version, data, namespace)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+		ON CONFLICT (job_id) DO UPDATE SET
+			status = EXCLUDED.status,
+			status_message = EXCLUDED.status_message,
+			priority = EXCLUDED.priority,
+			total_tasks = EXCLUDED.total_tasks,
+			completed_tasks = EXCLUDED.completed_tasks,
+			failed_tasks = EXCLUDED.failed_tasks,
+			current_loss = EXCLUDED.current_loss,
+			current_accuracy = EXCLUDED.current_accuracy,
+			updated_at = EXCLUDED.updated_at,
+			version = EXCLUDED.version,
+			data = EXCLUDED.data
+		WHERE jobs.version = $19`,
+		job.JobID, job.UserID, job.ModelType, job.DatasetPath, string(job.Status), job.StatusMessage,
+		job.Priority, job.Epochs, job.TotalTasks, job.CompletedTasks, job.FailedTasks,
+		job.CurrentLoss, job.CurrentAccuracy, job.CreatedAt, job.UpdatedAt, expected+1, data,
+		job.namespace(), expected)
+	if err != nil {
+		return fmt.Errorf("failed to save job row: %v", err)
+	}
+	if saved, err := result.RowsAffected(); err != nil {
+		return err
+	} else if saved == 0 {
+		var stored int64
+		if err := tx.QueryRowContext(ctx, `SELECT version FROM jobs WHERE job_id = $1`, job.JobID).Scan(&stored); err != nil {
+			return err
+		}
+		return &VersionConflictError{JobID: job.JobID, Expected: expected, Stored: stored}
+	}
+
+	changed, rows := p.changedTasks(job)
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO tasks (task_id, job_id, epoch, batch_start, batch_end, status, worker_id,
+			attempts, loss, accuracy, last_error, created_at, completed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (task_id) DO UPDATE SET
+			status = EXCLUDED.status,
+			worker_id = EXCLUDED.worker_id,
+			attempts = EXCLUDED.attempts,
+			loss = EXCLUDED.loss,
+			accuracy = EXCLUDED.accuracy,
+			last_error = EXCLUDED.last_error,
+			completed_at = EXCLUDED.completed_at`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, task := range changed {
+		_, err := stmt.ExecContext(ctx,
+			task.TaskID, job.JobID, task.Epoch, task.BatchStart, task.BatchEnd, task.Status, task.WorkerID,
+			task.Attempts, task.Loss, task.Accuracy, task.LastError, task.CreatedAt, task.CompletedAt)
+		if err != nil {
+			return fmt.Errorf("failed to save task %s: %v", task.TaskID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	job.Version = expected + 1
+	p.savedMu.Lock()
+	p.saved[job.JobID] = &savedTasks{version: job.Version, rows: rows}
+	p.savedMu.Unlock()
+	return nil
+}
+
+func (p *PostgresJobStore) LoadJob(ctx context.Context, jobID string) (*Job, error) {
+	var data []byte
+	err := p.db.QueryRowContext(ctx, `SELECT data FROM jobs WHERE job_id = $1`, jobID).Scan(&data)
+	if err != nil {
+		return nil, err
+	}
+
+	var job Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, err
+	}
+
+	return &job, nil
+}
+
+func (p *PostgresJobStore) LoadActiveJobs(ctx context.Context) ([]*Job, error) {
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT data FROM jobs WHERE status NOT IN ($1, $2, $3, $4)`,
+		string(JobStatusCompleted), string(JobStatusCompletedEarly), string(JobStatusFailed), string(JobStatusCancelled))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []*Job
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		var job Job
+		if err := json.Unmarshal(data, &job); err != nil {
+			log.Printf("Warning: Skipping unreadable job record: %v", err)
+			continue
+		}
+		jobs = append(jobs, &job)
+	}
+	return jobs, rows.Err()
+}
+
+func (p *PostgresJobStore) AppendEvent(ctx context.Context, jobID string, event JobEvent) error {
+	_, err := p.db.ExecContext(ctx, `
+		INSERT INTO job_events (job_id, type, message, task_id, worker_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		jobID, event.Type, event.Message, event.TaskID, event.WorkerID, time.UnixMilli(event.Timestamp))
+	return err
+}
+
+func (p *PostgresJobStore) ListEvents(ctx context.Context, jobID string) ([]JobEvent, error) {
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT type, message, task_id, worker_id, created_at
+		FROM job_events WHERE job_id = $1 ORDER BY id`, jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []JobEvent
+	for rows.Next() {
+		var event JobEvent
+		var at time.Time
+		if err := rows.Scan(&event.Type, &event.Message, &event.TaskID, &event.WorkerID, &at); err != nil {
+			return nil, err
+		}
+		event.Timestamp = at.UnixMilli()
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+func (p *PostgresJobStore) AppendMetricSample(ctx context.Context, jobID string, sample MetricSample) error {
+	_, err := p.db.ExecContext(ctx, `
+		INSERT INTO job_metrics (job_id, kind, epoch, task_id, loss, accuracy, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		jobID, sample.Kind, sample.Epoch, sample.TaskID, sample.Loss, sample.Accuracy, time.UnixMilli(sample.Timestamp))
+	return err
+}
+
+func (p *PostgresJobStore) ListMetricSamples(ctx context.Context, jobID string, sinceMs int64) ([]MetricSample, error) {
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT kind, epoch, task_id, loss, accuracy, created_at
+		FROM job_metrics WHERE job_id = $1 AND created_at >= $2
+		ORDER BY created_at, id`, jobID, time.UnixMilli(sinceMs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var samples []MetricSample
+	for rows.Next() {
+		var sample MetricSample
+		var at time.Time
+		if err := rows.Scan(&sample.Kind, &sample.Epoch, &sample.TaskID, &sample.Loss, &sample.Accuracy, &at); err != nil {
+			return nil, err
+		}
+		sample.Timestamp = at.UnixMilli()
+		samples = append(samples, sample)
+	}
+	return samples, rows.Err()
+}
+
+func (p *PostgresJobStore) ListFinishedJobs(ctx context.Context, updatedBefore time.Time) ([]string, error) {
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT job_id FROM jobs WHERE status IN ($1, $2, $3, $4) AND updated_at < $5`,
+		string(JobStatusCompleted), string(JobStatusCompletedEarly), string(JobStatusFailed), string(JobStatusCancelled),
+		updatedBefore)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobIDs []string
+	for rows.Next() {
+		var jobID string
+		if err := rows.Scan(&jobID); err != nil {
+			return nil, err
+		}
+		jobIDs = append(jobIDs, jobID)
+	}
+	return jobIDs, rows.Err()
+}
+
+func (p *PostgresJobStore) ListJobs(ctx context.Context, filter JobFilter, page JobPage) ([]*Job, int, error) {
+	where := " WHERE TRUE"
+	var args []interface{}
+	if filter.UserID != "" {
+		args = append(args, filter.UserID)
+		where += fmt.Sprintf(" AND user_id = $%d", len(args))
+	}
+	if filter.Namespace != "" {
+		args = append(args, filter.Namespace)
+		where += fmt.Sprintf(" AND namespace = $%d", len(args))
+	}
+	if filter.ModelType != "" {
+		args = append(args, filter.ModelType)
+		where += fmt.Sprintf(" AND model_type = $%d", len(args))
+	}
+	if len(filter.Statuses) > 0 {
+		placeholders := make([]string, len(filter.Statuses))
+		for i, st := range filter.Statuses {
+			args = append(args, string(st))
+			placeholders[i] = fmt.Sprintf("$%d", len(args))
+		}
+		where += " AND status IN (" + strings.Join(placeholders, ", ") + ")"
+	}
+	if len(page.Skip) > 0 {
+		placeholders := make([]string, 0, len(page.Skip))
+		for jobID := range page.Skip {
+			args = append(args, jobID)
+			placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+		}
+		where += " AND job_id NOT IN (" + strings.Join(placeholders, ", ") + ")"
+	}
+
+	var total int
+	if err := p.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM jobs"+where, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	query := "SELECT data FROM jobs" + where
+	if page.After != nil {
+		args = append(args, time.UnixMicro(page.After.createdAt).UTC(), page.After.jobID)
+		query += fmt.Sprintf(" AND (created_at < $%d OR (created_at = $%d AND job_id > $%d))", len(args)-1, len(args)-1, len(args))
+	}
+	query += " ORDER BY created_at DESC, job_id"
+	if page.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", page.Limit)
+	}
+
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var jobs []*Job
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, 0, err
+		}
+		var job Job
+		if err := json.Unmarshal(data, &job); err != nil {
+			log.Printf("Warning: Skipping unreadable job record: %v", err)
+			continue
+		}
+		jobs = append(jobs, &job)
+	}
+	return jobs, total, rows.Err()
+}
+
+func (p *PostgresJobStore) DeleteJob(ctx context.Context, jobID string) error {
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Tasks go with the job row
+	for _, query := range []string{
+		`DELETE FROM job_events WHERE job_id = $1`,
+		`DELETE FROM job_metrics WHERE job_id = $1`,
+		`DELETE FROM jobs WHERE job_id = $1`,
+	} {
+		if _, err := tx.ExecContext(ctx, query, jobID); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	p.savedMu.Lock()
+	delete(p.saved, jobID)
+	p.savedMu.Unlock()
+	return nil
+}
+
+func (p *PostgresJobStore) SaveUserUsage(ctx context.Context, usage *UserUsage) error {
+	_, err := p.db.ExecContext(ctx, `
+		INSERT INTO user_usage (user_id, task_seconds, tasks_completed, jobs_submitted,
+			quota_task_hours, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id) DO UPDATE SET
+			task_seconds = EXCLUDED.task_seconds,
+			tasks_completed = EXCLUDED.tasks_completed,
+			jobs_submitted = EXCLUDED.jobs_submitted,
+			quota_task_hours = EXCLUDED.quota_task_hours,
+			updated_at = EXCLUDED.updated_at`,
+		usage.UserID, usage.TaskSeconds, usage.TasksCompleted, usage.JobsSubmitted,
+		usage.QuotaTaskHours, usage.UpdatedAt)
+	return err
+}
+
+const userUsageColumns = `user_id, task_seconds, tasks_completed, jobs_submitted, quota_task_hours, updated_at`
+
+func scanUserUsage(row interface{ Scan(...interface{}) error }) (*UserUsage, error) {
+	var usage UserUsage
+	var quota sql.NullFloat64
+	if err := row.Scan(&usage.UserID, &usage.TaskSeconds, &usage.TasksCompleted, &usage.JobsSubmitted,
+		&quota, &usage.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if quota.Valid {
+		usage.QuotaTaskHours = &quota.Float64
+	}
+	return &usage, nil
+}
+
+func (p *PostgresJobStore) LoadUserUsage(ctx context.Context, userID string) (*UserUsage, error) {
+	usage, err := scanUserUsage(p.db.QueryRowContext(ctx,
+		`SELECT `+userUsageColumns+` FROM user_usage WHERE user_id = $1`, userID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return usage, err
+}
+
+func (p *PostgresJobStore) ListUserUsage(ctx context.Context) ([]*UserUsage, error) {
+	rows, err := p.db.QueryContext(ctx, `SELECT `+userUsageColumns+` FROM user_usage ORDER BY user_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*UserUsage
+	for rows.Next() {
+		usage, err := scanUserUsage(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, usage)
+	}
+	return users, rows.Err()
+}
+
+func (p *PostgresJobStore) Close() error {
+	return p.db.Close()
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Job events, metric samples and usage records are written to the job store
+// from a background goroutine rather than while s.mu is held, so a slow
+// store does not stall scheduling. Writes are made in the order they were
+// queued; the drain flushes them before the orchestrator exits.
+
+// storeWriteBacklog bounds the writes waiting while the store is down.
+// Further writes are dropped until it catches up.
+const storeWriteBacklog = 10000
+
+// storeWrite is one queued job store write.
+type storeWrite struct {
+	what  string // What is written, for logging
+	write func(ctx context.Context) error
+}
+
+// StoreWriter makes job store writes in the background.
+type StoreWriter struct {
+	ready chan struct{} // Signalled when writes are queued
+
+	mu      sync.Mutex
+	pending []storeWrite
+	writing bool // A batch taken from pending is being written
+	dropped int  // Writes dropped since the backlog was last full
+}
+
+func newStoreWriter() *StoreWriter {
+	return &StoreWriter{ready: make(chan struct{}, 1)}
+}
+
+// queue adds a write without blocking.
+func (w *StoreWriter) queue(what string, write func(ctx context.Context) error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.pending) >= storeWriteBacklog {
+		if w.dropped == 0 {
+			log.Printf("Warning: Job store write backlog full, dropping writes")
+		}
+		w.dropped++
+		return
+	}
+	w.pending = append(w.pending, storeWrite{what: what, write: write})
+	select {
+	case w.ready <- struct{}{}:
+	default:
+	}
+}
+
+// run makes queued writes until ctx is done.
+func (w *StoreWriter) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.ready:
+			w.writePending(ctx)
+		}
+	}
+}
+
+// writePending makes the writes queued so far.
+func (w *StoreWriter) writePending(ctx context.Context) {
+	w.mu.Lock()
+	batch := w.pending
+	w.pending = nil
+	w.writing = true
+	if w.dropped > 0 {
+		log.Printf("Warning: Dropped %d job store writes", w.dropped)
+		w.dropped = 0
+	}
+	w.mu.Unlock()
+
+	for _, write := range batch {
+		if err := write.write(ctx); err != nil {
+			log.Printf("Warning: Failed to %s: %v", write.what, err)
+		}
+	}
+
+	w.mu.Lock()
+	w.writing = false
+	w.mu.Unlock()
+}
+
+// flush returns once every write queued so far is made or the context
+// expires.
+func (w *StoreWriter) flush(ctx context.Context) {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		w.mu.Lock()
+		remaining := len(w.pending)
+		idle := remaining == 0 && !w.writing
+		w.mu.Unlock()
+		if idle {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Printf("Warning: Drain timed out with %d job store writes pending", remaining)
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"log"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	orchestratorpb "github.com/tensorfleet/orchestrator/proto/orchestrator"
+)
+
+// errNoTasks is returned when no task became available for a worker in time.
+var errNoTasks = errors.New("no tasks available")
+
+// taskStreamPollInterval bounds how long a task stream waits for work before
+// re-checking the worker, so a stream never blocks on a stale decision.
+var taskStreamPollInterval = getEnvDuration("TASK_STREAM_POLL_INTERVAL", 30*time.Second)
+
+// StreamTasks pushes tasks to a worker over one long-lived stream. Each
+// message from the worker asks for one task; the orchestrator sends it as
+// soon as one is available, so idle workers pick up work without polling.
+func (s *OrchestratorServer) StreamTasks(stream orchestratorpb.OrchestratorService_StreamTasksServer) error {
+	ctx := stream.Context()
+
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		for {
+			task, err := s.assignNextTask(ctx, req.WorkerId, time.Now().Add(taskStreamPollInterval))
+			if errors.Is(err, errNoTasks) {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				if s.isStandby() {
+					// Reconnecting workers reach the new leader
+					return status.Error(codes.Unavailable, "orchestrator stepped down as leader")
+				}
+				// An open stream is proof of life, like any other RPC
+				s.mu.Lock()
+				s.touchWorker(req.WorkerId)
+				s.mu.Unlock()
+				continue
+			}
+			if errors.Is(err, errDraining) {
+				// Reconnecting workers reach the next orchestrator
+				return drainingError()
+			}
+			if err != nil {
+				return err
+			}
+
+			if err := stream.Send(task); err != nil {
+				log.Printf("Failed to push task %s to worker %s: %v", task.TaskId, req.WorkerId, err)
+				s.returnUndelivered(task.JobId, task.TaskId, req.WorkerId)
+				return err
+			}
+			break
+		}
+	}
+}
+
+// returnUndelivered requeues a task that could not be pushed to its worker,
+// so neither the task nor the worker's in-flight slot waits for the lease to
+// expire.
+func (s *OrchestratorServer) returnUndelivered(jobID, taskID, workerID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[jobID]
+	if !ok {
+		return
+	}
+	task := job.findTask(taskID)
+	if task == nil || task.Status != TaskStatusAssigned || task.WorkerID != workerID {
+		return
+	}
+	// The worker never saw it; the attempt does not count
+	task.Attempts--
+	s.retryTask(job, task, "could not be delivered to worker "+workerID)
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	orchestratorpb "github.com/tensorfleet/orchestrator/proto/orchestrator"
+)
+
+// summaryWindow is how far back GetClusterSummary measures throughput.
+// Finished jobs leave memory after finishedJobTTL, so a shorter TTL
+// shortens the window.
+const summaryWindow = time.Hour
+
+// finishedAt is when the job reached its terminal status, or the zero time
+// if it has not.
+func (j *Job) finishedAt() time.Time {
+	if !j.Status.IsTerminal() || len(j.Transitions) == 0 {
+		return time.Time{}
+	}
+	return j.Transitions[len(j.Transitions)-1].At
+}
+
+// GetClusterSummary counts jobs, tasks and workers and measures recent
+// throughput, so dashboards need not page through every job to show them.
+func (s *OrchestratorServer) GetClusterSummary(ctx context.Context, req *orchestratorpb.ClusterSummaryRequest) (*orchestratorpb.ClusterSummaryResponse, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	window := summaryWindow
+	if finishedJobTTL < window {
+		window = finishedJobTTL
+	}
+	since := now.Add(-window)
+
+	resp := &orchestratorpb.ClusterSummaryResponse{
+		JobsByStatus:    make(map[string]int32),
+		WorkersByStatus: make(map[string]int32),
+		WindowSeconds:   int64(window / time.Second),
+		GeneratedAtMs:   now.UnixMilli(),
+	}
+
+	var totalDuration time.Duration
+	var timed int
+	for _, job := range s.jobs {
+		resp.JobsByStatus[string(job.Status)]++
+
+		for _, task := range job.Tasks {
+			switch {
+			case task.Status == TaskStatusPending && !job.Status.IsTerminal():
+				resp.QueuedTasks++
+			case task.Status == TaskStatusAssigned:
+				resp.RunningTasks++
+			case task.Status == TaskStatusCompleted && task.CompletedAt != nil && task.CompletedAt.After(since):
+				resp.TasksCompleted++
+			}
+		}
+
+		finished := job.finishedAt()
+		if finished.IsZero() || finished.Before(since) {
+			continue
+		}
+		switch job.Status {
+		case JobStatusCompleted, JobStatusCompletedEarly:
+			resp.JobsCompleted++
+			started := job.StartedAt
+			if started.IsZero() {
+				started = job.CreatedAt
+			}
+			totalDuration += finished.Sub(started)
+			timed++
+		case JobStatusFailed:
+			resp.JobsFailed++
+		}
+	}
+	if timed > 0 {
+		resp.AvgJobDurationMs = (totalDuration / time.Duration(timed)).Milliseconds()
+	}
+	resp.TasksPerMinute = float64(resp.TasksCompleted) / window.Minutes()
+
+	for _, worker := range s.workers {
+		resp.WorkersByStatus[worker.Status]++
+		if worker.quarantine.Quarantined {
+			resp.QuarantinedWorkers++
+		}
+		if worker.Draining {
+			resp.DrainingWorkers++
+		}
+	}
+	return resp, nil
+}
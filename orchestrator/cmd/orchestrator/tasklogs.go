@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"time"
+	"unicode/utf8"
+
+	"github.com/go-redis/redis/v8"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	orchestratorpb "github.com/tensorfleet/orchestrator/proto/orchestrator"
+)
+
+// Workers ship their tasks' training logs to the orchestrator, which keeps
+// them in a Redis stream per job, logs:<job_id>, for the gateway to serve.
+// Each stream is capped at roughly taskLogMaxLen entries and expires
+// taskLogTTL after the job last logged.
+var (
+	taskLogShipping = os.Getenv("TASK_LOGS") != "false"
+	taskLogMaxLen   = int64(getEnvInt("TASK_LOG_MAX_LEN", 50000))
+	taskLogTTL      = getEnvDuration("TASK_LOG_TTL", 7*24*time.Hour)
+)
+
+const taskLogStreamPrefix = "logs:"
+
+// maxTaskLogMessage is the longest log message kept; longer ones are cut.
+const maxTaskLogMessage = 8 << 10
+
+// TaskLogStore appends shipped task logs to Redis. A nil store drops them.
+type TaskLogStore struct {
+	client *redis.Client
+}
+
+func newTaskLogStore() *TaskLogStore {
+	if !taskLogShipping {
+		return nil
+	}
+
+	redisAddr := os.Getenv("REDIS_ADDR")
+	if redisAddr == "" {
+		redisAddr = "redis:6379"
+	}
+	return &TaskLogStore{client: redis.NewClient(&redis.Options{Addr: redisAddr})}
+}
+
+// append stores entries shipped by a worker, in order.
+func (l *TaskLogStore) append(ctx context.Context, workerID string, entries []*orchestratorpb.TaskLogEntry) error {
+	pipe := l.client.Pipeline()
+	streams := make(map[string]bool)
+	for _, e := range entries {
+		message := e.Message
+		if len(message) > maxTaskLogMessage {
+			// Cut between characters, leaving the message valid UTF-8
+			cut := maxTaskLogMessage
+			for cut > 0 && !utf8.RuneStart(message[cut]) {
+				cut--
+			}
+			message = message[:cut]
+		}
+		fields, err := json.Marshal(e.Fields)
+		if err != nil {
+			return err
+		}
+		stream := taskLogStreamPrefix + e.JobId
+		pipe.XAdd(ctx, &redis.XAddArgs{
+			Stream: stream,
+			MaxLen: taskLogMaxLen,
+			Approx: true,
+			Values: map[string]interface{}{
+				"task_id":   e.TaskId,
+				"worker_id": workerID,
+				"level":     e.Level,
+				"message":   message,
+				"timestamp": e.TimestampMs,
+				"fields":    fields,
+			},
+		})
+		streams[stream] = true
+	}
+	for stream := range streams {
+		pipe.Expire(ctx, stream, taskLogTTL)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (s *OrchestratorServer) ShipTaskLogs(ctx context.Context, req *orchestratorpb.ShipTaskLogsRequest) (*orchestratorpb.ShipTaskLogsResponse, error) {
+	if s.taskLogs == nil || len(req.Entries) == 0 {
+		return &orchestratorpb.ShipTaskLogsResponse{}, nil
+	}
+
+	// Lines of jobs the orchestrator does not know would never be read
+	s.mu.RLock()
+	entries := make([]*orchestratorpb.TaskLogEntry, 0, len(req.Entries))
+	for _, e := range req.Entries {
+		if _, ok := s.jobs[e.JobId]; ok {
+			entries = append(entries, e)
+		}
+	}
+	s.mu.RUnlock()
+	if len(entries) == 0 {
+		return &orchestratorpb.ShipTaskLogsResponse{}, nil
+	}
+
+	if err := s.taskLogs.append(ctx, req.WorkerId, entries); err != nil {
+		log.Printf("Warning: Failed to store logs from worker %s: %v", req.WorkerId, err)
+		return nil, status.Errorf(codes.Unavailable, "log store unavailable: %v", err)
+	}
+	return &orchestratorpb.ShipTaskLogsResponse{Accepted: int32(len(entries))}, nil
+}
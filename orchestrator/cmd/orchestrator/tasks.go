@@ -0,0 +1,285 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	orchestratorpb "github.com/tensorfleet/orchestrator/proto/orchestrator"
+)
+
+const (
+	TaskStatusPending   = "PENDING"
+	TaskStatusAssigned  = "ASSIGNED"
+	TaskStatusCompleted = "COMPLETED"
+	TaskStatusFailed    = "FAILED"
+	TaskStatusCancelled = "CANCELLED"
+
+	WorkerStatusSuspect = "SUSPECT"
+)
+
+// Lease tuning. An assigned task must be reported back within its job's task
+// timeout (see taskTimeout) or it is requeued; a task is given up on after
+// maxTaskRetries reassignments.
+var (
+	taskLeaseDuration = getEnvDuration("TASK_LEASE_DURATION", 2*time.Minute)
+	maxTaskRetries    = getEnvInt("MAX_RETRIES", 3)
+)
+
+// findTask looks up a task of the job by ID.
+func (j *Job) findTask(taskID string) *Task {
+	for _, task := range j.Tasks {
+		if task.TaskID == taskID {
+			return task
+		}
+	}
+	return nil
+}
+
+// leaseTask hands a task to a worker until the job's task timeout. Caller
+// must hold s.mu.
+func (s *OrchestratorServer) leaseTask(job *Job, task *Task, workerID string) {
+	now := time.Now()
+	task.Prefetched = false
+	if worker, ok := s.workers[workerID]; ok && worker.MaxConcurrentTasks > 0 {
+		task.Prefetched = worker.inFlight() >= worker.MaxConcurrentTasks
+	}
+	job.reserve(workerID, now)
+	task.WorkerID = workerID
+	task.Status = TaskStatusAssigned
+	task.Attempts++
+	task.AssignedAt = &now
+	task.LeaseExpiresAt = now.Add(job.timeoutFor(task))
+	task.Straggling = false
+	task.Progress = nil
+	s.recordEvent(job.JobID, JobEvent{
+		Type:     EventTaskAssigned,
+		Message:  fmt.Sprintf("Epoch %d task assigned (attempt %d)", task.Epoch, task.Attempts),
+		TaskID:   task.TaskID,
+		WorkerID: workerID,
+	})
+}
+
+// retryTask puts a task back on the queue if it has retries left under the
+// job's failure policy, and otherwise marks it FAILED. It returns true if the
+// task was requeued. Caller must hold s.mu.
+func (s *OrchestratorServer) retryTask(job *Job, task *Task, reason string) bool {
+	if task.WorkerID != "" {
+		job.release(task.WorkerID)
+	}
+	s.chargeTaskAttempt(job, task, false)
+	task.WorkerID = ""
+	task.AssignedAt = nil
+	task.LeaseExpiresAt = time.Time{}
+	task.LastError = reason
+
+	if task.Attempts > job.MaxTaskRetries {
+		task.Status = TaskStatusFailed
+		job.FailedTasks++
+		log.Printf("Task %s of job %s failed permanently after %d attempts: %s",
+			task.TaskID, job.JobID, task.Attempts, reason)
+		s.recordEvent(job.JobID, JobEvent{
+			Type:    EventTaskFailed,
+			Message: fmt.Sprintf("Failed permanently after %d attempts: %s", task.Attempts, reason),
+			TaskID:  task.TaskID,
+		})
+
+		s.applyFailurePolicy(job)
+		s.onTaskSettled(context.Background(), job, task)
+		s.advanceEpochBarrier(job)
+		return false
+	}
+
+	task.Status = TaskStatusPending
+	log.Printf("Requeueing task %s of job %s (attempt %d/%d): %s",
+		task.TaskID, job.JobID, task.Attempts, job.MaxTaskRetries+1, reason)
+	s.recordEvent(job.JobID, JobEvent{
+		Type:    EventTaskRetried,
+		Message: fmt.Sprintf("Requeued after attempt %d/%d: %s", task.Attempts, job.MaxTaskRetries+1, reason),
+		TaskID:  task.TaskID,
+	})
+
+	s.enqueueTask(job, task)
+	return true
+}
+
+// enqueueTask makes a task available to AssignTask at its job's priority. A
+// task still queued, e.g. one of a job readmitted after preemption, keeps
+// its place and the time it was queued. Caller must hold s.mu, which guards
+// QueuedAt; it is set before the task is visible to the scheduler.
+func (s *OrchestratorServer) enqueueTask(job *Job, task *Task) {
+	if s.taskQueue.Queued(task) {
+		return
+	}
+	task.QueuedAt = time.Now()
+	s.taskQueue.Push(task, job.namespace(), job.Priority, job.CreatedAt)
+}
+
+// monitorTaskLeases reclaims tasks whose lease expired before the worker
+// reported back, e.g. because the worker crashed mid-task or the task hung.
+func (s *OrchestratorServer) monitorTaskLeases(ctx context.Context) {
+	ticker := time.NewTicker(leaseCheckInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.reclaimExpiredLeases(ctx)
+		}
+	}
+}
+
+func (s *OrchestratorServer) reclaimExpiredLeases(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for _, job := range s.jobs {
+		if !job.isActive() {
+			continue
+		}
+
+		changed := false
+		for _, task := range job.Tasks {
+			if task.Status != TaskStatusAssigned || now.Before(task.LeaseExpiresAt) {
+				continue
+			}
+
+			workerID := task.WorkerID
+			// A task held prefetched has not started, so its lease is
+			// renewed while the worker is alive, and it is requeued without
+			// counting against the task or the worker once it is not
+			if task.Prefetched {
+				worker, ok := s.workers[workerID]
+				if ok && now.Sub(worker.LastHeartbeat) < workerOfflineAfter {
+					task.LeaseExpiresAt = now.Add(job.timeoutFor(task))
+					continue
+				}
+				s.unassignTask(job, task)
+				task.LastError = "Held prefetched by unresponsive worker " + workerID
+				s.enqueueTask(job, task)
+				log.Printf("Requeueing task %s of job %s held prefetched by unresponsive worker %s", task.TaskID, job.JobID, workerID)
+				s.recordEvent(job.JobID, JobEvent{
+					Type:     EventTaskReleased,
+					Message:  fmt.Sprintf("Epoch %d task requeued: held prefetched by an unresponsive worker", task.Epoch),
+					TaskID:   task.TaskID,
+					WorkerID: workerID,
+				})
+				changed = true
+				continue
+			}
+			timeout := job.timeoutFor(task)
+			if task.AssignedAt != nil {
+				timeout = task.LeaseExpiresAt.Sub(*task.AssignedAt)
+			}
+			log.Printf("Task %s timed out after %s on worker %s", task.TaskID, timeout, workerID)
+
+			if worker, ok := s.workers[workerID]; ok {
+				worker.Status = WorkerStatusSuspect
+				worker.MissedLeases++
+				if worker.CurrentTaskID == task.TaskID {
+					worker.CurrentTaskID = ""
+				}
+				s.recordWorkerFailure(workerID, "lease expired")
+				// The worker may still be running it; free it for the retry
+				go s.cancelOnWorker(workerID, task.TaskID, "lease expired")
+			}
+			s.recordEvent(job.JobID, JobEvent{
+				Type:     EventWorkerFailed,
+				Message:  fmt.Sprintf("Timed out after %s before the worker reported back", timeout),
+				TaskID:   task.TaskID,
+				WorkerID: workerID,
+			})
+
+			s.retryTask(job, task, fmt.Sprintf("timed out after %s on worker %s", timeout, workerID))
+			changed = true
+		}
+		if s.flagStragglers(job, now) {
+			changed = true
+		}
+
+		if changed {
+			if err := s.saveJob(ctx, job); err != nil {
+				log.Printf("Warning: Failed to save job: %v", err)
+			}
+		}
+	}
+}
+
+// ReleaseTask requeues a task its worker hands back unfinished, e.g. while
+// shutting down. The attempt does not count against the task's retries.
+func (s *OrchestratorServer) ReleaseTask(ctx context.Context, req *orchestratorpb.ReleaseTaskRequest) (*orchestratorpb.ReleaseTaskResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.jobs[req.JobId]
+	if !ok {
+		return &orchestratorpb.ReleaseTaskResponse{Released: false, Message: "Unknown task"}, nil
+	}
+	task := job.findTask(req.TaskId)
+	if task == nil {
+		return &orchestratorpb.ReleaseTaskResponse{Released: false, Message: "Unknown task"}, nil
+	}
+	// Only the current holder may hand a task back; otherwise it was
+	// already requeued, reassigned or settled
+	if task.Status != TaskStatusAssigned || task.WorkerID != req.WorkerId {
+		return &orchestratorpb.ReleaseTaskResponse{
+			Released: false,
+			Message:  "Task is not assigned to this worker",
+		}, nil
+	}
+
+	reason := req.Reason
+	if reason == "" {
+		reason = "released by worker"
+	}
+	s.releaseTask(job, task, reason)
+	log.Printf("Worker %s released task %s of job %s: %s", req.WorkerId, task.TaskID, job.JobID, reason)
+	if err := s.saveJob(ctx, job); err != nil {
+		log.Printf("Warning: Failed to save job: %v", err)
+	}
+
+	return &orchestratorpb.ReleaseTaskResponse{Released: true, Message: "Task requeued"}, nil
+}
+
+// releaseTask requeues a task its worker handed back, without counting the
+// attempt. Caller must hold s.mu.
+func (s *OrchestratorServer) releaseTask(job *Job, task *Task, reason string) {
+	workerID := task.WorkerID
+	s.unassignTask(job, task)
+	task.LastError = "Released by worker: " + reason
+	s.enqueueTask(job, task)
+	s.recordEvent(job.JobID, JobEvent{
+		Type:     EventTaskReleased,
+		Message:  fmt.Sprintf("Epoch %d task released: %s", task.Epoch, reason),
+		TaskID:   task.TaskID,
+		WorkerID: workerID,
+	})
+}
+
+// requeueWorkerTasks calls requeue for each task assigned to the worker and
+// saves the jobs whose tasks it was called for. It returns how many tasks
+// there were. Caller must hold s.mu.
+func (s *OrchestratorServer) requeueWorkerTasks(ctx context.Context, workerID string, requeue func(job *Job, task *Task)) int {
+	requeued := 0
+	for _, job := range s.jobs {
+		touched := false
+		for _, task := range job.Tasks {
+			if task.Status != TaskStatusAssigned || task.WorkerID != workerID {
+				continue
+			}
+			requeue(job, task)
+			touched = true
+			requeued++
+		}
+		if touched {
+			if err := s.saveJob(ctx, job); err != nil {
+				log.Printf("Warning: Failed to save job: %v", err)
+			}
+		}
+	}
+	return requeued
+}
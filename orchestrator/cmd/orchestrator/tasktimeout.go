@@ -0,0 +1,68 @@
+package main
+
+import (
+	"time"
+)
+
+// Task execution timeouts. An assignment expires after the job's configured
+// task timeout; jobs without one get taskTimeoutFactor times their average
+// task duration once taskTimeoutMinSamples tasks have completed, but never
+// less than taskTimeoutMin. Until then the plain taskLeaseDuration applies.
+var (
+	taskTimeoutFactor = getEnvInt("TASK_TIMEOUT_FACTOR", 3)
+	taskTimeoutMin    = getEnvDuration("TASK_TIMEOUT_MIN", 30*time.Second)
+)
+
+const taskTimeoutMinSamples = 5
+
+// observeTaskDuration folds a completed task's run time into the job's
+// average and duration window.
+func (j *Job) observeTaskDuration(d time.Duration) {
+	j.TaskDurations.observe(d)
+	j.TaskDurationSamples++
+	j.AvgTaskDuration += (d - j.AvgTaskDuration) / time.Duration(j.TaskDurationSamples)
+}
+
+// timeoutFor is how long a worker may run the task before it is reclaimed.
+// Stages, which run over the whole dataset, are not held to the duration
+// of the job's training tasks.
+func (j *Job) timeoutFor(task *Task) time.Duration {
+	if task.isStage() {
+		if j.TaskTimeout > 0 {
+			return j.TaskTimeout
+		}
+		return taskLeaseDuration
+	}
+	return j.taskTimeout()
+}
+
+// taskTimeout is how long a worker may run one of the job's training tasks
+// before it is reclaimed.
+func (j *Job) taskTimeout() time.Duration {
+	if j.TaskTimeout > 0 {
+		return j.TaskTimeout
+	}
+	if j.TaskDurationSamples < taskTimeoutMinSamples || taskTimeoutFactor <= 0 {
+		return taskLeaseDuration
+	}
+
+	timeout := j.AvgTaskDuration * time.Duration(taskTimeoutFactor)
+	if timeout < taskTimeoutMin {
+		timeout = taskTimeoutMin
+	}
+	return timeout
+}
+
+// leaseCheckInterval is how often expired assignments are looked for; short
+// enough to honour the smallest timeout a task can get.
+func leaseCheckInterval() time.Duration {
+	shortest := taskLeaseDuration
+	if taskTimeoutMin < shortest {
+		shortest = taskTimeoutMin
+	}
+	interval := shortest / 4
+	if interval < time.Second {
+		interval = time.Second
+	}
+	return interval
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	orchestratorpb "github.com/tensorfleet/orchestrator/proto/orchestrator"
+)
+
+// maxUploadedWeightsBytes bounds the weights fetched for one task report.
+var maxUploadedWeightsBytes = int64(getEnvInt("MAX_UPLOADED_WEIGHTS_MB", 1024)) << 20
+
+// fetchUploadedWeights reads weights a worker uploaded to storage rather
+// than sending them with its report, from the object store when one is
+// configured and through the storage service otherwise. The content must
+// match the digest the worker reported.
+func (s *OrchestratorServer) fetchUploadedWeights(ctx context.Context, ref *orchestratorpb.ObjectRef) ([]byte, error) {
+	if ref.SizeBytes > maxUploadedWeightsBytes {
+		return nil, fmt.Errorf("weights of %d bytes exceed the %d byte limit", ref.SizeBytes, maxUploadedWeightsBytes)
+	}
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	var data []byte
+	var err error
+	if s.objects != nil {
+		data, err = s.objects.Get(ctx, ref.Bucket, ref.Key)
+	} else {
+		data, err = downloadFromStorage(ctx, ref.Bucket, ref.Key)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(data)
+	if ref.Sha256 != "" && hex.EncodeToString(sum[:]) != ref.Sha256 {
+		return nil, fmt.Errorf("%s/%s does not match its digest", ref.Bucket, ref.Key)
+	}
+	return data, nil
+}
+
+// downloadFromStorage reads an object through the storage service.
+func downloadFromStorage(ctx context.Context, bucket, key string) ([]byte, error) {
+	endpoint, err := url.JoinPath(storageServiceURL(), "api/v1/download", bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("storage service returned status %d for %s/%s", resp.StatusCode, bucket, key)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxUploadedWeightsBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxUploadedWeightsBytes {
+		return nil, fmt.Errorf("%s/%s exceeds the %d byte limit", bucket, key, maxUploadedWeightsBytes)
+	}
+	return data, nil
+}
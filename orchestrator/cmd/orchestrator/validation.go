@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	orchestratorpb "github.com/tensorfleet/orchestrator/proto/orchestrator"
+)
+
+// datasetPath is the dataset a worker runs the task on: the held-out one
+// for validation, the job's own otherwise.
+func (t *Task) datasetPath(job *Job) string {
+	if t.taskType() == TaskTypeValidate {
+		return job.ValidationDatasetPath
+	}
+	return job.DatasetPath
+}
+
+// startingWeights are the weights a worker is sent with the task. A
+// validation task scores the weights merged when its epoch settled; it
+// falls back to the latest ones if it was loaded without them.
+func (t *Task) startingWeights(job *Job) []byte {
+	if t.taskType() == TaskTypeValidate && t.weights != nil {
+		return t.weights
+	}
+	return job.ModelWeights
+}
+
+// validationSettled reports whether the epoch's validation completed or
+// failed for good.
+func (j *Job) validationSettled(epoch int32) bool {
+	for _, task := range j.Tasks {
+		if task.taskType() == TaskTypeValidate && task.Epoch == epoch {
+			return task.Status == TaskStatusCompleted || task.Status == TaskStatusFailed
+		}
+	}
+	return false
+}
+
+// failedValidations counts the job's validation tasks that failed for good.
+// They leave their epoch without validation metrics but do not count
+// against the job's failure tolerance.
+func (j *Job) failedValidations() int {
+	n := 0
+	for _, task := range j.Tasks {
+		if task.taskType() == TaskTypeValidate && task.Status == TaskStatusFailed {
+			n++
+		}
+	}
+	return n
+}
+
+// validationMetrics returns the metrics of the epoch's validation, or nil if
+// it has not completed.
+func (j *Job) validationMetrics(epoch int32) *EpochMetrics {
+	for _, m := range j.ValidationMetrics {
+		if m.Epoch == epoch {
+			return m
+		}
+	}
+	return nil
+}
+
+// onValidationSettled records the outcome of an epoch's validation, kept
+// apart from the training metrics, and lets early stopping act on it.
+// Caller must hold s.mu.
+func (s *OrchestratorServer) onValidationSettled(ctx context.Context, job *Job, task *Task) {
+	task.weights = nil
+	if task.Status != TaskStatusCompleted {
+		log.Printf("Validation of job %s for epoch %d failed: %s", job.JobID, task.Epoch, task.LastError)
+		s.maybeStopEarly(job)
+		return
+	}
+
+	m := job.validationMetrics(task.Epoch)
+	if m == nil {
+		m = &EpochMetrics{Epoch: task.Epoch}
+		job.ValidationMetrics = append(job.ValidationMetrics, m)
+	}
+	m.Loss = task.Loss
+	m.Accuracy = task.Accuracy
+	m.Batches = task.BatchEnd - task.BatchStart
+	m.CompletedTasks = 1
+
+	s.appendMetricSample(job.JobID, MetricSample{
+		Kind:     MetricSampleValidation,
+		Epoch:    task.Epoch,
+		TaskID:   task.TaskID,
+		Loss:     task.Loss,
+		Accuracy: task.Accuracy,
+	})
+	s.recordEvent(job.JobID, JobEvent{
+		Type:    EventEpochValidated,
+		Message: fmt.Sprintf("Epoch %d validated: val_loss=%.4f, val_accuracy=%.4f", task.Epoch, task.Loss, task.Accuracy),
+		TaskID:  task.TaskID,
+	})
+	s.maybeStopEarly(job)
+}
+
+// dropValidationMetrics forgets the validation of the epoch and those after
+// it, e.g. when they are rerun from a checkpoint.
+func (j *Job) dropValidationMetrics(from int32) {
+	kept := j.ValidationMetrics[:0]
+	for _, m := range j.ValidationMetrics {
+		if m.Epoch < from {
+			kept = append(kept, m)
+		}
+	}
+	j.ValidationMetrics = kept
+}
+
+// validationMetricsProto reports the validation metrics in epoch order.
+func (j *Job) validationMetricsProto() []*orchestratorpb.EpochMetrics {
+	out := make([]*orchestratorpb.EpochMetrics, 0, len(j.ValidationMetrics))
+	for epoch := int32(0); epoch < j.Epochs; epoch++ {
+		m := j.validationMetrics(epoch)
+		if m == nil {
+			continue
+		}
+		out = append(out, &orchestratorpb.EpochMetrics{
+			Epoch:          m.Epoch,
+			Loss:           m.Loss,
+			Accuracy:       m.Accuracy,
+			CompletedTasks: int32(m.CompletedTasks),
+			Complete:       true,
+		})
+	}
+	return out
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"time"
+
+	orchestratorpb "github.com/tensorfleet/orchestrator/proto/orchestrator"
+)
+
+// jobWatchMinInterval spaces out the updates sent to a watcher so bursts of
+// changes, such as many tasks completing together, collapse into one update.
+var jobWatchMinInterval = getEnvDuration("JOB_WATCH_MIN_INTERVAL", 500*time.Millisecond)
+
+// watchJob subscribes to changes of a job. The returned channel receives a
+// value whenever the job may have changed; pending notifications coalesce.
+// Call the returned function to unsubscribe.
+func (s *OrchestratorServer) watchJob(jobID string) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	s.watchMu.Lock()
+	if s.watchers[jobID] == nil {
+		s.watchers[jobID] = make(map[chan struct{}]struct{})
+	}
+	s.watchers[jobID][ch] = struct{}{}
+	s.watchMu.Unlock()
+
+	return ch, func() {
+		s.watchMu.Lock()
+		delete(s.watchers[jobID], ch)
+		if len(s.watchers[jobID]) == 0 {
+			delete(s.watchers, jobID)
+		}
+		s.watchMu.Unlock()
+	}
+}
+
+// notifyJobChanged wakes every watcher of the job without blocking.
+func (s *OrchestratorServer) notifyJobChanged(jobID string) {
+	s.watchMu.Lock()
+	defer s.watchMu.Unlock()
+
+	for ch := range s.watchers[jobID] {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// WatchJobStatus streams the job's status, first immediately and then after
+// each change, until the job reaches a terminal status or the client goes
+// away.
+func (s *OrchestratorServer) WatchJobStatus(req *orchestratorpb.WatchJobStatusRequest, stream orchestratorpb.OrchestratorService_WatchJobStatusServer) error {
+	ctx := stream.Context()
+	changes, unsubscribe := s.watchJob(req.JobId)
+	defer unsubscribe()
+
+	for {
+		status, err := s.GetJobStatus(ctx, &orchestratorpb.GetJobStatusRequest{JobId: req.JobId})
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(status); err != nil {
+			return err
+		}
+		if JobStatus(status.Status).IsTerminal() {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changes:
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(jobWatchMinInterval):
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	workerpb "github.com/tensorfleet/orchestrator/proto/worker"
+)
+
+// workerConn is a connection to a worker's gRPC server.
+type workerConn struct {
+	address string // Address it was dialled at
+	conn    *grpc.ClientConn
+}
+
+// workerClient returns a WorkerService client for a registered worker,
+// reusing its connection across calls. The connection is redialled when the
+// worker registers a new address and closed when the worker is forgotten.
+func (s *OrchestratorServer) workerClient(workerID string) (workerpb.WorkerServiceClient, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	worker, ok := s.workers[workerID]
+	if !ok {
+		return nil, fmt.Errorf("worker is no longer registered")
+	}
+	if worker.Address == "" {
+		return nil, fmt.Errorf("worker has no registered address")
+	}
+
+	s.connMu.Lock()
+	defer s.connMu.Unlock()
+
+	if c, ok := s.workerConns[workerID]; ok {
+		if c.address == worker.Address {
+			return workerpb.NewWorkerServiceClient(c.conn), nil
+		}
+		c.conn.Close()
+		delete(s.workerConns, workerID)
+	}
+
+	conn, err := grpc.Dial(worker.Address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, err
+	}
+	s.workerConns[workerID] = workerConn{address: worker.Address, conn: conn}
+	return workerpb.NewWorkerServiceClient(conn), nil
+}
+
+// closeWorkerConn closes the connection to a worker being removed from
+// s.workers. Caller must hold s.mu.
+func (s *OrchestratorServer) closeWorkerConn(workerID string) {
+	s.connMu.Lock()
+	defer s.connMu.Unlock()
+
+	if c, ok := s.workerConns[workerID]; ok {
+		c.conn.Close()
+		delete(s.workerConns, workerID)
+	}
+}
+
+// closeWorkerConns closes every worker connection, when s.workers is
+// cleared. Caller must hold s.mu.
+func (s *OrchestratorServer) closeWorkerConns() {
+	s.connMu.Lock()
+	defer s.connMu.Unlock()
+
+	for workerID, c := range s.workerConns {
+		c.conn.Close()
+		delete(s.workerConns, workerID)
+	}
+}
+
+// cancelOnWorker asks a worker to stop a running task, telling it why.
+// Failures are only logged: the task is already settled on our side and any
+// late report from the worker will be ignored.
+func (s *OrchestratorServer) cancelOnWorker(workerID, taskID, reason string) {
+	client, err := s.workerClient(workerID)
+	if err != nil {
+		log.Printf("Warning: Cannot cancel task %s on worker %s: %v", taskID, workerID, err)
+		return
+	}
+
+	// The worker answers once the task has stopped, or after a bounded wait
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	resp, err := client.CancelTask(withWorkerAuth(ctx), &workerpb.CancelTaskRequest{TaskId: taskID, Reason: reason})
+	if err != nil {
+		log.Printf("Warning: CancelTask for task %s on worker %s failed: %v", taskID, workerID, err)
+		return
+	}
+	log.Printf("Worker %s acknowledged cancel of task %s: %s", workerID, taskID, resp.Message)
+}
@@ -0,0 +1,428 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	orchestratorpb "github.com/tensorfleet/orchestrator/proto/orchestrator"
+)
+
+const (
+	WorkerStatusIdle    = "IDLE"
+	WorkerStatusBusy    = "BUSY"
+	WorkerStatusOffline = "OFFLINE"
+)
+
+// Heartbeat tuning. Workers are expected to heartbeat every
+// workerHeartbeatInterval; after workerOfflineAfter without contact they are
+// shown as OFFLINE, and after workerEvictAfter they are dropped entirely.
+var (
+	workerHeartbeatInterval = getEnvDuration("WORKER_HEARTBEAT_INTERVAL", 10*time.Second)
+	workerOfflineAfter      = getEnvDuration("WORKER_OFFLINE_AFTER", 30*time.Second)
+	workerEvictAfter        = getEnvDuration("WORKER_EVICT_AFTER", 5*time.Minute)
+)
+
+// defaultWorkerMaxInFlight is how many tasks a worker that advertised no
+// limit at registration may hold at once; 0 means unlimited.
+var defaultWorkerMaxInFlight = getEnvInt("WORKER_MAX_IN_FLIGHT", 1)
+
+// workerMaxPrefetch caps the tasks a worker may hold prefetched beyond the
+// ones it runs; 0 turns prefetching off.
+var workerMaxPrefetch = getEnvInt("WORKER_MAX_PREFETCH", 1)
+
+// workerMaxCPUPercent holds work back from a worker whose latest heartbeat
+// reported more CPU use than this; 0 disables the check.
+var workerMaxCPUPercent = getEnvFloat("WORKER_MAX_CPU_PERCENT", 0)
+
+// capacityRecheckInterval is how often a worker at its in-flight limit
+// checks whether a slot has freed up.
+const capacityRecheckInterval = time.Second
+
+// maxInFlight is how many tasks the worker may hold at once, running or
+// prefetched; 0 means unlimited.
+func (w *WorkerActivity) maxInFlight() int {
+	if w.MaxConcurrentTasks > 0 {
+		return w.MaxConcurrentTasks + w.PrefetchTasks
+	}
+	return defaultWorkerMaxInFlight
+}
+
+// grantedPrefetch is how many of the prefetched tasks a worker asked for it
+// may hold. Only workers that advertise their concurrency prefetch.
+func grantedPrefetch(requested, maxConcurrent int32) int {
+	if requested <= 0 || maxConcurrent <= 0 {
+		return 0
+	}
+	return min(int(requested), workerMaxPrefetch)
+}
+
+// inFlight counts the tasks currently leased to the worker. Caller must hold
+// s.mu, for reading at least.
+func (w *WorkerActivity) inFlight() int {
+	n := 0
+	for _, task := range w.leased {
+		if task.Status == TaskStatusAssigned && task.WorkerID == w.WorkerID {
+			n++
+		}
+	}
+	return n
+}
+
+// atCapacity reports whether the worker holds as many tasks as it may.
+// Caller must hold s.mu.
+func (w *WorkerActivity) atCapacity() bool {
+	limit := w.maxInFlight()
+	return limit > 0 && w.inFlight() >= limit
+}
+
+// WorkerLoad is the host load a worker reports with its heartbeats.
+type WorkerLoad struct {
+	CPUPercent       float64
+	MemoryPercent    float64
+	LoadAverage      float64 // One-minute
+	MemoryUsedBytes  uint64
+	MemoryTotalBytes uint64
+	DiskPercent      float64
+	DiskUsedBytes    uint64
+	DiskTotalBytes   uint64
+	NetSentPerSec    float64
+	NetRecvPerSec    float64
+	GPUs             []GPULoad
+
+	// Set while the worker holds back new tasks because its host is busier
+	// than its own thresholds allow
+	Backpressure       bool
+	BackpressureReason string
+}
+
+// GPULoad is one of a worker's GPUs as of its latest heartbeat.
+type GPULoad struct {
+	Index              int32
+	UUID               string
+	Name               string
+	UtilizationPercent float64
+	MemoryUsedBytes    uint64
+	MemoryTotalBytes   uint64
+	TaskID             string // Task pinned to it; empty while free
+}
+
+func workerLoadFromProto(l *orchestratorpb.WorkerLoad) WorkerLoad {
+	if l == nil {
+		return WorkerLoad{}
+	}
+	gpus := make([]GPULoad, 0, len(l.Gpus))
+	for _, g := range l.Gpus {
+		gpus = append(gpus, GPULoad{
+			Index:              g.Index,
+			UUID:               g.Uuid,
+			Name:               g.Name,
+			UtilizationPercent: g.UtilizationPercent,
+			MemoryUsedBytes:    g.MemoryUsedBytes,
+			MemoryTotalBytes:   g.MemoryTotalBytes,
+			TaskID:             g.TaskId,
+		})
+	}
+	return WorkerLoad{
+		CPUPercent:       l.CpuPercent,
+		MemoryPercent:    l.MemoryPercent,
+		LoadAverage:      l.LoadAverage,
+		MemoryUsedBytes:  l.MemoryUsedBytes,
+		MemoryTotalBytes: l.MemoryTotalBytes,
+		DiskPercent:      l.DiskPercent,
+		DiskUsedBytes:    l.DiskUsedBytes,
+		DiskTotalBytes:   l.DiskTotalBytes,
+		NetSentPerSec:    l.NetSentBytesPerSecond,
+		NetRecvPerSec:    l.NetRecvBytesPerSecond,
+		GPUs:             gpus,
+
+		Backpressure:       l.Backpressure,
+		BackpressureReason: l.BackpressureReason,
+	}
+}
+
+func (l WorkerLoad) toProto() *orchestratorpb.WorkerLoad {
+	gpus := make([]*orchestratorpb.GpuLoad, 0, len(l.GPUs))
+	for _, g := range l.GPUs {
+		gpus = append(gpus, &orchestratorpb.GpuLoad{
+			Index:              g.Index,
+			Uuid:               g.UUID,
+			Name:               g.Name,
+			UtilizationPercent: g.UtilizationPercent,
+			MemoryUsedBytes:    g.MemoryUsedBytes,
+			MemoryTotalBytes:   g.MemoryTotalBytes,
+			TaskId:             g.TaskID,
+		})
+	}
+	return &orchestratorpb.WorkerLoad{
+		CpuPercent:            l.CPUPercent,
+		MemoryPercent:         l.MemoryPercent,
+		LoadAverage:           l.LoadAverage,
+		MemoryUsedBytes:       l.MemoryUsedBytes,
+		MemoryTotalBytes:      l.MemoryTotalBytes,
+		DiskPercent:           l.DiskPercent,
+		DiskUsedBytes:         l.DiskUsedBytes,
+		DiskTotalBytes:        l.DiskTotalBytes,
+		NetSentBytesPerSecond: l.NetSentPerSec,
+		NetRecvBytesPerSecond: l.NetRecvPerSec,
+		Gpus:                  gpus,
+		Backpressure:          l.Backpressure,
+		BackpressureReason:    l.BackpressureReason,
+	}
+}
+
+// overloaded reports whether the worker's latest load report shows its host
+// too busy for more work, by the worker's own thresholds or by
+// WORKER_MAX_CPU_PERCENT. Reports older than workerOfflineAfter are ignored.
+func (w *WorkerActivity) overloaded(now time.Time) bool {
+	if now.Sub(w.LoadReportedAt) > workerOfflineAfter {
+		return false
+	}
+	if w.Load.Backpressure {
+		return true
+	}
+	return workerMaxCPUPercent > 0 && w.Load.CPUPercent > workerMaxCPUPercent
+}
+
+// uptime is how long the worker process has been running, or how long ago
+// it registered if it never said when it started.
+func (w *WorkerActivity) uptime(now time.Time) time.Duration {
+	start := w.StartedAt
+	if start.IsZero() {
+		start = w.RegisteredAt
+	}
+	if start.IsZero() || now.Before(start) {
+		return 0
+	}
+	return now.Sub(start)
+}
+
+// trackLease records a task leased to the worker and forgets the ones that
+// have since left it. Caller must hold s.mu.
+func (w *WorkerActivity) trackLease(task *Task) {
+	for id, t := range w.leased {
+		if t.Status != TaskStatusAssigned || t.WorkerID != w.WorkerID {
+			delete(w.leased, id)
+		}
+	}
+	if w.leased == nil {
+		w.leased = make(map[string]*Task)
+	}
+	w.leased[task.TaskID] = task
+}
+
+// waitForCapacity pauses a worker at its in-flight limit before it looks
+// for work again. Unlike TaskQueue.Wait it does not take wake-ups meant for
+// workers that can run tasks. It returns false once the deadline passes or
+// ctx is cancelled.
+func waitForCapacity(ctx context.Context, deadline time.Time) bool {
+	wait := time.Until(deadline)
+	if wait <= 0 {
+		return false
+	}
+	if wait > capacityRecheckInterval {
+		wait = capacityRecheckInterval
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// touchWorker records contact from a worker, creating its entry on first
+// sight. Any RPC from a worker counts as proof of life, so workers that only
+// poll AssignTask still stay online. Caller must hold s.mu.
+func (s *OrchestratorServer) touchWorker(workerID string) *WorkerActivity {
+	now := time.Now()
+	worker, ok := s.workers[workerID]
+	if !ok {
+		worker = &WorkerActivity{
+			WorkerID:         workerID,
+			Status:           WorkerStatusIdle,
+			LastActivityTime: now,
+			RegisteredAt:     now,
+			quarantine:       s.workerQuarantine(workerID),
+		}
+		s.workers[workerID] = worker
+	}
+	if worker.Status == WorkerStatusOffline {
+		log.Printf("Worker %s is back online", workerID)
+		worker.Status = WorkerStatusIdle
+		if worker.CurrentTaskID != "" {
+			worker.Status = WorkerStatusBusy
+		}
+	}
+	worker.LastHeartbeat = now
+	return worker
+}
+
+func (s *OrchestratorServer) RegisterWorker(ctx context.Context, req *orchestratorpb.RegisterWorkerRequest) (*orchestratorpb.RegisterWorkerResponse, error) {
+	if req.WorkerId == "" {
+		return &orchestratorpb.RegisterWorkerResponse{
+			Success: false,
+			Message: "worker_id is required",
+		}, nil
+	}
+
+	s.mu.Lock()
+	worker := s.touchWorker(req.WorkerId)
+	worker.Hostname = req.Hostname
+	worker.Address = req.Address
+	worker.Executor = req.Executor
+	worker.Capabilities = capabilitiesFromProto(req.Capabilities)
+	worker.MaxConcurrentTasks = int(req.MaxConcurrentTasks)
+	worker.PrefetchTasks = grantedPrefetch(req.PrefetchTasks, req.MaxConcurrentTasks)
+	worker.setCachedDatasets(req.CachedDatasets)
+	if req.StartedAtMs > 0 {
+		worker.StartedAt = time.UnixMilli(req.StartedAtMs)
+	}
+	worker.sessionToken = newSessionToken()
+	token := worker.sessionToken
+	prefetch := worker.PrefetchTasks
+	s.refreshResourceAvailability()
+	s.admitWaitingGang()
+	s.mu.Unlock()
+
+	s.events.publish(ClusterEvent{
+		Type:     EventWorkerRegistered,
+		WorkerID: req.WorkerId,
+		Message:  fmt.Sprintf("Registered at %s with up to %d tasks in flight", req.Address, worker.maxInFlight()),
+	})
+	log.Printf("Registered worker %s (host: %s, address: %s, executor: %s, cpu: %d, memory: %dMB, gpu: %d %s, max tasks: %d, labels: %v)",
+		req.WorkerId, req.Hostname, req.Address, req.Executor, worker.Capabilities.CPUCores,
+		worker.Capabilities.MemoryMB, worker.Capabilities.GPUCount, worker.Capabilities.GPUType,
+		worker.maxInFlight(), worker.Capabilities.Labels)
+
+	return &orchestratorpb.RegisterWorkerResponse{
+		Success:                  true,
+		Message:                  "Worker registered",
+		HeartbeatIntervalSeconds: int32(workerHeartbeatInterval / time.Second),
+		SessionToken:             token,
+		PrefetchTasks:            int32(prefetch),
+	}, nil
+}
+
+func (s *OrchestratorServer) Heartbeat(ctx context.Context, req *orchestratorpb.HeartbeatRequest) (*orchestratorpb.HeartbeatResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.workers[req.WorkerId]; !ok {
+		// Unknown worker - ask it to register so we learn its address
+		return &orchestratorpb.HeartbeatResponse{
+			Acknowledged: true,
+			Registered:   false,
+		}, nil
+	}
+
+	worker := s.touchWorker(req.WorkerId)
+	if req.CachedDatasets != nil {
+		worker.setCachedDatasets(req.CachedDatasets)
+	}
+	if req.StartedAtMs > 0 {
+		worker.StartedAt = time.UnixMilli(req.StartedAtMs)
+	}
+	if req.Load != nil {
+		worker.Load = workerLoadFromProto(req.Load)
+		worker.LoadReportedAt = time.Now()
+	}
+
+	return &orchestratorpb.HeartbeatResponse{
+		Acknowledged: true,
+		Registered:   true,
+	}, nil
+}
+
+// DeregisterWorker forgets a worker that is shutting down, handing the tasks
+// it still holds back to the queue as ReleaseTask would.
+func (s *OrchestratorServer) DeregisterWorker(ctx context.Context, req *orchestratorpb.DeregisterWorkerRequest) (*orchestratorpb.DeregisterWorkerResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.workers[req.WorkerId]; !ok {
+		return &orchestratorpb.DeregisterWorkerResponse{Deregistered: false, Message: "Unknown worker"}, nil
+	}
+	reason := req.Reason
+	if reason == "" {
+		reason = "worker deregistered"
+	}
+
+	released := s.requeueWorkerTasks(ctx, req.WorkerId, func(job *Job, task *Task) {
+		s.releaseTask(job, task, reason)
+	})
+
+	delete(s.workers, req.WorkerId)
+	s.closeWorkerConn(req.WorkerId)
+	s.dropWorkerReservations(req.WorkerId)
+	// Jobs may have lost their only capable worker
+	s.refreshResourceAvailability()
+
+	log.Printf("Deregistered worker %s, %d tasks released: %s", req.WorkerId, released, reason)
+	s.events.publish(ClusterEvent{
+		Type:     EventWorkerDeregistered,
+		WorkerID: req.WorkerId,
+		Message:  fmt.Sprintf("%d tasks released: %s", released, reason),
+	})
+
+	return &orchestratorpb.DeregisterWorkerResponse{
+		Deregistered:  true,
+		Message:       "Worker deregistered",
+		ReleasedTasks: int32(released),
+	}, nil
+}
+
+// monitorWorkers periodically marks silent workers OFFLINE and evicts the
+// ones that have been gone long enough.
+func (s *OrchestratorServer) monitorWorkers(ctx context.Context) {
+	ticker := time.NewTicker(workerHeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.checkWorkerLiveness()
+		}
+	}
+}
+
+func (s *OrchestratorServer) checkWorkerLiveness() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for id, worker := range s.workers {
+		silence := now.Sub(worker.LastHeartbeat)
+		switch {
+		case silence > workerEvictAfter:
+			log.Printf("Evicting worker %s (no heartbeat for %s)", id, silence.Round(time.Second))
+			delete(s.workers, id)
+			s.closeWorkerConn(id)
+			s.dropWorkerReservations(id)
+			s.events.publish(ClusterEvent{Type: EventWorkerEvicted, WorkerID: id})
+		case silence > workerOfflineAfter && worker.Status != WorkerStatusOffline:
+			log.Printf("Worker %s marked OFFLINE (no heartbeat for %s)", id, silence.Round(time.Second))
+			worker.Status = WorkerStatusOffline
+			s.dropWorkerReservations(id)
+			s.events.publish(ClusterEvent{Type: EventWorkerOffline, WorkerID: id})
+		case s.faults.dropWorker():
+			// Its tasks come back when their leases expire, and the worker
+			// registers again on its next call
+			log.Printf("Dropping worker %s (injected fault)", id)
+			delete(s.workers, id)
+			s.closeWorkerConn(id)
+			s.dropWorkerReservations(id)
+			s.events.publish(ClusterEvent{Type: EventWorkerEvicted, WorkerID: id, Message: "injected fault"})
+		}
+	}
+	s.sweepQuarantine(now)
+
+	// Losing workers may leave some jobs without a capable worker
+	s.refreshResourceAvailability()
+}
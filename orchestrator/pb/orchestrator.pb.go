@@ -21,6 +21,146 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Lifecycle of a job: PENDING -> QUEUED -> RUNNING -> {COMPLETED, FAILED,
+// CANCELLED}. RUNNING jobs may move to PENDING_RESOURCES or PAUSED and back;
+// FAILED and CANCELLED jobs may be resumed into QUEUED. Jobs with early
+// stopping end in COMPLETED_EARLY once their metric plateaus. Jobs with
+// dependencies wait in BLOCKED before QUEUED. RUNNING jobs that complete no
+// task for a while are marked STALLED until one completes again.
+type JobState int32
+
+const (
+	JobState_JOB_STATE_UNSPECIFIED       JobState = 0
+	JobState_JOB_STATE_PENDING           JobState = 1
+	JobState_JOB_STATE_QUEUED            JobState = 2
+	JobState_JOB_STATE_RUNNING           JobState = 3
+	JobState_JOB_STATE_PENDING_RESOURCES JobState = 4
+	JobState_JOB_STATE_PAUSED            JobState = 5
+	JobState_JOB_STATE_COMPLETED         JobState = 6
+	JobState_JOB_STATE_FAILED            JobState = 7
+	JobState_JOB_STATE_CANCELLED         JobState = 8
+	JobState_JOB_STATE_SHARDING          JobState = 9
+	JobState_JOB_STATE_COMPLETED_EARLY   JobState = 10
+	JobState_JOB_STATE_BLOCKED           JobState = 11
+	JobState_JOB_STATE_STALLED           JobState = 12
+	// Running on a regional orchestrator; see the job's region.
+	JobState_JOB_STATE_DELEGATED JobState = 13
+)
+
+// Enum value maps for JobState.
+var (
+	JobState_name = map[int32]string{
+		0:  "JOB_STATE_UNSPECIFIED",
+		1:  "JOB_STATE_PENDING",
+		2:  "JOB_STATE_QUEUED",
+		3:  "JOB_STATE_RUNNING",
+		4:  "JOB_STATE_PENDING_RESOURCES",
+		5:  "JOB_STATE_PAUSED",
+		6:  "JOB_STATE_COMPLETED",
+		7:  "JOB_STATE_FAILED",
+		8:  "JOB_STATE_CANCELLED",
+		9:  "JOB_STATE_SHARDING",
+		10: "JOB_STATE_COMPLETED_EARLY",
+		11: "JOB_STATE_BLOCKED",
+		12: "JOB_STATE_STALLED",
+		13: "JOB_STATE_DELEGATED",
+	}
+	JobState_value = map[string]int32{
+		"JOB_STATE_UNSPECIFIED":       0,
+		"JOB_STATE_PENDING":           1,
+		"JOB_STATE_QUEUED":            2,
+		"JOB_STATE_RUNNING":           3,
+		"JOB_STATE_PENDING_RESOURCES": 4,
+		"JOB_STATE_PAUSED":            5,
+		"JOB_STATE_COMPLETED":         6,
+		"JOB_STATE_FAILED":            7,
+		"JOB_STATE_CANCELLED":         8,
+		"JOB_STATE_SHARDING":          9,
+		"JOB_STATE_COMPLETED_EARLY":   10,
+		"JOB_STATE_BLOCKED":           11,
+		"JOB_STATE_STALLED":           12,
+		"JOB_STATE_DELEGATED":         13,
+	}
+)
+
+func (x JobState) Enum() *JobState {
+	p := new(JobState)
+	*p = x
+	return p
+}
+
+func (x JobState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (JobState) Descriptor() protoreflect.EnumDescriptor {
+	return file_orchestrator_proto_enumTypes[0].Descriptor()
+}
+
+func (JobState) Type() protoreflect.EnumType {
+	return &file_orchestrator_proto_enumTypes[0]
+}
+
+func (x JobState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use JobState.Descriptor instead.
+func (JobState) EnumDescriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{0}
+}
+
+type DatasetCacheResult int32
+
+const (
+	// The worker keeps no dataset cache, or the job names no dataset.
+	DatasetCacheResult_DATASET_CACHE_UNSPECIFIED DatasetCacheResult = 0
+	DatasetCacheResult_DATASET_CACHE_HIT         DatasetCacheResult = 1
+	// Downloaded for the task.
+	DatasetCacheResult_DATASET_CACHE_MISS DatasetCacheResult = 2
+)
+
+// Enum value maps for DatasetCacheResult.
+var (
+	DatasetCacheResult_name = map[int32]string{
+		0: "DATASET_CACHE_UNSPECIFIED",
+		1: "DATASET_CACHE_HIT",
+		2: "DATASET_CACHE_MISS",
+	}
+	DatasetCacheResult_value = map[string]int32{
+		"DATASET_CACHE_UNSPECIFIED": 0,
+		"DATASET_CACHE_HIT":         1,
+		"DATASET_CACHE_MISS":        2,
+	}
+)
+
+func (x DatasetCacheResult) Enum() *DatasetCacheResult {
+	p := new(DatasetCacheResult)
+	*p = x
+	return p
+}
+
+func (x DatasetCacheResult) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DatasetCacheResult) Descriptor() protoreflect.EnumDescriptor {
+	return file_orchestrator_proto_enumTypes[1].Descriptor()
+}
+
+func (DatasetCacheResult) Type() protoreflect.EnumType {
+	return &file_orchestrator_proto_enumTypes[1]
+}
+
+func (x DatasetCacheResult) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DatasetCacheResult.Descriptor instead.
+func (DatasetCacheResult) EnumDescriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{1}
+}
+
 type TrainingJobRequest struct {
 	state           protoimpl.MessageState `protogen:"open.v1"`
 	JobId           string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
@@ -30,8 +170,78 @@ type TrainingJobRequest struct {
 	Hyperparameters map[string]string      `protobuf:"bytes,5,rep,name=hyperparameters,proto3" json:"hyperparameters,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	NumWorkers      int32                  `protobuf:"varint,6,opt,name=num_workers,json=numWorkers,proto3" json:"num_workers,omitempty"`
 	Epochs          int32                  `protobuf:"varint,7,opt,name=epochs,proto3" json:"epochs,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+	// Optional wall-clock limit; the job is cancelled once it runs longer.
+	MaxDurationSeconds int64 `protobuf:"varint,8,opt,name=max_duration_seconds,json=maxDurationSeconds,proto3" json:"max_duration_seconds,omitempty"`
+	// Optional limit on time without any task completing.
+	ProgressTimeoutSeconds int64 `protobuf:"varint,9,opt,name=progress_timeout_seconds,json=progressTimeoutSeconds,proto3" json:"progress_timeout_seconds,omitempty"`
+	// Failure policy: retries per task before it counts as failed (server
+	// default when unset), and the share of failed tasks the job tolerates.
+	MaxTaskRetries        *int32  `protobuf:"varint,10,opt,name=max_task_retries,json=maxTaskRetries,proto3,oneof" json:"max_task_retries,omitempty"`
+	MaxFailedTasksPercent float64 `protobuf:"fixed64,11,opt,name=max_failed_tasks_percent,json=maxFailedTasksPercent,proto3" json:"max_failed_tasks_percent,omitempty"`
+	// Scheduling priority; higher values are dispatched first. Defaults to 0.
+	Priority int32 `protobuf:"varint,12,opt,name=priority,proto3" json:"priority,omitempty"`
+	// Only workers meeting these requirements receive the job's tasks.
+	Requirements *ResourceRequirements `protobuf:"bytes,13,opt,name=requirements,proto3" json:"requirements,omitempty"`
+	// Synchronous training: dispatch an epoch's tasks only after every task of
+	// the previous epoch has finished.
+	SyncEpochs bool `protobuf:"varint,14,opt,name=sync_epochs,json=syncEpochs,proto3" json:"sync_epochs,omitempty"`
+	// Rule used to merge returned weights each epoch: "average" (FedAvg,
+	// default), "weighted_average" (by batch count), "trimmed_mean", "sum"
+	// (gradients applied to the current model), or any registered plugin.
+	Aggregation string `protobuf:"bytes,15,opt,name=aggregation,proto3" json:"aggregation,omitempty"`
+	// Record a checkpoint after every N epochs; 0 disables checkpointing.
+	CheckpointEveryEpochs int32 `protobuf:"varint,16,opt,name=checkpoint_every_epochs,json=checkpointEveryEpochs,proto3" json:"checkpoint_every_epochs,omitempty"`
+	// Seconds a worker may spend on one task before it is reclaimed and
+	// requeued; 0 derives the timeout from the job's completed task durations.
+	TaskTimeoutSeconds int64 `protobuf:"varint,17,opt,name=task_timeout_seconds,json=taskTimeoutSeconds,proto3" json:"task_timeout_seconds,omitempty"`
+	// Task layout. Each task trains one batch of samples_per_batch samples
+	// (default 100). With dataset_size set, a missing batches_per_epoch or
+	// samples_per_batch is derived from it; otherwise 10 batches per epoch.
+	BatchesPerEpoch int32 `protobuf:"varint,18,opt,name=batches_per_epoch,json=batchesPerEpoch,proto3" json:"batches_per_epoch,omitempty"`
+	SamplesPerBatch int32 `protobuf:"varint,19,opt,name=samples_per_batch,json=samplesPerBatch,proto3" json:"samples_per_batch,omitempty"`
+	DatasetSize     int64 `protobuf:"varint,20,opt,name=dataset_size,json=datasetSize,proto3" json:"dataset_size,omitempty"`
+	// Stop the job once the monitored epoch metric stops improving.
+	EarlyStopping *EarlyStopping `protobuf:"bytes,21,opt,name=early_stopping,json=earlyStopping,proto3" json:"early_stopping,omitempty"`
+	// Jobs that must complete before this one is queued; the job is BLOCKED
+	// until then. If one fails or is cancelled the job is failed ("fail",
+	// default), cancelled ("cancel"), or run anyway ("ignore").
+	DependsOn           []string `protobuf:"bytes,22,rep,name=depends_on,json=dependsOn,proto3" json:"depends_on,omitempty"`
+	OnDependencyFailure string   `protobuf:"bytes,23,opt,name=on_dependency_failure,json=onDependencyFailure,proto3" json:"on_dependency_failure,omitempty"`
+	// Start the job only once num_workers idle workers can be reserved for it
+	// together; until then it stays QUEUED. The workers serve only this job
+	// until it finishes. Requires num_workers.
+	GangScheduling bool `protobuf:"varint,24,opt,name=gang_scheduling,json=gangScheduling,proto3" json:"gang_scheduling,omitempty"`
+	// Project the job belongs to; "default" when empty. Workers labelled
+	// namespace=<name> only run jobs of that namespace.
+	Namespace string `protobuf:"bytes,25,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// Regional orchestrator to run the job on when this one federates
+	// regions; chosen by dataset locality or capacity when empty. "local"
+	// runs it here.
+	Region string `protobuf:"bytes,26,opt,name=region,proto3" json:"region,omitempty"`
+	// Most of the job's tasks that may run at once, however many workers are
+	// free, e.g. for rate-limited data sources. 0 leaves it uncapped.
+	MaxParallelTasks int32 `protobuf:"varint,27,opt,name=max_parallel_tasks,json=maxParallelTasks,proto3" json:"max_parallel_tasks,omitempty"`
+	// Container image the job's tasks run in on workers using the docker
+	// executor, e.g. "registry.example.com/train:1.4". Empty for the
+	// worker's default.
+	Image string `protobuf:"bytes,28,opt,name=image,proto3" json:"image,omitempty"`
+	// Stages around training: "preprocess" runs one task over the whole
+	// dataset before any training task, "evaluate" one task with the final
+	// weights once training has settled. Workers may limit the task types
+	// they run.
+	Stages []string `protobuf:"bytes,29,rep,name=stages,proto3" json:"stages,omitempty"`
+	// Held-out dataset scored with the merged weights after every epoch by a
+	// "validate" task; empty to skip validation. Its loss and accuracy are
+	// kept apart from the training metrics.
+	ValidationDatasetPath string `protobuf:"bytes,30,opt,name=validation_dataset_path,json=validationDatasetPath,proto3" json:"validation_dataset_path,omitempty"`
+	// What the task result cache may do for the job's tasks, whose results
+	// are keyed by a hash of their inputs: "read_write" (default) resolves a
+	// task from an earlier identical one and stores what it computes,
+	// "refresh" recomputes every task but stores the results, "off" leaves
+	// the cache alone.
+	ResultCache   string `protobuf:"bytes,31,opt,name=result_cache,json=resultCache,proto3" json:"result_cache,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *TrainingJobRequest) Reset() {
@@ -113,30 +323,202 @@ func (x *TrainingJobRequest) GetEpochs() int32 {
 	return 0
 }
 
-type TrainingJobResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
-	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
-	NumTasks      int32                  `protobuf:"varint,3,opt,name=num_tasks,json=numTasks,proto3" json:"num_tasks,omitempty"`
-	Message       string                 `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+func (x *TrainingJobRequest) GetMaxDurationSeconds() int64 {
+	if x != nil {
+		return x.MaxDurationSeconds
+	}
+	return 0
+}
+
+func (x *TrainingJobRequest) GetProgressTimeoutSeconds() int64 {
+	if x != nil {
+		return x.ProgressTimeoutSeconds
+	}
+	return 0
+}
+
+func (x *TrainingJobRequest) GetMaxTaskRetries() int32 {
+	if x != nil && x.MaxTaskRetries != nil {
+		return *x.MaxTaskRetries
+	}
+	return 0
+}
+
+func (x *TrainingJobRequest) GetMaxFailedTasksPercent() float64 {
+	if x != nil {
+		return x.MaxFailedTasksPercent
+	}
+	return 0
+}
+
+func (x *TrainingJobRequest) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *TrainingJobRequest) GetRequirements() *ResourceRequirements {
+	if x != nil {
+		return x.Requirements
+	}
+	return nil
+}
+
+func (x *TrainingJobRequest) GetSyncEpochs() bool {
+	if x != nil {
+		return x.SyncEpochs
+	}
+	return false
+}
+
+func (x *TrainingJobRequest) GetAggregation() string {
+	if x != nil {
+		return x.Aggregation
+	}
+	return ""
+}
+
+func (x *TrainingJobRequest) GetCheckpointEveryEpochs() int32 {
+	if x != nil {
+		return x.CheckpointEveryEpochs
+	}
+	return 0
+}
+
+func (x *TrainingJobRequest) GetTaskTimeoutSeconds() int64 {
+	if x != nil {
+		return x.TaskTimeoutSeconds
+	}
+	return 0
+}
+
+func (x *TrainingJobRequest) GetBatchesPerEpoch() int32 {
+	if x != nil {
+		return x.BatchesPerEpoch
+	}
+	return 0
+}
+
+func (x *TrainingJobRequest) GetSamplesPerBatch() int32 {
+	if x != nil {
+		return x.SamplesPerBatch
+	}
+	return 0
+}
+
+func (x *TrainingJobRequest) GetDatasetSize() int64 {
+	if x != nil {
+		return x.DatasetSize
+	}
+	return 0
+}
+
+func (x *TrainingJobRequest) GetEarlyStopping() *EarlyStopping {
+	if x != nil {
+		return x.EarlyStopping
+	}
+	return nil
+}
+
+func (x *TrainingJobRequest) GetDependsOn() []string {
+	if x != nil {
+		return x.DependsOn
+	}
+	return nil
+}
+
+func (x *TrainingJobRequest) GetOnDependencyFailure() string {
+	if x != nil {
+		return x.OnDependencyFailure
+	}
+	return ""
+}
+
+func (x *TrainingJobRequest) GetGangScheduling() bool {
+	if x != nil {
+		return x.GangScheduling
+	}
+	return false
+}
+
+func (x *TrainingJobRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *TrainingJobRequest) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *TrainingJobRequest) GetMaxParallelTasks() int32 {
+	if x != nil {
+		return x.MaxParallelTasks
+	}
+	return 0
+}
+
+func (x *TrainingJobRequest) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *TrainingJobRequest) GetStages() []string {
+	if x != nil {
+		return x.Stages
+	}
+	return nil
+}
+
+func (x *TrainingJobRequest) GetValidationDatasetPath() string {
+	if x != nil {
+		return x.ValidationDatasetPath
+	}
+	return ""
+}
+
+func (x *TrainingJobRequest) GetResultCache() string {
+	if x != nil {
+		return x.ResultCache
+	}
+	return ""
+}
+
+type EarlyStopping struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// "loss" (default, lower is better) or "accuracy" (higher is better) of
+	// training, or "val_loss" and "val_accuracy" of the epoch's validation.
+	Metric string `protobuf:"bytes,1,opt,name=metric,proto3" json:"metric,omitempty"`
+	// Settled epochs without an improvement before the job stops; 0 disables
+	// early stopping.
+	Patience int32 `protobuf:"varint,2,opt,name=patience,proto3" json:"patience,omitempty"`
+	// Smallest change of the metric that counts as an improvement.
+	MinDelta      float64 `protobuf:"fixed64,3,opt,name=min_delta,json=minDelta,proto3" json:"min_delta,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *TrainingJobResponse) Reset() {
-	*x = TrainingJobResponse{}
+func (x *EarlyStopping) Reset() {
+	*x = EarlyStopping{}
 	mi := &file_orchestrator_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *TrainingJobResponse) String() string {
+func (x *EarlyStopping) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TrainingJobResponse) ProtoMessage() {}
+func (*EarlyStopping) ProtoMessage() {}
 
-func (x *TrainingJobResponse) ProtoReflect() protoreflect.Message {
+func (x *EarlyStopping) ProtoReflect() protoreflect.Message {
 	mi := &file_orchestrator_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -148,60 +530,59 @@ func (x *TrainingJobResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TrainingJobResponse.ProtoReflect.Descriptor instead.
-func (*TrainingJobResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use EarlyStopping.ProtoReflect.Descriptor instead.
+func (*EarlyStopping) Descriptor() ([]byte, []int) {
 	return file_orchestrator_proto_rawDescGZIP(), []int{1}
 }
 
-func (x *TrainingJobResponse) GetJobId() string {
-	if x != nil {
-		return x.JobId
-	}
-	return ""
-}
-
-func (x *TrainingJobResponse) GetStatus() string {
+func (x *EarlyStopping) GetMetric() string {
 	if x != nil {
-		return x.Status
+		return x.Metric
 	}
 	return ""
 }
 
-func (x *TrainingJobResponse) GetNumTasks() int32 {
+func (x *EarlyStopping) GetPatience() int32 {
 	if x != nil {
-		return x.NumTasks
+		return x.Patience
 	}
 	return 0
 }
 
-func (x *TrainingJobResponse) GetMessage() string {
+func (x *EarlyStopping) GetMinDelta() float64 {
 	if x != nil {
-		return x.Message
+		return x.MinDelta
 	}
-	return ""
+	return 0
 }
 
-type GetJobStatusRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+type ResourceRequirements struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	MinCpuCores int32                  `protobuf:"varint,1,opt,name=min_cpu_cores,json=minCpuCores,proto3" json:"min_cpu_cores,omitempty"`
+	MinMemoryMb int64                  `protobuf:"varint,2,opt,name=min_memory_mb,json=minMemoryMb,proto3" json:"min_memory_mb,omitempty"`
+	MinGpuCount int32                  `protobuf:"varint,3,opt,name=min_gpu_count,json=minGpuCount,proto3" json:"min_gpu_count,omitempty"`
+	// Required GPU model, e.g. "a100"; empty accepts any.
+	GpuType string `protobuf:"bytes,4,opt,name=gpu_type,json=gpuType,proto3" json:"gpu_type,omitempty"`
+	// Labels the worker must carry with exactly these values.
+	Labels        map[string]string `protobuf:"bytes,5,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetJobStatusRequest) Reset() {
-	*x = GetJobStatusRequest{}
+func (x *ResourceRequirements) Reset() {
+	*x = ResourceRequirements{}
 	mi := &file_orchestrator_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetJobStatusRequest) String() string {
+func (x *ResourceRequirements) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetJobStatusRequest) ProtoMessage() {}
+func (*ResourceRequirements) ProtoMessage() {}
 
-func (x *GetJobStatusRequest) ProtoReflect() protoreflect.Message {
+func (x *ResourceRequirements) ProtoReflect() protoreflect.Message {
 	mi := &file_orchestrator_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -213,46 +594,70 @@ func (x *GetJobStatusRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetJobStatusRequest.ProtoReflect.Descriptor instead.
-func (*GetJobStatusRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ResourceRequirements.ProtoReflect.Descriptor instead.
+func (*ResourceRequirements) Descriptor() ([]byte, []int) {
 	return file_orchestrator_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *GetJobStatusRequest) GetJobId() string {
+func (x *ResourceRequirements) GetMinCpuCores() int32 {
 	if x != nil {
-		return x.JobId
+		return x.MinCpuCores
+	}
+	return 0
+}
+
+func (x *ResourceRequirements) GetMinMemoryMb() int64 {
+	if x != nil {
+		return x.MinMemoryMb
+	}
+	return 0
+}
+
+func (x *ResourceRequirements) GetMinGpuCount() int32 {
+	if x != nil {
+		return x.MinGpuCount
+	}
+	return 0
+}
+
+func (x *ResourceRequirements) GetGpuType() string {
+	if x != nil {
+		return x.GpuType
 	}
 	return ""
 }
 
-type GetJobStatusResponse struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	JobId           string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
-	Status          string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
-	Progress        int32                  `protobuf:"varint,3,opt,name=progress,proto3" json:"progress,omitempty"`
-	CompletedTasks  int32                  `protobuf:"varint,4,opt,name=completed_tasks,json=completedTasks,proto3" json:"completed_tasks,omitempty"`
-	TotalTasks      int32                  `protobuf:"varint,5,opt,name=total_tasks,json=totalTasks,proto3" json:"total_tasks,omitempty"`
-	CurrentLoss     float64                `protobuf:"fixed64,6,opt,name=current_loss,json=currentLoss,proto3" json:"current_loss,omitempty"`
-	CurrentAccuracy float64                `protobuf:"fixed64,7,opt,name=current_accuracy,json=currentAccuracy,proto3" json:"current_accuracy,omitempty"`
-	Message         string                 `protobuf:"bytes,8,opt,name=message,proto3" json:"message,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+func (x *ResourceRequirements) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
 }
 
-func (x *GetJobStatusResponse) Reset() {
-	*x = GetJobStatusResponse{}
+type TrainingJobResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	NumTasks      int32                  `protobuf:"varint,3,opt,name=num_tasks,json=numTasks,proto3" json:"num_tasks,omitempty"`
+	Message       string                 `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrainingJobResponse) Reset() {
+	*x = TrainingJobResponse{}
 	mi := &file_orchestrator_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetJobStatusResponse) String() string {
+func (x *TrainingJobResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetJobStatusResponse) ProtoMessage() {}
+func (*TrainingJobResponse) ProtoMessage() {}
 
-func (x *GetJobStatusResponse) ProtoReflect() protoreflect.Message {
+func (x *TrainingJobResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_orchestrator_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -264,9 +669,149 @@ func (x *GetJobStatusResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetJobStatusResponse.ProtoReflect.Descriptor instead.
-func (*GetJobStatusResponse) Descriptor() ([]byte, []int) {
-	return file_orchestrator_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use TrainingJobResponse.ProtoReflect.Descriptor instead.
+func (*TrainingJobResponse) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *TrainingJobResponse) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *TrainingJobResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *TrainingJobResponse) GetNumTasks() int32 {
+	if x != nil {
+		return x.NumTasks
+	}
+	return 0
+}
+
+func (x *TrainingJobResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type GetJobStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetJobStatusRequest) Reset() {
+	*x = GetJobStatusRequest{}
+	mi := &file_orchestrator_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetJobStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetJobStatusRequest) ProtoMessage() {}
+
+func (x *GetJobStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetJobStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetJobStatusRequest) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetJobStatusRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type GetJobStatusResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	JobId           string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Status          string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Progress        int32                  `protobuf:"varint,3,opt,name=progress,proto3" json:"progress,omitempty"`
+	CompletedTasks  int32                  `protobuf:"varint,4,opt,name=completed_tasks,json=completedTasks,proto3" json:"completed_tasks,omitempty"`
+	TotalTasks      int32                  `protobuf:"varint,5,opt,name=total_tasks,json=totalTasks,proto3" json:"total_tasks,omitempty"`
+	CurrentLoss     float64                `protobuf:"fixed64,6,opt,name=current_loss,json=currentLoss,proto3" json:"current_loss,omitempty"`
+	CurrentAccuracy float64                `protobuf:"fixed64,7,opt,name=current_accuracy,json=currentAccuracy,proto3" json:"current_accuracy,omitempty"`
+	Message         string                 `protobuf:"bytes,8,opt,name=message,proto3" json:"message,omitempty"`
+	// Requested worker cap and the workers currently serving the job.
+	NumWorkers       int32    `protobuf:"varint,9,opt,name=num_workers,json=numWorkers,proto3" json:"num_workers,omitempty"`
+	AllocatedWorkers []string `protobuf:"bytes,10,rep,name=allocated_workers,json=allocatedWorkers,proto3" json:"allocated_workers,omitempty"`
+	// 1-based position in the admission queue while the job is QUEUED, else 0.
+	QueuePosition int32 `protobuf:"varint,11,opt,name=queue_position,json=queuePosition,proto3" json:"queue_position,omitempty"`
+	// Metrics per epoch, as means over completed tasks weighted by batch count.
+	EpochMetrics []*EpochMetrics `protobuf:"bytes,12,rep,name=epoch_metrics,json=epochMetrics,proto3" json:"epoch_metrics,omitempty"`
+	// Typed form of status, and every status change so far.
+	State       JobState               `protobuf:"varint,13,opt,name=state,proto3,enum=orchestrator.JobState" json:"state,omitempty"`
+	Transitions []*JobStatusTransition `protobuf:"bytes,14,rep,name=transitions,proto3" json:"transitions,omitempty"`
+	// How long the job's recent tasks took, and the time its remaining tasks
+	// are expected to take at that pace (0 until enough tasks completed).
+	TaskDurations        *TaskDurationStats `protobuf:"bytes,15,opt,name=task_durations,json=taskDurations,proto3" json:"task_durations,omitempty"`
+	EstimatedRemainingMs int64              `protobuf:"varint,16,opt,name=estimated_remaining_ms,json=estimatedRemainingMs,proto3" json:"estimated_remaining_ms,omitempty"`
+	Namespace            string             `protobuf:"bytes,17,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// Region the job was delegated to; empty when it runs here.
+	Region string `protobuf:"bytes,18,opt,name=region,proto3" json:"region,omitempty"`
+	// Like progress, but counting the reported progress of running tasks.
+	DetailedProgress float64         `protobuf:"fixed64,19,opt,name=detailed_progress,json=detailedProgress,proto3" json:"detailed_progress,omitempty"`
+	RunningTasks     []*TaskProgress `protobuf:"bytes,20,rep,name=running_tasks,json=runningTasks,proto3" json:"running_tasks,omitempty"`
+	// Metrics of each epoch's validation, for jobs with a validation dataset.
+	ValidationMetrics []*EpochMetrics `protobuf:"bytes,21,rep,name=validation_metrics,json=validationMetrics,proto3" json:"validation_metrics,omitempty"`
+	// Completed tasks whose result came from the task result cache.
+	CachedTasks   int32 `protobuf:"varint,22,opt,name=cached_tasks,json=cachedTasks,proto3" json:"cached_tasks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetJobStatusResponse) Reset() {
+	*x = GetJobStatusResponse{}
+	mi := &file_orchestrator_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetJobStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetJobStatusResponse) ProtoMessage() {}
+
+func (x *GetJobStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetJobStatusResponse.ProtoReflect.Descriptor instead.
+func (*GetJobStatusResponse) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *GetJobStatusResponse) GetJobId() string {
@@ -283,70 +828,4736 @@ func (x *GetJobStatusResponse) GetStatus() string {
 	return ""
 }
 
-func (x *GetJobStatusResponse) GetProgress() int32 {
+func (x *GetJobStatusResponse) GetProgress() int32 {
+	if x != nil {
+		return x.Progress
+	}
+	return 0
+}
+
+func (x *GetJobStatusResponse) GetCompletedTasks() int32 {
+	if x != nil {
+		return x.CompletedTasks
+	}
+	return 0
+}
+
+func (x *GetJobStatusResponse) GetTotalTasks() int32 {
+	if x != nil {
+		return x.TotalTasks
+	}
+	return 0
+}
+
+func (x *GetJobStatusResponse) GetCurrentLoss() float64 {
+	if x != nil {
+		return x.CurrentLoss
+	}
+	return 0
+}
+
+func (x *GetJobStatusResponse) GetCurrentAccuracy() float64 {
+	if x != nil {
+		return x.CurrentAccuracy
+	}
+	return 0
+}
+
+func (x *GetJobStatusResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *GetJobStatusResponse) GetNumWorkers() int32 {
+	if x != nil {
+		return x.NumWorkers
+	}
+	return 0
+}
+
+func (x *GetJobStatusResponse) GetAllocatedWorkers() []string {
+	if x != nil {
+		return x.AllocatedWorkers
+	}
+	return nil
+}
+
+func (x *GetJobStatusResponse) GetQueuePosition() int32 {
+	if x != nil {
+		return x.QueuePosition
+	}
+	return 0
+}
+
+func (x *GetJobStatusResponse) GetEpochMetrics() []*EpochMetrics {
+	if x != nil {
+		return x.EpochMetrics
+	}
+	return nil
+}
+
+func (x *GetJobStatusResponse) GetState() JobState {
+	if x != nil {
+		return x.State
+	}
+	return JobState_JOB_STATE_UNSPECIFIED
+}
+
+func (x *GetJobStatusResponse) GetTransitions() []*JobStatusTransition {
+	if x != nil {
+		return x.Transitions
+	}
+	return nil
+}
+
+func (x *GetJobStatusResponse) GetTaskDurations() *TaskDurationStats {
+	if x != nil {
+		return x.TaskDurations
+	}
+	return nil
+}
+
+func (x *GetJobStatusResponse) GetEstimatedRemainingMs() int64 {
+	if x != nil {
+		return x.EstimatedRemainingMs
+	}
+	return 0
+}
+
+func (x *GetJobStatusResponse) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *GetJobStatusResponse) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *GetJobStatusResponse) GetDetailedProgress() float64 {
+	if x != nil {
+		return x.DetailedProgress
+	}
+	return 0
+}
+
+func (x *GetJobStatusResponse) GetRunningTasks() []*TaskProgress {
+	if x != nil {
+		return x.RunningTasks
+	}
+	return nil
+}
+
+func (x *GetJobStatusResponse) GetValidationMetrics() []*EpochMetrics {
+	if x != nil {
+		return x.ValidationMetrics
+	}
+	return nil
+}
+
+func (x *GetJobStatusResponse) GetCachedTasks() int32 {
+	if x != nil {
+		return x.CachedTasks
+	}
+	return 0
+}
+
+// JobInfo describes a job. ListJobs leaves the repeated fields and
+// hyperparameters empty; GetJob fills them in.
+type JobInfo struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	JobId                string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	UserId               string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ModelType            string                 `protobuf:"bytes,3,opt,name=model_type,json=modelType,proto3" json:"model_type,omitempty"`
+	DatasetPath          string                 `protobuf:"bytes,4,opt,name=dataset_path,json=datasetPath,proto3" json:"dataset_path,omitempty"`
+	State                JobState               `protobuf:"varint,5,opt,name=state,proto3,enum=orchestrator.JobState" json:"state,omitempty"`
+	Status               string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	StatusMessage        string                 `protobuf:"bytes,7,opt,name=status_message,json=statusMessage,proto3" json:"status_message,omitempty"`
+	Priority             int32                  `protobuf:"varint,8,opt,name=priority,proto3" json:"priority,omitempty"`
+	Epochs               int32                  `protobuf:"varint,9,opt,name=epochs,proto3" json:"epochs,omitempty"`
+	CurrentEpoch         int32                  `protobuf:"varint,10,opt,name=current_epoch,json=currentEpoch,proto3" json:"current_epoch,omitempty"`
+	NumWorkers           int32                  `protobuf:"varint,11,opt,name=num_workers,json=numWorkers,proto3" json:"num_workers,omitempty"`
+	TotalTasks           int32                  `protobuf:"varint,12,opt,name=total_tasks,json=totalTasks,proto3" json:"total_tasks,omitempty"`
+	CompletedTasks       int32                  `protobuf:"varint,13,opt,name=completed_tasks,json=completedTasks,proto3" json:"completed_tasks,omitempty"`
+	FailedTasks          int32                  `protobuf:"varint,14,opt,name=failed_tasks,json=failedTasks,proto3" json:"failed_tasks,omitempty"`
+	Progress             int32                  `protobuf:"varint,15,opt,name=progress,proto3" json:"progress,omitempty"`
+	CurrentLoss          float64                `protobuf:"fixed64,16,opt,name=current_loss,json=currentLoss,proto3" json:"current_loss,omitempty"`
+	CurrentAccuracy      float64                `protobuf:"fixed64,17,opt,name=current_accuracy,json=currentAccuracy,proto3" json:"current_accuracy,omitempty"`
+	CreatedAtMs          int64                  `protobuf:"varint,18,opt,name=created_at_ms,json=createdAtMs,proto3" json:"created_at_ms,omitempty"`
+	UpdatedAtMs          int64                  `protobuf:"varint,19,opt,name=updated_at_ms,json=updatedAtMs,proto3" json:"updated_at_ms,omitempty"`
+	StartedAtMs          int64                  `protobuf:"varint,20,opt,name=started_at_ms,json=startedAtMs,proto3" json:"started_at_ms,omitempty"`
+	Hyperparameters      map[string]string      `protobuf:"bytes,21,rep,name=hyperparameters,proto3" json:"hyperparameters,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	DependsOn            []string               `protobuf:"bytes,22,rep,name=depends_on,json=dependsOn,proto3" json:"depends_on,omitempty"`
+	EpochMetrics         []*EpochMetrics        `protobuf:"bytes,23,rep,name=epoch_metrics,json=epochMetrics,proto3" json:"epoch_metrics,omitempty"`
+	Transitions          []*JobStatusTransition `protobuf:"bytes,24,rep,name=transitions,proto3" json:"transitions,omitempty"`
+	AllocatedWorkers     []string               `protobuf:"bytes,25,rep,name=allocated_workers,json=allocatedWorkers,proto3" json:"allocated_workers,omitempty"`
+	QueuePosition        int32                  `protobuf:"varint,26,opt,name=queue_position,json=queuePosition,proto3" json:"queue_position,omitempty"`
+	TaskDurations        *TaskDurationStats     `protobuf:"bytes,27,opt,name=task_durations,json=taskDurations,proto3" json:"task_durations,omitempty"`
+	EstimatedRemainingMs int64                  `protobuf:"varint,28,opt,name=estimated_remaining_ms,json=estimatedRemainingMs,proto3" json:"estimated_remaining_ms,omitempty"`
+	Namespace            string                 `protobuf:"bytes,29,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Region               string                 `protobuf:"bytes,30,opt,name=region,proto3" json:"region,omitempty"`
+	MaxParallelTasks     int32                  `protobuf:"varint,31,opt,name=max_parallel_tasks,json=maxParallelTasks,proto3" json:"max_parallel_tasks,omitempty"`
+	Image                string                 `protobuf:"bytes,32,opt,name=image,proto3" json:"image,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *JobInfo) Reset() {
+	*x = JobInfo{}
+	mi := &file_orchestrator_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobInfo) ProtoMessage() {}
+
+func (x *JobInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobInfo.ProtoReflect.Descriptor instead.
+func (*JobInfo) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *JobInfo) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *JobInfo) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *JobInfo) GetModelType() string {
+	if x != nil {
+		return x.ModelType
+	}
+	return ""
+}
+
+func (x *JobInfo) GetDatasetPath() string {
+	if x != nil {
+		return x.DatasetPath
+	}
+	return ""
+}
+
+func (x *JobInfo) GetState() JobState {
+	if x != nil {
+		return x.State
+	}
+	return JobState_JOB_STATE_UNSPECIFIED
+}
+
+func (x *JobInfo) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *JobInfo) GetStatusMessage() string {
+	if x != nil {
+		return x.StatusMessage
+	}
+	return ""
+}
+
+func (x *JobInfo) GetPriority() int32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+func (x *JobInfo) GetEpochs() int32 {
+	if x != nil {
+		return x.Epochs
+	}
+	return 0
+}
+
+func (x *JobInfo) GetCurrentEpoch() int32 {
+	if x != nil {
+		return x.CurrentEpoch
+	}
+	return 0
+}
+
+func (x *JobInfo) GetNumWorkers() int32 {
+	if x != nil {
+		return x.NumWorkers
+	}
+	return 0
+}
+
+func (x *JobInfo) GetTotalTasks() int32 {
+	if x != nil {
+		return x.TotalTasks
+	}
+	return 0
+}
+
+func (x *JobInfo) GetCompletedTasks() int32 {
+	if x != nil {
+		return x.CompletedTasks
+	}
+	return 0
+}
+
+func (x *JobInfo) GetFailedTasks() int32 {
+	if x != nil {
+		return x.FailedTasks
+	}
+	return 0
+}
+
+func (x *JobInfo) GetProgress() int32 {
+	if x != nil {
+		return x.Progress
+	}
+	return 0
+}
+
+func (x *JobInfo) GetCurrentLoss() float64 {
+	if x != nil {
+		return x.CurrentLoss
+	}
+	return 0
+}
+
+func (x *JobInfo) GetCurrentAccuracy() float64 {
+	if x != nil {
+		return x.CurrentAccuracy
+	}
+	return 0
+}
+
+func (x *JobInfo) GetCreatedAtMs() int64 {
+	if x != nil {
+		return x.CreatedAtMs
+	}
+	return 0
+}
+
+func (x *JobInfo) GetUpdatedAtMs() int64 {
+	if x != nil {
+		return x.UpdatedAtMs
+	}
+	return 0
+}
+
+func (x *JobInfo) GetStartedAtMs() int64 {
+	if x != nil {
+		return x.StartedAtMs
+	}
+	return 0
+}
+
+func (x *JobInfo) GetHyperparameters() map[string]string {
+	if x != nil {
+		return x.Hyperparameters
+	}
+	return nil
+}
+
+func (x *JobInfo) GetDependsOn() []string {
+	if x != nil {
+		return x.DependsOn
+	}
+	return nil
+}
+
+func (x *JobInfo) GetEpochMetrics() []*EpochMetrics {
+	if x != nil {
+		return x.EpochMetrics
+	}
+	return nil
+}
+
+func (x *JobInfo) GetTransitions() []*JobStatusTransition {
+	if x != nil {
+		return x.Transitions
+	}
+	return nil
+}
+
+func (x *JobInfo) GetAllocatedWorkers() []string {
+	if x != nil {
+		return x.AllocatedWorkers
+	}
+	return nil
+}
+
+func (x *JobInfo) GetQueuePosition() int32 {
+	if x != nil {
+		return x.QueuePosition
+	}
+	return 0
+}
+
+func (x *JobInfo) GetTaskDurations() *TaskDurationStats {
+	if x != nil {
+		return x.TaskDurations
+	}
+	return nil
+}
+
+func (x *JobInfo) GetEstimatedRemainingMs() int64 {
+	if x != nil {
+		return x.EstimatedRemainingMs
+	}
+	return 0
+}
+
+func (x *JobInfo) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *JobInfo) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *JobInfo) GetMaxParallelTasks() int32 {
+	if x != nil {
+		return x.MaxParallelTasks
+	}
+	return 0
+}
+
+func (x *JobInfo) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+type GetJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetJobRequest) Reset() {
+	*x = GetJobRequest{}
+	mi := &file_orchestrator_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetJobRequest) ProtoMessage() {}
+
+func (x *GetJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetJobRequest.ProtoReflect.Descriptor instead.
+func (*GetJobRequest) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetJobRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type GetJobResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Job           *JobInfo               `protobuf:"bytes,1,opt,name=job,proto3" json:"job,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetJobResponse) Reset() {
+	*x = GetJobResponse{}
+	mi := &file_orchestrator_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetJobResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetJobResponse) ProtoMessage() {}
+
+func (x *GetJobResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetJobResponse.ProtoReflect.Descriptor instead.
+func (*GetJobResponse) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetJobResponse) GetJob() *JobInfo {
+	if x != nil {
+		return x.Job
+	}
+	return nil
+}
+
+type ListJobsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Filters; empty matches every job.
+	UserId    string     `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	States    []JobState `protobuf:"varint,2,rep,packed,name=states,proto3,enum=orchestrator.JobState" json:"states,omitempty"`
+	ModelType string     `protobuf:"bytes,3,opt,name=model_type,json=modelType,proto3" json:"model_type,omitempty"`
+	Namespace string     `protobuf:"bytes,6,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	// At most this many jobs are returned (default 50, at most 500).
+	PageSize int32 `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// next_page_token of the previous page, or empty for the first page.
+	PageToken     string `protobuf:"bytes,5,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListJobsRequest) Reset() {
+	*x = ListJobsRequest{}
+	mi := &file_orchestrator_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListJobsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJobsRequest) ProtoMessage() {}
+
+func (x *ListJobsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJobsRequest.ProtoReflect.Descriptor instead.
+func (*ListJobsRequest) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListJobsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListJobsRequest) GetStates() []JobState {
+	if x != nil {
+		return x.States
+	}
+	return nil
+}
+
+func (x *ListJobsRequest) GetModelType() string {
+	if x != nil {
+		return x.ModelType
+	}
+	return ""
+}
+
+func (x *ListJobsRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *ListJobsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListJobsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListJobsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Jobs  []*JobInfo             `protobuf:"bytes,1,rep,name=jobs,proto3" json:"jobs,omitempty"`
+	// Empty on the last page.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	// Jobs matching the filters across all pages.
+	Total         int32 `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListJobsResponse) Reset() {
+	*x = ListJobsResponse{}
+	mi := &file_orchestrator_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListJobsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListJobsResponse) ProtoMessage() {}
+
+func (x *ListJobsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListJobsResponse.ProtoReflect.Descriptor instead.
+func (*ListJobsResponse) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListJobsResponse) GetJobs() []*JobInfo {
+	if x != nil {
+		return x.Jobs
+	}
+	return nil
+}
+
+func (x *ListJobsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListJobsResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type GetJobTasksRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	JobId string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	// Filters; empty or zero matches every task.
+	Status   string `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Epoch    int32  `protobuf:"varint,3,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	WorkerId string `protobuf:"bytes,4,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	// At most this many tasks are returned (default 100, at most 1000).
+	PageSize int32 `protobuf:"varint,5,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// next_page_token of the previous page, or empty for the first page.
+	PageToken     string `protobuf:"bytes,6,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetJobTasksRequest) Reset() {
+	*x = GetJobTasksRequest{}
+	mi := &file_orchestrator_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetJobTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetJobTasksRequest) ProtoMessage() {}
+
+func (x *GetJobTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetJobTasksRequest.ProtoReflect.Descriptor instead.
+func (*GetJobTasksRequest) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetJobTasksRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *GetJobTasksRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *GetJobTasksRequest) GetEpoch() int32 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+func (x *GetJobTasksRequest) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *GetJobTasksRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *GetJobTasksRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type GetJobTasksResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	JobId string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	// Tasks by epoch, then batch.
+	Tasks []*TaskInfo `protobuf:"bytes,2,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	// Empty on the last page.
+	NextPageToken string `protobuf:"bytes,3,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	// Tasks matching the filters across all pages.
+	Total int32 `protobuf:"varint,4,opt,name=total,proto3" json:"total,omitempty"`
+	// Over the job's recent tasks, regardless of the filters.
+	Durations     *TaskDurationStats `protobuf:"bytes,5,opt,name=durations,proto3" json:"durations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetJobTasksResponse) Reset() {
+	*x = GetJobTasksResponse{}
+	mi := &file_orchestrator_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetJobTasksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetJobTasksResponse) ProtoMessage() {}
+
+func (x *GetJobTasksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetJobTasksResponse.ProtoReflect.Descriptor instead.
+func (*GetJobTasksResponse) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetJobTasksResponse) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *GetJobTasksResponse) GetTasks() []*TaskInfo {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+func (x *GetJobTasksResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *GetJobTasksResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *GetJobTasksResponse) GetDurations() *TaskDurationStats {
+	if x != nil {
+		return x.Durations
+	}
+	return nil
+}
+
+// Percentiles of task run time, from assignment to successful completion,
+// over the most recent tasks.
+type TaskDurationStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Samples       int32                  `protobuf:"varint,1,opt,name=samples,proto3" json:"samples,omitempty"`
+	P50Ms         int64                  `protobuf:"varint,2,opt,name=p50_ms,json=p50Ms,proto3" json:"p50_ms,omitempty"`
+	P95Ms         int64                  `protobuf:"varint,3,opt,name=p95_ms,json=p95Ms,proto3" json:"p95_ms,omitempty"`
+	P99Ms         int64                  `protobuf:"varint,4,opt,name=p99_ms,json=p99Ms,proto3" json:"p99_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskDurationStats) Reset() {
+	*x = TaskDurationStats{}
+	mi := &file_orchestrator_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskDurationStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskDurationStats) ProtoMessage() {}
+
+func (x *TaskDurationStats) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskDurationStats.ProtoReflect.Descriptor instead.
+func (*TaskDurationStats) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *TaskDurationStats) GetSamples() int32 {
+	if x != nil {
+		return x.Samples
+	}
+	return 0
+}
+
+func (x *TaskDurationStats) GetP50Ms() int64 {
+	if x != nil {
+		return x.P50Ms
+	}
+	return 0
+}
+
+func (x *TaskDurationStats) GetP95Ms() int64 {
+	if x != nil {
+		return x.P95Ms
+	}
+	return 0
+}
+
+func (x *TaskDurationStats) GetP99Ms() int64 {
+	if x != nil {
+		return x.P99Ms
+	}
+	return 0
+}
+
+type TaskInfo struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	TaskId string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Status string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	// Worker running the task, or that last ran it.
+	WorkerId   string  `protobuf:"bytes,3,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	Epoch      int32   `protobuf:"varint,4,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	BatchStart int32   `protobuf:"varint,5,opt,name=batch_start,json=batchStart,proto3" json:"batch_start,omitempty"`
+	BatchEnd   int32   `protobuf:"varint,6,opt,name=batch_end,json=batchEnd,proto3" json:"batch_end,omitempty"`
+	Loss       float64 `protobuf:"fixed64,7,opt,name=loss,proto3" json:"loss,omitempty"`
+	Accuracy   float64 `protobuf:"fixed64,8,opt,name=accuracy,proto3" json:"accuracy,omitempty"`
+	// Times the task has been handed to a worker.
+	Attempts         int32  `protobuf:"varint,9,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	LastError        string `protobuf:"bytes,10,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	CreatedAtMs      int64  `protobuf:"varint,11,opt,name=created_at_ms,json=createdAtMs,proto3" json:"created_at_ms,omitempty"`
+	AssignedAtMs     int64  `protobuf:"varint,12,opt,name=assigned_at_ms,json=assignedAtMs,proto3" json:"assigned_at_ms,omitempty"`
+	CompletedAtMs    int64  `protobuf:"varint,13,opt,name=completed_at_ms,json=completedAtMs,proto3" json:"completed_at_ms,omitempty"`
+	LeaseExpiresAtMs int64  `protobuf:"varint,14,opt,name=lease_expires_at_ms,json=leaseExpiresAtMs,proto3" json:"lease_expires_at_ms,omitempty"`
+	// Time spent queued before the last assignment, and running since it.
+	WaitMs int64 `protobuf:"varint,15,opt,name=wait_ms,json=waitMs,proto3" json:"wait_ms,omitempty"`
+	RunMs  int64 `protobuf:"varint,16,opt,name=run_ms,json=runMs,proto3" json:"run_ms,omitempty"`
+	// Running well past the job's P95 task duration.
+	Straggling bool `protobuf:"varint,17,opt,name=straggling,proto3" json:"straggling,omitempty"`
+	// Set while the task runs, once its worker has reported progress.
+	Progress *TaskProgress `protobuf:"bytes,18,opt,name=progress,proto3" json:"progress,omitempty"`
+	// Resources the last attempt used, as its worker reported them.
+	ResourceUsage *TaskResourceUsage `protobuf:"bytes,19,opt,name=resource_usage,json=resourceUsage,proto3" json:"resource_usage,omitempty"`
+	// "train", "preprocess", "evaluate" or "validate".
+	Type          string `protobuf:"bytes,20,opt,name=type,proto3" json:"type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskInfo) Reset() {
+	*x = TaskInfo{}
+	mi := &file_orchestrator_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskInfo) ProtoMessage() {}
+
+func (x *TaskInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskInfo.ProtoReflect.Descriptor instead.
+func (*TaskInfo) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *TaskInfo) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *TaskInfo) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *TaskInfo) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *TaskInfo) GetEpoch() int32 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+func (x *TaskInfo) GetBatchStart() int32 {
+	if x != nil {
+		return x.BatchStart
+	}
+	return 0
+}
+
+func (x *TaskInfo) GetBatchEnd() int32 {
+	if x != nil {
+		return x.BatchEnd
+	}
+	return 0
+}
+
+func (x *TaskInfo) GetLoss() float64 {
+	if x != nil {
+		return x.Loss
+	}
+	return 0
+}
+
+func (x *TaskInfo) GetAccuracy() float64 {
+	if x != nil {
+		return x.Accuracy
+	}
+	return 0
+}
+
+func (x *TaskInfo) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *TaskInfo) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *TaskInfo) GetCreatedAtMs() int64 {
+	if x != nil {
+		return x.CreatedAtMs
+	}
+	return 0
+}
+
+func (x *TaskInfo) GetAssignedAtMs() int64 {
+	if x != nil {
+		return x.AssignedAtMs
+	}
+	return 0
+}
+
+func (x *TaskInfo) GetCompletedAtMs() int64 {
+	if x != nil {
+		return x.CompletedAtMs
+	}
+	return 0
+}
+
+func (x *TaskInfo) GetLeaseExpiresAtMs() int64 {
+	if x != nil {
+		return x.LeaseExpiresAtMs
+	}
+	return 0
+}
+
+func (x *TaskInfo) GetWaitMs() int64 {
+	if x != nil {
+		return x.WaitMs
+	}
+	return 0
+}
+
+func (x *TaskInfo) GetRunMs() int64 {
+	if x != nil {
+		return x.RunMs
+	}
+	return 0
+}
+
+func (x *TaskInfo) GetStraggling() bool {
+	if x != nil {
+		return x.Straggling
+	}
+	return false
+}
+
+func (x *TaskInfo) GetProgress() *TaskProgress {
+	if x != nil {
+		return x.Progress
+	}
+	return nil
+}
+
+func (x *TaskInfo) GetResourceUsage() *TaskResourceUsage {
+	if x != nil {
+		return x.ResourceUsage
+	}
+	return nil
+}
+
+func (x *TaskInfo) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+type JobStatusTransition struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          JobState               `protobuf:"varint,1,opt,name=from,proto3,enum=orchestrator.JobState" json:"from,omitempty"`
+	To            JobState               `protobuf:"varint,2,opt,name=to,proto3,enum=orchestrator.JobState" json:"to,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	TimestampMs   int64                  `protobuf:"varint,4,opt,name=timestamp_ms,json=timestampMs,proto3" json:"timestamp_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobStatusTransition) Reset() {
+	*x = JobStatusTransition{}
+	mi := &file_orchestrator_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobStatusTransition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobStatusTransition) ProtoMessage() {}
+
+func (x *JobStatusTransition) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobStatusTransition.ProtoReflect.Descriptor instead.
+func (*JobStatusTransition) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *JobStatusTransition) GetFrom() JobState {
+	if x != nil {
+		return x.From
+	}
+	return JobState_JOB_STATE_UNSPECIFIED
+}
+
+func (x *JobStatusTransition) GetTo() JobState {
+	if x != nil {
+		return x.To
+	}
+	return JobState_JOB_STATE_UNSPECIFIED
+}
+
+func (x *JobStatusTransition) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *JobStatusTransition) GetTimestampMs() int64 {
+	if x != nil {
+		return x.TimestampMs
+	}
+	return 0
+}
+
+type EpochMetrics struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Epoch          int32                  `protobuf:"varint,1,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Loss           float64                `protobuf:"fixed64,2,opt,name=loss,proto3" json:"loss,omitempty"`
+	Accuracy       float64                `protobuf:"fixed64,3,opt,name=accuracy,proto3" json:"accuracy,omitempty"`
+	CompletedTasks int32                  `protobuf:"varint,4,opt,name=completed_tasks,json=completedTasks,proto3" json:"completed_tasks,omitempty"`
+	// True once no task of the epoch is pending or running.
+	Complete      bool `protobuf:"varint,5,opt,name=complete,proto3" json:"complete,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EpochMetrics) Reset() {
+	*x = EpochMetrics{}
+	mi := &file_orchestrator_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EpochMetrics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EpochMetrics) ProtoMessage() {}
+
+func (x *EpochMetrics) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EpochMetrics.ProtoReflect.Descriptor instead.
+func (*EpochMetrics) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *EpochMetrics) GetEpoch() int32 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+func (x *EpochMetrics) GetLoss() float64 {
+	if x != nil {
+		return x.Loss
+	}
+	return 0
+}
+
+func (x *EpochMetrics) GetAccuracy() float64 {
+	if x != nil {
+		return x.Accuracy
+	}
+	return 0
+}
+
+func (x *EpochMetrics) GetCompletedTasks() int32 {
+	if x != nil {
+		return x.CompletedTasks
+	}
+	return 0
+}
+
+func (x *EpochMetrics) GetComplete() bool {
+	if x != nil {
+		return x.Complete
+	}
+	return false
+}
+
+type WatchJobStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchJobStatusRequest) Reset() {
+	*x = WatchJobStatusRequest{}
+	mi := &file_orchestrator_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchJobStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchJobStatusRequest) ProtoMessage() {}
+
+func (x *WatchJobStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchJobStatusRequest.ProtoReflect.Descriptor instead.
+func (*WatchJobStatusRequest) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *WatchJobStatusRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type AssignTaskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkerId      string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssignTaskRequest) Reset() {
+	*x = AssignTaskRequest{}
+	mi := &file_orchestrator_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignTaskRequest) ProtoMessage() {}
+
+func (x *AssignTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignTaskRequest.ProtoReflect.Descriptor instead.
+func (*AssignTaskRequest) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *AssignTaskRequest) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+type AssignTaskResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	TaskId          string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	JobId           string                 `protobuf:"bytes,2,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	ModelType       string                 `protobuf:"bytes,3,opt,name=model_type,json=modelType,proto3" json:"model_type,omitempty"`
+	DatasetPath     string                 `protobuf:"bytes,4,opt,name=dataset_path,json=datasetPath,proto3" json:"dataset_path,omitempty"`
+	Hyperparameters map[string]string      `protobuf:"bytes,5,rep,name=hyperparameters,proto3" json:"hyperparameters,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Epoch           int32                  `protobuf:"varint,6,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	BatchStart      int32                  `protobuf:"varint,7,opt,name=batch_start,json=batchStart,proto3" json:"batch_start,omitempty"`
+	BatchEnd        int32                  `protobuf:"varint,8,opt,name=batch_end,json=batchEnd,proto3" json:"batch_end,omitempty"`
+	// Merged weights from the latest aggregated epoch; empty before the first
+	// epoch completes. Encoded as little-endian float32 values.
+	ModelWeights []byte `protobuf:"bytes,9,opt,name=model_weights,json=modelWeights,proto3" json:"model_weights,omitempty"`
+	// Container image to run the task in; empty for the worker's default.
+	Image string `protobuf:"bytes,10,opt,name=image,proto3" json:"image,omitempty"`
+	// GPUs the task is pinned to, from the job's min_gpu_count.
+	GpuCount int32 `protobuf:"varint,11,opt,name=gpu_count,json=gpuCount,proto3" json:"gpu_count,omitempty"`
+	// CPU and memory the task is limited to, from the job's min_cpu_cores and
+	// min_memory_mb; 0 for the worker's default.
+	CpuCores int32 `protobuf:"varint,12,opt,name=cpu_cores,json=cpuCores,proto3" json:"cpu_cores,omitempty"`
+	MemoryMb int64 `protobuf:"varint,13,opt,name=memory_mb,json=memoryMb,proto3" json:"memory_mb,omitempty"`
+	// When the task last entered the queue, in Unix milliseconds, for workers
+	// to measure how long it waited for one.
+	QueuedAtMs int64 `protobuf:"varint,14,opt,name=queued_at_ms,json=queuedAtMs,proto3" json:"queued_at_ms,omitempty"`
+	// "train", "preprocess", "evaluate" or "validate".
+	TaskType string `protobuf:"bytes,15,opt,name=task_type,json=taskType,proto3" json:"task_type,omitempty"`
+	// Which attempt at the task this is, counting from 1.
+	Attempt       int32 `protobuf:"varint,16,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssignTaskResponse) Reset() {
+	*x = AssignTaskResponse{}
+	mi := &file_orchestrator_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssignTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssignTaskResponse) ProtoMessage() {}
+
+func (x *AssignTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssignTaskResponse.ProtoReflect.Descriptor instead.
+func (*AssignTaskResponse) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *AssignTaskResponse) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *AssignTaskResponse) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *AssignTaskResponse) GetModelType() string {
+	if x != nil {
+		return x.ModelType
+	}
+	return ""
+}
+
+func (x *AssignTaskResponse) GetDatasetPath() string {
+	if x != nil {
+		return x.DatasetPath
+	}
+	return ""
+}
+
+func (x *AssignTaskResponse) GetHyperparameters() map[string]string {
+	if x != nil {
+		return x.Hyperparameters
+	}
+	return nil
+}
+
+func (x *AssignTaskResponse) GetEpoch() int32 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+func (x *AssignTaskResponse) GetBatchStart() int32 {
+	if x != nil {
+		return x.BatchStart
+	}
+	return 0
+}
+
+func (x *AssignTaskResponse) GetBatchEnd() int32 {
+	if x != nil {
+		return x.BatchEnd
+	}
+	return 0
+}
+
+func (x *AssignTaskResponse) GetModelWeights() []byte {
+	if x != nil {
+		return x.ModelWeights
+	}
+	return nil
+}
+
+func (x *AssignTaskResponse) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *AssignTaskResponse) GetGpuCount() int32 {
+	if x != nil {
+		return x.GpuCount
+	}
+	return 0
+}
+
+func (x *AssignTaskResponse) GetCpuCores() int32 {
+	if x != nil {
+		return x.CpuCores
+	}
+	return 0
+}
+
+func (x *AssignTaskResponse) GetMemoryMb() int64 {
+	if x != nil {
+		return x.MemoryMb
+	}
+	return 0
+}
+
+func (x *AssignTaskResponse) GetQueuedAtMs() int64 {
+	if x != nil {
+		return x.QueuedAtMs
+	}
+	return 0
+}
+
+func (x *AssignTaskResponse) GetTaskType() string {
+	if x != nil {
+		return x.TaskType
+	}
+	return ""
+}
+
+func (x *AssignTaskResponse) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+type TaskStreamRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkerId      string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskStreamRequest) Reset() {
+	*x = TaskStreamRequest{}
+	mi := &file_orchestrator_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskStreamRequest) ProtoMessage() {}
+
+func (x *TaskStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskStreamRequest.ProtoReflect.Descriptor instead.
+func (*TaskStreamRequest) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *TaskStreamRequest) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+type TaskCompletionRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	TaskId       string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	JobId        string                 `protobuf:"bytes,2,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	WorkerId     string                 `protobuf:"bytes,3,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	Success      bool                   `protobuf:"varint,4,opt,name=success,proto3" json:"success,omitempty"`
+	ErrorMessage string                 `protobuf:"bytes,5,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	Loss         float64                `protobuf:"fixed64,6,opt,name=loss,proto3" json:"loss,omitempty"`
+	Accuracy     float64                `protobuf:"fixed64,7,opt,name=accuracy,proto3" json:"accuracy,omitempty"`
+	ModelWeights []byte                 `protobuf:"bytes,8,opt,name=model_weights,json=modelWeights,proto3" json:"model_weights,omitempty"`
+	// Whether the worker found the task's dataset in its cache.
+	DatasetCache DatasetCacheResult `protobuf:"varint,9,opt,name=dataset_cache,json=datasetCache,proto3,enum=orchestrator.DatasetCacheResult" json:"dataset_cache,omitempty"`
+	// Where the worker uploaded the task's weights, instead of sending them
+	// in model_weights.
+	ModelWeightsRef *ObjectRef `protobuf:"bytes,10,opt,name=model_weights_ref,json=modelWeightsRef,proto3" json:"model_weights_ref,omitempty"`
+	// What the task used of its resource limits, if the worker enforced any.
+	ResourceUsage *TaskResourceUsage `protobuf:"bytes,11,opt,name=resource_usage,json=resourceUsage,proto3" json:"resource_usage,omitempty"`
+	// The attempt the report is for, as given with the assignment.
+	Attempt       int32 `protobuf:"varint,12,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskCompletionRequest) Reset() {
+	*x = TaskCompletionRequest{}
+	mi := &file_orchestrator_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskCompletionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskCompletionRequest) ProtoMessage() {}
+
+func (x *TaskCompletionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskCompletionRequest.ProtoReflect.Descriptor instead.
+func (*TaskCompletionRequest) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *TaskCompletionRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *TaskCompletionRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *TaskCompletionRequest) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *TaskCompletionRequest) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *TaskCompletionRequest) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+func (x *TaskCompletionRequest) GetLoss() float64 {
+	if x != nil {
+		return x.Loss
+	}
+	return 0
+}
+
+func (x *TaskCompletionRequest) GetAccuracy() float64 {
+	if x != nil {
+		return x.Accuracy
+	}
+	return 0
+}
+
+func (x *TaskCompletionRequest) GetModelWeights() []byte {
+	if x != nil {
+		return x.ModelWeights
+	}
+	return nil
+}
+
+func (x *TaskCompletionRequest) GetDatasetCache() DatasetCacheResult {
+	if x != nil {
+		return x.DatasetCache
+	}
+	return DatasetCacheResult_DATASET_CACHE_UNSPECIFIED
+}
+
+func (x *TaskCompletionRequest) GetModelWeightsRef() *ObjectRef {
+	if x != nil {
+		return x.ModelWeightsRef
+	}
+	return nil
+}
+
+func (x *TaskCompletionRequest) GetResourceUsage() *TaskResourceUsage {
+	if x != nil {
+		return x.ResourceUsage
+	}
+	return nil
+}
+
+func (x *TaskCompletionRequest) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+// Resources a task used under the limits its worker ran it with.
+type TaskResourceUsage struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Limits the task ran under; 0 for none.
+	CpuLimitCores    float64 `protobuf:"fixed64,1,opt,name=cpu_limit_cores,json=cpuLimitCores,proto3" json:"cpu_limit_cores,omitempty"`
+	MemoryLimitBytes int64   `protobuf:"varint,2,opt,name=memory_limit_bytes,json=memoryLimitBytes,proto3" json:"memory_limit_bytes,omitempty"`
+	PeakMemoryBytes  int64   `protobuf:"varint,3,opt,name=peak_memory_bytes,json=peakMemoryBytes,proto3" json:"peak_memory_bytes,omitempty"`
+	CpuSeconds       float64 `protobuf:"fixed64,4,opt,name=cpu_seconds,json=cpuSeconds,proto3" json:"cpu_seconds,omitempty"`
+	// Time the task was held back by its CPU limit.
+	CpuThrottledSeconds float64 `protobuf:"fixed64,5,opt,name=cpu_throttled_seconds,json=cpuThrottledSeconds,proto3" json:"cpu_throttled_seconds,omitempty"`
+	// The task was killed for exceeding its memory limit.
+	OomKilled     bool `protobuf:"varint,6,opt,name=oom_killed,json=oomKilled,proto3" json:"oom_killed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskResourceUsage) Reset() {
+	*x = TaskResourceUsage{}
+	mi := &file_orchestrator_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskResourceUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskResourceUsage) ProtoMessage() {}
+
+func (x *TaskResourceUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskResourceUsage.ProtoReflect.Descriptor instead.
+func (*TaskResourceUsage) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *TaskResourceUsage) GetCpuLimitCores() float64 {
+	if x != nil {
+		return x.CpuLimitCores
+	}
+	return 0
+}
+
+func (x *TaskResourceUsage) GetMemoryLimitBytes() int64 {
+	if x != nil {
+		return x.MemoryLimitBytes
+	}
+	return 0
+}
+
+func (x *TaskResourceUsage) GetPeakMemoryBytes() int64 {
+	if x != nil {
+		return x.PeakMemoryBytes
+	}
+	return 0
+}
+
+func (x *TaskResourceUsage) GetCpuSeconds() float64 {
+	if x != nil {
+		return x.CpuSeconds
+	}
+	return 0
+}
+
+func (x *TaskResourceUsage) GetCpuThrottledSeconds() float64 {
+	if x != nil {
+		return x.CpuThrottledSeconds
+	}
+	return 0
+}
+
+func (x *TaskResourceUsage) GetOomKilled() bool {
+	if x != nil {
+		return x.OomKilled
+	}
+	return false
+}
+
+// An object in the storage service's buckets.
+type ObjectRef struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Bucket string                 `protobuf:"bytes,1,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	Key    string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	// Hex SHA-256 of the object's content.
+	Sha256        string `protobuf:"bytes,3,opt,name=sha256,proto3" json:"sha256,omitempty"`
+	SizeBytes     int64  `protobuf:"varint,4,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ObjectRef) Reset() {
+	*x = ObjectRef{}
+	mi := &file_orchestrator_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ObjectRef) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ObjectRef) ProtoMessage() {}
+
+func (x *ObjectRef) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ObjectRef.ProtoReflect.Descriptor instead.
+func (*ObjectRef) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ObjectRef) GetBucket() string {
+	if x != nil {
+		return x.Bucket
+	}
+	return ""
+}
+
+func (x *ObjectRef) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *ObjectRef) GetSha256() string {
+	if x != nil {
+		return x.Sha256
+	}
+	return ""
+}
+
+func (x *ObjectRef) GetSizeBytes() int64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+type TaskCompletionResponse struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Acknowledged bool                   `protobuf:"varint,1,opt,name=acknowledged,proto3" json:"acknowledged,omitempty"`
+	Message      string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// The report changed nothing: the task had already been settled, or the
+	// reported failure was of an attempt the task has moved on from.
+	Duplicate     bool `protobuf:"varint,3,opt,name=duplicate,proto3" json:"duplicate,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskCompletionResponse) Reset() {
+	*x = TaskCompletionResponse{}
+	mi := &file_orchestrator_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskCompletionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskCompletionResponse) ProtoMessage() {}
+
+func (x *TaskCompletionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskCompletionResponse.ProtoReflect.Descriptor instead.
+func (*TaskCompletionResponse) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *TaskCompletionResponse) GetAcknowledged() bool {
+	if x != nil {
+		return x.Acknowledged
+	}
+	return false
+}
+
+func (x *TaskCompletionResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *TaskCompletionResponse) GetDuplicate() bool {
+	if x != nil {
+		return x.Duplicate
+	}
+	return false
+}
+
+type TaskProgressRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	TaskId       string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	JobId        string                 `protobuf:"bytes,2,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	WorkerId     string                 `protobuf:"bytes,3,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	BatchesDone  int32                  `protobuf:"varint,4,opt,name=batches_done,json=batchesDone,proto3" json:"batches_done,omitempty"`
+	BatchesTotal int32                  `protobuf:"varint,5,opt,name=batches_total,json=batchesTotal,proto3" json:"batches_total,omitempty"`
+	Loss         float64                `protobuf:"fixed64,6,opt,name=loss,proto3" json:"loss,omitempty"`
+	Accuracy     float64                `protobuf:"fixed64,7,opt,name=accuracy,proto3" json:"accuracy,omitempty"`
+	// The worker has just started a task it held prefetched. The task's lease
+	// and duration start over, so time spent waiting for a slot counts
+	// against neither.
+	Started       bool `protobuf:"varint,8,opt,name=started,proto3" json:"started,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskProgressRequest) Reset() {
+	*x = TaskProgressRequest{}
+	mi := &file_orchestrator_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskProgressRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskProgressRequest) ProtoMessage() {}
+
+func (x *TaskProgressRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskProgressRequest.ProtoReflect.Descriptor instead.
+func (*TaskProgressRequest) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *TaskProgressRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *TaskProgressRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *TaskProgressRequest) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *TaskProgressRequest) GetBatchesDone() int32 {
+	if x != nil {
+		return x.BatchesDone
+	}
+	return 0
+}
+
+func (x *TaskProgressRequest) GetBatchesTotal() int32 {
+	if x != nil {
+		return x.BatchesTotal
+	}
+	return 0
+}
+
+func (x *TaskProgressRequest) GetLoss() float64 {
+	if x != nil {
+		return x.Loss
+	}
+	return 0
+}
+
+func (x *TaskProgressRequest) GetAccuracy() float64 {
+	if x != nil {
+		return x.Accuracy
+	}
+	return 0
+}
+
+func (x *TaskProgressRequest) GetStarted() bool {
+	if x != nil {
+		return x.Started
+	}
+	return false
+}
+
+type TaskProgressResponse struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Acknowledged bool                   `protobuf:"varint,1,opt,name=acknowledged,proto3" json:"acknowledged,omitempty"`
+	// The task is no longer assigned to the worker, which should stop it.
+	Stop          bool   `protobuf:"varint,2,opt,name=stop,proto3" json:"stop,omitempty"`
+	Message       string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskProgressResponse) Reset() {
+	*x = TaskProgressResponse{}
+	mi := &file_orchestrator_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskProgressResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskProgressResponse) ProtoMessage() {}
+
+func (x *TaskProgressResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskProgressResponse.ProtoReflect.Descriptor instead.
+func (*TaskProgressResponse) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *TaskProgressResponse) GetAcknowledged() bool {
+	if x != nil {
+		return x.Acknowledged
+	}
+	return false
+}
+
+func (x *TaskProgressResponse) GetStop() bool {
+	if x != nil {
+		return x.Stop
+	}
+	return false
+}
+
+func (x *TaskProgressResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// Latest progress a worker reported for a running task.
+type TaskProgress struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	WorkerId      string                 `protobuf:"bytes,2,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	BatchesDone   int32                  `protobuf:"varint,3,opt,name=batches_done,json=batchesDone,proto3" json:"batches_done,omitempty"`
+	BatchesTotal  int32                  `protobuf:"varint,4,opt,name=batches_total,json=batchesTotal,proto3" json:"batches_total,omitempty"`
+	Loss          float64                `protobuf:"fixed64,5,opt,name=loss,proto3" json:"loss,omitempty"`
+	Accuracy      float64                `protobuf:"fixed64,6,opt,name=accuracy,proto3" json:"accuracy,omitempty"`
+	ReportedAtMs  int64                  `protobuf:"varint,7,opt,name=reported_at_ms,json=reportedAtMs,proto3" json:"reported_at_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskProgress) Reset() {
+	*x = TaskProgress{}
+	mi := &file_orchestrator_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskProgress) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskProgress) ProtoMessage() {}
+
+func (x *TaskProgress) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskProgress.ProtoReflect.Descriptor instead.
+func (*TaskProgress) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *TaskProgress) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *TaskProgress) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *TaskProgress) GetBatchesDone() int32 {
+	if x != nil {
+		return x.BatchesDone
+	}
+	return 0
+}
+
+func (x *TaskProgress) GetBatchesTotal() int32 {
+	if x != nil {
+		return x.BatchesTotal
+	}
+	return 0
+}
+
+func (x *TaskProgress) GetLoss() float64 {
+	if x != nil {
+		return x.Loss
+	}
+	return 0
+}
+
+func (x *TaskProgress) GetAccuracy() float64 {
+	if x != nil {
+		return x.Accuracy
+	}
+	return 0
+}
+
+func (x *TaskProgress) GetReportedAtMs() int64 {
+	if x != nil {
+		return x.ReportedAtMs
+	}
+	return 0
+}
+
+type ReleaseTaskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	JobId         string                 `protobuf:"bytes,2,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	WorkerId      string                 `protobuf:"bytes,3,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseTaskRequest) Reset() {
+	*x = ReleaseTaskRequest{}
+	mi := &file_orchestrator_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseTaskRequest) ProtoMessage() {}
+
+func (x *ReleaseTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseTaskRequest.ProtoReflect.Descriptor instead.
+func (*ReleaseTaskRequest) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ReleaseTaskRequest) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *ReleaseTaskRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *ReleaseTaskRequest) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *ReleaseTaskRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type ReleaseTaskResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// False when the task was no longer assigned to the worker, in which case
+	// nothing changed.
+	Released      bool   `protobuf:"varint,1,opt,name=released,proto3" json:"released,omitempty"`
+	Message       string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseTaskResponse) Reset() {
+	*x = ReleaseTaskResponse{}
+	mi := &file_orchestrator_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseTaskResponse) ProtoMessage() {}
+
+func (x *ReleaseTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseTaskResponse.ProtoReflect.Descriptor instead.
+func (*ReleaseTaskResponse) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ReleaseTaskResponse) GetReleased() bool {
+	if x != nil {
+		return x.Released
+	}
+	return false
+}
+
+func (x *ReleaseTaskResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// One line of a task's training log.
+type TaskLogEntry struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	TaskId      string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	JobId       string                 `protobuf:"bytes,2,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	TimestampMs int64                  `protobuf:"varint,3,opt,name=timestamp_ms,json=timestampMs,proto3" json:"timestamp_ms,omitempty"`
+	// "DEBUG", "INFO", "WARN" or "ERROR".
+	Level   string `protobuf:"bytes,4,opt,name=level,proto3" json:"level,omitempty"`
+	Message string `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`
+	// Structured values, e.g. "batch", "loss" and "accuracy" for a batch.
+	Fields        map[string]string `protobuf:"bytes,6,rep,name=fields,proto3" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TaskLogEntry) Reset() {
+	*x = TaskLogEntry{}
+	mi := &file_orchestrator_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TaskLogEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TaskLogEntry) ProtoMessage() {}
+
+func (x *TaskLogEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TaskLogEntry.ProtoReflect.Descriptor instead.
+func (*TaskLogEntry) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *TaskLogEntry) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *TaskLogEntry) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *TaskLogEntry) GetTimestampMs() int64 {
+	if x != nil {
+		return x.TimestampMs
+	}
+	return 0
+}
+
+func (x *TaskLogEntry) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+func (x *TaskLogEntry) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *TaskLogEntry) GetFields() map[string]string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+type ShipTaskLogsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkerId      string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	Entries       []*TaskLogEntry        `protobuf:"bytes,2,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShipTaskLogsRequest) Reset() {
+	*x = ShipTaskLogsRequest{}
+	mi := &file_orchestrator_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShipTaskLogsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShipTaskLogsRequest) ProtoMessage() {}
+
+func (x *ShipTaskLogsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShipTaskLogsRequest.ProtoReflect.Descriptor instead.
+func (*ShipTaskLogsRequest) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ShipTaskLogsRequest) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *ShipTaskLogsRequest) GetEntries() []*TaskLogEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type ShipTaskLogsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Entries stored; the others belonged to unknown jobs, or log shipping is
+	// disabled. Either way the worker need not send them again.
+	Accepted      int32 `protobuf:"varint,1,opt,name=accepted,proto3" json:"accepted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShipTaskLogsResponse) Reset() {
+	*x = ShipTaskLogsResponse{}
+	mi := &file_orchestrator_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShipTaskLogsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShipTaskLogsResponse) ProtoMessage() {}
+
+func (x *ShipTaskLogsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShipTaskLogsResponse.ProtoReflect.Descriptor instead.
+func (*ShipTaskLogsResponse) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ShipTaskLogsResponse) GetAccepted() int32 {
+	if x != nil {
+		return x.Accepted
+	}
+	return 0
+}
+
+type JobMetricsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Epoch         int32                  `protobuf:"varint,2,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Loss          float64                `protobuf:"fixed64,3,opt,name=loss,proto3" json:"loss,omitempty"`
+	Accuracy      float64                `protobuf:"fixed64,4,opt,name=accuracy,proto3" json:"accuracy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobMetricsRequest) Reset() {
+	*x = JobMetricsRequest{}
+	mi := &file_orchestrator_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobMetricsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobMetricsRequest) ProtoMessage() {}
+
+func (x *JobMetricsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobMetricsRequest.ProtoReflect.Descriptor instead.
+func (*JobMetricsRequest) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *JobMetricsRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *JobMetricsRequest) GetEpoch() int32 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+func (x *JobMetricsRequest) GetLoss() float64 {
+	if x != nil {
+		return x.Loss
+	}
+	return 0
+}
+
+func (x *JobMetricsRequest) GetAccuracy() float64 {
+	if x != nil {
+		return x.Accuracy
+	}
+	return 0
+}
+
+type JobMetricsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobMetricsResponse) Reset() {
+	*x = JobMetricsResponse{}
+	mi := &file_orchestrator_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobMetricsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobMetricsResponse) ProtoMessage() {}
+
+func (x *JobMetricsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobMetricsResponse.ProtoReflect.Descriptor instead.
+func (*JobMetricsResponse) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *JobMetricsResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type JobMetricsHistoryRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	JobId string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	// Optional filter: "task", "epoch" or "validation" samples only.
+	Kind string `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	// Only return samples recorded at or after this time.
+	SinceUnixMs   int64 `protobuf:"varint,3,opt,name=since_unix_ms,json=sinceUnixMs,proto3" json:"since_unix_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobMetricsHistoryRequest) Reset() {
+	*x = JobMetricsHistoryRequest{}
+	mi := &file_orchestrator_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobMetricsHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobMetricsHistoryRequest) ProtoMessage() {}
+
+func (x *JobMetricsHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobMetricsHistoryRequest.ProtoReflect.Descriptor instead.
+func (*JobMetricsHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *JobMetricsHistoryRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *JobMetricsHistoryRequest) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *JobMetricsHistoryRequest) GetSinceUnixMs() int64 {
+	if x != nil {
+		return x.SinceUnixMs
+	}
+	return 0
+}
+
+type JobMetricsHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Samples       []*MetricSample        `protobuf:"bytes,2,rep,name=samples,proto3" json:"samples,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobMetricsHistoryResponse) Reset() {
+	*x = JobMetricsHistoryResponse{}
+	mi := &file_orchestrator_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobMetricsHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobMetricsHistoryResponse) ProtoMessage() {}
+
+func (x *JobMetricsHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobMetricsHistoryResponse.ProtoReflect.Descriptor instead.
+func (*JobMetricsHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *JobMetricsHistoryResponse) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *JobMetricsHistoryResponse) GetSamples() []*MetricSample {
+	if x != nil {
+		return x.Samples
+	}
+	return nil
+}
+
+// One point of a job's training curve: a task completion or an epoch
+// aggregate.
+type MetricSample struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kind          string                 `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	Epoch         int32                  `protobuf:"varint,2,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	TaskId        string                 `protobuf:"bytes,3,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Loss          float64                `protobuf:"fixed64,4,opt,name=loss,proto3" json:"loss,omitempty"`
+	Accuracy      float64                `protobuf:"fixed64,5,opt,name=accuracy,proto3" json:"accuracy,omitempty"`
+	TimestampMs   int64                  `protobuf:"varint,6,opt,name=timestamp_ms,json=timestampMs,proto3" json:"timestamp_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MetricSample) Reset() {
+	*x = MetricSample{}
+	mi := &file_orchestrator_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MetricSample) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetricSample) ProtoMessage() {}
+
+func (x *MetricSample) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetricSample.ProtoReflect.Descriptor instead.
+func (*MetricSample) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *MetricSample) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *MetricSample) GetEpoch() int32 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+func (x *MetricSample) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *MetricSample) GetLoss() float64 {
+	if x != nil {
+		return x.Loss
+	}
+	return 0
+}
+
+func (x *MetricSample) GetAccuracy() float64 {
+	if x != nil {
+		return x.Accuracy
+	}
+	return 0
+}
+
+func (x *MetricSample) GetTimestampMs() int64 {
+	if x != nil {
+		return x.TimestampMs
+	}
+	return 0
+}
+
+type JobEventsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	JobId string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	// Optional filter on the event type, e.g. "TASK_RETRIED".
+	Type          string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobEventsRequest) Reset() {
+	*x = JobEventsRequest{}
+	mi := &file_orchestrator_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobEventsRequest) ProtoMessage() {}
+
+func (x *JobEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobEventsRequest.ProtoReflect.Descriptor instead.
+func (*JobEventsRequest) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *JobEventsRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *JobEventsRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+type JobEventsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	JobId string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	// Events in the order they happened.
+	Events        []*JobEvent `protobuf:"bytes,2,rep,name=events,proto3" json:"events,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobEventsResponse) Reset() {
+	*x = JobEventsResponse{}
+	mi := &file_orchestrator_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobEventsResponse) ProtoMessage() {}
+
+func (x *JobEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobEventsResponse.ProtoReflect.Descriptor instead.
+func (*JobEventsResponse) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *JobEventsResponse) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *JobEventsResponse) GetEvents() []*JobEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+type JobEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	TaskId        string                 `protobuf:"bytes,3,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	WorkerId      string                 `protobuf:"bytes,4,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	TimestampMs   int64                  `protobuf:"varint,5,opt,name=timestamp_ms,json=timestampMs,proto3" json:"timestamp_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JobEvent) Reset() {
+	*x = JobEvent{}
+	mi := &file_orchestrator_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JobEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JobEvent) ProtoMessage() {}
+
+func (x *JobEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JobEvent.ProtoReflect.Descriptor instead.
+func (*JobEvent) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *JobEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *JobEvent) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *JobEvent) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+func (x *JobEvent) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *JobEvent) GetTimestampMs() int64 {
+	if x != nil {
+		return x.TimestampMs
+	}
+	return 0
+}
+
+type CancelJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelJobRequest) Reset() {
+	*x = CancelJobRequest{}
+	mi := &file_orchestrator_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelJobRequest) ProtoMessage() {}
+
+func (x *CancelJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelJobRequest.ProtoReflect.Descriptor instead.
+func (*CancelJobRequest) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *CancelJobRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type CancelJobResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Success        bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message        string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	PreviousStatus string                 `protobuf:"bytes,3,opt,name=previous_status,json=previousStatus,proto3" json:"previous_status,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CancelJobResponse) Reset() {
+	*x = CancelJobResponse{}
+	mi := &file_orchestrator_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelJobResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelJobResponse) ProtoMessage() {}
+
+func (x *CancelJobResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelJobResponse.ProtoReflect.Descriptor instead.
+func (*CancelJobResponse) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *CancelJobResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *CancelJobResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *CancelJobResponse) GetPreviousStatus() string {
+	if x != nil {
+		return x.PreviousStatus
+	}
+	return ""
+}
+
+type PauseJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PauseJobRequest) Reset() {
+	*x = PauseJobRequest{}
+	mi := &file_orchestrator_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PauseJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseJobRequest) ProtoMessage() {}
+
+func (x *PauseJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseJobRequest.ProtoReflect.Descriptor instead.
+func (*PauseJobRequest) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *PauseJobRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type PauseJobResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Success        bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message        string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	PreviousStatus string                 `protobuf:"bytes,3,opt,name=previous_status,json=previousStatus,proto3" json:"previous_status,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *PauseJobResponse) Reset() {
+	*x = PauseJobResponse{}
+	mi := &file_orchestrator_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PauseJobResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseJobResponse) ProtoMessage() {}
+
+func (x *PauseJobResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseJobResponse.ProtoReflect.Descriptor instead.
+func (*PauseJobResponse) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *PauseJobResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *PauseJobResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *PauseJobResponse) GetPreviousStatus() string {
+	if x != nil {
+		return x.PreviousStatus
+	}
+	return ""
+}
+
+type ResumeJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResumeJobRequest) Reset() {
+	*x = ResumeJobRequest{}
+	mi := &file_orchestrator_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeJobRequest) ProtoMessage() {}
+
+func (x *ResumeJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeJobRequest.ProtoReflect.Descriptor instead.
+func (*ResumeJobRequest) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *ResumeJobRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+type ResumeJobResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Success        bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message        string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	PreviousStatus string                 `protobuf:"bytes,3,opt,name=previous_status,json=previousStatus,proto3" json:"previous_status,omitempty"`
+	// First epoch that will be rerun.
+	ResumeEpoch   int32 `protobuf:"varint,4,opt,name=resume_epoch,json=resumeEpoch,proto3" json:"resume_epoch,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResumeJobResponse) Reset() {
+	*x = ResumeJobResponse{}
+	mi := &file_orchestrator_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeJobResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeJobResponse) ProtoMessage() {}
+
+func (x *ResumeJobResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeJobResponse.ProtoReflect.Descriptor instead.
+func (*ResumeJobResponse) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *ResumeJobResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ResumeJobResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ResumeJobResponse) GetPreviousStatus() string {
+	if x != nil {
+		return x.PreviousStatus
+	}
+	return ""
+}
+
+func (x *ResumeJobResponse) GetResumeEpoch() int32 {
+	if x != nil {
+		return x.ResumeEpoch
+	}
+	return 0
+}
+
+type WorkerActivityRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkerActivityRequest) Reset() {
+	*x = WorkerActivityRequest{}
+	mi := &file_orchestrator_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkerActivityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkerActivityRequest) ProtoMessage() {}
+
+func (x *WorkerActivityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkerActivityRequest.ProtoReflect.Descriptor instead.
+func (*WorkerActivityRequest) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{47}
+}
+
+type WorkerActivityResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Workers       []*WorkerInfo          `protobuf:"bytes,1,rep,name=workers,proto3" json:"workers,omitempty"`
+	TotalWorkers  int32                  `protobuf:"varint,2,opt,name=total_workers,json=totalWorkers,proto3" json:"total_workers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkerActivityResponse) Reset() {
+	*x = WorkerActivityResponse{}
+	mi := &file_orchestrator_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkerActivityResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkerActivityResponse) ProtoMessage() {}
+
+func (x *WorkerActivityResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkerActivityResponse.ProtoReflect.Descriptor instead.
+func (*WorkerActivityResponse) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *WorkerActivityResponse) GetWorkers() []*WorkerInfo {
+	if x != nil {
+		return x.Workers
+	}
+	return nil
+}
+
+func (x *WorkerActivityResponse) GetTotalWorkers() int32 {
+	if x != nil {
+		return x.TotalWorkers
+	}
+	return 0
+}
+
+type WorkerInfo struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	WorkerId           string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	Status             string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	CurrentTaskId      string                 `protobuf:"bytes,3,opt,name=current_task_id,json=currentTaskId,proto3" json:"current_task_id,omitempty"`
+	CurrentJobId       string                 `protobuf:"bytes,4,opt,name=current_job_id,json=currentJobId,proto3" json:"current_job_id,omitempty"`
+	TasksCompleted     int32                  `protobuf:"varint,5,opt,name=tasks_completed,json=tasksCompleted,proto3" json:"tasks_completed,omitempty"`
+	LastActivityTime   int64                  `protobuf:"varint,6,opt,name=last_activity_time,json=lastActivityTime,proto3" json:"last_activity_time,omitempty"`
+	Hostname           string                 `protobuf:"bytes,7,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	Address            string                 `protobuf:"bytes,8,opt,name=address,proto3" json:"address,omitempty"`
+	LastHeartbeatTime  int64                  `protobuf:"varint,9,opt,name=last_heartbeat_time,json=lastHeartbeatTime,proto3" json:"last_heartbeat_time,omitempty"`
+	RegisteredAt       int64                  `protobuf:"varint,10,opt,name=registered_at,json=registeredAt,proto3" json:"registered_at,omitempty"`
+	Capabilities       *WorkerCapabilities    `protobuf:"bytes,11,opt,name=capabilities,proto3" json:"capabilities,omitempty"`
+	CachedDatasets     []string               `protobuf:"bytes,12,rep,name=cached_datasets,json=cachedDatasets,proto3" json:"cached_datasets,omitempty"`
+	QuarantineReason   string                 `protobuf:"bytes,13,opt,name=quarantine_reason,json=quarantineReason,proto3" json:"quarantine_reason,omitempty"`
+	MaxConcurrentTasks int32                  `protobuf:"varint,14,opt,name=max_concurrent_tasks,json=maxConcurrentTasks,proto3" json:"max_concurrent_tasks,omitempty"`
+	InFlightTasks      int32                  `protobuf:"varint,15,opt,name=in_flight_tasks,json=inFlightTasks,proto3" json:"in_flight_tasks,omitempty"`
+	TaskDurations      *TaskDurationStats     `protobuf:"bytes,16,opt,name=task_durations,json=taskDurations,proto3" json:"task_durations,omitempty"`
+	// Latest load the worker reported; unset before its first heartbeat.
+	Load             *WorkerLoad `protobuf:"bytes,17,opt,name=load,proto3" json:"load,omitempty"`
+	LoadReportedAtMs int64       `protobuf:"varint,18,opt,name=load_reported_at_ms,json=loadReportedAtMs,proto3" json:"load_reported_at_ms,omitempty"`
+	// Seconds since the worker process started, or since it registered if
+	// it did not say.
+	UptimeSeconds int64 `protobuf:"varint,19,opt,name=uptime_seconds,json=uptimeSeconds,proto3" json:"uptime_seconds,omitempty"`
+	// Completed tasks whose dataset the worker had cached, or downloaded.
+	DatasetCacheHits   int32  `protobuf:"varint,20,opt,name=dataset_cache_hits,json=datasetCacheHits,proto3" json:"dataset_cache_hits,omitempty"`
+	DatasetCacheMisses int32  `protobuf:"varint,21,opt,name=dataset_cache_misses,json=datasetCacheMisses,proto3" json:"dataset_cache_misses,omitempty"`
+	Executor           string `protobuf:"bytes,22,opt,name=executor,proto3" json:"executor,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *WorkerInfo) Reset() {
+	*x = WorkerInfo{}
+	mi := &file_orchestrator_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkerInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkerInfo) ProtoMessage() {}
+
+func (x *WorkerInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkerInfo.ProtoReflect.Descriptor instead.
+func (*WorkerInfo) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *WorkerInfo) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *WorkerInfo) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *WorkerInfo) GetCurrentTaskId() string {
+	if x != nil {
+		return x.CurrentTaskId
+	}
+	return ""
+}
+
+func (x *WorkerInfo) GetCurrentJobId() string {
+	if x != nil {
+		return x.CurrentJobId
+	}
+	return ""
+}
+
+func (x *WorkerInfo) GetTasksCompleted() int32 {
+	if x != nil {
+		return x.TasksCompleted
+	}
+	return 0
+}
+
+func (x *WorkerInfo) GetLastActivityTime() int64 {
+	if x != nil {
+		return x.LastActivityTime
+	}
+	return 0
+}
+
+func (x *WorkerInfo) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *WorkerInfo) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *WorkerInfo) GetLastHeartbeatTime() int64 {
+	if x != nil {
+		return x.LastHeartbeatTime
+	}
+	return 0
+}
+
+func (x *WorkerInfo) GetRegisteredAt() int64 {
+	if x != nil {
+		return x.RegisteredAt
+	}
+	return 0
+}
+
+func (x *WorkerInfo) GetCapabilities() *WorkerCapabilities {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
+func (x *WorkerInfo) GetCachedDatasets() []string {
+	if x != nil {
+		return x.CachedDatasets
+	}
+	return nil
+}
+
+func (x *WorkerInfo) GetQuarantineReason() string {
+	if x != nil {
+		return x.QuarantineReason
+	}
+	return ""
+}
+
+func (x *WorkerInfo) GetMaxConcurrentTasks() int32 {
+	if x != nil {
+		return x.MaxConcurrentTasks
+	}
+	return 0
+}
+
+func (x *WorkerInfo) GetInFlightTasks() int32 {
+	if x != nil {
+		return x.InFlightTasks
+	}
+	return 0
+}
+
+func (x *WorkerInfo) GetTaskDurations() *TaskDurationStats {
+	if x != nil {
+		return x.TaskDurations
+	}
+	return nil
+}
+
+func (x *WorkerInfo) GetLoad() *WorkerLoad {
+	if x != nil {
+		return x.Load
+	}
+	return nil
+}
+
+func (x *WorkerInfo) GetLoadReportedAtMs() int64 {
+	if x != nil {
+		return x.LoadReportedAtMs
+	}
+	return 0
+}
+
+func (x *WorkerInfo) GetUptimeSeconds() int64 {
+	if x != nil {
+		return x.UptimeSeconds
+	}
+	return 0
+}
+
+func (x *WorkerInfo) GetDatasetCacheHits() int32 {
+	if x != nil {
+		return x.DatasetCacheHits
+	}
+	return 0
+}
+
+func (x *WorkerInfo) GetDatasetCacheMisses() int32 {
+	if x != nil {
+		return x.DatasetCacheMisses
+	}
+	return 0
+}
+
+func (x *WorkerInfo) GetExecutor() string {
+	if x != nil {
+		return x.Executor
+	}
+	return ""
+}
+
+type WorkerCapabilities struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	CpuCores int32                  `protobuf:"varint,1,opt,name=cpu_cores,json=cpuCores,proto3" json:"cpu_cores,omitempty"`
+	MemoryMb int64                  `protobuf:"varint,2,opt,name=memory_mb,json=memoryMb,proto3" json:"memory_mb,omitempty"`
+	GpuCount int32                  `protobuf:"varint,3,opt,name=gpu_count,json=gpuCount,proto3" json:"gpu_count,omitempty"`
+	GpuType  string                 `protobuf:"bytes,4,opt,name=gpu_type,json=gpuType,proto3" json:"gpu_type,omitempty"`
+	Labels   map[string]string      `protobuf:"bytes,5,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Task types the worker runs, e.g. "preprocess"; empty for all of them.
+	TaskTypes     []string `protobuf:"bytes,6,rep,name=task_types,json=taskTypes,proto3" json:"task_types,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WorkerCapabilities) Reset() {
+	*x = WorkerCapabilities{}
+	mi := &file_orchestrator_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkerCapabilities) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkerCapabilities) ProtoMessage() {}
+
+func (x *WorkerCapabilities) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkerCapabilities.ProtoReflect.Descriptor instead.
+func (*WorkerCapabilities) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *WorkerCapabilities) GetCpuCores() int32 {
+	if x != nil {
+		return x.CpuCores
+	}
+	return 0
+}
+
+func (x *WorkerCapabilities) GetMemoryMb() int64 {
+	if x != nil {
+		return x.MemoryMb
+	}
+	return 0
+}
+
+func (x *WorkerCapabilities) GetGpuCount() int32 {
+	if x != nil {
+		return x.GpuCount
+	}
+	return 0
+}
+
+func (x *WorkerCapabilities) GetGpuType() string {
+	if x != nil {
+		return x.GpuType
+	}
+	return ""
+}
+
+func (x *WorkerCapabilities) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *WorkerCapabilities) GetTaskTypes() []string {
+	if x != nil {
+		return x.TaskTypes
+	}
+	return nil
+}
+
+type RegisterWorkerRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	WorkerId     string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	Hostname     string                 `protobuf:"bytes,2,opt,name=hostname,proto3" json:"hostname,omitempty"`
+	Address      string                 `protobuf:"bytes,3,opt,name=address,proto3" json:"address,omitempty"`
+	Capabilities *WorkerCapabilities    `protobuf:"bytes,4,opt,name=capabilities,proto3" json:"capabilities,omitempty"`
+	// Dataset paths already present in the worker's local cache.
+	CachedDatasets []string `protobuf:"bytes,5,rep,name=cached_datasets,json=cachedDatasets,proto3" json:"cached_datasets,omitempty"`
+	// Tasks the worker runs at once; the orchestrator hands out no more.
+	// Unset uses the orchestrator's default.
+	MaxConcurrentTasks int32 `protobuf:"varint,6,opt,name=max_concurrent_tasks,json=maxConcurrentTasks,proto3" json:"max_concurrent_tasks,omitempty"`
+	// When the worker process started, for uptime.
+	StartedAtMs int64 `protobuf:"varint,7,opt,name=started_at_ms,json=startedAtMs,proto3" json:"started_at_ms,omitempty"`
+	// Training backend the worker runs tasks with, e.g. "docker". Workers
+	// also carry it as the "executor" label, so jobs can require one.
+	Executor string `protobuf:"bytes,8,opt,name=executor,proto3" json:"executor,omitempty"`
+	// Tasks the worker asks to hold beyond max_concurrent_tasks, waiting for
+	// a slot, so that it starts the next task as soon as one finishes.
+	PrefetchTasks int32 `protobuf:"varint,9,opt,name=prefetch_tasks,json=prefetchTasks,proto3" json:"prefetch_tasks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterWorkerRequest) Reset() {
+	*x = RegisterWorkerRequest{}
+	mi := &file_orchestrator_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterWorkerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterWorkerRequest) ProtoMessage() {}
+
+func (x *RegisterWorkerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterWorkerRequest.ProtoReflect.Descriptor instead.
+func (*RegisterWorkerRequest) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *RegisterWorkerRequest) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *RegisterWorkerRequest) GetHostname() string {
+	if x != nil {
+		return x.Hostname
+	}
+	return ""
+}
+
+func (x *RegisterWorkerRequest) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *RegisterWorkerRequest) GetCapabilities() *WorkerCapabilities {
+	if x != nil {
+		return x.Capabilities
+	}
+	return nil
+}
+
+func (x *RegisterWorkerRequest) GetCachedDatasets() []string {
+	if x != nil {
+		return x.CachedDatasets
+	}
+	return nil
+}
+
+func (x *RegisterWorkerRequest) GetMaxConcurrentTasks() int32 {
+	if x != nil {
+		return x.MaxConcurrentTasks
+	}
+	return 0
+}
+
+func (x *RegisterWorkerRequest) GetStartedAtMs() int64 {
+	if x != nil {
+		return x.StartedAtMs
+	}
+	return 0
+}
+
+func (x *RegisterWorkerRequest) GetExecutor() string {
+	if x != nil {
+		return x.Executor
+	}
+	return ""
+}
+
+func (x *RegisterWorkerRequest) GetPrefetchTasks() int32 {
+	if x != nil {
+		return x.PrefetchTasks
+	}
+	return 0
+}
+
+type RegisterWorkerResponse struct {
+	state                    protoimpl.MessageState `protogen:"open.v1"`
+	Success                  bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message                  string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	HeartbeatIntervalSeconds int32                  `protobuf:"varint,3,opt,name=heartbeat_interval_seconds,json=heartbeatIntervalSeconds,proto3" json:"heartbeat_interval_seconds,omitempty"`
+	// Sent back as x-worker-id / x-worker-session metadata on AssignTask,
+	// StreamTasks and ReportTaskCompletion. Each registration issues a new
+	// one.
+	SessionToken string `protobuf:"bytes,4,opt,name=session_token,json=sessionToken,proto3" json:"session_token,omitempty"`
+	// How many of the requested prefetch_tasks the orchestrator allows.
+	PrefetchTasks int32 `protobuf:"varint,5,opt,name=prefetch_tasks,json=prefetchTasks,proto3" json:"prefetch_tasks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterWorkerResponse) Reset() {
+	*x = RegisterWorkerResponse{}
+	mi := &file_orchestrator_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterWorkerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterWorkerResponse) ProtoMessage() {}
+
+func (x *RegisterWorkerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterWorkerResponse.ProtoReflect.Descriptor instead.
+func (*RegisterWorkerResponse) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *RegisterWorkerResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *RegisterWorkerResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *RegisterWorkerResponse) GetHeartbeatIntervalSeconds() int32 {
+	if x != nil {
+		return x.HeartbeatIntervalSeconds
+	}
+	return 0
+}
+
+func (x *RegisterWorkerResponse) GetSessionToken() string {
+	if x != nil {
+		return x.SessionToken
+	}
+	return ""
+}
+
+func (x *RegisterWorkerResponse) GetPrefetchTasks() int32 {
+	if x != nil {
+		return x.PrefetchTasks
+	}
+	return 0
+}
+
+type DeregisterWorkerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkerId      string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeregisterWorkerRequest) Reset() {
+	*x = DeregisterWorkerRequest{}
+	mi := &file_orchestrator_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeregisterWorkerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeregisterWorkerRequest) ProtoMessage() {}
+
+func (x *DeregisterWorkerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeregisterWorkerRequest.ProtoReflect.Descriptor instead.
+func (*DeregisterWorkerRequest) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *DeregisterWorkerRequest) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *DeregisterWorkerRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type DeregisterWorkerResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// False when the orchestrator did not know the worker.
+	Deregistered  bool   `protobuf:"varint,1,opt,name=deregistered,proto3" json:"deregistered,omitempty"`
+	Message       string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	ReleasedTasks int32  `protobuf:"varint,3,opt,name=released_tasks,json=releasedTasks,proto3" json:"released_tasks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeregisterWorkerResponse) Reset() {
+	*x = DeregisterWorkerResponse{}
+	mi := &file_orchestrator_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeregisterWorkerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeregisterWorkerResponse) ProtoMessage() {}
+
+func (x *DeregisterWorkerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeregisterWorkerResponse.ProtoReflect.Descriptor instead.
+func (*DeregisterWorkerResponse) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *DeregisterWorkerResponse) GetDeregistered() bool {
+	if x != nil {
+		return x.Deregistered
+	}
+	return false
+}
+
+func (x *DeregisterWorkerResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *DeregisterWorkerResponse) GetReleasedTasks() int32 {
+	if x != nil {
+		return x.ReleasedTasks
+	}
+	return 0
+}
+
+type HeartbeatRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	WorkerId     string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	Status       string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	CurrentTasks int32                  `protobuf:"varint,3,opt,name=current_tasks,json=currentTasks,proto3" json:"current_tasks,omitempty"`
+	// Full set of dataset paths currently cached; replaces the previous report.
+	CachedDatasets []string    `protobuf:"bytes,4,rep,name=cached_datasets,json=cachedDatasets,proto3" json:"cached_datasets,omitempty"`
+	Load           *WorkerLoad `protobuf:"bytes,5,opt,name=load,proto3" json:"load,omitempty"`
+	// When the worker process started, for uptime.
+	StartedAtMs   int64 `protobuf:"varint,6,opt,name=started_at_ms,json=startedAtMs,proto3" json:"started_at_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HeartbeatRequest) Reset() {
+	*x = HeartbeatRequest{}
+	mi := &file_orchestrator_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeartbeatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeartbeatRequest) ProtoMessage() {}
+
+func (x *HeartbeatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeartbeatRequest.ProtoReflect.Descriptor instead.
+func (*HeartbeatRequest) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *HeartbeatRequest) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+func (x *HeartbeatRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *HeartbeatRequest) GetCurrentTasks() int32 {
+	if x != nil {
+		return x.CurrentTasks
+	}
+	return 0
+}
+
+func (x *HeartbeatRequest) GetCachedDatasets() []string {
+	if x != nil {
+		return x.CachedDatasets
+	}
+	return nil
+}
+
+func (x *HeartbeatRequest) GetLoad() *WorkerLoad {
+	if x != nil {
+		return x.Load
+	}
+	return nil
+}
+
+func (x *HeartbeatRequest) GetStartedAtMs() int64 {
+	if x != nil {
+		return x.StartedAtMs
+	}
+	return 0
+}
+
+// Host load a worker reports with each heartbeat. Zero fields are unknown.
+type WorkerLoad struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CpuPercent    float64                `protobuf:"fixed64,1,opt,name=cpu_percent,json=cpuPercent,proto3" json:"cpu_percent,omitempty"`
+	MemoryPercent float64                `protobuf:"fixed64,2,opt,name=memory_percent,json=memoryPercent,proto3" json:"memory_percent,omitempty"`
+	// One-minute load average.
+	LoadAverage      float64 `protobuf:"fixed64,3,opt,name=load_average,json=loadAverage,proto3" json:"load_average,omitempty"`
+	MemoryUsedBytes  uint64  `protobuf:"varint,4,opt,name=memory_used_bytes,json=memoryUsedBytes,proto3" json:"memory_used_bytes,omitempty"`
+	MemoryTotalBytes uint64  `protobuf:"varint,5,opt,name=memory_total_bytes,json=memoryTotalBytes,proto3" json:"memory_total_bytes,omitempty"`
+	// Of the file system holding the worker's data.
+	DiskPercent           float64    `protobuf:"fixed64,6,opt,name=disk_percent,json=diskPercent,proto3" json:"disk_percent,omitempty"`
+	DiskUsedBytes         uint64     `protobuf:"varint,7,opt,name=disk_used_bytes,json=diskUsedBytes,proto3" json:"disk_used_bytes,omitempty"`
+	DiskTotalBytes        uint64     `protobuf:"varint,8,opt,name=disk_total_bytes,json=diskTotalBytes,proto3" json:"disk_total_bytes,omitempty"`
+	NetSentBytesPerSecond float64    `protobuf:"fixed64,9,opt,name=net_sent_bytes_per_second,json=netSentBytesPerSecond,proto3" json:"net_sent_bytes_per_second,omitempty"`
+	NetRecvBytesPerSecond float64    `protobuf:"fixed64,10,opt,name=net_recv_bytes_per_second,json=netRecvBytesPerSecond,proto3" json:"net_recv_bytes_per_second,omitempty"`
+	Gpus                  []*GpuLoad `protobuf:"bytes,11,rep,name=gpus,proto3" json:"gpus,omitempty"`
+	// The host is busier than the worker's throttling thresholds allow, so
+	// it takes no new tasks until its use falls back; the reason names the
+	// resources over their limits.
+	Backpressure       bool   `protobuf:"varint,12,opt,name=backpressure,proto3" json:"backpressure,omitempty"`
+	BackpressureReason string `protobuf:"bytes,13,opt,name=backpressure_reason,json=backpressureReason,proto3" json:"backpressure_reason,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *WorkerLoad) Reset() {
+	*x = WorkerLoad{}
+	mi := &file_orchestrator_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WorkerLoad) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WorkerLoad) ProtoMessage() {}
+
+func (x *WorkerLoad) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WorkerLoad.ProtoReflect.Descriptor instead.
+func (*WorkerLoad) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *WorkerLoad) GetCpuPercent() float64 {
+	if x != nil {
+		return x.CpuPercent
+	}
+	return 0
+}
+
+func (x *WorkerLoad) GetMemoryPercent() float64 {
+	if x != nil {
+		return x.MemoryPercent
+	}
+	return 0
+}
+
+func (x *WorkerLoad) GetLoadAverage() float64 {
+	if x != nil {
+		return x.LoadAverage
+	}
+	return 0
+}
+
+func (x *WorkerLoad) GetMemoryUsedBytes() uint64 {
+	if x != nil {
+		return x.MemoryUsedBytes
+	}
+	return 0
+}
+
+func (x *WorkerLoad) GetMemoryTotalBytes() uint64 {
+	if x != nil {
+		return x.MemoryTotalBytes
+	}
+	return 0
+}
+
+func (x *WorkerLoad) GetDiskPercent() float64 {
+	if x != nil {
+		return x.DiskPercent
+	}
+	return 0
+}
+
+func (x *WorkerLoad) GetDiskUsedBytes() uint64 {
+	if x != nil {
+		return x.DiskUsedBytes
+	}
+	return 0
+}
+
+func (x *WorkerLoad) GetDiskTotalBytes() uint64 {
+	if x != nil {
+		return x.DiskTotalBytes
+	}
+	return 0
+}
+
+func (x *WorkerLoad) GetNetSentBytesPerSecond() float64 {
+	if x != nil {
+		return x.NetSentBytesPerSecond
+	}
+	return 0
+}
+
+func (x *WorkerLoad) GetNetRecvBytesPerSecond() float64 {
+	if x != nil {
+		return x.NetRecvBytesPerSecond
+	}
+	return 0
+}
+
+func (x *WorkerLoad) GetGpus() []*GpuLoad {
+	if x != nil {
+		return x.Gpus
+	}
+	return nil
+}
+
+func (x *WorkerLoad) GetBackpressure() bool {
+	if x != nil {
+		return x.Backpressure
+	}
+	return false
+}
+
+func (x *WorkerLoad) GetBackpressureReason() string {
+	if x != nil {
+		return x.BackpressureReason
+	}
+	return ""
+}
+
+// One of a worker's GPUs, as sampled through NVML.
+type GpuLoad struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Index              int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Uuid               string                 `protobuf:"bytes,2,opt,name=uuid,proto3" json:"uuid,omitempty"`
+	Name               string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	UtilizationPercent float64                `protobuf:"fixed64,4,opt,name=utilization_percent,json=utilizationPercent,proto3" json:"utilization_percent,omitempty"`
+	MemoryUsedBytes    uint64                 `protobuf:"varint,5,opt,name=memory_used_bytes,json=memoryUsedBytes,proto3" json:"memory_used_bytes,omitempty"`
+	MemoryTotalBytes   uint64                 `protobuf:"varint,6,opt,name=memory_total_bytes,json=memoryTotalBytes,proto3" json:"memory_total_bytes,omitempty"`
+	// Task the GPU is pinned to; empty while free.
+	TaskId        string `protobuf:"bytes,7,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GpuLoad) Reset() {
+	*x = GpuLoad{}
+	mi := &file_orchestrator_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GpuLoad) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GpuLoad) ProtoMessage() {}
+
+func (x *GpuLoad) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GpuLoad.ProtoReflect.Descriptor instead.
+func (*GpuLoad) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *GpuLoad) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *GpuLoad) GetUuid() string {
+	if x != nil {
+		return x.Uuid
+	}
+	return ""
+}
+
+func (x *GpuLoad) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *GpuLoad) GetUtilizationPercent() float64 {
+	if x != nil {
+		return x.UtilizationPercent
+	}
+	return 0
+}
+
+func (x *GpuLoad) GetMemoryUsedBytes() uint64 {
+	if x != nil {
+		return x.MemoryUsedBytes
+	}
+	return 0
+}
+
+func (x *GpuLoad) GetMemoryTotalBytes() uint64 {
+	if x != nil {
+		return x.MemoryTotalBytes
+	}
+	return 0
+}
+
+func (x *GpuLoad) GetTaskId() string {
+	if x != nil {
+		return x.TaskId
+	}
+	return ""
+}
+
+type HeartbeatResponse struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Acknowledged bool                   `protobuf:"varint,1,opt,name=acknowledged,proto3" json:"acknowledged,omitempty"`
+	// False when the orchestrator has no record of the worker (e.g. after an
+	// eviction or restart); the worker should call RegisterWorker again.
+	Registered    bool `protobuf:"varint,2,opt,name=registered,proto3" json:"registered,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HeartbeatResponse) Reset() {
+	*x = HeartbeatResponse{}
+	mi := &file_orchestrator_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HeartbeatResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HeartbeatResponse) ProtoMessage() {}
+
+func (x *HeartbeatResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HeartbeatResponse.ProtoReflect.Descriptor instead.
+func (*HeartbeatResponse) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *HeartbeatResponse) GetAcknowledged() bool {
+	if x != nil {
+		return x.Acknowledged
+	}
+	return false
+}
+
+func (x *HeartbeatResponse) GetRegistered() bool {
+	if x != nil {
+		return x.Registered
+	}
+	return false
+}
+
+type UnquarantineWorkerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WorkerId      string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnquarantineWorkerRequest) Reset() {
+	*x = UnquarantineWorkerRequest{}
+	mi := &file_orchestrator_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnquarantineWorkerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnquarantineWorkerRequest) ProtoMessage() {}
+
+func (x *UnquarantineWorkerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnquarantineWorkerRequest.ProtoReflect.Descriptor instead.
+func (*UnquarantineWorkerRequest) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *UnquarantineWorkerRequest) GetWorkerId() string {
+	if x != nil {
+		return x.WorkerId
+	}
+	return ""
+}
+
+type UnquarantineWorkerResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UnquarantineWorkerResponse) Reset() {
+	*x = UnquarantineWorkerResponse{}
+	mi := &file_orchestrator_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UnquarantineWorkerResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UnquarantineWorkerResponse) ProtoMessage() {}
+
+func (x *UnquarantineWorkerResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UnquarantineWorkerResponse.ProtoReflect.Descriptor instead.
+func (*UnquarantineWorkerResponse) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *UnquarantineWorkerResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *UnquarantineWorkerResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type DrainRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reason        string                 `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DrainRequest) Reset() {
+	*x = DrainRequest{}
+	mi := &file_orchestrator_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DrainRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DrainRequest) ProtoMessage() {}
+
+func (x *DrainRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DrainRequest.ProtoReflect.Descriptor instead.
+func (*DrainRequest) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *DrainRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type DrainResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Success bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// Tasks still running on workers when the drain started
+	InFlightTasks int32 `protobuf:"varint,3,opt,name=in_flight_tasks,json=inFlightTasks,proto3" json:"in_flight_tasks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DrainResponse) Reset() {
+	*x = DrainResponse{}
+	mi := &file_orchestrator_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DrainResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DrainResponse) ProtoMessage() {}
+
+func (x *DrainResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DrainResponse.ProtoReflect.Descriptor instead.
+func (*DrainResponse) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *DrainResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *DrainResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *DrainResponse) GetInFlightTasks() int32 {
+	if x != nil {
+		return x.InFlightTasks
+	}
+	return 0
+}
+
+type UserUsage struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserId string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// Worker time spent on the user's tasks, failed attempts included.
+	TaskHours      float64 `protobuf:"fixed64,2,opt,name=task_hours,json=taskHours,proto3" json:"task_hours,omitempty"`
+	TasksCompleted int32   `protobuf:"varint,3,opt,name=tasks_completed,json=tasksCompleted,proto3" json:"tasks_completed,omitempty"`
+	JobsSubmitted  int32   `protobuf:"varint,4,opt,name=jobs_submitted,json=jobsSubmitted,proto3" json:"jobs_submitted,omitempty"`
+	// Task-hours the user may consume; 0 means unlimited.
+	QuotaTaskHours float64 `protobuf:"fixed64,5,opt,name=quota_task_hours,json=quotaTaskHours,proto3" json:"quota_task_hours,omitempty"`
+	UpdatedAtMs    int64   `protobuf:"varint,6,opt,name=updated_at_ms,json=updatedAtMs,proto3" json:"updated_at_ms,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *UserUsage) Reset() {
+	*x = UserUsage{}
+	mi := &file_orchestrator_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserUsage) ProtoMessage() {}
+
+func (x *UserUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserUsage.ProtoReflect.Descriptor instead.
+func (*UserUsage) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *UserUsage) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UserUsage) GetTaskHours() float64 {
+	if x != nil {
+		return x.TaskHours
+	}
+	return 0
+}
+
+func (x *UserUsage) GetTasksCompleted() int32 {
 	if x != nil {
-		return x.Progress
+		return x.TasksCompleted
 	}
 	return 0
 }
 
-func (x *GetJobStatusResponse) GetCompletedTasks() int32 {
+func (x *UserUsage) GetJobsSubmitted() int32 {
 	if x != nil {
-		return x.CompletedTasks
+		return x.JobsSubmitted
 	}
 	return 0
 }
 
-func (x *GetJobStatusResponse) GetTotalTasks() int32 {
+func (x *UserUsage) GetQuotaTaskHours() float64 {
 	if x != nil {
-		return x.TotalTasks
+		return x.QuotaTaskHours
 	}
 	return 0
 }
 
-func (x *GetJobStatusResponse) GetCurrentLoss() float64 {
+func (x *UserUsage) GetUpdatedAtMs() int64 {
 	if x != nil {
-		return x.CurrentLoss
+		return x.UpdatedAtMs
 	}
 	return 0
 }
 
-func (x *GetJobStatusResponse) GetCurrentAccuracy() float64 {
+type GetUserUsageRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Empty lists every user with recorded usage.
+	UserId        string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserUsageRequest) Reset() {
+	*x = GetUserUsageRequest{}
+	mi := &file_orchestrator_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserUsageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserUsageRequest) ProtoMessage() {}
+
+func (x *GetUserUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[64]
 	if x != nil {
-		return x.CurrentAccuracy
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *GetJobStatusResponse) GetMessage() string {
+// Deprecated: Use GetUserUsageRequest.ProtoReflect.Descriptor instead.
+func (*GetUserUsageRequest) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *GetUserUsageRequest) GetUserId() string {
 	if x != nil {
-		return x.Message
+		return x.UserId
 	}
 	return ""
 }
 
-type AssignTaskRequest struct {
+type GetUserUsageResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	WorkerId      string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	Users         []*UserUsage           `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AssignTaskRequest) Reset() {
-	*x = AssignTaskRequest{}
-	mi := &file_orchestrator_proto_msgTypes[4]
+func (x *GetUserUsageResponse) Reset() {
+	*x = GetUserUsageResponse{}
+	mi := &file_orchestrator_proto_msgTypes[65]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AssignTaskRequest) String() string {
+func (x *GetUserUsageResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AssignTaskRequest) ProtoMessage() {}
+func (*GetUserUsageResponse) ProtoMessage() {}
 
-func (x *AssignTaskRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_orchestrator_proto_msgTypes[4]
+func (x *GetUserUsageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[65]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -357,47 +5568,42 @@ func (x *AssignTaskRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AssignTaskRequest.ProtoReflect.Descriptor instead.
-func (*AssignTaskRequest) Descriptor() ([]byte, []int) {
-	return file_orchestrator_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use GetUserUsageResponse.ProtoReflect.Descriptor instead.
+func (*GetUserUsageResponse) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{65}
 }
 
-func (x *AssignTaskRequest) GetWorkerId() string {
+func (x *GetUserUsageResponse) GetUsers() []*UserUsage {
 	if x != nil {
-		return x.WorkerId
+		return x.Users
 	}
-	return ""
+	return nil
 }
 
-type AssignTaskResponse struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	TaskId          string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
-	JobId           string                 `protobuf:"bytes,2,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
-	ModelType       string                 `protobuf:"bytes,3,opt,name=model_type,json=modelType,proto3" json:"model_type,omitempty"`
-	DatasetPath     string                 `protobuf:"bytes,4,opt,name=dataset_path,json=datasetPath,proto3" json:"dataset_path,omitempty"`
-	Hyperparameters map[string]string      `protobuf:"bytes,5,rep,name=hyperparameters,proto3" json:"hyperparameters,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	Epoch           int32                  `protobuf:"varint,6,opt,name=epoch,proto3" json:"epoch,omitempty"`
-	BatchStart      int32                  `protobuf:"varint,7,opt,name=batch_start,json=batchStart,proto3" json:"batch_start,omitempty"`
-	BatchEnd        int32                  `protobuf:"varint,8,opt,name=batch_end,json=batchEnd,proto3" json:"batch_end,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+type SetUserQuotaRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserId string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// Unset restores the cluster default; 0 makes the user unlimited.
+	TaskHoursQuota *float64 `protobuf:"fixed64,2,opt,name=task_hours_quota,json=taskHoursQuota,proto3,oneof" json:"task_hours_quota,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
-func (x *AssignTaskResponse) Reset() {
-	*x = AssignTaskResponse{}
-	mi := &file_orchestrator_proto_msgTypes[5]
+func (x *SetUserQuotaRequest) Reset() {
+	*x = SetUserQuotaRequest{}
+	mi := &file_orchestrator_proto_msgTypes[66]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AssignTaskResponse) String() string {
+func (x *SetUserQuotaRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AssignTaskResponse) ProtoMessage() {}
+func (*SetUserQuotaRequest) ProtoMessage() {}
 
-func (x *AssignTaskResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_orchestrator_proto_msgTypes[5]
+func (x *SetUserQuotaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[66]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -408,96 +5614,112 @@ func (x *AssignTaskResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AssignTaskResponse.ProtoReflect.Descriptor instead.
-func (*AssignTaskResponse) Descriptor() ([]byte, []int) {
-	return file_orchestrator_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use SetUserQuotaRequest.ProtoReflect.Descriptor instead.
+func (*SetUserQuotaRequest) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{66}
 }
 
-func (x *AssignTaskResponse) GetTaskId() string {
+func (x *SetUserQuotaRequest) GetUserId() string {
 	if x != nil {
-		return x.TaskId
+		return x.UserId
 	}
 	return ""
 }
 
-func (x *AssignTaskResponse) GetJobId() string {
-	if x != nil {
-		return x.JobId
+func (x *SetUserQuotaRequest) GetTaskHoursQuota() float64 {
+	if x != nil && x.TaskHoursQuota != nil {
+		return *x.TaskHoursQuota
 	}
-	return ""
+	return 0
 }
 
-func (x *AssignTaskResponse) GetModelType() string {
-	if x != nil {
-		return x.ModelType
-	}
-	return ""
+type SetUserQuotaResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Usage         *UserUsage             `protobuf:"bytes,3,opt,name=usage,proto3" json:"usage,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AssignTaskResponse) GetDatasetPath() string {
-	if x != nil {
-		return x.DatasetPath
-	}
-	return ""
+func (x *SetUserQuotaResponse) Reset() {
+	*x = SetUserQuotaResponse{}
+	mi := &file_orchestrator_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *AssignTaskResponse) GetHyperparameters() map[string]string {
+func (x *SetUserQuotaResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetUserQuotaResponse) ProtoMessage() {}
+
+func (x *SetUserQuotaResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[67]
 	if x != nil {
-		return x.Hyperparameters
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-func (x *AssignTaskResponse) GetEpoch() int32 {
+// Deprecated: Use SetUserQuotaResponse.ProtoReflect.Descriptor instead.
+func (*SetUserQuotaResponse) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *SetUserQuotaResponse) GetSuccess() bool {
 	if x != nil {
-		return x.Epoch
+		return x.Success
 	}
-	return 0
+	return false
 }
 
-func (x *AssignTaskResponse) GetBatchStart() int32 {
+func (x *SetUserQuotaResponse) GetMessage() string {
 	if x != nil {
-		return x.BatchStart
+		return x.Message
 	}
-	return 0
+	return ""
 }
 
-func (x *AssignTaskResponse) GetBatchEnd() int32 {
+func (x *SetUserQuotaResponse) GetUsage() *UserUsage {
 	if x != nil {
-		return x.BatchEnd
+		return x.Usage
 	}
-	return 0
+	return nil
 }
 
-type TaskCompletionRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
-	JobId         string                 `protobuf:"bytes,2,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
-	WorkerId      string                 `protobuf:"bytes,3,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
-	Success       bool                   `protobuf:"varint,4,opt,name=success,proto3" json:"success,omitempty"`
-	ErrorMessage  string                 `protobuf:"bytes,5,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
-	Loss          float64                `protobuf:"fixed64,6,opt,name=loss,proto3" json:"loss,omitempty"`
-	Accuracy      float64                `protobuf:"fixed64,7,opt,name=accuracy,proto3" json:"accuracy,omitempty"`
-	ModelWeights  []byte                 `protobuf:"bytes,8,opt,name=model_weights,json=modelWeights,proto3" json:"model_weights,omitempty"`
+type RequeueTaskRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	JobId    string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	TaskId   string                 `protobuf:"bytes,2,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	Reason   string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	Operator string                 `protobuf:"bytes,4,opt,name=operator,proto3" json:"operator,omitempty"`
+	// Gives the task a fresh set of retries.
+	ResetAttempts bool `protobuf:"varint,5,opt,name=reset_attempts,json=resetAttempts,proto3" json:"reset_attempts,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *TaskCompletionRequest) Reset() {
-	*x = TaskCompletionRequest{}
-	mi := &file_orchestrator_proto_msgTypes[6]
+func (x *RequeueTaskRequest) Reset() {
+	*x = RequeueTaskRequest{}
+	mi := &file_orchestrator_proto_msgTypes[68]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *TaskCompletionRequest) String() string {
+func (x *RequeueTaskRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TaskCompletionRequest) ProtoMessage() {}
+func (*RequeueTaskRequest) ProtoMessage() {}
 
-func (x *TaskCompletionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_orchestrator_proto_msgTypes[6]
+func (x *RequeueTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[68]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -508,90 +5730,136 @@ func (x *TaskCompletionRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TaskCompletionRequest.ProtoReflect.Descriptor instead.
-func (*TaskCompletionRequest) Descriptor() ([]byte, []int) {
-	return file_orchestrator_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use RequeueTaskRequest.ProtoReflect.Descriptor instead.
+func (*RequeueTaskRequest) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{68}
 }
 
-func (x *TaskCompletionRequest) GetTaskId() string {
+func (x *RequeueTaskRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *RequeueTaskRequest) GetTaskId() string {
 	if x != nil {
 		return x.TaskId
 	}
 	return ""
 }
 
-func (x *TaskCompletionRequest) GetJobId() string {
+func (x *RequeueTaskRequest) GetReason() string {
 	if x != nil {
-		return x.JobId
+		return x.Reason
 	}
 	return ""
 }
 
-func (x *TaskCompletionRequest) GetWorkerId() string {
+func (x *RequeueTaskRequest) GetOperator() string {
 	if x != nil {
-		return x.WorkerId
+		return x.Operator
 	}
 	return ""
 }
 
-func (x *TaskCompletionRequest) GetSuccess() bool {
+func (x *RequeueTaskRequest) GetResetAttempts() bool {
 	if x != nil {
-		return x.Success
+		return x.ResetAttempts
 	}
 	return false
 }
 
-func (x *TaskCompletionRequest) GetErrorMessage() string {
+type ForceFailJobRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	Operator      string                 `protobuf:"bytes,3,opt,name=operator,proto3" json:"operator,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ForceFailJobRequest) Reset() {
+	*x = ForceFailJobRequest{}
+	mi := &file_orchestrator_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForceFailJobRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForceFailJobRequest) ProtoMessage() {}
+
+func (x *ForceFailJobRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[69]
 	if x != nil {
-		return x.ErrorMessage
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *TaskCompletionRequest) GetLoss() float64 {
+// Deprecated: Use ForceFailJobRequest.ProtoReflect.Descriptor instead.
+func (*ForceFailJobRequest) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *ForceFailJobRequest) GetJobId() string {
 	if x != nil {
-		return x.Loss
+		return x.JobId
 	}
-	return 0
+	return ""
 }
 
-func (x *TaskCompletionRequest) GetAccuracy() float64 {
+func (x *ForceFailJobRequest) GetReason() string {
 	if x != nil {
-		return x.Accuracy
+		return x.Reason
 	}
-	return 0
+	return ""
 }
 
-func (x *TaskCompletionRequest) GetModelWeights() []byte {
+func (x *ForceFailJobRequest) GetOperator() string {
 	if x != nil {
-		return x.ModelWeights
+		return x.Operator
 	}
-	return nil
+	return ""
 }
 
-type TaskCompletionResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Acknowledged  bool                   `protobuf:"varint,1,opt,name=acknowledged,proto3" json:"acknowledged,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+type DrainWorkerRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	WorkerId string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	Reason   string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	Operator string                 `protobuf:"bytes,3,opt,name=operator,proto3" json:"operator,omitempty"`
+	// Cancels the worker's in-flight tasks and requeues them instead of
+	// letting them finish.
+	RequeueInFlight bool `protobuf:"varint,4,opt,name=requeue_in_flight,json=requeueInFlight,proto3" json:"requeue_in_flight,omitempty"`
+	// Removes the worker, requeueing its in-flight tasks. It is registered
+	// afresh if it connects again.
+	Deregister    bool `protobuf:"varint,5,opt,name=deregister,proto3" json:"deregister,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *TaskCompletionResponse) Reset() {
-	*x = TaskCompletionResponse{}
-	mi := &file_orchestrator_proto_msgTypes[7]
+func (x *DrainWorkerRequest) Reset() {
+	*x = DrainWorkerRequest{}
+	mi := &file_orchestrator_proto_msgTypes[70]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *TaskCompletionResponse) String() string {
+func (x *DrainWorkerRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*TaskCompletionResponse) ProtoMessage() {}
+func (*DrainWorkerRequest) ProtoMessage() {}
 
-func (x *TaskCompletionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_orchestrator_proto_msgTypes[7]
+func (x *DrainWorkerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[70]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -602,50 +5870,71 @@ func (x *TaskCompletionResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use TaskCompletionResponse.ProtoReflect.Descriptor instead.
-func (*TaskCompletionResponse) Descriptor() ([]byte, []int) {
-	return file_orchestrator_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use DrainWorkerRequest.ProtoReflect.Descriptor instead.
+func (*DrainWorkerRequest) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{70}
 }
 
-func (x *TaskCompletionResponse) GetAcknowledged() bool {
+func (x *DrainWorkerRequest) GetWorkerId() string {
 	if x != nil {
-		return x.Acknowledged
+		return x.WorkerId
 	}
-	return false
+	return ""
 }
 
-func (x *TaskCompletionResponse) GetMessage() string {
+func (x *DrainWorkerRequest) GetReason() string {
 	if x != nil {
-		return x.Message
+		return x.Reason
 	}
 	return ""
 }
 
-type JobMetricsRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
-	Epoch         int32                  `protobuf:"varint,2,opt,name=epoch,proto3" json:"epoch,omitempty"`
-	Loss          float64                `protobuf:"fixed64,3,opt,name=loss,proto3" json:"loss,omitempty"`
-	Accuracy      float64                `protobuf:"fixed64,4,opt,name=accuracy,proto3" json:"accuracy,omitempty"`
+func (x *DrainWorkerRequest) GetOperator() string {
+	if x != nil {
+		return x.Operator
+	}
+	return ""
+}
+
+func (x *DrainWorkerRequest) GetRequeueInFlight() bool {
+	if x != nil {
+		return x.RequeueInFlight
+	}
+	return false
+}
+
+func (x *DrainWorkerRequest) GetDeregister() bool {
+	if x != nil {
+		return x.Deregister
+	}
+	return false
+}
+
+type AdminResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Success bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// Tasks put back on the queue by the action.
+	RequeuedTasks int32 `protobuf:"varint,3,opt,name=requeued_tasks,json=requeuedTasks,proto3" json:"requeued_tasks,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *JobMetricsRequest) Reset() {
-	*x = JobMetricsRequest{}
-	mi := &file_orchestrator_proto_msgTypes[8]
+func (x *AdminResponse) Reset() {
+	*x = AdminResponse{}
+	mi := &file_orchestrator_proto_msgTypes[71]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *JobMetricsRequest) String() string {
+func (x *AdminResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*JobMetricsRequest) ProtoMessage() {}
+func (*AdminResponse) ProtoMessage() {}
 
-func (x *JobMetricsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_orchestrator_proto_msgTypes[8]
+func (x *AdminResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[71]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -656,61 +5945,55 @@ func (x *JobMetricsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use JobMetricsRequest.ProtoReflect.Descriptor instead.
-func (*JobMetricsRequest) Descriptor() ([]byte, []int) {
-	return file_orchestrator_proto_rawDescGZIP(), []int{8}
-}
-
-func (x *JobMetricsRequest) GetJobId() string {
-	if x != nil {
-		return x.JobId
-	}
-	return ""
+// Deprecated: Use AdminResponse.ProtoReflect.Descriptor instead.
+func (*AdminResponse) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{71}
 }
 
-func (x *JobMetricsRequest) GetEpoch() int32 {
+func (x *AdminResponse) GetSuccess() bool {
 	if x != nil {
-		return x.Epoch
+		return x.Success
 	}
-	return 0
+	return false
 }
 
-func (x *JobMetricsRequest) GetLoss() float64 {
+func (x *AdminResponse) GetMessage() string {
 	if x != nil {
-		return x.Loss
+		return x.Message
 	}
-	return 0
+	return ""
 }
 
-func (x *JobMetricsRequest) GetAccuracy() float64 {
+func (x *AdminResponse) GetRequeuedTasks() int32 {
 	if x != nil {
-		return x.Accuracy
+		return x.RequeuedTasks
 	}
 	return 0
 }
 
-type JobMetricsResponse struct {
+type ExportSnapshotRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Reason        string                 `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
+	Operator      string                 `protobuf:"bytes,2,opt,name=operator,proto3" json:"operator,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *JobMetricsResponse) Reset() {
-	*x = JobMetricsResponse{}
-	mi := &file_orchestrator_proto_msgTypes[9]
+func (x *ExportSnapshotRequest) Reset() {
+	*x = ExportSnapshotRequest{}
+	mi := &file_orchestrator_proto_msgTypes[72]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *JobMetricsResponse) String() string {
+func (x *ExportSnapshotRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*JobMetricsResponse) ProtoMessage() {}
+func (*ExportSnapshotRequest) ProtoMessage() {}
 
-func (x *JobMetricsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_orchestrator_proto_msgTypes[9]
+func (x *ExportSnapshotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[72]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -721,40 +6004,53 @@ func (x *JobMetricsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use JobMetricsResponse.ProtoReflect.Descriptor instead.
-func (*JobMetricsResponse) Descriptor() ([]byte, []int) {
-	return file_orchestrator_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use ExportSnapshotRequest.ProtoReflect.Descriptor instead.
+func (*ExportSnapshotRequest) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{72}
 }
 
-func (x *JobMetricsResponse) GetSuccess() bool {
+func (x *ExportSnapshotRequest) GetReason() string {
 	if x != nil {
-		return x.Success
+		return x.Reason
 	}
-	return false
+	return ""
 }
 
-type CancelJobRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	JobId         string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+func (x *ExportSnapshotRequest) GetOperator() string {
+	if x != nil {
+		return x.Operator
+	}
+	return ""
+}
+
+type ImportSnapshotRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Object key returned by ExportSnapshot.
+	Key      string `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Reason   string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	Operator string `protobuf:"bytes,3,opt,name=operator,proto3" json:"operator,omitempty"`
+	// Replaces stored jobs that are also in the snapshot instead of keeping
+	// them. Jobs this orchestrator holds in memory are never replaced.
+	Overwrite     bool `protobuf:"varint,4,opt,name=overwrite,proto3" json:"overwrite,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CancelJobRequest) Reset() {
-	*x = CancelJobRequest{}
-	mi := &file_orchestrator_proto_msgTypes[10]
+func (x *ImportSnapshotRequest) Reset() {
+	*x = ImportSnapshotRequest{}
+	mi := &file_orchestrator_proto_msgTypes[73]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CancelJobRequest) String() string {
+func (x *ImportSnapshotRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CancelJobRequest) ProtoMessage() {}
+func (*ImportSnapshotRequest) ProtoMessage() {}
 
-func (x *CancelJobRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_orchestrator_proto_msgTypes[10]
+func (x *ImportSnapshotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[73]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -765,42 +6061,71 @@ func (x *CancelJobRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CancelJobRequest.ProtoReflect.Descriptor instead.
-func (*CancelJobRequest) Descriptor() ([]byte, []int) {
-	return file_orchestrator_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use ImportSnapshotRequest.ProtoReflect.Descriptor instead.
+func (*ImportSnapshotRequest) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{73}
 }
 
-func (x *CancelJobRequest) GetJobId() string {
+func (x *ImportSnapshotRequest) GetKey() string {
 	if x != nil {
-		return x.JobId
+		return x.Key
 	}
 	return ""
 }
 
-type CancelJobResponse struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	Success        bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message        string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	PreviousStatus string                 `protobuf:"bytes,3,opt,name=previous_status,json=previousStatus,proto3" json:"previous_status,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+func (x *ImportSnapshotRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
 }
 
-func (x *CancelJobResponse) Reset() {
-	*x = CancelJobResponse{}
-	mi := &file_orchestrator_proto_msgTypes[11]
+func (x *ImportSnapshotRequest) GetOperator() string {
+	if x != nil {
+		return x.Operator
+	}
+	return ""
+}
+
+func (x *ImportSnapshotRequest) GetOverwrite() bool {
+	if x != nil {
+		return x.Overwrite
+	}
+	return false
+}
+
+type SnapshotResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Success bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// Object key of the snapshot in the snapshots bucket.
+	Key           string `protobuf:"bytes,3,opt,name=key,proto3" json:"key,omitempty"`
+	FormatVersion int32  `protobuf:"varint,4,opt,name=format_version,json=formatVersion,proto3" json:"format_version,omitempty"`
+	CreatedAtMs   int64  `protobuf:"varint,5,opt,name=created_at_ms,json=createdAtMs,proto3" json:"created_at_ms,omitempty"`
+	SizeBytes     int64  `protobuf:"varint,6,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+	Jobs          int32  `protobuf:"varint,7,opt,name=jobs,proto3" json:"jobs,omitempty"`
+	Workers       int32  `protobuf:"varint,8,opt,name=workers,proto3" json:"workers,omitempty"`
+	// Jobs of the snapshot that import left alone because they already exist.
+	SkippedJobs   int32 `protobuf:"varint,9,opt,name=skipped_jobs,json=skippedJobs,proto3" json:"skipped_jobs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SnapshotResponse) Reset() {
+	*x = SnapshotResponse{}
+	mi := &file_orchestrator_proto_msgTypes[74]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CancelJobResponse) String() string {
+func (x *SnapshotResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CancelJobResponse) ProtoMessage() {}
+func (*SnapshotResponse) ProtoMessage() {}
 
-func (x *CancelJobResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_orchestrator_proto_msgTypes[11]
+func (x *SnapshotResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[74]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -811,53 +6136,95 @@ func (x *CancelJobResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CancelJobResponse.ProtoReflect.Descriptor instead.
-func (*CancelJobResponse) Descriptor() ([]byte, []int) {
-	return file_orchestrator_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use SnapshotResponse.ProtoReflect.Descriptor instead.
+func (*SnapshotResponse) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{74}
 }
 
-func (x *CancelJobResponse) GetSuccess() bool {
+func (x *SnapshotResponse) GetSuccess() bool {
 	if x != nil {
 		return x.Success
 	}
 	return false
 }
 
-func (x *CancelJobResponse) GetMessage() string {
+func (x *SnapshotResponse) GetMessage() string {
 	if x != nil {
 		return x.Message
 	}
 	return ""
 }
 
-func (x *CancelJobResponse) GetPreviousStatus() string {
+func (x *SnapshotResponse) GetKey() string {
 	if x != nil {
-		return x.PreviousStatus
+		return x.Key
 	}
 	return ""
 }
 
-type WorkerActivityRequest struct {
+func (x *SnapshotResponse) GetFormatVersion() int32 {
+	if x != nil {
+		return x.FormatVersion
+	}
+	return 0
+}
+
+func (x *SnapshotResponse) GetCreatedAtMs() int64 {
+	if x != nil {
+		return x.CreatedAtMs
+	}
+	return 0
+}
+
+func (x *SnapshotResponse) GetSizeBytes() int64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+func (x *SnapshotResponse) GetJobs() int32 {
+	if x != nil {
+		return x.Jobs
+	}
+	return 0
+}
+
+func (x *SnapshotResponse) GetWorkers() int32 {
+	if x != nil {
+		return x.Workers
+	}
+	return 0
+}
+
+func (x *SnapshotResponse) GetSkippedJobs() int32 {
+	if x != nil {
+		return x.SkippedJobs
+	}
+	return 0
+}
+
+type ClusterSummaryRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *WorkerActivityRequest) Reset() {
-	*x = WorkerActivityRequest{}
-	mi := &file_orchestrator_proto_msgTypes[12]
+func (x *ClusterSummaryRequest) Reset() {
+	*x = ClusterSummaryRequest{}
+	mi := &file_orchestrator_proto_msgTypes[75]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *WorkerActivityRequest) String() string {
+func (x *ClusterSummaryRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*WorkerActivityRequest) ProtoMessage() {}
+func (*ClusterSummaryRequest) ProtoMessage() {}
 
-func (x *WorkerActivityRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_orchestrator_proto_msgTypes[12]
+func (x *ClusterSummaryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[75]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -868,34 +6235,50 @@ func (x *WorkerActivityRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use WorkerActivityRequest.ProtoReflect.Descriptor instead.
-func (*WorkerActivityRequest) Descriptor() ([]byte, []int) {
-	return file_orchestrator_proto_rawDescGZIP(), []int{12}
-}
-
-type WorkerActivityResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Workers       []*WorkerInfo          `protobuf:"bytes,1,rep,name=workers,proto3" json:"workers,omitempty"`
-	TotalWorkers  int32                  `protobuf:"varint,2,opt,name=total_workers,json=totalWorkers,proto3" json:"total_workers,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// Deprecated: Use ClusterSummaryRequest.ProtoReflect.Descriptor instead.
+func (*ClusterSummaryRequest) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{75}
+}
+
+// ClusterSummaryResponse covers the jobs the orchestrator holds in memory:
+// every unfinished job and those finished within FINISHED_JOB_TTL.
+type ClusterSummaryResponse struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	JobsByStatus       map[string]int32       `protobuf:"bytes,1,rep,name=jobs_by_status,json=jobsByStatus,proto3" json:"jobs_by_status,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	QueuedTasks        int32                  `protobuf:"varint,2,opt,name=queued_tasks,json=queuedTasks,proto3" json:"queued_tasks,omitempty"`    // Pending tasks of unfinished jobs
+	RunningTasks       int32                  `protobuf:"varint,3,opt,name=running_tasks,json=runningTasks,proto3" json:"running_tasks,omitempty"` // Tasks leased to workers
+	WorkersByStatus    map[string]int32       `protobuf:"bytes,4,rep,name=workers_by_status,json=workersByStatus,proto3" json:"workers_by_status,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	QuarantinedWorkers int32                  `protobuf:"varint,5,opt,name=quarantined_workers,json=quarantinedWorkers,proto3" json:"quarantined_workers,omitempty"`
+	DrainingWorkers    int32                  `protobuf:"varint,6,opt,name=draining_workers,json=drainingWorkers,proto3" json:"draining_workers,omitempty"`
+	// Throughput over the trailing window, one hour unless FINISHED_JOB_TTL
+	// is shorter.
+	WindowSeconds  int64   `protobuf:"varint,7,opt,name=window_seconds,json=windowSeconds,proto3" json:"window_seconds,omitempty"`
+	TasksCompleted int32   `protobuf:"varint,8,opt,name=tasks_completed,json=tasksCompleted,proto3" json:"tasks_completed,omitempty"`
+	JobsCompleted  int32   `protobuf:"varint,9,opt,name=jobs_completed,json=jobsCompleted,proto3" json:"jobs_completed,omitempty"` // Finished COMPLETED or COMPLETED_EARLY
+	JobsFailed     int32   `protobuf:"varint,10,opt,name=jobs_failed,json=jobsFailed,proto3" json:"jobs_failed,omitempty"`
+	TasksPerMinute float64 `protobuf:"fixed64,11,opt,name=tasks_per_minute,json=tasksPerMinute,proto3" json:"tasks_per_minute,omitempty"`
+	// Mean time from admission to finish of the jobs completed in the window.
+	AvgJobDurationMs int64 `protobuf:"varint,12,opt,name=avg_job_duration_ms,json=avgJobDurationMs,proto3" json:"avg_job_duration_ms,omitempty"`
+	GeneratedAtMs    int64 `protobuf:"varint,13,opt,name=generated_at_ms,json=generatedAtMs,proto3" json:"generated_at_ms,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
-func (x *WorkerActivityResponse) Reset() {
-	*x = WorkerActivityResponse{}
-	mi := &file_orchestrator_proto_msgTypes[13]
+func (x *ClusterSummaryResponse) Reset() {
+	*x = ClusterSummaryResponse{}
+	mi := &file_orchestrator_proto_msgTypes[76]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *WorkerActivityResponse) String() string {
+func (x *ClusterSummaryResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*WorkerActivityResponse) ProtoMessage() {}
+func (*ClusterSummaryResponse) ProtoMessage() {}
 
-func (x *WorkerActivityResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_orchestrator_proto_msgTypes[13]
+func (x *ClusterSummaryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orchestrator_proto_msgTypes[76]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -906,105 +6289,98 @@ func (x *WorkerActivityResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use WorkerActivityResponse.ProtoReflect.Descriptor instead.
-func (*WorkerActivityResponse) Descriptor() ([]byte, []int) {
-	return file_orchestrator_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use ClusterSummaryResponse.ProtoReflect.Descriptor instead.
+func (*ClusterSummaryResponse) Descriptor() ([]byte, []int) {
+	return file_orchestrator_proto_rawDescGZIP(), []int{76}
 }
 
-func (x *WorkerActivityResponse) GetWorkers() []*WorkerInfo {
+func (x *ClusterSummaryResponse) GetJobsByStatus() map[string]int32 {
 	if x != nil {
-		return x.Workers
+		return x.JobsByStatus
 	}
 	return nil
 }
 
-func (x *WorkerActivityResponse) GetTotalWorkers() int32 {
+func (x *ClusterSummaryResponse) GetQueuedTasks() int32 {
 	if x != nil {
-		return x.TotalWorkers
+		return x.QueuedTasks
 	}
 	return 0
 }
 
-type WorkerInfo struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	WorkerId         string                 `protobuf:"bytes,1,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
-	Status           string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
-	CurrentTaskId    string                 `protobuf:"bytes,3,opt,name=current_task_id,json=currentTaskId,proto3" json:"current_task_id,omitempty"`
-	CurrentJobId     string                 `protobuf:"bytes,4,opt,name=current_job_id,json=currentJobId,proto3" json:"current_job_id,omitempty"`
-	TasksCompleted   int32                  `protobuf:"varint,5,opt,name=tasks_completed,json=tasksCompleted,proto3" json:"tasks_completed,omitempty"`
-	LastActivityTime int64                  `protobuf:"varint,6,opt,name=last_activity_time,json=lastActivityTime,proto3" json:"last_activity_time,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+func (x *ClusterSummaryResponse) GetRunningTasks() int32 {
+	if x != nil {
+		return x.RunningTasks
+	}
+	return 0
 }
 
-func (x *WorkerInfo) Reset() {
-	*x = WorkerInfo{}
-	mi := &file_orchestrator_proto_msgTypes[14]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *ClusterSummaryResponse) GetWorkersByStatus() map[string]int32 {
+	if x != nil {
+		return x.WorkersByStatus
+	}
+	return nil
 }
 
-func (x *WorkerInfo) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *ClusterSummaryResponse) GetQuarantinedWorkers() int32 {
+	if x != nil {
+		return x.QuarantinedWorkers
+	}
+	return 0
 }
 
-func (*WorkerInfo) ProtoMessage() {}
-
-func (x *WorkerInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_orchestrator_proto_msgTypes[14]
+func (x *ClusterSummaryResponse) GetDrainingWorkers() int32 {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.DrainingWorkers
 	}
-	return mi.MessageOf(x)
+	return 0
 }
 
-// Deprecated: Use WorkerInfo.ProtoReflect.Descriptor instead.
-func (*WorkerInfo) Descriptor() ([]byte, []int) {
-	return file_orchestrator_proto_rawDescGZIP(), []int{14}
+func (x *ClusterSummaryResponse) GetWindowSeconds() int64 {
+	if x != nil {
+		return x.WindowSeconds
+	}
+	return 0
 }
 
-func (x *WorkerInfo) GetWorkerId() string {
+func (x *ClusterSummaryResponse) GetTasksCompleted() int32 {
 	if x != nil {
-		return x.WorkerId
+		return x.TasksCompleted
 	}
-	return ""
+	return 0
 }
 
-func (x *WorkerInfo) GetStatus() string {
+func (x *ClusterSummaryResponse) GetJobsCompleted() int32 {
 	if x != nil {
-		return x.Status
+		return x.JobsCompleted
 	}
-	return ""
+	return 0
 }
 
-func (x *WorkerInfo) GetCurrentTaskId() string {
+func (x *ClusterSummaryResponse) GetJobsFailed() int32 {
 	if x != nil {
-		return x.CurrentTaskId
+		return x.JobsFailed
 	}
-	return ""
+	return 0
 }
 
-func (x *WorkerInfo) GetCurrentJobId() string {
+func (x *ClusterSummaryResponse) GetTasksPerMinute() float64 {
 	if x != nil {
-		return x.CurrentJobId
+		return x.TasksPerMinute
 	}
-	return ""
+	return 0
 }
 
-func (x *WorkerInfo) GetTasksCompleted() int32 {
+func (x *ClusterSummaryResponse) GetAvgJobDurationMs() int64 {
 	if x != nil {
-		return x.TasksCompleted
+		return x.AvgJobDurationMs
 	}
 	return 0
 }
 
-func (x *WorkerInfo) GetLastActivityTime() int64 {
+func (x *ClusterSummaryResponse) GetGeneratedAtMs() int64 {
 	if x != nil {
-		return x.LastActivityTime
+		return x.GeneratedAtMs
 	}
 	return 0
 }
@@ -1013,7 +6389,7 @@ var File_orchestrator_proto protoreflect.FileDescriptor
 
 const file_orchestrator_proto_rawDesc = "" +
 	"\n" +
-	"\x12orchestrator.proto\x12\forchestrator\"\xe4\x02\n" +
+	"\x12orchestrator.proto\x12\forchestrator\"\x86\v\n" +
 	"\x12TrainingJobRequest\x12\x15\n" +
 	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1d\n" +
@@ -1023,9 +6399,50 @@ const file_orchestrator_proto_rawDesc = "" +
 	"\x0fhyperparameters\x18\x05 \x03(\v25.orchestrator.TrainingJobRequest.HyperparametersEntryR\x0fhyperparameters\x12\x1f\n" +
 	"\vnum_workers\x18\x06 \x01(\x05R\n" +
 	"numWorkers\x12\x16\n" +
-	"\x06epochs\x18\a \x01(\x05R\x06epochs\x1aB\n" +
+	"\x06epochs\x18\a \x01(\x05R\x06epochs\x120\n" +
+	"\x14max_duration_seconds\x18\b \x01(\x03R\x12maxDurationSeconds\x128\n" +
+	"\x18progress_timeout_seconds\x18\t \x01(\x03R\x16progressTimeoutSeconds\x12-\n" +
+	"\x10max_task_retries\x18\n" +
+	" \x01(\x05H\x00R\x0emaxTaskRetries\x88\x01\x01\x127\n" +
+	"\x18max_failed_tasks_percent\x18\v \x01(\x01R\x15maxFailedTasksPercent\x12\x1a\n" +
+	"\bpriority\x18\f \x01(\x05R\bpriority\x12F\n" +
+	"\frequirements\x18\r \x01(\v2\".orchestrator.ResourceRequirementsR\frequirements\x12\x1f\n" +
+	"\vsync_epochs\x18\x0e \x01(\bR\n" +
+	"syncEpochs\x12 \n" +
+	"\vaggregation\x18\x0f \x01(\tR\vaggregation\x126\n" +
+	"\x17checkpoint_every_epochs\x18\x10 \x01(\x05R\x15checkpointEveryEpochs\x120\n" +
+	"\x14task_timeout_seconds\x18\x11 \x01(\x03R\x12taskTimeoutSeconds\x12*\n" +
+	"\x11batches_per_epoch\x18\x12 \x01(\x05R\x0fbatchesPerEpoch\x12*\n" +
+	"\x11samples_per_batch\x18\x13 \x01(\x05R\x0fsamplesPerBatch\x12!\n" +
+	"\fdataset_size\x18\x14 \x01(\x03R\vdatasetSize\x12B\n" +
+	"\x0eearly_stopping\x18\x15 \x01(\v2\x1b.orchestrator.EarlyStoppingR\rearlyStopping\x12\x1d\n" +
+	"\n" +
+	"depends_on\x18\x16 \x03(\tR\tdependsOn\x122\n" +
+	"\x15on_dependency_failure\x18\x17 \x01(\tR\x13onDependencyFailure\x12'\n" +
+	"\x0fgang_scheduling\x18\x18 \x01(\bR\x0egangScheduling\x12\x1c\n" +
+	"\tnamespace\x18\x19 \x01(\tR\tnamespace\x12\x16\n" +
+	"\x06region\x18\x1a \x01(\tR\x06region\x12,\n" +
+	"\x12max_parallel_tasks\x18\x1b \x01(\x05R\x10maxParallelTasks\x12\x14\n" +
+	"\x05image\x18\x1c \x01(\tR\x05image\x12\x16\n" +
+	"\x06stages\x18\x1d \x03(\tR\x06stages\x126\n" +
+	"\x17validation_dataset_path\x18\x1e \x01(\tR\x15validationDatasetPath\x12!\n" +
+	"\fresult_cache\x18\x1f \x01(\tR\vresultCache\x1aB\n" +
 	"\x14HyperparametersEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\x13\n" +
+	"\x11_max_task_retries\"`\n" +
+	"\rEarlyStopping\x12\x16\n" +
+	"\x06metric\x18\x01 \x01(\tR\x06metric\x12\x1a\n" +
+	"\bpatience\x18\x02 \x01(\x05R\bpatience\x12\x1b\n" +
+	"\tmin_delta\x18\x03 \x01(\x01R\bminDelta\"\xa0\x02\n" +
+	"\x14ResourceRequirements\x12\"\n" +
+	"\rmin_cpu_cores\x18\x01 \x01(\x05R\vminCpuCores\x12\"\n" +
+	"\rmin_memory_mb\x18\x02 \x01(\x03R\vminMemoryMb\x12\"\n" +
+	"\rmin_gpu_count\x18\x03 \x01(\x05R\vminGpuCount\x12\x19\n" +
+	"\bgpu_type\x18\x04 \x01(\tR\agpuType\x12F\n" +
+	"\x06labels\x18\x05 \x03(\v2..orchestrator.ResourceRequirements.LabelsEntryR\x06labels\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"{\n" +
 	"\x13TrainingJobResponse\x12\x15\n" +
 	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x16\n" +
@@ -1033,7 +6450,7 @@ const file_orchestrator_proto_rawDesc = "" +
 	"\tnum_tasks\x18\x03 \x01(\x05R\bnumTasks\x12\x18\n" +
 	"\amessage\x18\x04 \x01(\tR\amessage\",\n" +
 	"\x13GetJobStatusRequest\x12\x15\n" +
-	"\x06job_id\x18\x01 \x01(\tR\x05jobId\"\x93\x02\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\"\xcc\a\n" +
 	"\x14GetJobStatusResponse\x12\x15\n" +
 	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x16\n" +
 	"\x06status\x18\x02 \x01(\tR\x06status\x12\x1a\n" +
@@ -1043,9 +6460,142 @@ const file_orchestrator_proto_rawDesc = "" +
 	"totalTasks\x12!\n" +
 	"\fcurrent_loss\x18\x06 \x01(\x01R\vcurrentLoss\x12)\n" +
 	"\x10current_accuracy\x18\a \x01(\x01R\x0fcurrentAccuracy\x12\x18\n" +
-	"\amessage\x18\b \x01(\tR\amessage\"0\n" +
+	"\amessage\x18\b \x01(\tR\amessage\x12\x1f\n" +
+	"\vnum_workers\x18\t \x01(\x05R\n" +
+	"numWorkers\x12+\n" +
+	"\x11allocated_workers\x18\n" +
+	" \x03(\tR\x10allocatedWorkers\x12%\n" +
+	"\x0equeue_position\x18\v \x01(\x05R\rqueuePosition\x12?\n" +
+	"\repoch_metrics\x18\f \x03(\v2\x1a.orchestrator.EpochMetricsR\fepochMetrics\x12,\n" +
+	"\x05state\x18\r \x01(\x0e2\x16.orchestrator.JobStateR\x05state\x12C\n" +
+	"\vtransitions\x18\x0e \x03(\v2!.orchestrator.JobStatusTransitionR\vtransitions\x12F\n" +
+	"\x0etask_durations\x18\x0f \x01(\v2\x1f.orchestrator.TaskDurationStatsR\rtaskDurations\x124\n" +
+	"\x16estimated_remaining_ms\x18\x10 \x01(\x03R\x14estimatedRemainingMs\x12\x1c\n" +
+	"\tnamespace\x18\x11 \x01(\tR\tnamespace\x12\x16\n" +
+	"\x06region\x18\x12 \x01(\tR\x06region\x12+\n" +
+	"\x11detailed_progress\x18\x13 \x01(\x01R\x10detailedProgress\x12?\n" +
+	"\rrunning_tasks\x18\x14 \x03(\v2\x1a.orchestrator.TaskProgressR\frunningTasks\x12I\n" +
+	"\x12validation_metrics\x18\x15 \x03(\v2\x1a.orchestrator.EpochMetricsR\x11validationMetrics\x12!\n" +
+	"\fcached_tasks\x18\x16 \x01(\x05R\vcachedTasks\"\xb0\n" +
+	"\n" +
+	"\aJobInfo\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"model_type\x18\x03 \x01(\tR\tmodelType\x12!\n" +
+	"\fdataset_path\x18\x04 \x01(\tR\vdatasetPath\x12,\n" +
+	"\x05state\x18\x05 \x01(\x0e2\x16.orchestrator.JobStateR\x05state\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x12%\n" +
+	"\x0estatus_message\x18\a \x01(\tR\rstatusMessage\x12\x1a\n" +
+	"\bpriority\x18\b \x01(\x05R\bpriority\x12\x16\n" +
+	"\x06epochs\x18\t \x01(\x05R\x06epochs\x12#\n" +
+	"\rcurrent_epoch\x18\n" +
+	" \x01(\x05R\fcurrentEpoch\x12\x1f\n" +
+	"\vnum_workers\x18\v \x01(\x05R\n" +
+	"numWorkers\x12\x1f\n" +
+	"\vtotal_tasks\x18\f \x01(\x05R\n" +
+	"totalTasks\x12'\n" +
+	"\x0fcompleted_tasks\x18\r \x01(\x05R\x0ecompletedTasks\x12!\n" +
+	"\ffailed_tasks\x18\x0e \x01(\x05R\vfailedTasks\x12\x1a\n" +
+	"\bprogress\x18\x0f \x01(\x05R\bprogress\x12!\n" +
+	"\fcurrent_loss\x18\x10 \x01(\x01R\vcurrentLoss\x12)\n" +
+	"\x10current_accuracy\x18\x11 \x01(\x01R\x0fcurrentAccuracy\x12\"\n" +
+	"\rcreated_at_ms\x18\x12 \x01(\x03R\vcreatedAtMs\x12\"\n" +
+	"\rupdated_at_ms\x18\x13 \x01(\x03R\vupdatedAtMs\x12\"\n" +
+	"\rstarted_at_ms\x18\x14 \x01(\x03R\vstartedAtMs\x12T\n" +
+	"\x0fhyperparameters\x18\x15 \x03(\v2*.orchestrator.JobInfo.HyperparametersEntryR\x0fhyperparameters\x12\x1d\n" +
+	"\n" +
+	"depends_on\x18\x16 \x03(\tR\tdependsOn\x12?\n" +
+	"\repoch_metrics\x18\x17 \x03(\v2\x1a.orchestrator.EpochMetricsR\fepochMetrics\x12C\n" +
+	"\vtransitions\x18\x18 \x03(\v2!.orchestrator.JobStatusTransitionR\vtransitions\x12+\n" +
+	"\x11allocated_workers\x18\x19 \x03(\tR\x10allocatedWorkers\x12%\n" +
+	"\x0equeue_position\x18\x1a \x01(\x05R\rqueuePosition\x12F\n" +
+	"\x0etask_durations\x18\x1b \x01(\v2\x1f.orchestrator.TaskDurationStatsR\rtaskDurations\x124\n" +
+	"\x16estimated_remaining_ms\x18\x1c \x01(\x03R\x14estimatedRemainingMs\x12\x1c\n" +
+	"\tnamespace\x18\x1d \x01(\tR\tnamespace\x12\x16\n" +
+	"\x06region\x18\x1e \x01(\tR\x06region\x12,\n" +
+	"\x12max_parallel_tasks\x18\x1f \x01(\x05R\x10maxParallelTasks\x12\x14\n" +
+	"\x05image\x18  \x01(\tR\x05image\x1aB\n" +
+	"\x14HyperparametersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"&\n" +
+	"\rGetJobRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\"9\n" +
+	"\x0eGetJobResponse\x12'\n" +
+	"\x03job\x18\x01 \x01(\v2\x15.orchestrator.JobInfoR\x03job\"\xd3\x01\n" +
+	"\x0fListJobsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12.\n" +
+	"\x06states\x18\x02 \x03(\x0e2\x16.orchestrator.JobStateR\x06states\x12\x1d\n" +
+	"\n" +
+	"model_type\x18\x03 \x01(\tR\tmodelType\x12\x1c\n" +
+	"\tnamespace\x18\x06 \x01(\tR\tnamespace\x12\x1b\n" +
+	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x05 \x01(\tR\tpageToken\"{\n" +
+	"\x10ListJobsResponse\x12)\n" +
+	"\x04jobs\x18\x01 \x03(\v2\x15.orchestrator.JobInfoR\x04jobs\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\x14\n" +
+	"\x05total\x18\x03 \x01(\x05R\x05total\"\xb2\x01\n" +
+	"\x12GetJobTasksRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x14\n" +
+	"\x05epoch\x18\x03 \x01(\x05R\x05epoch\x12\x1b\n" +
+	"\tworker_id\x18\x04 \x01(\tR\bworkerId\x12\x1b\n" +
+	"\tpage_size\x18\x05 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x06 \x01(\tR\tpageToken\"\xd7\x01\n" +
+	"\x13GetJobTasksResponse\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12,\n" +
+	"\x05tasks\x18\x02 \x03(\v2\x16.orchestrator.TaskInfoR\x05tasks\x12&\n" +
+	"\x0fnext_page_token\x18\x03 \x01(\tR\rnextPageToken\x12\x14\n" +
+	"\x05total\x18\x04 \x01(\x05R\x05total\x12=\n" +
+	"\tdurations\x18\x05 \x01(\v2\x1f.orchestrator.TaskDurationStatsR\tdurations\"r\n" +
+	"\x11TaskDurationStats\x12\x18\n" +
+	"\asamples\x18\x01 \x01(\x05R\asamples\x12\x15\n" +
+	"\x06p50_ms\x18\x02 \x01(\x03R\x05p50Ms\x12\x15\n" +
+	"\x06p95_ms\x18\x03 \x01(\x03R\x05p95Ms\x12\x15\n" +
+	"\x06p99_ms\x18\x04 \x01(\x03R\x05p99Ms\"\x9c\x05\n" +
+	"\bTaskInfo\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x1b\n" +
+	"\tworker_id\x18\x03 \x01(\tR\bworkerId\x12\x14\n" +
+	"\x05epoch\x18\x04 \x01(\x05R\x05epoch\x12\x1f\n" +
+	"\vbatch_start\x18\x05 \x01(\x05R\n" +
+	"batchStart\x12\x1b\n" +
+	"\tbatch_end\x18\x06 \x01(\x05R\bbatchEnd\x12\x12\n" +
+	"\x04loss\x18\a \x01(\x01R\x04loss\x12\x1a\n" +
+	"\baccuracy\x18\b \x01(\x01R\baccuracy\x12\x1a\n" +
+	"\battempts\x18\t \x01(\x05R\battempts\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\n" +
+	" \x01(\tR\tlastError\x12\"\n" +
+	"\rcreated_at_ms\x18\v \x01(\x03R\vcreatedAtMs\x12$\n" +
+	"\x0eassigned_at_ms\x18\f \x01(\x03R\fassignedAtMs\x12&\n" +
+	"\x0fcompleted_at_ms\x18\r \x01(\x03R\rcompletedAtMs\x12-\n" +
+	"\x13lease_expires_at_ms\x18\x0e \x01(\x03R\x10leaseExpiresAtMs\x12\x17\n" +
+	"\await_ms\x18\x0f \x01(\x03R\x06waitMs\x12\x15\n" +
+	"\x06run_ms\x18\x10 \x01(\x03R\x05runMs\x12\x1e\n" +
+	"\n" +
+	"straggling\x18\x11 \x01(\bR\n" +
+	"straggling\x126\n" +
+	"\bprogress\x18\x12 \x01(\v2\x1a.orchestrator.TaskProgressR\bprogress\x12F\n" +
+	"\x0eresource_usage\x18\x13 \x01(\v2\x1f.orchestrator.TaskResourceUsageR\rresourceUsage\x12\x12\n" +
+	"\x04type\x18\x14 \x01(\tR\x04type\"\xa4\x01\n" +
+	"\x13JobStatusTransition\x12*\n" +
+	"\x04from\x18\x01 \x01(\x0e2\x16.orchestrator.JobStateR\x04from\x12&\n" +
+	"\x02to\x18\x02 \x01(\x0e2\x16.orchestrator.JobStateR\x02to\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12!\n" +
+	"\ftimestamp_ms\x18\x04 \x01(\x03R\vtimestampMs\"\x99\x01\n" +
+	"\fEpochMetrics\x12\x14\n" +
+	"\x05epoch\x18\x01 \x01(\x05R\x05epoch\x12\x12\n" +
+	"\x04loss\x18\x02 \x01(\x01R\x04loss\x12\x1a\n" +
+	"\baccuracy\x18\x03 \x01(\x01R\baccuracy\x12'\n" +
+	"\x0fcompleted_tasks\x18\x04 \x01(\x05R\x0ecompletedTasks\x12\x1a\n" +
+	"\bcomplete\x18\x05 \x01(\bR\bcomplete\".\n" +
+	"\x15WatchJobStatusRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\"0\n" +
 	"\x11AssignTaskRequest\x12\x1b\n" +
-	"\tworker_id\x18\x01 \x01(\tR\bworkerId\"\xff\x02\n" +
+	"\tworker_id\x18\x01 \x01(\tR\bworkerId\"\xea\x04\n" +
 	"\x12AssignTaskResponse\x12\x17\n" +
 	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x15\n" +
 	"\x06job_id\x18\x02 \x01(\tR\x05jobId\x12\x1d\n" +
@@ -1056,10 +6606,22 @@ const file_orchestrator_proto_rawDesc = "" +
 	"\x05epoch\x18\x06 \x01(\x05R\x05epoch\x12\x1f\n" +
 	"\vbatch_start\x18\a \x01(\x05R\n" +
 	"batchStart\x12\x1b\n" +
-	"\tbatch_end\x18\b \x01(\x05R\bbatchEnd\x1aB\n" +
+	"\tbatch_end\x18\b \x01(\x05R\bbatchEnd\x12#\n" +
+	"\rmodel_weights\x18\t \x01(\fR\fmodelWeights\x12\x14\n" +
+	"\x05image\x18\n" +
+	" \x01(\tR\x05image\x12\x1b\n" +
+	"\tgpu_count\x18\v \x01(\x05R\bgpuCount\x12\x1b\n" +
+	"\tcpu_cores\x18\f \x01(\x05R\bcpuCores\x12\x1b\n" +
+	"\tmemory_mb\x18\r \x01(\x03R\bmemoryMb\x12 \n" +
+	"\fqueued_at_ms\x18\x0e \x01(\x03R\n" +
+	"queuedAtMs\x12\x1b\n" +
+	"\ttask_type\x18\x0f \x01(\tR\btaskType\x12\x18\n" +
+	"\aattempt\x18\x10 \x01(\x05R\aattempt\x1aB\n" +
 	"\x14HyperparametersEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xf8\x01\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"0\n" +
+	"\x11TaskStreamRequest\x12\x1b\n" +
+	"\tworker_id\x18\x01 \x01(\tR\bworkerId\"\xe6\x03\n" +
 	"\x15TaskCompletionRequest\x12\x17\n" +
 	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x15\n" +
 	"\x06job_id\x18\x02 \x01(\tR\x05jobId\x12\x1b\n" +
@@ -1068,27 +6630,131 @@ const file_orchestrator_proto_rawDesc = "" +
 	"\rerror_message\x18\x05 \x01(\tR\ferrorMessage\x12\x12\n" +
 	"\x04loss\x18\x06 \x01(\x01R\x04loss\x12\x1a\n" +
 	"\baccuracy\x18\a \x01(\x01R\baccuracy\x12#\n" +
-	"\rmodel_weights\x18\b \x01(\fR\fmodelWeights\"V\n" +
+	"\rmodel_weights\x18\b \x01(\fR\fmodelWeights\x12E\n" +
+	"\rdataset_cache\x18\t \x01(\x0e2 .orchestrator.DatasetCacheResultR\fdatasetCache\x12C\n" +
+	"\x11model_weights_ref\x18\n" +
+	" \x01(\v2\x17.orchestrator.ObjectRefR\x0fmodelWeightsRef\x12F\n" +
+	"\x0eresource_usage\x18\v \x01(\v2\x1f.orchestrator.TaskResourceUsageR\rresourceUsage\x12\x18\n" +
+	"\aattempt\x18\f \x01(\x05R\aattempt\"\x89\x02\n" +
+	"\x11TaskResourceUsage\x12&\n" +
+	"\x0fcpu_limit_cores\x18\x01 \x01(\x01R\rcpuLimitCores\x12,\n" +
+	"\x12memory_limit_bytes\x18\x02 \x01(\x03R\x10memoryLimitBytes\x12*\n" +
+	"\x11peak_memory_bytes\x18\x03 \x01(\x03R\x0fpeakMemoryBytes\x12\x1f\n" +
+	"\vcpu_seconds\x18\x04 \x01(\x01R\n" +
+	"cpuSeconds\x122\n" +
+	"\x15cpu_throttled_seconds\x18\x05 \x01(\x01R\x13cpuThrottledSeconds\x12\x1d\n" +
+	"\n" +
+	"oom_killed\x18\x06 \x01(\bR\toomKilled\"l\n" +
+	"\tObjectRef\x12\x16\n" +
+	"\x06bucket\x18\x01 \x01(\tR\x06bucket\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12\x16\n" +
+	"\x06sha256\x18\x03 \x01(\tR\x06sha256\x12\x1d\n" +
+	"\n" +
+	"size_bytes\x18\x04 \x01(\x03R\tsizeBytes\"t\n" +
 	"\x16TaskCompletionResponse\x12\"\n" +
 	"\facknowledged\x18\x01 \x01(\bR\facknowledged\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"p\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1c\n" +
+	"\tduplicate\x18\x03 \x01(\bR\tduplicate\"\xf4\x01\n" +
+	"\x13TaskProgressRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x15\n" +
+	"\x06job_id\x18\x02 \x01(\tR\x05jobId\x12\x1b\n" +
+	"\tworker_id\x18\x03 \x01(\tR\bworkerId\x12!\n" +
+	"\fbatches_done\x18\x04 \x01(\x05R\vbatchesDone\x12#\n" +
+	"\rbatches_total\x18\x05 \x01(\x05R\fbatchesTotal\x12\x12\n" +
+	"\x04loss\x18\x06 \x01(\x01R\x04loss\x12\x1a\n" +
+	"\baccuracy\x18\a \x01(\x01R\baccuracy\x12\x18\n" +
+	"\astarted\x18\b \x01(\bR\astarted\"h\n" +
+	"\x14TaskProgressResponse\x12\"\n" +
+	"\facknowledged\x18\x01 \x01(\bR\facknowledged\x12\x12\n" +
+	"\x04stop\x18\x02 \x01(\bR\x04stop\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"\xe2\x01\n" +
+	"\fTaskProgress\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x1b\n" +
+	"\tworker_id\x18\x02 \x01(\tR\bworkerId\x12!\n" +
+	"\fbatches_done\x18\x03 \x01(\x05R\vbatchesDone\x12#\n" +
+	"\rbatches_total\x18\x04 \x01(\x05R\fbatchesTotal\x12\x12\n" +
+	"\x04loss\x18\x05 \x01(\x01R\x04loss\x12\x1a\n" +
+	"\baccuracy\x18\x06 \x01(\x01R\baccuracy\x12$\n" +
+	"\x0ereported_at_ms\x18\a \x01(\x03R\freportedAtMs\"y\n" +
+	"\x12ReleaseTaskRequest\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x15\n" +
+	"\x06job_id\x18\x02 \x01(\tR\x05jobId\x12\x1b\n" +
+	"\tworker_id\x18\x03 \x01(\tR\bworkerId\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\"K\n" +
+	"\x13ReleaseTaskResponse\x12\x1a\n" +
+	"\breleased\x18\x01 \x01(\bR\breleased\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\x8c\x02\n" +
+	"\fTaskLogEntry\x12\x17\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x15\n" +
+	"\x06job_id\x18\x02 \x01(\tR\x05jobId\x12!\n" +
+	"\ftimestamp_ms\x18\x03 \x01(\x03R\vtimestampMs\x12\x14\n" +
+	"\x05level\x18\x04 \x01(\tR\x05level\x12\x18\n" +
+	"\amessage\x18\x05 \x01(\tR\amessage\x12>\n" +
+	"\x06fields\x18\x06 \x03(\v2&.orchestrator.TaskLogEntry.FieldsEntryR\x06fields\x1a9\n" +
+	"\vFieldsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"h\n" +
+	"\x13ShipTaskLogsRequest\x12\x1b\n" +
+	"\tworker_id\x18\x01 \x01(\tR\bworkerId\x124\n" +
+	"\aentries\x18\x02 \x03(\v2\x1a.orchestrator.TaskLogEntryR\aentries\"2\n" +
+	"\x14ShipTaskLogsResponse\x12\x1a\n" +
+	"\baccepted\x18\x01 \x01(\x05R\baccepted\"p\n" +
 	"\x11JobMetricsRequest\x12\x15\n" +
 	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x14\n" +
 	"\x05epoch\x18\x02 \x01(\x05R\x05epoch\x12\x12\n" +
 	"\x04loss\x18\x03 \x01(\x01R\x04loss\x12\x1a\n" +
 	"\baccuracy\x18\x04 \x01(\x01R\baccuracy\".\n" +
 	"\x12JobMetricsResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\")\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"i\n" +
+	"\x18JobMetricsHistoryRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\x12\"\n" +
+	"\rsince_unix_ms\x18\x03 \x01(\x03R\vsinceUnixMs\"h\n" +
+	"\x19JobMetricsHistoryResponse\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x124\n" +
+	"\asamples\x18\x02 \x03(\v2\x1a.orchestrator.MetricSampleR\asamples\"\xa4\x01\n" +
+	"\fMetricSample\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12\x14\n" +
+	"\x05epoch\x18\x02 \x01(\x05R\x05epoch\x12\x17\n" +
+	"\atask_id\x18\x03 \x01(\tR\x06taskId\x12\x12\n" +
+	"\x04loss\x18\x04 \x01(\x01R\x04loss\x12\x1a\n" +
+	"\baccuracy\x18\x05 \x01(\x01R\baccuracy\x12!\n" +
+	"\ftimestamp_ms\x18\x06 \x01(\x03R\vtimestampMs\"=\n" +
+	"\x10JobEventsRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\"Z\n" +
+	"\x11JobEventsResponse\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12.\n" +
+	"\x06events\x18\x02 \x03(\v2\x16.orchestrator.JobEventR\x06events\"\x91\x01\n" +
+	"\bJobEvent\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x17\n" +
+	"\atask_id\x18\x03 \x01(\tR\x06taskId\x12\x1b\n" +
+	"\tworker_id\x18\x04 \x01(\tR\bworkerId\x12!\n" +
+	"\ftimestamp_ms\x18\x05 \x01(\x03R\vtimestampMs\")\n" +
 	"\x10CancelJobRequest\x12\x15\n" +
 	"\x06job_id\x18\x01 \x01(\tR\x05jobId\"p\n" +
 	"\x11CancelJobResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\x12'\n" +
-	"\x0fprevious_status\x18\x03 \x01(\tR\x0epreviousStatus\"\x17\n" +
+	"\x0fprevious_status\x18\x03 \x01(\tR\x0epreviousStatus\"(\n" +
+	"\x0fPauseJobRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\"o\n" +
+	"\x10PauseJobResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12'\n" +
+	"\x0fprevious_status\x18\x03 \x01(\tR\x0epreviousStatus\")\n" +
+	"\x10ResumeJobRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\"\x93\x01\n" +
+	"\x11ResumeJobResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12'\n" +
+	"\x0fprevious_status\x18\x03 \x01(\tR\x0epreviousStatus\x12!\n" +
+	"\fresume_epoch\x18\x04 \x01(\x05R\vresumeEpoch\"\x17\n" +
 	"\x15WorkerActivityRequest\"q\n" +
 	"\x16WorkerActivityResponse\x122\n" +
 	"\aworkers\x18\x01 \x03(\v2\x18.orchestrator.WorkerInfoR\aworkers\x12#\n" +
-	"\rtotal_workers\x18\x02 \x01(\x05R\ftotalWorkers\"\xe6\x01\n" +
+	"\rtotal_workers\x18\x02 \x01(\x05R\ftotalWorkers\"\xaf\a\n" +
 	"\n" +
 	"WorkerInfo\x12\x1b\n" +
 	"\tworker_id\x18\x01 \x01(\tR\bworkerId\x12\x16\n" +
@@ -1096,16 +6762,245 @@ const file_orchestrator_proto_rawDesc = "" +
 	"\x0fcurrent_task_id\x18\x03 \x01(\tR\rcurrentTaskId\x12$\n" +
 	"\x0ecurrent_job_id\x18\x04 \x01(\tR\fcurrentJobId\x12'\n" +
 	"\x0ftasks_completed\x18\x05 \x01(\x05R\x0etasksCompleted\x12,\n" +
-	"\x12last_activity_time\x18\x06 \x01(\x03R\x10lastActivityTime2\xff\x04\n" +
+	"\x12last_activity_time\x18\x06 \x01(\x03R\x10lastActivityTime\x12\x1a\n" +
+	"\bhostname\x18\a \x01(\tR\bhostname\x12\x18\n" +
+	"\aaddress\x18\b \x01(\tR\aaddress\x12.\n" +
+	"\x13last_heartbeat_time\x18\t \x01(\x03R\x11lastHeartbeatTime\x12#\n" +
+	"\rregistered_at\x18\n" +
+	" \x01(\x03R\fregisteredAt\x12D\n" +
+	"\fcapabilities\x18\v \x01(\v2 .orchestrator.WorkerCapabilitiesR\fcapabilities\x12'\n" +
+	"\x0fcached_datasets\x18\f \x03(\tR\x0ecachedDatasets\x12+\n" +
+	"\x11quarantine_reason\x18\r \x01(\tR\x10quarantineReason\x120\n" +
+	"\x14max_concurrent_tasks\x18\x0e \x01(\x05R\x12maxConcurrentTasks\x12&\n" +
+	"\x0fin_flight_tasks\x18\x0f \x01(\x05R\rinFlightTasks\x12F\n" +
+	"\x0etask_durations\x18\x10 \x01(\v2\x1f.orchestrator.TaskDurationStatsR\rtaskDurations\x12,\n" +
+	"\x04load\x18\x11 \x01(\v2\x18.orchestrator.WorkerLoadR\x04load\x12-\n" +
+	"\x13load_reported_at_ms\x18\x12 \x01(\x03R\x10loadReportedAtMs\x12%\n" +
+	"\x0euptime_seconds\x18\x13 \x01(\x03R\ruptimeSeconds\x12,\n" +
+	"\x12dataset_cache_hits\x18\x14 \x01(\x05R\x10datasetCacheHits\x120\n" +
+	"\x14dataset_cache_misses\x18\x15 \x01(\x05R\x12datasetCacheMisses\x12\x1a\n" +
+	"\bexecutor\x18\x16 \x01(\tR\bexecutor\"\xa6\x02\n" +
+	"\x12WorkerCapabilities\x12\x1b\n" +
+	"\tcpu_cores\x18\x01 \x01(\x05R\bcpuCores\x12\x1b\n" +
+	"\tmemory_mb\x18\x02 \x01(\x03R\bmemoryMb\x12\x1b\n" +
+	"\tgpu_count\x18\x03 \x01(\x05R\bgpuCount\x12\x19\n" +
+	"\bgpu_type\x18\x04 \x01(\tR\agpuType\x12D\n" +
+	"\x06labels\x18\x05 \x03(\v2,.orchestrator.WorkerCapabilities.LabelsEntryR\x06labels\x12\x1d\n" +
+	"\n" +
+	"task_types\x18\x06 \x03(\tR\ttaskTypes\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xf2\x02\n" +
+	"\x15RegisterWorkerRequest\x12\x1b\n" +
+	"\tworker_id\x18\x01 \x01(\tR\bworkerId\x12\x1a\n" +
+	"\bhostname\x18\x02 \x01(\tR\bhostname\x12\x18\n" +
+	"\aaddress\x18\x03 \x01(\tR\aaddress\x12D\n" +
+	"\fcapabilities\x18\x04 \x01(\v2 .orchestrator.WorkerCapabilitiesR\fcapabilities\x12'\n" +
+	"\x0fcached_datasets\x18\x05 \x03(\tR\x0ecachedDatasets\x120\n" +
+	"\x14max_concurrent_tasks\x18\x06 \x01(\x05R\x12maxConcurrentTasks\x12\"\n" +
+	"\rstarted_at_ms\x18\a \x01(\x03R\vstartedAtMs\x12\x1a\n" +
+	"\bexecutor\x18\b \x01(\tR\bexecutor\x12%\n" +
+	"\x0eprefetch_tasks\x18\t \x01(\x05R\rprefetchTasks\"\xd6\x01\n" +
+	"\x16RegisterWorkerResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12<\n" +
+	"\x1aheartbeat_interval_seconds\x18\x03 \x01(\x05R\x18heartbeatIntervalSeconds\x12#\n" +
+	"\rsession_token\x18\x04 \x01(\tR\fsessionToken\x12%\n" +
+	"\x0eprefetch_tasks\x18\x05 \x01(\x05R\rprefetchTasks\"N\n" +
+	"\x17DeregisterWorkerRequest\x12\x1b\n" +
+	"\tworker_id\x18\x01 \x01(\tR\bworkerId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"\x7f\n" +
+	"\x18DeregisterWorkerResponse\x12\"\n" +
+	"\fderegistered\x18\x01 \x01(\bR\fderegistered\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12%\n" +
+	"\x0ereleased_tasks\x18\x03 \x01(\x05R\rreleasedTasks\"\xe7\x01\n" +
+	"\x10HeartbeatRequest\x12\x1b\n" +
+	"\tworker_id\x18\x01 \x01(\tR\bworkerId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12#\n" +
+	"\rcurrent_tasks\x18\x03 \x01(\x05R\fcurrentTasks\x12'\n" +
+	"\x0fcached_datasets\x18\x04 \x03(\tR\x0ecachedDatasets\x12,\n" +
+	"\x04load\x18\x05 \x01(\v2\x18.orchestrator.WorkerLoadR\x04load\x12\"\n" +
+	"\rstarted_at_ms\x18\x06 \x01(\x03R\vstartedAtMs\"\xba\x04\n" +
+	"\n" +
+	"WorkerLoad\x12\x1f\n" +
+	"\vcpu_percent\x18\x01 \x01(\x01R\n" +
+	"cpuPercent\x12%\n" +
+	"\x0ememory_percent\x18\x02 \x01(\x01R\rmemoryPercent\x12!\n" +
+	"\fload_average\x18\x03 \x01(\x01R\vloadAverage\x12*\n" +
+	"\x11memory_used_bytes\x18\x04 \x01(\x04R\x0fmemoryUsedBytes\x12,\n" +
+	"\x12memory_total_bytes\x18\x05 \x01(\x04R\x10memoryTotalBytes\x12!\n" +
+	"\fdisk_percent\x18\x06 \x01(\x01R\vdiskPercent\x12&\n" +
+	"\x0fdisk_used_bytes\x18\a \x01(\x04R\rdiskUsedBytes\x12(\n" +
+	"\x10disk_total_bytes\x18\b \x01(\x04R\x0ediskTotalBytes\x128\n" +
+	"\x19net_sent_bytes_per_second\x18\t \x01(\x01R\x15netSentBytesPerSecond\x128\n" +
+	"\x19net_recv_bytes_per_second\x18\n" +
+	" \x01(\x01R\x15netRecvBytesPerSecond\x12)\n" +
+	"\x04gpus\x18\v \x03(\v2\x15.orchestrator.GpuLoadR\x04gpus\x12\"\n" +
+	"\fbackpressure\x18\f \x01(\bR\fbackpressure\x12/\n" +
+	"\x13backpressure_reason\x18\r \x01(\tR\x12backpressureReason\"\xeb\x01\n" +
+	"\aGpuLoad\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x12\n" +
+	"\x04uuid\x18\x02 \x01(\tR\x04uuid\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12/\n" +
+	"\x13utilization_percent\x18\x04 \x01(\x01R\x12utilizationPercent\x12*\n" +
+	"\x11memory_used_bytes\x18\x05 \x01(\x04R\x0fmemoryUsedBytes\x12,\n" +
+	"\x12memory_total_bytes\x18\x06 \x01(\x04R\x10memoryTotalBytes\x12\x17\n" +
+	"\atask_id\x18\a \x01(\tR\x06taskId\"W\n" +
+	"\x11HeartbeatResponse\x12\"\n" +
+	"\facknowledged\x18\x01 \x01(\bR\facknowledged\x12\x1e\n" +
+	"\n" +
+	"registered\x18\x02 \x01(\bR\n" +
+	"registered\"8\n" +
+	"\x19UnquarantineWorkerRequest\x12\x1b\n" +
+	"\tworker_id\x18\x01 \x01(\tR\bworkerId\"P\n" +
+	"\x1aUnquarantineWorkerResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"&\n" +
+	"\fDrainRequest\x12\x16\n" +
+	"\x06reason\x18\x01 \x01(\tR\x06reason\"k\n" +
+	"\rDrainResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12&\n" +
+	"\x0fin_flight_tasks\x18\x03 \x01(\x05R\rinFlightTasks\"\xe1\x01\n" +
+	"\tUserUsage\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"task_hours\x18\x02 \x01(\x01R\ttaskHours\x12'\n" +
+	"\x0ftasks_completed\x18\x03 \x01(\x05R\x0etasksCompleted\x12%\n" +
+	"\x0ejobs_submitted\x18\x04 \x01(\x05R\rjobsSubmitted\x12(\n" +
+	"\x10quota_task_hours\x18\x05 \x01(\x01R\x0equotaTaskHours\x12\"\n" +
+	"\rupdated_at_ms\x18\x06 \x01(\x03R\vupdatedAtMs\".\n" +
+	"\x13GetUserUsageRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"E\n" +
+	"\x14GetUserUsageResponse\x12-\n" +
+	"\x05users\x18\x01 \x03(\v2\x17.orchestrator.UserUsageR\x05users\"r\n" +
+	"\x13SetUserQuotaRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12-\n" +
+	"\x10task_hours_quota\x18\x02 \x01(\x01H\x00R\x0etaskHoursQuota\x88\x01\x01B\x13\n" +
+	"\x11_task_hours_quota\"y\n" +
+	"\x14SetUserQuotaResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12-\n" +
+	"\x05usage\x18\x03 \x01(\v2\x17.orchestrator.UserUsageR\x05usage\"\x9f\x01\n" +
+	"\x12RequeueTaskRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x17\n" +
+	"\atask_id\x18\x02 \x01(\tR\x06taskId\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12\x1a\n" +
+	"\boperator\x18\x04 \x01(\tR\boperator\x12%\n" +
+	"\x0ereset_attempts\x18\x05 \x01(\bR\rresetAttempts\"`\n" +
+	"\x13ForceFailJobRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12\x1a\n" +
+	"\boperator\x18\x03 \x01(\tR\boperator\"\xb1\x01\n" +
+	"\x12DrainWorkerRequest\x12\x1b\n" +
+	"\tworker_id\x18\x01 \x01(\tR\bworkerId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12\x1a\n" +
+	"\boperator\x18\x03 \x01(\tR\boperator\x12*\n" +
+	"\x11requeue_in_flight\x18\x04 \x01(\bR\x0frequeueInFlight\x12\x1e\n" +
+	"\n" +
+	"deregister\x18\x05 \x01(\bR\n" +
+	"deregister\"j\n" +
+	"\rAdminResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12%\n" +
+	"\x0erequeued_tasks\x18\x03 \x01(\x05R\rrequeuedTasks\"K\n" +
+	"\x15ExportSnapshotRequest\x12\x16\n" +
+	"\x06reason\x18\x01 \x01(\tR\x06reason\x12\x1a\n" +
+	"\boperator\x18\x02 \x01(\tR\boperator\"{\n" +
+	"\x15ImportSnapshotRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12\x1a\n" +
+	"\boperator\x18\x03 \x01(\tR\boperator\x12\x1c\n" +
+	"\toverwrite\x18\x04 \x01(\bR\toverwrite\"\x93\x02\n" +
+	"\x10SnapshotResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x10\n" +
+	"\x03key\x18\x03 \x01(\tR\x03key\x12%\n" +
+	"\x0eformat_version\x18\x04 \x01(\x05R\rformatVersion\x12\"\n" +
+	"\rcreated_at_ms\x18\x05 \x01(\x03R\vcreatedAtMs\x12\x1d\n" +
+	"\n" +
+	"size_bytes\x18\x06 \x01(\x03R\tsizeBytes\x12\x12\n" +
+	"\x04jobs\x18\a \x01(\x05R\x04jobs\x12\x18\n" +
+	"\aworkers\x18\b \x01(\x05R\aworkers\x12!\n" +
+	"\fskipped_jobs\x18\t \x01(\x05R\vskippedJobs\"\x17\n" +
+	"\x15ClusterSummaryRequest\"\x9f\x06\n" +
+	"\x16ClusterSummaryResponse\x12\\\n" +
+	"\x0ejobs_by_status\x18\x01 \x03(\v26.orchestrator.ClusterSummaryResponse.JobsByStatusEntryR\fjobsByStatus\x12!\n" +
+	"\fqueued_tasks\x18\x02 \x01(\x05R\vqueuedTasks\x12#\n" +
+	"\rrunning_tasks\x18\x03 \x01(\x05R\frunningTasks\x12e\n" +
+	"\x11workers_by_status\x18\x04 \x03(\v29.orchestrator.ClusterSummaryResponse.WorkersByStatusEntryR\x0fworkersByStatus\x12/\n" +
+	"\x13quarantined_workers\x18\x05 \x01(\x05R\x12quarantinedWorkers\x12)\n" +
+	"\x10draining_workers\x18\x06 \x01(\x05R\x0fdrainingWorkers\x12%\n" +
+	"\x0ewindow_seconds\x18\a \x01(\x03R\rwindowSeconds\x12'\n" +
+	"\x0ftasks_completed\x18\b \x01(\x05R\x0etasksCompleted\x12%\n" +
+	"\x0ejobs_completed\x18\t \x01(\x05R\rjobsCompleted\x12\x1f\n" +
+	"\vjobs_failed\x18\n" +
+	" \x01(\x05R\n" +
+	"jobsFailed\x12(\n" +
+	"\x10tasks_per_minute\x18\v \x01(\x01R\x0etasksPerMinute\x12-\n" +
+	"\x13avg_job_duration_ms\x18\f \x01(\x03R\x10avgJobDurationMs\x12&\n" +
+	"\x0fgenerated_at_ms\x18\r \x01(\x03R\rgeneratedAtMs\x1a?\n" +
+	"\x11JobsByStatusEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01\x1aB\n" +
+	"\x14WorkersByStatusEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x05R\x05value:\x028\x01*\xe6\x02\n" +
+	"\bJobState\x12\x19\n" +
+	"\x15JOB_STATE_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11JOB_STATE_PENDING\x10\x01\x12\x14\n" +
+	"\x10JOB_STATE_QUEUED\x10\x02\x12\x15\n" +
+	"\x11JOB_STATE_RUNNING\x10\x03\x12\x1f\n" +
+	"\x1bJOB_STATE_PENDING_RESOURCES\x10\x04\x12\x14\n" +
+	"\x10JOB_STATE_PAUSED\x10\x05\x12\x17\n" +
+	"\x13JOB_STATE_COMPLETED\x10\x06\x12\x14\n" +
+	"\x10JOB_STATE_FAILED\x10\a\x12\x17\n" +
+	"\x13JOB_STATE_CANCELLED\x10\b\x12\x16\n" +
+	"\x12JOB_STATE_SHARDING\x10\t\x12\x1d\n" +
+	"\x19JOB_STATE_COMPLETED_EARLY\x10\n" +
+	"\x12\x15\n" +
+	"\x11JOB_STATE_BLOCKED\x10\v\x12\x15\n" +
+	"\x11JOB_STATE_STALLED\x10\f\x12\x17\n" +
+	"\x13JOB_STATE_DELEGATED\x10\r*b\n" +
+	"\x12DatasetCacheResult\x12\x1d\n" +
+	"\x19DATASET_CACHE_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11DATASET_CACHE_HIT\x10\x01\x12\x16\n" +
+	"\x12DATASET_CACHE_MISS\x10\x022\xb8\x12\n" +
 	"\x13OrchestratorService\x12X\n" +
 	"\x11CreateTrainingJob\x12 .orchestrator.TrainingJobRequest\x1a!.orchestrator.TrainingJobResponse\x12U\n" +
-	"\fGetJobStatus\x12!.orchestrator.GetJobStatusRequest\x1a\".orchestrator.GetJobStatusResponse\x12O\n" +
+	"\fGetJobStatus\x12!.orchestrator.GetJobStatusRequest\x1a\".orchestrator.GetJobStatusResponse\x12C\n" +
+	"\x06GetJob\x12\x1b.orchestrator.GetJobRequest\x1a\x1c.orchestrator.GetJobResponse\x12I\n" +
+	"\bListJobs\x12\x1d.orchestrator.ListJobsRequest\x1a\x1e.orchestrator.ListJobsResponse\x12R\n" +
+	"\vGetJobTasks\x12 .orchestrator.GetJobTasksRequest\x1a!.orchestrator.GetJobTasksResponse\x12[\n" +
+	"\x0eWatchJobStatus\x12#.orchestrator.WatchJobStatusRequest\x1a\".orchestrator.GetJobStatusResponse0\x01\x12O\n" +
 	"\n" +
-	"AssignTask\x12\x1f.orchestrator.AssignTaskRequest\x1a .orchestrator.AssignTaskResponse\x12a\n" +
-	"\x14ReportTaskCompletion\x12#.orchestrator.TaskCompletionRequest\x1a$.orchestrator.TaskCompletionResponse\x12U\n" +
+	"AssignTask\x12\x1f.orchestrator.AssignTaskRequest\x1a .orchestrator.AssignTaskResponse\x12T\n" +
+	"\vStreamTasks\x12\x1f.orchestrator.TaskStreamRequest\x1a .orchestrator.AssignTaskResponse(\x010\x01\x12a\n" +
+	"\x14ReportTaskCompletion\x12#.orchestrator.TaskCompletionRequest\x1a$.orchestrator.TaskCompletionResponse\x12R\n" +
+	"\vReleaseTask\x12 .orchestrator.ReleaseTaskRequest\x1a!.orchestrator.ReleaseTaskResponse\x12[\n" +
+	"\x12ReportTaskProgress\x12!.orchestrator.TaskProgressRequest\x1a\".orchestrator.TaskProgressResponse\x12U\n" +
+	"\fShipTaskLogs\x12!.orchestrator.ShipTaskLogsRequest\x1a\".orchestrator.ShipTaskLogsResponse\x12U\n" +
 	"\x10UpdateJobMetrics\x12\x1f.orchestrator.JobMetricsRequest\x1a .orchestrator.JobMetricsResponse\x12L\n" +
 	"\tCancelJob\x12\x1e.orchestrator.CancelJobRequest\x1a\x1f.orchestrator.CancelJobResponse\x12^\n" +
-	"\x11GetWorkerActivity\x12#.orchestrator.WorkerActivityRequest\x1a$.orchestrator.WorkerActivityResponseB8Z6github.com/tensorfleet/orchestrator/proto/orchestratorb\x06proto3"
+	"\x11GetWorkerActivity\x12#.orchestrator.WorkerActivityRequest\x1a$.orchestrator.WorkerActivityResponse\x12[\n" +
+	"\x0eRegisterWorker\x12#.orchestrator.RegisterWorkerRequest\x1a$.orchestrator.RegisterWorkerResponse\x12a\n" +
+	"\x10DeregisterWorker\x12%.orchestrator.DeregisterWorkerRequest\x1a&.orchestrator.DeregisterWorkerResponse\x12L\n" +
+	"\tHeartbeat\x12\x1e.orchestrator.HeartbeatRequest\x1a\x1f.orchestrator.HeartbeatResponse\x12g\n" +
+	"\x12UnquarantineWorker\x12'.orchestrator.UnquarantineWorkerRequest\x1a(.orchestrator.UnquarantineWorkerResponse\x12L\n" +
+	"\tResumeJob\x12\x1e.orchestrator.ResumeJobRequest\x1a\x1f.orchestrator.ResumeJobResponse\x12I\n" +
+	"\bPauseJob\x12\x1d.orchestrator.PauseJobRequest\x1a\x1e.orchestrator.PauseJobResponse\x12g\n" +
+	"\x14GetJobMetricsHistory\x12&.orchestrator.JobMetricsHistoryRequest\x1a'.orchestrator.JobMetricsHistoryResponse\x12O\n" +
+	"\fGetJobEvents\x12\x1e.orchestrator.JobEventsRequest\x1a\x1f.orchestrator.JobEventsResponse\x12@\n" +
+	"\x05Drain\x12\x1a.orchestrator.DrainRequest\x1a\x1b.orchestrator.DrainResponse\x12U\n" +
+	"\fGetUserUsage\x12!.orchestrator.GetUserUsageRequest\x1a\".orchestrator.GetUserUsageResponse\x12U\n" +
+	"\fSetUserQuota\x12!.orchestrator.SetUserQuotaRequest\x1a\".orchestrator.SetUserQuotaResponse\x12^\n" +
+	"\x11GetClusterSummary\x12#.orchestrator.ClusterSummaryRequest\x1a$.orchestrator.ClusterSummaryResponse2\xb4\x03\n" +
+	"\x18OrchestratorAdminService\x12L\n" +
+	"\vRequeueTask\x12 .orchestrator.RequeueTaskRequest\x1a\x1b.orchestrator.AdminResponse\x12N\n" +
+	"\fForceFailJob\x12!.orchestrator.ForceFailJobRequest\x1a\x1b.orchestrator.AdminResponse\x12L\n" +
+	"\vDrainWorker\x12 .orchestrator.DrainWorkerRequest\x1a\x1b.orchestrator.AdminResponse\x12U\n" +
+	"\x0eExportSnapshot\x12#.orchestrator.ExportSnapshotRequest\x1a\x1e.orchestrator.SnapshotResponse\x12U\n" +
+	"\x0eImportSnapshot\x12#.orchestrator.ImportSnapshotRequest\x1a\x1e.orchestrator.SnapshotResponseB8Z6github.com/tensorfleet/orchestrator/proto/orchestratorb\x06proto3"
 
 var (
 	file_orchestrator_proto_rawDescOnce sync.Once
@@ -1119,49 +7014,211 @@ func file_orchestrator_proto_rawDescGZIP() []byte {
 	return file_orchestrator_proto_rawDescData
 }
 
-var file_orchestrator_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_orchestrator_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_orchestrator_proto_msgTypes = make([]protoimpl.MessageInfo, 85)
 var file_orchestrator_proto_goTypes = []any{
-	(*TrainingJobRequest)(nil),     // 0: orchestrator.TrainingJobRequest
-	(*TrainingJobResponse)(nil),    // 1: orchestrator.TrainingJobResponse
-	(*GetJobStatusRequest)(nil),    // 2: orchestrator.GetJobStatusRequest
-	(*GetJobStatusResponse)(nil),   // 3: orchestrator.GetJobStatusResponse
-	(*AssignTaskRequest)(nil),      // 4: orchestrator.AssignTaskRequest
-	(*AssignTaskResponse)(nil),     // 5: orchestrator.AssignTaskResponse
-	(*TaskCompletionRequest)(nil),  // 6: orchestrator.TaskCompletionRequest
-	(*TaskCompletionResponse)(nil), // 7: orchestrator.TaskCompletionResponse
-	(*JobMetricsRequest)(nil),      // 8: orchestrator.JobMetricsRequest
-	(*JobMetricsResponse)(nil),     // 9: orchestrator.JobMetricsResponse
-	(*CancelJobRequest)(nil),       // 10: orchestrator.CancelJobRequest
-	(*CancelJobResponse)(nil),      // 11: orchestrator.CancelJobResponse
-	(*WorkerActivityRequest)(nil),  // 12: orchestrator.WorkerActivityRequest
-	(*WorkerActivityResponse)(nil), // 13: orchestrator.WorkerActivityResponse
-	(*WorkerInfo)(nil),             // 14: orchestrator.WorkerInfo
-	nil,                            // 15: orchestrator.TrainingJobRequest.HyperparametersEntry
-	nil,                            // 16: orchestrator.AssignTaskResponse.HyperparametersEntry
+	(JobState)(0),                      // 0: orchestrator.JobState
+	(DatasetCacheResult)(0),            // 1: orchestrator.DatasetCacheResult
+	(*TrainingJobRequest)(nil),         // 2: orchestrator.TrainingJobRequest
+	(*EarlyStopping)(nil),              // 3: orchestrator.EarlyStopping
+	(*ResourceRequirements)(nil),       // 4: orchestrator.ResourceRequirements
+	(*TrainingJobResponse)(nil),        // 5: orchestrator.TrainingJobResponse
+	(*GetJobStatusRequest)(nil),        // 6: orchestrator.GetJobStatusRequest
+	(*GetJobStatusResponse)(nil),       // 7: orchestrator.GetJobStatusResponse
+	(*JobInfo)(nil),                    // 8: orchestrator.JobInfo
+	(*GetJobRequest)(nil),              // 9: orchestrator.GetJobRequest
+	(*GetJobResponse)(nil),             // 10: orchestrator.GetJobResponse
+	(*ListJobsRequest)(nil),            // 11: orchestrator.ListJobsRequest
+	(*ListJobsResponse)(nil),           // 12: orchestrator.ListJobsResponse
+	(*GetJobTasksRequest)(nil),         // 13: orchestrator.GetJobTasksRequest
+	(*GetJobTasksResponse)(nil),        // 14: orchestrator.GetJobTasksResponse
+	(*TaskDurationStats)(nil),          // 15: orchestrator.TaskDurationStats
+	(*TaskInfo)(nil),                   // 16: orchestrator.TaskInfo
+	(*JobStatusTransition)(nil),        // 17: orchestrator.JobStatusTransition
+	(*EpochMetrics)(nil),               // 18: orchestrator.EpochMetrics
+	(*WatchJobStatusRequest)(nil),      // 19: orchestrator.WatchJobStatusRequest
+	(*AssignTaskRequest)(nil),          // 20: orchestrator.AssignTaskRequest
+	(*AssignTaskResponse)(nil),         // 21: orchestrator.AssignTaskResponse
+	(*TaskStreamRequest)(nil),          // 22: orchestrator.TaskStreamRequest
+	(*TaskCompletionRequest)(nil),      // 23: orchestrator.TaskCompletionRequest
+	(*TaskResourceUsage)(nil),          // 24: orchestrator.TaskResourceUsage
+	(*ObjectRef)(nil),                  // 25: orchestrator.ObjectRef
+	(*TaskCompletionResponse)(nil),     // 26: orchestrator.TaskCompletionResponse
+	(*TaskProgressRequest)(nil),        // 27: orchestrator.TaskProgressRequest
+	(*TaskProgressResponse)(nil),       // 28: orchestrator.TaskProgressResponse
+	(*TaskProgress)(nil),               // 29: orchestrator.TaskProgress
+	(*ReleaseTaskRequest)(nil),         // 30: orchestrator.ReleaseTaskRequest
+	(*ReleaseTaskResponse)(nil),        // 31: orchestrator.ReleaseTaskResponse
+	(*TaskLogEntry)(nil),               // 32: orchestrator.TaskLogEntry
+	(*ShipTaskLogsRequest)(nil),        // 33: orchestrator.ShipTaskLogsRequest
+	(*ShipTaskLogsResponse)(nil),       // 34: orchestrator.ShipTaskLogsResponse
+	(*JobMetricsRequest)(nil),          // 35: orchestrator.JobMetricsRequest
+	(*JobMetricsResponse)(nil),         // 36: orchestrator.JobMetricsResponse
+	(*JobMetricsHistoryRequest)(nil),   // 37: orchestrator.JobMetricsHistoryRequest
+	(*JobMetricsHistoryResponse)(nil),  // 38: orchestrator.JobMetricsHistoryResponse
+	(*MetricSample)(nil),               // 39: orchestrator.MetricSample
+	(*JobEventsRequest)(nil),           // 40: orchestrator.JobEventsRequest
+	(*JobEventsResponse)(nil),          // 41: orchestrator.JobEventsResponse
+	(*JobEvent)(nil),                   // 42: orchestrator.JobEvent
+	(*CancelJobRequest)(nil),           // 43: orchestrator.CancelJobRequest
+	(*CancelJobResponse)(nil),          // 44: orchestrator.CancelJobResponse
+	(*PauseJobRequest)(nil),            // 45: orchestrator.PauseJobRequest
+	(*PauseJobResponse)(nil),           // 46: orchestrator.PauseJobResponse
+	(*ResumeJobRequest)(nil),           // 47: orchestrator.ResumeJobRequest
+	(*ResumeJobResponse)(nil),          // 48: orchestrator.ResumeJobResponse
+	(*WorkerActivityRequest)(nil),      // 49: orchestrator.WorkerActivityRequest
+	(*WorkerActivityResponse)(nil),     // 50: orchestrator.WorkerActivityResponse
+	(*WorkerInfo)(nil),                 // 51: orchestrator.WorkerInfo
+	(*WorkerCapabilities)(nil),         // 52: orchestrator.WorkerCapabilities
+	(*RegisterWorkerRequest)(nil),      // 53: orchestrator.RegisterWorkerRequest
+	(*RegisterWorkerResponse)(nil),     // 54: orchestrator.RegisterWorkerResponse
+	(*DeregisterWorkerRequest)(nil),    // 55: orchestrator.DeregisterWorkerRequest
+	(*DeregisterWorkerResponse)(nil),   // 56: orchestrator.DeregisterWorkerResponse
+	(*HeartbeatRequest)(nil),           // 57: orchestrator.HeartbeatRequest
+	(*WorkerLoad)(nil),                 // 58: orchestrator.WorkerLoad
+	(*GpuLoad)(nil),                    // 59: orchestrator.GpuLoad
+	(*HeartbeatResponse)(nil),          // 60: orchestrator.HeartbeatResponse
+	(*UnquarantineWorkerRequest)(nil),  // 61: orchestrator.UnquarantineWorkerRequest
+	(*UnquarantineWorkerResponse)(nil), // 62: orchestrator.UnquarantineWorkerResponse
+	(*DrainRequest)(nil),               // 63: orchestrator.DrainRequest
+	(*DrainResponse)(nil),              // 64: orchestrator.DrainResponse
+	(*UserUsage)(nil),                  // 65: orchestrator.UserUsage
+	(*GetUserUsageRequest)(nil),        // 66: orchestrator.GetUserUsageRequest
+	(*GetUserUsageResponse)(nil),       // 67: orchestrator.GetUserUsageResponse
+	(*SetUserQuotaRequest)(nil),        // 68: orchestrator.SetUserQuotaRequest
+	(*SetUserQuotaResponse)(nil),       // 69: orchestrator.SetUserQuotaResponse
+	(*RequeueTaskRequest)(nil),         // 70: orchestrator.RequeueTaskRequest
+	(*ForceFailJobRequest)(nil),        // 71: orchestrator.ForceFailJobRequest
+	(*DrainWorkerRequest)(nil),         // 72: orchestrator.DrainWorkerRequest
+	(*AdminResponse)(nil),              // 73: orchestrator.AdminResponse
+	(*ExportSnapshotRequest)(nil),      // 74: orchestrator.ExportSnapshotRequest
+	(*ImportSnapshotRequest)(nil),      // 75: orchestrator.ImportSnapshotRequest
+	(*SnapshotResponse)(nil),           // 76: orchestrator.SnapshotResponse
+	(*ClusterSummaryRequest)(nil),      // 77: orchestrator.ClusterSummaryRequest
+	(*ClusterSummaryResponse)(nil),     // 78: orchestrator.ClusterSummaryResponse
+	nil,                                // 79: orchestrator.TrainingJobRequest.HyperparametersEntry
+	nil,                                // 80: orchestrator.ResourceRequirements.LabelsEntry
+	nil,                                // 81: orchestrator.JobInfo.HyperparametersEntry
+	nil,                                // 82: orchestrator.AssignTaskResponse.HyperparametersEntry
+	nil,                                // 83: orchestrator.TaskLogEntry.FieldsEntry
+	nil,                                // 84: orchestrator.WorkerCapabilities.LabelsEntry
+	nil,                                // 85: orchestrator.ClusterSummaryResponse.JobsByStatusEntry
+	nil,                                // 86: orchestrator.ClusterSummaryResponse.WorkersByStatusEntry
 }
 var file_orchestrator_proto_depIdxs = []int32{
-	15, // 0: orchestrator.TrainingJobRequest.hyperparameters:type_name -> orchestrator.TrainingJobRequest.HyperparametersEntry
-	16, // 1: orchestrator.AssignTaskResponse.hyperparameters:type_name -> orchestrator.AssignTaskResponse.HyperparametersEntry
-	14, // 2: orchestrator.WorkerActivityResponse.workers:type_name -> orchestrator.WorkerInfo
-	0,  // 3: orchestrator.OrchestratorService.CreateTrainingJob:input_type -> orchestrator.TrainingJobRequest
-	2,  // 4: orchestrator.OrchestratorService.GetJobStatus:input_type -> orchestrator.GetJobStatusRequest
-	4,  // 5: orchestrator.OrchestratorService.AssignTask:input_type -> orchestrator.AssignTaskRequest
-	6,  // 6: orchestrator.OrchestratorService.ReportTaskCompletion:input_type -> orchestrator.TaskCompletionRequest
-	8,  // 7: orchestrator.OrchestratorService.UpdateJobMetrics:input_type -> orchestrator.JobMetricsRequest
-	10, // 8: orchestrator.OrchestratorService.CancelJob:input_type -> orchestrator.CancelJobRequest
-	12, // 9: orchestrator.OrchestratorService.GetWorkerActivity:input_type -> orchestrator.WorkerActivityRequest
-	1,  // 10: orchestrator.OrchestratorService.CreateTrainingJob:output_type -> orchestrator.TrainingJobResponse
-	3,  // 11: orchestrator.OrchestratorService.GetJobStatus:output_type -> orchestrator.GetJobStatusResponse
-	5,  // 12: orchestrator.OrchestratorService.AssignTask:output_type -> orchestrator.AssignTaskResponse
-	7,  // 13: orchestrator.OrchestratorService.ReportTaskCompletion:output_type -> orchestrator.TaskCompletionResponse
-	9,  // 14: orchestrator.OrchestratorService.UpdateJobMetrics:output_type -> orchestrator.JobMetricsResponse
-	11, // 15: orchestrator.OrchestratorService.CancelJob:output_type -> orchestrator.CancelJobResponse
-	13, // 16: orchestrator.OrchestratorService.GetWorkerActivity:output_type -> orchestrator.WorkerActivityResponse
-	10, // [10:17] is the sub-list for method output_type
-	3,  // [3:10] is the sub-list for method input_type
-	3,  // [3:3] is the sub-list for extension type_name
-	3,  // [3:3] is the sub-list for extension extendee
-	0,  // [0:3] is the sub-list for field type_name
+	79, // 0: orchestrator.TrainingJobRequest.hyperparameters:type_name -> orchestrator.TrainingJobRequest.HyperparametersEntry
+	4,  // 1: orchestrator.TrainingJobRequest.requirements:type_name -> orchestrator.ResourceRequirements
+	3,  // 2: orchestrator.TrainingJobRequest.early_stopping:type_name -> orchestrator.EarlyStopping
+	80, // 3: orchestrator.ResourceRequirements.labels:type_name -> orchestrator.ResourceRequirements.LabelsEntry
+	18, // 4: orchestrator.GetJobStatusResponse.epoch_metrics:type_name -> orchestrator.EpochMetrics
+	0,  // 5: orchestrator.GetJobStatusResponse.state:type_name -> orchestrator.JobState
+	17, // 6: orchestrator.GetJobStatusResponse.transitions:type_name -> orchestrator.JobStatusTransition
+	15, // 7: orchestrator.GetJobStatusResponse.task_durations:type_name -> orchestrator.TaskDurationStats
+	29, // 8: orchestrator.GetJobStatusResponse.running_tasks:type_name -> orchestrator.TaskProgress
+	18, // 9: orchestrator.GetJobStatusResponse.validation_metrics:type_name -> orchestrator.EpochMetrics
+	0,  // 10: orchestrator.JobInfo.state:type_name -> orchestrator.JobState
+	81, // 11: orchestrator.JobInfo.hyperparameters:type_name -> orchestrator.JobInfo.HyperparametersEntry
+	18, // 12: orchestrator.JobInfo.epoch_metrics:type_name -> orchestrator.EpochMetrics
+	17, // 13: orchestrator.JobInfo.transitions:type_name -> orchestrator.JobStatusTransition
+	15, // 14: orchestrator.JobInfo.task_durations:type_name -> orchestrator.TaskDurationStats
+	8,  // 15: orchestrator.GetJobResponse.job:type_name -> orchestrator.JobInfo
+	0,  // 16: orchestrator.ListJobsRequest.states:type_name -> orchestrator.JobState
+	8,  // 17: orchestrator.ListJobsResponse.jobs:type_name -> orchestrator.JobInfo
+	16, // 18: orchestrator.GetJobTasksResponse.tasks:type_name -> orchestrator.TaskInfo
+	15, // 19: orchestrator.GetJobTasksResponse.durations:type_name -> orchestrator.TaskDurationStats
+	29, // 20: orchestrator.TaskInfo.progress:type_name -> orchestrator.TaskProgress
+	24, // 21: orchestrator.TaskInfo.resource_usage:type_name -> orchestrator.TaskResourceUsage
+	0,  // 22: orchestrator.JobStatusTransition.from:type_name -> orchestrator.JobState
+	0,  // 23: orchestrator.JobStatusTransition.to:type_name -> orchestrator.JobState
+	82, // 24: orchestrator.AssignTaskResponse.hyperparameters:type_name -> orchestrator.AssignTaskResponse.HyperparametersEntry
+	1,  // 25: orchestrator.TaskCompletionRequest.dataset_cache:type_name -> orchestrator.DatasetCacheResult
+	25, // 26: orchestrator.TaskCompletionRequest.model_weights_ref:type_name -> orchestrator.ObjectRef
+	24, // 27: orchestrator.TaskCompletionRequest.resource_usage:type_name -> orchestrator.TaskResourceUsage
+	83, // 28: orchestrator.TaskLogEntry.fields:type_name -> orchestrator.TaskLogEntry.FieldsEntry
+	32, // 29: orchestrator.ShipTaskLogsRequest.entries:type_name -> orchestrator.TaskLogEntry
+	39, // 30: orchestrator.JobMetricsHistoryResponse.samples:type_name -> orchestrator.MetricSample
+	42, // 31: orchestrator.JobEventsResponse.events:type_name -> orchestrator.JobEvent
+	51, // 32: orchestrator.WorkerActivityResponse.workers:type_name -> orchestrator.WorkerInfo
+	52, // 33: orchestrator.WorkerInfo.capabilities:type_name -> orchestrator.WorkerCapabilities
+	15, // 34: orchestrator.WorkerInfo.task_durations:type_name -> orchestrator.TaskDurationStats
+	58, // 35: orchestrator.WorkerInfo.load:type_name -> orchestrator.WorkerLoad
+	84, // 36: orchestrator.WorkerCapabilities.labels:type_name -> orchestrator.WorkerCapabilities.LabelsEntry
+	52, // 37: orchestrator.RegisterWorkerRequest.capabilities:type_name -> orchestrator.WorkerCapabilities
+	58, // 38: orchestrator.HeartbeatRequest.load:type_name -> orchestrator.WorkerLoad
+	59, // 39: orchestrator.WorkerLoad.gpus:type_name -> orchestrator.GpuLoad
+	65, // 40: orchestrator.GetUserUsageResponse.users:type_name -> orchestrator.UserUsage
+	65, // 41: orchestrator.SetUserQuotaResponse.usage:type_name -> orchestrator.UserUsage
+	85, // 42: orchestrator.ClusterSummaryResponse.jobs_by_status:type_name -> orchestrator.ClusterSummaryResponse.JobsByStatusEntry
+	86, // 43: orchestrator.ClusterSummaryResponse.workers_by_status:type_name -> orchestrator.ClusterSummaryResponse.WorkersByStatusEntry
+	2,  // 44: orchestrator.OrchestratorService.CreateTrainingJob:input_type -> orchestrator.TrainingJobRequest
+	6,  // 45: orchestrator.OrchestratorService.GetJobStatus:input_type -> orchestrator.GetJobStatusRequest
+	9,  // 46: orchestrator.OrchestratorService.GetJob:input_type -> orchestrator.GetJobRequest
+	11, // 47: orchestrator.OrchestratorService.ListJobs:input_type -> orchestrator.ListJobsRequest
+	13, // 48: orchestrator.OrchestratorService.GetJobTasks:input_type -> orchestrator.GetJobTasksRequest
+	19, // 49: orchestrator.OrchestratorService.WatchJobStatus:input_type -> orchestrator.WatchJobStatusRequest
+	20, // 50: orchestrator.OrchestratorService.AssignTask:input_type -> orchestrator.AssignTaskRequest
+	22, // 51: orchestrator.OrchestratorService.StreamTasks:input_type -> orchestrator.TaskStreamRequest
+	23, // 52: orchestrator.OrchestratorService.ReportTaskCompletion:input_type -> orchestrator.TaskCompletionRequest
+	30, // 53: orchestrator.OrchestratorService.ReleaseTask:input_type -> orchestrator.ReleaseTaskRequest
+	27, // 54: orchestrator.OrchestratorService.ReportTaskProgress:input_type -> orchestrator.TaskProgressRequest
+	33, // 55: orchestrator.OrchestratorService.ShipTaskLogs:input_type -> orchestrator.ShipTaskLogsRequest
+	35, // 56: orchestrator.OrchestratorService.UpdateJobMetrics:input_type -> orchestrator.JobMetricsRequest
+	43, // 57: orchestrator.OrchestratorService.CancelJob:input_type -> orchestrator.CancelJobRequest
+	49, // 58: orchestrator.OrchestratorService.GetWorkerActivity:input_type -> orchestrator.WorkerActivityRequest
+	53, // 59: orchestrator.OrchestratorService.RegisterWorker:input_type -> orchestrator.RegisterWorkerRequest
+	55, // 60: orchestrator.OrchestratorService.DeregisterWorker:input_type -> orchestrator.DeregisterWorkerRequest
+	57, // 61: orchestrator.OrchestratorService.Heartbeat:input_type -> orchestrator.HeartbeatRequest
+	61, // 62: orchestrator.OrchestratorService.UnquarantineWorker:input_type -> orchestrator.UnquarantineWorkerRequest
+	47, // 63: orchestrator.OrchestratorService.ResumeJob:input_type -> orchestrator.ResumeJobRequest
+	45, // 64: orchestrator.OrchestratorService.PauseJob:input_type -> orchestrator.PauseJobRequest
+	37, // 65: orchestrator.OrchestratorService.GetJobMetricsHistory:input_type -> orchestrator.JobMetricsHistoryRequest
+	40, // 66: orchestrator.OrchestratorService.GetJobEvents:input_type -> orchestrator.JobEventsRequest
+	63, // 67: orchestrator.OrchestratorService.Drain:input_type -> orchestrator.DrainRequest
+	66, // 68: orchestrator.OrchestratorService.GetUserUsage:input_type -> orchestrator.GetUserUsageRequest
+	68, // 69: orchestrator.OrchestratorService.SetUserQuota:input_type -> orchestrator.SetUserQuotaRequest
+	77, // 70: orchestrator.OrchestratorService.GetClusterSummary:input_type -> orchestrator.ClusterSummaryRequest
+	70, // 71: orchestrator.OrchestratorAdminService.RequeueTask:input_type -> orchestrator.RequeueTaskRequest
+	71, // 72: orchestrator.OrchestratorAdminService.ForceFailJob:input_type -> orchestrator.ForceFailJobRequest
+	72, // 73: orchestrator.OrchestratorAdminService.DrainWorker:input_type -> orchestrator.DrainWorkerRequest
+	74, // 74: orchestrator.OrchestratorAdminService.ExportSnapshot:input_type -> orchestrator.ExportSnapshotRequest
+	75, // 75: orchestrator.OrchestratorAdminService.ImportSnapshot:input_type -> orchestrator.ImportSnapshotRequest
+	5,  // 76: orchestrator.OrchestratorService.CreateTrainingJob:output_type -> orchestrator.TrainingJobResponse
+	7,  // 77: orchestrator.OrchestratorService.GetJobStatus:output_type -> orchestrator.GetJobStatusResponse
+	10, // 78: orchestrator.OrchestratorService.GetJob:output_type -> orchestrator.GetJobResponse
+	12, // 79: orchestrator.OrchestratorService.ListJobs:output_type -> orchestrator.ListJobsResponse
+	14, // 80: orchestrator.OrchestratorService.GetJobTasks:output_type -> orchestrator.GetJobTasksResponse
+	7,  // 81: orchestrator.OrchestratorService.WatchJobStatus:output_type -> orchestrator.GetJobStatusResponse
+	21, // 82: orchestrator.OrchestratorService.AssignTask:output_type -> orchestrator.AssignTaskResponse
+	21, // 83: orchestrator.OrchestratorService.StreamTasks:output_type -> orchestrator.AssignTaskResponse
+	26, // 84: orchestrator.OrchestratorService.ReportTaskCompletion:output_type -> orchestrator.TaskCompletionResponse
+	31, // 85: orchestrator.OrchestratorService.ReleaseTask:output_type -> orchestrator.ReleaseTaskResponse
+	28, // 86: orchestrator.OrchestratorService.ReportTaskProgress:output_type -> orchestrator.TaskProgressResponse
+	34, // 87: orchestrator.OrchestratorService.ShipTaskLogs:output_type -> orchestrator.ShipTaskLogsResponse
+	36, // 88: orchestrator.OrchestratorService.UpdateJobMetrics:output_type -> orchestrator.JobMetricsResponse
+	44, // 89: orchestrator.OrchestratorService.CancelJob:output_type -> orchestrator.CancelJobResponse
+	50, // 90: orchestrator.OrchestratorService.GetWorkerActivity:output_type -> orchestrator.WorkerActivityResponse
+	54, // 91: orchestrator.OrchestratorService.RegisterWorker:output_type -> orchestrator.RegisterWorkerResponse
+	56, // 92: orchestrator.OrchestratorService.DeregisterWorker:output_type -> orchestrator.DeregisterWorkerResponse
+	60, // 93: orchestrator.OrchestratorService.Heartbeat:output_type -> orchestrator.HeartbeatResponse
+	62, // 94: orchestrator.OrchestratorService.UnquarantineWorker:output_type -> orchestrator.UnquarantineWorkerResponse
+	48, // 95: orchestrator.OrchestratorService.ResumeJob:output_type -> orchestrator.ResumeJobResponse
+	46, // 96: orchestrator.OrchestratorService.PauseJob:output_type -> orchestrator.PauseJobResponse
+	38, // 97: orchestrator.OrchestratorService.GetJobMetricsHistory:output_type -> orchestrator.JobMetricsHistoryResponse
+	41, // 98: orchestrator.OrchestratorService.GetJobEvents:output_type -> orchestrator.JobEventsResponse
+	64, // 99: orchestrator.OrchestratorService.Drain:output_type -> orchestrator.DrainResponse
+	67, // 100: orchestrator.OrchestratorService.GetUserUsage:output_type -> orchestrator.GetUserUsageResponse
+	69, // 101: orchestrator.OrchestratorService.SetUserQuota:output_type -> orchestrator.SetUserQuotaResponse
+	78, // 102: orchestrator.OrchestratorService.GetClusterSummary:output_type -> orchestrator.ClusterSummaryResponse
+	73, // 103: orchestrator.OrchestratorAdminService.RequeueTask:output_type -> orchestrator.AdminResponse
+	73, // 104: orchestrator.OrchestratorAdminService.ForceFailJob:output_type -> orchestrator.AdminResponse
+	73, // 105: orchestrator.OrchestratorAdminService.DrainWorker:output_type -> orchestrator.AdminResponse
+	76, // 106: orchestrator.OrchestratorAdminService.ExportSnapshot:output_type -> orchestrator.SnapshotResponse
+	76, // 107: orchestrator.OrchestratorAdminService.ImportSnapshot:output_type -> orchestrator.SnapshotResponse
+	76, // [76:108] is the sub-list for method output_type
+	44, // [44:76] is the sub-list for method input_type
+	44, // [44:44] is the sub-list for extension type_name
+	44, // [44:44] is the sub-list for extension extendee
+	0,  // [0:44] is the sub-list for field type_name
 }
 
 func init() { file_orchestrator_proto_init() }
@@ -1169,18 +7226,21 @@ func file_orchestrator_proto_init() {
 	if File_orchestrator_proto != nil {
 		return
 	}
+	file_orchestrator_proto_msgTypes[0].OneofWrappers = []any{}
+	file_orchestrator_proto_msgTypes[66].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_orchestrator_proto_rawDesc), len(file_orchestrator_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   17,
+			NumEnums:      2,
+			NumMessages:   85,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   2,
 		},
 		GoTypes:           file_orchestrator_proto_goTypes,
 		DependencyIndexes: file_orchestrator_proto_depIdxs,
+		EnumInfos:         file_orchestrator_proto_enumTypes,
 		MessageInfos:      file_orchestrator_proto_msgTypes,
 	}.Build()
 	File_orchestrator_proto = out.File
@@ -17,10 +17,21 @@ protoc --go_out=../api-gateway --go_opt=paths=source_relative \
 protoc --go_out=../orchestrator --go_opt=paths=source_relative \
     --go-grpc_out=../orchestrator --go-grpc_opt=paths=source_relative \
     orchestrator.proto
+protoc --go_out=../orchestrator --go_opt=paths=source_relative \
+    --go-grpc_out=../orchestrator --go-grpc_opt=paths=source_relative \
+    orchestrator_v2.proto
 
 # Generate Go code for worker
 protoc --go_out=../worker --go_opt=paths=source_relative \
     --go-grpc_out=../worker --go-grpc_opt=paths=source_relative \
     worker.proto
 
+# Generate Go code for the storage service, and for the orchestrator, which calls it
+protoc --go_out=../storage/proto/storage --go_opt=paths=source_relative \
+    --go-grpc_out=../storage/proto/storage --go-grpc_opt=paths=source_relative \
+    storage.proto
+protoc --go_out=../orchestrator --go_opt=paths=source_relative \
+    --go-grpc_out=../orchestrator --go-grpc_opt=paths=source_relative \
+    storage.proto
+
 echo "✓ gRPC stubs generated successfully"
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"time"
+)
+
+// ArtifactRecord describes a file a job produced, such as a plot or a
+// report, kept in the artifacts bucket. Artifacts workers upload for their
+// tasks go straight to the bucket without a record.
+type ArtifactRecord struct {
+	ID           string    `json:"_id"`
+	JobID        string    `json:"job_id"`
+	JobName      string    `json:"job_name,omitempty"`
+	Algorithm    string    `json:"algorithm,omitempty"`
+	DatasetName  string    `json:"dataset_name,omitempty"`
+	Name         string    `json:"name"`
+	ArtifactType string    `json:"artifact_type"`
+	MinioPath    string    `json:"minio_path"`
+	MinioBucket  string    `json:"minio_bucket"`
+	MinioObject  string    `json:"minio_object"`
+	ContentType  string    `json:"content_type"`
+	Description  string    `json:"description"`
+	SizeBytes    int64     `json:"size_bytes"`
+	Checksum     string    `json:"checksum"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// Artifact records are kept per job, under artifacts/<job>/.
+func artifactKey(jobID, id string) string {
+	return recordKey(artifactsCollection, jobID, id)
+}
+
+func (s *StorageServer) handleSaveArtifact(w http.ResponseWriter, r *http.Request) {
+	var artifact ArtifactRecord
+	var format struct {
+		Extension string `json:"extension"`
+	}
+	file, header, err := readUpload(r, &artifact, &format)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if file == nil {
+		writeError(w, http.StatusBadRequest, "No file provided")
+		return
+	}
+	defer file.Close()
+	if !validID(artifact.JobID) {
+		writeError(w, http.StatusBadRequest, "job_id is required in metadata")
+		return
+	}
+	if artifact.ArtifactType == "" {
+		artifact.ArtifactType = "unknown"
+	}
+	if artifact.Name == "" {
+		artifact.Name = "artifact_" + artifact.JobID
+	}
+	if artifact.ContentType == "" {
+		artifact.ContentType = "application/octet-stream"
+	}
+	if format.Extension == "" {
+		format.Extension = extension(header.Filename, "bin")
+	}
+
+	artifact.ID = newRecordID()
+	artifact.MinioBucket = artifactsBucket
+	artifact.MinioObject = objectName(artifactsBucket, artifact.ID, sanitizeName(format.Extension),
+		artifact.JobName, artifact.Algorithm, artifact.DatasetName, artifact.ArtifactType)
+	artifact.MinioPath = minioPath(artifactsBucket, artifact.MinioObject)
+	artifact.CreatedAt = time.Now().UTC()
+
+	artifact.Checksum, err = s.store(r.Context(), artifactsBucket, artifact.MinioObject, file, header.Size)
+	if err == nil {
+		artifact.SizeBytes = header.Size
+		err = s.catalog.put(r.Context(), artifactKey(artifact.JobID, artifact.ID), &artifact)
+	}
+	if err != nil {
+		log.Printf("Error saving artifact: %v", err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	log.Printf("Saved artifact %s of job %s to %s", artifact.Name, artifact.JobID, artifact.MinioPath)
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"message": "Artifact saved successfully",
+		"result": map[string]string{
+			"artifact_id": artifact.ID,
+			"minio_path":  artifact.MinioPath,
+			"object_name": artifact.MinioObject,
+			"checksum":    artifact.Checksum,
+		},
+	})
+}
+
+// handleListArtifacts lists a job's artifact records newest first, only
+// those of ?type= if given.
+func (s *StorageServer) handleListArtifacts(w http.ResponseWriter, r *http.Request) {
+	var match func(*ArtifactRecord) bool
+	if t := r.URL.Query().Get("type"); t != "" {
+		match = func(a *ArtifactRecord) bool { return a.ArtifactType == t }
+	}
+	prefix := artifactsCollection + "/" + r.PathValue("job_id") + "/"
+	artifacts, err := listRecords(r.Context(), s.catalog, prefix, 0, match)
+	if err != nil {
+		log.Printf("Error listing artifacts: %v", err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"artifacts": artifacts,
+		"count":     len(artifacts),
+	})
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// errObjectNotFound is returned by backends for keys they do not hold.
+var errObjectNotFound = errors.New("object not found")
+
+// ObjectInfo describes a stored object.
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// Backend is an object store the service keeps everything in: the files
+// services upload and the catalog records describing them. Keys are
+// slash-separated paths within a bucket.
+type Backend interface {
+	// Name is the backend's name, as STORAGE_BACKEND gives it.
+	Name() string
+
+	// EnsureBucket creates the bucket unless it exists.
+	EnsureBucket(ctx context.Context, bucket string) error
+
+	// Put stores size bytes read from data under the key, replacing any
+	// object there.
+	Put(ctx context.Context, bucket, key string, data io.Reader, size int64) error
+
+	// Get opens the object stored under the key. It returns
+	// errObjectNotFound if there is none.
+	Get(ctx context.Context, bucket, key string) (io.ReadCloser, ObjectInfo, error)
+
+	// Stat describes the object stored under the key. It returns
+	// errObjectNotFound if there is none.
+	Stat(ctx context.Context, bucket, key string) (ObjectInfo, error)
+
+	// List returns the objects whose keys start with prefix, in key order.
+	List(ctx context.Context, bucket, prefix string) ([]ObjectInfo, error)
+
+	// Delete removes the object stored under the key; deleting a missing
+	// object is not an error.
+	Delete(ctx context.Context, bucket, key string) error
+
+	// Ping checks the backend can be reached.
+	Ping(ctx context.Context) error
+}
+
+// backends are the object stores STORAGE_BACKEND may name.
+var backends = map[string]func() (Backend, error){
+	"local": newLocalBackend,
+	"s3":    newS3Backend,
+}
+
+// backendNameFromEnv reads STORAGE_BACKEND. Without it objects go to S3 if
+// MINIO_ENDPOINT is set and to local disk otherwise.
+func backendNameFromEnv() string {
+	if name := os.Getenv("STORAGE_BACKEND"); name != "" {
+		return name
+	}
+	if os.Getenv("MINIO_ENDPOINT") != "" {
+		return "s3"
+	}
+	return "local"
+}
+
+// newBackend builds the named backend.
+func newBackend(name string) (Backend, error) {
+	if name == "minio" {
+		name = "s3"
+	}
+	build, ok := backends[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown STORAGE_BACKEND %q (want local or s3)", name)
+	}
+	return build()
+}
+
+// validKey reports whether a key is a relative slash-separated path without
+// empty, "." or ".." segments, which every backend can store.
+func validKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return false
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return false
+		}
+	}
+	return true
+}
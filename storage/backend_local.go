@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// localBackend keeps objects as files under STORAGE_DIR, one directory per
+// bucket, for single-node deployments without an object store. Files are
+// written to a temporary name and renamed into place, so readers never see
+// a partial object.
+type localBackend struct {
+	root string
+}
+
+// tmpDir is the directory under the root files are written to before they
+// are renamed into their bucket; it is not a bucket.
+const tmpDir = ".tmp"
+
+func newLocalBackend() (Backend, error) {
+	root := os.Getenv("STORAGE_DIR")
+	if root == "" {
+		root = "/data/storage"
+	}
+	if err := os.MkdirAll(filepath.Join(root, tmpDir), 0o755); err != nil {
+		return nil, err
+	}
+	log.Printf("Storing objects on local disk under %s", root)
+	return &localBackend{root: root}, nil
+}
+
+func (b *localBackend) Name() string { return "local" }
+
+func (b *localBackend) path(bucket, key string) (string, error) {
+	if !validKey(bucket) || strings.Contains(bucket, "/") || bucket == tmpDir {
+		return "", errors.New("invalid bucket name")
+	}
+	if key == "" {
+		return filepath.Join(b.root, bucket), nil
+	}
+	if !validKey(key) {
+		return "", errors.New("invalid object key")
+	}
+	return filepath.Join(b.root, bucket, filepath.FromSlash(key)), nil
+}
+
+func (b *localBackend) EnsureBucket(ctx context.Context, bucket string) error {
+	dir, err := b.path(bucket, "")
+	if err != nil {
+		return err
+	}
+	return os.MkdirAll(dir, 0o755)
+}
+
+func (b *localBackend) Put(ctx context.Context, bucket, key string, data io.Reader, size int64) error {
+	path, err := b.path(bucket, key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Join(b.root, tmpDir), "object-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	n, err := io.Copy(f, data)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if n != size {
+		return io.ErrUnexpectedEOF
+	}
+	return os.Rename(f.Name(), path)
+}
+
+func (b *localBackend) Get(ctx context.Context, bucket, key string) (io.ReadCloser, ObjectInfo, error) {
+	path, err := b.path(bucket, key)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ObjectInfo{}, errObjectNotFound
+	}
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, ObjectInfo{}, err
+	}
+	if fi.IsDir() {
+		f.Close()
+		return nil, ObjectInfo{}, errObjectNotFound
+	}
+	return f, ObjectInfo{Key: key, Size: fi.Size(), LastModified: fi.ModTime()}, nil
+}
+
+func (b *localBackend) Stat(ctx context.Context, bucket, key string) (ObjectInfo, error) {
+	path, err := b.path(bucket, key)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	fi, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) || (err == nil && fi.IsDir()) {
+		return ObjectInfo{}, errObjectNotFound
+	}
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	return ObjectInfo{Key: key, Size: fi.Size(), LastModified: fi.ModTime()}, nil
+}
+
+func (b *localBackend) List(ctx context.Context, bucket, prefix string) ([]ObjectInfo, error) {
+	dir, err := b.path(bucket, "")
+	if err != nil {
+		return nil, err
+	}
+
+	// Walk only the deepest directory the prefix names
+	start := dir
+	if i := strings.LastIndex(prefix, "/"); i > 0 && validKey(prefix[:i]) {
+		start = filepath.Join(dir, filepath.FromSlash(prefix[:i]))
+	}
+
+	var objects []ObjectInfo
+	err = filepath.WalkDir(start, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, ObjectInfo{Key: key, Size: fi.Size(), LastModified: fi.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Key < objects[j].Key })
+	return objects, nil
+}
+
+func (b *localBackend) Delete(ctx context.Context, bucket, key string) error {
+	path, err := b.path(bucket, key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	// Drop directories the delete left empty, up to the bucket
+	bucketDir, _ := b.path(bucket, "")
+	for dir := filepath.Dir(path); dir != bucketDir && strings.HasPrefix(dir, bucketDir); dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			break
+		}
+	}
+	return nil
+}
+
+func (b *localBackend) Ping(ctx context.Context) error {
+	_, err := os.Stat(b.root)
+	return err
+}
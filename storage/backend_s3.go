@@ -0,0 +1,342 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// s3Backend keeps objects in S3-compatible storage such as MinIO, signing
+// requests with AWS Signature Version 4. It reads the same MINIO_* settings
+// as the orchestrator, which writes model weights to the same buckets
+// directly.
+type s3Backend struct {
+	endpoint  string // host:port
+	accessKey string
+	secretKey string
+	region    string
+	secure    bool
+	client    *http.Client
+}
+
+func newS3Backend() (Backend, error) {
+	b := &s3Backend{
+		endpoint:  os.Getenv("MINIO_ENDPOINT"),
+		accessKey: os.Getenv("MINIO_ACCESS_KEY"),
+		secretKey: os.Getenv("MINIO_SECRET_KEY"),
+		region:    os.Getenv("MINIO_REGION"),
+		secure:    strings.ToLower(os.Getenv("MINIO_SECURE")) == "true",
+		// Uploads and downloads stream, so only connecting is bounded
+		client: &http.Client{Transport: &http.Transport{
+			Proxy:                 http.ProxyFromEnvironment,
+			ResponseHeaderTimeout: 60 * time.Second,
+			IdleConnTimeout:       90 * time.Second,
+			MaxIdleConnsPerHost:   16,
+		}},
+	}
+	if b.endpoint == "" {
+		b.endpoint = "minio:9000"
+	}
+	if b.accessKey == "" {
+		b.accessKey = "minioadmin"
+	}
+	if b.secretKey == "" {
+		b.secretKey = "minioadmin"
+	}
+	if b.region == "" {
+		b.region = "us-east-1"
+	}
+
+	log.Printf("Storing objects in S3 at %s", b.endpoint)
+	return b, nil
+}
+
+func (b *s3Backend) Name() string { return "s3" }
+
+func (b *s3Backend) EnsureBucket(ctx context.Context, bucket string) error {
+	resp, err := b.do(ctx, http.MethodHead, bucket, "", nil, nil, 0)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	resp, err = b.do(ctx, http.MethodPut, bucket, "", nil, nil, 0)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	// Another replica may have created it in the meantime
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusConflict {
+		return fmt.Errorf("object store returned status %d creating bucket %s", resp.StatusCode, bucket)
+	}
+	return nil
+}
+
+func (b *s3Backend) Put(ctx context.Context, bucket, key string, data io.Reader, size int64) error {
+	if !validKey(key) {
+		return fmt.Errorf("invalid object key %q", key)
+	}
+	resp, err := b.do(ctx, http.MethodPut, bucket, key, nil, data, size)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("object store returned status %d for %s/%s", resp.StatusCode, bucket, key)
+	}
+	return nil
+}
+
+func (b *s3Backend) Get(ctx context.Context, bucket, key string) (io.ReadCloser, ObjectInfo, error) {
+	if !validKey(key) {
+		return nil, ObjectInfo{}, errObjectNotFound
+	}
+	resp, err := b.do(ctx, http.MethodGet, bucket, key, nil, nil, 0)
+	if err != nil {
+		return nil, ObjectInfo{}, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ObjectInfo{}, errObjectNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, ObjectInfo{}, fmt.Errorf("object store returned status %d for %s/%s", resp.StatusCode, bucket, key)
+	}
+	return resp.Body, objectInfoFromHeader(key, resp), nil
+}
+
+func (b *s3Backend) Stat(ctx context.Context, bucket, key string) (ObjectInfo, error) {
+	if !validKey(key) {
+		return ObjectInfo{}, errObjectNotFound
+	}
+	resp, err := b.do(ctx, http.MethodHead, bucket, key, nil, nil, 0)
+	if err != nil {
+		return ObjectInfo{}, err
+	}
+	resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return objectInfoFromHeader(key, resp), nil
+	case http.StatusNotFound:
+		return ObjectInfo{}, errObjectNotFound
+	default:
+		return ObjectInfo{}, fmt.Errorf("object store returned status %d for %s/%s", resp.StatusCode, bucket, key)
+	}
+}
+
+func objectInfoFromHeader(key string, resp *http.Response) ObjectInfo {
+	modified, _ := http.ParseTime(resp.Header.Get("Last-Modified"))
+	return ObjectInfo{Key: key, Size: resp.ContentLength, LastModified: modified}
+}
+
+// listBucketResult is the part of a ListObjectsV2 response the backend
+// reads.
+type listBucketResult struct {
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+	Contents              []struct {
+		Key          string    `xml:"Key"`
+		Size         int64     `xml:"Size"`
+		LastModified time.Time `xml:"LastModified"`
+	} `xml:"Contents"`
+}
+
+func (b *s3Backend) List(ctx context.Context, bucket, prefix string) ([]ObjectInfo, error) {
+	var objects []ObjectInfo
+	token := ""
+	for {
+		query := map[string]string{"list-type": "2"}
+		if prefix != "" {
+			query["prefix"] = prefix
+		}
+		if token != "" {
+			query["continuation-token"] = token
+		}
+
+		resp, err := b.do(ctx, http.MethodGet, bucket, "", query, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("object store returned status %d listing %s", resp.StatusCode, bucket)
+		}
+		var page listBucketResult
+		err = xml.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode listing of %s: %v", bucket, err)
+		}
+
+		for _, c := range page.Contents {
+			objects = append(objects, ObjectInfo{Key: c.Key, Size: c.Size, LastModified: c.LastModified})
+		}
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			return objects, nil
+		}
+		token = page.NextContinuationToken
+	}
+}
+
+func (b *s3Backend) Delete(ctx context.Context, bucket, key string) error {
+	if !validKey(key) {
+		return fmt.Errorf("invalid object key %q", key)
+	}
+	resp, err := b.do(ctx, http.MethodDelete, bucket, key, nil, nil, 0)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	default:
+		return fmt.Errorf("object store returned status %d deleting %s/%s", resp.StatusCode, bucket, key)
+	}
+}
+
+func (b *s3Backend) Ping(ctx context.Context) error {
+	resp, err := b.do(ctx, http.MethodGet, "", "", nil, nil, 0)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("object store returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// emptyPayloadHash is the SHA-256 of an empty body.
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// unsignedPayload stands in for the hash of bodies that are streamed rather
+// than hashed up front.
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// do sends a signed request for the bucket, or the service if bucket is
+// empty, or the object if key is set. A body of size bytes is streamed.
+func (b *s3Backend) do(ctx context.Context, method, bucket, key string, query map[string]string, body io.Reader, size int64) (*http.Response, error) {
+	scheme := "http"
+	if b.secure {
+		scheme = "https"
+	}
+	path := "/"
+	if bucket != "" {
+		path += s3Escape(bucket, false)
+		if key != "" {
+			path += "/" + s3Escape(key, true)
+		}
+	}
+	rawQuery := canonicalQuery(query)
+	target := scheme + "://" + b.endpoint + path
+	if rawQuery != "" {
+		target += "?" + rawQuery
+	}
+
+	if body != nil && size == 0 {
+		body = http.NoBody
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	payloadHash := emptyPayloadHash
+	if body != nil {
+		req.ContentLength = size
+		req.Header.Set("Content-Type", "application/octet-stream")
+		payloadHash = unsignedPayload
+	}
+	b.sign(req, path, rawQuery, payloadHash, time.Now().UTC())
+	return b.client.Do(req)
+}
+
+// sign adds an AWS Signature Version 4 to the request.
+func (b *s3Backend) sign(req *http.Request, path, rawQuery, payloadHash string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+
+	const signedHeaders = "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		rawQuery,
+		"host:" + req.URL.Host,
+		"x-amz-content-sha256:" + payloadHash,
+		"x-amz-date:" + amzDate,
+		"",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + b.region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	signingKey := hmacSHA256([]byte("AWS4"+b.secretKey), date)
+	for _, part := range []string{b.region, "s3", "aws4_request"} {
+		signingKey = hmacSHA256(signingKey, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		b.accessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// s3Escape percent-encodes s the way Signature Version 4 expects: all but
+// unreserved characters, and slashes too unless keepSlash is set.
+func s3Escape(s string, keepSlash bool) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/' && keepSlash:
+			sb.WriteByte(c)
+		default:
+			fmt.Fprintf(&sb, "%%%02X", c)
+		}
+	}
+	return sb.String()
+}
+
+// canonicalQuery encodes query parameters sorted by name, as both the
+// request and its signature carry them.
+func canonicalQuery(query map[string]string) string {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = s3Escape(name, false) + "=" + s3Escape(query[name], false)
+	}
+	return strings.Join(parts, "&")
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// catalogBucket holds the records describing what the other buckets hold:
+// models, checkpoints, artifacts, datasets and jobs, the checkpoint
+// retention policies of jobs and the versions of each model name, one JSON
+// object per record under the collection's name. Keeping them in the
+// backend itself means the service needs no database.
+const catalogBucket = "catalog"
+
+// Catalog collections.
+const (
+	modelsCollection      = "models"
+	checkpointsCollection = "checkpoints"
+	artifactsCollection   = "artifacts"
+	datasetsCollection    = "datasets"
+	jobsCollection        = "jobs"
+	retentionCollection   = "retention"
+	// Versions of each model name, indexing the models collection
+	modelVersionsCollection = "model_versions"
+)
+
+var collections = []string{modelsCollection, checkpointsCollection, artifactsCollection, datasetsCollection, jobsCollection, retentionCollection, modelVersionsCollection}
+
+// Catalog stores records in the catalog bucket.
+type Catalog struct {
+	objects Backend
+}
+
+// newRecordID returns a new record ID: 24 hex digits, the first 8 the
+// creation time in seconds, so that records sort oldest first by key.
+func newRecordID() string {
+	var id [12]byte
+	now := uint32(time.Now().Unix())
+	id[0], id[1], id[2], id[3] = byte(now>>24), byte(now>>16), byte(now>>8), byte(now)
+	rand.Read(id[4:])
+	return hex.EncodeToString(id[:])
+}
+
+// recordKey is the key of a record in the catalog bucket; path elements are
+// the collection followed by any grouping, e.g. the job of a checkpoint.
+func recordKey(path ...string) string {
+	return strings.Join(path, "/") + ".json"
+}
+
+// validID reports whether an ID, such as a job's, can be a path element of
+// a record key.
+func validID(id string) bool {
+	return validKey(id) && !strings.Contains(id, "/")
+}
+
+func (c *Catalog) put(ctx context.Context, key string, record interface{}) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return c.objects.Put(ctx, catalogBucket, key, bytes.NewReader(data), int64(len(data)))
+}
+
+// get reads the record stored under the key into record. It returns
+// errObjectNotFound if there is none.
+func (c *Catalog) get(ctx context.Context, key string, record interface{}) error {
+	body, _, err := c.objects.Get(ctx, catalogBucket, key)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, record); err != nil {
+		return fmt.Errorf("malformed catalog record %s: %v", key, err)
+	}
+	return nil
+}
+
+func (c *Catalog) delete(ctx context.Context, key string) error {
+	return c.objects.Delete(ctx, catalogBucket, key)
+}
+
+// count returns how many records are stored under the prefix.
+func (c *Catalog) count(ctx context.Context, prefix string) (int, error) {
+	objects, err := c.objects.List(ctx, catalogBucket, prefix)
+	return len(objects), err
+}
+
+// listRecords reads the records stored under the prefix newest first,
+// keeping those match accepts, or all if match is nil, until it has limit
+// of them; limit 0 reads them all.
+func listRecords[T any](ctx context.Context, c *Catalog, prefix string, limit int, match func(*T) bool) ([]*T, error) {
+	objects, err := c.objects.List(ctx, catalogBucket, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []*T{}
+	for i := len(objects) - 1; i >= 0; i-- {
+		if limit > 0 && len(records) >= limit {
+			break
+		}
+		if !strings.HasSuffix(objects[i].Key, ".json") {
+			continue
+		}
+		record := new(T)
+		if err := c.get(ctx, objects[i].Key, record); err == errObjectNotFound {
+			// Deleted since it was listed
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		if match == nil || match(record) {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
@@ -0,0 +1,500 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CheckpointRecord describes a checkpoint of a job, kept in the checkpoints
+// bucket.
+type CheckpointRecord struct {
+	ID          string                 `json:"_id"`
+	JobID       string                 `json:"job_id"`
+	JobName     string                 `json:"job_name,omitempty"`
+	Algorithm   string                 `json:"algorithm,omitempty"`
+	DatasetName string                 `json:"dataset_name,omitempty"`
+	Epoch       int                    `json:"epoch"`
+	MinioPath   string                 `json:"minio_path"`
+	MinioBucket string                 `json:"minio_bucket"`
+	MinioObject string                 `json:"minio_object"`
+	Metrics     map[string]interface{} `json:"metrics"`
+	ModelState  string                 `json:"model_state"`
+	SizeBytes   int64                  `json:"size_bytes"`
+	Checksum    string                 `json:"checksum"`
+	CreatedAt   time.Time              `json:"created_at"`
+
+	// The data is an object another service wrote, not the service
+	Registered bool `json:"registered,omitempty"`
+
+	// Kept whatever the job's retention policy
+	Pinned bool `json:"pinned"`
+}
+
+// RetentionPolicy is which checkpoints of a job to keep: the latest
+// KeepLast by epoch and the best KeepBest by Metric, lowest first if Mode
+// is "min" and highest first if "max". Pinned checkpoints are always kept;
+// all others are deleted.
+type RetentionPolicy struct {
+	KeepLast int    `json:"keep_last"`
+	KeepBest int    `json:"keep_best"`
+	Metric   string `json:"metric,omitempty"`
+	Mode     string `json:"mode,omitempty"`
+}
+
+// validate fills in the metric and mode defaults: loss, lowest first.
+// Other metrics default to highest first unless named as a loss or error.
+func (p *RetentionPolicy) validate() error {
+	if p.KeepLast < 0 || p.KeepBest < 0 {
+		return errors.New("keep_last and keep_best must not be negative")
+	}
+	if p.KeepBest == 0 {
+		return nil
+	}
+	if p.Metric == "" {
+		p.Metric = "loss"
+	}
+	switch p.Mode {
+	case "":
+		p.Mode = "max"
+		if strings.Contains(p.Metric, "loss") || strings.Contains(p.Metric, "error") {
+			p.Mode = "min"
+		}
+	case "min", "max":
+	default:
+		return fmt.Errorf("invalid mode %q: want min or max", p.Mode)
+	}
+	return nil
+}
+
+// retained splits a job's checkpoints, latest epoch first, into those the
+// policy keeps and those it does not.
+func (p *RetentionPolicy) retained(checkpoints []*CheckpointRecord) (keep, drop []*CheckpointRecord) {
+	kept := make(map[string]bool, len(checkpoints))
+	for i, c := range checkpoints {
+		if c.Pinned || i < p.KeepLast {
+			kept[c.ID] = true
+		}
+	}
+	if p.KeepBest > 0 {
+		// Checkpoints without the metric are never the best
+		var scored []*CheckpointRecord
+		for _, c := range checkpoints {
+			if _, ok := c.metric(p.Metric); ok {
+				scored = append(scored, c)
+			}
+		}
+		sort.SliceStable(scored, func(i, j int) bool {
+			a, _ := scored[i].metric(p.Metric)
+			b, _ := scored[j].metric(p.Metric)
+			if p.Mode == "min" {
+				return a < b
+			}
+			return a > b
+		})
+		for i := 0; i < len(scored) && i < p.KeepBest; i++ {
+			kept[scored[i].ID] = true
+		}
+	}
+
+	for _, c := range checkpoints {
+		if kept[c.ID] {
+			keep = append(keep, c)
+		} else {
+			drop = append(drop, c)
+		}
+	}
+	return keep, drop
+}
+
+// metric returns a numeric metric of the checkpoint.
+func (c *CheckpointRecord) metric(name string) (float64, bool) {
+	v, ok := c.Metrics[name].(float64)
+	return v, ok
+}
+
+func retentionKey(jobID string) string {
+	return recordKey(retentionCollection, jobID)
+}
+
+// Checkpoint records are kept per job, under checkpoints/<job>/.
+func checkpointKey(jobID, id string) string {
+	return recordKey(checkpointsCollection, jobID, id)
+}
+
+func checkpointsPrefix(jobID string) string {
+	return checkpointsCollection + "/" + jobID + "/"
+}
+
+// checkpointID is the ID of the checkpoint of a job's epoch with the data,
+// identified by its checksum or the object holding it.
+func checkpointID(jobID string, epoch int, data string) string {
+	digest := sha256.Sum256([]byte(jobID + "/" + strconv.Itoa(epoch) + "/" + data))
+	return hex.EncodeToString(digest[:12])
+}
+
+// saveCheckpoint records a checkpoint whose data is size bytes read from
+// data or, if data is nil, the existing object. The job's retention policy,
+// if it has one, is applied afterwards. A checkpoint without an ID is
+// given a new one.
+func (s *StorageServer) saveCheckpoint(ctx context.Context, checkpoint *CheckpointRecord, data io.Reader, size int64, existing *existingObject) error {
+	if checkpoint.ID == "" {
+		checkpoint.ID = newRecordID()
+	}
+	checkpoint.MinioBucket = checkpointsBucket
+	if checkpoint.ModelState == "" {
+		checkpoint.ModelState = "training"
+	}
+	checkpoint.CreatedAt = time.Now().UTC()
+
+	if data != nil {
+		checkpoint.MinioObject = objectName(checkpointsBucket, checkpoint.ID, "weights",
+			checkpoint.JobName, checkpoint.Algorithm, checkpoint.DatasetName, "epoch_"+strconv.Itoa(checkpoint.Epoch))
+		digest, err := s.store(ctx, checkpointsBucket, checkpoint.MinioObject, data, size)
+		if err != nil {
+			return err
+		}
+		checkpoint.SizeBytes, checkpoint.Checksum = size, digest
+	} else {
+		if err := existing.resolve(ctx, s.objects, checkpointsBucket); err != nil {
+			return err
+		}
+		checkpoint.MinioObject, checkpoint.SizeBytes, checkpoint.Checksum = existing.ObjectName, existing.SizeBytes, existing.Checksum
+		checkpoint.Registered = true
+	}
+	checkpoint.MinioPath = minioPath(checkpointsBucket, checkpoint.MinioObject)
+
+	if err := s.catalog.put(ctx, checkpointKey(checkpoint.JobID, checkpoint.ID), checkpoint); err != nil {
+		return fmt.Errorf("failed to record checkpoint: %v", err)
+	}
+	log.Printf("Saved checkpoint of job %s for epoch %d to %s", checkpoint.JobID, checkpoint.Epoch, checkpoint.MinioPath)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var policy RetentionPolicy
+	if err := s.catalog.get(ctx, retentionKey(checkpoint.JobID), &policy); err == nil {
+		if _, err := s.applyRetention(ctx, checkpoint.JobID, &policy); err != nil {
+			log.Printf("Warning: Failed to apply retention policy of job %s: %v", checkpoint.JobID, err)
+		}
+	} else if !errors.Is(err, errObjectNotFound) {
+		log.Printf("Warning: Failed to read retention policy of job %s: %v", checkpoint.JobID, err)
+	}
+	return nil
+}
+
+// applyRetention deletes the checkpoints of a job the policy does not keep
+// and returns how many it deleted. Caller must hold s.mu.
+func (s *StorageServer) applyRetention(ctx context.Context, jobID string, policy *RetentionPolicy) (int, error) {
+	checkpoints, err := s.listCheckpoints(ctx, jobID)
+	if err != nil {
+		return 0, err
+	}
+	_, drop := policy.retained(checkpoints)
+	deleted := 0
+	for _, checkpoint := range drop {
+		if err := s.deleteCheckpoint(ctx, checkpoint); err != nil {
+			log.Printf("Error deleting checkpoint %s of job %s: %v", checkpoint.ID, jobID, err)
+			continue
+		}
+		deleted++
+	}
+	if deleted > 0 {
+		log.Printf("Deleted %d checkpoints of job %s by its retention policy", deleted, jobID)
+	}
+	return deleted, nil
+}
+
+// listCheckpoints returns a job's checkpoints, latest epoch first.
+func (s *StorageServer) listCheckpoints(ctx context.Context, jobID string) ([]*CheckpointRecord, error) {
+	checkpoints, err := listRecords[CheckpointRecord](ctx, s.catalog, checkpointsPrefix(jobID), 0, nil)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(checkpoints, func(i, j int) bool { return checkpoints[i].Epoch > checkpoints[j].Epoch })
+	return checkpoints, nil
+}
+
+func (s *StorageServer) handleSaveCheckpoint(w http.ResponseWriter, r *http.Request) {
+	var checkpoint CheckpointRecord
+	var existing existingObject
+	file, header, err := readUpload(r, &checkpoint, &existing)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if file == nil && existing.ObjectName == "" {
+		writeError(w, http.StatusBadRequest, "No file provided")
+		return
+	}
+	if !validID(checkpoint.JobID) {
+		if file != nil {
+			file.Close()
+		}
+		writeError(w, http.StatusBadRequest, "job_id is required in metadata")
+		return
+	}
+
+	if file != nil {
+		defer file.Close()
+		err = s.saveCheckpoint(r.Context(), &checkpoint, file, header.Size, nil)
+	} else {
+		err = s.saveCheckpoint(r.Context(), &checkpoint, nil, 0, &existing)
+	}
+	if err != nil {
+		log.Printf("Error saving checkpoint: %v", err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"message": "Checkpoint saved successfully",
+		"result": map[string]string{
+			"checkpoint_id": checkpoint.ID,
+			"minio_path":    checkpoint.MinioPath,
+			"object_name":   checkpoint.MinioObject,
+			"checksum":      checkpoint.Checksum,
+		},
+	})
+}
+
+func (s *StorageServer) handleListCheckpoints(w http.ResponseWriter, r *http.Request) {
+	checkpoints, err := s.listCheckpoints(r.Context(), r.PathValue("job_id"))
+	if err != nil {
+		log.Printf("Error listing checkpoints: %v", err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"checkpoints": checkpoints,
+		"count":       len(checkpoints),
+	})
+}
+
+// handleCleanupCheckpoints keeps only the latest ?keep= checkpoints of a
+// job, 5 by default, and the best ?keep_best= by ?metric= in ?mode=, with
+// the pinned ones.
+func (s *StorageServer) handleCleanupCheckpoints(w http.ResponseWriter, r *http.Request) {
+	jobID := r.PathValue("job_id")
+	q := r.URL.Query()
+	policy := RetentionPolicy{KeepLast: 5, Metric: q.Get("metric"), Mode: q.Get("mode")}
+	for param, n := range map[string]*int{"keep": &policy.KeepLast, "keep_best": &policy.KeepBest} {
+		if v := q.Get(param); v != "" {
+			var err error
+			if *n, err = strconv.Atoi(v); err != nil {
+				writeError(w, http.StatusBadRequest, param+" must be a non-negative integer")
+				return
+			}
+		}
+	}
+	if err := policy.validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deleted, err := s.applyRetention(r.Context(), jobID, &policy)
+	if err != nil {
+		log.Printf("Error cleaning up checkpoints of job %s: %v", jobID, err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	log.Printf("Cleaned up %d old checkpoints of job %s", deleted, jobID)
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message":       fmt.Sprintf("Cleaned up %d old checkpoints", deleted),
+		"deleted_count": deleted,
+	})
+}
+
+// loadCheckpoint reads a checkpoint's record, writing the error response if
+// it cannot.
+func (s *StorageServer) loadCheckpoint(w http.ResponseWriter, r *http.Request) (*CheckpointRecord, bool) {
+	jobID, id := r.PathValue("job_id"), r.PathValue("id")
+	var checkpoint CheckpointRecord
+	err := errObjectNotFound
+	if validID(jobID) && validID(id) {
+		err = s.catalog.get(r.Context(), checkpointKey(jobID, id), &checkpoint)
+	}
+	if errors.Is(err, errObjectNotFound) {
+		writeError(w, http.StatusNotFound, "Checkpoint not found: "+id)
+		return nil, false
+	}
+	if err != nil {
+		log.Printf("Error loading checkpoint %s of job %s: %v", id, jobID, err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+	return &checkpoint, true
+}
+
+func (s *StorageServer) handleDownloadCheckpoint(w http.ResponseWriter, r *http.Request) {
+	checkpoint, ok := s.loadCheckpoint(w, r)
+	if !ok {
+		return
+	}
+	name := fmt.Sprintf("%s_epoch_%d.weights", checkpoint.JobID, checkpoint.Epoch)
+	s.serveObject(w, r, checkpoint.MinioBucket, checkpoint.MinioObject, name)
+}
+
+// handleDeleteCheckpoint deletes a checkpoint unless it is pinned.
+func (s *StorageServer) handleDeleteCheckpoint(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	checkpoint, ok := s.loadCheckpoint(w, r)
+	if !ok {
+		return
+	}
+	if checkpoint.Pinned {
+		writeError(w, http.StatusConflict, "Checkpoint is pinned; unpin it to delete it")
+		return
+	}
+	if err := s.deleteCheckpoint(r.Context(), checkpoint); err != nil {
+		log.Printf("Error deleting checkpoint %s of job %s: %v", checkpoint.ID, checkpoint.JobID, err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	log.Printf("Deleted checkpoint %s of job %s", checkpoint.ID, checkpoint.JobID)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message":       "Checkpoint deleted successfully",
+		"checkpoint_id": checkpoint.ID,
+	})
+}
+
+// handlePinCheckpoint pins a checkpoint on PUT and unpins it on DELETE.
+func (s *StorageServer) handlePinCheckpoint(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	checkpoint, ok := s.loadCheckpoint(w, r)
+	if !ok {
+		return
+	}
+	checkpoint.Pinned = r.Method == http.MethodPut
+	if err := s.catalog.put(r.Context(), checkpointKey(checkpoint.JobID, checkpoint.ID), checkpoint); err != nil {
+		log.Printf("Error pinning checkpoint %s of job %s: %v", checkpoint.ID, checkpoint.JobID, err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	log.Printf("Set pinned=%t on checkpoint %s of job %s", checkpoint.Pinned, checkpoint.ID, checkpoint.JobID)
+	writeJSON(w, http.StatusOK, checkpoint)
+}
+
+// loadRetention reads a job's retention policy, writing the error response
+// if it cannot.
+func (s *StorageServer) loadRetention(w http.ResponseWriter, r *http.Request) (*RetentionPolicy, bool) {
+	jobID := r.PathValue("job_id")
+	var policy RetentionPolicy
+	err := errObjectNotFound
+	if validID(jobID) {
+		err = s.catalog.get(r.Context(), retentionKey(jobID), &policy)
+	}
+	if errors.Is(err, errObjectNotFound) {
+		writeError(w, http.StatusNotFound, "Job has no retention policy")
+		return nil, false
+	}
+	if err != nil {
+		log.Printf("Error loading retention policy of job %s: %v", jobID, err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+	return &policy, true
+}
+
+func (s *StorageServer) handleGetRetention(w http.ResponseWriter, r *http.Request) {
+	if policy, ok := s.loadRetention(w, r); ok {
+		writeJSON(w, http.StatusOK, policy)
+	}
+}
+
+// handleSetRetention sets a job's retention policy and applies it to the
+// checkpoints the job already has.
+func (s *StorageServer) handleSetRetention(w http.ResponseWriter, r *http.Request) {
+	jobID := r.PathValue("job_id")
+	if !validID(jobID) {
+		writeError(w, http.StatusBadRequest, "invalid job_id")
+		return
+	}
+	var policy RetentionPolicy
+	if err := json.NewDecoder(r.Body).Decode(&policy); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid retention policy: "+err.Error())
+		return
+	}
+	if err := policy.validate(); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if policy.KeepLast == 0 && policy.KeepBest == 0 {
+		writeError(w, http.StatusBadRequest, "keep_last or keep_best is required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.catalog.put(r.Context(), retentionKey(jobID), &policy); err != nil {
+		log.Printf("Error setting retention policy of job %s: %v", jobID, err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	deleted, err := s.applyRetention(r.Context(), jobID, &policy)
+	if err != nil {
+		log.Printf("Error applying retention policy of job %s: %v", jobID, err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	log.Printf("Set retention policy of job %s: keep last %d, best %d", jobID, policy.KeepLast, policy.KeepBest)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"policy":        policy,
+		"deleted_count": deleted,
+	})
+}
+
+func (s *StorageServer) handleDeleteRetention(w http.ResponseWriter, r *http.Request) {
+	if _, ok := s.loadRetention(w, r); !ok {
+		return
+	}
+	jobID := r.PathValue("job_id")
+	if err := s.catalog.delete(r.Context(), retentionKey(jobID)); err != nil {
+		log.Printf("Error deleting retention policy of job %s: %v", jobID, err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"message": "Retention policy deleted"})
+}
+
+// deleteCheckpoint removes a checkpoint's record and releases its data.
+func (s *StorageServer) deleteCheckpoint(ctx context.Context, checkpoint *CheckpointRecord) error {
+	if err := s.catalog.delete(ctx, checkpointKey(checkpoint.JobID, checkpoint.ID)); err != nil {
+		return err
+	}
+	_, err := s.releaseCheckpointObject(ctx, checkpoint)
+	return err
+}
+
+// releaseCheckpointObject deletes the data of a deleted checkpoint. The
+// orchestrator registers checkpoints content addressed, so those of jobs
+// that reached the same weights share one object, which is deleted with
+// the last checkpoint referring to it. It returns true if it deleted the
+// data.
+func (s *StorageServer) releaseCheckpointObject(ctx context.Context, checkpoint *CheckpointRecord) (bool, error) {
+	if checkpoint.Registered {
+		others, err := listRecords(ctx, s.catalog, checkpointsCollection+"/", 1, func(c *CheckpointRecord) bool {
+			return c.MinioBucket == checkpoint.MinioBucket && c.MinioObject == checkpoint.MinioObject
+		})
+		if err != nil || len(others) > 0 {
+			return false, err
+		}
+	}
+	return true, s.objects.Delete(ctx, checkpoint.MinioBucket, checkpoint.MinioObject)
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+// Objects and records drift apart when services write to the buckets
+// directly, or an object is deleted from under its record. The validate
+// endpoint reports where they disagree, and sync records what was uploaded
+// to the models and datasets buckets without going through the API.
+
+// recordedObject is the object a catalog record keeps its data in.
+type recordedObject struct {
+	ID     string
+	Bucket string
+	Object string
+}
+
+// recordedObjects lists the objects the records of a collection keep their
+// data in.
+func (s *StorageServer) recordedObjects(ctx context.Context, collection string) ([]recordedObject, error) {
+	var refs []recordedObject
+	prefix := collection + "/"
+	switch collection {
+	case modelsCollection:
+		records, err := listRecords[ModelRecord](ctx, s.catalog, prefix, 0, nil)
+		for _, r := range records {
+			refs = append(refs, recordedObject{r.ID, r.MinioBucket, r.MinioObject})
+		}
+		return refs, err
+	case datasetsCollection:
+		records, err := listRecords[DatasetRecord](ctx, s.catalog, prefix, 0, nil)
+		for _, r := range records {
+			refs = append(refs, recordedObject{r.ID, r.MinioBucket, r.MinioObject})
+		}
+		return refs, err
+	case checkpointsCollection:
+		records, err := listRecords[CheckpointRecord](ctx, s.catalog, prefix, 0, nil)
+		for _, r := range records {
+			refs = append(refs, recordedObject{r.ID, r.MinioBucket, r.MinioObject})
+		}
+		return refs, err
+	case artifactsCollection:
+		records, err := listRecords[ArtifactRecord](ctx, s.catalog, prefix, 0, nil)
+		for _, r := range records {
+			refs = append(refs, recordedObject{r.ID, r.MinioBucket, r.MinioObject})
+		}
+		return refs, err
+	}
+	return nil, errors.New("no objects recorded in " + collection)
+}
+
+// consistencyIssue is where a collection and the bucket it records
+// disagree.
+type consistencyIssue struct {
+	Type string `json:"type"`
+	// Records whose object is gone, as <record ID>: s3://<bucket>/<object>
+	MissingObjects []string `json:"missing_objects,omitempty"`
+	// Objects in the bucket no record keeps its data in
+	UnrecordedObjects []string `json:"unrecorded_objects,omitempty"`
+}
+
+// syncedBuckets are those whose unrecorded objects are issues, and which
+// sync records. Workers put task weights in the checkpoints bucket and
+// task artifacts in the artifacts bucket without records.
+var syncedBuckets = map[string]bool{modelsBucket: true, datasetsBucket: true}
+
+// checkConsistency compares the records of the models, datasets,
+// checkpoints and artifacts collections with the objects in the buckets.
+// It returns the bucket listings it compared them with.
+func (s *StorageServer) checkConsistency(ctx context.Context) ([]consistencyIssue, map[string][]ObjectInfo, error) {
+	listings := make(map[string][]ObjectInfo, len(buckets))
+	stored := make(map[string]map[string]bool, len(buckets))
+	for _, bucket := range buckets {
+		objects, err := s.objects.List(ctx, bucket, "")
+		if err != nil {
+			return nil, nil, err
+		}
+		listings[bucket] = objects
+		stored[bucket] = make(map[string]bool, len(objects))
+		for _, obj := range objects {
+			stored[bucket][obj.Key] = true
+		}
+	}
+
+	var issues []consistencyIssue
+	for _, collection := range []string{modelsCollection, datasetsCollection, checkpointsCollection, artifactsCollection} {
+		refs, err := s.recordedObjects(ctx, collection)
+		if err != nil {
+			return nil, nil, err
+		}
+		issue := consistencyIssue{Type: collection}
+		recorded := make(map[string]bool, len(refs))
+		for _, ref := range refs {
+			if ref.Bucket == collection {
+				recorded[ref.Object] = true
+			}
+			if !stored[ref.Bucket][ref.Object] {
+				issue.MissingObjects = append(issue.MissingObjects, ref.ID+": "+minioPath(ref.Bucket, ref.Object))
+			}
+		}
+		if syncedBuckets[collection] {
+			for _, obj := range listings[collection] {
+				if !recorded[obj.Key] {
+					issue.UnrecordedObjects = append(issue.UnrecordedObjects, obj.Key)
+				}
+			}
+		}
+		if len(issue.MissingObjects) > 0 || len(issue.UnrecordedObjects) > 0 {
+			issues = append(issues, issue)
+		}
+	}
+	return issues, listings, nil
+}
+
+func (s *StorageServer) handleValidate(w http.ResponseWriter, r *http.Request) {
+	issues, listings, err := s.checkConsistency(r.Context())
+	if err != nil {
+		log.Printf("Error validating storage: %v", err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	bucketStats := make(map[string]interface{}, len(listings))
+	for bucket, objects := range listings {
+		var total int64
+		for _, obj := range objects {
+			total += obj.Size
+		}
+		bucketStats[bucket] = map[string]interface{}{"object_count": len(objects), "total_size_bytes": total}
+	}
+	collectionStats := make(map[string]interface{}, len(collections))
+	for _, collection := range collections {
+		n, err := s.catalog.count(r.Context(), collection+"/")
+		if err != nil {
+			collectionStats[collection] = map[string]interface{}{"error": err.Error()}
+			continue
+		}
+		collectionStats[collection] = map[string]interface{}{"document_count": n}
+	}
+	if issues == nil {
+		issues = []consistencyIssue{}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"validation": map[string]interface{}{
+			"buckets":            bucketStats,
+			"collections":        collectionStats,
+			"consistency_issues": issues,
+		},
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// syncResult is what sync did for a bucket.
+type syncResult struct {
+	Synced int      `json:"synced"`
+	Errors []string `json:"errors"`
+}
+
+// handleSync records the objects in the models and datasets buckets that
+// have no record. A model is named after its file and, if the file name
+// starts with the ID of a job the service has a record of, described by
+// that job.
+func (s *StorageServer) handleSync(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	issues, listings, err := s.checkConsistency(ctx)
+	if err != nil {
+		log.Printf("Error syncing storage: %v", err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	sizes := make(map[string]map[string]ObjectInfo, len(syncedBuckets))
+	for bucket := range syncedBuckets {
+		sizes[bucket] = make(map[string]ObjectInfo, len(listings[bucket]))
+		for _, obj := range listings[bucket] {
+			sizes[bucket][obj.Key] = obj
+		}
+	}
+
+	results := map[string]*syncResult{
+		modelsBucket:   {Errors: []string{}},
+		datasetsBucket: {Errors: []string{}},
+	}
+	for _, issue := range issues {
+		result := results[issue.Type]
+		for _, object := range issue.UnrecordedObjects {
+			obj := sizes[issue.Type][object]
+			switch issue.Type {
+			case modelsBucket:
+				err = s.syncModel(ctx, obj)
+			case datasetsBucket:
+				err = s.syncDataset(ctx, obj)
+			}
+			if err != nil {
+				result.Errors = append(result.Errors, "Error syncing "+object+": "+err.Error())
+				continue
+			}
+			result.Synced++
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message":   "Data synchronization completed",
+		"results":   results,
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// baseName is the name of an object's file without its extension.
+func baseName(object string) string {
+	name := path.Base(object)
+	if i := strings.LastIndex(name, "."); i > 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+func (s *StorageServer) syncModel(ctx context.Context, obj ObjectInfo) error {
+	model := ModelRecord{
+		Name:            baseName(obj.Key),
+		Hyperparameters: map[string]interface{}{},
+		Metrics:         map[string]interface{}{},
+		Status:          "ready",
+	}
+	jobID, _, _ := strings.Cut(model.Name, "_")
+	var job JobRecord
+	if validID(jobID) {
+		if err := s.catalog.get(ctx, jobKey(jobID), &job); err == nil {
+			model.JobID = job.JobID
+			model.ModelType, model.Algorithm = job.ModelType, job.ModelType
+			model.DatasetPath = job.DatasetPath
+			if job.Hyperparameters != nil {
+				model.Hyperparameters = job.Hyperparameters
+			}
+			model.Metrics = map[string]interface{}{
+				"accuracy":        job.CurrentAccuracy,
+				"loss":            job.CurrentLoss,
+				"completed_tasks": job.CompletedTasks,
+				"total_tasks":     job.TotalTasks,
+			}
+			model.Epochs, model.NumWorkers = job.Epochs, job.NumWorkers
+		} else if !errors.Is(err, errObjectNotFound) {
+			return err
+		}
+	}
+
+	return s.saveModel(ctx, &model, nil, 0, "", &existingObject{ObjectName: obj.Key, SizeBytes: obj.Size})
+}
+
+func (s *StorageServer) syncDataset(ctx context.Context, obj ObjectInfo) error {
+	dataset := DatasetRecord{
+		ID:        newRecordID(),
+		Name:      baseName(obj.Key),
+		Format:    extension(obj.Key, "csv"),
+		CreatedAt: time.Now().UTC(),
+	}
+	if _, err := s.locateDataset(ctx, &dataset, obj.Key); err != nil {
+		return err
+	}
+	if err := s.catalog.put(ctx, datasetKey(dataset.ID), &dataset); err != nil {
+		return err
+	}
+	log.Printf("Synced dataset %s at %s", dataset.Name, dataset.MinioPath)
+	return nil
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DatasetRecord describes a dataset: an object in the datasets bucket or,
+// if registered by location, in another bucket.
+type DatasetRecord struct {
+	ID          string    `json:"_id"`
+	Name        string    `json:"name"`
+	JobName     string    `json:"job_name,omitempty"`
+	MinioPath   string    `json:"minio_path"`
+	MinioBucket string    `json:"minio_bucket"`
+	MinioObject string    `json:"minio_object"`
+	Description string    `json:"description"`
+	Format      string    `json:"format"`
+	SizeBytes   int64     `json:"size_bytes"`
+	Checksum    string    `json:"checksum"`
+	NumRows     *int64    `json:"num_rows"`
+	NumColumns  *int64    `json:"num_columns"`
+	Columns     []string  `json:"columns"`
+	Schema      []Field   `json:"schema,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Field is a column of a dataset's schema.
+type Field struct {
+	Name string `json:"name"`
+	Type string `json:"type,omitempty"`
+}
+
+// datasetRegistration names a dataset that is already stored, instead of
+// uploading it.
+type datasetRegistration struct {
+	Location string `json:"location"`
+}
+
+func datasetKey(id string) string {
+	return recordKey(datasetsCollection, id)
+}
+
+// handleSaveDataset records a dataset uploaded in the file field or, with
+// no file, the one at the metadata's location.
+func (s *StorageServer) handleSaveDataset(w http.ResponseWriter, r *http.Request) {
+	var dataset DatasetRecord
+	var registration datasetRegistration
+	file, header, err := readUpload(r, &dataset, &registration)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if file == nil && registration.Location == "" {
+		writeError(w, http.StatusBadRequest, "No file or location provided")
+		return
+	}
+	if file != nil {
+		defer file.Close()
+		if dataset.Name == "" {
+			dataset.Name = header.Filename
+		}
+		if dataset.Format == "" {
+			dataset.Format = extension(header.Filename, "csv")
+		}
+	} else if dataset.Name == "" {
+		writeError(w, http.StatusBadRequest, "name is required in metadata")
+		return
+	}
+	if len(dataset.Columns) == 0 {
+		for _, f := range dataset.Schema {
+			dataset.Columns = append(dataset.Columns, f.Name)
+		}
+	}
+	if dataset.NumColumns == nil && len(dataset.Columns) > 0 {
+		n := int64(len(dataset.Columns))
+		dataset.NumColumns = &n
+	}
+
+	dataset.ID = newRecordID()
+	dataset.CreatedAt = time.Now().UTC()
+	if file != nil {
+		dataset.MinioBucket = datasetsBucket
+		if dataset.JobName != "" {
+			dataset.MinioObject = objectName(datasetsBucket, dataset.ID, dataset.Format, dataset.JobName, dataset.Name)
+		} else {
+			dataset.MinioObject = objectName(datasetsBucket, dataset.ID, dataset.Format, dataset.Name)
+		}
+		dataset.MinioPath = minioPath(datasetsBucket, dataset.MinioObject)
+		dataset.Checksum, err = s.store(r.Context(), datasetsBucket, dataset.MinioObject, file, header.Size)
+		if err == nil {
+			dataset.SizeBytes = header.Size
+		}
+	} else {
+		if status, err := s.locateDataset(r.Context(), &dataset, registration.Location); err != nil {
+			writeError(w, status, err.Error())
+			return
+		}
+		if dataset.Format == "" {
+			dataset.Format = extension(registration.Location, "csv")
+		}
+	}
+	if err == nil {
+		err = s.catalog.put(r.Context(), datasetKey(dataset.ID), &dataset)
+	}
+	if err != nil {
+		log.Printf("Error saving dataset: %v", err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	log.Printf("Saved dataset %s to %s", dataset.Name, dataset.MinioPath)
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"message": "Dataset saved successfully",
+		"result": map[string]string{
+			"dataset_id":  dataset.ID,
+			"minio_path":  dataset.MinioPath,
+			"object_name": dataset.MinioObject,
+			"checksum":    dataset.Checksum,
+		},
+	})
+}
+
+// locateDataset points a registered dataset at its location, an object in
+// the datasets bucket given as s3://datasets/<object> or by its name.
+// The object must exist; its size is checked against the registration's and
+// filled in. It returns the status to answer with if the location is not
+// usable.
+func (s *StorageServer) locateDataset(ctx context.Context, dataset *DatasetRecord, location string) (int, error) {
+	bucket, name := datasetsBucket, location
+	if rest, ok := strings.CutPrefix(location, "s3://"); ok {
+		bucket, name, _ = strings.Cut(rest, "/")
+	} else if strings.Contains(location, "://") || strings.HasPrefix(location, "/") {
+		return http.StatusBadRequest, errors.New("location must be an object in the datasets bucket, s3://" + datasetsBucket + "/<object>: " + location)
+	}
+	if bucket != datasetsBucket {
+		return http.StatusBadRequest, errors.New("location must be in the " + datasetsBucket + " bucket: " + location)
+	}
+	if !validKey(name) {
+		return http.StatusBadRequest, errors.New("invalid location: " + location)
+	}
+	info, err := s.objects.Stat(ctx, bucket, name)
+	if errors.Is(err, errObjectNotFound) {
+		return http.StatusBadRequest, errors.New("no dataset at " + minioPath(bucket, name))
+	}
+	if err != nil {
+		log.Printf("Error finding dataset %s: %v", location, err)
+		return http.StatusInternalServerError, err
+	}
+	if dataset.SizeBytes != 0 && dataset.SizeBytes != info.Size {
+		return http.StatusBadRequest, fmt.Errorf("size_bytes is %d, but the dataset at %s has %d bytes", dataset.SizeBytes, location, info.Size)
+	}
+	dataset.MinioBucket, dataset.MinioObject = bucket, name
+	dataset.MinioPath = minioPath(bucket, name)
+	dataset.SizeBytes = info.Size
+	return 0, nil
+}
+
+// loadDataset reads a dataset's record, writing the error response if it
+// cannot.
+func (s *StorageServer) loadDataset(w http.ResponseWriter, r *http.Request) (*DatasetRecord, bool) {
+	id := r.PathValue("id")
+	var dataset DatasetRecord
+	err := errObjectNotFound
+	if validID(id) {
+		err = s.catalog.get(r.Context(), datasetKey(id), &dataset)
+	}
+	if errors.Is(err, errObjectNotFound) {
+		writeError(w, http.StatusNotFound, "Dataset not found: "+id)
+		return nil, false
+	}
+	if err != nil {
+		log.Printf("Error loading dataset %s: %v", id, err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+	return &dataset, true
+}
+
+func (s *StorageServer) handleGetDataset(w http.ResponseWriter, r *http.Request) {
+	if dataset, ok := s.loadDataset(w, r); ok {
+		writeJSON(w, http.StatusOK, dataset)
+	}
+}
+
+func (s *StorageServer) handleListDatasets(w http.ResponseWriter, r *http.Request) {
+	datasets, err := listRecords[DatasetRecord](r.Context(), s.catalog, datasetsCollection+"/", limitParam(r, 100), nil)
+	if err != nil {
+		log.Printf("Error listing datasets: %v", err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"datasets": datasets,
+		"count":    len(datasets),
+	})
+}
+
+// datasetObjectName is the object in the datasets bucket a job's dataset
+// path names, if it names one.
+func datasetObjectName(path string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(path, "s3://"), datasetsBucket+"/")
+}
+
+// findDataset returns the registered dataset the dataset path a job refers
+// to resolves to, which may be a location (s3://datasets/...), an object
+// name, a registered dataset's name, or "datasets/<name>". It returns nil
+// if none does.
+func (s *StorageServer) findDataset(ctx context.Context, path string) (*DatasetRecord, error) {
+	name := datasetObjectName(path)
+	base := name[strings.LastIndex(name, "/")+1:]
+	matches, err := listRecords(ctx, s.catalog, datasetsCollection+"/", 1, func(d *DatasetRecord) bool {
+		return d.MinioPath == path || d.MinioObject == name || d.Name == name || d.Name == base
+	})
+	if err != nil || len(matches) == 0 {
+		return nil, err
+	}
+	return matches[0], nil
+}
+
+// handleDatasetInfo resolves the dataset path a job refers to to a
+// registered dataset or, failing that, an object in the datasets bucket.
+func (s *StorageServer) handleDatasetInfo(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Query().Get("path")
+	if path == "" {
+		writeError(w, http.StatusBadRequest, "path is required")
+		return
+	}
+	name := datasetObjectName(path)
+
+	dataset, err := s.findDataset(r.Context(), path)
+	if err != nil {
+		log.Printf("Error finding dataset %s: %v", path, err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if dataset != nil {
+		writeJSON(w, http.StatusOK, dataset)
+		return
+	}
+
+	// Unregistered objects uploaded straight to the bucket
+	info, err := s.objects.Stat(r.Context(), datasetsBucket, name)
+	if errors.Is(err, errObjectNotFound) {
+		writeError(w, http.StatusNotFound, "Dataset not found: "+path)
+		return
+	}
+	if err != nil {
+		log.Printf("Error finding dataset %s: %v", path, err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"name":       name,
+		"minio_path": minioPath(datasetsBucket, name),
+		"size_bytes": info.Size,
+		"num_rows":   nil,
+	})
+}
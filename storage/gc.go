@@ -0,0 +1,467 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// GCPolicy is which models and checkpoints the garbage collector deletes.
+// Each rule is off at its zero value. A model's newest version is never
+// deleted for its age, and pinned checkpoints are never deleted.
+type GCPolicy struct {
+	Interval time.Duration // Between scheduled runs; 0 runs only on request
+	DryRun   bool          // Scheduled runs only report what they would delete
+
+	ModelMaxAge   time.Duration
+	ModelVersions int // Newest versions kept of each model
+
+	CheckpointMaxAge      time.Duration
+	CheckpointsPerJob     int      // Latest checkpoints kept of each job
+	CheckpointJobStatuses []string // Jobs whose checkpoints are all deleted, e.g. COMPLETED
+}
+
+// gcPolicyFromEnv reads the policy from STORAGE_GC_* variables.
+func gcPolicyFromEnv() (GCPolicy, error) {
+	policy := GCPolicy{Interval: time.Hour}
+	durations := map[string]*time.Duration{
+		"STORAGE_GC_INTERVAL":           &policy.Interval,
+		"STORAGE_GC_MODEL_MAX_AGE":      &policy.ModelMaxAge,
+		"STORAGE_GC_CHECKPOINT_MAX_AGE": &policy.CheckpointMaxAge,
+	}
+	for name, d := range durations {
+		if v := os.Getenv(name); v != "" {
+			parsed, err := time.ParseDuration(v)
+			if err != nil || parsed < 0 {
+				return policy, fmt.Errorf("invalid %s %q", name, v)
+			}
+			*d = parsed
+		}
+	}
+	counts := map[string]*int{
+		"STORAGE_GC_MODEL_VERSIONS":      &policy.ModelVersions,
+		"STORAGE_GC_CHECKPOINTS_PER_JOB": &policy.CheckpointsPerJob,
+	}
+	for name, n := range counts {
+		if v := os.Getenv(name); v != "" {
+			parsed, err := strconv.Atoi(v)
+			if err != nil || parsed < 0 {
+				return policy, fmt.Errorf("invalid %s %q", name, v)
+			}
+			*n = parsed
+		}
+	}
+	for _, status := range strings.Split(os.Getenv("STORAGE_GC_CHECKPOINT_JOB_STATUSES"), ",") {
+		if status = strings.ToUpper(strings.TrimSpace(status)); status != "" {
+			policy.CheckpointJobStatuses = append(policy.CheckpointJobStatuses, status)
+		}
+	}
+	policy.DryRun, _ = strconv.ParseBool(os.Getenv("STORAGE_GC_DRY_RUN"))
+	return policy, nil
+}
+
+// MarshalJSON reports durations as strings such as "720h0m0s".
+func (p GCPolicy) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"interval":                p.Interval.String(),
+		"dry_run":                 p.DryRun,
+		"model_max_age":           p.ModelMaxAge.String(),
+		"model_versions":          p.ModelVersions,
+		"checkpoint_max_age":      p.CheckpointMaxAge.String(),
+		"checkpoints_per_job":     p.CheckpointsPerJob,
+		"checkpoint_job_statuses": p.CheckpointJobStatuses,
+	})
+}
+
+// gcCandidate is a model or checkpoint a run deleted, or would delete in a
+// dry run.
+type gcCandidate struct {
+	Kind      string `json:"kind"` // "model" or "checkpoint"
+	ID        string `json:"id"`
+	JobID     string `json:"job_id"`
+	Location  string `json:"location"`
+	SizeBytes int64  `json:"size_bytes"`
+	Reason    string `json:"reason"`
+
+	// Whether deleting it frees its data, which models and registered
+	// checkpoints may share
+	Reclaims bool `json:"reclaims"`
+
+	model      *ModelRecord
+	checkpoint *CheckpointRecord
+}
+
+// GCReport is the outcome of a garbage collection run.
+type GCReport struct {
+	DryRun             bool          `json:"dry_run"`
+	StartedAt          time.Time     `json:"started_at"`
+	DurationSeconds    float64       `json:"duration_seconds"`
+	ModelsDeleted      int           `json:"models_deleted"`
+	CheckpointsDeleted int           `json:"checkpoints_deleted"`
+	BytesReclaimed     int64         `json:"bytes_reclaimed"` // Or that would be, in a dry run
+	Errors             int           `json:"errors"`
+	Candidates         []gcCandidate `json:"candidates"`
+}
+
+// gcMetrics accumulates what garbage collection did, for /metrics.
+type gcMetrics struct {
+	mu             sync.Mutex
+	runs           map[bool]int64   // By dry run
+	deleted        map[string]int64 // By kind
+	reclaimedBytes map[string]int64 // By kind
+	last           *GCReport
+}
+
+func (m *gcMetrics) record(report *GCReport) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.runs == nil {
+		m.runs, m.deleted, m.reclaimedBytes = map[bool]int64{}, map[string]int64{}, map[string]int64{}
+	}
+	m.runs[report.DryRun]++
+	m.last = report
+	if report.DryRun {
+		return
+	}
+	m.deleted["model"] += int64(report.ModelsDeleted)
+	m.deleted["checkpoint"] += int64(report.CheckpointsDeleted)
+	for _, c := range report.Candidates {
+		if c.Reclaims {
+			m.reclaimedBytes[c.Kind] += c.SizeBytes
+		}
+	}
+}
+
+func (m *gcMetrics) lastReport() *GCReport {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.last
+}
+
+// runGC collects garbage every policy interval until ctx is done.
+func (s *StorageServer) runGC(ctx context.Context) {
+	if s.gc.Interval <= 0 {
+		log.Printf("Scheduled garbage collection is off")
+		return
+	}
+	ticker := time.NewTicker(s.gc.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if _, err := s.collectGarbage(ctx, s.gc.DryRun); err != nil {
+			log.Printf("Error collecting garbage: %v", err)
+		}
+	}
+}
+
+// collectGarbage deletes the models and checkpoints the policy selects,
+// or in a dry run only reports them. Candidates are selected under s.mu
+// and deleted after releasing it, so saves are not held up by the deletes.
+func (s *StorageServer) collectGarbage(ctx context.Context, dryRun bool) (*GCReport, error) {
+	report, err := s.selectGarbage(ctx, dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range report.Candidates {
+		c := &report.Candidates[i]
+		if !dryRun {
+			// Whether the data is freed depends on what refers to it now,
+			// e.g. a model saved since with the same weights keeps them
+			reclaimed, err := s.deleteCandidate(ctx, c)
+			c.Reclaims = reclaimed && err == nil
+			if err != nil {
+				log.Printf("Error deleting %s %s: %v", c.Kind, c.ID, err)
+				report.Errors++
+				continue
+			}
+			if c.Kind == "model" {
+				report.ModelsDeleted++
+			} else {
+				report.CheckpointsDeleted++
+			}
+		}
+		if c.Reclaims {
+			report.BytesReclaimed += c.SizeBytes
+		}
+	}
+	report.DurationSeconds = time.Since(report.StartedAt).Seconds()
+	s.gcStats.record(report)
+
+	verb := "Deleted"
+	if dryRun {
+		verb = "Would delete"
+	}
+	log.Printf("Garbage collection: %s %d models and checkpoints, reclaiming %d bytes", verb, len(report.Candidates), report.BytesReclaimed)
+	return report, nil
+}
+
+// selectGarbage lists the records and picks the candidates a run deletes.
+func (s *StorageServer) selectGarbage(ctx context.Context, dryRun bool) (*GCReport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	report := &GCReport{DryRun: dryRun, StartedAt: time.Now().UTC(), Candidates: []gcCandidate{}}
+	models, err := listRecords[ModelRecord](ctx, s.catalog, modelsCollection+"/", 0, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list models: %v", err)
+	}
+	checkpoints, err := listRecords[CheckpointRecord](ctx, s.catalog, checkpointsCollection+"/", 0, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checkpoints: %v", err)
+	}
+	report.Candidates = append(s.gcModels(models, report.StartedAt), s.gcCheckpoints(ctx, checkpoints, models, report.StartedAt)...)
+	return report, nil
+}
+
+// gcModels selects models to delete: those beyond the newest
+// ModelVersions of their name, and those older than ModelMaxAge except each
+// name's newest. Their weights are reclaimed unless a model that is kept
+// shares them.
+func (s *StorageServer) gcModels(models []*ModelRecord, now time.Time) []gcCandidate {
+	byName := make(map[string][]*ModelRecord)
+	for _, m := range models {
+		byName[m.Name] = append(byName[m.Name], m)
+	}
+
+	var candidates []gcCandidate
+	deleted := make(map[string]bool)
+	for _, versions := range byName {
+		sort.SliceStable(versions, func(i, j int) bool { return versions[i].CreatedAt.After(versions[j].CreatedAt) })
+		for i, m := range versions {
+			var reason string
+			switch {
+			case s.gc.ModelVersions > 0 && i >= s.gc.ModelVersions:
+				reason = fmt.Sprintf("beyond the newest %d versions", s.gc.ModelVersions)
+			case s.gc.ModelMaxAge > 0 && i > 0 && now.Sub(m.CreatedAt) > s.gc.ModelMaxAge:
+				reason = "older than " + s.gc.ModelMaxAge.String()
+			default:
+				continue
+			}
+			deleted[m.ID] = true
+			candidates = append(candidates, gcCandidate{
+				Kind: "model", ID: m.ID, JobID: m.JobID, Location: m.MinioPath,
+				SizeBytes: m.SizeBytes, Reason: reason, model: m,
+			})
+		}
+	}
+
+	// Weights are content addressed, so models may share them
+	kept := make(map[string]bool)
+	for _, m := range models {
+		if !deleted[m.ID] {
+			kept[m.MinioBucket+"/"+m.MinioObject] = true
+		}
+	}
+	counted := make(map[string]bool)
+	for i := range candidates {
+		m := candidates[i].model
+		object := m.MinioBucket + "/" + m.MinioObject
+		candidates[i].Reclaims = !kept[object] && !counted[object]
+		counted[object] = true
+	}
+	return candidates
+}
+
+// gcCheckpoints selects checkpoints to delete: all of jobs in one of
+// CheckpointJobStatuses, those beyond the latest CheckpointsPerJob of their
+// job, and those older than CheckpointMaxAge. Pinned checkpoints are kept.
+// The data of registered checkpoints is reclaimed unless a checkpoint that
+// is kept shares it.
+func (s *StorageServer) gcCheckpoints(ctx context.Context, checkpoints []*CheckpointRecord, models []*ModelRecord, now time.Time) []gcCandidate {
+	byJob := make(map[string][]*CheckpointRecord)
+	for _, c := range checkpoints {
+		byJob[c.JobID] = append(byJob[c.JobID], c)
+	}
+	withModel := make(map[string]bool)
+	for _, m := range models {
+		withModel[m.JobID] = true
+	}
+
+	var candidates []gcCandidate
+	for jobID, jobCheckpoints := range byJob {
+		var status string
+		if len(s.gc.CheckpointJobStatuses) > 0 {
+			status = strings.ToUpper(s.jobStatus(ctx, jobID, withModel[jobID]))
+		}
+		sort.SliceStable(jobCheckpoints, func(i, j int) bool { return jobCheckpoints[i].Epoch > jobCheckpoints[j].Epoch })
+		for i, c := range jobCheckpoints {
+			if c.Pinned {
+				continue
+			}
+			var reason string
+			switch {
+			case status != "" && containsString(s.gc.CheckpointJobStatuses, status):
+				reason = "job is " + status
+			case s.gc.CheckpointsPerJob > 0 && i >= s.gc.CheckpointsPerJob:
+				reason = fmt.Sprintf("beyond the latest %d of its job", s.gc.CheckpointsPerJob)
+			case s.gc.CheckpointMaxAge > 0 && now.Sub(c.CreatedAt) > s.gc.CheckpointMaxAge:
+				reason = "older than " + s.gc.CheckpointMaxAge.String()
+			default:
+				continue
+			}
+			candidates = append(candidates, gcCandidate{
+				Kind: "checkpoint", ID: c.ID, JobID: c.JobID, Location: c.MinioPath,
+				SizeBytes: c.SizeBytes, Reason: reason, checkpoint: c,
+			})
+		}
+	}
+
+	deleted := make(map[string]bool, len(candidates))
+	for _, c := range candidates {
+		deleted[c.ID] = true
+	}
+	kept := make(map[string]bool)
+	for _, c := range checkpoints {
+		if !deleted[c.ID] {
+			kept[c.MinioBucket+"/"+c.MinioObject] = true
+		}
+	}
+	counted := make(map[string]bool)
+	for i := range candidates {
+		c := candidates[i].checkpoint
+		object := c.MinioBucket + "/" + c.MinioObject
+		candidates[i].Reclaims = !kept[object] && !counted[object]
+		counted[object] = true
+	}
+	return candidates
+}
+
+// jobStatus returns what the service knows of a job's status: that of its
+// record, or of its archive, or COMPLETED if a model was saved for it, as
+// the orchestrator saves models of completed jobs only. It is empty if the
+// service knows nothing of the job.
+func (s *StorageServer) jobStatus(ctx context.Context, jobID string, hasModel bool) string {
+	var job JobRecord
+	if err := s.catalog.get(ctx, jobKey(jobID), &job); err == nil && job.Status != "" {
+		return job.Status
+	}
+
+	// The orchestrator archives finished jobs to the jobs bucket
+	if body, _, err := s.objects.Get(ctx, jobsBucket, "archive/"+jobID+".json"); err == nil {
+		var archive struct {
+			Job struct {
+				Status string
+			} `json:"job"`
+		}
+		err := json.NewDecoder(body).Decode(&archive)
+		body.Close()
+		if err == nil && archive.Job.Status != "" {
+			return archive.Job.Status
+		}
+	} else if !errors.Is(err, errObjectNotFound) {
+		log.Printf("Warning: Failed to read archive of job %s: %v", jobID, err)
+	}
+
+	if hasModel {
+		return "COMPLETED"
+	}
+	return ""
+}
+
+// deleteCandidate deletes a model or checkpoint chosen for collection, and
+// its data unless another record still refers to it. It returns true if
+// it deleted the data.
+func (s *StorageServer) deleteCandidate(ctx context.Context, c *gcCandidate) (bool, error) {
+	if c.Kind == "model" {
+		if err := s.catalog.delete(ctx, modelKey(c.ID)); err != nil {
+			return false, err
+		}
+		return s.releaseModelObject(ctx, c.model)
+	}
+	// Checkpoints may have been pinned since they were selected
+	var current CheckpointRecord
+	if err := s.catalog.get(ctx, checkpointKey(c.checkpoint.JobID, c.ID), &current); err != nil {
+		return false, err
+	}
+	if current.Pinned {
+		return false, errors.New("pinned since it was selected")
+	}
+	if err := s.catalog.delete(ctx, checkpointKey(c.checkpoint.JobID, c.ID)); err != nil {
+		return false, err
+	}
+	return s.releaseCheckpointObject(ctx, c.checkpoint)
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// handleRunGC collects garbage now. ?dry_run= overrides whether the
+// policy's runs are dry.
+func (s *StorageServer) handleRunGC(w http.ResponseWriter, r *http.Request) {
+	dryRun := s.gc.DryRun
+	if v := r.URL.Query().Get("dry_run"); v != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(v); err != nil {
+			writeError(w, http.StatusBadRequest, "dry_run must be true or false")
+			return
+		}
+	}
+	report, err := s.collectGarbage(r.Context(), dryRun)
+	if err != nil {
+		log.Printf("Error collecting garbage: %v", err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+// handleGCStatus reports the policy and the last run.
+func (s *StorageServer) handleGCStatus(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"policy":   s.gc,
+		"last_run": s.gcStats.lastReport(),
+	})
+}
+
+// handleMetrics serves garbage collection metrics in the Prometheus text
+// format.
+func (s *StorageServer) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	m := &s.gcStats
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	fmt.Fprintln(w, "# HELP storage_gc_runs_total Garbage collection runs")
+	fmt.Fprintln(w, "# TYPE storage_gc_runs_total counter")
+	for _, dryRun := range []bool{false, true} {
+		fmt.Fprintf(w, "storage_gc_runs_total{dry_run=\"%t\"} %d\n", dryRun, m.runs[dryRun])
+	}
+	fmt.Fprintln(w, "# HELP storage_gc_deleted_total Models and checkpoints deleted by garbage collection")
+	fmt.Fprintln(w, "# TYPE storage_gc_deleted_total counter")
+	for _, kind := range []string{"checkpoint", "model"} {
+		fmt.Fprintf(w, "storage_gc_deleted_total{kind=%q} %d\n", kind, m.deleted[kind])
+	}
+	fmt.Fprintln(w, "# HELP storage_gc_reclaimed_bytes_total Bytes of data deleted by garbage collection")
+	fmt.Fprintln(w, "# TYPE storage_gc_reclaimed_bytes_total counter")
+	for _, kind := range []string{"checkpoint", "model"} {
+		fmt.Fprintf(w, "storage_gc_reclaimed_bytes_total{kind=%q} %d\n", kind, m.reclaimedBytes[kind])
+	}
+	if m.last != nil {
+		fmt.Fprintln(w, "# HELP storage_gc_last_run_timestamp_seconds When the last garbage collection run started")
+		fmt.Fprintln(w, "# TYPE storage_gc_last_run_timestamp_seconds gauge")
+		fmt.Fprintf(w, "storage_gc_last_run_timestamp_seconds %d\n", m.last.StartedAt.Unix())
+		fmt.Fprintln(w, "# HELP storage_gc_last_run_reclaimable_bytes Bytes the last run reclaimed, or would have in a dry run")
+		fmt.Fprintln(w, "# TYPE storage_gc_last_run_reclaimable_bytes gauge")
+		fmt.Fprintf(w, "storage_gc_last_run_reclaimable_bytes{dry_run=\"%t\"} %d\n", m.last.DryRun, m.last.BytesReclaimed)
+	}
+}
@@ -0,0 +1,296 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	storagepb "github.com/tensorfleet/storage/proto/storage"
+)
+
+// The orchestrator saves models and checkpoints through StorageService,
+// defined in proto/storage.proto, which records them as the HTTP API does.
+
+// maxGRPCMessage bounds a gRPC request. Requests carry model weights and
+// checkpoints when the orchestrator has no object store to write them to.
+const maxGRPCMessage = 256 << 20
+
+// storageAuthToken is the secret shared with the orchestrator. When set,
+// StorageService calls must carry it.
+var storageAuthToken = os.Getenv("STORAGE_AUTH_TOKEN")
+
+const authMetadataKey = "authorization"
+
+// grpcServer returns a gRPC server serving StorageService.
+func (s *StorageServer) grpcServer() *grpc.Server {
+	server := grpc.NewServer(
+		grpc.MaxRecvMsgSize(maxGRPCMessage),
+		grpc.ChainUnaryInterceptor(recoveryUnaryInterceptor, loggingUnaryInterceptor, authUnaryInterceptor),
+	)
+	storagepb.RegisterStorageServiceServer(server, s)
+	return server
+}
+
+// recoveryUnaryInterceptor turns a handler panic into an INTERNAL error
+// instead of taking down the service.
+func recoveryUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Panic in %s: %v\n%s", info.FullMethod, r, debug.Stack())
+			err = status.Errorf(codes.Internal, "internal error in %s", info.FullMethod)
+		}
+	}()
+	return handler(ctx, req)
+}
+
+// loggingUnaryInterceptor writes one key=value line per RPC.
+func loggingUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	log.Printf("grpc method=%s code=%s duration=%s", info.FullMethod, status.Code(err),
+		time.Since(start).Round(time.Microsecond))
+	return resp, err
+}
+
+// authUnaryInterceptor rejects calls without the storage token.
+func authUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if storageAuthToken == "" {
+		return handler(ctx, req)
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get(authMetadataKey) {
+		if validStorageToken(value) {
+			return handler(ctx, req)
+		}
+	}
+	return nil, status.Error(codes.Unauthenticated, "missing or invalid storage token")
+}
+
+// requireStorageToken rejects HTTP requests without the storage token. It
+// guards only the auto-save and checkpoint upload routes, which services
+// call in place of StorageService; the file, record and GC routes the
+// frontend and workers use stay open.
+func requireStorageToken(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if storageAuthToken != "" && !validStorageToken(r.Header.Get("Authorization")) {
+			writeError(w, http.StatusUnauthorized, "missing or invalid storage token")
+			return
+		}
+		next(w, r)
+	}
+}
+
+// validStorageToken reports whether an authorization value carries the
+// storage token.
+func validStorageToken(value string) bool {
+	token := strings.TrimPrefix(value, "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(token), []byte(storageAuthToken)) == 1
+}
+
+// grpcError is the status of an error saving or reading a record.
+func grpcError(err error) error {
+	switch {
+	case errors.Is(err, errObjectNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, errVersionExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+func (s *StorageServer) SaveModel(ctx context.Context, req *storagepb.SaveModelRequest) (*storagepb.SaveModelResponse, error) {
+	if !validID(req.JobId) {
+		return nil, status.Error(codes.InvalidArgument, "job_id is required")
+	}
+	if !jobCompleted(req.Status) {
+		return nil, status.Errorf(codes.FailedPrecondition, "job %s is %s, not completed", req.JobId, req.Status)
+	}
+	save := &autoSaveRequest{
+		JobID:           req.JobId,
+		JobName:         req.JobName,
+		Namespace:       req.Namespace,
+		ModelType:       req.ModelType,
+		DatasetPath:     req.DatasetPath,
+		Hyperparameters: make(map[string]interface{}, len(req.Hyperparameters)),
+		CurrentAccuracy: req.Accuracy,
+		CurrentLoss:     req.Loss,
+		CompletedTasks:  int(req.CompletedTasks),
+		TotalTasks:      int(req.TotalTasks),
+		Epochs:          int(req.Epochs),
+		NumWorkers:      int(req.NumWorkers),
+		Status:          req.Status,
+		ModelWeights:    req.ModelWeights,
+	}
+	for k, v := range req.Hyperparameters {
+		save.Hyperparameters[k] = v
+	}
+	if obj := req.WeightsObject; obj != nil {
+		if obj.ObjectName == "" {
+			return nil, status.Error(codes.InvalidArgument, "weights_object needs an object_name")
+		}
+		save.existingObject = existingObject{ObjectName: obj.ObjectName, Checksum: obj.Checksum, SizeBytes: obj.SizeBytes}
+	}
+
+	model, created, err := s.autoSaveModel(ctx, save)
+	if err != nil {
+		log.Printf("Error auto-saving model for job %s: %v", req.JobId, err)
+		return nil, grpcError(err)
+	}
+	return &storagepb.SaveModelResponse{Model: modelProto(model), Created: created}, nil
+}
+
+func (s *StorageServer) GetModel(ctx context.Context, req *storagepb.GetModelRequest) (*storagepb.Model, error) {
+	var model ModelRecord
+	err := errObjectNotFound
+	if validID(req.ModelId) {
+		err = s.catalog.get(ctx, modelKey(req.ModelId), &model)
+	}
+	if errors.Is(err, errObjectNotFound) {
+		return nil, status.Error(codes.NotFound, "model not found: "+req.ModelId)
+	}
+	if err != nil {
+		log.Printf("Error loading model %s: %v", req.ModelId, err)
+		return nil, grpcError(err)
+	}
+	return modelProto(&model), nil
+}
+
+func (s *StorageServer) ListModels(ctx context.Context, req *storagepb.ListModelsRequest) (*storagepb.ListModelsResponse, error) {
+	limit := int(req.Limit)
+	if limit < 0 {
+		return nil, status.Error(codes.InvalidArgument, "limit must not be negative")
+	}
+	if limit == 0 {
+		limit = 50
+	}
+	models, err := s.listModels(ctx, modelFilter{JobID: req.JobId, Name: req.Name, Dataset: req.Dataset}, limit)
+	if err != nil {
+		log.Printf("Error listing models: %v", err)
+		return nil, grpcError(err)
+	}
+	resp := &storagepb.ListModelsResponse{Models: make([]*storagepb.Model, 0, len(models))}
+	for _, model := range models {
+		resp.Models = append(resp.Models, modelProto(model))
+	}
+	return resp, nil
+}
+
+func (s *StorageServer) SaveCheckpoint(ctx context.Context, req *storagepb.SaveCheckpointRequest) (*storagepb.SaveCheckpointResponse, error) {
+	if !validID(req.JobId) {
+		return nil, status.Error(codes.InvalidArgument, "job_id is required")
+	}
+	obj := req.DataObject
+	if obj == nil && len(req.Data) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "data or data_object is required")
+	}
+	if obj != nil && obj.ObjectName == "" {
+		return nil, status.Error(codes.InvalidArgument, "data_object needs an object_name")
+	}
+
+	// The record is keyed on the job, epoch and data, so a retried call,
+	// even one racing the call it retries, finds the checkpoint recorded
+	// rather than recording it again
+	data := "object:" + obj.GetObjectName()
+	if obj.GetChecksum() != "" {
+		data = "sha256:" + obj.GetChecksum()
+	} else if obj == nil {
+		digest := sha256.Sum256(req.Data)
+		data = "sha256:" + hex.EncodeToString(digest[:])
+	}
+	id := checkpointID(req.JobId, int(req.Epoch), data)
+	var recorded CheckpointRecord
+	err := s.catalog.get(ctx, checkpointKey(req.JobId, id), &recorded)
+	if err == nil {
+		return checkpointProto(&recorded), nil
+	}
+	if !errors.Is(err, errObjectNotFound) {
+		log.Printf("Error reading checkpoint %s of job %s: %v", id, req.JobId, err)
+		return nil, grpcError(err)
+	}
+
+	checkpoint := CheckpointRecord{
+		ID:          id,
+		JobID:       req.JobId,
+		JobName:     req.JobName,
+		Algorithm:   req.Algorithm,
+		DatasetName: req.DatasetName,
+		Epoch:       int(req.Epoch),
+		Metrics:     make(map[string]interface{}, len(req.Metrics)),
+	}
+	for k, v := range req.Metrics {
+		checkpoint.Metrics[k] = v
+	}
+	if obj != nil {
+		err = s.saveCheckpoint(ctx, &checkpoint, nil, 0, &existingObject{ObjectName: obj.ObjectName, Checksum: obj.Checksum, SizeBytes: obj.SizeBytes})
+	} else {
+		err = s.saveCheckpoint(ctx, &checkpoint, bytes.NewReader(req.Data), int64(len(req.Data)), nil)
+	}
+	if err != nil {
+		log.Printf("Error saving checkpoint: %v", err)
+		return nil, grpcError(err)
+	}
+	return checkpointProto(&checkpoint), nil
+}
+
+func modelProto(m *ModelRecord) *storagepb.Model {
+	model := &storagepb.Model{
+		ModelId:         m.ID,
+		JobId:           m.JobID,
+		JobName:         m.JobName,
+		Name:            m.Name,
+		Version:         m.Version,
+		Algorithm:       m.Algorithm,
+		ModelType:       m.ModelType,
+		DatasetName:     m.DatasetName,
+		DatasetPath:     m.DatasetPath,
+		DatasetId:       m.DatasetID,
+		Hyperparameters: make(map[string]string, len(m.Hyperparameters)),
+		Metrics:         make(map[string]float64, len(m.Metrics)),
+		Epochs:          int32(m.Epochs),
+		NumWorkers:      int32(m.NumWorkers),
+		Location:        m.MinioPath,
+		SizeBytes:       m.SizeBytes,
+		Checksum:        m.Checksum,
+		Status:          m.Status,
+		CreatedAtMs:     m.CreatedAt.UnixMilli(),
+	}
+	for k, v := range m.Hyperparameters {
+		if s, ok := v.(string); ok {
+			model.Hyperparameters[k] = s
+		} else {
+			model.Hyperparameters[k] = fmt.Sprint(v)
+		}
+	}
+	for k, v := range m.Metrics {
+		switch n := v.(type) {
+		case float64:
+			model.Metrics[k] = n
+		case int:
+			model.Metrics[k] = float64(n)
+		}
+	}
+	return model
+}
+
+func checkpointProto(c *CheckpointRecord) *storagepb.SaveCheckpointResponse {
+	return &storagepb.SaveCheckpointResponse{
+		CheckpointId: c.ID,
+		Location:     c.MinioPath,
+		Checksum:     c.Checksum,
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// JobRecord is what the web UI records about a job, apart from the
+// orchestrator's own job store.
+type JobRecord struct {
+	ID              string                 `json:"_id"`
+	JobID           string                 `json:"job_id"`
+	UserID          string                 `json:"user_id"`
+	Status          string                 `json:"status"`
+	ModelType       string                 `json:"model_type,omitempty"`
+	DatasetPath     string                 `json:"dataset_path,omitempty"`
+	Hyperparameters map[string]interface{} `json:"hyperparameters"`
+	NumWorkers      int                    `json:"num_workers"`
+	Epochs          int                    `json:"epochs"`
+	TotalTasks      int                    `json:"total_tasks"`
+	CompletedTasks  int                    `json:"completed_tasks"`
+	Progress        float64                `json:"progress"`
+	CurrentLoss     float64                `json:"current_loss"`
+	CurrentAccuracy float64                `json:"current_accuracy"`
+	CreatedAt       time.Time              `json:"created_at"`
+	UpdatedAt       time.Time              `json:"updated_at"`
+	StartedAt       string                 `json:"started_at,omitempty"`
+	CompletedAt     string                 `json:"completed_at,omitempty"`
+}
+
+func jobKey(jobID string) string {
+	return recordKey(jobsCollection, jobID)
+}
+
+func (s *StorageServer) handleSaveJob(w http.ResponseWriter, r *http.Request) {
+	job := JobRecord{UserID: "anonymous", Status: "PENDING", NumWorkers: 1, Epochs: 1}
+	if err := json.NewDecoder(r.Body).Decode(&job); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid job: "+err.Error())
+		return
+	}
+	if !validID(job.JobID) {
+		writeError(w, http.StatusBadRequest, "job_id is required")
+		return
+	}
+	job.ID = newRecordID()
+	job.CreatedAt = time.Now().UTC()
+	job.UpdatedAt = job.CreatedAt
+
+	if err := s.catalog.put(r.Context(), jobKey(job.JobID), &job); err != nil {
+		log.Printf("Error saving job %s: %v", job.JobID, err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	log.Printf("Saved job %s", job.JobID)
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"message": "Job saved successfully",
+		"_id":     job.ID,
+		"job_id":  job.JobID,
+	})
+}
+
+// loadJob reads a job's record, writing the error response if it cannot.
+func (s *StorageServer) loadJob(w http.ResponseWriter, r *http.Request) (*JobRecord, bool) {
+	jobID := r.PathValue("job_id")
+	var job JobRecord
+	err := errObjectNotFound
+	if validID(jobID) {
+		err = s.catalog.get(r.Context(), jobKey(jobID), &job)
+	}
+	if errors.Is(err, errObjectNotFound) {
+		writeError(w, http.StatusNotFound, "Job not found")
+		return nil, false
+	}
+	if err != nil {
+		log.Printf("Error getting job %s: %v", jobID, err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+	return &job, true
+}
+
+func (s *StorageServer) handleGetJob(w http.ResponseWriter, r *http.Request) {
+	if job, ok := s.loadJob(w, r); ok {
+		writeJSON(w, http.StatusOK, job)
+	}
+}
+
+// handleUpdateJob sets the fields the body gives on a job's record.
+func (s *StorageServer) handleUpdateJob(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, ok := s.loadJob(w, r)
+	if !ok {
+		return
+	}
+	id, jobID, created := job.ID, job.JobID, job.CreatedAt
+	if err := json.NewDecoder(r.Body).Decode(job); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid update: "+err.Error())
+		return
+	}
+	job.ID, job.JobID, job.CreatedAt = id, jobID, created
+	job.UpdatedAt = time.Now().UTC()
+
+	if err := s.catalog.put(r.Context(), jobKey(job.JobID), job); err != nil {
+		log.Printf("Error updating job %s: %v", job.JobID, err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	log.Printf("Updated job %s", job.JobID)
+	writeJSON(w, http.StatusOK, map[string]string{"message": "Job updated successfully"})
+}
+
+// listJobs returns job records newest first, filtered by ?user_id= and
+// ?status=.
+func (s *StorageServer) listJobs(w http.ResponseWriter, r *http.Request, limit int) {
+	userID, status := r.URL.Query().Get("user_id"), r.URL.Query().Get("status")
+	jobs, err := listRecords(r.Context(), s.catalog, jobsCollection+"/", 0, func(j *JobRecord) bool {
+		return (userID == "" || j.UserID == userID) && (status == "" || j.Status == status)
+	})
+	if err != nil {
+		log.Printf("Error listing jobs: %v", err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// Job records are keyed by job ID, not in order of creation
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].CreatedAt.After(jobs[j].CreatedAt) })
+	if len(jobs) > limit {
+		jobs = jobs[:limit]
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"jobs":  jobs,
+		"count": len(jobs),
+	})
+}
+
+func (s *StorageServer) handleListJobs(w http.ResponseWriter, r *http.Request) {
+	s.listJobs(w, r, limitParam(r, 50))
+}
+
+func (s *StorageServer) handleRecentJobs(w http.ResponseWriter, r *http.Request) {
+	s.listJobs(w, r, limitParam(r, 10))
+}
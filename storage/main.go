@@ -0,0 +1,514 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	storagepb "github.com/tensorfleet/storage/proto/storage"
+)
+
+// Buckets services upload to. The catalog bucket is kept apart: it is
+// written through the record endpoints only.
+const (
+	modelsBucket      = "models"
+	datasetsBucket    = "datasets"
+	checkpointsBucket = "checkpoints"
+	artifactsBucket   = "artifacts"
+	jobsBucket        = "jobs"
+)
+
+var buckets = []string{modelsBucket, datasetsBucket, checkpointsBucket, artifactsBucket, jobsBucket}
+
+// maxMemoryUpload is how much of a multipart upload is held in memory; the
+// rest is spooled to disk until it is stored.
+const maxMemoryUpload = 32 << 20
+
+// StorageServer serves the files and records of TensorFleet: datasets,
+// model weights, checkpoints, artifacts and job archives, kept in a
+// pluggable object store backend.
+type StorageServer struct {
+	storagepb.UnimplementedStorageServiceServer
+
+	objects Backend
+	catalog *Catalog
+
+	// Serializes read-modify-write of catalog records, such as job updates
+	// and numbering model versions, and garbage collection runs
+	mu sync.Mutex
+
+	// Jobs whose model is being auto-saved, closed when it is saved
+	savingMu sync.Mutex
+	saving   map[string]chan struct{}
+
+	gc      GCPolicy
+	gcStats gcMetrics
+}
+
+func NewStorageServer(objects Backend, gc GCPolicy) *StorageServer {
+	return &StorageServer{
+		objects: objects,
+		catalog: &Catalog{objects: objects},
+		saving:  make(map[string]chan struct{}),
+		gc:      gc,
+	}
+}
+
+// ensureBuckets creates the buckets the service uses.
+func (s *StorageServer) ensureBuckets(ctx context.Context) error {
+	for _, bucket := range append(buckets, catalogBucket) {
+		if err := s.objects.EnsureBucket(ctx, bucket); err != nil {
+			return fmt.Errorf("failed to create bucket %s: %v", bucket, err)
+		}
+	}
+	return nil
+}
+
+func (s *StorageServer) routes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /health", s.handleHealth)
+
+	// Files
+	mux.HandleFunc("POST /api/v1/upload/{bucket}/{object...}", s.handleUpload)
+	mux.HandleFunc("GET /api/v1/download/{bucket}/{object...}", s.handleDownload)
+	mux.HandleFunc("GET /api/v1/list/{bucket}", s.handleList)
+	mux.HandleFunc("DELETE /api/v1/delete/{bucket}/{object...}", s.handleDelete)
+	mux.HandleFunc("GET /api/v1/buckets", s.handleBuckets)
+	mux.HandleFunc("GET /api/v1/storage/stats", s.handleStats)
+	mux.HandleFunc("GET /api/v1/storage/validate", s.handleValidate)
+	mux.HandleFunc("POST /api/v1/sync", s.handleSync)
+
+	// Records
+	mux.HandleFunc("POST /api/v1/models", s.handleSaveModel)
+	mux.HandleFunc("GET /api/v1/models", s.handleListModels)
+	mux.HandleFunc("GET /api/v1/models/{id}", s.handleDownloadModel)
+	mux.HandleFunc("GET /api/v1/models/{id}/metadata", s.handleGetModel)
+	mux.HandleFunc("GET /api/v1/models/{id}/lineage", s.handleModelLineage)
+	mux.HandleFunc("DELETE /api/v1/models/{id}", s.handleDeleteModel)
+	mux.HandleFunc("POST /api/v1/checkpoints", requireStorageToken(s.handleSaveCheckpoint))
+	mux.HandleFunc("GET /api/v1/checkpoints/{job_id}", s.handleListCheckpoints)
+	mux.HandleFunc("POST /api/v1/checkpoints/{job_id}/cleanup", s.handleCleanupCheckpoints)
+	mux.HandleFunc("GET /api/v1/checkpoints/{job_id}/retention", s.handleGetRetention)
+	mux.HandleFunc("PUT /api/v1/checkpoints/{job_id}/retention", s.handleSetRetention)
+	mux.HandleFunc("DELETE /api/v1/checkpoints/{job_id}/retention", s.handleDeleteRetention)
+	mux.HandleFunc("GET /api/v1/checkpoints/{job_id}/{id}", s.handleDownloadCheckpoint)
+	mux.HandleFunc("DELETE /api/v1/checkpoints/{job_id}/{id}", s.handleDeleteCheckpoint)
+	mux.HandleFunc("PUT /api/v1/checkpoints/{job_id}/{id}/pin", s.handlePinCheckpoint)
+	mux.HandleFunc("DELETE /api/v1/checkpoints/{job_id}/{id}/pin", s.handlePinCheckpoint)
+	mux.HandleFunc("POST /api/v1/artifacts", s.handleSaveArtifact)
+	mux.HandleFunc("GET /api/v1/artifacts/{job_id}", s.handleListArtifacts)
+	mux.HandleFunc("POST /api/v1/datasets", s.handleSaveDataset)
+	mux.HandleFunc("GET /api/v1/datasets", s.handleListDatasets)
+	mux.HandleFunc("GET /api/v1/datasets/info", s.handleDatasetInfo)
+	mux.HandleFunc("GET /api/v1/datasets/{id}", s.handleGetDataset)
+	mux.HandleFunc("POST /api/v1/jobs", s.handleSaveJob)
+	mux.HandleFunc("GET /api/v1/jobs", s.handleListJobs)
+	mux.HandleFunc("GET /api/v1/jobs/recent", s.handleRecentJobs)
+	mux.HandleFunc("GET /api/v1/jobs/{job_id}", s.handleGetJob)
+	mux.HandleFunc("PUT /api/v1/jobs/{job_id}", s.handleUpdateJob)
+	mux.HandleFunc("POST /api/v1/jobs/{job_id}/auto-save-model", requireStorageToken(s.handleAutoSaveModel))
+	mux.HandleFunc("GET /api/v1/gc", s.handleGCStatus)
+	mux.HandleFunc("POST /api/v1/gc", s.handleRunGC)
+	mux.HandleFunc("GET /metrics", s.handleMetrics)
+}
+
+func (s *StorageServer) handleHealth(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := s.objects.Ping(ctx); err != nil {
+		log.Printf("Health check failed: %v", err)
+		writeJSON(w, http.StatusServiceUnavailable, map[string]interface{}{
+			"status":  "unhealthy",
+			"service": "storage",
+			"backend": s.objects.Name(),
+			"error":   err.Error(),
+		})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"status":  "healthy",
+		"service": "storage",
+		"backend": s.objects.Name(),
+	})
+}
+
+// knownBucket reports whether files may be uploaded to the bucket.
+func knownBucket(bucket string) bool {
+	for _, b := range buckets {
+		if b == bucket {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *StorageServer) handleUpload(w http.ResponseWriter, r *http.Request) {
+	bucket, object := r.PathValue("bucket"), r.PathValue("object")
+	if !knownBucket(bucket) {
+		writeError(w, http.StatusBadRequest, "Invalid bucket: "+bucket)
+		return
+	}
+	if !validKey(object) {
+		writeError(w, http.StatusBadRequest, "Invalid object name: "+object)
+		return
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "No file provided")
+		return
+	}
+	defer file.Close()
+	if header.Filename == "" {
+		writeError(w, http.StatusBadRequest, "Empty filename")
+		return
+	}
+
+	if _, err := s.store(r.Context(), bucket, object, file, header.Size); err != nil {
+		log.Printf("Error uploading %s/%s: %v", bucket, object, err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	log.Printf("Uploaded %s to bucket %s (%d bytes)", object, bucket, header.Size)
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"message":     "File uploaded successfully",
+		"bucket":      bucket,
+		"object_name": object,
+		"size":        header.Size,
+	})
+}
+
+func (s *StorageServer) handleDownload(w http.ResponseWriter, r *http.Request) {
+	bucket, object := r.PathValue("bucket"), r.PathValue("object")
+	if !knownBucket(bucket) {
+		writeError(w, http.StatusBadRequest, "Invalid bucket: "+bucket)
+		return
+	}
+	s.serveObject(w, r, bucket, object, object[strings.LastIndex(object, "/")+1:])
+}
+
+// serveObject streams an object as an attachment called name.
+func (s *StorageServer) serveObject(w http.ResponseWriter, r *http.Request, bucket, object, name string) {
+	body, info, err := s.objects.Get(r.Context(), bucket, object)
+	if errors.Is(err, errObjectNotFound) {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("Object not found: %s/%s", bucket, object))
+		return
+	}
+	if err != nil {
+		log.Printf("Error downloading %s/%s: %v", bucket, object, err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer body.Close()
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	if info.Size >= 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(info.Size))
+	}
+	if !info.LastModified.IsZero() {
+		w.Header().Set("Last-Modified", info.LastModified.UTC().Format(http.TimeFormat))
+	}
+	if _, err := io.Copy(w, body); err != nil {
+		log.Printf("Error sending %s/%s: %v", bucket, object, err)
+	}
+}
+
+func (s *StorageServer) handleList(w http.ResponseWriter, r *http.Request) {
+	bucket := r.PathValue("bucket")
+	if !knownBucket(bucket) {
+		writeError(w, http.StatusBadRequest, "Invalid bucket: "+bucket)
+		return
+	}
+
+	objects, err := s.objects.List(r.Context(), bucket, r.URL.Query().Get("prefix"))
+	if err != nil {
+		log.Printf("Error listing bucket %s: %v", bucket, err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	type listedObject struct {
+		Name         string `json:"name"`
+		Size         int64  `json:"size"`
+		LastModified string `json:"last_modified,omitempty"`
+	}
+	listing := make([]listedObject, 0, len(objects))
+	for _, obj := range objects {
+		entry := listedObject{Name: obj.Key, Size: obj.Size}
+		if !obj.LastModified.IsZero() {
+			entry.LastModified = obj.LastModified.UTC().Format(time.RFC3339)
+		}
+		listing = append(listing, entry)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"bucket":  bucket,
+		"objects": listing,
+		"count":   len(listing),
+	})
+}
+
+func (s *StorageServer) handleDelete(w http.ResponseWriter, r *http.Request) {
+	bucket, object := r.PathValue("bucket"), r.PathValue("object")
+	if !knownBucket(bucket) {
+		writeError(w, http.StatusBadRequest, "Invalid bucket: "+bucket)
+		return
+	}
+
+	if err := s.objects.Delete(r.Context(), bucket, object); err != nil {
+		log.Printf("Error deleting %s/%s: %v", bucket, object, err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	log.Printf("Deleted %s from bucket %s", object, bucket)
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message":     "File deleted successfully",
+		"bucket":      bucket,
+		"object_name": object,
+	})
+}
+
+func (s *StorageServer) handleBuckets(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{"buckets": buckets})
+}
+
+func (s *StorageServer) handleStats(w http.ResponseWriter, r *http.Request) {
+	bucketStats := make(map[string]interface{}, len(buckets))
+	for _, bucket := range buckets {
+		objects, err := s.objects.List(r.Context(), bucket, "")
+		if err != nil {
+			log.Printf("Error getting stats for bucket %s: %v", bucket, err)
+			bucketStats[bucket] = map[string]interface{}{"error": err.Error()}
+			continue
+		}
+		var total int64
+		for _, obj := range objects {
+			total += obj.Size
+		}
+		bucketStats[bucket] = map[string]interface{}{
+			"object_count":     len(objects),
+			"total_size_bytes": total,
+			"total_size_mb":    math.Round(float64(total)/(1<<20)*100) / 100,
+		}
+	}
+
+	collectionStats := make(map[string]interface{}, len(collections))
+	for _, collection := range collections {
+		n, err := s.catalog.count(r.Context(), collection+"/")
+		if err != nil {
+			collectionStats[collection] = map[string]interface{}{"error": err.Error()}
+			continue
+		}
+		collectionStats[collection] = map[string]interface{}{"document_count": n}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"backend":     s.objects.Name(),
+		"buckets":     bucketStats,
+		"collections": collectionStats,
+		"timestamp":   time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// store writes size bytes from data to the bucket and returns their
+// SHA-256 in hex.
+func (s *StorageServer) store(ctx context.Context, bucket, object string, data io.Reader, size int64) (string, error) {
+	digest := sha256.New()
+	if err := s.objects.Put(ctx, bucket, object, io.TeeReader(data, digest), size); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(digest.Sum(nil)), nil
+}
+
+// existingObject is how metadata names an object another service already
+// wrote, such as weights the orchestrator put in the object store itself,
+// to be recorded rather than uploaded.
+type existingObject struct {
+	ObjectName string `json:"object_name"`
+	Checksum   string `json:"checksum"`
+	SizeBytes  int64  `json:"size_bytes"`
+}
+
+// resolve checks the object exists in the bucket and fills in its size if
+// the metadata did not give it.
+func (e *existingObject) resolve(ctx context.Context, objects Backend, bucket string) error {
+	info, err := objects.Stat(ctx, bucket, e.ObjectName)
+	if err != nil {
+		return fmt.Errorf("object %s/%s: %w", bucket, e.ObjectName, err)
+	}
+	if e.SizeBytes == 0 {
+		e.SizeBytes = info.Size
+	}
+	return nil
+}
+
+// readUpload reads a record upload: a multipart form with the record's
+// metadata as JSON in the metadata field and, optionally, its data in the
+// file field. file is nil if none was sent; the caller closes it.
+func readUpload(r *http.Request, metadata ...interface{}) (file multipart.File, header *multipart.FileHeader, err error) {
+	if err := r.ParseMultipartForm(maxMemoryUpload); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+		return nil, nil, fmt.Errorf("invalid form: %v", err)
+	}
+	if raw := r.FormValue("metadata"); raw != "" {
+		for _, m := range metadata {
+			if err := json.Unmarshal([]byte(raw), m); err != nil {
+				return nil, nil, fmt.Errorf("invalid metadata: %v", err)
+			}
+		}
+	}
+	file, header, err = r.FormFile("file")
+	if errors.Is(err, http.ErrMissingFile) || errors.Is(err, http.ErrNotMultipart) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid file: %v", err)
+	}
+	return file, header, nil
+}
+
+var (
+	unsafeNameChars     = regexp.MustCompile(`[^\w\-.]+`)
+	repeatedUnderscores = regexp.MustCompile(`_+`)
+)
+
+// sanitizeName makes a name safe to use in an object name.
+func sanitizeName(name string) string {
+	name = strings.Trim(unsafeNameChars.ReplaceAllString(strings.TrimSpace(name), "_"), "_")
+	name = repeatedUnderscores.ReplaceAllString(name, "_")
+	if len(name) > 50 {
+		name = name[:50]
+	}
+	if name == "" {
+		return "unnamed"
+	}
+	return name
+}
+
+// objectName builds a descriptive object name for a record's data from
+// those of parts such as the job name, algorithm and dataset that are set,
+// made unique by the record's ID.
+func objectName(prefix, id, ext string, parts ...string) string {
+	clean := make([]string, 0, len(parts)+1)
+	for _, part := range parts {
+		if part != "" {
+			clean = append(clean, sanitizeName(part))
+		}
+	}
+	clean = append(clean, id)
+	return prefix + "/" + strings.Join(clean, "_") + "." + ext
+}
+
+// extension is the extension of an uploaded file's name, or fallback.
+func extension(filename, fallback string) string {
+	if i := strings.LastIndex(filename, "."); i >= 0 && i < len(filename)-1 {
+		return sanitizeName(filename[i+1:])
+	}
+	return fallback
+}
+
+// minioPath is where an object is, in the s3://bucket/key form services
+// pass around whichever backend holds it.
+func minioPath(bucket, object string) string {
+	return "s3://" + bucket + "/" + object
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
+
+// withCORS lets the web UI, served from another origin, call the service.
+func withCORS(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func main() {
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8081"
+	}
+	grpcPort := os.Getenv("GRPC_PORT")
+	if grpcPort == "" {
+		grpcPort = "50053"
+	}
+
+	objects, err := newBackend(backendNameFromEnv())
+	if err != nil {
+		log.Fatalf("Failed to set up storage backend: %v", err)
+	}
+	gc, err := gcPolicyFromEnv()
+	if err != nil {
+		log.Fatalf("Invalid garbage collection policy: %v", err)
+	}
+	server := NewStorageServer(objects, gc)
+
+	// The object store may still be starting
+	for attempt := 1; ; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		err = server.ensureBuckets(ctx)
+		cancel()
+		if err == nil {
+			break
+		}
+		if attempt == 10 {
+			log.Fatalf("Failed to prepare buckets: %v", err)
+		}
+		log.Printf("Waiting for %s backend: %v", objects.Name(), err)
+		time.Sleep(3 * time.Second)
+	}
+	if err := server.indexModelVersions(context.Background()); err != nil {
+		log.Fatalf("Failed to index model versions: %v", err)
+	}
+
+	go server.runGC(context.Background())
+
+	lis, err := net.Listen("tcp", ":"+grpcPort)
+	if err != nil {
+		log.Fatalf("Failed to listen on :%s: %v", grpcPort, err)
+	}
+	go func() {
+		log.Printf("StorageService listening on :%s", grpcPort)
+		if err := server.grpcServer().Serve(lis); err != nil {
+			log.Fatalf("Failed to serve gRPC: %v", err)
+		}
+	}()
+
+	mux := http.NewServeMux()
+	server.routes(mux)
+
+	log.Printf("Storage service listening on :%s (%s backend)", port, objects.Name())
+	if err := http.ListenAndServe(":"+port, withCORS(mux)); err != nil {
+		log.Fatalf("Failed to serve: %v", err)
+	}
+}
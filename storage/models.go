@@ -0,0 +1,665 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ModelRecord describes a trained model whose weights are in the models
+// bucket. Records are immutable: saving a model under a name it was saved
+// under before records a new version of it, and nothing changes a record
+// once saved. Models the orchestrator saves keep what produced them: the
+// job, its dataset, hyperparameters and final metrics.
+type ModelRecord struct {
+	ID              string                 `json:"_id"`
+	JobID           string                 `json:"job_id"`
+	JobName         string                 `json:"job_name,omitempty"`
+	Name            string                 `json:"name"`
+	MinioPath       string                 `json:"minio_path"`
+	MinioBucket     string                 `json:"minio_bucket"`
+	MinioObject     string                 `json:"minio_object"`
+	Algorithm       string                 `json:"algorithm,omitempty"`
+	ModelType       string                 `json:"model_type,omitempty"`
+	Hyperparameters map[string]interface{} `json:"hyperparameters"`
+	Metrics         map[string]interface{} `json:"metrics"`
+	Version         string                 `json:"version"`
+	DatasetName     string                 `json:"dataset_name,omitempty"`
+	DatasetPath     string                 `json:"dataset_path,omitempty"`
+	DatasetID       string                 `json:"dataset_id,omitempty"` // If the dataset is registered
+	Epochs          int                    `json:"epochs,omitempty"`
+	NumWorkers      int                    `json:"num_workers,omitempty"`
+	SizeBytes       int64                  `json:"size_bytes"`
+	Checksum        string                 `json:"checksum"`
+	Status          string                 `json:"status"`
+	CreatedAt       time.Time              `json:"created_at"`
+	UpdatedAt       time.Time              `json:"updated_at"`
+}
+
+var errVersionExists = errors.New("model version already exists")
+
+// modelKey is where a model's record is in the catalog.
+func modelKey(id string) string {
+	return recordKey(modelsCollection, id)
+}
+
+// modelFilter selects models to list. Empty fields match any model.
+type modelFilter struct {
+	JobID   string
+	Name    string
+	Dataset string // A dataset's path, name or ID
+}
+
+func modelFilterFromQuery(r *http.Request) modelFilter {
+	q := r.URL.Query()
+	return modelFilter{JobID: q.Get("job_id"), Name: q.Get("name"), Dataset: q.Get("dataset")}
+}
+
+func (f modelFilter) match(m *ModelRecord) bool {
+	return (f.JobID == "" || m.JobID == f.JobID) &&
+		(f.Name == "" || m.Name == f.Name) &&
+		(f.Dataset == "" || m.DatasetPath == f.Dataset || m.DatasetName == f.Dataset || m.DatasetID == f.Dataset)
+}
+
+// ModelVersions indexes the versions saved of a model name, so numbering
+// a new version reads one record rather than every model's. Versions of
+// deleted models stay in it, so they are not reused.
+type ModelVersions struct {
+	Name     string            `json:"name"`
+	Versions map[string]string `json:"versions"` // Model ID by version
+}
+
+// modelVersionsKey is where the versions of a model name are indexed. Names
+// are hashed since they may contain anything.
+func modelVersionsKey(name string) string {
+	sum := sha256.Sum256([]byte(name))
+	return recordKey(modelVersionsCollection, hex.EncodeToString(sum[:]))
+}
+
+// modelVersions reads the versions saved of the named model.
+func (s *StorageServer) modelVersions(ctx context.Context, name string) (*ModelVersions, error) {
+	versions := &ModelVersions{Name: name}
+	err := s.catalog.get(ctx, modelVersionsKey(name), versions)
+	if err != nil && !errors.Is(err, errObjectNotFound) {
+		return nil, err
+	}
+	if versions.Versions == nil {
+		versions.Versions = map[string]string{}
+	}
+	return versions, nil
+}
+
+// nextVersion numbers a new version of the named model, one past the
+// highest numbered version saved so far. If version is given instead, it
+// checks that no version of the model has it.
+func (v *ModelVersions) nextVersion(version string) (string, error) {
+	if version != "" {
+		if _, ok := v.Versions[version]; ok {
+			return "", fmt.Errorf("%w: %s version %s", errVersionExists, v.Name, version)
+		}
+		return version, nil
+	}
+	latest := 0
+	for saved := range v.Versions {
+		if n, err := strconv.Atoi(saved); err == nil && n > latest {
+			latest = n
+		}
+	}
+	return strconv.Itoa(latest + 1), nil
+}
+
+// indexModelVersions builds the version index from the models collection
+// if it has not been built yet, as when upgrading from a release without
+// it.
+func (s *StorageServer) indexModelVersions(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if n, err := s.catalog.count(ctx, modelVersionsCollection+"/"); err != nil || n > 0 {
+		return err
+	}
+	models, err := listRecords[ModelRecord](ctx, s.catalog, modelsCollection+"/", 0, nil)
+	if err != nil {
+		return err
+	}
+	index := make(map[string]*ModelVersions)
+	for _, m := range models {
+		if index[m.Name] == nil {
+			index[m.Name] = &ModelVersions{Name: m.Name, Versions: map[string]string{}}
+		}
+		index[m.Name].Versions[m.Version] = m.ID
+	}
+	for name, versions := range index {
+		if err := s.catalog.put(ctx, modelVersionsKey(name), versions); err != nil {
+			return err
+		}
+	}
+	if len(index) > 0 {
+		log.Printf("Indexed the versions of %d models", len(index))
+	}
+	return nil
+}
+
+// saveModel records a new version of a model. Its weights are size bytes
+// read from data, stored with the extension ext, or, if data is nil, the
+// existing object. It fills in the record's ID, version, location, size
+// and checksum, and the ID of its dataset if that is registered. The
+// weights are stored first; s.mu is held only to number the version and
+// record it.
+func (s *StorageServer) saveModel(ctx context.Context, model *ModelRecord, data io.Reader, size int64, ext string, existing *existingObject) error {
+	now := time.Now().UTC()
+	model.ID = newRecordID()
+	model.MinioBucket = modelsBucket
+	if model.Name == "" {
+		model.Name = "model_" + model.JobID
+	}
+	// Fail before uploading if the version asked for is taken
+	if model.Version != "" {
+		versions, err := s.modelVersions(ctx, model.Name)
+		if err != nil {
+			return err
+		}
+		if _, err := versions.nextVersion(model.Version); err != nil {
+			return err
+		}
+	}
+	if model.Status == "" {
+		model.Status = "trained"
+	}
+	if model.DatasetID == "" && model.DatasetPath != "" {
+		if dataset, err := s.findDataset(ctx, model.DatasetPath); err != nil {
+			log.Printf("Warning: Failed to resolve dataset %s of model %s: %v", model.DatasetPath, model.ID, err)
+		} else if dataset != nil {
+			model.DatasetID = dataset.ID
+		}
+	}
+	model.CreatedAt, model.UpdatedAt = now, now
+
+	if data != nil {
+		model.MinioObject = objectName(modelsBucket, model.ID, ext, model.JobName, model.Algorithm, model.DatasetName)
+		digest, err := s.store(ctx, modelsBucket, model.MinioObject, data, size)
+		if err != nil {
+			return err
+		}
+		model.SizeBytes, model.Checksum = size, digest
+	} else {
+		if err := existing.resolve(ctx, s.objects, modelsBucket); err != nil {
+			return err
+		}
+		model.MinioObject, model.SizeBytes, model.Checksum = existing.ObjectName, existing.SizeBytes, existing.Checksum
+	}
+	model.MinioPath = minioPath(modelsBucket, model.MinioObject)
+
+	if err := s.recordModel(ctx, model); err != nil {
+		if data != nil {
+			if err := s.objects.Delete(ctx, modelsBucket, model.MinioObject); err != nil {
+				log.Printf("Warning: Failed to delete weights of unsaved model %s: %v", model.ID, err)
+			}
+		}
+		return err
+	}
+	log.Printf("Saved model %s version %s of job %s to %s", model.Name, model.Version, model.JobID, model.MinioPath)
+	return nil
+}
+
+// recordModel numbers the version of a model whose weights are stored and
+// records it. The index is written first, so a failure to record the
+// model skips its version number rather than letting it be reused.
+func (s *StorageServer) recordModel(ctx context.Context, model *ModelRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	versions, err := s.modelVersions(ctx, model.Name)
+	if err != nil {
+		return err
+	}
+	if model.Version, err = versions.nextVersion(model.Version); err != nil {
+		return err
+	}
+	versions.Versions[model.Version] = model.ID
+	if err := s.catalog.put(ctx, modelVersionsKey(model.Name), versions); err != nil {
+		return fmt.Errorf("failed to index model version: %v", err)
+	}
+	if err := s.catalog.put(ctx, modelKey(model.ID), model); err != nil {
+		return fmt.Errorf("failed to record model: %v", err)
+	}
+	return nil
+}
+
+// listModels returns the models the filter selects, newest first.
+func (s *StorageServer) listModels(ctx context.Context, filter modelFilter, limit int) ([]*ModelRecord, error) {
+	return listRecords(ctx, s.catalog, modelsCollection+"/", limit, filter.match)
+}
+
+func (s *StorageServer) handleSaveModel(w http.ResponseWriter, r *http.Request) {
+	var model ModelRecord
+	file, header, err := readUpload(r, &model)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if file == nil {
+		writeError(w, http.StatusBadRequest, "No file provided")
+		return
+	}
+	defer file.Close()
+	if model.JobID == "" {
+		writeError(w, http.StatusBadRequest, "job_id is required in metadata")
+		return
+	}
+
+	err = s.saveModel(r.Context(), &model, file, header.Size, extension(header.Filename, "bin"), nil)
+	if errors.Is(err, errVersionExists) {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+	if err != nil {
+		log.Printf("Error saving model: %v", err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"message": "Model saved successfully",
+		"result": map[string]string{
+			"model_id":    model.ID,
+			"version":     model.Version,
+			"minio_path":  model.MinioPath,
+			"object_name": model.MinioObject,
+			"checksum":    model.Checksum,
+		},
+	})
+}
+
+// limitParam reads a positive limit from the query, or returns fallback.
+func limitParam(r *http.Request, fallback int) int {
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		return fallback
+	}
+	return limit
+}
+
+// handleListModels lists models newest first, only those of ?job_id=,
+// ?name= and ?dataset= if given.
+func (s *StorageServer) handleListModels(w http.ResponseWriter, r *http.Request) {
+	models, err := s.listModels(r.Context(), modelFilterFromQuery(r), limitParam(r, 50))
+	if err != nil {
+		log.Printf("Error listing models: %v", err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"models": models,
+		"count":  len(models),
+	})
+}
+
+// loadModel reads a model's record, writing the error response if it
+// cannot.
+func (s *StorageServer) loadModel(w http.ResponseWriter, r *http.Request) (*ModelRecord, bool) {
+	id := r.PathValue("id")
+	var model ModelRecord
+	err := errObjectNotFound
+	if validID(id) {
+		err = s.catalog.get(r.Context(), modelKey(id), &model)
+	}
+	if errors.Is(err, errObjectNotFound) {
+		writeError(w, http.StatusNotFound, "Model not found: "+id)
+		return nil, false
+	}
+	if err != nil {
+		log.Printf("Error loading model %s: %v", id, err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+	return &model, true
+}
+
+func (s *StorageServer) handleGetModel(w http.ResponseWriter, r *http.Request) {
+	if model, ok := s.loadModel(w, r); ok {
+		writeJSON(w, http.StatusOK, model)
+	}
+}
+
+// modelVersion is one version of a model in its lineage.
+type modelVersion struct {
+	ModelID   string    `json:"model_id"`
+	Version   string    `json:"version"`
+	JobID     string    `json:"job_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// modelLineage is what produced a model.
+type modelLineage struct {
+	ModelID         string                 `json:"model_id"`
+	Name            string                 `json:"name"`
+	Version         string                 `json:"version"`
+	Job             lineageJob             `json:"job"`
+	Dataset         lineageDataset         `json:"dataset"`
+	Hyperparameters map[string]interface{} `json:"hyperparameters"`
+	Metrics         map[string]interface{} `json:"metrics"`
+	Versions        []modelVersion         `json:"versions"` // Of the model's name, newest first
+}
+
+type lineageJob struct {
+	JobID      string     `json:"job_id"`
+	JobName    string     `json:"job_name,omitempty"`
+	Epochs     int        `json:"epochs,omitempty"`
+	NumWorkers int        `json:"num_workers,omitempty"`
+	Record     *JobRecord `json:"record,omitempty"` // If the web UI recorded the job
+}
+
+type lineageDataset struct {
+	Path   string         `json:"path,omitempty"`
+	Name   string         `json:"name,omitempty"`
+	ID     string         `json:"dataset_id,omitempty"`
+	Record *DatasetRecord `json:"record,omitempty"` // If the dataset is registered
+}
+
+// handleModelLineage reports the job and dataset that produced a model,
+// how it was trained, and the other versions of it.
+func (s *StorageServer) handleModelLineage(w http.ResponseWriter, r *http.Request) {
+	model, ok := s.loadModel(w, r)
+	if !ok {
+		return
+	}
+	ctx := r.Context()
+	lineage := modelLineage{
+		ModelID:         model.ID,
+		Name:            model.Name,
+		Version:         model.Version,
+		Job:             lineageJob{JobID: model.JobID, JobName: model.JobName, Epochs: model.Epochs, NumWorkers: model.NumWorkers},
+		Dataset:         lineageDataset{Path: model.DatasetPath, Name: model.DatasetName, ID: model.DatasetID},
+		Hyperparameters: model.Hyperparameters,
+		Metrics:         model.Metrics,
+		Versions:        []modelVersion{},
+	}
+
+	var job JobRecord
+	if err := s.catalog.get(ctx, jobKey(model.JobID), &job); err == nil {
+		lineage.Job.Record = &job
+	} else if !errors.Is(err, errObjectNotFound) {
+		log.Printf("Warning: Failed to read job %s of model %s: %v", model.JobID, model.ID, err)
+	}
+
+	// The dataset may have been registered after the model was saved
+	var dataset DatasetRecord
+	if model.DatasetID != "" {
+		if err := s.catalog.get(ctx, datasetKey(model.DatasetID), &dataset); err == nil {
+			lineage.Dataset.Record = &dataset
+		} else if !errors.Is(err, errObjectNotFound) {
+			log.Printf("Warning: Failed to read dataset %s of model %s: %v", model.DatasetID, model.ID, err)
+		}
+	} else if model.DatasetPath != "" {
+		if d, err := s.findDataset(ctx, model.DatasetPath); err != nil {
+			log.Printf("Warning: Failed to resolve dataset %s of model %s: %v", model.DatasetPath, model.ID, err)
+		} else if d != nil {
+			lineage.Dataset.ID, lineage.Dataset.Record = d.ID, d
+		}
+	}
+
+	versions, err := s.listModels(ctx, modelFilter{Name: model.Name}, 0)
+	if err != nil {
+		log.Printf("Error listing versions of model %s: %v", model.Name, err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	// Record IDs order models by the second they were saved in only
+	sort.SliceStable(versions, func(i, j int) bool { return versions[i].CreatedAt.After(versions[j].CreatedAt) })
+	for _, v := range versions {
+		lineage.Versions = append(lineage.Versions, modelVersion{ModelID: v.ID, Version: v.Version, JobID: v.JobID, CreatedAt: v.CreatedAt})
+	}
+	writeJSON(w, http.StatusOK, lineage)
+}
+
+func (s *StorageServer) handleDownloadModel(w http.ResponseWriter, r *http.Request) {
+	model, ok := s.loadModel(w, r)
+	if !ok {
+		return
+	}
+	name := model.MinioObject[strings.LastIndex(model.MinioObject, "/")+1:]
+	s.serveObject(w, r, model.MinioBucket, model.MinioObject, name)
+}
+
+func (s *StorageServer) handleDeleteModel(w http.ResponseWriter, r *http.Request) {
+	model, ok := s.loadModel(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.catalog.delete(r.Context(), modelKey(model.ID)); err != nil {
+		log.Printf("Error deleting model %s: %v", model.ID, err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if _, err := s.releaseModelObject(r.Context(), model); err != nil {
+		log.Printf("Warning: Failed to delete weights of model %s: %v", model.ID, err)
+	}
+	log.Printf("Deleted model %s of job %s", model.ID, model.JobID)
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"message":  "Model deleted successfully",
+		"model_id": model.ID,
+	})
+}
+
+// releaseModelObject deletes the weights of a deleted model unless another
+// model still refers to them: weights the orchestrator writes are content
+// addressed, so jobs that trained identical weights share one object. It
+// returns true if it deleted them.
+func (s *StorageServer) releaseModelObject(ctx context.Context, model *ModelRecord) (bool, error) {
+	others, err := listRecords(ctx, s.catalog, modelsCollection+"/", 1, func(m *ModelRecord) bool {
+		return m.MinioBucket == model.MinioBucket && m.MinioObject == model.MinioObject
+	})
+	if err != nil || len(others) > 0 {
+		return false, err
+	}
+	return true, s.objects.Delete(ctx, model.MinioBucket, model.MinioObject)
+}
+
+// autoSaveRequest is what the orchestrator sends when a job completes.
+type autoSaveRequest struct {
+	JobID           string                 `json:"job_id"`
+	JobName         string                 `json:"job_name"`
+	Namespace       string                 `json:"namespace"`
+	ModelType       string                 `json:"model_type"`
+	DatasetPath     string                 `json:"dataset_path"`
+	Hyperparameters map[string]interface{} `json:"hyperparameters"`
+	CurrentAccuracy float64                `json:"current_accuracy"`
+	CurrentLoss     float64                `json:"current_loss"`
+	CompletedTasks  int                    `json:"completed_tasks"`
+	TotalTasks      int                    `json:"total_tasks"`
+	Epochs          int                    `json:"epochs"`
+	NumWorkers      int                    `json:"num_workers"`
+	Status          string                 `json:"status"`
+
+	// The final weights, when the orchestrator has no object store to
+	// write them to itself
+	ModelWeights []byte `json:"model_weights,omitempty"`
+
+	// Weights the orchestrator already wrote to the models bucket
+	existingObject
+}
+
+// datasetName is the name of the dataset at a path, without directories
+// or extension.
+func datasetName(path string) string {
+	name := path[strings.LastIndex(path, "/")+1:]
+	if i := strings.Index(name, "."); i > 0 {
+		name = name[:i]
+	}
+	if name == "" {
+		return "unknown_dataset"
+	}
+	return name
+}
+
+// jobCompleted reports whether a job of the status has a model to save:
+// it ran all its epochs or stopped early once its metric plateaued.
+func jobCompleted(status string) bool {
+	return status == "COMPLETED" || status == "COMPLETED_EARLY"
+}
+
+// autoSaveModel records the model of a completed job, named after its
+// namespace, model type and dataset, unless one was saved for the job
+// before, in which case that model is returned and created is false.
+func (s *StorageServer) autoSaveModel(ctx context.Context, req *autoSaveRequest) (model *ModelRecord, created bool, err error) {
+	done, err := s.lockAutoSave(ctx, req.JobID)
+	if err != nil {
+		return nil, false, err
+	}
+	defer done()
+
+	existingModels, err := s.listModels(ctx, modelFilter{JobID: req.JobID}, 1)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(existingModels) > 0 {
+		return existingModels[0], false, nil
+	}
+
+	if req.JobName == "" {
+		req.JobName = req.JobID
+	}
+	if req.ModelType == "" {
+		req.ModelType = "unknown"
+	}
+	dataset := datasetName(req.DatasetPath)
+	// Jobs of a namespace training the same model type on the same dataset
+	// save versions of one model
+	owner := req.JobName
+	if req.Namespace != "" {
+		owner = req.Namespace
+	}
+	model = &ModelRecord{
+		JobID:           req.JobID,
+		JobName:         req.JobName,
+		Name:            strings.ReplaceAll(owner, " ", "_") + "_" + req.ModelType + "_" + dataset,
+		Algorithm:       req.ModelType,
+		ModelType:       "trained",
+		Hyperparameters: req.Hyperparameters,
+		Metrics: map[string]interface{}{
+			"accuracy":        req.CurrentAccuracy,
+			"loss":            req.CurrentLoss,
+			"completed_tasks": req.CompletedTasks,
+			"total_tasks":     req.TotalTasks,
+		},
+		DatasetName: dataset,
+		DatasetPath: req.DatasetPath,
+		Epochs:      req.Epochs,
+		NumWorkers:  req.NumWorkers,
+	}
+
+	var data []byte
+	ext := "weights"
+	switch {
+	case req.ObjectName != "":
+	case len(req.ModelWeights) > 0:
+		data = req.ModelWeights
+	default:
+		ext = "json"
+		// Without weights the model is a summary of how it was trained
+		data, err = json.MarshalIndent(map[string]interface{}{
+			"job_id":                req.JobID,
+			"model_type":            req.ModelType,
+			"final_metrics":         model.Metrics,
+			"hyperparameters":       req.Hyperparameters,
+			"training_completed_at": time.Now().UTC().Format(time.RFC3339),
+			"auto_saved":            true,
+			"dataset_path":          req.DatasetPath,
+			"epochs":                req.Epochs,
+			"num_workers":           req.NumWorkers,
+		}, "", "  ")
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	var weights io.Reader
+	if data != nil {
+		weights = bytes.NewReader(data)
+	}
+	if err := s.saveModel(ctx, model, weights, int64(len(data)), ext, &req.existingObject); err != nil {
+		return nil, false, err
+	}
+	log.Printf("Automatically saved model of completed job %s", req.JobID)
+	return model, true, nil
+}
+
+// lockAutoSave waits until no other call is auto-saving the job's model and
+// claims it, so a retried call finds the model the call it retries saved
+// rather than saving a second one. done releases it.
+func (s *StorageServer) lockAutoSave(ctx context.Context, jobID string) (done func(), err error) {
+	for {
+		s.savingMu.Lock()
+		saving, busy := s.saving[jobID]
+		if !busy {
+			saved := make(chan struct{})
+			s.saving[jobID] = saved
+			s.savingMu.Unlock()
+			return func() {
+				s.savingMu.Lock()
+				delete(s.saving, jobID)
+				s.savingMu.Unlock()
+				close(saved)
+			}, nil
+		}
+		s.savingMu.Unlock()
+
+		select {
+		case <-saving:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (s *StorageServer) handleAutoSaveModel(w http.ResponseWriter, r *http.Request) {
+	jobID := r.PathValue("job_id")
+	var req autoSaveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Job data is required")
+		return
+	}
+	if req.JobID != jobID {
+		writeError(w, http.StatusBadRequest, "Job ID mismatch")
+		return
+	}
+	if !jobCompleted(req.Status) {
+		writeError(w, http.StatusBadRequest, "Job must be completed to save model")
+		return
+	}
+
+	model, created, err := s.autoSaveModel(r.Context(), &req)
+	if err != nil {
+		log.Printf("Error auto-saving model for job %s: %v", jobID, err)
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !created {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"message":  "Model already exists for this job",
+			"model_id": model.ID,
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"message":    "Model automatically saved successfully",
+		"model_id":   model.ID,
+		"version":    model.Version,
+		"minio_path": model.MinioPath,
+	})
+}
@@ -0,0 +1,990 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.33.1
+// source: storage.proto
+
+package storage
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// StoredObject names data the caller already wrote to the object store.
+type StoredObject struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	ObjectName string                 `protobuf:"bytes,1,opt,name=object_name,json=objectName,proto3" json:"object_name,omitempty"`
+	// SHA-256 of the data, in hex.
+	Checksum      string `protobuf:"bytes,2,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	SizeBytes     int64  `protobuf:"varint,3,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StoredObject) Reset() {
+	*x = StoredObject{}
+	mi := &file_storage_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StoredObject) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StoredObject) ProtoMessage() {}
+
+func (x *StoredObject) ProtoReflect() protoreflect.Message {
+	mi := &file_storage_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StoredObject.ProtoReflect.Descriptor instead.
+func (*StoredObject) Descriptor() ([]byte, []int) {
+	return file_storage_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *StoredObject) GetObjectName() string {
+	if x != nil {
+		return x.ObjectName
+	}
+	return ""
+}
+
+func (x *StoredObject) GetChecksum() string {
+	if x != nil {
+		return x.Checksum
+	}
+	return ""
+}
+
+func (x *StoredObject) GetSizeBytes() int64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+type SaveModelRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	JobId   string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	JobName string                 `protobuf:"bytes,2,opt,name=job_name,json=jobName,proto3" json:"job_name,omitempty"`
+	// Jobs of a namespace training a model type on a dataset save versions
+	// of one model.
+	Namespace       string            `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	ModelType       string            `protobuf:"bytes,4,opt,name=model_type,json=modelType,proto3" json:"model_type,omitempty"`
+	DatasetPath     string            `protobuf:"bytes,5,opt,name=dataset_path,json=datasetPath,proto3" json:"dataset_path,omitempty"`
+	Hyperparameters map[string]string `protobuf:"bytes,6,rep,name=hyperparameters,proto3" json:"hyperparameters,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Final metrics of the job.
+	Accuracy       float64 `protobuf:"fixed64,7,opt,name=accuracy,proto3" json:"accuracy,omitempty"`
+	Loss           float64 `protobuf:"fixed64,8,opt,name=loss,proto3" json:"loss,omitempty"`
+	CompletedTasks int32   `protobuf:"varint,9,opt,name=completed_tasks,json=completedTasks,proto3" json:"completed_tasks,omitempty"`
+	TotalTasks     int32   `protobuf:"varint,10,opt,name=total_tasks,json=totalTasks,proto3" json:"total_tasks,omitempty"`
+	Epochs         int32   `protobuf:"varint,11,opt,name=epochs,proto3" json:"epochs,omitempty"`
+	NumWorkers     int32   `protobuf:"varint,12,opt,name=num_workers,json=numWorkers,proto3" json:"num_workers,omitempty"`
+	// The final weights, or the object in the models bucket holding them.
+	// Without either the model is a summary of how it was trained.
+	ModelWeights  []byte        `protobuf:"bytes,13,opt,name=model_weights,json=modelWeights,proto3" json:"model_weights,omitempty"`
+	WeightsObject *StoredObject `protobuf:"bytes,14,opt,name=weights_object,json=weightsObject,proto3" json:"weights_object,omitempty"`
+	// Status of the job; only COMPLETED and COMPLETED_EARLY jobs save a
+	// model.
+	Status        string `protobuf:"bytes,15,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SaveModelRequest) Reset() {
+	*x = SaveModelRequest{}
+	mi := &file_storage_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SaveModelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SaveModelRequest) ProtoMessage() {}
+
+func (x *SaveModelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_storage_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SaveModelRequest.ProtoReflect.Descriptor instead.
+func (*SaveModelRequest) Descriptor() ([]byte, []int) {
+	return file_storage_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SaveModelRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *SaveModelRequest) GetJobName() string {
+	if x != nil {
+		return x.JobName
+	}
+	return ""
+}
+
+func (x *SaveModelRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *SaveModelRequest) GetModelType() string {
+	if x != nil {
+		return x.ModelType
+	}
+	return ""
+}
+
+func (x *SaveModelRequest) GetDatasetPath() string {
+	if x != nil {
+		return x.DatasetPath
+	}
+	return ""
+}
+
+func (x *SaveModelRequest) GetHyperparameters() map[string]string {
+	if x != nil {
+		return x.Hyperparameters
+	}
+	return nil
+}
+
+func (x *SaveModelRequest) GetAccuracy() float64 {
+	if x != nil {
+		return x.Accuracy
+	}
+	return 0
+}
+
+func (x *SaveModelRequest) GetLoss() float64 {
+	if x != nil {
+		return x.Loss
+	}
+	return 0
+}
+
+func (x *SaveModelRequest) GetCompletedTasks() int32 {
+	if x != nil {
+		return x.CompletedTasks
+	}
+	return 0
+}
+
+func (x *SaveModelRequest) GetTotalTasks() int32 {
+	if x != nil {
+		return x.TotalTasks
+	}
+	return 0
+}
+
+func (x *SaveModelRequest) GetEpochs() int32 {
+	if x != nil {
+		return x.Epochs
+	}
+	return 0
+}
+
+func (x *SaveModelRequest) GetNumWorkers() int32 {
+	if x != nil {
+		return x.NumWorkers
+	}
+	return 0
+}
+
+func (x *SaveModelRequest) GetModelWeights() []byte {
+	if x != nil {
+		return x.ModelWeights
+	}
+	return nil
+}
+
+func (x *SaveModelRequest) GetWeightsObject() *StoredObject {
+	if x != nil {
+		return x.WeightsObject
+	}
+	return nil
+}
+
+func (x *SaveModelRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type SaveModelResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Model *Model                 `protobuf:"bytes,1,opt,name=model,proto3" json:"model,omitempty"`
+	// False if the job's model had been saved before.
+	Created       bool `protobuf:"varint,2,opt,name=created,proto3" json:"created,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SaveModelResponse) Reset() {
+	*x = SaveModelResponse{}
+	mi := &file_storage_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SaveModelResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SaveModelResponse) ProtoMessage() {}
+
+func (x *SaveModelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_storage_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SaveModelResponse.ProtoReflect.Descriptor instead.
+func (*SaveModelResponse) Descriptor() ([]byte, []int) {
+	return file_storage_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SaveModelResponse) GetModel() *Model {
+	if x != nil {
+		return x.Model
+	}
+	return nil
+}
+
+func (x *SaveModelResponse) GetCreated() bool {
+	if x != nil {
+		return x.Created
+	}
+	return false
+}
+
+type GetModelRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ModelId       string                 `protobuf:"bytes,1,opt,name=model_id,json=modelId,proto3" json:"model_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetModelRequest) Reset() {
+	*x = GetModelRequest{}
+	mi := &file_storage_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetModelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetModelRequest) ProtoMessage() {}
+
+func (x *GetModelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_storage_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetModelRequest.ProtoReflect.Descriptor instead.
+func (*GetModelRequest) Descriptor() ([]byte, []int) {
+	return file_storage_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetModelRequest) GetModelId() string {
+	if x != nil {
+		return x.ModelId
+	}
+	return ""
+}
+
+type Model struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	ModelId     string                 `protobuf:"bytes,1,opt,name=model_id,json=modelId,proto3" json:"model_id,omitempty"`
+	JobId       string                 `protobuf:"bytes,2,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	JobName     string                 `protobuf:"bytes,3,opt,name=job_name,json=jobName,proto3" json:"job_name,omitempty"`
+	Name        string                 `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	Version     string                 `protobuf:"bytes,5,opt,name=version,proto3" json:"version,omitempty"`
+	Algorithm   string                 `protobuf:"bytes,6,opt,name=algorithm,proto3" json:"algorithm,omitempty"`
+	ModelType   string                 `protobuf:"bytes,7,opt,name=model_type,json=modelType,proto3" json:"model_type,omitempty"`
+	DatasetName string                 `protobuf:"bytes,8,opt,name=dataset_name,json=datasetName,proto3" json:"dataset_name,omitempty"`
+	DatasetPath string                 `protobuf:"bytes,9,opt,name=dataset_path,json=datasetPath,proto3" json:"dataset_path,omitempty"`
+	// Set if the dataset is registered.
+	DatasetId       string            `protobuf:"bytes,10,opt,name=dataset_id,json=datasetId,proto3" json:"dataset_id,omitempty"`
+	Hyperparameters map[string]string `protobuf:"bytes,11,rep,name=hyperparameters,proto3" json:"hyperparameters,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Numeric metrics only.
+	Metrics    map[string]float64 `protobuf:"bytes,12,rep,name=metrics,proto3" json:"metrics,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
+	Epochs     int32              `protobuf:"varint,13,opt,name=epochs,proto3" json:"epochs,omitempty"`
+	NumWorkers int32              `protobuf:"varint,14,opt,name=num_workers,json=numWorkers,proto3" json:"num_workers,omitempty"`
+	// Where the weights are, as s3://<bucket>/<object>.
+	Location      string `protobuf:"bytes,15,opt,name=location,proto3" json:"location,omitempty"`
+	SizeBytes     int64  `protobuf:"varint,16,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+	Checksum      string `protobuf:"bytes,17,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	Status        string `protobuf:"bytes,18,opt,name=status,proto3" json:"status,omitempty"`
+	CreatedAtMs   int64  `protobuf:"varint,19,opt,name=created_at_ms,json=createdAtMs,proto3" json:"created_at_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Model) Reset() {
+	*x = Model{}
+	mi := &file_storage_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Model) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Model) ProtoMessage() {}
+
+func (x *Model) ProtoReflect() protoreflect.Message {
+	mi := &file_storage_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Model.ProtoReflect.Descriptor instead.
+func (*Model) Descriptor() ([]byte, []int) {
+	return file_storage_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Model) GetModelId() string {
+	if x != nil {
+		return x.ModelId
+	}
+	return ""
+}
+
+func (x *Model) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *Model) GetJobName() string {
+	if x != nil {
+		return x.JobName
+	}
+	return ""
+}
+
+func (x *Model) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Model) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *Model) GetAlgorithm() string {
+	if x != nil {
+		return x.Algorithm
+	}
+	return ""
+}
+
+func (x *Model) GetModelType() string {
+	if x != nil {
+		return x.ModelType
+	}
+	return ""
+}
+
+func (x *Model) GetDatasetName() string {
+	if x != nil {
+		return x.DatasetName
+	}
+	return ""
+}
+
+func (x *Model) GetDatasetPath() string {
+	if x != nil {
+		return x.DatasetPath
+	}
+	return ""
+}
+
+func (x *Model) GetDatasetId() string {
+	if x != nil {
+		return x.DatasetId
+	}
+	return ""
+}
+
+func (x *Model) GetHyperparameters() map[string]string {
+	if x != nil {
+		return x.Hyperparameters
+	}
+	return nil
+}
+
+func (x *Model) GetMetrics() map[string]float64 {
+	if x != nil {
+		return x.Metrics
+	}
+	return nil
+}
+
+func (x *Model) GetEpochs() int32 {
+	if x != nil {
+		return x.Epochs
+	}
+	return 0
+}
+
+func (x *Model) GetNumWorkers() int32 {
+	if x != nil {
+		return x.NumWorkers
+	}
+	return 0
+}
+
+func (x *Model) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *Model) GetSizeBytes() int64 {
+	if x != nil {
+		return x.SizeBytes
+	}
+	return 0
+}
+
+func (x *Model) GetChecksum() string {
+	if x != nil {
+		return x.Checksum
+	}
+	return ""
+}
+
+func (x *Model) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Model) GetCreatedAtMs() int64 {
+	if x != nil {
+		return x.CreatedAtMs
+	}
+	return 0
+}
+
+type ListModelsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Filters; empty ones match any model. dataset matches a dataset's path,
+	// name or ID.
+	JobId   string `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	Name    string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Dataset string `protobuf:"bytes,3,opt,name=dataset,proto3" json:"dataset,omitempty"`
+	// 0 for the default of 50.
+	Limit         int32 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListModelsRequest) Reset() {
+	*x = ListModelsRequest{}
+	mi := &file_storage_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListModelsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListModelsRequest) ProtoMessage() {}
+
+func (x *ListModelsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_storage_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListModelsRequest.ProtoReflect.Descriptor instead.
+func (*ListModelsRequest) Descriptor() ([]byte, []int) {
+	return file_storage_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListModelsRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *ListModelsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ListModelsRequest) GetDataset() string {
+	if x != nil {
+		return x.Dataset
+	}
+	return ""
+}
+
+func (x *ListModelsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListModelsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Models        []*Model               `protobuf:"bytes,1,rep,name=models,proto3" json:"models,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListModelsResponse) Reset() {
+	*x = ListModelsResponse{}
+	mi := &file_storage_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListModelsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListModelsResponse) ProtoMessage() {}
+
+func (x *ListModelsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_storage_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListModelsResponse.ProtoReflect.Descriptor instead.
+func (*ListModelsResponse) Descriptor() ([]byte, []int) {
+	return file_storage_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListModelsResponse) GetModels() []*Model {
+	if x != nil {
+		return x.Models
+	}
+	return nil
+}
+
+type SaveCheckpointRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	JobId       string                 `protobuf:"bytes,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	JobName     string                 `protobuf:"bytes,2,opt,name=job_name,json=jobName,proto3" json:"job_name,omitempty"`
+	Algorithm   string                 `protobuf:"bytes,3,opt,name=algorithm,proto3" json:"algorithm,omitempty"`
+	DatasetName string                 `protobuf:"bytes,4,opt,name=dataset_name,json=datasetName,proto3" json:"dataset_name,omitempty"`
+	Epoch       int32                  `protobuf:"varint,5,opt,name=epoch,proto3" json:"epoch,omitempty"`
+	Metrics     map[string]float64     `protobuf:"bytes,6,rep,name=metrics,proto3" json:"metrics,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed64,2,opt,name=value"`
+	// The checkpoint's data, or the object in the checkpoints bucket holding
+	// it; one is required.
+	Data          []byte        `protobuf:"bytes,7,opt,name=data,proto3" json:"data,omitempty"`
+	DataObject    *StoredObject `protobuf:"bytes,8,opt,name=data_object,json=dataObject,proto3" json:"data_object,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SaveCheckpointRequest) Reset() {
+	*x = SaveCheckpointRequest{}
+	mi := &file_storage_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SaveCheckpointRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SaveCheckpointRequest) ProtoMessage() {}
+
+func (x *SaveCheckpointRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_storage_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SaveCheckpointRequest.ProtoReflect.Descriptor instead.
+func (*SaveCheckpointRequest) Descriptor() ([]byte, []int) {
+	return file_storage_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SaveCheckpointRequest) GetJobId() string {
+	if x != nil {
+		return x.JobId
+	}
+	return ""
+}
+
+func (x *SaveCheckpointRequest) GetJobName() string {
+	if x != nil {
+		return x.JobName
+	}
+	return ""
+}
+
+func (x *SaveCheckpointRequest) GetAlgorithm() string {
+	if x != nil {
+		return x.Algorithm
+	}
+	return ""
+}
+
+func (x *SaveCheckpointRequest) GetDatasetName() string {
+	if x != nil {
+		return x.DatasetName
+	}
+	return ""
+}
+
+func (x *SaveCheckpointRequest) GetEpoch() int32 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+func (x *SaveCheckpointRequest) GetMetrics() map[string]float64 {
+	if x != nil {
+		return x.Metrics
+	}
+	return nil
+}
+
+func (x *SaveCheckpointRequest) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *SaveCheckpointRequest) GetDataObject() *StoredObject {
+	if x != nil {
+		return x.DataObject
+	}
+	return nil
+}
+
+type SaveCheckpointResponse struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	CheckpointId string                 `protobuf:"bytes,1,opt,name=checkpoint_id,json=checkpointId,proto3" json:"checkpoint_id,omitempty"`
+	// Where the data is, as s3://<bucket>/<object>.
+	Location      string `protobuf:"bytes,2,opt,name=location,proto3" json:"location,omitempty"`
+	Checksum      string `protobuf:"bytes,3,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SaveCheckpointResponse) Reset() {
+	*x = SaveCheckpointResponse{}
+	mi := &file_storage_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SaveCheckpointResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SaveCheckpointResponse) ProtoMessage() {}
+
+func (x *SaveCheckpointResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_storage_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SaveCheckpointResponse.ProtoReflect.Descriptor instead.
+func (*SaveCheckpointResponse) Descriptor() ([]byte, []int) {
+	return file_storage_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SaveCheckpointResponse) GetCheckpointId() string {
+	if x != nil {
+		return x.CheckpointId
+	}
+	return ""
+}
+
+func (x *SaveCheckpointResponse) GetLocation() string {
+	if x != nil {
+		return x.Location
+	}
+	return ""
+}
+
+func (x *SaveCheckpointResponse) GetChecksum() string {
+	if x != nil {
+		return x.Checksum
+	}
+	return ""
+}
+
+var File_storage_proto protoreflect.FileDescriptor
+
+const file_storage_proto_rawDesc = "" +
+	"\n" +
+	"\rstorage.proto\x12\astorage\"j\n" +
+	"\fStoredObject\x12\x1f\n" +
+	"\vobject_name\x18\x01 \x01(\tR\n" +
+	"objectName\x12\x1a\n" +
+	"\bchecksum\x18\x02 \x01(\tR\bchecksum\x12\x1d\n" +
+	"\n" +
+	"size_bytes\x18\x03 \x01(\x03R\tsizeBytes\"\xf0\x04\n" +
+	"\x10SaveModelRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x19\n" +
+	"\bjob_name\x18\x02 \x01(\tR\ajobName\x12\x1c\n" +
+	"\tnamespace\x18\x03 \x01(\tR\tnamespace\x12\x1d\n" +
+	"\n" +
+	"model_type\x18\x04 \x01(\tR\tmodelType\x12!\n" +
+	"\fdataset_path\x18\x05 \x01(\tR\vdatasetPath\x12X\n" +
+	"\x0fhyperparameters\x18\x06 \x03(\v2..storage.SaveModelRequest.HyperparametersEntryR\x0fhyperparameters\x12\x1a\n" +
+	"\baccuracy\x18\a \x01(\x01R\baccuracy\x12\x12\n" +
+	"\x04loss\x18\b \x01(\x01R\x04loss\x12'\n" +
+	"\x0fcompleted_tasks\x18\t \x01(\x05R\x0ecompletedTasks\x12\x1f\n" +
+	"\vtotal_tasks\x18\n" +
+	" \x01(\x05R\n" +
+	"totalTasks\x12\x16\n" +
+	"\x06epochs\x18\v \x01(\x05R\x06epochs\x12\x1f\n" +
+	"\vnum_workers\x18\f \x01(\x05R\n" +
+	"numWorkers\x12#\n" +
+	"\rmodel_weights\x18\r \x01(\fR\fmodelWeights\x12<\n" +
+	"\x0eweights_object\x18\x0e \x01(\v2\x15.storage.StoredObjectR\rweightsObject\x12\x16\n" +
+	"\x06status\x18\x0f \x01(\tR\x06status\x1aB\n" +
+	"\x14HyperparametersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"S\n" +
+	"\x11SaveModelResponse\x12$\n" +
+	"\x05model\x18\x01 \x01(\v2\x0e.storage.ModelR\x05model\x12\x18\n" +
+	"\acreated\x18\x02 \x01(\bR\acreated\",\n" +
+	"\x0fGetModelRequest\x12\x19\n" +
+	"\bmodel_id\x18\x01 \x01(\tR\amodelId\"\xf6\x05\n" +
+	"\x05Model\x12\x19\n" +
+	"\bmodel_id\x18\x01 \x01(\tR\amodelId\x12\x15\n" +
+	"\x06job_id\x18\x02 \x01(\tR\x05jobId\x12\x19\n" +
+	"\bjob_name\x18\x03 \x01(\tR\ajobName\x12\x12\n" +
+	"\x04name\x18\x04 \x01(\tR\x04name\x12\x18\n" +
+	"\aversion\x18\x05 \x01(\tR\aversion\x12\x1c\n" +
+	"\talgorithm\x18\x06 \x01(\tR\talgorithm\x12\x1d\n" +
+	"\n" +
+	"model_type\x18\a \x01(\tR\tmodelType\x12!\n" +
+	"\fdataset_name\x18\b \x01(\tR\vdatasetName\x12!\n" +
+	"\fdataset_path\x18\t \x01(\tR\vdatasetPath\x12\x1d\n" +
+	"\n" +
+	"dataset_id\x18\n" +
+	" \x01(\tR\tdatasetId\x12M\n" +
+	"\x0fhyperparameters\x18\v \x03(\v2#.storage.Model.HyperparametersEntryR\x0fhyperparameters\x125\n" +
+	"\ametrics\x18\f \x03(\v2\x1b.storage.Model.MetricsEntryR\ametrics\x12\x16\n" +
+	"\x06epochs\x18\r \x01(\x05R\x06epochs\x12\x1f\n" +
+	"\vnum_workers\x18\x0e \x01(\x05R\n" +
+	"numWorkers\x12\x1a\n" +
+	"\blocation\x18\x0f \x01(\tR\blocation\x12\x1d\n" +
+	"\n" +
+	"size_bytes\x18\x10 \x01(\x03R\tsizeBytes\x12\x1a\n" +
+	"\bchecksum\x18\x11 \x01(\tR\bchecksum\x12\x16\n" +
+	"\x06status\x18\x12 \x01(\tR\x06status\x12\"\n" +
+	"\rcreated_at_ms\x18\x13 \x01(\x03R\vcreatedAtMs\x1aB\n" +
+	"\x14HyperparametersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a:\n" +
+	"\fMetricsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\"n\n" +
+	"\x11ListModelsRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x18\n" +
+	"\adataset\x18\x03 \x01(\tR\adataset\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\"<\n" +
+	"\x12ListModelsResponse\x12&\n" +
+	"\x06models\x18\x01 \x03(\v2\x0e.storage.ModelR\x06models\"\xef\x02\n" +
+	"\x15SaveCheckpointRequest\x12\x15\n" +
+	"\x06job_id\x18\x01 \x01(\tR\x05jobId\x12\x19\n" +
+	"\bjob_name\x18\x02 \x01(\tR\ajobName\x12\x1c\n" +
+	"\talgorithm\x18\x03 \x01(\tR\talgorithm\x12!\n" +
+	"\fdataset_name\x18\x04 \x01(\tR\vdatasetName\x12\x14\n" +
+	"\x05epoch\x18\x05 \x01(\x05R\x05epoch\x12E\n" +
+	"\ametrics\x18\x06 \x03(\v2+.storage.SaveCheckpointRequest.MetricsEntryR\ametrics\x12\x12\n" +
+	"\x04data\x18\a \x01(\fR\x04data\x126\n" +
+	"\vdata_object\x18\b \x01(\v2\x15.storage.StoredObjectR\n" +
+	"dataObject\x1a:\n" +
+	"\fMetricsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x01R\x05value:\x028\x01\"u\n" +
+	"\x16SaveCheckpointResponse\x12#\n" +
+	"\rcheckpoint_id\x18\x01 \x01(\tR\fcheckpointId\x12\x1a\n" +
+	"\blocation\x18\x02 \x01(\tR\blocation\x12\x1a\n" +
+	"\bchecksum\x18\x03 \x01(\tR\bchecksum2\xa4\x02\n" +
+	"\x0eStorageService\x12B\n" +
+	"\tSaveModel\x12\x19.storage.SaveModelRequest\x1a\x1a.storage.SaveModelResponse\x124\n" +
+	"\bGetModel\x12\x18.storage.GetModelRequest\x1a\x0e.storage.Model\x12E\n" +
+	"\n" +
+	"ListModels\x12\x1a.storage.ListModelsRequest\x1a\x1b.storage.ListModelsResponse\x12Q\n" +
+	"\x0eSaveCheckpoint\x12\x1e.storage.SaveCheckpointRequest\x1a\x1f.storage.SaveCheckpointResponseB.Z,github.com/tensorfleet/storage/proto/storageb\x06proto3"
+
+var (
+	file_storage_proto_rawDescOnce sync.Once
+	file_storage_proto_rawDescData []byte
+)
+
+func file_storage_proto_rawDescGZIP() []byte {
+	file_storage_proto_rawDescOnce.Do(func() {
+		file_storage_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_storage_proto_rawDesc), len(file_storage_proto_rawDesc)))
+	})
+	return file_storage_proto_rawDescData
+}
+
+var file_storage_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_storage_proto_goTypes = []any{
+	(*StoredObject)(nil),           // 0: storage.StoredObject
+	(*SaveModelRequest)(nil),       // 1: storage.SaveModelRequest
+	(*SaveModelResponse)(nil),      // 2: storage.SaveModelResponse
+	(*GetModelRequest)(nil),        // 3: storage.GetModelRequest
+	(*Model)(nil),                  // 4: storage.Model
+	(*ListModelsRequest)(nil),      // 5: storage.ListModelsRequest
+	(*ListModelsResponse)(nil),     // 6: storage.ListModelsResponse
+	(*SaveCheckpointRequest)(nil),  // 7: storage.SaveCheckpointRequest
+	(*SaveCheckpointResponse)(nil), // 8: storage.SaveCheckpointResponse
+	nil,                            // 9: storage.SaveModelRequest.HyperparametersEntry
+	nil,                            // 10: storage.Model.HyperparametersEntry
+	nil,                            // 11: storage.Model.MetricsEntry
+	nil,                            // 12: storage.SaveCheckpointRequest.MetricsEntry
+}
+var file_storage_proto_depIdxs = []int32{
+	9,  // 0: storage.SaveModelRequest.hyperparameters:type_name -> storage.SaveModelRequest.HyperparametersEntry
+	0,  // 1: storage.SaveModelRequest.weights_object:type_name -> storage.StoredObject
+	4,  // 2: storage.SaveModelResponse.model:type_name -> storage.Model
+	10, // 3: storage.Model.hyperparameters:type_name -> storage.Model.HyperparametersEntry
+	11, // 4: storage.Model.metrics:type_name -> storage.Model.MetricsEntry
+	4,  // 5: storage.ListModelsResponse.models:type_name -> storage.Model
+	12, // 6: storage.SaveCheckpointRequest.metrics:type_name -> storage.SaveCheckpointRequest.MetricsEntry
+	0,  // 7: storage.SaveCheckpointRequest.data_object:type_name -> storage.StoredObject
+	1,  // 8: storage.StorageService.SaveModel:input_type -> storage.SaveModelRequest
+	3,  // 9: storage.StorageService.GetModel:input_type -> storage.GetModelRequest
+	5,  // 10: storage.StorageService.ListModels:input_type -> storage.ListModelsRequest
+	7,  // 11: storage.StorageService.SaveCheckpoint:input_type -> storage.SaveCheckpointRequest
+	2,  // 12: storage.StorageService.SaveModel:output_type -> storage.SaveModelResponse
+	4,  // 13: storage.StorageService.GetModel:output_type -> storage.Model
+	6,  // 14: storage.StorageService.ListModels:output_type -> storage.ListModelsResponse
+	8,  // 15: storage.StorageService.SaveCheckpoint:output_type -> storage.SaveCheckpointResponse
+	12, // [12:16] is the sub-list for method output_type
+	8,  // [8:12] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_storage_proto_init() }
+func file_storage_proto_init() {
+	if File_storage_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_storage_proto_rawDesc), len(file_storage_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   13,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_storage_proto_goTypes,
+		DependencyIndexes: file_storage_proto_depIdxs,
+		MessageInfos:      file_storage_proto_msgTypes,
+	}.Build()
+	File_storage_proto = out.File
+	file_storage_proto_goTypes = nil
+	file_storage_proto_depIdxs = nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"math/rand"
+	"os"
+	"time"
+)
+
+// reconnectMaxBackoffFromEnv reads WORKER_RECONNECT_MAX_BACKOFF, the longest
+// the worker waits before reopening a broken task stream.
+func reconnectMaxBackoffFromEnv() time.Duration {
+	d, err := time.ParseDuration(os.Getenv("WORKER_RECONNECT_MAX_BACKOFF"))
+	if err != nil || d < time.Second {
+		return time.Minute
+	}
+	return d
+}
+
+// backoff spaces out retries of a failing call: each delay doubles up to a
+// cap, and is jittered so that workers that failed together, e.g. when the
+// orchestrator restarted, do not retry in lockstep.
+type backoff struct {
+	base time.Duration
+	max  time.Duration
+
+	attempt int
+}
+
+func newBackoff(base, max time.Duration) *backoff {
+	return &backoff{base: base, max: max}
+}
+
+// next is how long to wait before the next retry: a random delay between
+// half and all of the current step.
+func (b *backoff) next() time.Duration {
+	d := b.max
+	if b.attempt < 32 {
+		if step := b.base << b.attempt; step > 0 && step < b.max {
+			d = step
+		}
+	}
+	b.attempt++
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// reset starts the delays over after a success.
+func (b *backoff) reset() {
+	b.attempt = 0
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var tasksResumed = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "worker_tasks_resumed_total",
+	Help: "Tasks resumed from a local checkpoint of an interrupted attempt",
+}, []string{"job_id"})
+
+func init() {
+	prometheus.MustRegister(tasksResumed)
+}
+
+// taskCheckpointMetaFile records which task input a checkpoint belongs to.
+const taskCheckpointMetaFile = ".tensorfleet-checkpoint.json"
+
+// taskCheckpointSweepInterval is how often checkpoints of tasks that never
+// came back are looked for.
+const taskCheckpointSweepInterval = time.Hour
+
+// taskCheckpointDirFromEnv reads WORKER_TASK_CHECKPOINT_DIR, where tasks
+// keep intermediate state that survives their interruption. Empty disables
+// task checkpoints.
+func taskCheckpointDirFromEnv() string {
+	dir, ok := os.LookupEnv("WORKER_TASK_CHECKPOINT_DIR")
+	if !ok {
+		return "/var/lib/tensorfleet/task-checkpoints"
+	}
+	return dir
+}
+
+// taskCheckpointMaxAgeFromEnv reads WORKER_TASK_CHECKPOINT_MAX_AGE, how long
+// the checkpoint of an interrupted task is kept for it to come back.
+func taskCheckpointMaxAgeFromEnv() time.Duration {
+	d, err := time.ParseDuration(os.Getenv("WORKER_TASK_CHECKPOINT_MAX_AGE"))
+	if err != nil || d <= 0 {
+		return 24 * time.Hour
+	}
+	return d
+}
+
+// taskCheckpoints gives each task a local directory that outlives an
+// attempt. An attempt that is interrupted, by preemption, shutdown or a
+// crash of the worker, leaves its state there, and the next attempt of the
+// same task on this worker resumes from it instead of starting the shard
+// again. A task that completes or fails outright has its directory removed;
+// those of tasks that never come back are removed after maxAge.
+type taskCheckpoints struct {
+	dir    string
+	maxAge time.Duration
+}
+
+// checkpointInput is what a checkpoint was made from. A checkpoint only
+// resumes an attempt with the same input, e.g. not one of a task whose
+// starting weights changed when its epoch was rerun.
+type checkpointInput struct {
+	TaskID        string `json:"task_id"`
+	JobID         string `json:"job_id"`
+	Epoch         int32  `json:"epoch"`
+	BatchStart    int32  `json:"batch_start"`
+	BatchEnd      int32  `json:"batch_end"`
+	WeightsSHA256 string `json:"weights_sha256"`
+}
+
+func newTaskCheckpoints(dir string, maxAge time.Duration) *taskCheckpoints {
+	if dir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		log.Printf("Task checkpoints disabled: %v", err)
+		return nil
+	}
+	return &taskCheckpoints{dir: dir, maxAge: maxAge}
+}
+
+func (c *taskCheckpoints) taskDir(taskID string) string {
+	sum := sha256.Sum256([]byte(taskID))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:16]))
+}
+
+// open returns the task's checkpoint directory and whether it holds state
+// of an earlier attempt to resume from. A checkpoint of different input is
+// discarded.
+func (c *taskCheckpoints) open(spec TaskSpec) (string, bool, error) {
+	sum := sha256.Sum256(spec.ModelWeights)
+	input := checkpointInput{
+		TaskID:        spec.TaskID,
+		JobID:         spec.JobID,
+		Epoch:         spec.Epoch,
+		BatchStart:    spec.BatchStart,
+		BatchEnd:      spec.BatchEnd,
+		WeightsSHA256: hex.EncodeToString(sum[:]),
+	}
+	dir := c.taskDir(spec.TaskID)
+	meta := filepath.Join(dir, taskCheckpointMetaFile)
+
+	var saved checkpointInput
+	if data, err := os.ReadFile(meta); err == nil && json.Unmarshal(data, &saved) == nil && saved == input {
+		entries, err := os.ReadDir(dir)
+		if err == nil {
+			now := time.Now()
+			os.Chtimes(dir, now, now)
+			return dir, len(entries) > 1, nil
+		}
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return "", false, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", false, err
+	}
+	data, err := json.Marshal(input)
+	if err != nil {
+		return "", false, err
+	}
+	if err := os.WriteFile(meta, data, 0o644); err != nil {
+		return "", false, fmt.Errorf("failed to write checkpoint metadata: %v", err)
+	}
+	return dir, false, nil
+}
+
+// remove discards the task's checkpoint.
+func (c *taskCheckpoints) remove(taskID string) {
+	if err := os.RemoveAll(c.taskDir(taskID)); err != nil {
+		log.Printf("Failed to remove checkpoint of task %s: %v", taskID, err)
+	}
+}
+
+// run removes checkpoints left longer than maxAge, now and then every
+// taskCheckpointSweepInterval, until ctx is done.
+func (c *taskCheckpoints) run(ctx context.Context) {
+	if c == nil {
+		return
+	}
+	for {
+		c.sweep()
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(taskCheckpointSweepInterval):
+		}
+	}
+}
+
+func (c *taskCheckpoints) sweep() {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil || time.Since(info.ModTime()) <= c.maxAge {
+			continue
+		}
+		log.Printf("Removing checkpoint %s, unused for %s", e.Name(), time.Since(info.ModTime()).Round(time.Minute))
+		os.RemoveAll(filepath.Join(c.dir, e.Name()))
+	}
+}
@@ -0,0 +1,290 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/yaml.v3"
+)
+
+var configReloads = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "worker_config_reloads_total",
+	Help: "Reloads of the worker's configuration file, by result",
+}, []string{"result"})
+
+func init() {
+	prometheus.MustRegister(configReloads)
+}
+
+// configSettings are the environment variables the configuration file can
+// set, by their key in the file: the variable's name in lower case, without
+// its WORKER_ prefix. WORKER_AUTH_TOKEN and WORKER_METRICS_JOB_LABELS are
+// read before the file and can only come from the environment.
+var configSettings = func() map[string]string {
+	vars := []string{
+		"ORCHESTRATOR_ADDR", "STORAGE_SERVICE_URL", "PORT",
+		"WORKER_ADVERTISE_ADDR", "WORKER_EXECUTOR", "WORKER_EVAL_EXECUTOR", "WORKER_LABELS", "WORKER_TASK_TYPES",
+		"WORKER_MAX_CONCURRENT_TASKS", "WORKER_PREFETCH_TASKS", "WORKER_RECONNECT_MAX_BACKOFF",
+		"WORKER_ORCHESTRATOR_CONTACT_TIMEOUT", "WORKER_SHUTDOWN_TIMEOUT",
+		"WORKER_PROGRESS_INTERVAL", "WORKER_DISK_PATH", "WORKER_PPROF",
+		"WORKER_THROTTLE_CPU_PERCENT", "WORKER_THROTTLE_MEMORY_PERCENT", "WORKER_THROTTLE_GPU_PERCENT",
+		"WORKER_DATASET_CACHE_DIR", "WORKER_DATASET_CACHE_SIZE_MB",
+		"WORKER_INLINE_WEIGHTS_MAX_BYTES", "WORKER_WORKSPACE_DIR", "WORKER_ARTIFACT_MAX_BYTES",
+		"WORKER_REPORT_SPOOL_DIR", "WORKER_REPORT_MAX_AGE",
+		"WORKER_TASK_CHECKPOINT_DIR", "WORKER_TASK_CHECKPOINT_MAX_AGE",
+		"WORKER_PREEMPTIBLE", "WORKER_PREEMPTIBLE_CHECKPOINT_INTERVAL",
+		"WORKER_LOG_SHIPPING", "WORKER_LOG_SHIP_INTERVAL",
+		"WORKER_TRAIN_SCRIPT", "WORKER_PYTHON", "WORKER_TRAIN_TIMEOUT", "WORKER_SANDBOX_CGROUP",
+		"WORKER_SIM_SEED", "WORKER_SIM_MIN_DURATION", "WORKER_SIM_MAX_DURATION", "WORKER_SIM_FAILURE_RATE",
+		"WORKER_SIM_CURVE", "WORKER_SIM_CONVERGENCE_RATE", "WORKER_SIM_NOISE",
+		"WORKER_DOCKER_BIN", "WORKER_DOCKER_DEFAULT_IMAGE", "WORKER_DOCKER_CPUS", "WORKER_DOCKER_MEMORY",
+		"WORKER_DOCKER_GPUS", "WORKER_DOCKER_NETWORK",
+		"WORKER_K8S_NAMESPACE", "WORKER_K8S_SERVICE_ACCOUNT", "WORKER_K8S_CPU", "WORKER_K8S_MEMORY", "WORKER_K8S_GPUS",
+	}
+	settings := make(map[string]string, len(vars))
+	for _, v := range vars {
+		settings[strings.ToLower(strings.TrimPrefix(v, "WORKER_"))] = v
+	}
+	return settings
+}()
+
+// reloadableSettings take effect when the configuration file is reloaded;
+// changes to the others wait for the worker to restart.
+var reloadableSettings = map[string]bool{
+	"WORKER_MAX_CONCURRENT_TASKS":    true,
+	"WORKER_PREFETCH_TASKS":          true,
+	"WORKER_DATASET_CACHE_SIZE_MB":   true,
+	"WORKER_RECONNECT_MAX_BACKOFF":   true,
+	"WORKER_THROTTLE_CPU_PERCENT":    true,
+	"WORKER_THROTTLE_MEMORY_PERCENT": true,
+	"WORKER_THROTTLE_GPU_PERCENT":    true,
+}
+
+// workerConfigPathFromEnv reads WORKER_CONFIG, the YAML file the worker
+// reads its settings from. Without one the environment alone configures it.
+func workerConfigPathFromEnv() string {
+	return os.Getenv("WORKER_CONFIG")
+}
+
+// configPollIntervalFromEnv reads WORKER_CONFIG_POLL_INTERVAL, how often the
+// configuration file is checked for changes; zero reloads it on SIGHUP only.
+func configPollIntervalFromEnv() time.Duration {
+	d, err := time.ParseDuration(os.Getenv("WORKER_CONFIG_POLL_INTERVAL"))
+	if err != nil || d < 0 {
+		return 30 * time.Second
+	}
+	return d
+}
+
+// workerConfig is a YAML file of worker settings, e.g.
+//
+//	executor: docker
+//	max_concurrent_tasks: 4
+//	dataset_cache_size_mb: 20480
+//	labels:
+//	  zone: us-east
+//
+// Each setting stands in for its environment variable, which the worker's
+// components read as before; a variable set in the environment overrides
+// the file. Reloading the file updates the variables and applies the
+// settings that can change while tasks run.
+type workerConfig struct {
+	path string
+
+	mu      sync.Mutex
+	data    []byte            // File content last applied
+	applied map[string]string // Environment variables set from the file
+	fromEnv map[string]bool   // Set in the environment; the file does not override them
+}
+
+// loadWorkerConfig reads the file at path and applies its settings. With
+// no path it returns nil.
+func loadWorkerConfig(path string) (*workerConfig, error) {
+	if path == "" {
+		return nil, nil
+	}
+	c := &workerConfig{path: path, applied: make(map[string]string), fromEnv: make(map[string]bool)}
+	for _, env := range configSettings {
+		if _, ok := os.LookupEnv(env); ok {
+			c.fromEnv[env] = true
+		}
+	}
+	if _, err := c.reload(); err != nil {
+		return nil, err
+	}
+	log.Printf("Loaded worker configuration from %s", path)
+	return c, nil
+}
+
+// parseWorkerConfig turns a configuration file into the environment
+// variables it sets.
+func parseWorkerConfig(data []byte) (map[string]string, error) {
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	values := make(map[string]string, len(raw))
+	for key, value := range raw {
+		env, ok := configSettings[key]
+		if !ok {
+			return nil, fmt.Errorf("unknown setting %q", key)
+		}
+		switch v := value.(type) {
+		case nil:
+			continue
+		case map[string]interface{}:
+			if key != "labels" {
+				return nil, fmt.Errorf("setting %q must be a single value", key)
+			}
+			// Labels may be written as a map for WORKER_LABELS' key=value list
+			pairs := make([]string, 0, len(v))
+			for k, lv := range v {
+				pairs = append(pairs, fmt.Sprintf("%s=%v", k, lv))
+			}
+			sort.Strings(pairs)
+			values[env] = strings.Join(pairs, ",")
+		case []interface{}:
+			return nil, fmt.Errorf("setting %q must be a single value", key)
+		default:
+			values[env] = fmt.Sprint(v)
+		}
+	}
+	return values, nil
+}
+
+// reload reads the file again and updates the environment from it. It
+// returns the variables that changed, none if the file did not. A file that
+// cannot be read or parsed changes nothing.
+func (c *workerConfig) reload() ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read worker configuration: %v", err)
+	}
+	if c.data != nil && bytes.Equal(data, c.data) {
+		return nil, nil
+	}
+	values, err := parseWorkerConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid worker configuration %s: %v", c.path, err)
+	}
+	c.data = data
+
+	var changed []string
+	for env, value := range values {
+		if c.fromEnv[env] {
+			continue
+		}
+		if old, ok := c.applied[env]; !ok || old != value {
+			os.Setenv(env, value)
+			c.applied[env] = value
+			changed = append(changed, env)
+		}
+	}
+	for env := range c.applied {
+		if _, ok := values[env]; !ok {
+			// Removed from the file: back to the default
+			os.Unsetenv(env)
+			delete(c.applied, env)
+			changed = append(changed, env)
+		}
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// watchConfig reloads the configuration file on SIGHUP, and whenever it
+// changes, until ctx is done.
+func (ws *WorkerServer) watchConfig(ctx context.Context, c *workerConfig) {
+	if c == nil {
+		return
+	}
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	var poll <-chan time.Time
+	if interval := configPollIntervalFromEnv(); interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		poll = ticker.C
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			log.Printf("Reloading worker configuration from %s", c.path)
+		case <-poll:
+		}
+		changed, err := c.reload()
+		if err != nil {
+			log.Printf("Keeping the current configuration: %v", err)
+			configReloads.WithLabelValues("failure").Inc()
+			continue
+		}
+		if len(changed) > 0 {
+			configReloads.WithLabelValues("success").Inc()
+			ws.applySettings(changed)
+		}
+	}
+}
+
+// applySettings puts changed settings into effect where they can change
+// while tasks run. Running tasks keep going: a smaller pool only holds back
+// new tasks until enough have finished.
+func (ws *WorkerServer) applySettings(changed []string) {
+	var restart []string
+	for _, env := range changed {
+		if !reloadableSettings[env] {
+			restart = append(restart, env)
+		}
+	}
+	for _, env := range changed {
+		switch env {
+		case "WORKER_MAX_CONCURRENT_TASKS":
+			size := maxConcurrentTasksFromEnv()
+			if size == ws.pool.size() {
+				continue
+			}
+			ws.pool.resize(size)
+			log.Printf("Now running up to %d tasks at once", size)
+			// The orchestrator learns of the new size at registration
+			go ws.reregister("pool size")
+		case "WORKER_PREFETCH_TASKS":
+			go ws.reregister("prefetch allowance")
+		case "WORKER_DATASET_CACHE_SIZE_MB":
+			ws.datasets.resize(datasetCacheMaxBytesFromEnv())
+		case "WORKER_RECONNECT_MAX_BACKOFF":
+			d := reconnectMaxBackoffFromEnv()
+			ws.maxReconnectDelay.Store(int64(d))
+			log.Printf("Task stream reconnects now back off up to %s", d)
+		case "WORKER_THROTTLE_CPU_PERCENT", "WORKER_THROTTLE_MEMORY_PERCENT", "WORKER_THROTTLE_GPU_PERCENT":
+			ws.throttle.setThresholds(loadThresholdsFromEnv())
+		}
+	}
+	if len(restart) > 0 {
+		log.Printf("Changed settings %s take effect when the worker restarts", strings.Join(restart, ", "))
+	}
+}
+
+// reregister registers again so the orchestrator learns of a changed
+// setting; what names it in the log.
+func (ws *WorkerServer) reregister(what string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	if err := ws.register(ctx); err != nil {
+		log.Printf("Failed to register the new %s: %v", what, err)
+	}
+}
@@ -0,0 +1,443 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	datasetCacheHits = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "worker_dataset_cache_hits_total",
+		Help: "Tasks whose dataset was already in the worker's cache",
+	}, []string{"job_id"})
+	datasetCacheMisses = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "worker_dataset_cache_misses_total",
+		Help: "Tasks whose dataset had to be downloaded",
+	}, []string{"job_id"})
+	datasetCacheHitRatio = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "worker_dataset_cache_hit_ratio",
+		Help: "Share of tasks since the worker started whose dataset was already cached",
+	})
+	datasetCacheBytes = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "worker_dataset_cache_bytes",
+		Help: "Size of the datasets in the worker's cache",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(datasetCacheHits, datasetCacheMisses, datasetCacheHitRatio, datasetCacheBytes)
+}
+
+// Dataset lookups since the worker started, for the hit ratio.
+var (
+	datasetLookupsMu   sync.Mutex
+	datasetLookups     int
+	datasetLookupsHits int
+)
+
+// recordDatasetLookup counts whether a task of the job found its dataset
+// in the cache.
+func recordDatasetLookup(jobID string, hit bool) {
+	datasetLookupsMu.Lock()
+	defer datasetLookupsMu.Unlock()
+	datasetLookups++
+	if hit {
+		datasetLookupsHits++
+		datasetCacheHits.WithLabelValues(jobLabel(jobID)).Inc()
+	} else {
+		datasetCacheMisses.WithLabelValues(jobLabel(jobID)).Inc()
+	}
+	datasetCacheHitRatio.Set(float64(datasetLookupsHits) / float64(datasetLookups))
+}
+
+// datasetCacheDirFromEnv reads WORKER_DATASET_CACHE_DIR, where datasets are
+// cached on the worker's disk.
+func datasetCacheDirFromEnv() string {
+	if dir := os.Getenv("WORKER_DATASET_CACHE_DIR"); dir != "" {
+		return dir
+	}
+	return "/var/cache/tensorfleet/datasets"
+}
+
+// datasetCacheMaxBytesFromEnv reads WORKER_DATASET_CACHE_SIZE_MB, the size
+// the cache is trimmed back to once tasks stop using its datasets.
+func datasetCacheMaxBytesFromEnv() int64 {
+	mb, err := strconv.ParseInt(os.Getenv("WORKER_DATASET_CACHE_SIZE_MB"), 10, 64)
+	if err != nil || mb <= 0 {
+		mb = 10 << 10
+	}
+	return mb << 20
+}
+
+// storageServiceURL is the base URL of the storage service datasets are
+// downloaded from.
+func storageServiceURL() string {
+	if u := os.Getenv("STORAGE_SERVICE_URL"); u != "" {
+		return strings.TrimRight(u, "/")
+	}
+	return "http://storage:8081"
+}
+
+// datasetCacheMetaFile describes a cached dataset, next to its data.
+const datasetCacheMetaFile = "dataset.json"
+
+// datasetCache keeps the datasets tasks train on on the worker's disk. A
+// dataset is downloaded from the storage service the first time a task
+// needs it; once the cache grows past its size cap, the least recently used
+// datasets no running task holds are evicted. The cache survives restarts,
+// and the orchestrator is told what it holds so that tasks are scheduled
+// onto workers that already have their data.
+type datasetCache struct {
+	dir        string
+	maxBytes   int64
+	storageURL string
+	client     *http.Client
+
+	mu      sync.Mutex
+	entries map[string]*cachedDataset // By dataset path, as jobs name it
+	size    int64                     // Of the entries fetched so far
+}
+
+// cachedDataset is one dataset in the cache, or being fetched into it.
+type cachedDataset struct {
+	DatasetPath string `json:"dataset_path"`
+	File        string `json:"file"` // Relative to the cache directory
+	SizeBytes   int64  `json:"size_bytes"`
+
+	lastUsed time.Time
+	users    int           // Tasks holding the dataset
+	ready    chan struct{} // Closed once fetched, or once the fetch failed
+	err      error         // Why the fetch failed
+}
+
+// newDatasetCache opens the cache in dir, picking up datasets cached by
+// earlier runs of the worker.
+func newDatasetCache(dir string, maxBytes int64) (*datasetCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create dataset cache: %v", err)
+	}
+	c := &datasetCache{
+		dir:        dir,
+		maxBytes:   maxBytes,
+		storageURL: storageServiceURL(),
+		client:     &http.Client{},
+		entries:    make(map[string]*cachedDataset),
+	}
+	c.load()
+	c.mu.Lock()
+	c.evict()
+	c.mu.Unlock()
+	log.Printf("Dataset cache in %s holds %d datasets (%d of %d MB)",
+		dir, len(c.entries), c.size>>20, maxBytes>>20)
+	return c, nil
+}
+
+// resize changes the size the cache is trimmed back to, evicting datasets
+// at once if it shrank.
+func (c *datasetCache) resize(maxBytes int64) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.maxBytes = maxBytes
+	c.evict()
+	log.Printf("Dataset cache now holds up to %d MB (%d MB used)", maxBytes>>20, c.size>>20)
+}
+
+// load indexes the datasets already in the cache directory. Directories
+// without a description are left over from interrupted downloads and are
+// removed.
+func (c *datasetCache) load() {
+	dirs, err := os.ReadDir(c.dir)
+	if err != nil {
+		log.Printf("Failed to read dataset cache: %v", err)
+		return
+	}
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		entryDir := filepath.Join(c.dir, d.Name())
+		metaPath := filepath.Join(entryDir, datasetCacheMetaFile)
+		data, err := os.ReadFile(metaPath)
+		var e cachedDataset
+		if err == nil {
+			err = json.Unmarshal(data, &e)
+		}
+		info, statErr := os.Stat(metaPath)
+		if err != nil || statErr != nil || e.DatasetPath == "" || filepath.Dir(e.File) != d.Name() {
+			os.RemoveAll(entryDir)
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(c.dir, e.File)); err != nil {
+			os.RemoveAll(entryDir)
+			continue
+		}
+		e.lastUsed = info.ModTime()
+		e.ready = make(chan struct{})
+		close(e.ready)
+		c.entries[e.DatasetPath] = &e
+		c.size += e.SizeBytes
+	}
+	datasetCacheBytes.Set(float64(c.size))
+}
+
+// acquire returns the local path of a dataset, downloading it first unless
+// it is cached, and whether it was. The dataset stays cached until the
+// returned release is called.
+func (c *datasetCache) acquire(ctx context.Context, datasetPath string) (string, bool, func(), error) {
+	for {
+		c.mu.Lock()
+		e, ok := c.entries[datasetPath]
+		if !ok {
+			e = &cachedDataset{DatasetPath: datasetPath, users: 1, ready: make(chan struct{})}
+			c.entries[datasetPath] = e
+			c.mu.Unlock()
+			return c.fetch(ctx, e)
+		}
+		e.users++
+		e.lastUsed = time.Now()
+		c.mu.Unlock()
+
+		// Another task may be fetching it
+		select {
+		case <-e.ready:
+		case <-ctx.Done():
+			c.release(e)
+			return "", false, nil, ctx.Err()
+		}
+		if e.err != nil {
+			c.release(e)
+			// A fetch given up by the task that started it is tried again
+			if errors.Is(e.err, context.Canceled) && ctx.Err() == nil {
+				continue
+			}
+			return "", false, nil, e.err
+		}
+		c.touch(e)
+		return filepath.Join(c.dir, e.File), true, func() { c.release(e) }, nil
+	}
+}
+
+// fetch downloads a dataset into the cache on behalf of acquire.
+func (c *datasetCache) fetch(ctx context.Context, e *cachedDataset) (string, bool, func(), error) {
+	start := time.Now()
+	err := c.download(ctx, e)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err != nil {
+		e.err = fmt.Errorf("failed to fetch dataset %s: %w", e.DatasetPath, err)
+		e.users--
+		delete(c.entries, e.DatasetPath)
+		if e.File != "" {
+			os.RemoveAll(filepath.Join(c.dir, filepath.Dir(e.File)))
+		}
+		close(e.ready)
+		return "", false, nil, e.err
+	}
+	e.lastUsed = time.Now()
+	c.size += e.SizeBytes
+	close(e.ready)
+	c.evict()
+	log.Printf("Cached dataset %s (%d MB) in %s", e.DatasetPath, e.SizeBytes>>20, time.Since(start).Round(time.Millisecond))
+	return filepath.Join(c.dir, e.File), false, func() { c.release(e) }, nil
+}
+
+// download resolves a dataset through the storage service and saves it to
+// a directory of its own in the cache.
+func (c *datasetCache) download(ctx context.Context, e *cachedDataset) error {
+	bucket, object, err := c.locate(ctx, e.DatasetPath)
+	if err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256([]byte(e.DatasetPath))
+	key := hex.EncodeToString(sum[:8])
+	entryDir := filepath.Join(c.dir, key)
+	if err := os.MkdirAll(entryDir, 0o755); err != nil {
+		return err
+	}
+	e.File = filepath.Join(key, path.Base(object))
+
+	endpoint, err := url.JoinPath(c.storageURL, "api/v1/download", bucket, object)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("storage service returned status %d", resp.StatusCode)
+	}
+
+	// Written under a temporary name, so that a crash mid-download leaves
+	// no description and the directory is cleared at the next start
+	dataPath := filepath.Join(c.dir, e.File)
+	f, err := os.Create(dataPath + ".part")
+	if err != nil {
+		return err
+	}
+	n, err := io.Copy(f, resp.Body)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(dataPath+".part", dataPath); err != nil {
+		return err
+	}
+	e.SizeBytes = n
+
+	meta, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(entryDir, datasetCacheMetaFile), meta, 0o644)
+}
+
+// locate asks the storage service which object holds a dataset.
+func (c *datasetCache) locate(ctx context.Context, datasetPath string) (bucket, object string, err error) {
+	endpoint := c.storageURL + "/api/v1/datasets/info?path=" + url.QueryEscape(datasetPath)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", "", err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return "", "", errors.New("no such dataset in the storage service")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("storage service returned status %d", resp.StatusCode)
+	}
+
+	var info struct {
+		MinioPath string `json:"minio_path"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return "", "", fmt.Errorf("failed to decode dataset info: %v", err)
+	}
+	bucket, object, ok := strings.Cut(strings.TrimPrefix(info.MinioPath, "s3://"), "/")
+	if !ok || object == "" {
+		return "", "", fmt.Errorf("unexpected dataset location %q", info.MinioPath)
+	}
+	return bucket, object, nil
+}
+
+// touch records a use of the dataset on disk, so that its place in the
+// eviction order survives a restart.
+func (c *datasetCache) touch(e *cachedDataset) {
+	now := time.Now()
+	metaPath := filepath.Join(c.dir, filepath.Dir(e.File), datasetCacheMetaFile)
+	if err := os.Chtimes(metaPath, now, now); err != nil {
+		log.Printf("Failed to touch cached dataset %s: %v", e.DatasetPath, err)
+	}
+}
+
+// release lets go of a dataset taken with acquire.
+func (c *datasetCache) release(e *cachedDataset) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e.users--
+	e.lastUsed = time.Now()
+	c.evict()
+}
+
+// evict removes the least recently used datasets no task holds until the
+// cache fits its size cap. Caller must hold c.mu.
+func (c *datasetCache) evict() {
+	defer func() { datasetCacheBytes.Set(float64(c.size)) }()
+	for c.size > c.maxBytes {
+		var oldest *cachedDataset
+		for _, e := range c.entries {
+			if e.users > 0 || e.File == "" || e.err != nil {
+				continue
+			}
+			select {
+			case <-e.ready:
+			default:
+				continue
+			}
+			if oldest == nil || e.lastUsed.Before(oldest.lastUsed) {
+				oldest = e
+			}
+		}
+		if oldest == nil {
+			return
+		}
+		if err := os.RemoveAll(filepath.Join(c.dir, filepath.Dir(oldest.File))); err != nil {
+			log.Printf("Failed to evict dataset %s: %v", oldest.DatasetPath, err)
+			return
+		}
+		delete(c.entries, oldest.DatasetPath)
+		c.size -= oldest.SizeBytes
+		log.Printf("Evicted dataset %s (%d MB) from the cache", oldest.DatasetPath, oldest.SizeBytes>>20)
+	}
+}
+
+// list is the paths of the datasets in the cache, as reported to the
+// orchestrator. The result is never nil, so that an empty cache replaces
+// the orchestrator's view of it.
+func (c *datasetCache) list() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	datasets := make([]string, 0, len(c.entries))
+	for p, e := range c.entries {
+		select {
+		case <-e.ready:
+			if e.err == nil {
+				datasets = append(datasets, p)
+			}
+		default:
+		}
+	}
+	sort.Strings(datasets)
+	return datasets
+}
+
+// cachedDatasets is what the worker tells the orchestrator it has cached,
+// nil when it keeps no cache.
+func (ws *WorkerServer) cachedDatasets() []string {
+	if ws.datasets == nil {
+		return nil
+	}
+	return ws.datasets.list()
+}
+
+// readsLocalDatasets reports whether the executor trains on the worker's
+// own copy of a task's dataset, so that the dataset is fetched into the
+// cache before the task runs. Executors that do implement localDatasets.
+func readsLocalDatasets(e Executor) bool {
+	_, local := e.(interface{ localDatasets() })
+	return local
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"crypto/subtle"
+	"log"
+	"net/http"
+	"net/http/pprof"
+	"os"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+// The default Go collector only exports the classic memstats. Long-running
+// workers also export the runtime's GC, heap and scheduler metrics, such as
+// go_gc_heap_goal_bytes and go_sched_goroutines_goroutines, to tell memory
+// growth from goroutine leaks before profiling.
+func init() {
+	prometheus.Unregister(collectors.NewGoCollector())
+	prometheus.MustRegister(collectors.NewGoCollector(
+		collectors.WithGoCollectorRuntimeMetrics(collectors.MetricsGC, collectors.MetricsMemory, collectors.MetricsScheduler),
+	))
+}
+
+// pprofFromEnv reads WORKER_PPROF; set to false to not serve /debug/pprof.
+func pprofFromEnv() bool {
+	return os.Getenv("WORKER_PPROF") != "false"
+}
+
+// registerPprofHandlers serves the runtime profiles under /debug/pprof on
+// mux. With WORKER_AUTH_TOKEN set they need it as a bearer token, as the
+// worker's gRPC calls do, since profiles expose the worker's memory.
+func registerPprofHandlers(mux *http.ServeMux) {
+	if !pprofFromEnv() {
+		return
+	}
+	mux.Handle("/debug/pprof/", requireWorkerToken(http.HandlerFunc(pprof.Index)))
+	mux.Handle("/debug/pprof/cmdline", requireWorkerToken(http.HandlerFunc(pprof.Cmdline)))
+	mux.Handle("/debug/pprof/profile", requireWorkerToken(http.HandlerFunc(pprof.Profile)))
+	mux.Handle("/debug/pprof/symbol", requireWorkerToken(http.HandlerFunc(pprof.Symbol)))
+	mux.Handle("/debug/pprof/trace", requireWorkerToken(http.HandlerFunc(pprof.Trace)))
+	log.Println("Serving profiles at /debug/pprof")
+}
+
+func requireWorkerToken(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if workerAuthToken != "" {
+			token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(token), []byte(workerAuthToken)) != 1 {
+				http.Error(w, "missing or invalid worker token", http.StatusUnauthorized)
+				return
+			}
+		}
+		h.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// dockerExecutor runs each task in a container of its job's image, speaking
+// the same protocol as the subprocess executor: the task directory is
+// mounted at /task and the TENSORFLEET_* variables point into it. The
+// image's own entrypoint does the training. The worker's dataset cache is
+// mounted read-only at /data. A job's CPU and memory requirements limit its
+// containers in place of WORKER_DOCKER_CPUS and WORKER_DOCKER_MEMORY.
+type dockerExecutor struct {
+	docker       string
+	defaultImage string
+	datasetDir   string
+	timeout      time.Duration
+	limits       []string // docker run flags capping the container's resources
+	gpus         string   // --gpus for tasks not pinned to devices of their own
+
+	imagesMu sync.Mutex
+	images   map[string]*imagePull
+}
+
+// imagePull is an image tasks have asked for. Pulls of different images
+// proceed at once; tasks needing the same image wait for one pull.
+type imagePull struct {
+	mu     sync.Mutex
+	pulled bool
+}
+
+// Where a task container sees the task directory, the dataset cache and
+// the task's checkpoint directory.
+const (
+	containerTaskDir       = "/task"
+	containerDatasetDir    = "/data"
+	containerCheckpointDir = "/checkpoint"
+)
+
+// Time a container gets to exit after being stopped before it is killed.
+const dockerStopTimeout = 10 * time.Second
+
+func newDockerExecutor() (Executor, error) {
+	docker := os.Getenv("WORKER_DOCKER_BIN")
+	if docker == "" {
+		docker = "docker"
+	}
+	if _, err := exec.LookPath(docker); err != nil {
+		return nil, fmt.Errorf("the docker executor needs the docker CLI: %v", err)
+	}
+	timeout, err := trainTimeoutFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	datasetDir := datasetCacheDirFromEnv()
+
+	var limits []string
+	for _, limit := range []struct{ flag, env string }{
+		{"--cpus", "WORKER_DOCKER_CPUS"},
+		{"--memory", "WORKER_DOCKER_MEMORY"},
+		{"--network", "WORKER_DOCKER_NETWORK"},
+	} {
+		if v := os.Getenv(limit.env); v != "" {
+			limits = append(limits, limit.flag, v)
+		}
+	}
+
+	e := &dockerExecutor{
+		docker:       docker,
+		defaultImage: os.Getenv("WORKER_DOCKER_DEFAULT_IMAGE"),
+		datasetDir:   datasetDir,
+		timeout:      timeout,
+		limits:       limits,
+		gpus:         os.Getenv("WORKER_DOCKER_GPUS"),
+		images:       make(map[string]*imagePull),
+	}
+	log.Printf("Running tasks in containers (default image %q, limits %v, gpus %q, timeout %s)", e.defaultImage, limits, e.gpus, timeout)
+	return e, nil
+}
+
+// Tasks read their datasets from the worker's cache.
+func (e *dockerExecutor) localDatasets() {}
+
+// checkHealth reports whether the Docker daemon answers.
+func (e *dockerExecutor) checkHealth(ctx context.Context) error {
+	out, err := exec.CommandContext(ctx, e.docker, "version", "--format", "{{.Server.Version}}").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("docker daemon unavailable: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// ensureImage pulls an image the first time a task needs it, unless it is
+// already present locally.
+func (e *dockerExecutor) ensureImage(ctx context.Context, image string) error {
+	e.imagesMu.Lock()
+	pull, ok := e.images[image]
+	if !ok {
+		pull = &imagePull{}
+		e.images[image] = pull
+	}
+	e.imagesMu.Unlock()
+
+	pull.mu.Lock()
+	defer pull.mu.Unlock()
+	if pull.pulled {
+		return nil
+	}
+	if exec.CommandContext(ctx, e.docker, "image", "inspect", image).Run() != nil {
+		log.Printf("Pulling image %s", image)
+		out, err := exec.CommandContext(ctx, e.docker, "pull", image).CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("failed to pull image %s: %v: %s", image, err, out)
+		}
+	}
+	pull.pulled = true
+	return nil
+}
+
+func containerName() string {
+	b := make([]byte, 4)
+	rand.Read(b)
+	return "tensorfleet-task-" + hex.EncodeToString(b)
+}
+
+func (e *dockerExecutor) Run(ctx context.Context, spec TaskSpec) (Result, error) {
+	image := spec.Image
+	if image == "" {
+		image = e.defaultImage
+	}
+	if image == "" {
+		return Result{}, errors.New("job names no image and WORKER_DOCKER_DEFAULT_IMAGE is unset")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+	if err := e.ensureImage(ctx, image); err != nil {
+		return Result{}, err
+	}
+
+	dir := spec.Workspace
+	// The image may train as a non-root user
+	if err := os.Chmod(dir, 0o777); err != nil {
+		return Result{}, err
+	}
+
+	name := containerName()
+	args := []string{"run", "--rm", "--name", name,
+		"--label", "tensorfleet.task-id=" + spec.TaskID,
+		"--label", "tensorfleet.job-id=" + spec.JobID,
+		"-v", dir + ":" + containerTaskDir,
+		"-v", e.datasetDir + ":" + containerDatasetDir + ":ro",
+		"-w", containerTaskDir,
+		"-e", "TENSORFLEET_DATASET_DIR=" + containerDatasetDir,
+		"-e", "TENSORFLEET_PROGRESS_FILE=" + filepath.Join(containerTaskDir, taskProgressFile),
+	}
+	if spec.CheckpointDir != "" {
+		// The image may train as a non-root user
+		if err := os.Chmod(spec.CheckpointDir, 0o777); err != nil {
+			return Result{}, err
+		}
+		args = append(args,
+			"-v", spec.CheckpointDir+":"+containerCheckpointDir,
+			"-e", "TENSORFLEET_CHECKPOINT_DIR="+containerCheckpointDir,
+			"-e", "TENSORFLEET_RESUME="+strconv.FormatBool(spec.Resumed),
+		)
+		if spec.CheckpointInterval > 0 {
+			args = append(args, "-e", "TENSORFLEET_CHECKPOINT_INTERVAL="+strconv.Itoa(int(spec.CheckpointInterval.Seconds())))
+		}
+	}
+	if rel, err := filepath.Rel(e.datasetDir, spec.DatasetFile); spec.DatasetFile != "" && err == nil {
+		args = append(args, "-e", "TENSORFLEET_DATASET_FILE="+filepath.Join(containerDatasetDir, rel))
+	}
+	weightsIn, resultFile := taskDirPaths(spec, containerTaskDir)
+	for _, kv := range taskEnv(spec, weightsIn, resultFile) {
+		args = append(args, "-e", kv)
+	}
+	args = append(args, e.limitsFor(spec)...)
+	switch {
+	case len(spec.GPUs) > 0:
+		args = append(args, "--gpus", `"device=`+strings.Join(spec.GPUs, ",")+`"`)
+	case spec.GPUs == nil && e.gpus != "":
+		args = append(args, "--gpus", e.gpus)
+	}
+	args = append(args, image)
+
+	cmd := exec.CommandContext(ctx, e.docker, args...)
+	// Stopping the client would leave the container running
+	cmd.Cancel = func() error {
+		stop := exec.Command(e.docker, "stop", "-t", fmt.Sprint(int(dockerStopTimeout/time.Second)), name)
+		return stop.Run()
+	}
+	cmd.WaitDelay = dockerStopTimeout + 5*time.Second
+
+	stderr := &tailBuffer{max: subprocessStderrTail}
+	stdoutLog, stderrLog := newLogWriter(spec, "INFO", "stdout"), newLogWriter(spec, "WARN", "stderr")
+	defer stdoutLog.flush()
+	defer stderrLog.flush()
+	cmd.Stdout = io.MultiWriter(os.Stdout, stdoutLog)
+	cmd.Stderr = io.MultiWriter(os.Stderr, stderr, stderrLog)
+
+	watchCtx, stopWatching := context.WithCancel(ctx)
+	defer stopWatching()
+	go watchProgressFile(watchCtx, filepath.Join(dir, taskProgressFile), spec)
+
+	started := time.Now()
+	err := cmd.Run()
+	usage := taskLimits(spec)
+	usage.OOMKilled = err != nil && e.oomKilled(name, started)
+	spec.reportUsage(usage)
+	if err != nil {
+		switch {
+		case errors.Is(ctx.Err(), context.DeadlineExceeded):
+			return Result{}, fmt.Errorf("training container timed out after %s", e.timeout)
+		case ctx.Err() != nil:
+			return Result{}, ctx.Err()
+		case usage.OOMKilled:
+			return Result{}, usage.oomError("training container")
+		}
+		return Result{}, fmt.Errorf("training container failed: %v: %s", err, stderr.String())
+	}
+
+	return readTaskResult(spec, dir, containerTaskDir)
+}
+
+// limitsFor is the docker run flags limiting the task's container: the
+// task's own CPU and memory, where it asks for them, and the worker's
+// defaults otherwise.
+func (e *dockerExecutor) limitsFor(spec TaskSpec) []string {
+	var limits []string
+	for i := 0; i+1 < len(e.limits); i += 2 {
+		flag := e.limits[i]
+		if (flag == "--cpus" && spec.CPUCores > 0) || (flag == "--memory" && spec.MemoryMB > 0) {
+			continue
+		}
+		limits = append(limits, flag, e.limits[i+1])
+	}
+	if spec.CPUCores > 0 {
+		limits = append(limits, "--cpus", strconv.Itoa(int(spec.CPUCores)))
+	}
+	if spec.MemoryMB > 0 {
+		// Equal to --memory, so the container cannot swap
+		memory := strconv.FormatInt(spec.MemoryMB, 10) + "m"
+		limits = append(limits, "--memory", memory, "--memory-swap", memory)
+	}
+	return limits
+}
+
+// oomKilled reports whether the container, started at started, was killed
+// for exceeding its memory limit. It is gone by then, so the daemon's
+// events tell.
+func (e *dockerExecutor) oomKilled(name string, started time.Time) bool {
+	out, err := exec.Command(e.docker, "events",
+		"--since", strconv.FormatInt(started.Add(-time.Second).Unix(), 10),
+		"--until", strconv.FormatInt(time.Now().Add(time.Second).Unix(), 10),
+		"--filter", "container="+name, "--filter", "event=oom",
+		"--format", "{{.Status}}").Output()
+	return err == nil && strings.TrimSpace(string(out)) != ""
+}
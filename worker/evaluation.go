@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// isEvaluation reports whether tasks of the type score the weights they are
+// given rather than train them: the job's final evaluation and the
+// validation of each epoch on a held-out dataset.
+func isEvaluation(taskType string) bool {
+	return taskType == taskTypeEvaluate || taskType == taskTypeValidate
+}
+
+// evaluatorNameFromEnv reads WORKER_EVAL_EXECUTOR, the backend evaluation
+// and validation tasks run on; the training executor's by default.
+func evaluatorNameFromEnv(executorName string) string {
+	if name := os.Getenv("WORKER_EVAL_EXECUTOR"); name != "" {
+		return name
+	}
+	return executorName
+}
+
+// evaluationExecutor runs evaluation and validation tasks on a backend of
+// its own. The weights such a task scores go back to the orchestrator
+// unchanged, so any the backend returns are dropped.
+type evaluationExecutor struct {
+	backend Executor
+}
+
+// newEvaluationExecutor builds the evaluation executor on the named
+// backend, sharing the training executor when they are the same.
+func newEvaluationExecutor(name, executorName string, executor Executor) (*evaluationExecutor, error) {
+	if name == executorName {
+		return &evaluationExecutor{backend: executor}, nil
+	}
+	backend, err := newExecutor(name)
+	if err != nil {
+		return nil, fmt.Errorf("WORKER_EVAL_EXECUTOR: %v", err)
+	}
+	return &evaluationExecutor{backend: backend}, nil
+}
+
+func (e *evaluationExecutor) Run(ctx context.Context, spec TaskSpec) (Result, error) {
+	result, err := e.backend.Run(ctx, spec)
+	if err != nil {
+		return Result{}, err
+	}
+	result.ModelWeights = nil
+	return result, nil
+}
+
+// executorFor is the executor tasks of the type run on.
+func (ws *WorkerServer) executorFor(taskType string) Executor {
+	if isEvaluation(taskType) {
+		return ws.evaluator
+	}
+	return ws.executor
+}
+
+// backendFor is the backend tasks of the type run on, which tells where
+// they run and what they read.
+func (ws *WorkerServer) backendFor(taskType string) Executor {
+	if isEvaluation(taskType) {
+		return ws.evaluator.backend
+	}
+	return ws.executor
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	workerpb "github.com/tensorfleet/worker/proto/worker"
+)
+
+// Task types. Preprocessing tasks prepare the job's dataset before training
+// and need not report metrics; evaluation tasks score the final weights and
+// validation tasks an epoch's weights on a held-out dataset, and return
+// none. See evaluation.go.
+const (
+	taskTypeTrain      = "train"
+	taskTypePreprocess = "preprocess"
+	taskTypeEvaluate   = "evaluate"
+	taskTypeValidate   = "validate"
+)
+
+// taskTypesFromEnv reads WORKER_TASK_TYPES, a comma-separated list of the
+// task types the worker runs, e.g. "preprocess,evaluate" for a worker kept
+// off training. Empty runs all of them.
+func taskTypesFromEnv() ([]string, error) {
+	var types []string
+	for _, t := range strings.Split(os.Getenv("WORKER_TASK_TYPES"), ",") {
+		t = strings.TrimSpace(t)
+		switch t {
+		case "":
+			continue
+		case taskTypeTrain, taskTypePreprocess, taskTypeEvaluate, taskTypeValidate:
+			types = append(types, t)
+		default:
+			return nil, fmt.Errorf("invalid WORKER_TASK_TYPES entry %q", t)
+		}
+	}
+	return types, nil
+}
+
+// runsTaskType reports whether the worker runs tasks of the type.
+func (ws *WorkerServer) runsTaskType(taskType string) bool {
+	return len(ws.taskTypes) == 0 || slices.Contains(ws.taskTypes, taskType)
+}
+
+// TaskSpec is everything an executor needs to run one task.
+type TaskSpec struct {
+	TaskID          string
+	TaskType        string // taskTypeTrain, taskTypePreprocess, taskTypeEvaluate or taskTypeValidate
+	JobID           string
+	ModelType       string
+	DatasetPath     string
+	Hyperparameters map[string]string
+	Epoch           int32
+	BatchStart      int32
+	BatchEnd        int32
+	ModelWeights    []byte // Starting weights, as little-endian float32 values; empty to initialise
+	Image           string // Container image for the docker executor; empty for its default
+	GPUCount        int32  // GPUs the job asks for
+	CPUCores        int32  // CPU cores to limit the task to; 0 for the executor's default
+	MemoryMB        int64  // Memory to limit the task to; 0 for the executor's default
+	DatasetFile     string // Local copy of the dataset in the worker's cache; empty if not fetched
+	Workspace       string // Working directory of this run, holding the starting weights; empty for remote executors
+	CheckpointDir   string // Kept across interrupted attempts of the task; empty without checkpoints
+	Resumed         bool   // CheckpointDir holds state of an interrupted attempt
+
+	// How often the task should checkpoint into CheckpointDir, e.g. on a
+	// preemptible worker; 0 leaves it to the task
+	CheckpointInterval time.Duration
+
+	// UUIDs of the worker's GPUs the task is pinned to; empty to hide them
+	// all, nil when the worker manages no GPUs
+	GPUs []string
+
+	// Progress is told how far the task has got, as often as the executor
+	// likes; it must not block. Executors call it through reportProgress.
+	Progress func(Progress)
+
+	// Log is given the task's training log a line at a time; it must not
+	// block. Executors call it through logLine.
+	Log func(LogLine)
+
+	// Usage is told what the task used of its resource limits once it has
+	// ended, by executors that enforce limits. Executors call it through
+	// reportUsage.
+	Usage func(ResourceUsage)
+}
+
+func taskSpecFromRequest(req *workerpb.TaskRequest) TaskSpec {
+	return TaskSpec{
+		TaskID:          req.TaskId,
+		TaskType:        taskTypeOf(req),
+		JobID:           req.JobId,
+		ModelType:       req.ModelType,
+		DatasetPath:     req.DatasetPath,
+		Hyperparameters: req.Hyperparameters,
+		Epoch:           req.Epoch,
+		BatchStart:      req.BatchStart,
+		BatchEnd:        req.BatchEnd,
+		ModelWeights:    req.ModelWeights,
+		Image:           req.Image,
+		GPUCount:        req.GpuCount,
+		CPUCores:        req.CpuCores,
+		MemoryMB:        req.MemoryMb,
+	}
+}
+
+// taskTypeOf is the request's task type; requests without one train.
+func taskTypeOf(req *workerpb.TaskRequest) string {
+	if req.TaskType == "" {
+		return taskTypeTrain
+	}
+	return req.TaskType
+}
+
+// Result is what a task produced.
+type Result struct {
+	Loss         float64
+	Accuracy     float64
+	ModelWeights []byte   // Updated weights, in the same encoding as TaskSpec.ModelWeights
+	Artifacts    []string // Files or glob patterns in the workspace to upload as the task's artifacts
+}
+
+// Executor trains one task. Run must return promptly once ctx is cancelled,
+// which happens when the orchestrator cancels the task or its job. Executors
+// that can tell how far training has got report it through the spec.
+type Executor interface {
+	Run(ctx context.Context, spec TaskSpec) (Result, error)
+}
+
+// executors are the training backends WORKER_EXECUTOR may name.
+var executors = map[string]func() (Executor, error){
+	"simulator":  newSimulatorExecutor,
+	"subprocess": newSubprocessExecutor,
+	"docker":     newDockerExecutor,
+	"kubernetes": newKubernetesExecutor,
+}
+
+// executorNameFromEnv reads WORKER_EXECUTOR, the simulator by default.
+func executorNameFromEnv() string {
+	if name := os.Getenv("WORKER_EXECUTOR"); name != "" {
+		return name
+	}
+	return "simulator"
+}
+
+// newExecutor builds the named executor.
+func newExecutor(name string) (Executor, error) {
+	build, ok := executors[name]
+	if !ok {
+		names := make([]string, 0, len(executors))
+		for n := range executors {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown WORKER_EXECUTOR %q (want one of %s)", name, strings.Join(names, ", "))
+	}
+	return build()
+}
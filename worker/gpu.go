@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+
+	orchestratorpb "github.com/tensorfleet/worker/proto/orchestrator"
+)
+
+// gpuDevice is a GPU found through NVML at startup.
+type gpuDevice struct {
+	Index    int
+	UUID     string
+	Name     string
+	MemoryMB int64
+
+	handle nvml.Device
+}
+
+// gpuManager hands the worker's GPUs out to tasks, each device to one task
+// at a time. A worker without GPUs, or without NVML, has an empty manager.
+type gpuManager struct {
+	devices []*gpuDevice
+
+	mu      sync.Mutex
+	holders map[int]string // Device index -> task holding it
+	freed   chan struct{}  // Closed and replaced whenever devices are freed
+}
+
+// gpuWaitInterval bounds how long a task waiting for GPUs sleeps between
+// checks, should a wake-up be missed.
+const gpuWaitInterval = 5 * time.Second
+
+// detectGPUs finds the host's GPUs through NVML. NVML stays initialised for
+// the life of the process so utilisation can be sampled.
+func detectGPUs() *gpuManager {
+	m := &gpuManager{holders: make(map[int]string), freed: make(chan struct{})}
+	if ret := nvml.Init(); ret != nvml.SUCCESS {
+		log.Printf("No GPUs: NVML unavailable: %s", nvml.ErrorString(ret))
+		return m
+	}
+	count, ret := nvml.DeviceGetCount()
+	if ret != nvml.SUCCESS {
+		log.Printf("No GPUs: failed to count devices: %s", nvml.ErrorString(ret))
+		return m
+	}
+	for i := 0; i < count; i++ {
+		handle, ret := nvml.DeviceGetHandleByIndex(i)
+		if ret != nvml.SUCCESS {
+			log.Printf("Skipping GPU %d: %s", i, nvml.ErrorString(ret))
+			continue
+		}
+		dev := &gpuDevice{Index: i, handle: handle}
+		dev.UUID, _ = handle.GetUUID()
+		dev.Name, _ = handle.GetName()
+		if mem, ret := handle.GetMemoryInfo(); ret == nvml.SUCCESS {
+			dev.MemoryMB = int64(mem.Total / (1 << 20))
+		}
+		m.devices = append(m.devices, dev)
+		log.Printf("Found GPU %d: %s (%s, %d MB)", i, dev.Name, dev.UUID, dev.MemoryMB)
+	}
+	return m
+}
+
+// count is how many GPUs the worker has.
+func (m *gpuManager) count() int {
+	return len(m.devices)
+}
+
+// gpuType names the worker's GPU model for registration, e.g. "a100", from
+// its first device.
+func (m *gpuManager) gpuType() string {
+	if len(m.devices) == 0 {
+		return ""
+	}
+	name := strings.ToLower(m.devices[0].Name)
+	for _, model := range []string{"h100", "a100", "a10g", "l40s", "l4", "v100", "t4"} {
+		if strings.Contains(name, model) {
+			return model
+		}
+	}
+	return name
+}
+
+// allocate reserves n free GPUs for a task, waiting for other tasks to free
+// them if need be. It fails at once if the worker has fewer than n GPUs.
+func (m *gpuManager) allocate(ctx context.Context, taskID string, n int) ([]*gpuDevice, error) {
+	if n > len(m.devices) {
+		return nil, fmt.Errorf("task needs %d GPUs but the worker has %d", n, len(m.devices))
+	}
+	for {
+		m.mu.Lock()
+		var free []*gpuDevice
+		for _, dev := range m.devices {
+			if _, held := m.holders[dev.Index]; !held {
+				free = append(free, dev)
+			}
+		}
+		if len(free) >= n {
+			for _, dev := range free[:n] {
+				m.holders[dev.Index] = taskID
+			}
+			m.mu.Unlock()
+			return free[:n], nil
+		}
+		freed := m.freed
+		m.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-freed:
+		case <-time.After(gpuWaitInterval):
+		}
+	}
+}
+
+// release frees the GPUs held by a task.
+func (m *gpuManager) release(devices []*gpuDevice) {
+	if len(devices) == 0 {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, dev := range devices {
+		delete(m.holders, dev.Index)
+	}
+	close(m.freed)
+	m.freed = make(chan struct{})
+}
+
+// stats samples each GPU's utilisation and memory for heartbeats.
+func (m *gpuManager) stats() []*orchestratorpb.GpuLoad {
+	m.mu.Lock()
+	holders := make(map[int]string, len(m.holders))
+	for index, taskID := range m.holders {
+		holders[index] = taskID
+	}
+	m.mu.Unlock()
+
+	loads := make([]*orchestratorpb.GpuLoad, 0, len(m.devices))
+	for _, dev := range m.devices {
+		load := &orchestratorpb.GpuLoad{
+			Index:  int32(dev.Index),
+			Uuid:   dev.UUID,
+			Name:   dev.Name,
+			TaskId: holders[dev.Index],
+		}
+		if util, ret := dev.handle.GetUtilizationRates(); ret == nvml.SUCCESS {
+			load.UtilizationPercent = float64(util.Gpu)
+		}
+		if mem, ret := dev.handle.GetMemoryInfo(); ret == nvml.SUCCESS {
+			load.MemoryUsedBytes = mem.Used
+			load.MemoryTotalBytes = mem.Total
+		}
+		loads = append(loads, load)
+	}
+	return loads
+}
+
+// gpuUUIDs identifies devices by UUID, which unlike indices does not
+// depend on CUDA's device ordering. The result is never nil.
+func gpuUUIDs(devices []*gpuDevice) []string {
+	ids := make([]string, len(devices))
+	for i, dev := range devices {
+		ids[i] = dev.UUID
+	}
+	return ids
+}
+
+// usesLocalGPUs reports whether the executor runs tasks on the worker's own
+// host, so that they are pinned to its GPUs. Executors that run tasks
+// elsewhere implement remote.
+func usesLocalGPUs(e Executor) bool {
+	_, remote := e.(interface{ remote() })
+	return !remote
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// version and commit identify the worker's build, set with
+// -ldflags "-X main.version=... -X main.commit=...". Without a commit, the
+// one Go stamped into the binary, if any, is reported.
+var (
+	version = "dev"
+	commit  = ""
+)
+
+// buildInfo is what /version reports of the running binary.
+type buildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	GoVersion string `json:"go_version"`
+	Executor  string `json:"executor"`
+	WorkerID  string `json:"worker_id"`
+}
+
+func (ws *WorkerServer) buildInfo() buildInfo {
+	info := buildInfo{
+		Version:   version,
+		Commit:    commit,
+		GoVersion: runtime.Version(),
+		Executor:  ws.executorName,
+		WorkerID:  ws.workerID,
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok && info.Commit == "" {
+		for _, s := range bi.Settings {
+			if s.Key == "vcs.revision" {
+				info.Commit = s.Value
+			}
+		}
+	}
+	return info
+}
+
+// executorCheckInterval is how often the executor's dependencies are
+// checked; probes read the latest result.
+const executorCheckInterval = 30 * time.Second
+
+// orchestratorContactTimeoutFromEnv reads WORKER_ORCHESTRATOR_CONTACT_TIMEOUT,
+// how long the worker may go without reaching the orchestrator before
+// /healthz fails and the platform restarts it. Zero never fails it for that.
+func orchestratorContactTimeoutFromEnv() time.Duration {
+	d, err := time.ParseDuration(os.Getenv("WORKER_ORCHESTRATOR_CONTACT_TIMEOUT"))
+	if err != nil || d < 0 {
+		return 10 * time.Minute
+	}
+	return d
+}
+
+// healthChecker is implemented by executors that depend on something
+// outside the worker that can fail, such as the Docker daemon.
+type healthChecker interface {
+	checkHealth(ctx context.Context) error
+}
+
+// workerHealth tracks what the worker's health endpoints report: when the
+// orchestrator was last reached and whether the executor can run tasks.
+type workerHealth struct {
+	contactTimeout time.Duration // Longest gap in contact before the worker is unhealthy
+
+	mu          sync.Mutex
+	lastContact time.Time // Last successful call to the orchestrator
+	executorErr error     // Latest executor check; nil while healthy
+	checkedAt   time.Time
+}
+
+func newWorkerHealth() *workerHealth {
+	return &workerHealth{contactTimeout: orchestratorContactTimeoutFromEnv()}
+}
+
+// contacted records a successful call to the orchestrator.
+func (h *workerHealth) contacted() {
+	h.mu.Lock()
+	h.lastContact = time.Now()
+	h.mu.Unlock()
+}
+
+// checkExecutor checks the executor now and then until ctx is done.
+func (h *workerHealth) checkExecutor(ctx context.Context, e Executor) {
+	checker, ok := e.(healthChecker)
+	if !ok {
+		return
+	}
+	for {
+		checkCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		err := checker.checkHealth(checkCtx)
+		cancel()
+
+		h.mu.Lock()
+		if err != nil && h.executorErr == nil {
+			log.Printf("Executor unhealthy: %v", err)
+		} else if err == nil && h.executorErr != nil {
+			log.Printf("Executor healthy again")
+		}
+		h.executorErr, h.checkedAt = err, time.Now()
+		h.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(executorCheckInterval):
+		}
+	}
+}
+
+// healthReport is the body of /healthz and /readyz.
+type healthReport struct {
+	Status       string            `json:"status"` // "ok", or "unhealthy" or "not ready"
+	Problems     []string          `json:"problems,omitempty"`
+	Orchestrator orchestratorCheck `json:"orchestrator"`
+	Executor     executorCheck     `json:"executor"`
+	RunningTasks int               `json:"running_tasks"`
+	UptimeSec    int64             `json:"uptime_seconds"`
+	Version      string            `json:"version"`
+}
+
+type orchestratorCheck struct {
+	TaskStream    bool  `json:"task_stream"`               // Open and taking tasks
+	LastContactMs int64 `json:"last_contact_ms,omitempty"` // Unix milliseconds
+}
+
+type executorCheck struct {
+	Name      string `json:"name"`
+	Healthy   bool   `json:"healthy"`
+	Error     string `json:"error,omitempty"`
+	CheckedMs int64  `json:"checked_ms,omitempty"` // Unix milliseconds; unset for executors without checks
+}
+
+// healthReport describes the worker's health. It is live as long as its
+// executor can run tasks and it has reached the orchestrator lately, and
+// ready when it also holds an open task stream and is not shutting down.
+func (ws *WorkerServer) healthReport(ctx context.Context) (report healthReport, live, ready bool) {
+	h := ws.probes
+	h.mu.Lock()
+	lastContact, executorErr, checkedAt := h.lastContact, h.executorErr, h.checkedAt
+	h.mu.Unlock()
+
+	resp, err := ws.health.Check(ctx, &healthpb.HealthCheckRequest{})
+	serving := err == nil && resp.Status == healthpb.HealthCheckResponse_SERVING
+
+	ws.runningMu.Lock()
+	running, shuttingDown := len(ws.running), ws.shuttingDown
+	ws.runningMu.Unlock()
+
+	report = healthReport{
+		Orchestrator: orchestratorCheck{TaskStream: serving},
+		Executor:     executorCheck{Name: ws.executorName, Healthy: executorErr == nil},
+		RunningTasks: running,
+		UptimeSec:    int64(time.Since(ws.startedAt).Seconds()),
+		Version:      version,
+	}
+	if !lastContact.IsZero() {
+		report.Orchestrator.LastContactMs = lastContact.UnixMilli()
+	}
+	if !checkedAt.IsZero() {
+		report.Executor.CheckedMs = checkedAt.UnixMilli()
+	}
+
+	live = true
+	if executorErr != nil {
+		report.Executor.Error = executorErr.Error()
+		report.Problems = append(report.Problems, "executor cannot run tasks: "+executorErr.Error())
+		live = false
+	}
+	since := lastContact
+	if since.IsZero() {
+		since = ws.startedAt
+	}
+	if h.contactTimeout > 0 && time.Since(since) > h.contactTimeout {
+		report.Problems = append(report.Problems, "orchestrator unreachable for "+time.Since(since).Round(time.Second).String())
+		live = false
+	}
+	ready = live && serving && !shuttingDown
+	if shuttingDown {
+		report.Problems = append(report.Problems, "shutting down")
+	} else if !serving {
+		report.Problems = append(report.Problems, "no task stream to the orchestrator")
+	}
+
+	switch {
+	case !live:
+		report.Status = "unhealthy"
+	case !ready:
+		report.Status = "not ready"
+	default:
+		report.Status = "ok"
+	}
+	return report, live, ready
+}
+
+// registerHealthHandlers serves /healthz, /readyz and /version on mux, for
+// the liveness and readiness probes of orchestration platforms.
+func (ws *WorkerServer) registerHealthHandlers(mux *http.ServeMux) {
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		report, live, _ := ws.healthReport(r.Context())
+		writeHealthJSON(w, live, report)
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		report, _, ready := ws.healthReport(r.Context())
+		writeHealthJSON(w, ready, report)
+	})
+	mux.HandleFunc("/version", func(w http.ResponseWriter, r *http.Request) {
+		writeHealthJSON(w, true, ws.buildInfo())
+	})
+}
+
+func writeHealthJSON(w http.ResponseWriter, ok bool, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if !ok {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(body)
+}
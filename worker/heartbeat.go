@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	orchestratorpb "github.com/tensorfleet/worker/proto/orchestrator"
+)
+
+// defaultHeartbeatInterval is used until the orchestrator names one at
+// registration.
+const defaultHeartbeatInterval = 10 * time.Second
+
+// heartbeatInterval is how often the worker heartbeats, as the orchestrator
+// asked at its latest registration.
+func (ws *WorkerServer) heartbeatInterval() time.Duration {
+	if d := time.Duration(ws.heartbeatSeconds.Load()) * time.Second; d > 0 {
+		return d
+	}
+	return defaultHeartbeatInterval
+}
+
+// heartbeatLoop tells the orchestrator the worker is alive, and how loaded
+// its host is, whether or not it is running tasks. A worker the orchestrator
+// has forgotten registers again.
+func (ws *WorkerServer) heartbeatLoop(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(ws.heartbeatInterval()):
+		}
+
+		load := ws.resources.stats().toProto()
+		load.Gpus = ws.gpus.stats()
+		load.BackpressureReason = ws.throttle.backpressure()
+		load.Backpressure = load.BackpressureReason != ""
+		registered, err := ws.heartbeat(ctx, load)
+		if err != nil {
+			log.Printf("Heartbeat failed: %v", err)
+			continue
+		}
+		if !registered {
+			log.Printf("Orchestrator no longer knows this worker; registering again")
+			if err := ws.register(ctx); err != nil {
+				log.Printf("Failed to register with orchestrator: %v", err)
+			}
+		}
+	}
+}
+
+// heartbeat sends one heartbeat and reports whether the orchestrator still
+// has the worker registered.
+func (ws *WorkerServer) heartbeat(ctx context.Context, load *orchestratorpb.WorkerLoad) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	ws.runningMu.Lock()
+	running := len(ws.running)
+	ws.runningMu.Unlock()
+	status := "IDLE"
+	if running > 0 {
+		status = "BUSY"
+	}
+
+	resp, err := ws.orchestratorClient.Heartbeat(ctx, &orchestratorpb.HeartbeatRequest{
+		WorkerId:       ws.workerID,
+		Status:         status,
+		CurrentTasks:   int32(running),
+		Load:           load,
+		StartedAtMs:    ws.startedAt.UnixMilli(),
+		CachedDatasets: ws.cachedDatasets(),
+	})
+	if err != nil {
+		return false, err
+	}
+	ws.probes.contacted()
+	return resp.Registered, nil
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"log"
+	"os"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// workerAuthToken is the secret shared with the orchestrator. The worker
+// sends it with every call to the orchestrator and, when set, requires it on
+// calls to its own WorkerService.
+var workerAuthToken = os.Getenv("WORKER_AUTH_TOKEN")
+
+const authMetadataKey = "authorization"
+
+// Metadata identifying the worker's session with the orchestrator.
+const (
+	workerIDMetadataKey      = "x-worker-id"
+	workerSessionMetadataKey = "x-worker-session"
+)
+
+// workerSession is the session token the orchestrator issued at the latest
+// registration. The orchestrator requires it on task RPCs.
+type workerSession struct {
+	mu       sync.RWMutex
+	workerID string
+	token    string
+}
+
+var session workerSession
+
+func (s *workerSession) set(workerID, token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.workerID, s.token = workerID, token
+}
+
+func (s *workerSession) attach(ctx context.Context) context.Context {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.token == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, workerIDMetadataKey, s.workerID, workerSessionMetadataKey, s.token)
+}
+
+// serverInterceptors returns panic recovery, request logging and token
+// authentication for the worker's gRPC server, outermost first.
+func serverInterceptors() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(recoveryUnaryInterceptor, loggingUnaryInterceptor, authUnaryInterceptor),
+	}
+}
+
+// clientInterceptors attach the worker token and session to calls to the
+// orchestrator.
+func clientInterceptors() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+			return invoker(withAuth(ctx), method, req, reply, cc, opts...)
+		}),
+		grpc.WithChainStreamInterceptor(func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+			return streamer(withAuth(ctx), desc, cc, method, opts...)
+		}),
+	}
+}
+
+func withAuth(ctx context.Context) context.Context {
+	ctx = session.attach(ctx)
+	if workerAuthToken == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, authMetadataKey, "Bearer "+workerAuthToken)
+}
+
+// recoveryUnaryInterceptor turns a handler panic into an INTERNAL error
+// instead of taking down the worker and its running tasks.
+func recoveryUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Panic in %s: %v\n%s", info.FullMethod, r, debug.Stack())
+			err = status.Errorf(codes.Internal, "internal error in %s", info.FullMethod)
+		}
+	}()
+	return handler(ctx, req)
+}
+
+// loggingUnaryInterceptor writes one key=value line per RPC.
+func loggingUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	if !strings.HasPrefix(info.FullMethod, "/grpc.health.") {
+		log.Printf("grpc method=%s code=%s duration=%s", info.FullMethod, status.Code(err),
+			time.Since(start).Round(time.Microsecond))
+	}
+	return resp, err
+}
+
+// authUnaryInterceptor rejects WorkerService calls without the worker token.
+// Health checks stay open for probes.
+func authUnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if workerAuthToken == "" || strings.HasPrefix(info.FullMethod, "/grpc.health.") {
+		return handler(ctx, req)
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get(authMetadataKey) {
+		token := strings.TrimPrefix(value, "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(workerAuthToken)) == 1 {
+			return handler(ctx, req)
+		}
+	}
+	return nil, status.Errorf(codes.Unauthenticated, "missing or invalid worker token")
+}
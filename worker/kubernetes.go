@@ -0,0 +1,446 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// kubernetesExecutor runs each task in a Pod of its own, for clusters
+// without long-lived workers: the worker only holds the task stream and
+// launches, watches and cleans up Pods. The training container speaks the
+// subprocess protocol, with two differences imposed by running elsewhere:
+// starting weights arrive through a ConfigMap mounted at /task-input, and
+// TENSORFLEET_RESULT_FILE is the container's termination message, so the
+// result must fit in Kubernetes' 4 KiB limit and weights_path is ignored.
+// The container returns its weights by uploading them to the storage
+// service at TENSORFLEET_WEIGHTS_UPLOAD_URL instead, where the worker picks
+// them up once the Pod has succeeded.
+// A job's GPU, CPU and memory requirements size its Pods in place of
+// WORKER_K8S_GPUS, WORKER_K8S_CPU and WORKER_K8S_MEMORY.
+type kubernetesExecutor struct {
+	kube           *kubeClient
+	defaultImage   string
+	serviceAccount string
+	timeout        time.Duration
+	limits         map[string]string // Container resource requests and limits
+	storage        *storageClient    // Where Pods upload their weights
+}
+
+const (
+	serviceAccountDir       = "/var/run/secrets/kubernetes.io/serviceaccount"
+	serviceAccountTokenPath = serviceAccountDir + "/token"
+
+	podInputDir        = "/task-input"
+	podTerminationPath = "/dev/termination-log"
+	podPollInterval    = 2 * time.Second
+	podGracePeriod     = 10 // Seconds a cancelled Pod gets to stop
+)
+
+func newKubernetesExecutor() (Executor, error) {
+	kube, err := newInClusterKubeClient()
+	if err != nil {
+		return nil, fmt.Errorf("the kubernetes executor must run in a cluster: %v", err)
+	}
+	timeout, err := trainTimeoutFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	limits := make(map[string]string)
+	for _, limit := range []struct{ resource, env string }{
+		{"cpu", "WORKER_K8S_CPU"},
+		{"memory", "WORKER_K8S_MEMORY"},
+		{"nvidia.com/gpu", "WORKER_K8S_GPUS"},
+	} {
+		if v := os.Getenv(limit.env); v != "" {
+			limits[limit.resource] = v
+		}
+	}
+
+	e := &kubernetesExecutor{
+		kube:           kube,
+		defaultImage:   os.Getenv("WORKER_DOCKER_DEFAULT_IMAGE"),
+		serviceAccount: os.Getenv("WORKER_K8S_SERVICE_ACCOUNT"),
+		timeout:        timeout,
+		limits:         limits,
+		storage:        newStorageClient(),
+	}
+	log.Printf("Running tasks as pods in namespace %s (default image %q, limits %v, timeout %s)",
+		kube.namespace, e.defaultImage, limits, timeout)
+	return e, nil
+}
+
+// kubeObject is the metadata of any object the executor creates.
+type kubeObject struct {
+	Metadata struct {
+		Name string `json:"name"`
+	} `json:"metadata"`
+}
+
+// The parts of the Pod status the executor reads.
+type podStatus struct {
+	kubeObject
+	Status struct {
+		Phase             string `json:"phase"`
+		Reason            string `json:"reason"`
+		Message           string `json:"message"`
+		ContainerStatuses []struct {
+			State struct {
+				Terminated *struct {
+					ExitCode int    `json:"exitCode"`
+					Reason   string `json:"reason"`
+					Message  string `json:"message"`
+				} `json:"terminated"`
+			} `json:"state"`
+		} `json:"containerStatuses"`
+	} `json:"status"`
+}
+
+// remote marks the executor as running tasks off the worker's host, so they
+// are not pinned to its GPUs.
+func (e *kubernetesExecutor) remote() {}
+
+// checkHealth reports whether the API server answers and lets the worker
+// list Pods in its namespace.
+func (e *kubernetesExecutor) checkHealth(ctx context.Context) error {
+	if err := e.kube.do(ctx, http.MethodGet, e.kube.url("/api/v1", "pods", "")+"?limit=1", "", nil, nil); err != nil {
+		return fmt.Errorf("kubernetes API unavailable: %v", err)
+	}
+	return nil
+}
+
+func (e *kubernetesExecutor) Run(ctx context.Context, spec TaskSpec) (Result, error) {
+	image := spec.Image
+	if image == "" {
+		image = e.defaultImage
+	}
+	if image == "" {
+		return Result{}, errors.New("job names no image and WORKER_DOCKER_DEFAULT_IMAGE is unset")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	// Cleanup runs even once ctx is done
+	cleanup, cleanupCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cleanupCancel()
+
+	weightsIn := ""
+	configMap := ""
+	if len(spec.ModelWeights) > 0 {
+		name, err := e.createWeightsConfigMap(ctx, spec)
+		if err != nil {
+			return Result{}, fmt.Errorf("failed to store starting weights: %v", err)
+		}
+		configMap = name
+		defer e.kube.delete(cleanup, e.kube.url("/api/v1", "configmaps", name))
+		weightsIn = podInputDir + "/" + taskWeightsInFile
+	}
+
+	// Removed whether or not the Pod got to upload its weights
+	weightsKey := podWeightsKey(spec)
+	defer func() {
+		if err := e.storage.remove(cleanup, weightsBucket, weightsKey); err != nil {
+			log.Printf("Failed to remove uploaded weights of task %s: %v", spec.TaskID, err)
+		}
+	}()
+	weightsOut, err := url.JoinPath(e.storage.baseURL, "api/v1/upload", weightsBucket, weightsKey)
+	if err != nil {
+		return Result{}, err
+	}
+
+	pod, err := e.createPod(ctx, spec, image, weightsIn, weightsOut, configMap)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to create pod: %v", err)
+	}
+	podURL := e.kube.url("/api/v1", "pods", pod)
+	defer e.kube.delete(cleanup, podURL)
+	log.Printf("Task %s running in pod %s", spec.TaskID, pod)
+
+	ticker := time.NewTicker(podPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return Result{}, fmt.Errorf("training pod %s timed out after %s", pod, e.timeout)
+			}
+			return Result{}, ctx.Err()
+		case <-ticker.C:
+		}
+
+		var status podStatus
+		if err := e.kube.do(ctx, http.MethodGet, podURL, "", nil, &status); err != nil {
+			log.Printf("Failed to check pod %s: %v", pod, err)
+			continue
+		}
+		switch status.Status.Phase {
+		case "Succeeded", "Failed":
+		default:
+			continue
+		}
+
+		message := status.Status.Message
+		exitCode := -1
+		usage := taskLimits(spec)
+		if cs := status.Status.ContainerStatuses; len(cs) > 0 && cs[0].State.Terminated != nil {
+			message = cs[0].State.Terminated.Message
+			exitCode = cs[0].State.Terminated.ExitCode
+			usage.OOMKilled = cs[0].State.Terminated.Reason == "OOMKilled"
+		}
+		spec.reportUsage(usage)
+		if usage.OOMKilled {
+			return Result{}, usage.oomError("training pod " + pod)
+		}
+		if status.Status.Phase == "Failed" {
+			return Result{}, fmt.Errorf("training pod %s failed (exit code %d, %s): %s",
+				pod, exitCode, status.Status.Reason, strings.TrimSpace(message))
+		}
+
+		out, err := decodeTaskResult([]byte(message), spec.TaskType)
+		if err != nil {
+			return Result{}, err
+		}
+		if out.WeightsPath != "" {
+			log.Printf("Ignoring weights_path of task %s: pods upload their weights instead", spec.TaskID)
+		}
+		result := out.metrics()
+		weights, err := e.storage.download(ctx, weightsBucket, weightsKey)
+		switch {
+		case errors.Is(err, errNoObject):
+		case err != nil:
+			return Result{}, fmt.Errorf("failed to fetch weights uploaded by pod %s: %v", pod, err)
+		default:
+			result.ModelWeights = weights
+		}
+		return result, nil
+	}
+}
+
+// podWeightsKey is where in weightsBucket a task's Pod uploads its weights,
+// unique to the run so that retries do not pick up an earlier attempt's.
+func podWeightsKey(spec TaskSpec) string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return "weights/pods/" + spec.TaskID + "-" + hex.EncodeToString(b)
+}
+
+// createWeightsConfigMap stores a task's starting weights for its Pod to
+// mount, returning the ConfigMap's name.
+func (e *kubernetesExecutor) createWeightsConfigMap(ctx context.Context, spec TaskSpec) (string, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"generateName": "tensorfleet-weights-",
+			"labels":       map[string]string{"app": "tensorfleet-task"},
+			"annotations":  map[string]string{"tensorfleet.io/task-id": spec.TaskID},
+		},
+		// encoding/json writes []byte as base64, as binaryData expects
+		"binaryData": map[string][]byte{taskWeightsInFile: spec.ModelWeights},
+	})
+	if err != nil {
+		return "", err
+	}
+	var created kubeObject
+	if err := e.kube.do(ctx, http.MethodPost, e.kube.url("/api/v1", "configmaps", ""), "application/json", body, &created); err != nil {
+		return "", err
+	}
+	return created.Metadata.Name, nil
+}
+
+func (e *kubernetesExecutor) createPod(ctx context.Context, spec TaskSpec, image, weightsIn, weightsOut, configMap string) (string, error) {
+	var env []map[string]string
+	for _, kv := range append(taskEnv(spec, weightsIn, podTerminationPath), "TENSORFLEET_WEIGHTS_UPLOAD_URL="+weightsOut) {
+		name, value, _ := strings.Cut(kv, "=")
+		env = append(env, map[string]string{"name": name, "value": value})
+	}
+
+	container := map[string]interface{}{
+		"name":                     "train",
+		"image":                    image,
+		"workingDir":               containerTaskDir,
+		"env":                      env,
+		"terminationMessagePath":   podTerminationPath,
+		"terminationMessagePolicy": "File",
+		"volumeMounts": []map[string]interface{}{
+			{"name": "task", "mountPath": containerTaskDir},
+		},
+	}
+	// The job's requirements win over the worker's defaults
+	limits := make(map[string]string, len(e.limits))
+	for resource, v := range e.limits {
+		limits[resource] = v
+	}
+	if spec.GPUCount > 0 {
+		limits["nvidia.com/gpu"] = strconv.Itoa(int(spec.GPUCount))
+	}
+	if spec.CPUCores > 0 {
+		limits["cpu"] = strconv.Itoa(int(spec.CPUCores))
+	}
+	if spec.MemoryMB > 0 {
+		limits["memory"] = strconv.FormatInt(spec.MemoryMB, 10) + "Mi"
+	}
+	if len(limits) > 0 {
+		container["resources"] = map[string]interface{}{"requests": limits, "limits": limits}
+	}
+	volumes := []map[string]interface{}{
+		{"name": "task", "emptyDir": map[string]interface{}{}},
+	}
+	if configMap != "" {
+		container["volumeMounts"] = append(container["volumeMounts"].([]map[string]interface{}),
+			map[string]interface{}{"name": "input", "mountPath": podInputDir, "readOnly": true})
+		volumes = append(volumes, map[string]interface{}{
+			"name": "input", "configMap": map[string]interface{}{"name": configMap},
+		})
+	}
+
+	podSpec := map[string]interface{}{
+		"restartPolicy":                 "Never",
+		"activeDeadlineSeconds":         int64(e.timeout / time.Second),
+		"terminationGracePeriodSeconds": podGracePeriod,
+		"containers":                    []interface{}{container},
+		"volumes":                       volumes,
+	}
+	if e.serviceAccount != "" {
+		podSpec["serviceAccountName"] = e.serviceAccount
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata": map[string]interface{}{
+			"generateName": "tensorfleet-task-",
+			"labels":       map[string]string{"app": "tensorfleet-task"},
+			"annotations": map[string]string{
+				"tensorfleet.io/task-id": spec.TaskID,
+				"tensorfleet.io/job-id":  spec.JobID,
+			},
+		},
+		"spec": podSpec,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var created podStatus
+	if err := e.kube.do(ctx, http.MethodPost, e.kube.url("/api/v1", "pods", ""), "application/json", body, &created); err != nil {
+		return "", err
+	}
+	return created.Metadata.Name, nil
+}
+
+// kubeClient is just enough of a Kubernetes API client to manage task Pods
+// and their ConfigMaps, authenticated as the pod's service account.
+type kubeClient struct {
+	baseURL   string
+	namespace string
+	client    *http.Client
+}
+
+func newInClusterKubeClient() (*kubeClient, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return nil, fmt.Errorf("not running in a Kubernetes cluster")
+	}
+	if _, err := os.Stat(serviceAccountTokenPath); err != nil {
+		return nil, fmt.Errorf("no service account token: %v", err)
+	}
+	caCert, err := os.ReadFile(serviceAccountDir + "/ca.crt")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cluster CA: %v", err)
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(caCert) {
+		return nil, fmt.Errorf("invalid cluster CA certificate")
+	}
+
+	namespace := os.Getenv("WORKER_K8S_NAMESPACE")
+	if namespace == "" {
+		if ns, err := os.ReadFile(serviceAccountDir + "/namespace"); err == nil {
+			namespace = strings.TrimSpace(string(ns))
+		}
+	}
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	return &kubeClient{
+		baseURL:   "https://" + net.JoinHostPort(host, port),
+		namespace: namespace,
+		client: &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}},
+		},
+	}, nil
+}
+
+// url names a namespaced resource collection, or one object of it.
+func (k *kubeClient) url(api, resource, name string) string {
+	u := fmt.Sprintf("%s%s/namespaces/%s/%s", k.baseURL, api, k.namespace, resource)
+	if name != "" {
+		u += "/" + name
+	}
+	return u
+}
+
+// delete removes an object, logging failures; one already gone is fine.
+func (k *kubeClient) delete(ctx context.Context, url string) {
+	body := []byte(fmt.Sprintf(`{"gracePeriodSeconds":%d,"propagationPolicy":"Background"}`, podGracePeriod))
+	err := k.do(ctx, http.MethodDelete, url, "application/json", body, nil)
+	if err != nil && !errors.Is(err, errKubeNotFound) {
+		log.Printf("Warning: Failed to delete %s: %v", url, err)
+	}
+}
+
+var errKubeNotFound = errors.New("not found")
+
+func (k *kubeClient) do(ctx context.Context, method, url, contentType string, body []byte, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	// The kubelet rotates the token file, so it is read for every request
+	token, err := os.ReadFile(serviceAccountTokenPath)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	req.Header.Set("Accept", "application/json")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%s %s: %w", method, url, errKubeNotFound)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s %s: status %d: %s", method, url, resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// executorLabel is the label every worker carries naming its executor, so
+// jobs can require e.g. executor=docker.
+const executorLabel = "executor"
+
+// labelPattern is what label keys and values may look like, after
+// Kubernetes labels so the same ones can be used for Pods.
+var labelPattern = regexp.MustCompile(`^[A-Za-z0-9]([-A-Za-z0-9_./]{0,61}[A-Za-z0-9])?$`)
+
+// labelsFromEnv reads WORKER_LABELS, comma-separated key=value pairs such as
+// "zone=us-east,gpu=a100" that jobs can require of the workers they run on.
+// The executor label is added from WORKER_EXECUTOR.
+func labelsFromEnv(executor string) (map[string]string, error) {
+	labels := map[string]string{executorLabel: executor}
+	for _, pair := range strings.Split(os.Getenv("WORKER_LABELS"), ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok || !labelPattern.MatchString(key) || (value != "" && !labelPattern.MatchString(value)) {
+			return nil, fmt.Errorf("invalid WORKER_LABELS entry %q (want key=value)", pair)
+		}
+		if key == executorLabel && value != executor {
+			return nil, fmt.Errorf("WORKER_LABELS cannot set %s=%s; it follows WORKER_EXECUTOR (%s)", key, value, executor)
+		}
+		labels[key] = value
+	}
+	return labels, nil
+}
@@ -0,0 +1,328 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/protobuf/proto"
+
+	orchestratorpb "github.com/tensorfleet/worker/proto/orchestrator"
+	workerpb "github.com/tensorfleet/worker/proto/worker"
+)
+
+var (
+	logEntriesShipped = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "worker_log_entries_shipped_total",
+		Help: "Task log lines shipped to the orchestrator",
+	})
+	logEntriesDropped = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "worker_log_entries_dropped_total",
+		Help: "Task log lines dropped because the buffer was full or they could not be shipped",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(logEntriesShipped, logEntriesDropped)
+}
+
+const (
+	// logBufferMax is how many log lines wait to be shipped at most; the
+	// oldest are dropped beyond it, e.g. while the orchestrator is down.
+	logBufferMax = 10000
+	// logBatchMax is how many lines one ShipTaskLogs call carries.
+	logBatchMax = 500
+	// logLineMax is the longest line shipped; longer output is split.
+	logLineMax = 8 << 10
+	// logFlushTimeout bounds how long a stopping worker spends shipping the
+	// lines still buffered.
+	logFlushTimeout = 5 * time.Second
+)
+
+// LogLine is one line of a task's training log.
+type LogLine struct {
+	Level   string // "DEBUG", "INFO", "WARN" or "ERROR"
+	Message string
+	Fields  map[string]string // Structured values, e.g. the batch and its loss
+}
+
+// logLine passes a log line on to the worker, if it is listening.
+func (spec TaskSpec) logLine(line LogLine) {
+	if spec.Log != nil {
+		spec.Log(line)
+	}
+}
+
+// logShippingFromEnv reads WORKER_LOG_SHIPPING; set to false to keep task
+// logs on the worker.
+func logShippingFromEnv() bool {
+	return os.Getenv("WORKER_LOG_SHIPPING") != "false"
+}
+
+// logShipIntervalFromEnv reads WORKER_LOG_SHIP_INTERVAL, how often buffered
+// task log lines are shipped.
+func logShipIntervalFromEnv() time.Duration {
+	d, err := time.ParseDuration(os.Getenv("WORKER_LOG_SHIP_INTERVAL"))
+	if err != nil || d <= 0 {
+		return 2 * time.Second
+	}
+	return d
+}
+
+// logShipper buffers task log lines and ships them to the orchestrator in
+// batches, which keeps them in the central log store. Shipping never holds
+// training up: lines wait in a bounded buffer while the orchestrator is
+// unreachable. A nil shipper drops everything.
+type logShipper struct {
+	client   orchestratorpb.OrchestratorServiceClient
+	workerID string
+	interval time.Duration
+
+	mu  sync.Mutex
+	buf []*orchestratorpb.TaskLogEntry
+
+	flushMu sync.Mutex // One flush at a time, so lines are shipped once and in order
+}
+
+func newLogShipper(client orchestratorpb.OrchestratorServiceClient, workerID string) *logShipper {
+	if !logShippingFromEnv() {
+		return nil
+	}
+	return &logShipper{client: client, workerID: workerID, interval: logShipIntervalFromEnv()}
+}
+
+// add queues a line of a task's log. Invalid UTF-8, which a training
+// script may well print, is replaced, as protobuf strings must be valid.
+func (s *logShipper) add(taskID, jobID string, line LogLine) {
+	if s == nil {
+		return
+	}
+	entry := &orchestratorpb.TaskLogEntry{
+		TaskId:      taskID,
+		JobId:       jobID,
+		TimestampMs: time.Now().UnixMilli(),
+		Level:       line.Level,
+		Message:     strings.ToValidUTF8(line.Message, "\uFFFD"),
+		Fields:      make(map[string]string, len(line.Fields)),
+	}
+	for k, v := range line.Fields {
+		entry.Fields[strings.ToValidUTF8(k, "\uFFFD")] = strings.ToValidUTF8(v, "\uFFFD")
+	}
+	s.mu.Lock()
+	if len(s.buf) >= logBufferMax {
+		s.buf = s.buf[1:]
+		logEntriesDropped.Inc()
+	}
+	s.buf = append(s.buf, entry)
+	s.mu.Unlock()
+}
+
+// run ships buffered lines every interval until ctx is done.
+func (s *logShipper) run(ctx context.Context) {
+	if s == nil {
+		return
+	}
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	failing := false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		err := s.flush(ctx)
+		// Log once per outage rather than once per attempt
+		if err != nil && !failing {
+			log.Printf("Failed to ship task logs: %v", err)
+		} else if err == nil && failing {
+			log.Printf("Shipping task logs again")
+		}
+		failing = err != nil
+	}
+}
+
+// flush ships the buffered lines, oldest first, until none are left or a
+// batch fails. A batch that cannot be marshalled would fail every time, so
+// it is dropped instead.
+func (s *logShipper) flush(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+	for {
+		s.mu.Lock()
+		batch := s.buf[:min(len(s.buf), logBatchMax)]
+		s.mu.Unlock()
+		if len(batch) == 0 {
+			return nil
+		}
+
+		req := &orchestratorpb.ShipTaskLogsRequest{WorkerId: s.workerID, Entries: batch}
+		sendCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		_, err := s.client.ShipTaskLogs(sendCtx, req)
+		cancel()
+		if err != nil {
+			_, merr := proto.Marshal(req)
+			if merr == nil {
+				return err
+			}
+			log.Printf("Dropping %d task log lines that cannot be shipped: %v", len(batch), merr)
+			logEntriesDropped.Add(float64(len(batch)))
+		} else {
+			logEntriesShipped.Add(float64(len(batch)))
+		}
+		s.remove(batch)
+	}
+}
+
+// remove takes a batch that was shipped or dropped off the buffer. Lines
+// are only dropped from the front, so whatever is left of the batch still
+// leads the buffer.
+func (s *logShipper) remove(batch []*orchestratorpb.TaskLogEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	last := batch[len(batch)-1]
+	for i := 0; i < len(s.buf) && i < len(batch); i++ {
+		if s.buf[i] == last {
+			s.buf = s.buf[i+1:]
+			break
+		}
+	}
+}
+
+// taskLogger ships the log lines of one task.
+type taskLogger struct {
+	shipper *logShipper
+	taskID  string
+	jobID   string
+}
+
+func (ws *WorkerServer) taskLogger(req *workerpb.TaskRequest) taskLogger {
+	return taskLogger{shipper: ws.logs, taskID: req.TaskId, jobID: req.JobId}
+}
+
+func (l taskLogger) line(line LogLine) {
+	l.shipper.add(l.taskID, l.jobID, line)
+}
+
+func (l taskLogger) printf(level, format string, args ...interface{}) {
+	l.line(LogLine{Level: level, Message: fmt.Sprintf(format, args...)})
+}
+
+// progress logs how far the task has got, with the metrics of the batches
+// done so far as fields.
+func (l taskLogger) progress(p Progress) {
+	l.line(LogLine{
+		Level:   "INFO",
+		Message: fmt.Sprintf("Batch %d/%d: loss %.4f, accuracy %.4f", p.BatchesDone, p.BatchesTotal, p.Loss, p.Accuracy),
+		Fields: map[string]string{
+			"batch":         strconv.Itoa(int(p.BatchesDone)),
+			"batches_total": strconv.Itoa(int(p.BatchesTotal)),
+			"loss":          strconv.FormatFloat(p.Loss, 'g', -1, 64),
+			"accuracy":      strconv.FormatFloat(p.Accuracy, 'g', -1, 64),
+		},
+	})
+}
+
+// usage logs what the task used of its resource limits.
+func (l taskLogger) usage(u ResourceUsage) {
+	level := "INFO"
+	if u.OOMKilled {
+		level = "ERROR"
+	}
+	l.line(LogLine{
+		Level:   level,
+		Message: "Resources used: " + u.summary(),
+		Fields: map[string]string{
+			"cpu_limit_cores":       strconv.FormatFloat(u.CPULimitCores, 'g', -1, 64),
+			"memory_limit_bytes":    strconv.FormatInt(u.MemoryLimitBytes, 10),
+			"peak_memory_bytes":     strconv.FormatInt(u.PeakMemoryBytes, 10),
+			"cpu_seconds":           strconv.FormatFloat(u.CPUSeconds, 'g', -1, 64),
+			"cpu_throttled_seconds": strconv.FormatFloat(u.CPUThrottledSeconds, 'g', -1, 64),
+			"oom_killed":            strconv.FormatBool(u.OOMKilled),
+		},
+	})
+}
+
+// logWriter turns the output of a training script or container into log
+// lines of the task, one per line written. Lines longer than logLineMax
+// are split between characters. flush passes on a last line left without
+// a newline.
+type logWriter struct {
+	spec   TaskSpec
+	level  string
+	stream string
+
+	mu      sync.Mutex
+	partial []byte
+}
+
+func newLogWriter(spec TaskSpec, level, stream string) *logWriter {
+	return &logWriter{spec: spec, level: level, stream: stream}
+}
+
+func (w *logWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, b := range p {
+		if b == '\n' {
+			w.emit()
+			continue
+		}
+		w.partial = append(w.partial, b)
+		if len(w.partial) >= logLineMax {
+			w.split()
+		}
+	}
+	return len(p), nil
+}
+
+// split passes on the partial line up to its last complete character and
+// keeps the rest for the next line. Caller must hold w.mu.
+func (w *logWriter) split() {
+	cut := len(w.partial)
+	for i := cut - 1; i >= 0 && i >= cut-utf8.UTFMax; i-- {
+		if utf8.RuneStart(w.partial[i]) {
+			if !utf8.FullRune(w.partial[i:]) {
+				cut = i
+			}
+			break
+		}
+	}
+	if cut == 0 {
+		cut = len(w.partial)
+	}
+	rest := append([]byte(nil), w.partial[cut:]...)
+	w.partial = w.partial[:cut]
+	w.emit()
+	w.partial = append(w.partial, rest...)
+}
+
+func (w *logWriter) flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.emit()
+}
+
+// emit passes on the partial line. Caller must hold w.mu.
+func (w *logWriter) emit() {
+	if len(w.partial) == 0 {
+		return
+	}
+	w.spec.logLine(LogLine{
+		Level:   w.level,
+		Message: string(w.partial),
+		Fields:  map[string]string{"stream": w.stream},
+	})
+	w.partial = w.partial[:0]
+}
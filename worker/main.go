@@ -2,73 +2,242 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
-	"math/rand"
 	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 
 	orchestratorpb "github.com/tensorfleet/worker/proto/orchestrator"
 	workerpb "github.com/tensorfleet/worker/proto/worker"
 )
 
 var (
-	taskDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+	taskDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
 		Name: "worker_task_duration_seconds",
 		Help: "Time taken to complete a task",
-	})
-	tasksCompleted = prometheus.NewCounter(prometheus.CounterOpts{
+	}, []string{"job_id"})
+	tasksCompleted = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Name: "worker_tasks_completed_total",
 		Help: "Total number of tasks completed",
-	})
-	tasksFailed = prometheus.NewCounter(prometheus.CounterOpts{
+	}, []string{"job_id"})
+	tasksFailed = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Name: "worker_tasks_failed_total",
 		Help: "Total number of tasks failed",
+	}, []string{"job_id"})
+	tasksCancelled = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "worker_tasks_cancelled_total",
+		Help: "Total number of tasks stopped by cancellation",
+	}, []string{"job_id"})
+	tasksRunning = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "worker_tasks_running",
+		Help: "Tasks currently holding a slot in the worker's pool",
+	})
+	tasksPrefetched = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "worker_tasks_prefetched",
+		Help: "Tasks received from the orchestrator and waiting for a slot in the pool",
 	})
+	tasksInFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "worker_tasks_in_flight",
+		Help: "Tasks started and not yet finished",
+	}, []string{"job_id"})
+	taskQueueWait = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "worker_task_queue_wait_seconds",
+		Help:    "Time tasks waited in the orchestrator's queue before reaching the worker",
+		Buckets: prometheus.ExponentialBuckets(0.01, 4, 10),
+	}, []string{"job_id"})
+	taskAssignmentLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "worker_task_assignment_latency_seconds",
+		Help:    "Time from asking the orchestrator for a task to receiving one",
+		Buckets: prometheus.ExponentialBuckets(0.01, 4, 10),
+	}, []string{"job_id"})
 )
 
 func init() {
 	prometheus.MustRegister(taskDuration)
 	prometheus.MustRegister(tasksCompleted)
 	prometheus.MustRegister(tasksFailed)
+	prometheus.MustRegister(tasksCancelled)
+	prometheus.MustRegister(tasksRunning)
+	prometheus.MustRegister(tasksPrefetched)
+	prometheus.MustRegister(tasksInFlight)
+	prometheus.MustRegister(taskQueueWait)
+	prometheus.MustRegister(taskAssignmentLatency)
 }
 
 type WorkerServer struct {
 	workerpb.UnimplementedWorkerServiceServer
-	workerID            string
-	orchestratorClient  orchestratorpb.OrchestratorServiceClient
-	currentTasks        int
-	completedTasks      int
+	workerID           string
+	orchestratorClient orchestratorpb.OrchestratorServiceClient
+	completedTasks     int
+	health             *health.Server
+	probes             *workerHealth       // What /healthz and /readyz report
+	executor           Executor            // Trains the tasks
+	executorName       string              // WORKER_EXECUTOR it was built from
+	evaluator          *evaluationExecutor // Runs evaluation and validation tasks
+	evaluatorName      string              // WORKER_EVAL_EXECUTOR it was built from
+	labels             map[string]string
+	taskTypes          []string // Task types the worker runs; empty for all
+	resources          *resourceMonitor
+	gpus               *gpuManager
+	throttle           *loadThrottle // Holds back new tasks while the host is too busy
+	datasets           *datasetCache // Nil unless the executor reads datasets locally
+	storage            *storageClient
+	inlineWeightsMax   int              // Largest weights reported inline; larger ones are uploaded
+	reports            *reportQueue     // Delivers task reports, retrying until they land
+	logs               *logShipper      // Ships task logs; nil when disabled
+	checkpoints        *taskCheckpoints // Local state of interrupted tasks; nil when disabled
+	checkpointInterval time.Duration    // How often tasks are asked to checkpoint; 0 leaves it to them
+	workspaceDir       string           // Parent of the tasks' working directories
+	artifactMaxBytes   int64            // Largest file uploaded as a task artifact
+	startedAt          time.Time
+	progressInterval   time.Duration // Least time between a task's progress reports
+
+	// Heartbeat interval the orchestrator asked for at registration
+	heartbeatSeconds atomic.Int32
+
+	// Longest wait before reopening the task stream, as a time.Duration;
+	// changes when the configuration is reloaded
+	maxReconnectDelay atomic.Int64
+
+	// Tasks the orchestrator lets the worker hold beyond its pool, as
+	// granted at registration
+	prefetchTasks atomic.Int32
+
+	// Bounds the tasks run at once; its size is advertised to the
+	// orchestrator at registration
+	pool *taskPool
+
+	runningMu    sync.Mutex
+	running      map[string]*runningTask // In-progress tasks by ID
+	shuttingDown bool                    // No new tasks are started once set
+	preempted    chan struct{}           // Closed on a preemption notice
+	inFlight     sync.WaitGroup          // Tasks started and not yet reported
+}
+
+// runningTask is a task in progress, which CancelTask can stop.
+type runningTask struct {
+	jobID  string
+	cancel context.CancelCauseFunc
+	done   chan struct{} // Closed once the task has stopped
+}
+
+// Causes a running task is cancelled with.
+var (
+	errTaskCancelled      = errors.New("task cancelled")
+	errWorkerShuttingDown = errors.New("worker shutting down")
+	errWorkerPreempted    = errors.New("worker preempted")
+)
+
+// cancelWaitTimeout bounds how long CancelTask waits for a task to stop. It
+// covers the executors' grace periods for interrupted scripts and containers.
+const cancelWaitTimeout = 15 * time.Second
+
+// listenPort is the port of the worker's gRPC server.
+func listenPort() string {
+	if port := os.Getenv("PORT"); port != "" {
+		return port
+	}
+	return "50052"
 }
 
 func NewWorkerServer() (*WorkerServer, error) {
-	workerID := uuid.New().String()
-	
 	orchestratorAddr := os.Getenv("ORCHESTRATOR_ADDR")
 	if orchestratorAddr == "" {
 		orchestratorAddr = "orchestrator:50051"
 	}
 
 	log.Printf("Connecting to orchestrator at %s", orchestratorAddr)
-	conn, err := grpc.Dial(orchestratorAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	dialOpts := append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, clientInterceptors()...)
+	conn, err := grpc.Dial(orchestratorAddr, dialOpts...)
 	if err != nil {
 		return nil, err
 	}
 
 	client := orchestratorpb.NewOrchestratorServiceClient(conn)
+	reports := newReportQueue(client, reportSpoolDirFromEnv(), reportMaxAgeFromEnv())
+	workerID := reports.workerID()
+
+	executorName := executorNameFromEnv()
+	executor, err := newExecutor(executorName)
+	if err != nil {
+		return nil, err
+	}
+	evaluatorName := evaluatorNameFromEnv(executorName)
+	evaluator, err := newEvaluationExecutor(evaluatorName, executorName, executor)
+	if err != nil {
+		return nil, err
+	}
+	labels, err := labelsFromEnv(executorName)
+	if err != nil {
+		return nil, err
+	}
+	preemptible := preemptibleFromEnv()
+	if preemptible {
+		labels[preemptibleLabel] = "true"
+	}
+	taskTypes, err := taskTypesFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	var datasets *datasetCache
+	if readsLocalDatasets(executor) || readsLocalDatasets(evaluator.backend) {
+		if datasets, err = newDatasetCache(datasetCacheDirFromEnv(), datasetCacheMaxBytesFromEnv()); err != nil {
+			return nil, err
+		}
+	}
 
 	ws := &WorkerServer{
 		workerID:           workerID,
 		orchestratorClient: client,
+		health:             health.NewServer(),
+		probes:             newWorkerHealth(),
+		executor:           executor,
+		executorName:       executorName,
+		evaluator:          evaluator,
+		evaluatorName:      evaluatorName,
+		labels:             labels,
+		taskTypes:          taskTypes,
+		resources:          newResourceMonitor(),
+		gpus:               detectGPUs(),
+		throttle:           newLoadThrottle(loadThresholdsFromEnv()),
+		datasets:           datasets,
+		storage:            newStorageClient(),
+		inlineWeightsMax:   inlineWeightsMaxFromEnv(),
+		reports:            reports,
+		logs:               newLogShipper(client, workerID),
+		checkpoints:        newTaskCheckpoints(taskCheckpointDirFromEnv(), taskCheckpointMaxAgeFromEnv()),
+		workspaceDir:       workspaceDirFromEnv(),
+		artifactMaxBytes:   artifactMaxBytesFromEnv(),
+		startedAt:          time.Now(),
+		progressInterval:   progressIntervalFromEnv(),
+		pool:               newTaskPool(maxConcurrentTasksFromEnv()),
+		running:            make(map[string]*runningTask),
+		preempted:          make(chan struct{}),
 	}
+	// Spot instances are reclaimed at short notice; checkpoint often so
+	// their tasks lose little when they are
+	if preemptible {
+		ws.checkpointInterval = preemptibleCheckpointIntervalFromEnv()
+	}
+	// Not serving until the task stream to the orchestrator is open
+	ws.setServingStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+	ws.reports.uploadWeights = ws.uploadReportWeights
+	ws.maxReconnectDelay.Store(int64(reconnectMaxBackoffFromEnv()))
+	ws.reports.recover(workerID)
 
 	return ws, nil
 }
@@ -78,27 +247,114 @@ func (ws *WorkerServer) isJobCancelled(ctx context.Context, jobID string) (bool,
 	// Create a context with timeout for the status check
 	checkCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
 	defer cancel()
-	
+
 	resp, err := ws.orchestratorClient.GetJobStatus(checkCtx, &orchestratorpb.GetJobStatusRequest{
 		JobId: jobID,
 	})
-	
+
 	if err != nil {
 		log.Printf("Failed to check job status: %v", err)
 		return false, err
 	}
-	
+
 	// Consider job cancelled if status is CANCELLED or FAILED
 	return resp.Status == "CANCELLED" || resp.Status == "FAILED", nil
 }
 
+// ExecuteTask runs a task sent directly to the worker if it has a free
+// slot and its host is not too busy, and turns it away otherwise.
 func (ws *WorkerServer) ExecuteTask(ctx context.Context, req *workerpb.TaskRequest) (*workerpb.TaskResponse, error) {
+	if !ws.runsTaskType(taskTypeOf(req)) {
+		return &workerpb.TaskResponse{
+			TaskId:  req.TaskId,
+			Success: false,
+			Message: fmt.Sprintf("Worker does not run %s tasks", taskTypeOf(req)),
+		}, nil
+	}
+	if reason := ws.throttle.backpressure(); reason != "" {
+		return &workerpb.TaskResponse{
+			TaskId:  req.TaskId,
+			Success: false,
+			Message: "Worker host is too busy: " + reason,
+		}, nil
+	}
+	if !ws.pool.tryAcquire() {
+		return &workerpb.TaskResponse{
+			TaskId:  req.TaskId,
+			Success: false,
+			Message: fmt.Sprintf("Worker is at capacity (%d tasks)", ws.pool.size()),
+		}, nil
+	}
+	defer ws.pool.release()
+	return ws.runTask(ctx, req)
+}
+
+// runTask trains a task and reports the result. The caller holds a pool
+// slot for it.
+func (ws *WorkerServer) runTask(ctx context.Context, req *workerpb.TaskRequest) (*workerpb.TaskResponse, error) {
 	start := time.Now()
-	log.Printf("Worker %s executing task %s (epoch %d, batches %d-%d)", 
+	log.Printf("Worker %s executing task %s (epoch %d, batches %d-%d)",
 		ws.workerID, req.TaskId, req.Epoch, req.BatchStart, req.BatchEnd)
 
-	ws.currentTasks++
-	defer func() { ws.currentTasks-- }()
+	// CancelTask stops the task, e.g. when the orchestrator reclaims it
+	// after its timeout; the cause says why
+	ctx, cancel := context.WithCancelCause(ctx)
+	task := &runningTask{jobID: req.JobId, cancel: cancel, done: make(chan struct{})}
+	ws.runningMu.Lock()
+	if ws.shuttingDown {
+		ws.runningMu.Unlock()
+		cancel(nil)
+		// Leased to this worker just before it stopped taking tasks
+		ws.releaseTask(req, "worker shutting down")
+		return &workerpb.TaskResponse{
+			TaskId:  req.TaskId,
+			Success: false,
+			Message: "Task released - worker is shutting down",
+		}, nil
+	}
+	ws.running[req.TaskId] = task
+	ws.inFlight.Add(1)
+	ws.updateInFlight(req.JobId)
+	ws.runningMu.Unlock()
+	defer func() {
+		ws.runningMu.Lock()
+		delete(ws.running, req.TaskId)
+		ws.updateInFlight(req.JobId)
+		ws.runningMu.Unlock()
+		cancel(nil)
+		close(task.done)
+		ws.inFlight.Done()
+	}()
+
+	// A task the worker already finished, and crashed or lost touch with
+	// the orchestrator before reporting, is handed out again once its
+	// lease expires. Its journaled result stands instead of a second run,
+	// unless its weights cannot be had
+	if report, ok := ws.reports.completed(req.TaskId, req.Attempt); ok {
+		weights, err := ws.reportedWeights(ctx, report)
+		if err == nil {
+			log.Printf("Task %s already completed; delivering its journaled result instead of running it again", req.TaskId)
+			if report.Attempt == req.Attempt {
+				ws.reports.signal()
+			} else {
+				// The orchestrator only takes the result for the attempt it
+				// handed out; the journaled one for the earlier attempt is
+				// turned away
+				report.Attempt = req.Attempt
+				report.WorkerId = ws.workerID
+				ws.reports.submit(report, 10*time.Second)
+			}
+			return &workerpb.TaskResponse{
+				TaskId:       req.TaskId,
+				Success:      true,
+				Message:      "Task already completed",
+				Loss:         report.Loss,
+				Accuracy:     report.Accuracy,
+				ModelWeights: weights,
+			}, nil
+		}
+		log.Printf("Running task %s again: failed to fetch the weights of its journaled result: %v", req.TaskId, err)
+	}
 
 	// Check if job is cancelled before starting
 	if cancelled, err := ws.isJobCancelled(ctx, req.JobId); err == nil && cancelled {
@@ -110,33 +366,59 @@ func (ws *WorkerServer) ExecuteTask(ctx context.Context, req *workerpb.TaskReque
 		}, nil
 	}
 
-	// Simulate training
-	success, loss, accuracy := ws.simulateTraining(ctx, req)
+	tlog := ws.taskLogger(req)
+	tlog.printf("INFO", "Task started on worker %s (epoch %d, batches %d-%d)", ws.workerID, req.Epoch, req.BatchStart, req.BatchEnd)
+	go ws.watchJob(ctx, req.JobId, cancel)
+	progress := ws.newProgressReporter(req, cancel)
+	go progress.run(ctx)
+	// Sent with the task's report by executors that enforce its limits
+	var usage *orchestratorpb.TaskResourceUsage
+	result, datasetCached, err := ws.train(ctx, req, func(p Progress) {
+		progress.report(p)
+		tlog.progress(p)
+	}, func(u ResourceUsage) {
+		usage = u.toProto()
+		if u.OOMKilled {
+			tasksOOMKilled.WithLabelValues(jobLabel(req.JobId)).Inc()
+		}
+		tlog.usage(u)
+	}, tlog)
 
 	duration := time.Since(start).Seconds()
-	taskDuration.Observe(duration)
+	job := jobLabel(req.JobId)
+	taskDuration.WithLabelValues(job).Observe(duration)
 
-	if success {
-		tasksCompleted.Inc()
+	if err == nil {
+		tasksCompleted.WithLabelValues(job).Inc()
+		ws.runningMu.Lock()
 		ws.completedTasks++
-
-		// Report completion to orchestrator
-		_, err := ws.orchestratorClient.ReportTaskCompletion(ctx, &orchestratorpb.TaskCompletionRequest{
-			TaskId:       req.TaskId,
-			JobId:        req.JobId,
-			WorkerId:     ws.workerID,
-			Success:      true,
-			Loss:         loss,
-			Accuracy:     accuracy,
-			ModelWeights: []byte{}, // Simulated weights
-		})
-
-		if err != nil {
-			log.Printf("Failed to report task completion: %v", err)
+		ws.runningMu.Unlock()
+		loss, accuracy, weights := result.Loss, result.Accuracy, result.ModelWeights
+
+		// Report completion to orchestrator, even if the task is being
+		// stopped now that its result is in. The report is journaled with
+		// the weights before large ones are uploaded; the orchestrator
+		// fetches uploaded weights before it answers
+		reportTimeout := 10 * time.Second
+		if len(weights) > ws.inlineWeightsMax {
+			reportTimeout = 90 * time.Second
 		}
-
-		log.Printf("Task %s completed successfully. Loss: %.4f, Accuracy: %.4f", 
+		ws.reports.submit(&orchestratorpb.TaskCompletionRequest{
+			TaskId:        req.TaskId,
+			JobId:         req.JobId,
+			Attempt:       req.Attempt,
+			WorkerId:      ws.workerID,
+			Success:       true,
+			Loss:          loss,
+			Accuracy:      accuracy,
+			ModelWeights:  weights,
+			DatasetCache:  datasetCached,
+			ResourceUsage: usage,
+		}, reportTimeout)
+
+		log.Printf("Task %s completed successfully. Loss: %.4f, Accuracy: %.4f",
 			req.TaskId, loss, accuracy)
+		tlog.printf("INFO", "Task completed in %.1fs: loss %.4f, accuracy %.4f", duration, loss, accuracy)
 
 		return &workerpb.TaskResponse{
 			TaskId:       req.TaskId,
@@ -144,153 +426,482 @@ func (ws *WorkerServer) ExecuteTask(ctx context.Context, req *workerpb.TaskReque
 			Message:      "Task completed successfully",
 			Loss:         loss,
 			Accuracy:     accuracy,
-			ModelWeights: []byte{},
+			ModelWeights: weights,
+		}, nil
+	}
+
+	switch cause := context.Cause(ctx); {
+	case errors.Is(cause, errWorkerShuttingDown):
+		// Stopped by shutdown rather than failed; another worker can
+		// run it
+		tlog.printf("WARN", "Task stopped and released: worker shutting down")
+		ws.releaseTask(req, "worker shutting down")
+		return &workerpb.TaskResponse{
+			TaskId:  req.TaskId,
+			Success: false,
+			Message: "Task released - worker is shutting down",
+		}, nil
+	case errors.Is(cause, errWorkerPreempted):
+		// Its checkpoint stays for the next attempt should the task come
+		// back here; until then another worker can run it
+		tlog.printf("WARN", "Task stopped and released: worker preempted")
+		ws.releaseTask(req, "worker preempted")
+		return &workerpb.TaskResponse{
+			TaskId:  req.TaskId,
+			Success: false,
+			Message: "Task released - worker is preempted",
+		}, nil
+	case errors.Is(cause, errTaskCancelled):
+		// The orchestrator has already settled the task; nothing to report
+		tasksCancelled.WithLabelValues(job).Inc()
+		log.Printf("Task %s stopped: %v", req.TaskId, cause)
+		tlog.printf("WARN", "Task stopped: %v", cause)
+		return &workerpb.TaskResponse{
+			TaskId:  req.TaskId,
+			Success: false,
+			Message: fmt.Sprintf("Task stopped - %v", cause),
 		}, nil
 	}
 
-	tasksFailed.Inc()
+	tasksFailed.WithLabelValues(job).Inc()
+	log.Printf("Task %s failed: %v", req.TaskId, err)
+	tlog.printf("ERROR", "Task failed: %v", err)
+	ws.reports.submit(&orchestratorpb.TaskCompletionRequest{
+		TaskId:        req.TaskId,
+		JobId:         req.JobId,
+		Attempt:       req.Attempt,
+		WorkerId:      ws.workerID,
+		Success:       false,
+		ErrorMessage:  err.Error(),
+		DatasetCache:  datasetCached,
+		ResourceUsage: usage,
+	}, 10*time.Second)
 	return &workerpb.TaskResponse{
 		TaskId:  req.TaskId,
 		Success: false,
-		Message: "Task failed during training",
+		Message: fmt.Sprintf("Task failed during training: %v", err),
 	}, nil
 }
 
-func (ws *WorkerServer) simulateTraining(ctx context.Context, req *workerpb.TaskRequest) (bool, float64, float64) {
-	// Simulate ML training with periodic status checks
-	totalDuration := time.Duration(rand.Intn(3000)+1000) * time.Millisecond
-	checkInterval := 500 * time.Millisecond
-	elapsed := time.Duration(0)
-	
-	// Check job status periodically during training
-	for elapsed < totalDuration {
-		sleepTime := checkInterval
-		if totalDuration-elapsed < checkInterval {
-			sleepTime = totalDuration - elapsed
+// train runs a task on the executor for its type. When the worker manages
+// GPUs and the executor runs tasks locally, the task is pinned to GPUs of
+// its own, and waits for them if other tasks hold them.
+func (ws *WorkerServer) train(ctx context.Context, req *workerpb.TaskRequest, progress func(Progress), usage func(ResourceUsage), tlog taskLogger) (Result, orchestratorpb.DatasetCacheResult, error) {
+	spec := taskSpecFromRequest(req)
+	spec.Progress = progress
+	spec.Usage = usage
+	spec.Log = tlog.line
+	executor, backend := ws.executorFor(spec.TaskType), ws.backendFor(spec.TaskType)
+	cached := orchestratorpb.DatasetCacheResult_DATASET_CACHE_UNSPECIFIED
+	if ws.datasets != nil && req.DatasetPath != "" && readsLocalDatasets(backend) {
+		file, hit, release, err := ws.datasets.acquire(ctx, req.DatasetPath)
+		if err != nil {
+			return Result{}, cached, err
 		}
-		
-		time.Sleep(sleepTime)
-		elapsed += sleepTime
-		
-		// Check if job was cancelled during training
-		if cancelled, err := ws.isJobCancelled(ctx, req.JobId); err == nil && cancelled {
-			log.Printf("Training interrupted - job %s was cancelled", req.JobId)
-			return false, 0, 0
+		defer release()
+		spec.DatasetFile = file
+		cached = orchestratorpb.DatasetCacheResult_DATASET_CACHE_MISS
+		recordDatasetLookup(req.JobId, hit)
+		if hit {
+			cached = orchestratorpb.DatasetCacheResult_DATASET_CACHE_HIT
+			tlog.printf("INFO", "Dataset %s found in the local cache", req.DatasetPath)
+		} else {
+			tlog.printf("INFO", "Dataset %s downloaded to the local cache", req.DatasetPath)
 		}
 	}
+	if ws.gpus.count() > 0 && usesLocalGPUs(backend) {
+		devices, err := ws.gpus.allocate(ctx, req.TaskId, int(req.GpuCount))
+		if err != nil {
+			return Result{}, cached, err
+		}
+		defer ws.gpus.release(devices)
+		spec.GPUs = gpuUUIDs(devices)
+	}
+	if ws.checkpoints != nil && usesWorkspaces(backend) {
+		dir, resumed, err := ws.checkpoints.open(spec)
+		if err != nil {
+			log.Printf("Running task %s without a checkpoint: %v", req.TaskId, err)
+		} else {
+			spec.CheckpointDir, spec.Resumed = dir, resumed
+			spec.CheckpointInterval = ws.checkpointInterval
+			if resumed {
+				tasksResumed.WithLabelValues(jobLabel(req.JobId)).Inc()
+				log.Printf("Resuming task %s from its local checkpoint", req.TaskId)
+				tlog.printf("INFO", "Resuming from the checkpoint of an interrupted attempt")
+			}
+		}
+	}
+	if usesWorkspaces(backend) {
+		dir, err := newWorkspace(ws.workspaceDir, spec)
+		if err != nil {
+			return Result{}, cached, err
+		}
+		defer os.RemoveAll(dir)
+		spec.Workspace = dir
+	}
 
-	// Simulate convergence: loss decreases, accuracy increases over epochs
-	baseLoss := 2.5
-	baseAccuracy := 0.1
-	
-	loss := baseLoss / (1 + float64(req.Epoch)*0.2) + (rand.Float64()-0.5)*0.1
-	accuracy := baseAccuracy + float64(req.Epoch)*0.08 + (rand.Float64()-0.5)*0.02
-
-	if loss < 0 {
-		loss = 0.01
+	result, err := executor.Run(ctx, spec)
+	// An interrupted attempt leaves its checkpoint for the next one; a task
+	// that completed or failed outright starts afresh if it comes back
+	if spec.CheckpointDir != "" && ctx.Err() == nil {
+		ws.checkpoints.remove(req.TaskId)
 	}
-	if accuracy > 1.0 {
-		accuracy = 0.99
+	if err == nil && spec.Workspace != "" && len(result.Artifacts) > 0 {
+		// Uploaded even if the task is being stopped now that it is done
+		ws.collectArtifacts(context.Background(), spec, result.Artifacts, tlog)
 	}
+	return result, cached, err
+}
+
+// jobCheckInterval is how often a running task checks whether its job was
+// cancelled.
+const jobCheckInterval = 500 * time.Millisecond
+
+// watchJob cancels a running task once its job is cancelled or failed, so
+// executors only need to honour their context.
+func (ws *WorkerServer) watchJob(ctx context.Context, jobID string, cancel context.CancelCauseFunc) {
+	ticker := time.NewTicker(jobCheckInterval)
+	defer ticker.Stop()
 
-	return true, loss, accuracy
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if cancelled, err := ws.isJobCancelled(ctx, jobID); err == nil && cancelled {
+				log.Printf("Training interrupted - job %s was cancelled", jobID)
+				cancel(fmt.Errorf("%w: job was cancelled", errTaskCancelled))
+				return
+			}
+		}
+	}
 }
 
 func (ws *WorkerServer) GetWorkerStatus(ctx context.Context, req *workerpb.WorkerStatusRequest) (*workerpb.WorkerStatusResponse, error) {
+	ws.runningMu.Lock()
+	currentTasks, completedTasks := len(ws.running), ws.completedTasks
+	ws.runningMu.Unlock()
+	host := ws.resources.stats()
+
 	return &workerpb.WorkerStatusResponse{
-		WorkerId:       ws.workerID,
-		Status:         "ACTIVE",
-		CurrentTasks:   int32(ws.currentTasks),
-		CompletedTasks: int32(ws.completedTasks),
-		CpuUsage:       rand.Float64() * 100,
-		MemoryUsage:    rand.Float64() * 100,
+		WorkerId:              ws.workerID,
+		Status:                "ACTIVE",
+		CurrentTasks:          int32(currentTasks),
+		CompletedTasks:        int32(completedTasks),
+		MaxConcurrentTasks:    int32(ws.pool.size()),
+		CpuUsage:              host.CPUPercent,
+		MemoryUsage:           host.MemoryPercent,
+		MemoryUsedBytes:       host.MemoryUsedBytes,
+		DiskUsage:             host.DiskPercent,
+		LoadAverage:           host.LoadAverage,
+		NetSentBytesPerSecond: host.NetSentPerSec,
+		NetRecvBytesPerSecond: host.NetRecvPerSec,
 	}, nil
 }
 
+// CancelTask stops a running task and waits, up to cancelWaitTimeout, for
+// it to wind down. The task ends without a report to the orchestrator,
+// which has already settled it.
 func (ws *WorkerServer) CancelTask(ctx context.Context, req *workerpb.CancelTaskRequest) (*workerpb.CancelTaskResponse, error) {
+	ws.runningMu.Lock()
+	task, ok := ws.running[req.TaskId]
+	ws.runningMu.Unlock()
+
+	if !ok {
+		return &workerpb.CancelTaskResponse{
+			Success: false,
+			Message: fmt.Sprintf("Task %s is not running", req.TaskId),
+		}, nil
+	}
+
+	reason := req.Reason
+	if reason == "" {
+		reason = "cancelled by request"
+	}
+	log.Printf("Cancelling task %s: %s", req.TaskId, reason)
+	task.cancel(fmt.Errorf("%w: %s", errTaskCancelled, reason))
+
+	timer := time.NewTimer(cancelWaitTimeout)
+	defer timer.Stop()
+	select {
+	case <-task.done:
+		return &workerpb.CancelTaskResponse{
+			Success: true,
+			Stopped: true,
+			Message: fmt.Sprintf("Task %s stopped", req.TaskId),
+		}, nil
+	case <-timer.C:
+	case <-ctx.Done():
+	}
 	return &workerpb.CancelTaskResponse{
 		Success: true,
-		Message: fmt.Sprintf("Task %s cancelled", req.TaskId),
+		Message: fmt.Sprintf("Task %s is stopping", req.TaskId),
 	}, nil
 }
 
-func (ws *WorkerServer) startTaskFetcher(ctx context.Context) {
-	ticker := time.NewTicker(5 * time.Second)
-	defer ticker.Stop()
+// setServingStatus reports the worker's health for the server as a whole and
+// for the worker service.
+func (ws *WorkerServer) setServingStatus(status healthpb.HealthCheckResponse_ServingStatus) {
+	ws.health.SetServingStatus("", status)
+	ws.health.SetServingStatus(workerpb.WorkerService_ServiceDesc.ServiceName, status)
+}
+
+// register tells the orchestrator where to reach the worker and how many
+// tasks it runs at once.
+func (ws *WorkerServer) register(ctx context.Context) error {
+	hostname, _ := os.Hostname()
+	address := os.Getenv("WORKER_ADVERTISE_ADDR")
+	if address == "" {
+		address = hostname + ":" + listenPort()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	resp, err := ws.orchestratorClient.RegisterWorker(ctx, &orchestratorpb.RegisterWorkerRequest{
+		WorkerId:           ws.workerID,
+		Hostname:           hostname,
+		Address:            address,
+		Capabilities:       ws.capabilities(),
+		MaxConcurrentTasks: int32(ws.pool.size()),
+		StartedAtMs:        ws.startedAt.UnixMilli(),
+		CachedDatasets:     ws.cachedDatasets(),
+		Executor:           ws.executorName,
+		PrefetchTasks:      int32(prefetchTasksFromEnv()),
+	})
+	if err != nil {
+		return err
+	}
+	if !resp.Success {
+		return fmt.Errorf("registration rejected: %s", resp.Message)
+	}
+	ws.probes.contacted()
+	session.set(ws.workerID, resp.SessionToken)
+	ws.heartbeatSeconds.Store(resp.HeartbeatIntervalSeconds)
+	ws.prefetchTasks.Store(resp.PrefetchTasks)
+	log.Printf("Registered with orchestrator as %s (%s, %s executor, up to %d tasks at once plus %d prefetched, %d GPUs, labels %v)",
+		ws.workerID, address, ws.executorName, ws.pool.size(), resp.PrefetchTasks, ws.gpus.count(), ws.labels)
+	return nil
+}
 
+// taskStreamHealthyAfter is how long a task stream must stay open to count
+// as working, so that the next reconnect starts from the shortest delay.
+const taskStreamHealthyAfter = time.Minute
+
+// startTaskStream keeps a StreamTasks stream open to the orchestrator and
+// runs the tasks it pushes as pool slots free up. The orchestrator holds
+// each request until it has work, so an idle worker makes no calls. The
+// worker registers before each stream; a broken stream is reopened after a
+// jittered backoff that grows while the orchestrator keeps failing or
+// refusing it, e.g. while it is down or the worker is quarantined.
+func (ws *WorkerServer) startTaskStream(ctx context.Context) {
+	retry := newBackoff(time.Second, time.Duration(ws.maxReconnectDelay.Load()))
 	for {
+		retry.max = time.Duration(ws.maxReconnectDelay.Load())
+		opened := time.Now()
+		if err := ws.register(ctx); err != nil {
+			log.Printf("Failed to register with orchestrator: %v", err)
+		} else if err := ws.streamTasks(ctx, retry); err != nil {
+			log.Printf("Task stream closed: %v", err)
+		}
+		ws.setServingStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+		if time.Since(opened) > taskStreamHealthyAfter {
+			retry.reset()
+		}
+
+		delay := retry.next()
+		log.Printf("Reconnecting to orchestrator in %s", delay.Round(time.Millisecond))
 		select {
 		case <-ctx.Done():
 			return
-		case <-ticker.C:
-			ws.fetchAndExecuteTask(ctx)
+		case <-time.After(delay):
 		}
 	}
 }
 
-func (ws *WorkerServer) fetchAndExecuteTask(ctx context.Context) {
-	taskCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
-	defer cancel()
-
-	resp, err := ws.orchestratorClient.AssignTask(taskCtx, &orchestratorpb.AssignTaskRequest{
-		WorkerId: ws.workerID,
-	})
-
+// streamTasks runs one task stream until it breaks. Each task received
+// resets the reconnect backoff.
+func (ws *WorkerServer) streamTasks(ctx context.Context, retry *backoff) error {
+	stream, err := ws.orchestratorClient.StreamTasks(ctx)
 	if err != nil {
-		// No tasks available or error
-		return
+		return err
 	}
+	defer stream.CloseSend()
+
+	for {
+		// Ask for the next task once the host has room for it and a slot
+		// is free, or while the pool is full if the orchestrator allows a
+		// task to be held for it
+		if err := ws.throttle.wait(ctx); err != nil {
+			return err
+		}
+		if err := ws.pool.reserve(ctx, int(ws.prefetchTasks.Load())); err != nil {
+			return err
+		}
+		if err := stream.Send(&orchestratorpb.TaskStreamRequest{WorkerId: ws.workerID}); err != nil {
+			ws.pool.unreserve()
+			return err
+		}
+		ws.setServingStatus(healthpb.HealthCheckResponse_SERVING)
+		asked := time.Now()
 
-	// Execute task
-	taskReq := &workerpb.TaskRequest{
-		TaskId:          resp.TaskId,
-		JobId:           resp.JobId,
-		ModelType:       resp.ModelType,
-		DatasetPath:     resp.DatasetPath,
-		Hyperparameters: resp.Hyperparameters,
-		Epoch:           resp.Epoch,
-		BatchStart:      resp.BatchStart,
-		BatchEnd:        resp.BatchEnd,
+		resp, err := stream.Recv()
+		if err != nil {
+			ws.pool.unreserve()
+			return err
+		}
+		ws.probes.contacted()
+		retry.reset()
+		observeAssignment(resp, asked)
+
+		go func() {
+			req := &workerpb.TaskRequest{
+				TaskId:          resp.TaskId,
+				JobId:           resp.JobId,
+				ModelType:       resp.ModelType,
+				DatasetPath:     resp.DatasetPath,
+				Hyperparameters: resp.Hyperparameters,
+				Epoch:           resp.Epoch,
+				BatchStart:      resp.BatchStart,
+				BatchEnd:        resp.BatchEnd,
+				ModelWeights:    resp.ModelWeights,
+				TaskType:        resp.TaskType,
+				Attempt:         resp.Attempt,
+				Image:           resp.Image,
+				GpuCount:        resp.GpuCount,
+				CpuCores:        resp.CpuCores,
+				MemoryMb:        resp.MemoryMb,
+			}
+			if !ws.startPrefetched(ctx, req) {
+				return
+			}
+			defer ws.pool.release()
+			ws.runTask(context.Background(), req)
+		}()
 	}
+}
 
-	go ws.ExecuteTask(context.Background(), taskReq)
+// startPrefetched takes a pool slot for a task received from the stream,
+// waiting for one if the task was prefetched. A task that waited restarts
+// its lease at the orchestrator, which may have reclaimed it meanwhile. With
+// a prefetch allowance every task reports its start: the orchestrator
+// cannot tell which tasks were held, and keeps renewing their leases until
+// they start. It reports whether the task should run; if not, it holds no
+// slot.
+func (ws *WorkerServer) startPrefetched(ctx context.Context, req *workerpb.TaskRequest) bool {
+	waited, err := ws.pool.claim(ctx)
+	if err != nil {
+		// The stream closed, e.g. for shutdown, before a slot came free
+		ws.releaseTask(req, "worker stopped before starting prefetched task")
+		return false
+	}
+	if !waited && ws.prefetchTasks.Load() == 0 {
+		return true
+	}
+	reqCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	resp, err := ws.orchestratorClient.ReportTaskProgress(reqCtx, &orchestratorpb.TaskProgressRequest{
+		TaskId:   req.TaskId,
+		JobId:    req.JobId,
+		WorkerId: ws.workerID,
+		Started:  true,
+	})
+	switch {
+	case err != nil:
+		// Run it anyway: the lease may still hold, and a completion for a
+		// reclaimed task is rejected
+		log.Printf("Failed to report start of prefetched task %s: %v", req.TaskId, err)
+	case resp.Stop:
+		log.Printf("Dropping prefetched task %s: %s", req.TaskId, resp.Message)
+		ws.pool.release()
+		return false
+	}
+	return true
 }
 
 func main() {
+	config, err := loadWorkerConfig(workerConfigPathFromEnv())
+	if err != nil {
+		log.Fatalf("Failed to load configuration: %v", err)
+	}
 	worker, err := NewWorkerServer()
 	if err != nil {
 		log.Fatalf("Failed to create worker: %v", err)
 	}
 
-	// Start Prometheus metrics server
+	// Start Prometheus metrics server, which also serves the health probes
+	// and profiles. It has a mux of its own: importing net/http/pprof
+	// serves the profiles on the default one, without the worker token
 	go func() {
-		http.Handle("/metrics", promhttp.Handler())
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promhttp.Handler())
+		worker.registerHealthHandlers(mux)
+		worker.registerPreemptionHandler(mux)
+		registerPprofHandlers(mux)
 		log.Println("Metrics server listening on :2112")
-		if err := http.ListenAndServe(":2112", nil); err != nil {
+		if err := http.ListenAndServe(":2112", mux); err != nil {
 			log.Printf("Metrics server error: %v", err)
 		}
 	}()
 
-	// Start task fetcher
-	ctx := context.Background()
-	go worker.startTaskFetcher(ctx)
+	// Receive tasks until SIGTERM or SIGINT. Heartbeats carry on while
+	// running tasks finish, so the worker is not taken for dead, and stop
+	// before it deregisters, so it does not register again
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+	// Stop the same way once the instance is about to be preempted
+	ctx, stopPreempted := context.WithCancel(ctx)
+	defer stopPreempted()
+	go func() {
+		select {
+		case <-worker.preempted:
+			stopPreempted()
+		case <-ctx.Done():
+		}
+	}()
+	heartbeats, stopHeartbeats := context.WithCancel(context.Background())
+	go worker.startTaskStream(ctx)
+	go worker.resources.run(context.Background())
+	go worker.watchLoad(context.Background())
+	go worker.heartbeatLoop(heartbeats)
+	go worker.reports.run(context.Background())
+	go worker.logs.run(context.Background())
+	go worker.checkpoints.run(context.Background())
+	go worker.probes.checkExecutor(context.Background(), worker.executor)
+	go worker.watchConfig(ctx, config)
 
 	// Start gRPC server
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "50052"
-	}
+	port := listenPort()
 
 	lis, err := net.Listen("tcp", ":"+port)
 	if err != nil {
 		log.Fatalf("Failed to listen: %v", err)
 	}
 
-	grpcServer := grpc.NewServer()
+	grpcServer := grpc.NewServer(serverInterceptors()...)
 	workerpb.RegisterWorkerServiceServer(grpcServer, worker)
+	healthpb.RegisterHealthServer(grpcServer, worker.health)
 
 	log.Printf("Worker %s listening on port %s", worker.workerID, port)
-	if err := grpcServer.Serve(lis); err != nil {
-		log.Fatalf("Failed to serve: %v", err)
+	go func() {
+		if err := grpcServer.Serve(lis); err != nil {
+			log.Fatalf("Failed to serve: %v", err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Printf("Shutting down; no longer taking tasks")
+	// A preempted worker has already stopped its tasks and only waits for
+	// them to be released
+	timeout, reason := shutdownTimeoutFromEnv(), "worker shutting down"
+	select {
+	case <-worker.preempted:
+		timeout, reason = 0, "worker preempted"
+	default:
 	}
+	worker.shutdown(timeout)
+	stopHeartbeats()
+	worker.deregister(reason)
+	grpcServer.Stop()
+	log.Printf("Worker %s stopped", worker.workerID)
 }
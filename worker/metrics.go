@@ -0,0 +1,53 @@
+package main
+
+import (
+	"os"
+	"time"
+
+	orchestratorpb "github.com/tensorfleet/worker/proto/orchestrator"
+)
+
+// metricsJobLabels is whether task metrics are labelled with their job, so
+// they can be graphed per job. Each job adds series for as long as the
+// worker runs; WORKER_METRICS_JOB_LABELS=false leaves the label empty on
+// workers that see very many jobs.
+var metricsJobLabels = os.Getenv("WORKER_METRICS_JOB_LABELS") != "false"
+
+// jobLabel is the job_id label value of a task's metrics.
+func jobLabel(jobID string) string {
+	if !metricsJobLabels {
+		return ""
+	}
+	return jobID
+}
+
+// observeAssignment records how long a task streamed to the worker waited:
+// in the orchestrator's queue, and for the worker since it asked for a task.
+func observeAssignment(resp *orchestratorpb.AssignTaskResponse, asked time.Time) {
+	job := jobLabel(resp.JobId)
+	taskAssignmentLatency.WithLabelValues(job).Observe(time.Since(asked).Seconds())
+	if resp.QueuedAtMs > 0 {
+		// Clocks of the worker and orchestrator may disagree a little
+		wait := time.Since(time.UnixMilli(resp.QueuedAtMs))
+		if wait < 0 {
+			wait = 0
+		}
+		taskQueueWait.WithLabelValues(job).Observe(wait.Seconds())
+	}
+}
+
+// updateInFlight sets the in-flight gauge of a job from the tasks running,
+// dropping the series once none are left. The caller holds runningMu.
+func (ws *WorkerServer) updateInFlight(jobID string) {
+	n := 0
+	for _, task := range ws.running {
+		if jobLabel(task.jobID) == jobLabel(jobID) {
+			n++
+		}
+	}
+	if n == 0 {
+		tasksInFlight.DeleteLabelValues(jobLabel(jobID))
+		return
+	}
+	tasksInFlight.WithLabelValues(jobLabel(jobID)).Set(float64(n))
+}
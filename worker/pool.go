@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// taskPool bounds how many tasks the worker runs at once, whether they come
+// over the task stream or in ExecuteTask calls. Each running task holds one
+// slot. The number of slots can change while tasks hold them.
+//
+// Tasks from the stream are reserved before they are asked for and claim a
+// slot once they arrive. A reservation may go beyond the free slots by the
+// prefetch allowance, so the next task is on hand when a running one ends.
+type taskPool struct {
+	mu     sync.Mutex
+	limit  int
+	used   int
+	queued int           // Reserved and not yet holding a slot
+	freed  chan struct{} // Closed when a slot may have come free
+}
+
+func newTaskPool(size int) *taskPool {
+	return &taskPool{limit: size, freed: make(chan struct{})}
+}
+
+// prefetchTasksFromEnv reads WORKER_PREFETCH_TASKS, how many tasks the
+// worker asks to hold while its pool is full. None by default; the
+// orchestrator may grant fewer.
+func prefetchTasksFromEnv() int {
+	n, err := strconv.Atoi(os.Getenv("WORKER_PREFETCH_TASKS"))
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// maxConcurrentTasksFromEnv reads WORKER_MAX_CONCURRENT_TASKS, or the older
+// MAX_CONCURRENT_TASKS, defaulting to one task at a time.
+func maxConcurrentTasksFromEnv() int {
+	raw := os.Getenv("WORKER_MAX_CONCURRENT_TASKS")
+	if raw == "" {
+		raw = os.Getenv("MAX_CONCURRENT_TASKS")
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n < 1 {
+		return 1
+	}
+	return n
+}
+
+// acquire waits for a free slot. It fails only if ctx is done first.
+func (p *taskPool) acquire(ctx context.Context) error {
+	for {
+		p.mu.Lock()
+		freed := p.freed
+		p.mu.Unlock()
+		if p.tryAcquire() {
+			return nil
+		}
+		select {
+		case <-freed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// tryAcquire takes a free slot if there is one.
+func (p *taskPool) tryAcquire() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.used >= p.limit {
+		return false
+	}
+	p.used++
+	tasksRunning.Inc()
+	return true
+}
+
+// reserve waits until fewer than the pool's slots plus extra are taken or
+// reserved, and reserves one for a task about to be asked for. It fails
+// only if ctx is done first.
+func (p *taskPool) reserve(ctx context.Context, extra int) error {
+	for {
+		p.mu.Lock()
+		if p.used+p.queued < p.limit+extra {
+			p.queued++
+			tasksPrefetched.Set(float64(p.queued))
+			p.mu.Unlock()
+			return nil
+		}
+		freed := p.freed
+		p.mu.Unlock()
+		select {
+		case <-freed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// unreserve gives up a reservation that will not be claimed.
+func (p *taskPool) unreserve() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.queued--
+	tasksPrefetched.Set(float64(p.queued))
+	p.wake()
+}
+
+// claim turns a reservation into a slot, waiting for one to come free. It
+// reports whether it had to wait, i.e. whether the task was prefetched. If
+// ctx is done first the reservation is given up.
+func (p *taskPool) claim(ctx context.Context) (bool, error) {
+	waited := false
+	for {
+		p.mu.Lock()
+		if p.used < p.limit {
+			p.queued--
+			p.used++
+			tasksPrefetched.Set(float64(p.queued))
+			tasksRunning.Inc()
+			p.mu.Unlock()
+			return waited, nil
+		}
+		freed := p.freed
+		p.mu.Unlock()
+		waited = true
+		select {
+		case <-freed:
+		case <-ctx.Done():
+			p.unreserve()
+			return waited, ctx.Err()
+		}
+	}
+}
+
+// release frees a slot taken by acquire, tryAcquire or claim.
+func (p *taskPool) release() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.used--
+	tasksRunning.Dec()
+	p.wake()
+}
+
+// resize changes how many tasks may run at once. Shrinking the pool stops
+// no task; new ones wait until fewer than size are running.
+func (p *taskPool) resize(size int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.limit = size
+	p.wake()
+}
+
+// wake lets waiting acquires look for a slot again. The caller holds mu.
+func (p *taskPool) wake() {
+	close(p.freed)
+	p.freed = make(chan struct{})
+}
+
+// size is how many tasks may run at once.
+func (p *taskPool) size() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.limit
+}
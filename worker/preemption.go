@@ -0,0 +1,109 @@
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// preemptibleLabel marks a worker running on a spot or preemptible
+// instance, which its provider may reclaim at short notice. The
+// orchestrator keeps synchronous gang jobs from running on such workers
+// alone.
+const preemptibleLabel = "preemptible"
+
+// preemptibleFromEnv reads WORKER_PREEMPTIBLE, set on spot and preemptible
+// instances.
+func preemptibleFromEnv() bool {
+	preemptible, _ := strconv.ParseBool(os.Getenv("WORKER_PREEMPTIBLE"))
+	return preemptible
+}
+
+// preemptibleCheckpointIntervalFromEnv reads
+// WORKER_PREEMPTIBLE_CHECKPOINT_INTERVAL, how often tasks on a preemptible
+// worker are asked to checkpoint, so that little work is lost when the
+// instance is reclaimed.
+func preemptibleCheckpointIntervalFromEnv() time.Duration {
+	d, err := time.ParseDuration(os.Getenv("WORKER_PREEMPTIBLE_CHECKPOINT_INTERVAL"))
+	if err != nil || d <= 0 {
+		return 30 * time.Second
+	}
+	return d
+}
+
+// preemptionNotice is the body a node termination handler may post to
+// /preemption-notice; both fields are optional.
+type preemptionNotice struct {
+	Reason string `json:"reason"`
+	// When the instance will be reclaimed, e.g. from the provider's
+	// metadata service
+	TerminationTime time.Time `json:"termination_time"`
+}
+
+// registerPreemptionHandler serves /preemption-notice on mux on preemptible
+// workers. A POST tells the worker its instance is about to be reclaimed:
+// it stops taking tasks, stops those it runs, which keep their checkpoints
+// and are released back to the orchestrator, and shuts down. With
+// WORKER_AUTH_TOKEN set the notice needs it as a bearer token.
+func (ws *WorkerServer) registerPreemptionHandler(mux *http.ServeMux) {
+	if !preemptibleFromEnv() {
+		return
+	}
+	mux.Handle("/preemption-notice", requireWorkerToken(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		var notice preemptionNotice
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&notice); err != nil {
+				http.Error(w, "invalid notice: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+		stopped := ws.preempt(notice)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"worker_id":     ws.workerID,
+			"stopped_tasks": stopped,
+		})
+	})))
+}
+
+// preempt acts on a preemption notice: no new task is started, and those
+// running are stopped to be released. It returns how many were stopped;
+// repeated notices stop nothing more.
+func (ws *WorkerServer) preempt(notice preemptionNotice) int {
+	reason := "instance preempted"
+	if notice.Reason != "" {
+		reason = notice.Reason
+	}
+
+	ws.runningMu.Lock()
+	defer ws.runningMu.Unlock()
+	select {
+	case <-ws.preempted:
+		return 0
+	default:
+	}
+	close(ws.preempted)
+	ws.shuttingDown = true
+	if notice.TerminationTime.IsZero() {
+		log.Printf("Preemption notice (%s); releasing %d running task(s)", reason, len(ws.running))
+	} else {
+		log.Printf("Preemption notice (%s), termination in %s; releasing %d running task(s)",
+			reason, time.Until(notice.TerminationTime).Round(time.Second), len(ws.running))
+	}
+	for _, task := range ws.running {
+		task.cancel(errWorkerPreempted)
+	}
+	ws.setServingStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+	return len(ws.running)
+}
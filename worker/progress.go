@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	orchestratorpb "github.com/tensorfleet/worker/proto/orchestrator"
+	workerpb "github.com/tensorfleet/worker/proto/worker"
+)
+
+// Progress is how far a running task has got, with metrics over the batches
+// done so far.
+type Progress struct {
+	BatchesDone  int32   `json:"batches_done"`
+	BatchesTotal int32   `json:"batches_total"`
+	Loss         float64 `json:"loss"`
+	Accuracy     float64 `json:"accuracy"`
+}
+
+// reportProgress passes progress on to the worker, if it is listening.
+func (spec TaskSpec) reportProgress(p Progress) {
+	if spec.Progress != nil {
+		spec.Progress(p)
+	}
+}
+
+// progressIntervalFromEnv reads WORKER_PROGRESS_INTERVAL, the least time
+// between progress reports for one task. Zero stops reporting.
+func progressIntervalFromEnv() time.Duration {
+	d, err := time.ParseDuration(os.Getenv("WORKER_PROGRESS_INTERVAL"))
+	if err != nil || d < 0 {
+		return 5 * time.Second
+	}
+	return d
+}
+
+// progressReporter forwards a task's progress to the orchestrator. Executors
+// may report every batch; only the latest progress is sent, at most once per
+// interval, so a slow orchestrator never holds training up.
+type progressReporter struct {
+	ws     *WorkerServer
+	req    *workerpb.TaskRequest
+	cancel context.CancelCauseFunc
+
+	mu      sync.Mutex
+	latest  Progress
+	pending bool
+}
+
+func (ws *WorkerServer) newProgressReporter(req *workerpb.TaskRequest, cancel context.CancelCauseFunc) *progressReporter {
+	return &progressReporter{ws: ws, req: req, cancel: cancel}
+}
+
+// report records the task's latest progress.
+func (r *progressReporter) report(p Progress) {
+	r.mu.Lock()
+	r.latest, r.pending = p, true
+	r.mu.Unlock()
+}
+
+// run sends progress until ctx is done. A task the orchestrator no longer
+// assigns to the worker, e.g. one it gave up on and requeued, is stopped.
+func (r *progressReporter) run(ctx context.Context) {
+	if r.ws.progressInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(r.ws.progressInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		r.mu.Lock()
+		p, pending := r.latest, r.pending
+		r.pending = false
+		r.mu.Unlock()
+		if !pending {
+			continue
+		}
+
+		resp, err := r.send(ctx, p)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Printf("Failed to report progress of task %s: %v", r.req.TaskId, err)
+			}
+			continue
+		}
+		if resp.Stop {
+			log.Printf("Stopping task %s: %s", r.req.TaskId, resp.Message)
+			r.cancel(fmt.Errorf("%w: %s", errTaskCancelled, resp.Message))
+			return
+		}
+	}
+}
+
+func (r *progressReporter) send(ctx context.Context, p Progress) (*orchestratorpb.TaskProgressResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	return r.ws.orchestratorClient.ReportTaskProgress(ctx, &orchestratorpb.TaskProgressRequest{
+		TaskId:       r.req.TaskId,
+		JobId:        r.req.JobId,
+		WorkerId:     r.ws.workerID,
+		BatchesDone:  p.BatchesDone,
+		BatchesTotal: p.BatchesTotal,
+		Loss:         p.Loss,
+		Accuracy:     p.Accuracy,
+	})
+}
+
+// progressFilePollInterval is how often a training script's progress file
+// is read.
+const progressFilePollInterval = time.Second
+
+// watchProgressFile passes on the progress a training script writes to the
+// JSON file at path until ctx is done. The script rewrites the whole file
+// each time, so a read may catch it half-written; such reads are skipped.
+func watchProgressFile(ctx context.Context, path string, spec TaskSpec) {
+	ticker := time.NewTicker(progressFilePollInterval)
+	defer ticker.Stop()
+
+	var lastMod time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		info, err := os.Stat(path)
+		if err != nil || !info.ModTime().After(lastMod) {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var p Progress
+		if err := json.Unmarshal(data, &p); err != nil {
+			continue
+		}
+		lastMod = info.ModTime()
+		spec.reportProgress(p)
+	}
+}
@@ -0,0 +1,378 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	orchestratorpb "github.com/tensorfleet/worker/proto/orchestrator"
+)
+
+var (
+	reportsPending = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "worker_reports_pending",
+		Help: "Task reports waiting to be delivered to the orchestrator",
+	})
+	// reason is "retry" for a failure the report is retried after,
+	// "rejected" when the orchestrator did not accept it, "refused" when it
+	// refused it for good and "expired" when the worker gave up on it
+	reportFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "worker_report_failures_total",
+		Help: "Failed attempts to deliver task reports to the orchestrator",
+	}, []string{"job_id", "reason"})
+)
+
+func init() {
+	prometheus.MustRegister(reportsPending, reportFailures)
+}
+
+// reportSpoolDirFromEnv reads WORKER_REPORT_SPOOL_DIR, where undelivered
+// task reports are kept across restarts.
+func reportSpoolDirFromEnv() string {
+	if dir := os.Getenv("WORKER_REPORT_SPOOL_DIR"); dir != "" {
+		return dir
+	}
+	return "/var/lib/tensorfleet/reports"
+}
+
+// reportMaxAgeFromEnv reads WORKER_REPORT_MAX_AGE, how long the worker keeps
+// trying to deliver a task report before giving up on it.
+func reportMaxAgeFromEnv() time.Duration {
+	d, err := time.ParseDuration(os.Getenv("WORKER_REPORT_MAX_AGE"))
+	if err != nil || d <= 0 {
+		return 24 * time.Hour
+	}
+	return d
+}
+
+// reportQueue delivers task reports to the orchestrator. A report that
+// cannot be delivered straight away, e.g. while the orchestrator restarts,
+// is retried with backoff until it is accepted, refused for good or too
+// old. The spool directory is a journal of the reports not yet settled:
+// each is synced to disk before anything else is done with it, with the
+// task's weights as they came from training, so reports a crashed or
+// restarted worker never delivered are sent when it starts again and
+// finished work is not lost between training and reporting.
+type reportQueue struct {
+	client orchestratorpb.OrchestratorServiceClient
+	dir    string // "" when reports cannot be spooled
+	maxAge time.Duration
+
+	// uploadWeights uploads the weights of a journaled report that are too
+	// large to send inline and returns where they are, or nil if they stay
+	// inline. It runs before a report is first sent, also for reports
+	// recovered from the journal, and again before each retry until it
+	// succeeds
+	uploadWeights func(ctx context.Context, req *orchestratorpb.TaskCompletionRequest) (*orchestratorpb.ObjectRef, error)
+
+	mu      sync.Mutex
+	pending []*pendingReport
+	wake    chan struct{}
+
+	flushMu sync.Mutex // One flush at a time, so a report is sent once per round
+}
+
+// pendingReport is a task report not yet delivered.
+type pendingReport struct {
+	req      *orchestratorpb.TaskCompletionRequest
+	file     string // In the spool; "" if it could not be written
+	since    time.Time
+	prepared bool
+}
+
+func newReportQueue(client orchestratorpb.OrchestratorServiceClient, dir string, maxAge time.Duration) *reportQueue {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		log.Printf("Task reports will not survive restarts: %v", err)
+		dir = ""
+	}
+	return &reportQueue{client: client, dir: dir, maxAge: maxAge, wake: make(chan struct{}, 1)}
+}
+
+// workerIDFile keeps the worker's ID in the spool across restarts.
+const workerIDFile = "worker-id"
+
+// workerID returns the ID the worker registers under: the one kept in the
+// spool by an earlier run, so its journaled reports are replayed in the
+// name their tasks were leased to, or else a new one. A worker that cannot
+// spool gets a new ID on every start.
+func (q *reportQueue) workerID() string {
+	if q.dir != "" {
+		if data, err := os.ReadFile(filepath.Join(q.dir, workerIDFile)); err == nil {
+			if id := strings.TrimSpace(string(data)); id != "" {
+				return id
+			}
+		}
+	}
+	id := uuid.New().String()
+	if q.dir != "" {
+		if err := writeSynced(filepath.Join(q.dir, workerIDFile), []byte(id+"\n")); err != nil {
+			log.Printf("Worker ID will not survive restarts: %v", err)
+		}
+	}
+	return id
+}
+
+// recover queues the reports spooled by earlier runs of the worker. They
+// are sent in the current worker's name, which is the one they were
+// journaled under unless the spooled worker ID was lost: the orchestrator
+// only accepts a report from the worker its task is leased to.
+func (q *reportQueue) recover(workerID string) {
+	if q.dir == "" {
+		return
+	}
+	files, err := filepath.Glob(filepath.Join(q.dir, "*.pb"))
+	if err != nil {
+		return
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		info, statErr := os.Stat(file)
+		req := &orchestratorpb.TaskCompletionRequest{}
+		if err == nil {
+			err = proto.Unmarshal(data, req)
+		}
+		if err != nil || statErr != nil {
+			log.Printf("Discarding unreadable spooled report %s: %v", file, err)
+			os.Remove(file)
+			continue
+		}
+		req.WorkerId = workerID
+		q.pending = append(q.pending, &pendingReport{req: req, file: file, since: info.ModTime()})
+	}
+	sort.Slice(q.pending, func(i, j int) bool { return q.pending[i].since.Before(q.pending[j].since) })
+	reportsPending.Set(float64(len(q.pending)))
+	if len(q.pending) > 0 {
+		log.Printf("Recovered %d undelivered task reports", len(q.pending))
+		q.signal()
+	}
+}
+
+// submit delivers a report, trying once within timeout before leaving it to
+// the retry loop.
+func (q *reportQueue) submit(req *orchestratorpb.TaskCompletionRequest, timeout time.Duration) {
+	p := &pendingReport{req: req, since: time.Now()}
+	if q.dir != "" {
+		file := filepath.Join(q.dir, fmt.Sprintf("%s-%d.pb", req.TaskId, time.Now().UnixNano()))
+		if err := writeReport(file, req); err != nil {
+			log.Printf("Failed to spool report of task %s: %v", req.TaskId, err)
+		} else {
+			p.file = file
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if q.attempt(ctx, p) {
+		return
+	}
+	q.mu.Lock()
+	q.pending = append(q.pending, p)
+	reportsPending.Set(float64(len(q.pending)))
+	q.mu.Unlock()
+	q.signal()
+}
+
+// writeReport writes a report to file and syncs it, so that it survives a
+// crash of the worker or its host once writeReport returns. An existing
+// file is replaced whole or not at all.
+func writeReport(file string, req *orchestratorpb.TaskCompletionRequest) error {
+	data, err := proto.Marshal(req)
+	if err != nil {
+		return err
+	}
+	return writeSynced(file, data)
+}
+
+// writeSynced writes data to file and syncs it. An existing file is
+// replaced whole or not at all.
+func writeSynced(file string, data []byte) error {
+	tmp, err := os.Create(file + ".tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file+".tmp", file)
+	}
+	if err != nil {
+		os.Remove(file + ".tmp")
+		return err
+	}
+	// The rename is only durable once the directory is synced
+	if dir, err := os.Open(filepath.Dir(file)); err == nil {
+		dir.Sync()
+		dir.Close()
+	}
+	return nil
+}
+
+// ready readies a report for sending by uploading weights too large to
+// send inline. The journaled report is updated to reference them, so they
+// are not uploaded again after a restart. A failed upload is retried on the
+// report's next attempt.
+func (q *reportQueue) ready(ctx context.Context, p *pendingReport) error {
+	if p.prepared || q.uploadWeights == nil {
+		return nil
+	}
+	ref, err := q.uploadWeights(ctx, p.req)
+	if err != nil {
+		return fmt.Errorf("failed to upload weights: %v", err)
+	}
+	q.mu.Lock()
+	p.prepared = true
+	if ref != nil {
+		p.req.ModelWeights, p.req.ModelWeightsRef = nil, ref
+	}
+	q.mu.Unlock()
+	if ref != nil && p.file != "" {
+		if err := writeReport(p.file, p.req); err != nil {
+			log.Printf("Failed to update spooled report of task %s: %v", p.req.TaskId, err)
+		}
+	}
+	return nil
+}
+
+// completed returns a copy of a successful report of the task still waiting
+// to be delivered, e.g. one recovered from the journal after the worker
+// crashed, and reports whether there is one. Only a report of the given
+// attempt or an earlier one stands for it; reports spooled before attempts
+// were recorded count as earlier.
+func (q *reportQueue) completed(taskID string, attempt int32) (*orchestratorpb.TaskCompletionRequest, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, p := range q.pending {
+		if p.req.TaskId == taskID && p.req.Success && p.req.Attempt <= attempt {
+			return proto.Clone(p.req).(*orchestratorpb.TaskCompletionRequest), true
+		}
+	}
+	return nil, false
+}
+
+// attempt sends a report once. It reports whether the report is settled:
+// delivered, refused for good, or given up on as too old.
+func (q *reportQueue) attempt(ctx context.Context, p *pendingReport) bool {
+	var resp *orchestratorpb.TaskCompletionResponse
+	err := q.ready(ctx, p)
+	if err == nil {
+		resp, err = q.client.ReportTaskCompletion(ctx, p.req)
+	}
+	job := jobLabel(p.req.JobId)
+	switch {
+	case err == nil:
+		if !resp.Acknowledged {
+			log.Printf("Orchestrator did not accept report of task %s: %s", p.req.TaskId, resp.Message)
+			reportFailures.WithLabelValues(job, "rejected").Inc()
+		}
+	case permanentReportError(err):
+		log.Printf("Orchestrator refused report of task %s: %v", p.req.TaskId, err)
+		reportFailures.WithLabelValues(job, "refused").Inc()
+	case time.Since(p.since) > q.maxAge:
+		log.Printf("Giving up on report of task %s after %s: %v", p.req.TaskId, q.maxAge, err)
+		reportFailures.WithLabelValues(job, "expired").Inc()
+	default:
+		log.Printf("Failed to report task %s, will retry: %v", p.req.TaskId, err)
+		reportFailures.WithLabelValues(job, "retry").Inc()
+		return false
+	}
+	if p.file != "" {
+		os.Remove(p.file)
+	}
+	return true
+}
+
+// permanentReportError reports whether retrying a refused report is
+// pointless, e.g. because its job no longer exists.
+func permanentReportError(err error) bool {
+	switch status.Code(err) {
+	case codes.NotFound, codes.InvalidArgument, codes.PermissionDenied:
+		return true
+	}
+	return false
+}
+
+func (q *reportQueue) signal() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run retries pending reports until ctx is done, oldest first, backing off
+// while the orchestrator keeps failing them.
+func (q *reportQueue) run(ctx context.Context) {
+	retry := newBackoff(time.Second, time.Minute)
+	var timer <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.wake:
+		case <-timer:
+		}
+		timer = nil
+
+		if q.flush(ctx) {
+			retry.reset()
+			continue
+		}
+		timer = time.After(retry.next())
+	}
+}
+
+// flush tries each pending report once, stopping at the first that fails
+// since the orchestrator is then likely unreachable. It reports whether
+// none are left.
+func (q *reportQueue) flush(ctx context.Context) bool {
+	q.flushMu.Lock()
+	defer q.flushMu.Unlock()
+	for {
+		q.mu.Lock()
+		if len(q.pending) == 0 {
+			q.mu.Unlock()
+			return true
+		}
+		p := q.pending[0]
+		q.mu.Unlock()
+
+		attemptCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		settled := q.attempt(attemptCtx, p)
+		cancel()
+		if !settled {
+			return false
+		}
+
+		q.mu.Lock()
+		q.pending = q.pending[1:]
+		reportsPending.Set(float64(len(q.pending)))
+		q.mu.Unlock()
+	}
+}
+
+// pendingTasks lists the tasks whose reports are pending.
+func (q *reportQueue) pendingTasks() []string {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	ids := make([]string, len(q.pending))
+	for i, p := range q.pending {
+		ids[i] = p.req.TaskId
+	}
+	return ids
+}
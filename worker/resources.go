@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/shirou/gopsutil/v3/load"
+	"github.com/shirou/gopsutil/v3/mem"
+	psnet "github.com/shirou/gopsutil/v3/net"
+
+	orchestratorpb "github.com/tensorfleet/worker/proto/orchestrator"
+)
+
+// resourceSampleInterval is how often host resource use is sampled.
+const resourceSampleInterval = 5 * time.Second
+
+var (
+	hostCPUPercent = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "worker_cpu_usage_percent",
+		Help: "Host CPU use across all cores",
+	})
+	hostMemoryBytes = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "worker_memory_usage_bytes",
+		Help: "Host memory in use",
+	})
+	hostMemoryPercent = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "worker_memory_usage_percent",
+		Help: "Share of host memory in use",
+	})
+	hostDiskPercent = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "worker_disk_usage_percent",
+		Help: "Share of the worker's disk in use",
+	})
+	hostLoadAverage = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "worker_load_average_1m",
+		Help: "Host one-minute load average",
+	})
+	hostNetSentRate = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "worker_network_sent_bytes_per_second",
+		Help: "Bytes the host sent per second over the last sample",
+	})
+	hostNetRecvRate = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "worker_network_received_bytes_per_second",
+		Help: "Bytes the host received per second over the last sample",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(hostCPUPercent, hostMemoryBytes, hostMemoryPercent,
+		hostDiskPercent, hostLoadAverage, hostNetSentRate, hostNetRecvRate)
+}
+
+// hostStats is one sample of the host's resource use. Fields that could not
+// be read are zero.
+type hostStats struct {
+	CPUPercent       float64
+	MemoryPercent    float64
+	MemoryUsedBytes  uint64
+	MemoryTotalBytes uint64
+	DiskPercent      float64 // Of the file system holding WORKER_DISK_PATH
+	DiskUsedBytes    uint64
+	DiskTotalBytes   uint64
+	LoadAverage      float64 // One-minute
+	NetSentPerSec    float64
+	NetRecvPerSec    float64
+}
+
+func (h hostStats) toProto() *orchestratorpb.WorkerLoad {
+	return &orchestratorpb.WorkerLoad{
+		CpuPercent:            h.CPUPercent,
+		MemoryPercent:         h.MemoryPercent,
+		LoadAverage:           h.LoadAverage,
+		MemoryUsedBytes:       h.MemoryUsedBytes,
+		MemoryTotalBytes:      h.MemoryTotalBytes,
+		DiskPercent:           h.DiskPercent,
+		DiskUsedBytes:         h.DiskUsedBytes,
+		DiskTotalBytes:        h.DiskTotalBytes,
+		NetSentBytesPerSecond: h.NetSentPerSec,
+		NetRecvBytesPerSecond: h.NetRecvPerSec,
+	}
+}
+
+// resourceMonitor samples the host's resource use in the background and
+// keeps the latest sample for heartbeats, GetWorkerStatus and metrics.
+type resourceMonitor struct {
+	diskPath string
+
+	mu     sync.Mutex
+	latest hostStats
+
+	// Network counters at the previous sample, for rates
+	prevNetAt         time.Time
+	prevSent, prevRcv uint64
+}
+
+func newResourceMonitor() *resourceMonitor {
+	diskPath := os.Getenv("WORKER_DISK_PATH")
+	if diskPath == "" {
+		diskPath = "/"
+	}
+	return &resourceMonitor{diskPath: diskPath}
+}
+
+// run samples until ctx is cancelled.
+func (m *resourceMonitor) run(ctx context.Context) {
+	ticker := time.NewTicker(resourceSampleInterval)
+	defer ticker.Stop()
+	for {
+		m.sample()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// stats returns the latest sample.
+func (m *resourceMonitor) stats() hostStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.latest
+}
+
+func (m *resourceMonitor) sample() {
+	var h hostStats
+	// Use since the previous call
+	if pct, err := cpu.Percent(0, false); err == nil && len(pct) > 0 {
+		h.CPUPercent = pct[0]
+	}
+	if vm, err := mem.VirtualMemory(); err == nil {
+		h.MemoryPercent = vm.UsedPercent
+		h.MemoryUsedBytes = vm.Used
+		h.MemoryTotalBytes = vm.Total
+	}
+	if du, err := disk.Usage(m.diskPath); err == nil {
+		h.DiskPercent = du.UsedPercent
+		h.DiskUsedBytes = du.Used
+		h.DiskTotalBytes = du.Total
+	}
+	if avg, err := load.Avg(); err == nil {
+		h.LoadAverage = avg.Load1
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if counters, err := psnet.IOCounters(false); err == nil && len(counters) > 0 {
+		now := time.Now()
+		sent, rcv := counters[0].BytesSent, counters[0].BytesRecv
+		if !m.prevNetAt.IsZero() && sent >= m.prevSent && rcv >= m.prevRcv {
+			elapsed := now.Sub(m.prevNetAt).Seconds()
+			h.NetSentPerSec = float64(sent-m.prevSent) / elapsed
+			h.NetRecvPerSec = float64(rcv-m.prevRcv) / elapsed
+		}
+		m.prevNetAt, m.prevSent, m.prevRcv = now, sent, rcv
+	}
+	m.latest = h
+
+	hostCPUPercent.Set(h.CPUPercent)
+	hostMemoryBytes.Set(float64(h.MemoryUsedBytes))
+	hostMemoryPercent.Set(h.MemoryPercent)
+	hostDiskPercent.Set(h.DiskPercent)
+	hostLoadAverage.Set(h.LoadAverage)
+	hostNetSentRate.Set(h.NetSentPerSec)
+	hostNetRecvRate.Set(h.NetRecvPerSec)
+}
+
+// capabilities describes the host to the orchestrator at registration, so
+// that jobs with resource or label requirements are scheduled onto it.
+func (ws *WorkerServer) capabilities() *orchestratorpb.WorkerCapabilities {
+	caps := &orchestratorpb.WorkerCapabilities{
+		GpuCount: int32(ws.gpus.count()),
+		GpuType:  ws.gpus.gpuType(),
+		Labels:   ws.labels,
+
+		TaskTypes: ws.taskTypes,
+	}
+	if cores, err := cpu.Counts(true); err == nil {
+		caps.CpuCores = int32(cores)
+	}
+	if vm, err := mem.VirtualMemory(); err == nil {
+		caps.MemoryMb = int64(vm.Total / (1 << 20))
+	}
+	return caps
+}
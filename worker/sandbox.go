@@ -0,0 +1,280 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	orchestratorpb "github.com/tensorfleet/worker/proto/orchestrator"
+)
+
+var tasksOOMKilled = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "worker_tasks_oom_killed_total",
+	Help: "Tasks killed for exceeding their memory limit",
+}, []string{"job_id"})
+
+func init() {
+	prometheus.MustRegister(tasksOOMKilled)
+}
+
+// ResourceUsage is what a task used of the CPU and memory limits it ran
+// under. A zero limit means the task was not limited on that resource.
+type ResourceUsage struct {
+	CPULimitCores       float64
+	MemoryLimitBytes    int64
+	PeakMemoryBytes     int64
+	CPUSeconds          float64
+	CPUThrottledSeconds float64 // Time the task was held back by its CPU limit
+	OOMKilled           bool    // The task was killed for exceeding its memory limit
+}
+
+// reportUsage passes the task's resource usage on to the worker, if it is
+// listening. Executors that enforce limits call it once the task has ended.
+func (spec TaskSpec) reportUsage(u ResourceUsage) {
+	if spec.Usage != nil {
+		spec.Usage(u)
+	}
+}
+
+// taskLimits is the CPU and memory a task asked for, as ResourceUsage
+// limits.
+func taskLimits(spec TaskSpec) ResourceUsage {
+	return ResourceUsage{
+		CPULimitCores:    float64(spec.CPUCores),
+		MemoryLimitBytes: spec.MemoryMB << 20,
+	}
+}
+
+func (u ResourceUsage) toProto() *orchestratorpb.TaskResourceUsage {
+	return &orchestratorpb.TaskResourceUsage{
+		CpuLimitCores:       u.CPULimitCores,
+		MemoryLimitBytes:    u.MemoryLimitBytes,
+		PeakMemoryBytes:     u.PeakMemoryBytes,
+		CpuSeconds:          u.CPUSeconds,
+		CpuThrottledSeconds: u.CPUThrottledSeconds,
+		OomKilled:           u.OOMKilled,
+	}
+}
+
+// oomError fails a task whose script or container, named by what, was
+// killed for exceeding its memory limit.
+func (u ResourceUsage) oomError(what string) error {
+	switch {
+	case u.MemoryLimitBytes == 0:
+		return fmt.Errorf("%s was killed: it exceeded its memory limit", what)
+	case u.PeakMemoryBytes > 0:
+		return fmt.Errorf("%s was killed: it exceeded its memory limit of %d MiB (peak %d MiB)",
+			what, u.MemoryLimitBytes>>20, u.PeakMemoryBytes>>20)
+	default:
+		return fmt.Errorf("%s was killed: it exceeded its memory limit of %d MiB", what, u.MemoryLimitBytes>>20)
+	}
+}
+
+// summary describes the usage for the task's log.
+func (u ResourceUsage) summary() string {
+	var parts []string
+	switch {
+	case u.PeakMemoryBytes > 0 && u.MemoryLimitBytes > 0:
+		parts = append(parts, fmt.Sprintf("peak memory %d of %d MiB", u.PeakMemoryBytes>>20, u.MemoryLimitBytes>>20))
+	case u.PeakMemoryBytes > 0:
+		parts = append(parts, fmt.Sprintf("peak memory %d MiB", u.PeakMemoryBytes>>20))
+	case u.MemoryLimitBytes > 0:
+		parts = append(parts, fmt.Sprintf("memory limit %d MiB", u.MemoryLimitBytes>>20))
+	}
+	if u.CPUSeconds > 0 {
+		parts = append(parts, fmt.Sprintf("CPU time %.1fs", u.CPUSeconds))
+	}
+	if u.CPULimitCores > 0 {
+		parts = append(parts, fmt.Sprintf("CPU limit %g cores (throttled %.1fs)", u.CPULimitCores, u.CPUThrottledSeconds))
+	}
+	if u.OOMKilled {
+		parts = append(parts, "killed for exceeding its memory limit")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// cgroupPeriod is the cpu.max period; a task's quota is its cores times it.
+const cgroupPeriod = 100000
+
+// cgroupRemoveTimeout bounds how long removing a task's cgroup waits for
+// its processes to die.
+const cgroupRemoveTimeout = 5 * time.Second
+
+// sandboxCgroupFromEnv reads WORKER_SANDBOX_CGROUP, the cgroup v2 directory
+// under which training scripts get a cgroup per task. Empty disables
+// sandboxing.
+func sandboxCgroupFromEnv() string {
+	dir, ok := os.LookupEnv("WORKER_SANDBOX_CGROUP")
+	if !ok {
+		return "/sys/fs/cgroup/tensorfleet"
+	}
+	return dir
+}
+
+// cgroupSandbox runs training scripts in a cgroup v2 group per task, capped
+// at the CPU and memory the task's job asked for. The kernel kills a script
+// that exceeds its memory limit, which fails the task with that reason
+// rather than taking the worker down; the group also accounts what the
+// script used. A nil sandbox runs scripts unconfined.
+type cgroupSandbox struct {
+	root string
+}
+
+// newCgroupSandbox prepares root to hold task groups, returning nil if
+// cgroup v2 is not available to the worker.
+func newCgroupSandbox(root string) *cgroupSandbox {
+	if root == "" {
+		return nil
+	}
+	if err := setupCgroupRoot(root); err != nil {
+		log.Printf("Resource sandboxing disabled: %v", err)
+		return nil
+	}
+	log.Printf("Sandboxing training scripts in cgroups under %s", root)
+	return &cgroupSandbox{root: root}
+}
+
+// setupCgroupRoot creates root and delegates the cpu and memory controllers
+// to the groups below it.
+func setupCgroupRoot(root string) error {
+	parent := filepath.Dir(root)
+	if _, err := os.Stat(filepath.Join(parent, "cgroup.controllers")); err != nil {
+		return fmt.Errorf("%s is not a cgroup v2 hierarchy", parent)
+	}
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return err
+	}
+	err := enableControllers(parent)
+	if errors.Is(err, syscall.EBUSY) {
+		// A group with processes of its own cannot delegate controllers, as
+		// when the worker is alone in its container's cgroup; it moves into
+		// a leaf group beside root
+		err = joinCgroup(filepath.Join(parent, "worker"), os.Getpid())
+		if err == nil {
+			err = enableControllers(parent)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delegate controllers of %s: %v", parent, err)
+	}
+	if err := enableControllers(root); err != nil {
+		return fmt.Errorf("failed to delegate controllers of %s: %v", root, err)
+	}
+	return nil
+}
+
+func enableControllers(dir string) error {
+	return os.WriteFile(filepath.Join(dir, "cgroup.subtree_control"), []byte("+cpu +memory"), 0o644)
+}
+
+// joinCgroup moves the process pid into the group at dir, creating it.
+func joinCgroup(dir string, pid int) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "cgroup.procs"), []byte(strconv.Itoa(pid)), 0o644)
+}
+
+// taskCgroup is the cgroup of one run of a task.
+type taskCgroup struct {
+	dir    string
+	limits ResourceUsage
+}
+
+// create makes a group for a run of the task with the task's limits.
+func (s *cgroupSandbox) create(spec TaskSpec) (*taskCgroup, error) {
+	b := make([]byte, 4)
+	rand.Read(b)
+	g := &taskCgroup{
+		dir:    filepath.Join(s.root, "task-"+hex.EncodeToString(b)),
+		limits: taskLimits(spec),
+	}
+	if err := os.Mkdir(g.dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create the task's cgroup: %v", err)
+	}
+
+	var settings [][2]string
+	if spec.CPUCores > 0 {
+		quota := int64(spec.CPUCores) * cgroupPeriod
+		settings = append(settings, [2]string{"cpu.max", fmt.Sprintf("%d %d", quota, cgroupPeriod)})
+	}
+	if spec.MemoryMB > 0 {
+		settings = append(settings, [2]string{"memory.max", strconv.FormatInt(g.limits.MemoryLimitBytes, 10)})
+	}
+	for _, s := range settings {
+		if err := os.WriteFile(filepath.Join(g.dir, s[0]), []byte(s[1]), 0o644); err != nil {
+			g.remove()
+			return nil, fmt.Errorf("failed to set %s of the task's cgroup: %v", s[0], err)
+		}
+	}
+	if spec.MemoryMB > 0 {
+		// Swapping would let the task exceed its limit slowly rather than
+		// fail; without swap accounting the file does not exist
+		os.WriteFile(filepath.Join(g.dir, "memory.swap.max"), []byte("0"), 0o644)
+	}
+	return g, nil
+}
+
+// usage reads what the group's processes used. Counters the kernel does not
+// provide stay zero.
+func (g *taskCgroup) usage() ResourceUsage {
+	u := g.limits
+	if data, err := os.ReadFile(filepath.Join(g.dir, "memory.peak")); err == nil {
+		u.PeakMemoryBytes, _ = strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	}
+	cpu := g.readKeyed("cpu.stat")
+	u.CPUSeconds = float64(cpu["usage_usec"]) / 1e6
+	u.CPUThrottledSeconds = float64(cpu["throttled_usec"]) / 1e6
+	u.OOMKilled = g.readKeyed("memory.events")["oom_kill"] > 0
+	return u
+}
+
+// readKeyed parses a flat keyed cgroup file such as cpu.stat.
+func (g *taskCgroup) readKeyed(name string) map[string]int64 {
+	values := make(map[string]int64)
+	data, err := os.ReadFile(filepath.Join(g.dir, name))
+	if err != nil {
+		return values
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), " ")
+		if !ok {
+			continue
+		}
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			values[key] = n
+		}
+	}
+	return values
+}
+
+// remove kills what is left in the group, such as processes the script
+// forked and left behind, and deletes it.
+func (g *taskCgroup) remove() {
+	os.WriteFile(filepath.Join(g.dir, "cgroup.kill"), []byte("1"), 0o644)
+	deadline := time.Now().Add(cgroupRemoveTimeout)
+	for {
+		err := os.Remove(g.dir)
+		if err == nil || os.IsNotExist(err) {
+			return
+		}
+		if time.Now().After(deadline) {
+			log.Printf("Failed to remove cgroup %s: %v", g.dir, err)
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
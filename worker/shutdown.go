@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	orchestratorpb "github.com/tensorfleet/worker/proto/orchestrator"
+	workerpb "github.com/tensorfleet/worker/proto/worker"
+)
+
+// releaseGracePeriod bounds how long stopped tasks get to wind down and be
+// released once the shutdown timeout has passed. It covers the executors'
+// own grace periods for interrupted scripts and containers.
+const releaseGracePeriod = 30 * time.Second
+
+// reportFlushTimeout bounds how long a stopping worker keeps trying to
+// deliver pending task reports. Those left are spooled for its next start.
+const reportFlushTimeout = 10 * time.Second
+
+// shutdownTimeoutFromEnv reads WORKER_SHUTDOWN_TIMEOUT, how long a stopping
+// worker waits for running tasks to finish.
+func shutdownTimeoutFromEnv() time.Duration {
+	d, err := time.ParseDuration(os.Getenv("WORKER_SHUTDOWN_TIMEOUT"))
+	if err != nil || d < 0 {
+		return 60 * time.Second
+	}
+	return d
+}
+
+// shutdown winds the worker down once its task stream has been stopped.
+// Running tasks get up to timeout to finish and report; those still running
+// then are stopped and released back to the orchestrator, which requeues
+// them without counting the attempt. Reports not yet delivered and task
+// logs not yet shipped get one last try.
+func (ws *WorkerServer) shutdown(timeout time.Duration) {
+	defer func() {
+		ws.flushReports()
+		ws.flushLogs()
+	}()
+
+	ws.runningMu.Lock()
+	ws.shuttingDown = true
+	running := len(ws.running)
+	ws.runningMu.Unlock()
+	ws.setServingStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+
+	if running > 0 {
+		log.Printf("Waiting up to %s for %d running task(s) to finish", timeout, running)
+	}
+	if ws.waitForTasks(timeout) {
+		return
+	}
+
+	ws.runningMu.Lock()
+	log.Printf("Stopping %d task(s) still running after %s", len(ws.running), timeout)
+	for _, task := range ws.running {
+		task.cancel(errWorkerShuttingDown)
+	}
+	ws.runningMu.Unlock()
+	if !ws.waitForTasks(releaseGracePeriod) {
+		log.Printf("Tasks did not stop within %s; their leases will expire on the orchestrator", releaseGracePeriod)
+	}
+}
+
+// flushReports tries to deliver the task reports still pending before the
+// worker exits.
+func (ws *WorkerServer) flushReports() {
+	if len(ws.reports.pendingTasks()) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), reportFlushTimeout)
+	defer cancel()
+	if !ws.reports.flush(ctx) {
+		log.Printf("Reports of tasks %s left undelivered; they are sent on the next start",
+			strings.Join(ws.reports.pendingTasks(), ", "))
+	}
+}
+
+// flushLogs ships the task log lines still buffered before the worker exits.
+func (ws *WorkerServer) flushLogs() {
+	ctx, cancel := context.WithTimeout(context.Background(), logFlushTimeout)
+	defer cancel()
+	if err := ws.logs.flush(ctx); err != nil {
+		log.Printf("Failed to ship remaining task logs: %v", err)
+	}
+}
+
+// waitForTasks waits until no task is running, up to timeout. It reports
+// whether they all finished.
+func (ws *WorkerServer) waitForTasks(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		ws.inFlight.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// deregister tells the orchestrator the worker is gone, so it leaves the
+// activity views at once and any task it still holds, e.g. one that did not
+// stop in time, is requeued now rather than when its lease expires.
+func (ws *WorkerServer) deregister(reason string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := ws.orchestratorClient.DeregisterWorker(ctx, &orchestratorpb.DeregisterWorkerRequest{
+		WorkerId: ws.workerID,
+		Reason:   reason,
+	})
+	switch {
+	case err != nil:
+		log.Printf("Failed to deregister from orchestrator: %v", err)
+	case !resp.Deregistered:
+		log.Printf("Not deregistered: %s", resp.Message)
+	default:
+		log.Printf("Deregistered from orchestrator (%d tasks released)", resp.ReleasedTasks)
+	}
+}
+
+// releaseTask hands a task the worker will not finish back to the
+// orchestrator.
+func (ws *WorkerServer) releaseTask(req *workerpb.TaskRequest, reason string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp, err := ws.orchestratorClient.ReleaseTask(ctx, &orchestratorpb.ReleaseTaskRequest{
+		TaskId:   req.TaskId,
+		JobId:    req.JobId,
+		WorkerId: ws.workerID,
+		Reason:   reason,
+	})
+	switch {
+	case err != nil:
+		log.Printf("Failed to release task %s: %v", req.TaskId, err)
+	case !resp.Released:
+		log.Printf("Task %s not released: %s", req.TaskId, resp.Message)
+	default:
+		log.Printf("Released task %s back to the orchestrator", req.TaskId)
+	}
+}
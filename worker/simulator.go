@@ -0,0 +1,310 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// simulatorExecutor fakes training: it sleeps for a few seconds, reports a
+// loss and accuracy that improve with the epoch and nudges the weights.
+// With a seed, runs are reproducible: the same task, seed and settings give
+// the same duration, trajectory, weights and failures, for each run of the
+// task on a worker.
+type simulatorExecutor struct {
+	config simulatorConfig
+
+	mu   sync.Mutex
+	runs map[string]int // Runs so far of tasks not completed, so retries draw afresh
+}
+
+// simulatorConfig shapes simulated training.
+type simulatorConfig struct {
+	seed        int64
+	seeded      bool
+	minDuration time.Duration // Range a task's duration is drawn from
+	maxDuration time.Duration
+	failureRate float64 // Share of runs that fail
+	curve       string  // "hyperbolic" or "exponential"
+	rate        float64 // How fast loss and accuracy converge per epoch
+	noise       float64 // Amplitude of the loss noise; accuracy gets a fifth of it
+}
+
+// Convergence curves of the simulator.
+const (
+	curveHyperbolic  = "hyperbolic"
+	curveExponential = "exponential"
+)
+
+// Where simulated loss and accuracy start and, on the exponential curve,
+// level off.
+const (
+	simulatedInitialLoss     = 2.5
+	simulatedFinalLoss       = 0.05
+	simulatedInitialAccuracy = 0.1
+	simulatedFinalAccuracy   = 0.99
+)
+
+// simulatedValidationLossGap is how much worse than training a simulated
+// validation scores: its loss is this many times higher, its accuracy this
+// many times lower.
+const simulatedValidationLossGap = 1.1
+
+// errSimulatedFailure fails the runs the simulator's failure rate picks.
+var errSimulatedFailure = errors.New("simulated training failure")
+
+// simulatorConfigFromEnv reads the WORKER_SIM_* settings.
+func simulatorConfigFromEnv() (simulatorConfig, error) {
+	c := simulatorConfig{
+		minDuration: time.Second,
+		maxDuration: 4 * time.Second,
+		curve:       curveHyperbolic,
+		rate:        0.2,
+		noise:       0.1,
+	}
+	if v := os.Getenv("WORKER_SIM_SEED"); v != "" {
+		seed, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return c, fmt.Errorf("invalid WORKER_SIM_SEED %q", v)
+		}
+		c.seed, c.seeded = seed, true
+	}
+	for _, d := range []struct {
+		env string
+		out *time.Duration
+	}{
+		{"WORKER_SIM_MIN_DURATION", &c.minDuration},
+		{"WORKER_SIM_MAX_DURATION", &c.maxDuration},
+	} {
+		if v := os.Getenv(d.env); v != "" {
+			parsed, err := time.ParseDuration(v)
+			if err != nil || parsed < 0 {
+				return c, fmt.Errorf("invalid %s %q", d.env, v)
+			}
+			*d.out = parsed
+		}
+	}
+	if c.maxDuration < c.minDuration {
+		return c, fmt.Errorf("WORKER_SIM_MAX_DURATION %s is below WORKER_SIM_MIN_DURATION %s", c.maxDuration, c.minDuration)
+	}
+	for _, f := range []struct {
+		env      string
+		out      *float64
+		min, max float64
+	}{
+		{"WORKER_SIM_FAILURE_RATE", &c.failureRate, 0, 1},
+		{"WORKER_SIM_CONVERGENCE_RATE", &c.rate, 0, math.Inf(1)},
+		{"WORKER_SIM_NOISE", &c.noise, 0, math.Inf(1)},
+	} {
+		if v := os.Getenv(f.env); v != "" {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil || parsed < f.min || parsed > f.max {
+				return c, fmt.Errorf("invalid %s %q", f.env, v)
+			}
+			*f.out = parsed
+		}
+	}
+	if v := os.Getenv("WORKER_SIM_CURVE"); v != "" {
+		if v != curveHyperbolic && v != curveExponential {
+			return c, fmt.Errorf("invalid WORKER_SIM_CURVE %q (want %s or %s)", v, curveHyperbolic, curveExponential)
+		}
+		c.curve = v
+	}
+	return c, nil
+}
+
+func newSimulatorExecutor() (Executor, error) {
+	config, err := simulatorConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if config.seeded {
+		log.Printf("Simulating training deterministically (seed %d, %s curve, %s-%s per task, failure rate %g)",
+			config.seed, config.curve, config.minDuration, config.maxDuration, config.failureRate)
+	}
+	return &simulatorExecutor{config: config, runs: make(map[string]int)}, nil
+}
+
+// rng returns the random source of a run of the task: derived from the seed
+// and the task's input when seeded, random otherwise.
+func (e *simulatorExecutor) rng(spec TaskSpec) *rand.Rand {
+	e.mu.Lock()
+	run := e.runs[spec.TaskID]
+	e.runs[spec.TaskID]++
+	e.mu.Unlock()
+	if !e.config.seeded {
+		return rand.New(rand.NewSource(rand.Int63()))
+	}
+
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d/%s/%s/%d/%d-%d/%d", e.config.seed, spec.JobID, spec.TaskID, spec.Epoch, spec.BatchStart, spec.BatchEnd, run)
+	return rand.New(rand.NewSource(int64(h.Sum64())))
+}
+
+// completed forgets the runs of a task that has completed; only failed runs
+// are retried.
+func (e *simulatorExecutor) completed(taskID string) {
+	e.mu.Lock()
+	delete(e.runs, taskID)
+	e.mu.Unlock()
+}
+
+// metrics is the loss and accuracy the curve reaches at a fractional epoch,
+// counted from 0.
+func (c simulatorConfig) metrics(epoch float64) (loss, accuracy float64) {
+	epoch = max(epoch, 0)
+	if c.curve == curveExponential {
+		decay := math.Exp(-c.rate * epoch)
+		loss = simulatedFinalLoss + (simulatedInitialLoss-simulatedFinalLoss)*decay
+		accuracy = simulatedFinalAccuracy - (simulatedFinalAccuracy-simulatedInitialAccuracy)*decay
+		return loss, accuracy
+	}
+	return simulatedInitialLoss / (1 + epoch*c.rate), simulatedInitialAccuracy + epoch*c.rate*0.4
+}
+
+// simulatedProgressSteps bounds how many times a simulated task reports
+// progress, however many batches it covers.
+const simulatedProgressSteps = 10
+
+// simulatorCheckpointFile is where a simulated task records the steps it
+// has done, in its checkpoint directory.
+const simulatorCheckpointFile = "simulator.json"
+
+// simulatorCheckpoint is a simulated task's state after a step.
+type simulatorCheckpoint struct {
+	Step     int32   `json:"step"`
+	Steps    int32   `json:"steps"`
+	Loss     float64 `json:"loss"`
+	Accuracy float64 `json:"accuracy"`
+}
+
+func (e *simulatorExecutor) Run(ctx context.Context, spec TaskSpec) (Result, error) {
+	rng := e.rng(spec)
+	batches := max(spec.BatchEnd-spec.BatchStart, 1)
+	steps := min(batches, simulatedProgressSteps)
+	duration := e.config.minDuration
+	if spread := e.config.maxDuration - e.config.minDuration; spread > 0 {
+		duration += time.Duration(rng.Int63n(int64(spread) + 1))
+	}
+	step := duration / time.Duration(steps)
+	// Drawn up front, so a run fails the same way however far it gets
+	failAt := int32(0)
+	if rng.Float64() < e.config.failureRate {
+		failAt = rng.Int31n(steps) + 1
+	}
+
+	// Simulate convergence: loss decreases, accuracy increases over epochs
+	// and, more gently, over the task's batches
+	var loss, accuracy float64
+	first := int32(1)
+	if cp, ok := loadSimulatorCheckpoint(spec); ok && cp.Steps == steps {
+		first, loss, accuracy = cp.Step+1, cp.Loss, cp.Accuracy
+	}
+	for i := first; i <= steps; i++ {
+		timer := time.NewTimer(step)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return Result{}, ctx.Err()
+		case <-timer.C:
+		}
+		if i == failAt {
+			return Result{}, fmt.Errorf("%w at batch %d/%d", errSimulatedFailure, i*batches/steps, batches)
+		}
+
+		loss, accuracy = e.config.metrics(float64(spec.Epoch) + float64(i)/float64(steps) - 1)
+		loss += (rng.Float64() - 0.5) * e.config.noise
+		accuracy += (rng.Float64() - 0.5) * e.config.noise / 5
+		spec.reportProgress(Progress{
+			BatchesDone:  i * batches / steps,
+			BatchesTotal: batches,
+			Loss:         loss,
+			Accuracy:     accuracy,
+		})
+		saveSimulatorCheckpoint(spec, simulatorCheckpoint{Step: i, Steps: steps, Loss: loss, Accuracy: accuracy})
+	}
+	e.completed(spec.TaskID)
+
+	if loss < 0 {
+		loss = 0.01
+	}
+	if accuracy > 1.0 {
+		accuracy = 0.99
+	}
+
+	// Only training changes the model
+	switch spec.TaskType {
+	case taskTypePreprocess:
+		return Result{}, nil
+	case taskTypeEvaluate:
+		return Result{Loss: loss, Accuracy: accuracy}, nil
+	case taskTypeValidate:
+		// Held-out data scores a little worse than the data trained on
+		return Result{Loss: loss * simulatedValidationLossGap, Accuracy: accuracy / simulatedValidationLossGap}, nil
+	}
+	return Result{
+		Loss:         loss,
+		Accuracy:     accuracy,
+		ModelWeights: simulateWeights(rng, spec.ModelWeights),
+	}, nil
+}
+
+// loadSimulatorCheckpoint reads the steps an interrupted attempt of the task
+// got through.
+func loadSimulatorCheckpoint(spec TaskSpec) (simulatorCheckpoint, bool) {
+	var cp simulatorCheckpoint
+	if !spec.Resumed {
+		return cp, false
+	}
+	data, err := os.ReadFile(filepath.Join(spec.CheckpointDir, simulatorCheckpointFile))
+	if err != nil || json.Unmarshal(data, &cp) != nil {
+		return cp, false
+	}
+	return cp, true
+}
+
+func saveSimulatorCheckpoint(spec TaskSpec, cp simulatorCheckpoint) {
+	if spec.CheckpointDir == "" {
+		return
+	}
+	data, _ := json.Marshal(cp)
+	path := filepath.Join(spec.CheckpointDir, simulatorCheckpointFile)
+	if os.WriteFile(path+".tmp", data, 0o644) == nil {
+		os.Rename(path+".tmp", path)
+	}
+}
+
+// simulatedModelSize is the number of parameters in the simulated model
+const simulatedModelSize = 64
+
+// simulateWeights applies a simulated training step to the weights received
+// from the orchestrator. Weights travel as little-endian float32 values; empty
+// input starts a fresh model.
+func simulateWeights(rng *rand.Rand, initial []byte) []byte {
+	n := len(initial) / 4
+	if n == 0 {
+		n = simulatedModelSize
+	}
+
+	data := make([]byte, n*4)
+	for i := 0; i < n; i++ {
+		w := float32(0)
+		if len(initial) >= (i+1)*4 {
+			w = math.Float32frombits(binary.LittleEndian.Uint32(initial[i*4:]))
+		}
+		w += float32((rng.Float64() - 0.5) * 0.01)
+		binary.LittleEndian.PutUint32(data[i*4:], math.Float32bits(w))
+	}
+	return data
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	orchestratorpb "github.com/tensorfleet/worker/proto/orchestrator"
+)
+
+// weightsBucket is the storage service bucket task weights are uploaded to.
+const weightsBucket = "checkpoints"
+
+// inlineWeightsMaxFromEnv reads WORKER_INLINE_WEIGHTS_MAX_BYTES, the
+// largest weights sent to the orchestrator inside a task report; larger
+// weights are uploaded to storage and referenced instead.
+func inlineWeightsMaxFromEnv() int {
+	n, err := strconv.Atoi(os.Getenv("WORKER_INLINE_WEIGHTS_MAX_BYTES"))
+	if err != nil || n < 0 {
+		return 256 << 10
+	}
+	return n
+}
+
+// storageClient uploads what tasks produce to the storage service.
+type storageClient struct {
+	baseURL string
+	client  *http.Client
+}
+
+func newStorageClient() *storageClient {
+	return &storageClient{baseURL: storageServiceURL(), client: &http.Client{Timeout: 5 * time.Minute}}
+}
+
+// uploadWeights stores a task's weights under a key derived from their
+// SHA-256, so identical weights are stored once, and returns a reference
+// the orchestrator can fetch them by.
+func (c *storageClient) uploadWeights(ctx context.Context, data []byte) (*orchestratorpb.ObjectRef, error) {
+	sum := sha256.Sum256(data)
+	digest := hex.EncodeToString(sum[:])
+	key := "weights/" + digest
+	if err := c.upload(ctx, weightsBucket, key, digest, data); err != nil {
+		return nil, err
+	}
+	return &orchestratorpb.ObjectRef{
+		Bucket:    weightsBucket,
+		Key:       key,
+		Sha256:    digest,
+		SizeBytes: int64(len(data)),
+	}, nil
+}
+
+// upload stores data in the bucket under key, as a file called name.
+func (c *storageClient) upload(ctx context.Context, bucket, key, name string, data []byte) error {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", name)
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(data); err != nil {
+		return err
+	}
+	if err := form.Close(); err != nil {
+		return err
+	}
+
+	endpoint, err := url.JoinPath(c.baseURL, "api/v1/upload", bucket, key)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("storage service returned status %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+// errNoObject is returned by download for an object the storage service
+// does not have.
+var errNoObject = errors.New("no such object")
+
+// download reads the object stored in the bucket under key.
+func (c *storageClient) download(ctx context.Context, bucket, key string) ([]byte, error) {
+	endpoint, err := url.JoinPath(c.baseURL, "api/v1/download", bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errNoObject
+	}
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("storage service returned status %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// remove deletes the object stored in the bucket under key.
+func (c *storageClient) remove(ctx context.Context, bucket, key string) error {
+	endpoint, err := url.JoinPath(c.baseURL, "api/v1/delete", bucket, key)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("storage service returned status %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+// uploadReportWeights uploads the weights of a task's report that are too
+// large to send inline and returns where they are, or nil if they stay
+// inline.
+func (ws *WorkerServer) uploadReportWeights(ctx context.Context, req *orchestratorpb.TaskCompletionRequest) (*orchestratorpb.ObjectRef, error) {
+	if len(req.ModelWeights) <= ws.inlineWeightsMax || req.ModelWeightsRef != nil {
+		return nil, nil
+	}
+	ref, err := ws.storage.uploadWeights(ctx, req.ModelWeights)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Uploaded weights of task %s to %s/%s (%d bytes)", req.TaskId, ref.Bucket, ref.Key, ref.SizeBytes)
+	return ref, nil
+}
+
+// reportedWeights returns the weights of a task's report, downloading them
+// if they were uploaded.
+func (ws *WorkerServer) reportedWeights(ctx context.Context, req *orchestratorpb.TaskCompletionRequest) ([]byte, error) {
+	if req.ModelWeightsRef == nil {
+		return req.ModelWeights, nil
+	}
+	return ws.storage.download(ctx, req.ModelWeightsRef.Bucket, req.ModelWeightsRef.Key)
+}
@@ -0,0 +1,367 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// subprocessExecutor runs a user's Python training script once per task.
+// The task is described to the script in TENSORFLEET_* environment
+// variables and mirrored as flags; the script writes its outcome to the
+// JSON file named by TENSORFLEET_RESULT_FILE:
+//
+//	{"loss": 0.42, "accuracy": 0.87, "weights_path": "weights.bin"}
+//
+// weights_path is optional and resolved against the task directory, which
+// is the script's working directory. A script that exits non-zero, writes
+// no result, or sets "error" in it fails the task. "artifacts" optionally
+// lists files or glob patterns in the task directory, such as plots and
+// evaluation reports, for the worker to upload once the task completes.
+//
+// While it trains, the script may rewrite the JSON file named by
+// TENSORFLEET_PROGRESS_FILE to report how far it has got:
+//
+//	{"batches_done": 40, "batches_total": 100, "loss": 0.61, "accuracy": 0.72}
+//
+// With a sandbox, each script runs in a cgroup limited to the CPU and
+// memory its job asked for.
+type subprocessExecutor struct {
+	python  string
+	script  string
+	timeout time.Duration
+	sandbox *cgroupSandbox
+}
+
+// Time a script gets to exit after being interrupted before it is killed.
+const subprocessGracePeriod = 10 * time.Second
+
+// Most of a script's stderr quoted in a task's error.
+const subprocessStderrTail = 2048
+
+func newSubprocessExecutor() (Executor, error) {
+	script := os.Getenv("WORKER_TRAIN_SCRIPT")
+	if script == "" {
+		return nil, errors.New("the subprocess executor needs WORKER_TRAIN_SCRIPT")
+	}
+	python := os.Getenv("WORKER_PYTHON")
+	if python == "" {
+		python = "python3"
+	}
+	timeout, err := trainTimeoutFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Running tasks with %s %s (timeout %s)", python, script, timeout)
+	return &subprocessExecutor{
+		python:  python,
+		script:  script,
+		timeout: timeout,
+		sandbox: newCgroupSandbox(sandboxCgroupFromEnv()),
+	}, nil
+}
+
+// Scripts read their datasets from the worker's cache.
+func (e *subprocessExecutor) localDatasets() {}
+
+// checkHealth reports whether the interpreter and the training script are
+// still there.
+func (e *subprocessExecutor) checkHealth(ctx context.Context) error {
+	if _, err := exec.LookPath(e.python); err != nil {
+		return err
+	}
+	if _, err := os.Stat(e.script); err != nil {
+		return fmt.Errorf("training script unavailable: %v", err)
+	}
+	return nil
+}
+
+// subprocessResult is the result file a training script writes.
+type subprocessResult struct {
+	Loss        *float64 `json:"loss"`
+	Accuracy    *float64 `json:"accuracy"`
+	WeightsPath string   `json:"weights_path"`
+	Artifacts   []string `json:"artifacts"`
+	Error       string   `json:"error"`
+}
+
+// trainTimeoutFromEnv reads WORKER_TRAIN_TIMEOUT, the longest a training
+// script or container may run.
+func trainTimeoutFromEnv() (time.Duration, error) {
+	raw := os.Getenv("WORKER_TRAIN_TIMEOUT")
+	if raw == "" {
+		return 30 * time.Minute, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid WORKER_TRAIN_TIMEOUT %q", raw)
+	}
+	return d, nil
+}
+
+// Files in a task directory.
+const (
+	taskWeightsInFile = "weights_in.bin"
+	taskResultFile    = "result.json"
+	taskProgressFile  = "progress.json"
+)
+
+// taskDirPaths are where a training script that sees the task directory at
+// dir finds its starting weights, empty if there are none, and writes its
+// result.
+func taskDirPaths(spec TaskSpec, dir string) (weightsIn, resultFile string) {
+	if len(spec.ModelWeights) > 0 {
+		weightsIn = filepath.Join(dir, taskWeightsInFile)
+	}
+	return weightsIn, filepath.Join(dir, taskResultFile)
+}
+
+// taskEnv describes the task to a training script.
+func taskEnv(spec TaskSpec, weightsIn, resultFile string) []string {
+	hyperparameters, _ := json.Marshal(spec.Hyperparameters)
+	return []string{
+		"TENSORFLEET_TASK_ID=" + spec.TaskID,
+		"TENSORFLEET_TASK_TYPE=" + spec.TaskType,
+		"TENSORFLEET_JOB_ID=" + spec.JobID,
+		"TENSORFLEET_MODEL_TYPE=" + spec.ModelType,
+		"TENSORFLEET_DATASET_PATH=" + spec.DatasetPath,
+		"TENSORFLEET_EPOCH=" + strconv.Itoa(int(spec.Epoch)),
+		"TENSORFLEET_BATCH_START=" + strconv.Itoa(int(spec.BatchStart)),
+		"TENSORFLEET_BATCH_END=" + strconv.Itoa(int(spec.BatchEnd)),
+		"TENSORFLEET_HYPERPARAMETERS=" + string(hyperparameters),
+		"TENSORFLEET_WEIGHTS_IN=" + weightsIn,
+		"TENSORFLEET_RESULT_FILE=" + resultFile,
+	}
+}
+
+// decodeTaskResult parses a result file, failing the task if the script
+// reported an error or left out its metrics. Preprocessing tasks need not
+// report metrics.
+func decodeTaskResult(data []byte, taskType string) (*subprocessResult, error) {
+	var out subprocessResult
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("malformed result file: %v", err)
+	}
+	if out.Error != "" {
+		return nil, fmt.Errorf("training script reported: %s", out.Error)
+	}
+	if taskType != taskTypePreprocess && (out.Loss == nil || out.Accuracy == nil) {
+		return nil, errors.New("result file needs loss and accuracy")
+	}
+	return &out, nil
+}
+
+// metrics is the result's loss and accuracy, zero where it left them out.
+func (out *subprocessResult) metrics() Result {
+	var result Result
+	if out.Loss != nil {
+		result.Loss = *out.Loss
+	}
+	if out.Accuracy != nil {
+		result.Accuracy = *out.Accuracy
+	}
+	return result
+}
+
+// readTaskResult reads the result a training script wrote to the task
+// directory dir. scriptDir is where the script saw that directory, to
+// resolve an absolute weights_path.
+func readTaskResult(spec TaskSpec, dir, scriptDir string) (Result, error) {
+	resultPath, err := inTaskDir(dir, taskResultFile)
+	if err != nil {
+		return Result{}, fmt.Errorf("training script wrote no result: %v", err)
+	}
+	data, err := os.ReadFile(resultPath)
+	if err != nil {
+		return Result{}, fmt.Errorf("training script wrote no result: %v", err)
+	}
+	out, err := decodeTaskResult(data, spec.TaskType)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := out.metrics()
+	if out.WeightsPath != "" {
+		path, err := taskDirRel(out.WeightsPath, scriptDir)
+		if err == nil {
+			path, err = inTaskDir(dir, path)
+		}
+		if err != nil {
+			return Result{}, fmt.Errorf("weights_path %v", err)
+		}
+		if result.ModelWeights, err = os.ReadFile(path); err != nil {
+			return Result{}, fmt.Errorf("failed to read weights: %v", err)
+		}
+	}
+	for _, artifact := range out.Artifacts {
+		path, err := taskDirRel(artifact, scriptDir)
+		if err != nil {
+			return Result{}, fmt.Errorf("artifact %v", err)
+		}
+		result.Artifacts = append(result.Artifacts, path)
+	}
+	return result, nil
+}
+
+// taskDirRel resolves a path a training script named against scriptDir,
+// where it saw the task directory, to one relative to the task directory.
+func taskDirRel(path, scriptDir string) (string, error) {
+	rel := path
+	if filepath.IsAbs(path) {
+		var err error
+		if rel, err = filepath.Rel(scriptDir, path); err != nil {
+			return "", fmt.Errorf("%s is outside the task directory", path)
+		}
+	}
+	if !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("%s is outside the task directory", path)
+	}
+	return rel, nil
+}
+
+// inTaskDir returns the path of rel within the task directory dir with
+// symlinks followed, or an error if they lead out of it. Training code
+// writes the task directory, and may link files the worker must not read
+// into it.
+func inTaskDir(dir, rel string) (string, error) {
+	root, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return "", err
+	}
+	path, err := filepath.EvalSymlinks(filepath.Join(root, rel))
+	if err != nil {
+		return "", err
+	}
+	if inside, err := filepath.Rel(root, path); err != nil || !filepath.IsLocal(inside) {
+		return "", fmt.Errorf("%s is outside the task directory", rel)
+	}
+	return path, nil
+}
+
+func (e *subprocessExecutor) Run(ctx context.Context, spec TaskSpec) (Result, error) {
+	dir := spec.Workspace
+
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, e.python, e.script,
+		"--task-id", spec.TaskID,
+		"--task-type", spec.TaskType,
+		"--job-id", spec.JobID,
+		"--epoch", strconv.Itoa(int(spec.Epoch)),
+		"--batch-start", strconv.Itoa(int(spec.BatchStart)),
+		"--batch-end", strconv.Itoa(int(spec.BatchEnd)),
+		"--result-file", filepath.Join(dir, taskResultFile),
+	)
+	cmd.Dir = dir
+	weightsIn, resultFile := taskDirPaths(spec, dir)
+	cmd.Env = append(os.Environ(), taskEnv(spec, weightsIn, resultFile)...)
+	cmd.Env = append(cmd.Env,
+		"TENSORFLEET_PROGRESS_FILE="+filepath.Join(dir, taskProgressFile),
+		"TENSORFLEET_DATASET_FILE="+spec.DatasetFile,
+		"TENSORFLEET_CHECKPOINT_DIR="+spec.CheckpointDir,
+		"TENSORFLEET_RESUME="+strconv.FormatBool(spec.Resumed),
+	)
+	if spec.CheckpointInterval > 0 {
+		cmd.Env = append(cmd.Env, "TENSORFLEET_CHECKPOINT_INTERVAL="+strconv.Itoa(int(spec.CheckpointInterval.Seconds())))
+	}
+	if spec.GPUs != nil {
+		cmd.Env = append(cmd.Env, "CUDA_VISIBLE_DEVICES="+strings.Join(spec.GPUs, ","))
+	}
+	// Let the script save what it can before it is killed
+	cmd.Cancel = func() error { return cmd.Process.Signal(os.Interrupt) }
+	cmd.WaitDelay = subprocessGracePeriod
+
+	stderr := &tailBuffer{max: subprocessStderrTail}
+	stdoutLog, stderrLog := newLogWriter(spec, "INFO", "stdout"), newLogWriter(spec, "WARN", "stderr")
+	defer stdoutLog.flush()
+	defer stderrLog.flush()
+	cmd.Stdout = io.MultiWriter(os.Stdout, stdoutLog)
+	cmd.Stderr = io.MultiWriter(os.Stderr, stderr, stderrLog)
+
+	watchCtx, stopWatching := context.WithCancel(ctx)
+	defer stopWatching()
+	go watchProgressFile(watchCtx, filepath.Join(dir, taskProgressFile), spec)
+
+	var group *taskCgroup
+	if e.sandbox != nil {
+		var err error
+		if group, err = e.sandbox.create(spec); err != nil {
+			return Result{}, err
+		}
+		defer group.remove()
+	}
+
+	err := runInCgroup(cmd, group)
+	var usage ResourceUsage
+	if group != nil {
+		usage = group.usage()
+		spec.reportUsage(usage)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(ctx.Err(), context.DeadlineExceeded):
+			return Result{}, fmt.Errorf("training script timed out after %s", e.timeout)
+		case ctx.Err() != nil:
+			return Result{}, ctx.Err()
+		case usage.OOMKilled:
+			return Result{}, usage.oomError("training script")
+		}
+		return Result{}, fmt.Errorf("training script failed: %v: %s", err, stderr.String())
+	}
+
+	return readTaskResult(spec, dir, dir)
+}
+
+// runInCgroup runs cmd, in group if there is one. The script is started in
+// the group, so neither it nor anything it forks ever runs outside it.
+func runInCgroup(cmd *exec.Cmd, group *taskCgroup) error {
+	if group == nil {
+		return cmd.Run()
+	}
+	dir, err := os.Open(group.dir)
+	if err != nil {
+		return fmt.Errorf("failed to open the script's cgroup: %v", err)
+	}
+	defer dir.Close()
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.UseCgroupFD = true
+	cmd.SysProcAttr.CgroupFD = int(dir.Fd())
+	return cmd.Run()
+}
+
+// tailBuffer keeps the last max bytes written to it.
+type tailBuffer struct {
+	mu  sync.Mutex
+	max int
+	buf []byte
+}
+
+func (t *tailBuffer) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.buf = append(t.buf, p...)
+	if len(t.buf) > t.max {
+		t.buf = t.buf[len(t.buf)-t.max:]
+	}
+	return len(p), nil
+}
+
+func (t *tailBuffer) String() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return strings.TrimSpace(string(t.buf))
+}
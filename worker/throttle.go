@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	orchestratorpb "github.com/tensorfleet/worker/proto/orchestrator"
+)
+
+// throttleResumeMargin is how far, in percentage points, use must fall
+// below a threshold before a throttled worker takes work again, so that it
+// does not flap around the threshold.
+const throttleResumeMargin = 5
+
+var workerBackpressure = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "worker_backpressure",
+	Help: "1 while the host is too busy for the worker to take new tasks",
+})
+
+func init() {
+	prometheus.MustRegister(workerBackpressure)
+}
+
+// loadThresholds are the host use, in percent, above which the worker takes
+// no new tasks. Zero turns a threshold off.
+type loadThresholds struct {
+	CPUPercent    float64
+	MemoryPercent float64
+	GPUPercent    float64 // Mean utilization across the worker's GPUs
+}
+
+// loadThresholdsFromEnv reads WORKER_THROTTLE_CPU_PERCENT,
+// WORKER_THROTTLE_MEMORY_PERCENT and WORKER_THROTTLE_GPU_PERCENT. All are
+// off by default.
+func loadThresholdsFromEnv() loadThresholds {
+	percent := func(env string) float64 {
+		v, err := strconv.ParseFloat(os.Getenv(env), 64)
+		if err != nil || v <= 0 {
+			return 0
+		}
+		return v
+	}
+	return loadThresholds{
+		CPUPercent:    percent("WORKER_THROTTLE_CPU_PERCENT"),
+		MemoryPercent: percent("WORKER_THROTTLE_MEMORY_PERCENT"),
+		GPUPercent:    percent("WORKER_THROTTLE_GPU_PERCENT"),
+	}
+}
+
+// loadThrottle holds back new tasks while the host is busier than its
+// thresholds allow, whoever is keeping it busy. Tasks already running carry
+// on. Once throttled, the worker takes work again only when every use has
+// fallen throttleResumeMargin below its threshold.
+type loadThrottle struct {
+	mu         sync.Mutex
+	thresholds loadThresholds
+	reason     string        // Why work is held back; empty while it is not
+	lifted     chan struct{} // Closed when the throttle lifts
+}
+
+func newLoadThrottle(thresholds loadThresholds) *loadThrottle {
+	return &loadThrottle{thresholds: thresholds}
+}
+
+// setThresholds replaces the thresholds; the next sample applies them.
+func (t *loadThrottle) setThresholds(thresholds loadThresholds) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.thresholds = thresholds
+}
+
+// update throttles or lifts the throttle for a sample of the host's use.
+func (t *loadThrottle) update(h hostStats, gpus []*orchestratorpb.GpuLoad) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	margin := 0.0
+	if t.reason != "" {
+		margin = throttleResumeMargin
+	}
+	var over []string
+	check := func(name string, value, threshold float64) {
+		if threshold > 0 && value > threshold-margin {
+			over = append(over, fmt.Sprintf("%s %.0f%% (limit %.0f%%)", name, value, threshold))
+		}
+	}
+	check("CPU", h.CPUPercent, t.thresholds.CPUPercent)
+	check("memory", h.MemoryPercent, t.thresholds.MemoryPercent)
+	if len(gpus) > 0 {
+		total := 0.0
+		for _, g := range gpus {
+			total += g.UtilizationPercent
+		}
+		check("GPU", total/float64(len(gpus)), t.thresholds.GPUPercent)
+	}
+
+	switch {
+	case len(over) > 0 && t.reason == "":
+		t.reason = strings.Join(over, ", ")
+		t.lifted = make(chan struct{})
+		workerBackpressure.Set(1)
+		log.Printf("Host too busy, taking no new tasks: %s", t.reason)
+	case len(over) > 0:
+		t.reason = strings.Join(over, ", ")
+	case t.reason != "":
+		t.reason = ""
+		close(t.lifted)
+		workerBackpressure.Set(0)
+		log.Printf("Host load back under its limits, taking tasks again")
+	}
+}
+
+// backpressure is why new tasks are held back, or empty if they are not.
+func (t *loadThrottle) backpressure() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.reason
+}
+
+// wait returns once the worker may take new tasks. It fails only if ctx is
+// done first.
+func (t *loadThrottle) wait(ctx context.Context) error {
+	t.mu.Lock()
+	throttled, lifted := t.reason != "", t.lifted
+	t.mu.Unlock()
+	if !throttled {
+		return nil
+	}
+	select {
+	case <-lifted:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// watchLoad checks the host's use against the throttle's thresholds each
+// time it is sampled, until ctx is done.
+func (ws *WorkerServer) watchLoad(ctx context.Context) {
+	ticker := time.NewTicker(resourceSampleInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		ws.throttle.update(ws.resources.stats(), ws.gpus.stats())
+	}
+}
@@ -31,8 +31,21 @@ type TaskRequest struct {
 	Epoch           int32                  `protobuf:"varint,6,opt,name=epoch,proto3" json:"epoch,omitempty"`
 	BatchStart      int32                  `protobuf:"varint,7,opt,name=batch_start,json=batchStart,proto3" json:"batch_start,omitempty"`
 	BatchEnd        int32                  `protobuf:"varint,8,opt,name=batch_end,json=batchEnd,proto3" json:"batch_end,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+	// Starting weights, as little-endian float32 values; empty to initialise.
+	ModelWeights []byte `protobuf:"bytes,9,opt,name=model_weights,json=modelWeights,proto3" json:"model_weights,omitempty"`
+	// Container image to run the task in; empty for the worker's default.
+	Image string `protobuf:"bytes,10,opt,name=image,proto3" json:"image,omitempty"`
+	// GPUs to pin the task to.
+	GpuCount int32 `protobuf:"varint,11,opt,name=gpu_count,json=gpuCount,proto3" json:"gpu_count,omitempty"`
+	// CPU cores and memory to limit the task to; 0 for the worker's default.
+	CpuCores int32 `protobuf:"varint,12,opt,name=cpu_cores,json=cpuCores,proto3" json:"cpu_cores,omitempty"`
+	MemoryMb int64 `protobuf:"varint,13,opt,name=memory_mb,json=memoryMb,proto3" json:"memory_mb,omitempty"`
+	// "train", "preprocess", "evaluate" or "validate"; empty means train.
+	TaskType string `protobuf:"bytes,14,opt,name=task_type,json=taskType,proto3" json:"task_type,omitempty"`
+	// Which attempt at the task this is, counting from 1.
+	Attempt       int32 `protobuf:"varint,15,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *TaskRequest) Reset() {
@@ -121,6 +134,55 @@ func (x *TaskRequest) GetBatchEnd() int32 {
 	return 0
 }
 
+func (x *TaskRequest) GetModelWeights() []byte {
+	if x != nil {
+		return x.ModelWeights
+	}
+	return nil
+}
+
+func (x *TaskRequest) GetImage() string {
+	if x != nil {
+		return x.Image
+	}
+	return ""
+}
+
+func (x *TaskRequest) GetGpuCount() int32 {
+	if x != nil {
+		return x.GpuCount
+	}
+	return 0
+}
+
+func (x *TaskRequest) GetCpuCores() int32 {
+	if x != nil {
+		return x.CpuCores
+	}
+	return 0
+}
+
+func (x *TaskRequest) GetMemoryMb() int64 {
+	if x != nil {
+		return x.MemoryMb
+	}
+	return 0
+}
+
+func (x *TaskRequest) GetTaskType() string {
+	if x != nil {
+		return x.TaskType
+	}
+	return ""
+}
+
+func (x *TaskRequest) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
 type TaskResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
@@ -257,8 +319,17 @@ type WorkerStatusResponse struct {
 	CompletedTasks int32                  `protobuf:"varint,4,opt,name=completed_tasks,json=completedTasks,proto3" json:"completed_tasks,omitempty"`
 	CpuUsage       float64                `protobuf:"fixed64,5,opt,name=cpu_usage,json=cpuUsage,proto3" json:"cpu_usage,omitempty"`
 	MemoryUsage    float64                `protobuf:"fixed64,6,opt,name=memory_usage,json=memoryUsage,proto3" json:"memory_usage,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	// Tasks the worker runs at once.
+	MaxConcurrentTasks int32 `protobuf:"varint,7,opt,name=max_concurrent_tasks,json=maxConcurrentTasks,proto3" json:"max_concurrent_tasks,omitempty"`
+	// Host resource use: cpu_usage, memory_usage and disk_usage are
+	// percentages.
+	MemoryUsedBytes       uint64  `protobuf:"varint,8,opt,name=memory_used_bytes,json=memoryUsedBytes,proto3" json:"memory_used_bytes,omitempty"`
+	DiskUsage             float64 `protobuf:"fixed64,9,opt,name=disk_usage,json=diskUsage,proto3" json:"disk_usage,omitempty"`
+	LoadAverage           float64 `protobuf:"fixed64,10,opt,name=load_average,json=loadAverage,proto3" json:"load_average,omitempty"`
+	NetSentBytesPerSecond float64 `protobuf:"fixed64,11,opt,name=net_sent_bytes_per_second,json=netSentBytesPerSecond,proto3" json:"net_sent_bytes_per_second,omitempty"`
+	NetRecvBytesPerSecond float64 `protobuf:"fixed64,12,opt,name=net_recv_bytes_per_second,json=netRecvBytesPerSecond,proto3" json:"net_recv_bytes_per_second,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
 }
 
 func (x *WorkerStatusResponse) Reset() {
@@ -333,9 +404,53 @@ func (x *WorkerStatusResponse) GetMemoryUsage() float64 {
 	return 0
 }
 
+func (x *WorkerStatusResponse) GetMaxConcurrentTasks() int32 {
+	if x != nil {
+		return x.MaxConcurrentTasks
+	}
+	return 0
+}
+
+func (x *WorkerStatusResponse) GetMemoryUsedBytes() uint64 {
+	if x != nil {
+		return x.MemoryUsedBytes
+	}
+	return 0
+}
+
+func (x *WorkerStatusResponse) GetDiskUsage() float64 {
+	if x != nil {
+		return x.DiskUsage
+	}
+	return 0
+}
+
+func (x *WorkerStatusResponse) GetLoadAverage() float64 {
+	if x != nil {
+		return x.LoadAverage
+	}
+	return 0
+}
+
+func (x *WorkerStatusResponse) GetNetSentBytesPerSecond() float64 {
+	if x != nil {
+		return x.NetSentBytesPerSecond
+	}
+	return 0
+}
+
+func (x *WorkerStatusResponse) GetNetRecvBytesPerSecond() float64 {
+	if x != nil {
+		return x.NetRecvBytesPerSecond
+	}
+	return 0
+}
+
 type CancelTaskRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	TaskId        string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	TaskId string                 `protobuf:"bytes,1,opt,name=task_id,json=taskId,proto3" json:"task_id,omitempty"`
+	// Why the task is cancelled, for the worker's logs and the task's result.
+	Reason        string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -377,10 +492,20 @@ func (x *CancelTaskRequest) GetTaskId() string {
 	return ""
 }
 
+func (x *CancelTaskRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
 type CancelTaskResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Success bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// The task had stopped by the time the worker answered; otherwise it is
+	// still winding down.
+	Stopped       bool `protobuf:"varint,3,opt,name=stopped,proto3" json:"stopped,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -429,11 +554,18 @@ func (x *CancelTaskResponse) GetMessage() string {
 	return ""
 }
 
+func (x *CancelTaskResponse) GetStopped() bool {
+	if x != nil {
+		return x.Stopped
+	}
+	return false
+}
+
 var File_worker_proto protoreflect.FileDescriptor
 
 const file_worker_proto_rawDesc = "" +
 	"\n" +
-	"\fworker.proto\x12\x06worker\"\xeb\x02\n" +
+	"\fworker.proto\x12\x06worker\"\xb4\x04\n" +
 	"\vTaskRequest\x12\x17\n" +
 	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x15\n" +
 	"\x06job_id\x18\x02 \x01(\tR\x05jobId\x12\x1d\n" +
@@ -444,7 +576,15 @@ const file_worker_proto_rawDesc = "" +
 	"\x05epoch\x18\x06 \x01(\x05R\x05epoch\x12\x1f\n" +
 	"\vbatch_start\x18\a \x01(\x05R\n" +
 	"batchStart\x12\x1b\n" +
-	"\tbatch_end\x18\b \x01(\x05R\bbatchEnd\x1aB\n" +
+	"\tbatch_end\x18\b \x01(\x05R\bbatchEnd\x12#\n" +
+	"\rmodel_weights\x18\t \x01(\fR\fmodelWeights\x12\x14\n" +
+	"\x05image\x18\n" +
+	" \x01(\tR\x05image\x12\x1b\n" +
+	"\tgpu_count\x18\v \x01(\x05R\bgpuCount\x12\x1b\n" +
+	"\tcpu_cores\x18\f \x01(\x05R\bcpuCores\x12\x1b\n" +
+	"\tmemory_mb\x18\r \x01(\x03R\bmemoryMb\x12\x1b\n" +
+	"\ttask_type\x18\x0e \x01(\tR\btaskType\x12\x18\n" +
+	"\aattempt\x18\x0f \x01(\x05R\aattempt\x1aB\n" +
 	"\x14HyperparametersEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xb0\x01\n" +
@@ -456,19 +596,29 @@ const file_worker_proto_rawDesc = "" +
 	"\baccuracy\x18\x05 \x01(\x01R\baccuracy\x12#\n" +
 	"\rmodel_weights\x18\x06 \x01(\fR\fmodelWeights\"2\n" +
 	"\x13WorkerStatusRequest\x12\x1b\n" +
-	"\tworker_id\x18\x01 \x01(\tR\bworkerId\"\xd9\x01\n" +
+	"\tworker_id\x18\x01 \x01(\tR\bworkerId\"\xed\x03\n" +
 	"\x14WorkerStatusResponse\x12\x1b\n" +
 	"\tworker_id\x18\x01 \x01(\tR\bworkerId\x12\x16\n" +
 	"\x06status\x18\x02 \x01(\tR\x06status\x12#\n" +
 	"\rcurrent_tasks\x18\x03 \x01(\x05R\fcurrentTasks\x12'\n" +
 	"\x0fcompleted_tasks\x18\x04 \x01(\x05R\x0ecompletedTasks\x12\x1b\n" +
 	"\tcpu_usage\x18\x05 \x01(\x01R\bcpuUsage\x12!\n" +
-	"\fmemory_usage\x18\x06 \x01(\x01R\vmemoryUsage\",\n" +
+	"\fmemory_usage\x18\x06 \x01(\x01R\vmemoryUsage\x120\n" +
+	"\x14max_concurrent_tasks\x18\a \x01(\x05R\x12maxConcurrentTasks\x12*\n" +
+	"\x11memory_used_bytes\x18\b \x01(\x04R\x0fmemoryUsedBytes\x12\x1d\n" +
+	"\n" +
+	"disk_usage\x18\t \x01(\x01R\tdiskUsage\x12!\n" +
+	"\fload_average\x18\n" +
+	" \x01(\x01R\vloadAverage\x128\n" +
+	"\x19net_sent_bytes_per_second\x18\v \x01(\x01R\x15netSentBytesPerSecond\x128\n" +
+	"\x19net_recv_bytes_per_second\x18\f \x01(\x01R\x15netRecvBytesPerSecond\"D\n" +
 	"\x11CancelTaskRequest\x12\x17\n" +
-	"\atask_id\x18\x01 \x01(\tR\x06taskId\"H\n" +
+	"\atask_id\x18\x01 \x01(\tR\x06taskId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"b\n" +
 	"\x12CancelTaskResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage2\xdc\x01\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x18\n" +
+	"\astopped\x18\x03 \x01(\bR\astopped2\xdc\x01\n" +
 	"\rWorkerService\x128\n" +
 	"\vExecuteTask\x12\x13.worker.TaskRequest\x1a\x14.worker.TaskResponse\x12L\n" +
 	"\x0fGetWorkerStatus\x12\x1b.worker.WorkerStatusRequest\x1a\x1c.worker.WorkerStatusResponse\x12C\n" +
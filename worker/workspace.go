@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var artifactsUploaded = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "worker_artifacts_uploaded_total",
+	Help: "Files tasks declared as artifacts and the worker uploaded to storage",
+}, []string{"job_id"})
+
+func init() {
+	prometheus.MustRegister(artifactsUploaded)
+}
+
+// artifactsBucket is the storage service bucket task artifacts are uploaded
+// to, under jobs/<job_id>/tasks/<task_id>/.
+const artifactsBucket = "artifacts"
+
+// maxArtifactFiles bounds how many files one task uploads as artifacts.
+const maxArtifactFiles = 100
+
+// workspaceDirFromEnv reads WORKER_WORKSPACE_DIR, under which each task
+// gets a working directory of its own; the system's temporary directory by
+// default.
+func workspaceDirFromEnv() string {
+	if dir := os.Getenv("WORKER_WORKSPACE_DIR"); dir != "" {
+		return dir
+	}
+	return os.TempDir()
+}
+
+// artifactMaxBytesFromEnv reads WORKER_ARTIFACT_MAX_BYTES, the largest file
+// uploaded as an artifact; larger ones are skipped.
+func artifactMaxBytesFromEnv() int64 {
+	n, err := strconv.ParseInt(os.Getenv("WORKER_ARTIFACT_MAX_BYTES"), 10, 64)
+	if err != nil || n <= 0 {
+		return 100 << 20
+	}
+	return n
+}
+
+// usesWorkspaces reports whether the executor runs tasks on the worker's own
+// host, in a workspace the worker provides and with their checkpoints kept
+// there.
+func usesWorkspaces(e Executor) bool {
+	_, remote := e.(interface{ remote() })
+	return !remote
+}
+
+// newWorkspace creates a working directory for one run of a task under
+// root, holding its starting weights, if any. The caller removes it.
+func newWorkspace(root string, spec TaskSpec) (string, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create task workspace: %v", err)
+	}
+	dir, err := os.MkdirTemp(root, "task-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create task workspace: %v", err)
+	}
+	if len(spec.ModelWeights) > 0 {
+		if err := os.WriteFile(filepath.Join(dir, taskWeightsInFile), spec.ModelWeights, 0o644); err != nil {
+			os.RemoveAll(dir)
+			return "", fmt.Errorf("failed to write starting weights: %v", err)
+		}
+	}
+	return dir, nil
+}
+
+// artifactFiles expands the patterns a task declared, relative to its
+// workspace, into the files they match, as slash-separated paths relative
+// to the workspace. A pattern reaching outside the workspace, or matching
+// a symlink that leads out of it, is an error.
+func artifactFiles(workspace string, patterns []string) ([]string, error) {
+	root, err := filepath.EvalSymlinks(workspace)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var files []string
+	for _, pattern := range patterns {
+		if !filepath.IsLocal(pattern) {
+			return nil, fmt.Errorf("artifact %q is outside the task directory", pattern)
+		}
+		matches, err := filepath.Glob(filepath.Join(root, pattern))
+		if err != nil {
+			return nil, fmt.Errorf("invalid artifact pattern %q: %v", pattern, err)
+		}
+		for _, match := range matches {
+			rel, err := filepath.Rel(root, match)
+			if err != nil {
+				return nil, err
+			}
+			resolved, err := inTaskDir(root, rel)
+			if errors.Is(err, fs.ErrNotExist) {
+				// A dangling symlink
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("artifact %v", err)
+			}
+			// A matched directory stands for the files in it. Symlinks
+			// within it are not followed
+			err = filepath.WalkDir(resolved, func(p string, d os.DirEntry, err error) error {
+				if err != nil || !d.Type().IsRegular() {
+					return err
+				}
+				rel, err := filepath.Rel(root, p)
+				if err != nil {
+					return err
+				}
+				if rel = filepath.ToSlash(rel); !seen[rel] {
+					seen[rel] = true
+					files = append(files, rel)
+				}
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return files, nil
+}
+
+// collectArtifacts uploads the files a completed task declared as artifacts
+// to the storage service, keyed by its job and task. A file that cannot be
+// uploaded is logged and skipped; it does not fail the task.
+func (ws *WorkerServer) collectArtifacts(ctx context.Context, spec TaskSpec, patterns []string, tlog taskLogger) {
+	files, err := artifactFiles(spec.Workspace, patterns)
+	if err != nil {
+		tlog.printf("WARN", "No artifacts collected: %v", err)
+		return
+	}
+	if len(files) > maxArtifactFiles {
+		tlog.printf("WARN", "Task declared %d artifact files; only the first %d are collected", len(files), maxArtifactFiles)
+		files = files[:maxArtifactFiles]
+	}
+
+	prefix := path.Join("jobs", spec.JobID, "tasks", spec.TaskID)
+	uploaded := 0
+	for _, name := range files {
+		local, err := inTaskDir(spec.Workspace, filepath.FromSlash(name))
+		if err != nil {
+			continue
+		}
+		info, err := os.Stat(local)
+		if err != nil {
+			continue
+		}
+		if info.Size() > ws.artifactMaxBytes {
+			tlog.printf("WARN", "Artifact %s skipped: %d bytes is over the %d byte limit", name, info.Size(), ws.artifactMaxBytes)
+			continue
+		}
+		data, err := os.ReadFile(local)
+		if err != nil {
+			tlog.printf("WARN", "Artifact %s skipped: %v", name, err)
+			continue
+		}
+		key := path.Join(prefix, name)
+		if err := ws.storage.upload(ctx, artifactsBucket, key, path.Base(name), data); err != nil {
+			log.Printf("Failed to upload artifact %s of task %s: %v", name, spec.TaskID, err)
+			tlog.printf("WARN", "Artifact %s not uploaded: %v", name, err)
+			continue
+		}
+		uploaded++
+		artifactsUploaded.WithLabelValues(jobLabel(spec.JobID)).Inc()
+		tlog.line(LogLine{
+			Level:   "INFO",
+			Message: "Uploaded artifact " + name,
+			Fields:  map[string]string{"artifact": name, "size_bytes": strconv.FormatInt(info.Size(), 10)},
+		})
+	}
+	if uploaded > 0 {
+		log.Printf("Uploaded %d artifact(s) of task %s to %s/%s", uploaded, spec.TaskID, artifactsBucket, prefix)
+	}
+}